@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/rohmanhakim/docs-crawler/pkg/hashutil"
+	"github.com/rohmanhakim/docs-crawler/pkg/retryjitter"
 )
 
 type AssetFetchResult struct {
@@ -55,6 +56,12 @@ type ResolveParam struct {
 	outputDir    string
 	maxAssetSize int64
 	hashAlgo     hashutil.HashAlgo
+	assetNaming  AssetNaming
+	// imageMaxWidth and imageFormat configure image transcoding (see transcode.go)
+	imageMaxWidth int
+	imageFormat   ImageFormat
+	// retryJitter is waited before every retry of an asset fetch
+	retryJitter retryjitter.Source
 }
 
 func NewResolveParam(outputDir string, maxAssetSize int64, hashAlgo hashutil.HashAlgo) ResolveParam {
@@ -77,11 +84,62 @@ func (r ResolveParam) HashAlgo() hashutil.HashAlgo {
 	return r.hashAlgo
 }
 
+// AssetNaming returns the scheme used to name written assets.
+func (r ResolveParam) AssetNaming() AssetNaming {
+	return r.assetNaming
+}
+
+// WithAssetNaming returns a copy of the param naming assets with naming.
+func (r ResolveParam) WithAssetNaming(naming AssetNaming) ResolveParam {
+	r.assetNaming = naming
+	return r
+}
+
+// ImageMaxWidth returns the width raster images are downscaled to; 0 keeps
+// their size.
+func (r ResolveParam) ImageMaxWidth() int {
+	return r.imageMaxWidth
+}
+
+// WithImageMaxWidth returns a copy of the param downscaling wider raster
+// images to width.
+func (r ResolveParam) WithImageMaxWidth(width int) ResolveParam {
+	r.imageMaxWidth = width
+	return r
+}
+
+// ImageFormat returns the format raster images are re-encoded in; empty
+// keeps their format.
+func (r ResolveParam) ImageFormat() ImageFormat {
+	return r.imageFormat
+}
+
+// WithImageFormat returns a copy of the param re-encoding raster images
+// as format.
+func (r ResolveParam) WithImageFormat(format ImageFormat) ResolveParam {
+	r.imageFormat = format
+	return r
+}
+
+// RetryJitter returns the jitter waited before every retry of an asset
+// fetch; nil waits none.
+func (r ResolveParam) RetryJitter() retryjitter.Source {
+	return r.retryJitter
+}
+
+// WithRetryJitter returns a copy of the param waiting one draw of jitter
+// before every retry of an asset fetch.
+func (r ResolveParam) WithRetryJitter(jitter retryjitter.Source) ResolveParam {
+	r.retryJitter = jitter
+	return r
+}
+
 type AssetfulMarkdownDoc struct {
 	content         []byte
 	missingAssets   map[string]AssetsErrorCause // key: URL string, value: error cause
 	unparseableURLs []string
 	localAssets     []string
+	writtenBytes    int64 // bytes of the assets newly written for this document
 }
 
 func NewAssetfulMarkdownDoc(content []byte, missingAssets map[string]AssetsErrorCause, unparseableURLs []string, localAssets []string) AssetfulMarkdownDoc {
@@ -108,3 +166,17 @@ func (a AssetfulMarkdownDoc) UnparseableURLs() []string {
 func (a AssetfulMarkdownDoc) LocalAssets() []string {
 	return a.localAssets
 }
+
+// WrittenBytes returns the total size of the assets written while resolving
+// this document. Assets already written for an earlier document are not
+// counted again.
+func (a AssetfulMarkdownDoc) WrittenBytes() int64 {
+	return a.writtenBytes
+}
+
+// WithWrittenBytes returns a copy of the document recording the size of the
+// assets written for it.
+func (a AssetfulMarkdownDoc) WithWrittenBytes(bytes int64) AssetfulMarkdownDoc {
+	a.writtenBytes = bytes
+	return a
+}
@@ -0,0 +1,95 @@
+package assets
+
+import (
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net/url"
+	"strings"
+)
+
+/*
+Data URI Assets
+
+Some pages inline small images as data URIs, e.g.
+"data:image/png;base64,iVBORw0...". The resolver decodes them instead of
+fetching, and writes them like downloaded assets: transcoded, deduplicated
+by content hash and named under the asset naming scheme as "inline", with
+the extension of the media type. Only image media types are accepted; a
+data URI that cannot be decoded is reported as a missing asset.
+*/
+
+// dataURIAssetURL names data URI assets under every naming scheme.
+var dataURIAssetURL = url.URL{Path: "inline"}
+
+// dataURIExtensions maps image media types to asset file extensions.
+var dataURIExtensions = map[string]string{
+	"image/png":                "png",
+	"image/jpeg":               "jpg",
+	"image/jpg":                "jpg",
+	"image/gif":                "gif",
+	"image/webp":               "webp",
+	"image/avif":               "avif",
+	"image/svg+xml":            "svg",
+	"image/bmp":                "bmp",
+	"image/x-icon":             "ico",
+	"image/vnd.microsoft.icon": "ico",
+	"image/tiff":               "tiff",
+}
+
+// isDataURI reports whether the raw image reference is a data URI.
+func isDataURI(raw string) bool {
+	return len(raw) >= 5 && strings.EqualFold(raw[:5], "data:")
+}
+
+// dataURILabel identifies a data URI in records and logs by its header
+// (e.g. "data:image/png;base64"), without the encoded payload.
+func dataURILabel(raw string) string {
+	if header, _, found := strings.Cut(raw, ","); found {
+		return header
+	}
+	return raw
+}
+
+// decodeDataURI decodes an image data URI and returns its bytes and the file
+// extension of its media type.
+func decodeDataURI(raw string) ([]byte, string, *AssetsError) {
+	header, payload, found := strings.Cut(raw[len("data:"):], ",")
+	if !found {
+		return nil, "", NewAssetsError(ErrCauseDataURIInvalid, "data URI has no payload")
+	}
+
+	isBase64 := false
+	if rest, ok := strings.CutSuffix(header, ";base64"); ok {
+		header, isBase64 = rest, true
+	}
+	mediaType, _, err := mime.ParseMediaType(header)
+	if err != nil {
+		return nil, "", NewAssetsError(ErrCauseDataURIInvalid, fmt.Sprintf("invalid media type %q: %v", header, err))
+	}
+	extension, ok := dataURIExtensions[mediaType]
+	if !ok {
+		return nil, "", NewAssetsError(ErrCauseDataURIInvalid, fmt.Sprintf("unsupported media type %q", mediaType))
+	}
+
+	var data []byte
+	if isBase64 {
+		// Inline base64 is sometimes wrapped or left unpadded
+		payload = strings.Join(strings.Fields(payload), "")
+		data, err = base64.StdEncoding.DecodeString(payload)
+		if err != nil {
+			data, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(payload, "="))
+		}
+	} else {
+		var unescaped string
+		unescaped, err = url.PathUnescape(payload)
+		data = []byte(unescaped)
+	}
+	if err != nil {
+		return nil, "", NewAssetsError(ErrCauseDataURIInvalid, fmt.Sprintf("failed to decode payload: %v", err))
+	}
+	if len(data) == 0 {
+		return nil, "", NewAssetsError(ErrCauseDataURIInvalid, "data URI payload is empty")
+	}
+	return data, extension, nil
+}
@@ -0,0 +1,125 @@
+package assets_test
+
+import (
+	"context"
+	"encoding/base64"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/assets"
+	"github.com/rohmanhakim/docs-crawler/internal/mdconvert"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/pkg/hashutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pixelPNGDataURI is a 1x1 PNG inlined as a base64 data URI
+const pixelPNGDataURI = "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNk+M9QDwADhgGAWjR9awAAAABJRU5ErkJggg=="
+
+// imageConversionResult builds a conversion result referencing each image
+// source once, in order.
+func imageConversionResult(sources ...string) mdconvert.ConversionResult {
+	var markdown strings.Builder
+	markdown.WriteString("# Test\n")
+	var linkRefs []mdconvert.LinkRef
+	for _, src := range sources {
+		markdown.WriteString("\n![pixel](" + src + ")\n")
+		linkRefs = append(linkRefs, mdconvert.NewLinkRef(src, mdconvert.KindImage))
+	}
+	return mdconvert.NewConversionResult([]byte(markdown.String()), linkRefs)
+}
+
+func TestResolve_DataURI_DecodedWrittenOnceAndRewritten(t *testing.T) {
+	mockSink := &metadataSinkMock{}
+	resolver := newTestResolver(mockSink)
+	tempDir := t.TempDir()
+	pageURL, _ := url.Parse("https://example.com/docs/page")
+
+	// The same image is inlined twice on one page and again on another
+	first, err := resolveWithTestParams(resolver, context.Background(), *pageURL, imageConversionResult(pixelPNGDataURI, pixelPNGDataURI), tempDir)
+	require.NoError(t, err)
+	otherURL, _ := url.Parse("https://example.com/docs/other")
+	second, err := resolveWithTestParams(resolver, context.Background(), *otherURL, imageConversionResult(pixelPNGDataURI), tempDir)
+	require.NoError(t, err)
+
+	pngBytes, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(pixelPNGDataURI, "data:image/png;base64,"))
+	contentHash, _ := hashutil.HashBytes(pngBytes, hashutil.HashAlgoSHA256)
+	expectedPath := buildExpectedPath("inline", contentHash, "png")
+
+	// Decoded and written once, without fetching
+	assert.Empty(t, mockSink.GetFetchRecords())
+	artifacts := mockSink.GetArtifactRecords()
+	require.Len(t, artifacts, 1)
+	assert.Equal(t, expectedPath, artifacts[0].WritePath())
+	assert.Equal(t, "data:image/png;base64", artifacts[0].SourceURL())
+	written, err := os.ReadFile(filepath.Join(tempDir, expectedPath))
+	require.NoError(t, err)
+	assert.Equal(t, pngBytes, written)
+	assert.Equal(t, int64(len(pngBytes)), first.WrittenBytes())
+	assert.Zero(t, second.WrittenBytes())
+
+	// Rewritten to the local path on both pages
+	for _, doc := range []assets.AssetfulMarkdownDoc{first, second} {
+		assert.NotContains(t, string(doc.Content()), "data:")
+		assert.Contains(t, string(doc.Content()), "![pixel]("+expectedPath+")")
+		assert.Equal(t, []string{expectedPath}, doc.LocalAssets())
+		assert.Empty(t, doc.MissingAssets())
+	}
+	assert.False(t, mockSink.RecordErrorCalled)
+}
+
+func TestResolve_DataURI_MediaTypeSetsExtension(t *testing.T) {
+	mockSink := &metadataSinkMock{}
+	resolver := newTestResolver(mockSink)
+	tempDir := t.TempDir()
+	pageURL, _ := url.Parse("https://example.com/docs/page")
+
+	svgDataURI := "data:image/svg+xml,%3Csvg%20xmlns%3D%22http%3A%2F%2Fwww.w3.org%2F2000%2Fsvg%22%2F%3E"
+	doc, err := resolveWithTestParams(resolver, context.Background(), *pageURL, imageConversionResult(svgDataURI), tempDir)
+	require.NoError(t, err)
+
+	require.Len(t, doc.LocalAssets(), 1)
+	localPath := doc.LocalAssets()[0]
+	assert.Equal(t, ".svg", filepath.Ext(localPath))
+	written, err := os.ReadFile(filepath.Join(tempDir, localPath))
+	require.NoError(t, err)
+	assert.Equal(t, `<svg xmlns="http://www.w3.org/2000/svg"/>`, string(written))
+}
+
+func TestResolve_DataURI_MalformedRecordedAsFailedAsset(t *testing.T) {
+	tests := []struct {
+		name    string
+		dataURI string
+		label   string
+	}{
+		{"invalid base64", "data:image/png;base64,not*base64!", "data:image/png;base64"},
+		{"non-image media type", "data:text/html;base64,PGgxPmhpPC9oMT4=", "data:text/html;base64"},
+		{"no payload", "data:image/png;base64", "data:image/png;base64"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSink := &metadataSinkMock{}
+			resolver := newTestResolver(mockSink)
+			tempDir := t.TempDir()
+			pageURL, _ := url.Parse("https://example.com/docs/page")
+
+			doc, err := resolveWithTestParams(resolver, context.Background(), *pageURL, imageConversionResult(tt.dataURI), tempDir)
+			require.NoError(t, err)
+
+			// Reported as a missing asset, the reference is left as is
+			assert.Equal(t, map[string]assets.AssetsErrorCause{tt.label: assets.ErrCauseDataURIInvalid}, doc.MissingAssets())
+			assert.Contains(t, string(doc.Content()), "![pixel]("+tt.dataURI+")")
+			assert.Empty(t, doc.LocalAssets())
+			assert.Empty(t, mockSink.GetArtifactRecords())
+
+			errorRecords := mockSink.GetErrorRecords()
+			require.Len(t, errorRecords, 1)
+			assert.Equal(t, metadata.ErrorCause(metadata.CauseContentInvalid), errorRecords[0].Cause())
+		})
+	}
+}
@@ -150,7 +150,7 @@ func (r *DryRunResolver) resolve(
 		extension := getFileExtension(assetURL.Path)
 
 		// Build the local path
-		localPath := buildAssetPath(assetURL.Path, syntheticHash, extension)
+		localPath := assetPath(resolveParam.AssetNaming(), assetURL, syntheticHash, extension)
 
 		// Record successfully "resolved" asset
 		r.writtenAssets[canonicalKey] = syntheticHash
@@ -169,7 +169,7 @@ func (r *DryRunResolver) resolve(
 	}
 
 	// Construct local asset paths for the current document's image URLs
-	currentDocumentAssets := r.constructLocalPaths(imageURLs, host, scheme)
+	currentDocumentAssets := r.constructLocalPaths(imageURLs, host, scheme, resolveParam.AssetNaming())
 
 	// Build localAssets slice from map values
 	var localAssets []string
@@ -214,7 +214,7 @@ func (r *DryRunResolver) mechanicalDeduplicate(urls []url.URL, host string, sche
 	return deduplicated
 }
 
-func (r *DryRunResolver) constructLocalPaths(imageUrls []url.URL, host string, scheme string) map[string]string {
+func (r *DryRunResolver) constructLocalPaths(imageUrls []url.URL, host string, scheme string, naming AssetNaming) map[string]string {
 	localPaths := make(map[string]string)
 
 	for _, imgURL := range imageUrls {
@@ -233,7 +233,7 @@ func (r *DryRunResolver) constructLocalPaths(imageUrls []url.URL, host string, s
 			if localPath == "" {
 				// Build new path if not found
 				extension := getFileExtension(canonical.Path)
-				localPath = buildAssetPath(canonical.Path, syntheticHash, extension)
+				localPath = assetPath(naming, canonical, syntheticHash, extension)
 			}
 			localPaths[rawURLStr] = localPath
 		}
@@ -24,6 +24,7 @@ const (
 	ErrCauseRequest5xx            = "5xx"
 	ErrCauseAssetTooLarge         = "asset too large"
 	ErrCauseHashError             = "hash error"
+	ErrCauseDataURIInvalid        = "malformed data URI"
 )
 
 // assetsErrorClassifications provides explicit retry policy and impact level
@@ -47,6 +48,7 @@ var assetsErrorClassifications = map[AssetsErrorCause]struct {
 	ErrCauseWriteFailure:          {failure.RetryPolicyNever, failure.ImpactLevelContinue},
 	ErrCausePathError:             {failure.RetryPolicyNever, failure.ImpactLevelContinue},
 	ErrCauseHashError:             {failure.RetryPolicyNever, failure.ImpactLevelContinue},
+	ErrCauseDataURIInvalid:        {failure.RetryPolicyNever, failure.ImpactLevelContinue},
 }
 
 // AssetsError represents an error that occurred during asset resolution.
@@ -136,6 +138,8 @@ func mapAssetsErrorToMetadataCause(err AssetsError) metadata.ErrorCause {
 		return metadata.CausePolicyDisallow
 	case ErrCauseHashError:
 		return metadata.CauseContentInvalid
+	case ErrCauseDataURIInvalid:
+		return metadata.CauseContentInvalid
 	default:
 		return metadata.CauseUnknown
 	}
@@ -0,0 +1,158 @@
+package assets
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+/*
+Asset Manifest
+
+The LocalResolver keeps one record per distinct asset content (content hash)
+written during the crawl: where it was written, which URLs served it, and
+which pages reference it. Written as assets.json next to the page manifest,
+it lets a later run garbage-collect assets no page references any more:
+
+	{
+	  "<content_hash>": {
+	    "path": "assets/images/logo-3f2a9c1.png",
+	    "sourceUrls": ["https://example.com/img/logo.png"],
+	    "pages": ["https://example.com/docs", "https://example.com/docs/guide"],
+	    "size": 4096,
+	    "refCount": 2
+	  }
+	}
+
+Paths are relative to the output directory of the pages. Source URLs and
+pages are sorted and deduplicated; refCount is the number of pages.
+*/
+
+// AssetManifestFileName is the name of the asset manifest written into the
+// output directory.
+const AssetManifestFileName = "assets.json"
+
+// AssetRecord describes one distinct asset content written by the crawl.
+type AssetRecord struct {
+	contentHash string
+	localPath   string
+	size        int64
+	sourceURLs  map[string]struct{}
+	pages       map[string]struct{}
+}
+
+func newAssetRecord(contentHash string, localPath string, size int64) *AssetRecord {
+	return &AssetRecord{
+		contentHash: contentHash,
+		localPath:   localPath,
+		size:        size,
+		sourceURLs:  make(map[string]struct{}),
+		pages:       make(map[string]struct{}),
+	}
+}
+
+func (a AssetRecord) ContentHash() string {
+	return a.contentHash
+}
+
+func (a AssetRecord) LocalPath() string {
+	return a.localPath
+}
+
+func (a AssetRecord) Size() int64 {
+	return a.size
+}
+
+// SourceURLs returns the sorted URLs the asset content was served from.
+func (a AssetRecord) SourceURLs() []string {
+	return sortedKeys(a.sourceURLs)
+}
+
+// Pages returns the sorted URLs of the pages referencing the asset.
+func (a AssetRecord) Pages() []string {
+	return sortedKeys(a.pages)
+}
+
+// RefCount returns the number of pages referencing the asset.
+func (a AssetRecord) RefCount() int {
+	return len(a.pages)
+}
+
+type assetManifestEntryDTO struct {
+	Path       string   `json:"path"`
+	SourceURLs []string `json:"sourceUrls"`
+	Pages      []string `json:"pages"`
+	Size       int64    `json:"size"`
+	RefCount   int      `json:"refCount"`
+}
+
+// AssetManifest returns a record of every asset written by the resolver,
+// sorted by local path.
+func (r *LocalResolver) AssetManifest() []AssetRecord {
+	records := make([]AssetRecord, 0, len(r.assetRecords))
+	for _, record := range r.assetRecords {
+		records = append(records, *record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].localPath < records[j].localPath
+	})
+	return records
+}
+
+// WriteAssetManifest writes the asset manifest to
+// outputDir/AssetManifestFileName. Nothing is written when no asset was.
+func (r *LocalResolver) WriteAssetManifest(outputDir string) *AssetsError {
+	if len(r.assetRecords) == 0 {
+		return nil
+	}
+	entries := make(map[string]assetManifestEntryDTO, len(r.assetRecords))
+	for _, record := range r.assetRecords {
+		entries[record.contentHash] = assetManifestEntryDTO{
+			Path:       record.localPath,
+			SourceURLs: record.SourceURLs(),
+			Pages:      record.Pages(),
+			Size:       record.size,
+			RefCount:   record.RefCount(),
+		}
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return NewAssetsError(ErrCauseWriteFailure, fmt.Sprintf("failed to encode asset manifest: %v", err))
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, AssetManifestFileName), data, 0644); err != nil {
+		return NewAssetsError(ErrCauseWriteFailure, fmt.Sprintf("failed to write asset manifest: %v", err))
+	}
+	return nil
+}
+
+// recordAssetSource records that assetURL served the content with
+// contentHash, written to localPath.
+func (r *LocalResolver) recordAssetSource(contentHash string, localPath string, size int64, assetURL string) {
+	record, ok := r.assetRecords[localPath]
+	if !ok {
+		record = newAssetRecord(contentHash, localPath, size)
+		r.assetRecords[localPath] = record
+	}
+	record.sourceURLs[assetURL] = struct{}{}
+}
+
+// recordPageReferences records pageURL as a reference of every asset
+// written to one of localPaths.
+func (r *LocalResolver) recordPageReferences(pageURL string, localPaths []string) {
+	for _, localPath := range localPaths {
+		if record, ok := r.assetRecords[localPath]; ok {
+			record.pages[pageURL] = struct{}{}
+		}
+	}
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,99 @@
+package assets_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/assets"
+	"github.com/rohmanhakim/docs-crawler/internal/mdconvert"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pageWithImages returns a conversion result referencing the given images.
+func pageWithImages(imageURLs ...string) mdconvert.ConversionResult {
+	markdown := "# Page\n"
+	var linkRefs []mdconvert.LinkRef
+	for _, imageURL := range imageURLs {
+		markdown += "\n![image](" + imageURL + ")\n"
+		linkRefs = append(linkRefs, mdconvert.NewLinkRef(imageURL, mdconvert.KindImage))
+	}
+	return mdconvert.NewConversionResult([]byte(markdown), linkRefs)
+}
+
+func TestAssetManifest_SharedAssetCountsEveryReferencingPage(t *testing.T) {
+	images := map[string]string{
+		"/shared.png": "shared-image-data",
+		"/mirror.png": "shared-image-data",
+		"/a.png":      "image-a-data",
+		"/b.png":      "image-b-data",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(images[r.URL.Path]))
+	}))
+	defer server.Close()
+
+	resolver := newTestResolver(&metadataSinkMock{})
+	outputDir := t.TempDir()
+	pageA, _ := url.Parse(server.URL + "/docs/a")
+	pageB, _ := url.Parse(server.URL + "/docs/b")
+
+	_, err := resolveWithTestParams(resolver, context.Background(), *pageA,
+		pageWithImages(server.URL+"/shared.png", server.URL+"/a.png"), outputDir)
+	require.NoError(t, err)
+	// Page B reaches the shared content through a second URL too
+	_, err = resolveWithTestParams(resolver, context.Background(), *pageB,
+		pageWithImages(server.URL+"/shared.png", server.URL+"/mirror.png", server.URL+"/b.png"), outputDir)
+	require.NoError(t, err)
+
+	records := resolver.AssetManifest()
+	require.Len(t, records, 3, "one record per distinct content")
+	byFirstSource := make(map[string]assets.AssetRecord)
+	for _, record := range records {
+		byFirstSource[record.SourceURLs()[0]] = record
+	}
+
+	shared := byFirstSource[server.URL+"/mirror.png"]
+	assert.Equal(t, []string{server.URL + "/mirror.png", server.URL + "/shared.png"}, shared.SourceURLs())
+	assert.Equal(t, []string{pageA.String(), pageB.String()}, shared.Pages())
+	assert.Equal(t, 2, shared.RefCount())
+	assert.Equal(t, int64(len("shared-image-data")), shared.Size())
+
+	assert.Equal(t, []string{pageA.String()}, byFirstSource[server.URL+"/a.png"].Pages())
+	assert.Equal(t, 1, byFirstSource[server.URL+"/a.png"].RefCount())
+	assert.Equal(t, []string{pageB.String()}, byFirstSource[server.URL+"/b.png"].Pages())
+
+	// The manifest file maps content hash to the same record
+	require.Nil(t, resolver.WriteAssetManifest(outputDir))
+	data, readErr := os.ReadFile(filepath.Join(outputDir, assets.AssetManifestFileName))
+	require.NoError(t, readErr)
+	var manifest map[string]struct {
+		Path       string   `json:"path"`
+		SourceURLs []string `json:"sourceUrls"`
+		Pages      []string `json:"pages"`
+		Size       int64    `json:"size"`
+		RefCount   int      `json:"refCount"`
+	}
+	require.NoError(t, json.Unmarshal(data, &manifest))
+	require.Len(t, manifest, 3)
+	entry := manifest[shared.ContentHash()]
+	assert.Equal(t, shared.LocalPath(), entry.Path)
+	assert.Equal(t, shared.Pages(), entry.Pages)
+	assert.Equal(t, 2, entry.RefCount)
+}
+
+func TestAssetManifest_NotWrittenWithoutAssets(t *testing.T) {
+	resolver := newTestResolver(&metadataSinkMock{})
+	outputDir := t.TempDir()
+
+	require.Nil(t, resolver.WriteAssetManifest(outputDir))
+
+	_, err := os.Stat(filepath.Join(outputDir, assets.AssetManifestFileName))
+	assert.True(t, os.IsNotExist(err))
+}
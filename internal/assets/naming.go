@@ -0,0 +1,98 @@
+package assets
+
+import (
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+/*
+Asset Naming
+
+AssetNaming selects how a written asset's local path is derived. Every
+scheme is deterministic: the same asset URL and content always produce the
+same path.
+
+  - nameHash:    assets/images/<original-name>-<short-hash>.<ext>
+  - contentHash: assets/images/<content-hash>.<ext>
+  - mirror:      assets/<host>/<asset URL path>
+
+Mirrored paths can collide, e.g. when two URL paths sanitize to the same
+name. The asset written second then gets the short content hash appended to
+its name, as in nameHash.
+*/
+type AssetNaming string
+
+const (
+	AssetNamingNameHash    AssetNaming = "nameHash"
+	AssetNamingContentHash AssetNaming = "contentHash"
+	AssetNamingMirror      AssetNaming = "mirror"
+)
+
+// IsValid reports whether n is a known naming scheme. The empty value is
+// valid and behaves like AssetNamingNameHash.
+func (n AssetNaming) IsValid() bool {
+	switch n {
+	case "", AssetNamingNameHash, AssetNamingContentHash, AssetNamingMirror:
+		return true
+	}
+	return false
+}
+
+// assetPath builds the relative path of an asset under the naming scheme.
+func assetPath(naming AssetNaming, assetURL url.URL, contentHash string, extension string) string {
+	switch naming {
+	case AssetNamingContentHash:
+		filename := contentHash
+		if extension != "" {
+			filename = filename + "." + extension
+		}
+		return filepath.Join("assets", "images", filename)
+	case AssetNamingMirror:
+		mirrored := buildMirrorPath(assetURL)
+		// A transcoded image keeps its mirrored name but takes the new extension
+		if ext := filepath.Ext(mirrored); extension != "" && !strings.EqualFold(ext, "."+extension) {
+			mirrored = strings.TrimSuffix(mirrored, ext) + "." + extension
+		}
+		return mirrored
+	default:
+		return buildAssetPath(assetURL.Path, contentHash, extension)
+	}
+}
+
+// buildMirrorPath mirrors the asset URL under assets/<host>/. Each path
+// segment is sanitized, and a path ending in "/" is stored as "index".
+func buildMirrorPath(assetURL url.URL) string {
+	host := sanitizeFilename(assetURL.Host)
+	if host == "" {
+		host = "local"
+	}
+
+	// Cleaning a rooted path drops any ".." that would escape the host dir
+	cleaned := path.Clean("/" + assetURL.Path)
+	if cleaned == "/" || strings.HasSuffix(assetURL.Path, "/") {
+		cleaned = path.Join(cleaned, "index")
+	}
+
+	parts := []string{"assets", host}
+	for _, segment := range strings.Split(strings.TrimPrefix(cleaned, "/"), "/") {
+		safe := sanitizeFilename(segment)
+		if safe == "" {
+			safe = "_"
+		}
+		parts = append(parts, safe)
+	}
+	return filepath.Join(parts...)
+}
+
+// withShortHash appends the short content hash to the file name of
+// localPath, keeping its extension.
+func withShortHash(localPath string, contentHash string) string {
+	shortHash := contentHash
+	if len(contentHash) > 7 {
+		shortHash = contentHash[:7]
+	}
+	ext := filepath.Ext(localPath)
+	return strings.TrimSuffix(localPath, ext) + "-" + shortHash + ext
+}
@@ -0,0 +1,107 @@
+package assets_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/assets"
+	"github.com/rohmanhakim/docs-crawler/internal/mdconvert"
+	"github.com/rohmanhakim/docs-crawler/pkg/hashutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resolveWithNaming resolves one page referencing imagePaths on server
+// under the naming scheme, and returns the written local paths by image path.
+func resolveWithNaming(t *testing.T, server *httptest.Server, naming assets.AssetNaming, imagePaths ...string) map[string]string {
+	t.Helper()
+	resolver := newTestResolver(&metadataSinkMock{})
+	tempDir := t.TempDir()
+
+	var linkRefs []mdconvert.LinkRef
+	var markdown strings.Builder
+	markdown.WriteString("# Test\n")
+	for _, p := range imagePaths {
+		linkRefs = append(linkRefs, mdconvert.NewLinkRef(server.URL+p, mdconvert.KindImage))
+		markdown.WriteString("\n![img](" + server.URL + p + ")\n")
+	}
+	pageURL, _ := url.Parse(server.URL + "/docs/page")
+
+	resolveParam := assets.NewResolveParam(tempDir, 10*1024*1024, hashutil.HashAlgoSHA256).WithAssetNaming(naming)
+	_, err := resolver.Resolve(context.Background(), *pageURL, mdconvert.NewConversionResult([]byte(markdown.String()), linkRefs), resolveParam, testRetryOptions())
+	require.NoError(t, err)
+
+	paths := make(map[string]string)
+	for _, record := range resolver.AssetManifest() {
+		for _, source := range record.SourceURLs() {
+			paths[strings.TrimPrefix(source, server.URL)] = record.LocalPath()
+			_, statErr := os.Stat(filepath.Join(tempDir, record.LocalPath()))
+			assert.NoError(t, statErr, "asset should be written at %s", record.LocalPath())
+		}
+	}
+	return paths
+}
+
+func TestResolve_AssetNaming(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("logo-bytes"))
+	}))
+	defer server.Close()
+
+	hash, err := hashutil.HashBytes([]byte("logo-bytes"), hashutil.HashAlgoSHA256)
+	require.NoError(t, err)
+	mirrorHost := strings.ReplaceAll(strings.TrimPrefix(server.URL, "http://"), ":", "_")
+
+	tests := []struct {
+		name   string
+		naming assets.AssetNaming
+		want   string
+	}{
+		{name: "nameHash", naming: assets.AssetNamingNameHash, want: buildExpectedPath("logo", hash, "png")},
+		{name: "empty behaves like nameHash", naming: "", want: buildExpectedPath("logo", hash, "png")},
+		{name: "contentHash", naming: assets.AssetNamingContentHash, want: "assets/images/" + hash + ".png"},
+		{name: "mirror", naming: assets.AssetNamingMirror, want: "assets/" + mirrorHost + "/static/img/logo.png"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paths := resolveWithNaming(t, server, tt.naming, "/static/img/logo.png")
+			assert.Equal(t, filepath.FromSlash(tt.want), paths["/static/img/logo.png"])
+		})
+	}
+}
+
+func TestResolve_AssetNamingMirror_Collision(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("content of " + r.URL.Path))
+	}))
+	defer server.Close()
+
+	mirrorHost := strings.ReplaceAll(strings.TrimPrefix(server.URL, "http://"), ":", "_")
+	secondHash, err := hashutil.HashBytes([]byte("content of /img/a_b.png"), hashutil.HashAlgoSHA256)
+	require.NoError(t, err)
+
+	// "a b.png" and "a_b.png" both sanitize to a_b.png
+	paths := resolveWithNaming(t, server, assets.AssetNamingMirror, "/img/a%20b.png", "/img/a_b.png")
+
+	assert.Equal(t, filepath.FromSlash("assets/"+mirrorHost+"/img/a_b.png"), paths["/img/a%20b.png"])
+	assert.Equal(t, filepath.FromSlash("assets/"+mirrorHost+"/img/a_b-"+secondHash[:7]+".png"), paths["/img/a_b.png"])
+}
+
+func TestResolve_AssetNamingMirror_DirectoryPath(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("generated"))
+	}))
+	defer server.Close()
+
+	mirrorHost := strings.ReplaceAll(strings.TrimPrefix(server.URL, "http://"), ":", "_")
+	paths := resolveWithNaming(t, server, assets.AssetNamingMirror, "/diagrams/../charts/")
+
+	assert.Equal(t, filepath.FromSlash("assets/"+mirrorHost+"/charts/index"), paths["/diagrams/../charts/"])
+}
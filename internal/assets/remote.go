@@ -0,0 +1,40 @@
+package assets
+
+import (
+	"net/url"
+
+	"github.com/rohmanhakim/docs-crawler/internal/mdconvert"
+)
+
+/*
+Remote Assets
+
+RemoteAssets turns a conversion result into an AssetfulMarkdownDoc without
+fetching or writing anything: assets stay on the site, and image links are
+made absolute against the page URL so they keep pointing there. It serves
+tools running the pipeline on a single document, such as the extract
+command, where no output directory exists.
+
+Only http(s) targets are rewritten; against any other page URL, e.g. the
+file:// URL of a local file, image links are left as written.
+*/
+
+// RemoteAssets returns the Markdown of conversionResult with its image
+// links resolved against pageURL, without resolving any asset.
+func RemoteAssets(pageURL url.URL, conversionResult mdconvert.ConversionResult) AssetfulMarkdownDoc {
+	var unparseableURLs []string
+	content := imageRegex.ReplaceAllStringFunc(string(conversionResult.GetMarkdownContent()), func(match string) string {
+		submatches := imageRegex.FindStringSubmatch(match)
+		ref, err := url.Parse(submatches[2])
+		if err != nil {
+			unparseableURLs = append(unparseableURLs, submatches[2])
+			return match
+		}
+		target := pageURL.ResolveReference(ref)
+		if target.Scheme != "http" && target.Scheme != "https" {
+			return match
+		}
+		return "![" + submatches[1] + "](" + target.String() + ")"
+	})
+	return NewAssetfulMarkdownDoc([]byte(content), map[string]AssetsErrorCause{}, unparseableURLs, nil)
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/rohmanhakim/docs-crawler/pkg/failure"
 	"github.com/rohmanhakim/docs-crawler/pkg/fileutil"
 	"github.com/rohmanhakim/docs-crawler/pkg/hashutil"
+	"github.com/rohmanhakim/docs-crawler/pkg/retryjitter"
 	"github.com/rohmanhakim/docs-crawler/pkg/urlutil"
 	"github.com/rohmanhakim/retrier"
 )
@@ -55,8 +56,9 @@ type Resolver interface {
 
 type LocalResolver struct {
 	metadataSink  metadata.MetadataSink
-	writtenAssets map[string]string // key: assetURL, value: contentHash
-	hashToPath    map[string]string // key: contentHash, value: localPath (only for files actually written)
+	writtenAssets map[string]string       // key: assetURL, value: contentHash
+	hashToPath    map[string]string       // key: contentHash, value: localPath (only for files actually written)
+	assetRecords  map[string]*AssetRecord // key: localPath, see AssetManifest
 	httpClient    *http.Client
 	userAgent     string
 	debugLogger   debug.DebugLogger
@@ -69,6 +71,7 @@ func NewLocalResolver(
 		metadataSink:  metadataSink,
 		writtenAssets: make(map[string]string),
 		hashToPath:    make(map[string]string),
+		assetRecords:  make(map[string]*AssetRecord),
 		debugLogger:   debug.NewNoOpLogger(),
 	}
 }
@@ -123,7 +126,9 @@ func (r *LocalResolver) Resolve(
 	// Asset callback - only called when actual new write happens.
 	// Carries the remote asset URL, content hash, and byte count so RecordArtifact
 	// can be fully populated.
+	var writtenBytes int64
 	assetCallback := func(localPath string, assetURL string, contentHash string, bytes int64) {
+		writtenBytes += bytes
 		r.metadataSink.RecordArtifact(metadata.NewArtifactRecord(
 			metadata.ArtifactAsset,
 			localPath,
@@ -145,9 +150,22 @@ func (r *LocalResolver) Resolve(
 		fetchEventCallback,
 		assetCallback,
 	)
+	assetfulMarkdownDoc = assetfulMarkdownDoc.WithWrittenBytes(writtenBytes)
 
-	// Record errors for missing URLs
+	// Record errors for missing URLs; oversized assets are a policy skip
 	for urlStr, cause := range assetfulMarkdownDoc.MissingAssets() {
+		if cause == ErrCauseAssetTooLarge {
+			r.metadataSink.RecordSkip(metadata.NewSkipEvent(
+				urlStr,
+				metadata.SkipReasonAssetTooLarge,
+				time.Now(),
+				[]metadata.Attribute{
+					metadata.NewAttr(metadata.AttrAssetURL, urlStr),
+					metadata.NewAttr(metadata.AttrURL, pageUrl.String()),
+				},
+			))
+			continue
+		}
 		r.metadataSink.RecordError(metadata.NewErrorRecord(
 			time.Now(),
 			"assets",
@@ -210,6 +228,7 @@ func (r *LocalResolver) Resolve(
 		return AssetfulMarkdownDoc{}, err
 	}
 
+	r.recordPageReferences(pageUrl.String(), assetfulMarkdownDoc.LocalAssets())
 	return assetfulMarkdownDoc, nil
 }
 
@@ -223,11 +242,16 @@ func (r *LocalResolver) resolve(
 	fetchCallback func(int, AssetFetchResult),
 	assetCallback func(localPath string, assetURL string, contentHash string, bytes int64),
 ) (AssetfulMarkdownDoc, failure.ClassifiedError) {
-	// Extract image URLs from link refs
+	// Extract image URLs from link refs; data URIs are decoded, not fetched
 	var imageURLs []url.URL
+	var dataURIs []string
 	var unparseableURLs []string
 	for _, linkRef := range conversionResult.GetLinkRefs() {
 		if linkRef.GetKind() == mdconvert.KindImage {
+			if isDataURI(linkRef.GetRaw()) {
+				dataURIs = append(dataURIs, linkRef.GetRaw())
+				continue
+			}
 			u, err := url.Parse(linkRef.GetRaw())
 			if err != nil {
 				// Track unparseable URL
@@ -282,7 +306,7 @@ func (r *LocalResolver) resolve(
 				})
 			}
 
-			result := r.fetchAssetWithRetry(ctx, assetURL, r.userAgent, retryOptions, resolveParam.MaxAssetSize())
+			result := r.fetchAssetWithRetry(ctx, assetURL, r.userAgent, retryOptions, resolveParam.MaxAssetSize(), resolveParam.RetryJitter())
 
 			// Calculate retry count (attempts - 1, since first try is not a retry)
 			retryCount := result.Attempts() - 1
@@ -321,8 +345,23 @@ func (r *LocalResolver) resolve(
 				})
 			}
 
-			// Hash the content using the configured hash algorithm
+			// Downscale or convert raster images before hashing, so the
+			// hash and extension describe the bytes that get written
 			assetData := fetchResult.Data()
+			extension := fileutil.GetFileExtension(assetURL.Path)
+			if transcoded, newExtension, changed := transcodeImage(assetData, extension, resolveParam.ImageMaxWidth(), resolveParam.ImageFormat()); changed {
+				if r.debugLogger.Enabled() {
+					r.debugLogger.LogStep(ctx, "assets", "asset_transcoded", debug.FieldMap{
+						"asset_url":     assetURL.String(),
+						"format":        newExtension,
+						"original_size": len(assetData),
+						"size_bytes":    len(transcoded),
+					})
+				}
+				assetData, extension = transcoded, newExtension
+			}
+
+			// Hash the content using the configured hash algorithm
 			contentHash, hashErr := hashutil.HashBytes(assetData, resolveParam.HashAlgo())
 			if hashErr != nil {
 				// This should not happen with valid algorithms, but handle defensively
@@ -330,9 +369,6 @@ func (r *LocalResolver) resolve(
 				continue
 			}
 
-			// Get extension from asset URL
-			extension := fileutil.GetFileExtension(assetURL.Path)
-
 			// Check if content hash already exists (content-hash deduplication)
 			if existingPath := r.findPathByHash(contentHash); existingPath != "" {
 				// Log content-hash deduplication skip
@@ -347,11 +383,12 @@ func (r *LocalResolver) resolve(
 				// DON'T call assetCallback - no new write happened
 				// Store using canonical key (without query params) for consistent lookup
 				r.writtenAssets[canonicalKey] = contentHash
+				r.recordAssetSource(contentHash, existingPath, int64(len(assetData)), assetURL.String())
 				continue
 			}
 
-			// Write asset to disk (pass original URL path for filename)
-			localPath, err := r.writeAsset(resolveParam.OutputDir(), assetURL.Path, contentHash, extension, assetData)
+			// Write asset to disk (the URL names the file under every scheme)
+			localPath, err := r.writeAsset(resolveParam.OutputDir(), resolveParam.AssetNaming(), assetURL, contentHash, extension, assetData)
 			if err != nil {
 				// Write failed - don't update writtenAssets, asset remains "pending"
 				var assetsErr *AssetsError
@@ -379,20 +416,43 @@ func (r *LocalResolver) resolve(
 
 			// Store hash -> path mapping for content-hash deduplication lookups
 			r.hashToPath[contentHash] = localPath
+			r.recordAssetSource(contentHash, localPath, int64(len(assetData)), assetURL.String())
 
 			// Call assetCallback ONLY for actual new writes (not content-hash dedups)
 			assetCallback(localPath, assetURL.String(), contentHash, int64(len(assetData)))
 		}
 	}
 
+	// Decode and write the data URI images
+	dataURIAssets := r.resolveDataURIs(ctx, dataURIs, resolveParam, missingAssetErrors, assetCallback)
+
 	// Construct local asset paths for the current document's image URLs
-	currentDocumentAssets := r.constructLocalPaths(imageURLs, host, scheme)
+	currentDocumentAssets := r.constructLocalPaths(imageURLs, host, scheme, resolveParam.AssetNaming())
 
-	// Build localAssets slice from map values
+	// List every local asset once, in order of first reference: the same
+	// image referenced under several URLs (relative, absolute, with query
+	// params) maps to one local path
 	var localAssets []string
-	for _, localPath := range currentDocumentAssets {
+	listed := make(map[string]bool)
+	for _, imgURL := range imageURLs {
+		localPath, ok := currentDocumentAssets[imgURL.String()]
+		if !ok || listed[localPath] {
+			continue
+		}
+		listed[localPath] = true
 		localAssets = append(localAssets, localPath)
 	}
+	for _, raw := range dataURIs {
+		localPath, ok := dataURIAssets[raw]
+		if !ok {
+			continue
+		}
+		currentDocumentAssets[raw] = localPath
+		if !listed[localPath] {
+			listed[localPath] = true
+			localAssets = append(localAssets, localPath)
+		}
+	}
 
 	// Get content from constructDocument
 	content := r.constructDocument(conversionResult.GetMarkdownContent(), currentDocumentAssets)
@@ -402,6 +462,87 @@ func (r *LocalResolver) resolve(
 	return resolvedDoc, nil
 }
 
+// resolveDataURIs decodes the page's data URI images and writes them like
+// downloaded assets, deduplicated by content hash. It returns the local path
+// of each decoded URI, keyed by the URI as it appears in the Markdown. A URI
+// that cannot be decoded is recorded in missingAssetErrors under its label.
+func (r *LocalResolver) resolveDataURIs(
+	ctx context.Context,
+	dataURIs []string,
+	resolveParam ResolveParam,
+	missingAssetErrors map[string]AssetsErrorCause,
+	assetCallback func(localPath string, assetURL string, contentHash string, bytes int64),
+) map[string]string {
+	localPaths := make(map[string]string)
+	for _, raw := range dataURIs {
+		if _, resolved := localPaths[raw]; resolved {
+			continue
+		}
+		label := dataURILabel(raw)
+
+		assetData, extension, decodeErr := decodeDataURI(raw)
+		if decodeErr != nil {
+			if r.debugLogger.Enabled() {
+				r.debugLogger.LogStep(ctx, "assets", "asset_failed", debug.FieldMap{
+					"asset_url": label,
+					"error":     decodeErr.Error(),
+				})
+			}
+			missingAssetErrors[label] = decodeErr.Cause
+			continue
+		}
+		if transcoded, newExtension, changed := transcodeImage(assetData, extension, resolveParam.ImageMaxWidth(), resolveParam.ImageFormat()); changed {
+			assetData, extension = transcoded, newExtension
+		}
+
+		contentHash, hashErr := hashutil.HashBytes(assetData, resolveParam.HashAlgo())
+		if hashErr != nil {
+			missingAssetErrors[label] = ErrCauseHashError
+			continue
+		}
+
+		// The same image may be inlined on several pages, or also linked
+		if existingPath := r.findPathByHash(contentHash); existingPath != "" {
+			if r.debugLogger.Enabled() {
+				r.debugLogger.LogStep(ctx, "assets", "asset_content_dedup", debug.FieldMap{
+					"asset_url":     label,
+					"content_hash":  contentHash[:7],
+					"existing_path": existingPath,
+				})
+			}
+			r.recordAssetSource(contentHash, existingPath, int64(len(assetData)), label)
+			localPaths[raw] = existingPath
+			continue
+		}
+
+		localPath, err := r.writeAsset(resolveParam.OutputDir(), resolveParam.AssetNaming(), dataURIAssetURL, contentHash, extension, assetData)
+		if err != nil {
+			var assetsErr *AssetsError
+			if errors.As(err, &assetsErr) {
+				missingAssetErrors[label] = assetsErr.Cause
+			} else {
+				missingAssetErrors[label] = ErrCauseWriteFailure
+			}
+			continue
+		}
+
+		if r.debugLogger.Enabled() {
+			r.debugLogger.LogStep(ctx, "assets", "asset_written", debug.FieldMap{
+				"asset_url":    label,
+				"local_path":   localPath,
+				"content_hash": contentHash[:7],
+				"size_bytes":   len(assetData),
+			})
+		}
+
+		r.hashToPath[contentHash] = localPath
+		r.recordAssetSource(contentHash, localPath, int64(len(assetData)), label)
+		assetCallback(localPath, label, contentHash, int64(len(assetData)))
+		localPaths[raw] = localPath
+	}
+	return localPaths
+}
+
 // findPathByHash finds the stored path for a content hash.
 // This is used for content-hash deduplication.
 // Returns empty string if no file was written for this hash.
@@ -451,6 +592,7 @@ func (r *LocalResolver) fetchAssetWithRetry(
 	userAgent string,
 	retryOptions []retrier.RetryOption,
 	maxAssetSize int64,
+	retryJitter retryjitter.Source,
 ) retrier.Result[AssetFetchResult] {
 	fetchTask := func() (AssetFetchResult, error) {
 		result, err := r.performFetch(ctx, fetchUrl, userAgent, maxAssetSize)
@@ -460,7 +602,7 @@ func (r *LocalResolver) fetchAssetWithRetry(
 		return result, nil
 	}
 
-	return retrier.Retry(ctx, debug.AsRetryLogger(r.debugLogger), fetchTask, retryOptions...)
+	return retrier.Retry(ctx, debug.AsRetryLogger(r.debugLogger), retryjitter.Wrap(ctx, retryJitter, fetchTask), retryOptions...)
 }
 
 func (r *LocalResolver) performFetch(ctx context.Context, fetchUrl url.URL, userAgent string, maxAssetSize int64) (AssetFetchResult, failure.ClassifiedError) {
@@ -536,9 +678,16 @@ func (r *LocalResolver) performFetch(ctx context.Context, fetchUrl url.URL, user
 	return NewAssetFetchResult(fetchUrl, resp.StatusCode, duration, startTime, body), nil
 }
 
-func (r *LocalResolver) writeAsset(outputDir string, originalPath string, contentHash string, extension string, data []byte) (string, failure.ClassifiedError) {
-	localPath := buildAssetPath(originalPath, contentHash, extension)
+func (r *LocalResolver) writeAsset(outputDir string, naming AssetNaming, assetURL url.URL, contentHash string, extension string, data []byte) (string, failure.ClassifiedError) {
+	localPath := assetPath(naming, assetURL, contentHash, extension)
+	// Content-hash dedup already ran, so a path that is taken holds other content
+	if _, taken := r.assetRecords[localPath]; taken {
+		localPath = withShortHash(localPath, contentHash)
+	}
 	filePath := filepath.Join(outputDir, localPath)
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return "", NewAssetsError(ErrCausePathError, fmt.Sprintf("%v", err))
+	}
 
 	if err := os.WriteFile(filePath, data, 0644); err != nil {
 		// Check if disk is full
@@ -551,7 +700,7 @@ func (r *LocalResolver) writeAsset(outputDir string, originalPath string, conten
 	return localPath, nil
 }
 
-func (r *LocalResolver) constructLocalPaths(imageUrls []url.URL, host string, scheme string) map[string]string {
+func (r *LocalResolver) constructLocalPaths(imageUrls []url.URL, host string, scheme string, naming AssetNaming) map[string]string {
 	localPaths := make(map[string]string)
 
 	for _, imgURL := range imageUrls {
@@ -571,7 +720,7 @@ func (r *LocalResolver) constructLocalPaths(imageUrls []url.URL, host string, sc
 			if localPath == "" {
 				// No existing path found, build new path
 				extension := fileutil.GetFileExtension(canonical.Path)
-				localPath = buildAssetPath(canonical.Path, contentHash, extension)
+				localPath = assetPath(naming, canonical, contentHash, extension)
 			}
 
 			// Map the RAW URL (as it appears in markdown) to the local path
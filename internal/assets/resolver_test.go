@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -14,9 +15,11 @@ import (
 	"github.com/rohmanhakim/docs-crawler/internal/mdconvert"
 	"github.com/rohmanhakim/docs-crawler/internal/metadata"
 	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/rohmanhakim/docs-crawler/internal/sanitizer"
 	"github.com/rohmanhakim/docs-crawler/pkg/debug/debugtest"
 	"github.com/rohmanhakim/docs-crawler/pkg/hashutil"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/html"
 )
 
 // metadataSinkMock is an alias to the shared mock for backward compatibility
@@ -330,6 +333,56 @@ func TestResolve_MechanicalDeduplication_SinglePage(t *testing.T) {
 	assert.False(t, mockSink.RecordErrorCalled, "RecordError should not be called for successful assets")
 }
 
+func TestResolve_MechanicalDeduplication_SinglePage_MixedReferences(t *testing.T) {
+	// Arrange - the same image referenced as absolute, root-relative and
+	// query-carrying URLs in one document
+	var requests int
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("image-data"))
+	}))
+	defer server.Close()
+
+	mockSink := &metadataSinkMock{}
+	resolver := newTestResolver(mockSink)
+
+	references := []string{server.URL + "/image.png", "/image.png", "/image.png?v=2"}
+	var linkRefs []mdconvert.LinkRef
+	var lines []string
+	for i, ref := range references {
+		linkRefs = append(linkRefs, mdconvert.NewLinkRef(ref, mdconvert.KindImage))
+		lines = append(lines, fmt.Sprintf("![Img%d](%s)", i+1, ref))
+	}
+	conversionResult := mdconvert.NewConversionResult([]byte(strings.Join(lines, "\n\n")), linkRefs)
+	pageUrl, _ := url.Parse(server.URL + "/page")
+
+	// Act
+	resolveParam := assets.NewResolveParam(t.TempDir(), 10*1024*1024, hashutil.HashAlgoSHA256)
+	doc, err := resolver.Resolve(context.Background(), *pageUrl, conversionResult, resolveParam, testRetryOptions())
+
+	// Assert
+	assert.NoError(t, err)
+
+	// Assert - the image is downloaded, recorded and listed once
+	assert.Equal(t, 1, requests, "The image should be requested once")
+	assert.Len(t, mockSink.GetFetchRecords(), 1)
+	assert.Len(t, mockSink.GetArtifactRecords(), 1)
+	expectedLocalPath := buildExpectedPath("image", "2b700b7786d5a3f0cb487c3afaccb889fae829504a0ad1b70881e4643360f344", "png")
+	assert.Equal(t, []string{expectedLocalPath}, doc.LocalAssets())
+
+	// Assert - every reference is rewritten to the same local path
+	expected := strings.Join([]string{
+		"![Img1](" + expectedLocalPath + ")",
+		"![Img2](" + expectedLocalPath + ")",
+		"![Img3](" + expectedLocalPath + ")",
+	}, "\n\n")
+	assert.Equal(t, expected, string(doc.Content()))
+}
+
 func TestResolve_CrossCallDeduplication(t *testing.T) {
 	// Arrange - two Resolve() calls with same asset URL
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -398,6 +451,36 @@ func TestResolve_CrossCallDeduplication(t *testing.T) {
 	assert.Contains(t, output, expectedLocalPath)
 }
 
+func TestResolve_WrittenBytes_CountsNewWritesOnly(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("image-data-for-" + r.URL.Path))
+	}))
+	defer server.Close()
+
+	resolver := newTestResolver(&metadataSinkMock{})
+	resolveParam := assets.NewResolveParam(t.TempDir(), 10*1024*1024, hashutil.HashAlgoSHA256)
+	resolvePage := func(page string, images ...string) assets.AssetfulMarkdownDoc {
+		var linkRefs []mdconvert.LinkRef
+		var markdown string
+		for _, image := range images {
+			linkRefs = append(linkRefs, mdconvert.NewLinkRef(server.URL+image, mdconvert.KindImage))
+			markdown += "![Img](" + server.URL + image + ")\n"
+		}
+		pageURL, _ := url.Parse(server.URL + page)
+		doc, err := resolver.Resolve(context.Background(), *pageURL, mdconvert.NewConversionResult([]byte(markdown), linkRefs), resolveParam, testRetryOptions())
+		assert.NoError(t, err)
+		return doc
+	}
+
+	first := resolvePage("/page1", "/a.png", "/bb.png")
+	assert.Equal(t, int64(len("image-data-for-/a.png")+len("image-data-for-/bb.png")), first.WrittenBytes())
+
+	// The shared asset was written for the first page; only the new one counts
+	second := resolvePage("/page2", "/a.png", "/ccc.png")
+	assert.Equal(t, int64(len("image-data-for-/ccc.png")), second.WrittenBytes())
+}
+
 func TestResolve_NonImageLinksIgnored(t *testing.T) {
 	// Arrange
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -635,11 +718,12 @@ func TestResolve_AssetTooLarge_ContentLengthHeader(t *testing.T) {
 	// Assert - no error should be returned from Resolve (large assets are reported, not fatal)
 	assert.NoError(t, err)
 
-	// Assert - RecordError should be called for oversized asset
-	assert.True(t, mockSink.RecordErrorCalled, "RecordError should be called for oversized asset")
-	errorRecords := mockSink.GetErrorRecords()
-	assert.Len(t, errorRecords, 1, "Should have 1 error record for oversized asset")
-	assert.True(t, errorRecords[0].Cause() == metadata.CausePolicyDisallow || errorRecords[0].Cause() == metadata.CauseUnknown, "Expected CausePolicyDisallow or CauseUnknown, got %v", errorRecords[0].Cause())
+	// Assert - oversized asset is recorded as a skip, not an error
+	assert.False(t, mockSink.RecordErrorCalled, "RecordError should not be called for oversized asset")
+	assert.True(t, mockSink.RecordSkipCalled, "RecordSkip should be called for oversized asset")
+	assert.Len(t, mockSink.SkipEvents, 1, "Should have 1 skip event for oversized asset")
+	assert.Equal(t, metadata.SkipReasonAssetTooLarge, mockSink.SkipEvents[0].Reason())
+	assert.Equal(t, imageURL, mockSink.SkipEvents[0].SkippedURL())
 
 	// Assert - RecordArtifact should NOT be called for oversized asset
 	assert.False(t, mockSink.RecordArtifactCalled, "RecordArtifact should not be called for oversized asset")
@@ -685,11 +769,12 @@ func TestResolve_AssetTooLarge_UnknownContentLength(t *testing.T) {
 	// Assert - no error should be returned from Resolve (large assets are reported, not fatal)
 	assert.NoError(t, err)
 
-	// Assert - RecordError should be called for oversized asset
-	assert.True(t, mockSink.RecordErrorCalled, "RecordError should be called for oversized asset")
-	errorRecords := mockSink.GetErrorRecords()
-	assert.Len(t, errorRecords, 1, "Should have 1 error record for oversized asset")
-	assert.True(t, errorRecords[0].Cause() == metadata.CausePolicyDisallow || errorRecords[0].Cause() == metadata.CauseUnknown, "Expected CausePolicyDisallow or CauseUnknown, got %v", errorRecords[0].Cause())
+	// Assert - oversized asset is recorded as a skip, not an error
+	assert.False(t, mockSink.RecordErrorCalled, "RecordError should not be called for oversized asset")
+	assert.True(t, mockSink.RecordSkipCalled, "RecordSkip should be called for oversized asset")
+	assert.Len(t, mockSink.SkipEvents, 1, "Should have 1 skip event for oversized asset")
+	assert.Equal(t, metadata.SkipReasonAssetTooLarge, mockSink.SkipEvents[0].Reason())
+	assert.Equal(t, imageURL, mockSink.SkipEvents[0].SkippedURL())
 
 	// Assert - RecordArtifact should NOT be called for oversized asset
 	assert.False(t, mockSink.RecordArtifactCalled, "RecordArtifact should not be called for oversized asset")
@@ -736,20 +821,21 @@ func TestResolve_AssetTooLarge_LyingContentLength(t *testing.T) {
 	// Assert - no error should be returned from Resolve (large assets are reported, not fatal)
 	assert.NoError(t, err)
 
-	// Assert - RecordError should be called for oversized asset (caught by post-read check)
-	// The error may be:
-	// - CausePolicyDisallow (AssetTooLarge detected post-read)
-	// - CauseRetryFailure (read error retried and exhausted, when Go's HTTP transport
+	// Assert - exactly one outcome is recorded for the oversized asset.
+	// It may be:
+	// - a SkipReasonAssetTooLarge skip (AssetTooLarge detected post-read)
+	// - an error (read error retried and exhausted, when Go's HTTP transport
 	//   reports an error reading beyond Content-Length)
-	// - CauseUnknown (other error paths)
-	assert.True(t, mockSink.RecordErrorCalled, "RecordError should be called for oversized asset")
 	errorRecords := mockSink.GetErrorRecords()
-	assert.Len(t, errorRecords, 1, "Should have 1 error record for oversized asset")
-	validCause := errorRecords[0].Cause() == metadata.CausePolicyDisallow ||
-		errorRecords[0].Cause() == metadata.CauseUnknown ||
-		errorRecords[0].Cause() == metadata.CauseRetryFailure ||
-		errorRecords[0].Cause() == metadata.CauseContentInvalid
-	assert.True(t, validCause, "Expected CausePolicyDisallow, CauseUnknown, CauseRetryFailure, or CauseContentInvalid, got %v", errorRecords[0].Cause())
+	assert.Equal(t, 1, len(errorRecords)+len(mockSink.SkipEvents), "Should have exactly 1 skip or error for oversized asset")
+	if len(mockSink.SkipEvents) == 1 {
+		assert.Equal(t, metadata.SkipReasonAssetTooLarge, mockSink.SkipEvents[0].Reason())
+	} else if len(errorRecords) == 1 {
+		validCause := errorRecords[0].Cause() == metadata.CauseUnknown ||
+			errorRecords[0].Cause() == metadata.CauseRetryFailure ||
+			errorRecords[0].Cause() == metadata.CauseContentInvalid
+		assert.True(t, validCause, "Expected CauseUnknown, CauseRetryFailure, or CauseContentInvalid, got %v", errorRecords[0].Cause())
+	}
 
 	// Assert - RecordArtifact should NOT be called for oversized asset
 	assert.False(t, mockSink.RecordArtifactCalled, "RecordArtifact should not be called for oversized asset")
@@ -828,7 +914,8 @@ func TestResolve_AssetAtSizeBoundary(t *testing.T) {
 			} else {
 				// Asset should be rejected
 				assert.False(t, mockSink.RecordArtifactCalled, "RecordArtifact should not be called for oversized asset")
-				assert.True(t, mockSink.RecordErrorCalled, "RecordError should be called for oversized asset")
+				assert.False(t, mockSink.RecordErrorCalled, "RecordError should not be called for oversized asset")
+				assert.True(t, mockSink.RecordSkipCalled, "RecordSkip should be called for oversized asset")
 				writtenAssets := resolver.WrittenAssets()
 				assert.Equal(t, 0, len(writtenAssets), "Oversized asset should not be in writtenAssets")
 				output := string(doc.Content())
@@ -1201,3 +1288,52 @@ func TestResolve_QueryParamsDeduplication(t *testing.T) {
 	assert.Equal(t, 2, strings.Count(output, "assets/images/"),
 		"Both images should be rewritten to local paths")
 }
+
+func TestResolve_ResponsiveImages_FetchesChosenCandidate(t *testing.T) {
+	// Arrange - a server that serves every candidate and records requests
+	var (
+		mu        sync.Mutex
+		requested []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested = append(requested, r.URL.Path)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("image-bytes-for-" + r.URL.Path))
+	}))
+	defer server.Close()
+
+	pageHTML := `<html><body>
+<img src="/img/diagram-small.png" srcset="/img/diagram-1x.png 1x, /img/diagram-2x.png 2x" alt="Diagram">
+<picture>
+<source type="image/webp" srcset="/img/hero.webp 1x, /img/hero@2x.webp 2x">
+<source type="image/png" srcset="/img/hero.png 1x, /img/hero@2x.png 2x">
+<img src="/img/hero-placeholder.png" alt="Hero">
+</picture>
+</body></html>`
+	doc, parseErr := html.Parse(strings.NewReader(pageHTML))
+	assert.NoError(t, parseErr)
+	conversionResult, convErr := mdconvert.NewRule(&metadata.NoopSink{}).
+		Convert(sanitizer.NewSanitizedHTMLDoc(doc, nil), server.URL+"/page")
+	assert.NoError(t, convErr)
+
+	resolver := newTestResolver(&metadataSinkMock{})
+	pageUrl, _ := url.Parse(server.URL + "/page")
+
+	// Act
+	resolved, err := resolveWithTestParams(resolver, context.Background(), *pageUrl, conversionResult, t.TempDir())
+
+	// Assert - only the highest-resolution candidates are fetched
+	assert.NoError(t, err)
+	mu.Lock()
+	assert.ElementsMatch(t, []string{"/img/diagram-2x.png", "/img/hero@2x.webp"}, requested)
+	mu.Unlock()
+
+	// Assert - the markdown points at the downloaded candidates
+	content := string(resolved.Content())
+	assert.Contains(t, content, "![Diagram](assets/images/diagram-2x-")
+	assert.Contains(t, content, "![Hero](assets/images/hero@2x-")
+	assert.NotContains(t, content, "placeholder")
+	assert.NotContains(t, content, "/img/")
+}
@@ -0,0 +1,145 @@
+package assets
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+
+	// Registered for image.Decode
+	_ "image/gif"
+)
+
+/*
+Image Transcoding
+
+Image-heavy sites produce large asset directories. When ResolveParam sets an
+image max width or format, every fetched raster image (PNG, JPEG, GIF) is
+decoded before it is hashed and written:
+
+  - wider than the max width, it is downscaled to that width, keeping the
+    aspect ratio;
+  - with a format set, it is re-encoded in that format, changing the
+    extension of the written file.
+
+The content hash is taken from the transcoded bytes, so content-hash
+deduplication and the asset manifest describe what is on disk. Anything
+that does not decode as a raster image, SVG included, is written unchanged,
+as is a raster image that needs neither resizing nor conversion.
+
+Only formats with a standard library encoder are supported; WebP has none,
+so config validation rejects it.
+*/
+type ImageFormat string
+
+const (
+	ImageFormatPNG  ImageFormat = "png"
+	ImageFormatJPEG ImageFormat = "jpeg"
+)
+
+// jpegQuality is the quality used when re-encoding JPEG images.
+const jpegQuality = 85
+
+// IsValid reports whether f is a supported output format. The empty value
+// is valid and keeps each image's own format.
+func (f ImageFormat) IsValid() bool {
+	switch f {
+	case "", ImageFormatPNG, ImageFormatJPEG:
+		return true
+	}
+	return false
+}
+
+// extension returns the file extension written for the format.
+func (f ImageFormat) extension() string {
+	if f == ImageFormatJPEG {
+		return "jpg"
+	}
+	return string(f)
+}
+
+// transcodeImage downscales data to maxWidth and re-encodes it as format.
+// A maxWidth of 0 keeps the width and an empty format keeps the decoded
+// format (GIF becomes PNG when resized). It returns the bytes and extension
+// to write, and whether they differ from the input.
+func transcodeImage(data []byte, extension string, maxWidth int, format ImageFormat) ([]byte, string, bool) {
+	if maxWidth <= 0 && format == "" {
+		return data, extension, false
+	}
+	img, decoded, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		// Not a raster image (e.g. SVG): pass through
+		return data, extension, false
+	}
+
+	resize := maxWidth > 0 && img.Bounds().Dx() > maxWidth
+	if !resize && (format == "" || format == ImageFormat(decoded)) {
+		return data, extension, false
+	}
+	target := format
+	if target == "" {
+		target = ImageFormat(decoded)
+		if target != ImageFormatJPEG {
+			target = ImageFormatPNG
+		}
+	}
+
+	if resize {
+		img = downscale(img, maxWidth)
+	}
+	var buf bytes.Buffer
+	switch target {
+	case ImageFormatJPEG:
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality})
+	default:
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		return data, extension, false
+	}
+	return buf.Bytes(), target.extension(), true
+}
+
+// downscale resizes img to width, keeping the aspect ratio. Each
+// destination pixel is the average of the source pixels it covers.
+func downscale(img image.Image, width int) image.Image {
+	src := img.Bounds()
+	height := (src.Dy()*width + src.Dx()/2) / src.Dx()
+	if height < 1 {
+		height = 1
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := src.Min.Y + y*src.Dy()/height
+		y1 := src.Min.Y + (y+1)*src.Dy()/height
+		if y1 == y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < width; x++ {
+			x0 := src.Min.X + x*src.Dx()/width
+			x1 := src.Min.X + (x+1)*src.Dx()/width
+			if x1 == x0 {
+				x1 = x0 + 1
+			}
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					c := color.NRGBA64Model.Convert(img.At(sx, sy)).(color.NRGBA64)
+					r += uint64(c.R)
+					g += uint64(c.G)
+					b += uint64(c.B)
+					a += uint64(c.A)
+					n++
+				}
+			}
+			dst.Set(x, y, color.NRGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(b / n),
+				A: uint16(a / n),
+			})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,120 @@
+package assets_test
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/assets"
+	"github.com/rohmanhakim/docs-crawler/internal/mdconvert"
+	"github.com/rohmanhakim/docs-crawler/pkg/hashutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const transcodeTestSVG = `<svg xmlns="http://www.w3.org/2000/svg" width="2000" height="1000"><rect width="2000" height="1000"/></svg>`
+
+// largePNG encodes a width x height PNG filled with a gradient.
+func largePNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestResolve_ImageTranscoding(t *testing.T) {
+	pngData := largePNG(t, 400, 200)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/img/big.png":
+			w.Write(pngData)
+		case "/img/diagram.svg":
+			w.Write([]byte(transcodeTestSVG))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	resolver := newTestResolver(&metadataSinkMock{})
+	tempDir := t.TempDir()
+	markdown := "# Test\n\n![big](" + server.URL + "/img/big.png)\n\n![diagram](" + server.URL + "/img/diagram.svg)\n"
+	linkRefs := []mdconvert.LinkRef{
+		mdconvert.NewLinkRef(server.URL+"/img/big.png", mdconvert.KindImage),
+		mdconvert.NewLinkRef(server.URL+"/img/diagram.svg", mdconvert.KindImage),
+	}
+	pageURL, _ := url.Parse(server.URL + "/docs/page")
+	resolveParam := assets.NewResolveParam(tempDir, 10*1024*1024, hashutil.HashAlgoSHA256).
+		WithImageMaxWidth(100).
+		WithImageFormat(assets.ImageFormatJPEG)
+
+	doc, err := resolver.Resolve(context.Background(), *pageURL, mdconvert.NewConversionResult([]byte(markdown), linkRefs), resolveParam, testRetryOptions())
+	require.NoError(t, err)
+	require.Empty(t, doc.MissingAssets())
+
+	records := make(map[string]assets.AssetRecord)
+	for _, record := range resolver.AssetManifest() {
+		for _, source := range record.SourceURLs() {
+			records[strings.TrimPrefix(source, server.URL)] = record
+		}
+	}
+
+	// The PNG is downscaled to the max width and stored as JPEG
+	big := records["/img/big.png"]
+	assert.True(t, strings.HasSuffix(big.LocalPath(), ".jpg"), "expected a .jpg path, got %s", big.LocalPath())
+	written, readErr := os.ReadFile(filepath.Join(tempDir, big.LocalPath()))
+	require.NoError(t, readErr)
+	img, decodeErr := jpeg.Decode(bytes.NewReader(written))
+	require.NoError(t, decodeErr)
+	assert.Equal(t, 100, img.Bounds().Dx())
+	assert.Equal(t, 50, img.Bounds().Dy())
+	wantHash, _ := hashutil.HashBytes(written, hashutil.HashAlgoSHA256)
+	assert.Equal(t, wantHash, big.ContentHash())
+	assert.Contains(t, string(doc.Content()), "![big]("+big.LocalPath()+")")
+
+	// The SVG is written unchanged
+	diagram := records["/img/diagram.svg"]
+	assert.True(t, strings.HasSuffix(diagram.LocalPath(), ".svg"), "expected a .svg path, got %s", diagram.LocalPath())
+	svg, readErr := os.ReadFile(filepath.Join(tempDir, diagram.LocalPath()))
+	require.NoError(t, readErr)
+	assert.Equal(t, transcodeTestSVG, string(svg))
+}
+
+func TestResolve_ImageTranscoding_SmallImageUnchanged(t *testing.T) {
+	pngData := largePNG(t, 50, 20)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(pngData)
+	}))
+	defer server.Close()
+
+	resolver := newTestResolver(&metadataSinkMock{})
+	tempDir := t.TempDir()
+	linkRefs := []mdconvert.LinkRef{mdconvert.NewLinkRef(server.URL+"/img/small.png", mdconvert.KindImage)}
+	pageURL, _ := url.Parse(server.URL + "/docs/page")
+	resolveParam := assets.NewResolveParam(tempDir, 10*1024*1024, hashutil.HashAlgoSHA256).WithImageMaxWidth(100)
+
+	_, err := resolver.Resolve(context.Background(), *pageURL, mdconvert.NewConversionResult([]byte("![s]("+server.URL+"/img/small.png)\n"), linkRefs), resolveParam, testRetryOptions())
+	require.NoError(t, err)
+
+	manifest := resolver.AssetManifest()
+	require.Len(t, manifest, 1)
+	written, readErr := os.ReadFile(filepath.Join(tempDir, manifest[0].LocalPath()))
+	require.NoError(t, readErr)
+	assert.Equal(t, pngData, written)
+}
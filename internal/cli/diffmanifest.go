@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// diffManifestCmd compares the manifests of two crawl runs
+var diffManifestCmd = &cobra.Command{
+	Use:   "diff-manifest old.json new.json",
+	Short: "Compare two crawl manifests.",
+	Long: `diff-manifest compares the manifests of two crawl runs and reports which
+pages were added, removed, or changed (same URL, different content hash).`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		diff, err := DiffManifestFiles(args[0], args[1])
+		if err != nil {
+			return err
+		}
+		PrintManifestDiff(cmd.OutOrStdout(), diff)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffManifestCmd)
+}
+
+// DiffManifestFiles loads two manifest files and returns their difference.
+func DiffManifestFiles(oldPath string, newPath string) (storage.ManifestDiff, error) {
+	oldManifest, err := storage.ReadManifest(oldPath)
+	if err != nil {
+		return storage.ManifestDiff{}, fmt.Errorf("error loading old manifest: %w", err)
+	}
+	newManifest, err := storage.ReadManifest(newPath)
+	if err != nil {
+		return storage.ManifestDiff{}, fmt.Errorf("error loading new manifest: %w", err)
+	}
+	return storage.DiffManifests(oldManifest, newManifest), nil
+}
+
+// PrintManifestDiff writes a human-readable summary of a manifest diff.
+func PrintManifestDiff(w io.Writer, diff storage.ManifestDiff) {
+	fmt.Fprintf(w, "Added:   %d\n", len(diff.Added()))
+	fmt.Fprintf(w, "Removed: %d\n", len(diff.Removed()))
+	fmt.Fprintf(w, "Changed: %d\n", len(diff.Changed()))
+
+	for _, u := range diff.Added() {
+		fmt.Fprintf(w, "+ %s\n", u)
+	}
+	for _, u := range diff.Removed() {
+		fmt.Fprintf(w, "- %s\n", u)
+	}
+	for _, u := range diff.Changed() {
+		fmt.Fprintf(w, "~ %s\n", u)
+	}
+}
@@ -0,0 +1,61 @@
+package cmd_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	cmd "github.com/rohmanhakim/docs-crawler/internal/cli"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+)
+
+func TestDiffManifestFiles(t *testing.T) {
+	dir := t.TempDir()
+	oldPath := filepath.Join(dir, "old.json")
+	newPath := filepath.Join(dir, "new.json")
+
+	if err := storage.WriteManifest(oldPath, []storage.WriteResult{
+		storage.NewWriteResult("aaa", "out/aaa.md", "sha256:1").WithSourceURL("https://example.com/aaa"),
+		storage.NewWriteResult("bbb", "out/bbb.md", "sha256:2").WithSourceURL("https://example.com/bbb"),
+		storage.NewWriteResult("ccc", "out/ccc.md", "sha256:3").WithSourceURL("https://example.com/ccc"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.WriteManifest(newPath, []storage.WriteResult{
+		storage.NewWriteResult("aaa", "out/aaa.md", "sha256:1").WithSourceURL("https://example.com/aaa"),
+		storage.NewWriteResult("bbb", "out/bbb.md", "sha256:20").WithSourceURL("https://example.com/bbb"),
+		storage.NewWriteResult("ddd", "out/ddd.md", "sha256:4").WithSourceURL("https://example.com/ddd"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := cmd.DiffManifestFiles(oldPath, newPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	cmd.PrintManifestDiff(&buf, diff)
+
+	expected := "Added:   1\n" +
+		"Removed: 1\n" +
+		"Changed: 1\n" +
+		"+ https://example.com/ddd\n" +
+		"- https://example.com/ccc\n" +
+		"~ https://example.com/bbb\n"
+	if buf.String() != expected {
+		t.Errorf("unexpected output.\nexpected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestDiffManifestFiles_MissingFile(t *testing.T) {
+	dir := t.TempDir()
+	newPath := filepath.Join(dir, "new.json")
+	if err := storage.WriteManifest(newPath, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := cmd.DiffManifestFiles(filepath.Join(dir, "missing.json"), newPath); err == nil {
+		t.Error("expected error for missing old manifest")
+	}
+}
@@ -25,11 +25,13 @@ func NewEventPrinter(tp *treeprinter.TreePrinter) *EventPrinter {
 
 // PrintEvent prints a metadata event in tree-structured format.
 // Events are grouped by page URL:
-//   - FETCH (kind=page) starts a new parent node
+//   - FETCH (kind=page) starts a new parent node, with its request trace
+//     as the first child when the fetch was traced
 //   - PIPELINE events become children of the current page
 //   - ARTIFACT events become children of the current page
 //   - FETCH (kind=asset) becomes a child of the current page
 //   - SKIP and STATS are printed standalone
+//   - TUNING is a child of the current page, otherwise standalone
 //   - ERROR is a child if there's a current page context, otherwise standalone
 func (ep *EventPrinter) PrintEvent(e metadata.Event) {
 	switch e.Kind() {
@@ -50,6 +52,9 @@ func (ep *EventPrinter) PrintEvent(e metadata.Event) {
 
 	case metadata.EventKindStats:
 		ep.printStats(e.Stats())
+
+	case metadata.EventKindTuning:
+		ep.printTuning(e.Tuning())
 	}
 }
 
@@ -73,15 +78,44 @@ func (ep *EventPrinter) printFetch(fetch *metadata.FetchEvent) {
 	}
 
 	// Start new parent
-	ep.treePrinter.StartParent("[FETCH] %s - %d %s (%.3fs, depth=%d)",
+	cached := ""
+	if fetch.CacheHit() {
+		cached = ", cached"
+	}
+	ep.treePrinter.StartParent("[FETCH] %s - %d %s (%.3fs, depth=%d%s)",
 		fetch.FetchURL(),
 		fetch.HTTPStatus(),
 		fetch.ContentType(),
 		fetch.Duration().Seconds(),
-		fetch.CrawlDepth())
+		fetch.CrawlDepth(),
+		cached)
 
 	ep.currentPage = fetch.FetchURL()
 	ep.hasChildren = false
+
+	if trace := fetch.Trace(); trace != nil {
+		ep.printTrace(trace)
+	}
+}
+
+// printTrace prints the timings of a traced page fetch as its first child.
+func (ep *EventPrinter) printTrace(trace *metadata.RequestTrace) {
+	tlsInfo := "no TLS"
+	if trace.TLSVersion() != "" {
+		tlsInfo = trace.TLSVersion() + " " + trace.TLSCipherSuite()
+	}
+	reused := ""
+	if trace.ReusedConn() {
+		reused = ", reused conn"
+	}
+	ep.treePrinter.AddChild("[TRACE] dns=%s connect=%s tls=%s ttfb=%s (%s%s)",
+		trace.DNSLookup(),
+		trace.Connect(),
+		trace.TLSHandshake(),
+		trace.TimeToFirstByte(),
+		tlsInfo,
+		reused)
+	ep.hasChildren = true
 }
 
 // printArtifact handles ARTIFACT events as children of the current page.
@@ -156,6 +190,18 @@ func (ep *EventPrinter) printError(err *metadata.ErrorRecord) {
 	}
 }
 
+// printTuning handles TUNING events, attached to the page whose fetch
+// triggered the adjustment when there is one.
+func (ep *EventPrinter) printTuning(tuning *metadata.TuningEvent) {
+	format := "[TUNING] recommended_concurrency=%d delay=%s (%s)"
+	if ep.currentPage != "" {
+		ep.treePrinter.AddChild(format, tuning.RecommendedConcurrency(), tuning.Delay(), tuning.Reason())
+		ep.hasChildren = true
+		return
+	}
+	ep.treePrinter.PrintStandalone(format, tuning.RecommendedConcurrency(), tuning.Delay(), tuning.Reason())
+}
+
 // printStats handles STATS events, finalizing any current tree first.
 func (ep *EventPrinter) printStats(stats *metadata.CrawlStats) {
 	// Finalize any current parent
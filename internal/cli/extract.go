@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/assets"
+	"github.com/rohmanhakim/docs-crawler/internal/build"
+	"github.com/rohmanhakim/docs-crawler/internal/extractor"
+	"github.com/rohmanhakim/docs-crawler/internal/mdconvert"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/internal/normalize"
+	"github.com/rohmanhakim/docs-crawler/internal/sanitizer"
+	"github.com/rohmanhakim/docs-crawler/pkg/hashutil"
+	"github.com/spf13/cobra"
+)
+
+var extractBaseURL string
+
+// extractCmd converts a local HTML file without crawling
+var extractCmd = &cobra.Command{
+	Use:   "extract file.html [--base-url URL]",
+	Short: "Convert a local HTML file to Markdown.",
+	Long: `extract runs a local HTML file through extraction, sanitization, conversion
+and normalization, and prints the resulting Markdown to stdout, for debugging
+extraction. Nothing is crawled or fetched: assets are not downloaded, and
+image links keep pointing at the site.
+
+--base-url is the URL the page was served from. Relative links and images
+are resolved against it; without it they are left as written.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		markdown, err := ExtractFile(args[0], extractBaseURL)
+		if err != nil {
+			return err
+		}
+		_, err = cmd.OutOrStdout().Write(markdown)
+		return err
+	},
+}
+
+func init() {
+	extractCmd.Flags().StringVar(&extractBaseURL, "base-url", "", "URL the page was served from, to resolve relative links against")
+	rootCmd.AddCommand(extractCmd)
+}
+
+// ExtractFile reads the HTML file at path and returns its Markdown as the
+// crawl would write it for the page at baseURL. An empty baseURL leaves
+// relative links as written.
+func ExtractFile(path string, baseURL string) ([]byte, error) {
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", path, err)
+	}
+
+	var pageURL url.URL
+	if baseURL == "" {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return nil, fmt.Errorf("error resolving %s: %w", path, err)
+		}
+		pageURL = url.URL{Scheme: "file", Path: filepath.ToSlash(absPath)}
+	} else {
+		parsed, err := url.Parse(baseURL)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing base URL %s: %w", baseURL, err)
+		}
+		if parsed.Scheme != "http" && parsed.Scheme != "https" {
+			return nil, fmt.Errorf("base URL %s must be an http(s) URL", baseURL)
+		}
+		pageURL = *parsed
+	}
+	return ExtractHTML(body, pageURL)
+}
+
+// ExtractHTML runs body through extraction, sanitization, conversion and
+// normalization as the page at pageURL, leaving assets on the site, and
+// returns the resulting Markdown.
+func ExtractHTML(body []byte, pageURL url.URL) ([]byte, error) {
+	sink := &metadata.NoopSink{}
+
+	domExtractor := extractor.NewDomExtractor(sink)
+	extractionResult, err := domExtractor.Extract(pageURL, body)
+	if err != nil {
+		return nil, fmt.Errorf("error extracting content: %w", err)
+	}
+
+	var conversionResult mdconvert.ConversionResult
+	if extractionResult.Markdown != nil {
+		conversionResult = mdconvert.NewConversionResult(extractionResult.Markdown, nil)
+	} else {
+		htmlSanitizer := sanitizer.NewHTMLSanitizer(sink)
+		sanitized, err := htmlSanitizer.Sanitize(extractionResult.ContentNode)
+		if err != nil {
+			return nil, fmt.Errorf("error sanitizing content: %w", err)
+		}
+		conversionResult, err = mdconvert.NewRule(sink).Convert(sanitized, pageURL.String())
+		if err != nil {
+			return nil, fmt.Errorf("error converting content: %w", err)
+		}
+	}
+
+	// Links to other pages are made absolute: none of them is in an output
+	noPages := func(url.URL) (string, bool) { return "", false }
+	normalizeParam := normalize.NewNormalizeParam(
+		build.FullVersion(),
+		time.Now(),
+		hashutil.HashAlgoSHA256,
+		0,
+		nil,
+	).WithStructuredSections(extractionResult.StructuredSections).
+		WithLinkRewriting("", noPages)
+	constraint := normalize.NewMarkdownConstraint(sink)
+	normalized, err := constraint.Normalize(pageURL, assets.RemoteAssets(pageURL, conversionResult), normalizeParam)
+	if err != nil {
+		return nil, fmt.Errorf("error normalizing content: %w", err)
+	}
+	return normalized.Content(), nil
+}
@@ -0,0 +1,76 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	cmd "github.com/rohmanhakim/docs-crawler/internal/cli"
+)
+
+const extractPageHTML = `<!DOCTYPE html>
+<html>
+<head><title>Install</title></head>
+<body>
+<nav><a href="/">Home</a></nav>
+<main>
+<h1>Installation</h1>
+<p>Download the tool and follow the <a href="../guide/setup">setup guide</a>
+to configure it. See also the <a href="https://example.org/faq">FAQ</a>.</p>
+<p><img src="img/diagram.png" alt="Diagram"></p>
+<h2>Requirements</h2>
+<p>You need a recent operating system and a working network connection.</p>
+</main>
+</body>
+</html>
+`
+
+func writeExtractPageForTest(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "page.html")
+	if err := os.WriteFile(path, []byte(extractPageHTML), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestExtractFile(t *testing.T) {
+	markdown, err := cmd.ExtractFile(writeExtractPageForTest(t), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "# Installation\n\n" +
+		"Download the tool and follow the [setup guide](../guide/setup) to configure it. See also the [FAQ](https://example.org/faq).\n\n" +
+		"![Diagram](img/diagram.png)\n\n" +
+		"## Requirements\n\n" +
+		"You need a recent operating system and a working network connection."
+	if string(markdown) != expected {
+		t.Errorf("unexpected output.\nexpected:\n%s\ngot:\n%s", expected, markdown)
+	}
+}
+
+func TestExtractFile_BaseURLResolvesRelativeLinks(t *testing.T) {
+	markdown, err := cmd.ExtractFile(writeExtractPageForTest(t), "https://docs.example.com/docs/install/")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "# Installation\n\n" +
+		"Download the tool and follow the [setup guide](https://docs.example.com/docs/guide/setup) to configure it. See also the [FAQ](https://example.org/faq).\n\n" +
+		"![Diagram](https://docs.example.com/docs/install/img/diagram.png)\n\n" +
+		"## Requirements\n\n" +
+		"You need a recent operating system and a working network connection."
+	if string(markdown) != expected {
+		t.Errorf("unexpected output.\nexpected:\n%s\ngot:\n%s", expected, markdown)
+	}
+}
+
+func TestExtractFile_Errors(t *testing.T) {
+	if _, err := cmd.ExtractFile(filepath.Join(t.TempDir(), "missing.html"), ""); err == nil {
+		t.Error("expected error for a missing file")
+	}
+	if _, err := cmd.ExtractFile(writeExtractPageForTest(t), "ftp://docs.example.com/"); err == nil {
+		t.Error("expected error for a non-http base URL")
+	}
+}
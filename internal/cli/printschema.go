@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// printSchemaCmd prints the JSON Schema of config files
+var printSchemaCmd = &cobra.Command{
+	Use:   "print-schema",
+	Short: "Print the JSON Schema of config files.",
+	Long: `print-schema writes the JSON Schema describing config files to stdout, for
+validating configs in CI or enabling completion in editors:
+
+  docs-crawler print-schema > docs-crawler.schema.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, err := cmd.OutOrStdout().Write(config.JSONSchema())
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(printSchemaCmd)
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"net/url"
 	"os"
@@ -30,8 +31,12 @@ var (
 	jitter            time.Duration
 	randomSeed        int64
 	allowedHosts      []string
+	includeSubdomains bool
+	onlyHost          string
 	allowedPathPrefix []string
 	selectorBlacklist []string
+	maxBytes          int64
+	imageFormat       string
 	versionFlag       bool
 	// Debug logging flags
 	debug       bool
@@ -115,11 +120,7 @@ producing high-quality Markdown suitable for embedding and retrieval.`,
 				fmt.Printf("Seed URLs: %s\n", strings.Join(urls, ", "))
 			}
 			if len(cfg.AllowedHosts()) > 0 {
-				var hosts []string
-				for host := range cfg.AllowedHosts() {
-					hosts = append(hosts, host)
-				}
-				fmt.Printf("Allowed Hosts: %s\n", strings.Join(hosts, ", "))
+				fmt.Printf("Allowed Hosts: %s\n", strings.Join(cfg.SortedAllowedHosts(), ", "))
 			}
 			if len(cfg.AllowedPathPrefix()) > 0 {
 				fmt.Printf("Allowed Path Prefixes: %s\n", strings.Join(cfg.AllowedPathPrefix(), ", "))
@@ -184,7 +185,10 @@ producing high-quality Markdown suitable for embedding and retrieval.`,
 		}
 		exec, err := sched.ExecuteCrawlingWithState(init)
 
-		if err != nil {
+		if errors.Is(err, scheduler.ErrOutputBudgetExceeded) {
+			// The crawl stopped early but what it wrote is complete
+			fmt.Fprintf(os.Stderr, "Warning: crawl stopped: %v\n", err)
+		} else if err != nil {
 			fmt.Fprintf(os.Stderr, "Error during crawl: %v\n", err)
 			os.Exit(1)
 		}
@@ -202,6 +206,9 @@ producing high-quality Markdown suitable for embedding and retrieval.`,
 					fmt.Printf("Pages processed: %d\n", exec.TotalPages())
 					fmt.Printf("Errors:          %d\n", exec.TotalErrors())
 					fmt.Printf("Assets resolved: %d\n", exec.TotalAssets())
+					if reason := exec.StopReason(); reason != scheduler.StopReasonCompleted {
+						fmt.Printf("Stopped by:      %s\n", reason)
+					}
 
 					if cfg.DryRun() {
 						fmt.Println("\nDRY RUN - No files were written.")
@@ -233,12 +240,18 @@ func init() {
 	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "crawl without writing output")
 	rootCmd.PersistentFlags().StringVar(&dumpStageOutput, "dump-stage-output", "", "directory to dump intermediate stage outputs (for debugging)")
 	rootCmd.PersistentFlags().IntVar(&maxPages, "max-pages", 0, "maximum number of pages to fetch (0 for unlimited)")
+	rootCmd.PersistentFlags().Int64Var(&maxBytes, "max-bytes", 0, "stop the crawl once pages and assets written exceed this many bytes, uncompressed (0 for unlimited)")
+	rootCmd.PersistentFlags().StringVar(&imageFormat, "image-format", "", "re-encode raster image assets as png or jpeg before writing them (webp is not supported; empty keeps their format)")
 	rootCmd.PersistentFlags().StringVar(&userAgent, "user-agent", "", "user agent string for HTTP requests")
 	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 0, "timeout for HTTP requests")
 	rootCmd.PersistentFlags().DurationVar(&baseDelay, "base-delay", 0, "base delay between HTTP requests to the same host")
 	rootCmd.PersistentFlags().DurationVar(&jitter, "jitter", 0, "random jitter added to base delay")
 	rootCmd.PersistentFlags().Int64Var(&randomSeed, "random-seed", 0, "seed for random number generation (0 for current time)")
-	rootCmd.PersistentFlags().StringArrayVar(&allowedHosts, "allowed-host", []string{}, "explicit hostname allowlist (defaults to seed host)")
+	rootCmd.PersistentFlags().StringArrayVar(&allowedHosts, "allowed-host", []string{}, "explicit hostname allowlist, wildcards like *.example.com allowed (defaults to seed host)")
+	rootCmd.PersistentFlags().BoolVar(&includeSubdomains, "include-subdomains", false, "treat every subdomain of the seed hosts as in scope")
+	rootCmd.PersistentFlags().StringVar(&onlyHost, "only-host", "", "limit the run to one host: seeds on other hosts are dropped and only links to it are followed, whatever the config allows")
+	rootCmd.PersistentFlags().StringVar(&onlyHost, "limit-host", "", "alias of --only-host")
+	rootCmd.PersistentFlags().MarkHidden("limit-host")
 	rootCmd.PersistentFlags().StringArrayVar(&allowedPathPrefix, "allowed-path-prefix", []string{}, "restrict crawl to paths like `/docs`, `/guide`")
 	rootCmd.PersistentFlags().StringArrayVar(&selectorBlacklist, "selector-blacklist", []string{}, "CSS selectors for elements to remove before extraction (e.g., .promo-banner, #ad)")
 	// Debug logging flags
@@ -310,6 +323,14 @@ func InitConfigWithError(seedUrls []url.URL) (config.Config, error) {
 		configBuilder = configBuilder.WithMaxPages(maxPages)
 	}
 
+	if maxBytes > 0 {
+		configBuilder = configBuilder.WithMaxOutputBytes(maxBytes)
+	}
+
+	if imageFormat != "" {
+		configBuilder = configBuilder.WithImageFormat(imageFormat)
+	}
+
 	if userAgent != "" {
 		configBuilder = configBuilder.WithUserAgent(userAgent)
 	}
@@ -334,6 +355,14 @@ func InitConfigWithError(seedUrls []url.URL) (config.Config, error) {
 		configBuilder = configBuilder.WithAllowedHosts(parseStringSliceToSet(allowedHosts))
 	}
 
+	if includeSubdomains {
+		configBuilder = configBuilder.WithIncludeSubdomains(includeSubdomains)
+	}
+
+	if onlyHost != "" {
+		configBuilder = configBuilder.WithOnlyHost(onlyHost)
+	}
+
 	if len(allowedPathPrefix) > 0 {
 		configBuilder = configBuilder.WithAllowedPathPrefix(allowedPathPrefix)
 	}
@@ -357,6 +386,9 @@ func InitConfigWithError(seedUrls []url.URL) (config.Config, error) {
 	if err != nil {
 		return config.Config{}, err
 	}
+	for _, warning := range cfg.Warnings() {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
 	return cfg, nil
 }
 
@@ -369,12 +401,16 @@ func ResetFlags() {
 	dryRun = false
 	dumpStageOutput = ""
 	maxPages = 0
+	maxBytes = 0
+	imageFormat = ""
 	userAgent = ""
 	timeout = 0
 	baseDelay = 0
 	jitter = 0
 	randomSeed = 0
 	allowedHosts = []string{}
+	includeSubdomains = false
+	onlyHost = ""
 	allowedPathPrefix = []string{}
 	selectorBlacklist = []string{}
 	versionFlag = false
@@ -412,6 +448,14 @@ func SetMaxPagesForTest(pages int) {
 	maxPages = pages
 }
 
+func SetMaxBytesForTest(bytes int64) {
+	maxBytes = bytes
+}
+
+func SetImageFormatForTest(format string) {
+	imageFormat = format
+}
+
 func SetUserAgentForTest(agent string) {
 	userAgent = agent
 }
@@ -436,6 +480,14 @@ func SetAllowedHostsForTest(hosts []string) {
 	allowedHosts = hosts
 }
 
+func SetIncludeSubdomainsForTest(enabled bool) {
+	includeSubdomains = enabled
+}
+
+func SetOnlyHostForTest(host string) {
+	onlyHost = host
+}
+
 func SetAllowedPathPrefixForTest(prefixes []string) {
 	allowedPathPrefix = prefixes
 }
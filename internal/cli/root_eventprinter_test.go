@@ -141,6 +141,7 @@ func TestEventPrinter_SkipEvent(t *testing.T) {
 		"https://example.com/admin",
 		metadata.SkipReasonRobotsDisallow,
 		time.Now(),
+		nil,
 	))
 
 	// Print all events
@@ -159,6 +160,26 @@ func TestEventPrinter_SkipEvent(t *testing.T) {
 	}
 }
 
+// TestEventPrinter_TuningEvent verifies the tuner's concurrency is printed as a recommendation.
+func TestEventPrinter_TuningEvent(t *testing.T) {
+	var buf bytes.Buffer
+	tp := treeprinter.NewTreePrinterWithWriter(&buf)
+	ep := NewEventPrinter(tp)
+	rec := metadata.NewRecorder("test")
+
+	rec.RecordTuning(metadata.NewTuningEvent(2, time.Second, metadata.TuningReasonThrottled, time.Now()))
+
+	for _, e := range rec.Events() {
+		ep.PrintEvent(e)
+	}
+	ep.Flush()
+
+	output := buf.String()
+	if !bytes.Contains([]byte(output), []byte("[TUNING] recommended_concurrency=2 delay=1s (throttled)")) {
+		t.Errorf("expected TUNING line, got:\n%s", output)
+	}
+}
+
 // TestEventPrinter_AssetFetch verifies asset fetches are children of the current page.
 func TestEventPrinter_AssetFetch(t *testing.T) {
 	var buf bytes.Buffer
@@ -201,6 +222,43 @@ func TestEventPrinter_AssetFetch(t *testing.T) {
 	}
 }
 
+// TestEventPrinter_TracedFetch verifies a traced page fetch prints its timings as the first child.
+func TestEventPrinter_TracedFetch(t *testing.T) {
+	var buf bytes.Buffer
+	tp := treeprinter.NewTreePrinterWithWriter(&buf)
+	ep := NewEventPrinter(tp)
+	rec := metadata.NewRecorder("test")
+
+	rec.RecordFetch(metadata.NewFetchEvent(
+		time.Now(),
+		"https://example.com/page.html",
+		200,
+		100*time.Millisecond,
+		"text/html",
+		0, 0,
+		metadata.KindPage,
+	).WithTrace(metadata.NewRequestTrace(
+		2*time.Millisecond,
+		3*time.Millisecond,
+		5*time.Millisecond,
+		40*time.Millisecond,
+		"TLS 1.3",
+		"TLS_AES_128_GCM_SHA256",
+		false,
+	)))
+
+	for _, e := range rec.Events() {
+		ep.PrintEvent(e)
+	}
+	ep.Flush()
+
+	output := buf.String()
+	expected := "└── [TRACE] dns=2ms connect=3ms tls=5ms ttfb=40ms (TLS 1.3 TLS_AES_128_GCM_SHA256)"
+	if !bytes.Contains([]byte(output), []byte(expected)) {
+		t.Errorf("expected trace child %q, got:\n%s", expected, output)
+	}
+}
+
 // TestEventPrinter_StatsFinalizesParent verifies STATS events finalize the current tree.
 func TestEventPrinter_StatsFinalizesParent(t *testing.T) {
 	var buf bytes.Buffer
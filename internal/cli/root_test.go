@@ -1,6 +1,7 @@
 package cmd_test
 
 import (
+	"encoding/json"
 	"errors"
 	"net/url"
 	"os"
@@ -754,6 +755,50 @@ func TestInitConfigWithMaxPages(t *testing.T) {
 	}
 }
 
+// TestInitConfigWithMaxBytes tests that the max-bytes flag sets the output budget
+func TestInitConfigWithMaxBytes(t *testing.T) {
+	cmd.ResetFlags()
+	cmd.SetMaxBytesForTest(4096)
+
+	cfg, err := cmd.InitConfigWithError(defaultTestURLs())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cfg.MaxOutputBytes() != 4096 {
+		t.Errorf("Expected MaxOutputBytes 4096, got %d", cfg.MaxOutputBytes())
+	}
+
+	cmd.ResetFlags()
+	cfg, err = cmd.InitConfigWithError(defaultTestURLs())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cfg.MaxOutputBytes() != 0 {
+		t.Errorf("Expected no output budget without the flag, got %d", cfg.MaxOutputBytes())
+	}
+}
+
+func TestInitConfigWithImageFormat(t *testing.T) {
+	cmd.ResetFlags()
+	cmd.SetImageFormatForTest("jpeg")
+
+	cfg, err := cmd.InitConfigWithError(defaultTestURLs())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cfg.ImageFormat() != "jpeg" {
+		t.Errorf("Expected ImageFormat jpeg, got %q", cfg.ImageFormat())
+	}
+
+	cmd.ResetFlags()
+	cmd.SetImageFormatForTest("webp")
+	_, err = cmd.InitConfigWithError(defaultTestURLs())
+	if !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("Expected ErrInvalidConfig for webp, got %v", err)
+	}
+	cmd.ResetFlags()
+}
+
 // TestInitConfigWithUserAgent tests that userAgent flag is properly applied
 func TestInitConfigWithUserAgent(t *testing.T) {
 	tests := []struct {
@@ -1077,6 +1122,50 @@ func TestInitConfigAllowedHostsDefaultsToSeedUrls(t *testing.T) {
 	}
 }
 
+// TestInitConfigWithOnlyHost tests that --only-host drops seeds on other hosts
+// and narrows admission to that host, whatever allowedHosts says
+func TestInitConfigWithOnlyHost(t *testing.T) {
+	cmd.ResetFlags()
+	cmd.SetAllowedHostsForTest([]string{"docs.example.com", "blog.example.com", "api.example.com"})
+	cmd.SetOnlyHostForTest("docs.example.com")
+	defer cmd.ResetFlags()
+
+	seedURLs := []url.URL{
+		{Scheme: "https", Host: "docs.example.com", Path: "/guide"},
+		{Scheme: "https", Host: "blog.example.com"},
+	}
+
+	cfg, err := cmd.InitConfigWithError(seedURLs)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(cfg.SeedURLs()) != 1 || cfg.SeedURLs()[0].Host != "docs.example.com" {
+		t.Errorf("Expected only the docs.example.com seed, got %v", cfg.SeedURLs())
+	}
+	if !cfg.IsHostAllowed("docs.example.com") {
+		t.Errorf("Expected docs.example.com to be admitted")
+	}
+	for _, host := range []string{"blog.example.com", "api.example.com"} {
+		if cfg.IsHostAllowed(host) {
+			t.Errorf("Expected cross-host links to %s not to be admitted", host)
+		}
+	}
+}
+
+// TestInitConfigWithOnlyHostWithoutMatchingSeed tests that --only-host fails
+// when it would leave the run without seeds
+func TestInitConfigWithOnlyHostWithoutMatchingSeed(t *testing.T) {
+	cmd.ResetFlags()
+	cmd.SetOnlyHostForTest("docs.example.com")
+	defer cmd.ResetFlags()
+
+	_, err := cmd.InitConfigWithError([]url.URL{{Scheme: "https", Host: "blog.example.com"}})
+	if !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("Expected ErrInvalidConfig, got %v", err)
+	}
+}
+
 // TestInitConfigWithConfigFileAndCLIDefaults tests that config file values
 // are preserved when CLI flags are not explicitly set (using Cobra defaults).
 // This is a regression test for bugs where CLI default values would overwrite
@@ -1206,6 +1295,55 @@ func TestInitConfigWithConfigFileAndCLIOverride(t *testing.T) {
 	}
 }
 
+// TestInitConfigSnapshotReflectsCLIOverride tests that the effective config
+// snapshot records CLI values over config file values
+func TestInitConfigSnapshotReflectsCLIOverride(t *testing.T) {
+	cmd.ResetFlags()
+
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, "config.json")
+	configContent := `{
+		"seedUrls": ["https://example.com/docs"],
+		"maxDepth": 1,
+		"userAgent": "file-agent/1.0"
+	}`
+	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+
+	cmd.SetConfigFileForTest(configFile)
+	cmd.SetMaxDepthForTest(10)
+	cmd.SetBaseDelayForTest(2 * time.Second)
+
+	cfg, err := cmd.InitConfigWithError([]url.URL{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	snapshot, err := config.EncodeSnapshot(cfg, "run-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var fields struct {
+		RunID     string `json:"runId"`
+		MaxDepth  int    `json:"maxDepth"`
+		BaseDelay string `json:"baseDelay"`
+		UserAgent string `json:"userAgent"`
+	}
+	if err := json.Unmarshal(snapshot, &fields); err != nil {
+		t.Fatalf("Snapshot is not valid JSON: %v", err)
+	}
+	if fields.RunID != "run-1" {
+		t.Errorf("Expected runId run-1, got %q", fields.RunID)
+	}
+	if fields.MaxDepth != 10 || fields.BaseDelay != "2s" {
+		t.Errorf("Expected CLI maxDepth 10 and baseDelay 2s, got %d and %s", fields.MaxDepth, fields.BaseDelay)
+	}
+	if fields.UserAgent != "file-agent/1.0" {
+		t.Errorf("Expected userAgent from the config file, got %q", fields.UserAgent)
+	}
+}
+
 // TestSuppressDefaultOutput tests the SuppressDefaultOutput config method
 func TestSuppressDefaultOutput(t *testing.T) {
 	tests := []struct {
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/scheduler"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	verifyManifestPath string
+	verifyRepair       bool
+)
+
+// verifyCmd checks previously written outputs against a crawl manifest
+var verifyCmd = &cobra.Command{
+	Use:   "verify --config-file config.json --manifest manifest.json",
+	Short: "Verify written outputs against a crawl manifest.",
+	Long: `verify re-hashes every file listed in a crawl manifest and reports files
+that are missing or whose content no longer matches the recorded hash.
+
+The output directory and hash algorithm are taken from the config. With
+--repair, only the pages behind missing or corrupt entries are re-fetched
+and rewritten, their new hashes are merged into the manifest, and the
+outputs are verified again. Entries from manifests written before page URLs
+were recorded cannot be repaired; re-run the crawl instead.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if verifyManifestPath == "" {
+			return fmt.Errorf("--manifest is required")
+		}
+		cfg, err := InitConfigWithError(nil)
+		if err != nil {
+			return err
+		}
+
+		report, err := VerifyManifestFile(cfg, verifyManifestPath)
+		if err != nil {
+			return err
+		}
+
+		if !report.IsClean() && verifyRepair {
+			PrintVerifyReport(cmd.OutOrStdout(), report)
+			fmt.Fprintf(cmd.OutOrStdout(), "Repairing: re-crawling %d page(s)...\n", len(report.Issues()))
+			if err := repairOutputs(cfg, verifyManifestPath, report); err != nil {
+				return err
+			}
+			report, err = VerifyManifestFile(cfg, verifyManifestPath)
+			if err != nil {
+				return err
+			}
+		}
+
+		PrintVerifyReport(cmd.OutOrStdout(), report)
+		if !report.IsClean() {
+			return fmt.Errorf("verification failed: %d issue(s)", len(report.Issues()))
+		}
+		return nil
+	},
+}
+
+func init() {
+	verifyCmd.Flags().StringVar(&verifyManifestPath, "manifest", "", "manifest file path produced by a previous crawl")
+	verifyCmd.Flags().BoolVar(&verifyRepair, "repair", false, "re-crawl the pages behind missing or corrupt outputs")
+	rootCmd.AddCommand(verifyCmd)
+}
+
+// VerifyManifestFile loads a manifest file and verifies it against the
+// output directory and hash algorithm of cfg.
+func VerifyManifestFile(cfg config.Config, manifestPath string) (storage.VerifyReport, error) {
+	results, err := storage.ReadManifest(manifestPath)
+	if err != nil {
+		return storage.VerifyReport{}, fmt.Errorf("error loading manifest: %w", err)
+	}
+	report, err := storage.VerifyManifest(cfg.OutputDir(), results, cfg.HashAlgo())
+	if err != nil {
+		return storage.VerifyReport{}, fmt.Errorf("error verifying outputs: %w", err)
+	}
+	return report, nil
+}
+
+// PrintVerifyReport writes a human-readable summary of a verify report.
+func PrintVerifyReport(w io.Writer, report storage.VerifyReport) {
+	fmt.Fprintf(w, "Checked: %d\n", report.Checked())
+	fmt.Fprintf(w, "Missing: %d\n", len(report.Missing()))
+	fmt.Fprintf(w, "Corrupt: %d\n", len(report.Corrupt()))
+
+	for _, issue := range report.Issues() {
+		fmt.Fprintf(w, "%s %s (%s)\n", issue.Category(), issue.URLHash(), issue.Path())
+	}
+}
+
+// repairOutputs re-crawls the pages behind the issues of report and merges
+// their write results into the manifest at manifestPath, so pages whose
+// content changed since the original run verify against their new hash.
+func repairOutputs(cfg config.Config, manifestPath string, report storage.VerifyReport) error {
+	results, readErr := storage.ReadManifest(manifestPath)
+	if readErr != nil {
+		return fmt.Errorf("error loading manifest: %w", readErr)
+	}
+
+	var pages []url.URL
+	for _, issue := range report.Issues() {
+		if issue.SourceURL() == "" {
+			return fmt.Errorf("manifest entry %s has no page URL; re-run the crawl to repair it", issue.URLHash())
+		}
+		page, err := url.Parse(issue.SourceURL())
+		if err != nil {
+			return fmt.Errorf("invalid page URL for manifest entry %s: %w", issue.URLHash(), err)
+		}
+		pages = append(pages, *page)
+	}
+
+	sched := scheduler.NewSchedulerWithConfig(cfg)
+	execution, err := sched.RecrawlPages(cfg, pages)
+	if err != nil {
+		return fmt.Errorf("error during re-crawl: %w", err)
+	}
+
+	merged := storage.MergeManifests(results, execution.WriteResults())
+	if err := storage.WriteManifest(manifestPath, merged); err != nil {
+		return fmt.Errorf("error writing manifest: %w", err)
+	}
+	return nil
+}
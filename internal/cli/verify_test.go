@@ -0,0 +1,76 @@
+package cmd_test
+
+import (
+	"bytes"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	cmd "github.com/rohmanhakim/docs-crawler/internal/cli"
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/rohmanhakim/docs-crawler/pkg/hashutil"
+)
+
+func TestVerifyManifestFile(t *testing.T) {
+	dir := t.TempDir()
+	manifestPath := filepath.Join(dir, "manifest.json")
+
+	var results []storage.WriteResult
+	for urlHash, content := range map[string]string{"aaa": "# A\n", "bbb": "# B\n", "ccc": "# C\n"} {
+		path := filepath.Join(dir, urlHash+".md")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		hash, err := hashutil.HashBytes([]byte(content), hashutil.HashAlgoSHA256)
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, storage.NewWriteResult(urlHash, path, "sha256:"+hash))
+	}
+	if err := storage.WriteManifest(manifestPath, results); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "bbb.md"), []byte("# B, edited\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(dir, "ccc.md")); err != nil {
+		t.Fatal(err)
+	}
+
+	seed, _ := url.Parse("https://example.com")
+	cfg, err := config.WithDefault([]url.URL{*seed}).WithOutputDir(dir).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := cmd.VerifyManifestFile(cfg, manifestPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	cmd.PrintVerifyReport(&buf, report)
+
+	expected := "Checked: 3\n" +
+		"Missing: 1\n" +
+		"Corrupt: 1\n" +
+		"corrupt bbb (" + filepath.Join(dir, "bbb.md") + ")\n" +
+		"missing ccc (" + filepath.Join(dir, "ccc.md") + ")\n"
+	if buf.String() != expected {
+		t.Errorf("unexpected output.\nexpected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestVerifyManifestFile_MissingManifest(t *testing.T) {
+	seed, _ := url.Parse("https://example.com")
+	cfg, err := config.WithDefault([]url.URL{*seed}).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := cmd.VerifyManifestFile(cfg, filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected error for missing manifest")
+	}
+}
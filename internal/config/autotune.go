@@ -0,0 +1,112 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// AutoTune bounds the crawl auto-tuner, which adjusts the delay between
+// requests from the responses it observes. When enabled, the tuner starts at
+// MaxDelay and never leaves these bounds; the configured baseDelay is then
+// only used when it is disabled. The tuner also reports a recommended
+// concurrency between MinConcurrency and MaxConcurrency; it is advisory,
+// since pages are fetched one at a time.
+type AutoTune struct {
+	enabled        bool
+	minConcurrency int
+	maxConcurrency int
+	minDelay       time.Duration
+	maxDelay       time.Duration
+}
+
+// NewAutoTune creates an enabled AutoTune with the given bounds.
+func NewAutoTune(minConcurrency, maxConcurrency int, minDelay, maxDelay time.Duration) AutoTune {
+	return AutoTune{
+		enabled:        true,
+		minConcurrency: minConcurrency,
+		maxConcurrency: maxConcurrency,
+		minDelay:       minDelay,
+		maxDelay:       maxDelay,
+	}
+}
+
+// Enabled reports whether the auto-tuner runs.
+func (a AutoTune) Enabled() bool {
+	return a.enabled
+}
+
+// MinConcurrency returns the lowest concurrency the tuner recommends.
+func (a AutoTune) MinConcurrency() int {
+	return a.minConcurrency
+}
+
+// MaxConcurrency returns the highest concurrency the tuner recommends.
+func (a AutoTune) MaxConcurrency() int {
+	return a.maxConcurrency
+}
+
+// MinDelay returns the shortest delay between requests the tuner recovers to.
+func (a AutoTune) MinDelay() time.Duration {
+	return a.minDelay
+}
+
+// MaxDelay returns the longest delay between requests the tuner backs off to.
+func (a AutoTune) MaxDelay() time.Duration {
+	return a.maxDelay
+}
+
+// validate reports bounds the tuner cannot work within.
+func (a AutoTune) validate() error {
+	if a.minConcurrency < 1 {
+		return fmt.Errorf("%w: autoTune.minConcurrency must be at least 1, got %d", ErrInvalidConfig, a.minConcurrency)
+	}
+	if a.maxConcurrency < a.minConcurrency {
+		return fmt.Errorf("%w: autoTune.maxConcurrency (%d) cannot be lower than minConcurrency (%d)",
+			ErrInvalidConfig, a.maxConcurrency, a.minConcurrency)
+	}
+	if a.minDelay < 0 {
+		return fmt.Errorf("%w: autoTune.minDelay cannot be negative, got %s", ErrInvalidConfig, a.minDelay)
+	}
+	if a.maxDelay < a.minDelay {
+		return fmt.Errorf("%w: autoTune.maxDelay (%s) cannot be lower than minDelay (%s)",
+			ErrInvalidConfig, a.maxDelay, a.minDelay)
+	}
+	return nil
+}
+
+// autoTuneDTO is the JSON form of AutoTune. Omitted fields keep their defaults.
+type autoTuneDTO struct {
+	Enabled        *bool   `json:"enabled,omitempty"`
+	MinConcurrency *int    `json:"minConcurrency,omitempty"`
+	MaxConcurrency *int    `json:"maxConcurrency,omitempty"`
+	MinDelay       *string `json:"minDelay,omitempty"`
+	MaxDelay       *string `json:"maxDelay,omitempty"`
+}
+
+// applyAutoTuneDTO overlays the fields present in dto onto base.
+func applyAutoTuneDTO(base AutoTune, dto autoTuneDTO) (AutoTune, error) {
+	if dto.Enabled != nil {
+		base.enabled = *dto.Enabled
+	}
+	if dto.MinConcurrency != nil {
+		base.minConcurrency = *dto.MinConcurrency
+	}
+	if dto.MaxConcurrency != nil {
+		base.maxConcurrency = *dto.MaxConcurrency
+	}
+	if dto.MinDelay != nil {
+		d, err := parseDurationString(*dto.MinDelay, "autoTune.minDelay")
+		if err != nil {
+			return AutoTune{}, err
+		}
+		base.minDelay = d
+	}
+	if dto.MaxDelay != nil {
+		d, err := parseDurationString(*dto.MaxDelay, "autoTune.maxDelay")
+		if err != nil {
+			return AutoTune{}, err
+		}
+		base.maxDelay = d
+	}
+	return base, nil
+}
@@ -1,10 +1,14 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"net/url"
 	"os"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/rohmanhakim/docs-crawler/pkg/hashutil"
@@ -16,18 +20,163 @@ type Config struct {
 	//===============
 	// Initial pages to give to the crawler to begin discovering and traversing other pages.
 	seedURLs []url.URL
-	// Whitelisted hostname. Empty means all hostnames are allowed
+	// Whitelisted hostname. Empty means all hostnames are allowed.
+	// Entries may be glob patterns such as "*.example.com"
 	allowedHosts map[string]struct{}
+	// Whether every subdomain of a seed host, such as docs.example.com for
+	// a seed on example.com, is in scope without a wildcard entry. When
+	// false, seed hosts are matched exactly.
+	// Default: false
+	includeSubdomains bool
+	// Host the whole run is limited to, overriding allowedHosts and
+	// includeSubdomains: seeds on other hosts are dropped and only links to
+	// this host are admitted. Meant for re-crawling one host of a
+	// multi-host config from the command line.
+	// Default: "" (no limit)
+	onlyHost string
+	// What happens to a page whose fetch redirects to a host outside the
+	// allowed hosts: RedirectOutOfScopeSkip records a skip with the final
+	// URL and neither writes the page nor follows its links;
+	// RedirectOutOfScopeWrite processes it under the requested URL.
+	// Default: RedirectOutOfScopeSkip
+	redirectOutOfScope string
+	// allowedHosts compiled for matching, set by Build
+	hostMatcher HostMatcher
 	// Which URL path segments are permitted to be fetched and traversed, even if the links are on the same domain
 	allowedPathPrefix []string
+	// Whether rel="next"/rel="prev" pagination links (Link headers and <head> links)
+	// are followed at the current depth
+	followPagination bool
+	// Whether only the seed URLs are crawled: links found on them, pagination
+	// included, are never discovered or submitted to the frontier.
+	// Default: false
+	singlePage bool
+	// ExcludedExtensions lists file extensions (such as ".zip" or ".tar.gz")
+	// whose URLs are skipped before any fetch. Matched case-insensitively
+	// against the end of the URL path. Seed URLs are never excluded.
+	// Default: common archive, image, media, font and binary extensions
+	excludedExtensions []string
+	// excludedExtensions compiled for matching, set by Build
+	extensionMatcher ExtensionMatcher
+	// File names a server returns for a directory URL (such as "index.html").
+	// The frontier treats "/dir/index.html" and "/dir/" as the same page,
+	// crawling whichever spelling it meets first. Matched case-insensitively.
+	// Default: none
+	defaultDocuments []string
+	// Whether a trailing slash tells URLs apart, applied when URLs are
+	// admitted and so to deduplication and output file names: "strip"
+	// ("/a/" and "/a" are one URL, spelled "/a"), "preserve" ("/a/" and "/a"
+	// are distinct) or "add" ("/a/" and "/a" are one URL, spelled "/a/";
+	// paths ending in a file extension keep their spelling).
+	// Default: "strip"
+	trailingSlashPolicy string
+	// Whether query strings are stripped from URLs before admission and
+	// deduplication, so "?sort=" and "?page=" variants of a page collapse
+	// into one. Parameters in allowedQueryParams are kept regardless. When
+	// false, every parameter is kept.
+	// Default: true
+	dropQueryStrings bool
+	// Query parameters kept on URLs even when dropQueryStrings is set, e.g.
+	// "version". Names are matched exactly. URLs differing only in other
+	// parameters are deduplicated as the same page.
+	// Default: none
+	allowedQueryParams []string
+	// dropQueryStrings and allowedQueryParams compiled for filtering, set by Build
+	queryFilter QueryFilter
+	// Regular expressions matched against the anchor text of discovered
+	// links (see LinkTextMatcher). A matching link is not submitted to the
+	// frontier and is recorded as a skip. Seed URLs are never skipped.
+	// Default: none
+	skipLinkTextPatterns []string
+	// skipLinkTextPatterns compiled for matching, set by Build
+	linkTextMatcher LinkTextMatcher
 
 	//===============
 	// Limits
 	//===============
 	// Maximum number of hyperlink hops from a seed (root) URL
 	maxDepth int
+	// Per-host maxDepth overrides, keyed by host as it appears in the URL
+	// (including any port). 0 means unlimited for that host. Hosts not
+	// listed use maxDepth.
+	// Default: none
+	hostMaxDepth map[string]int
+	// Starting depths of seed URLs, keyed by seed URL as listed in seedURLs.
+	// A seed at depth N is admitted as if it were N hops from the root, so
+	// its links go in at N+1 and maxDepth keeps its meaning when a crawl is
+	// split across workers by depth. Seeds not listed start at depth 0.
+	// Default: none
+	seedDepths map[string]int
+	// How the depth of a URL is counted, for maxDepth, hostMaxDepth and the
+	// frontier's depth levels: DepthModeLinkDistance (hyperlink hops from a
+	// seed) or DepthModePathDepth (number of URL path segments, however the
+	// URL was reached).
+	// Default: DepthModeLinkDistance
+	depthMode string
 	// Maximum number of total documents are allowed to be fetched
 	maxPages int
+	// Maximum number of documents admitted at any single depth level.
+	// 0 means unlimited
+	maxPagesPerDepth int
+	// Number of distinct URLs admitted per URL pattern, where a pattern is
+	// the URL with every numeric path segment and query value replaced by
+	// a placeholder. Beyond it, links such as "?page=N" or "/2020/01/02/"
+	// are treated as a crawler trap and skipped. Seeds are never skipped.
+	// 0 means unlimited.
+	// Default: 0
+	maxPatternRepeats int
+	// Output size budget in bytes: the uncompressed size of the Markdown
+	// pages plus the assets written by the crawl. Manifests, sidecars
+	// and journals are not counted. The crawl stops once the budget is
+	// exceeded, so the last page may overshoot it. 0 means unlimited.
+	// Default: 0
+	maxOutputBytes int64
+	// Wall-clock budget of the crawl loop. The crawl stops before the next
+	// page once it has run this long. 0 means unlimited.
+	// Default: 0
+	maxDuration time.Duration
+	// Page failures after which the crawl stops before the next page. Only
+	// pages that were not written count; non-fatal errors of written pages,
+	// such as a missing asset, do not. 0 means unlimited.
+	// Default: 0
+	maxErrors int
+	// Stop the crawl once the first page matching matchPattern is written,
+	// for crawls searching for one page. Pages written before it are kept.
+	// Default: false
+	stopAfterFirstMatch bool
+	// Regular expression matched, unanchored, against a written page's URL
+	// and normalized Markdown content. Required by stopAfterFirstMatch.
+	// Default: ""
+	matchPattern string
+	// matchPattern compiled for matching, set by Build
+	matchRegexp *regexp.Regexp
+
+	//===============
+	// Repetition
+	//===============
+	// Number of crawl passes in one invocation, for monitoring a site for
+	// changes. Each pass after the first starts repeatInterval after the
+	// previous one ended and rewrites only the pages whose content changed.
+	// 0 repeats until the crawl is cancelled and needs a repeatInterval.
+	// Default: 1
+	maxIterations int
+	// Wait between the end of a crawl pass and the start of the next.
+	// Default: 0
+	repeatInterval time.Duration
+
+	//===============
+	// Ordering
+	//===============
+	// DeterministicOrder dequeues URLs within a depth level in canonical-URL
+	// sort order instead of discovery order, so output is reproducible.
+	// Default: false
+	deterministicOrder bool
+	// FrontierStrategy orders URLs within a depth level:
+	// FrontierStrategyBreadthFirst (priority, then discovery order) or
+	// FrontierStrategyBestFirst (priority, then the scheduler's URL scorer,
+	// highest first). Equal scores keep the breadth-first tie-breaking.
+	// Default: FrontierStrategyBreadthFirst
+	frontierStrategy string
 
 	//===============
 	// Politeness
@@ -37,9 +186,28 @@ type Config struct {
 	concurrency int
 	// Minimum, fixed waiting time you enforce between two HTTP requests to the same host.
 	baseDelay time.Duration
+	// Crawl delay applied to a host whose robots.txt sets none, and floor of
+	// the one it sets: a larger robots.txt Crawl-delay still wins. 0 leaves
+	// hosts without a Crawl-delay to the base delay.
+	// Default: 0
+	defaultCrawlDelay time.Duration
+	// Slows the crawl down as it goes deeper: the delay before fetching a
+	// page at depth d is baseDelay * (1 + depthDelayFactor*d), capped at
+	// maxDepthDelay. 0 keeps the base delay at every depth.
+	// Default: 0
+	depthDelayFactor float64
+	// Cap of the depth-scaled delay; it never lowers the base delay itself.
+	// 0 leaves the depth-scaled delay uncapped.
+	// Default: 30s
+	maxDepthDelay time.Duration
 	// Randomized variation added on top of the base delay.
 	// Intentional randomness applied to timing.
 	jitter time.Duration
+	// Shape of the jitter draws within [0, jitter): JitterUniform spreads
+	// them evenly, JitterExponential makes short waits common and long ones
+	// rare, JitterNormal clusters them around half the jitter.
+	// Default: JitterUniform
+	jitterDistribution string
 	// Controls the random number generator
 	randomSeed int64
 	// maximum attempt during retry
@@ -50,6 +218,25 @@ type Config struct {
 	backoffMultiplier float64
 	// capped maximum delay for backoff to stop exponential multiplication
 	backoffMaxDuration time.Duration
+	// Daily time windows during which crawling is allowed.
+	// Empty means crawling is always allowed
+	crawlWindows []TimeWindow
+	// Whole-page retries on top of per-stage retries.
+	// Default: 1 attempt (disabled), 1s base backoff
+	pageRetry PageRetry
+	// Bounds of the auto-tuner that adjusts the delay from observed
+	// throttling. Its concurrency is an advisory recommendation only.
+	// Default: disabled, concurrency 1-4, delay 0-2s
+	autoTune AutoTune
+	// Number of consecutive pages failing in the same stage (fetch, storage)
+	// after which the crawl is aborted. 0 disables the circuit breaker.
+	// Default: 0
+	circuitBreakerThreshold int
+	// Abort the crawl on the first page failing in extraction, sanitization,
+	// conversion, asset resolution, normalization or writing, instead of
+	// skipping the page. Fetch failures are unaffected.
+	// Default: false
+	strictMode bool
 
 	// ===============
 	// Fetch
@@ -62,10 +249,94 @@ type Config struct {
 	maxIdleConnsPerHost int
 	// Maximum time to keep idle connections alive
 	idleConnTimeout time.Duration
+	// Negotiate HTTP/2 with hosts supporting it; HTTP/1.1 only when false.
+	// Default: true
+	http2 bool
 	// User agent that will be used in the request header. In raw string
 	userAgent string
+	// Per-host user agent overrides for robots.txt and page fetches, keyed by
+	// host as it appears in the URL (including any port). Hosts not listed
+	// use userAgent.
+	hostUserAgents map[string]string
+	// Inline robots.txt contents keyed by host (including any port), used
+	// instead of fetching robots.txt from the listed hosts, e.g. for staging
+	// sites whose robots.txt is missing or wrong.
+	// Default: none
+	robotsOverride map[string]string
+	// Extra request headers sent with every page fetch, over the built-in
+	// browser-like headers.
+	// Default: none
+	defaultHeaders map[string]string
+	// Headers sent with the page fetches whose URL matches a rule's regular
+	// expression, over defaultHeaders. Later rules override earlier ones on
+	// the same header.
+	// Default: none
+	headerRules []HeaderRule
+	// Per-host TLS settings, keyed by host name without port, for internal
+	// hosts with a private CA or a self-signed certificate. Hosts not
+	// listed, public sites included, keep the default verification.
+	// Default: none
+	hostTLS map[string]HostTLS
 	// Maximum size of assets to download in bytes. 0 means unlimited.
 	maxAssetSize int64
+	// How written assets are named: "nameHash" (original name and short
+	// content hash), "contentHash" (content hash only) or "mirror" (the
+	// asset URL's host and path under assets/)
+	// Default: "nameHash"
+	assetNaming string
+	// Width raster image assets are downscaled to before they are written,
+	// keeping the aspect ratio. 0 keeps their size.
+	// Default: 0
+	imageMaxWidth int
+	// Format raster image assets are re-encoded in before they are written:
+	// "png" or "jpeg". Empty keeps their format. SVGs are never converted.
+	// WebP output is not supported, as there is no WebP encoder to use.
+	// Default: ""
+	imageFormat string
+	// Whether to issue a HEAD request before GET to skip resources
+	// with a disallowed content type or an oversized body
+	preflightHead bool
+	// Media types a page response may have to be processed
+	allowedContentTypes []string
+	// Maximum size of a page response body in bytes. 0 means unlimited.
+	maxResponseBytes int64
+	// Maximum size in bytes of an HTML body handed to the extractor.
+	// Larger pages are fetched but skipped as too large to parse, since
+	// parsing and the extraction heuristics slow down badly on huge
+	// documents. Unlike maxResponseBytes this does not limit the download.
+	// 0 means unlimited.
+	// Default: 0
+	maxHTMLParseBytes int64
+	// Whether to capture DNS, connect, TLS handshake and time-to-first-byte
+	// timings and the negotiated TLS version and cipher of page fetches.
+	// Default: false
+	traceRequests bool
+	// Directory of a replay archive (see fetcher.ArchiveFetcher). When set,
+	// pages, robots.txt and assets are served from the archive instead of
+	// the network. Empty means a live crawl.
+	// Default: ""
+	replayArchive string
+	// Whether robots.txt of the seed and allowed hosts is fetched
+	// concurrently before the crawl starts, instead of serially as each
+	// host is first met. Wildcard allowed-host patterns are skipped.
+	// Default: false
+	robotsWarmup bool
+	// Directory of the fetch cache (see fetcher.CachingFetcher). When set,
+	// page responses are stored there keyed by canonical URL and served from
+	// it on later runs until fetchCacheTTL has passed. Empty disables the
+	// cache.
+	// Default: ""
+	fetchCacheDir string
+	// How long a cached response is served before the page is fetched
+	// again. 0 means cached responses never expire.
+	// Default: 24h
+	fetchCacheTTL time.Duration
+	// Whether pages are served from the fetch cache only, whatever their
+	// age, with no network access: a page missing from the cache fails with
+	// fetcher.ErrCacheMiss, and robots.txt and assets, which the cache does
+	// not hold, are treated as missing. Needs fetchCacheDir.
+	// Default: false
+	offlineOnly bool
 
 	//===============
 	// Output
@@ -75,9 +346,52 @@ type Config struct {
 	// Whether the program will simulates what it would do without
 	// actually performing any irreversible or side-effecting actions
 	dryRun bool
+	// Whether the crawl only checks links: pages are fetched for link
+	// discovery but not extracted or written, and the status of every
+	// discovered link is reported in a broken-links report instead.
+	// Default: false
+	linkCheckOnly bool
 	// Directory to dump intermediate stage outputs for debugging.
 	// Empty means stage dumping is disabled.
 	dumpStageOutput string
+	// Path of a combined Markdown file that every written page is appended
+	// to, in write order (see storage.SingleFileWriter). A relative path is
+	// resolved against outputDir. Empty means no combined file.
+	// Default: ""
+	singleFileOutput string
+	// Whether the combined file replaces the per-page files instead of
+	// being written in addition to them. Requires singleFileOutput.
+	// Default: false
+	singleFileOnly bool
+	// Whether pages (and their assets) are written under a subdirectory of
+	// outputDir named after the page's declared language, e.g. output/fr/.
+	// Pages without a declared language go to output/und/.
+	// Default: false
+	partitionByLanguage bool
+	// Whether links to crawled pages are rewritten to the relative path of
+	// their output file, so the output can be browsed offline. Links to
+	// pages outside the crawl are made absolute. Needs per-page files, so
+	// it cannot be combined with singleFileOnly.
+	// Default: false
+	relativeLinks bool
+	// Whether the fetched body of every page is also saved unchanged to
+	// outputDir/_raw/<url_hash>.html, for debugging extraction. Dry runs
+	// save nothing.
+	// Default: false
+	saveRawHTML bool
+	// Format of the crawl graph written to outputDir at the end of the run:
+	// GraphFormatJSON (graph.json) or GraphFormatDOT (graph.dot). Empty
+	// writes no graph; the edges are still available on the execution.
+	// Default: ""
+	graphFormat string
+	// Formats every page is written in, next to each other with the same
+	// base name: OutputFormatMarkdown (<url_hash>.md), OutputFormatHTML
+	// (the sanitized HTML, <url_hash>.html) and OutputFormatText (plain
+	// text, <url_hash>.txt). Markdown is always required; the manifest and
+	// incremental crawls are keyed on the .md file. Needs per-page files,
+	// so formats besides Markdown cannot be combined with singleFileOnly.
+	// Default: ["md"]
+	outputFormats []string
 
 	//===============
 	// Extraction
@@ -122,12 +436,130 @@ type Config struct {
 	// content is considered navigation-only and rejected.
 	// Default: 0.8 (80%)
 	thresholdMaxLinkDensity float64
+	// MinTextRatio is the minimum share of the body's text the selected
+	// content must hold. Pages below it, e.g. landing pages where only a
+	// short blurb sits among link lists, are reported as having no
+	// substantial content and are not written. 0 disables the check.
+	// Default: 0
+	minTextRatio float64
+	// NoscriptMode controls how <noscript> content is treated during extraction:
+	// "drop", "inline", or "preferWhenEmpty".
+	// Default: "drop"
+	noscriptMode string
+	// StructuredExtraction detects repeated API reference blocks (signature,
+	// parameters, returns) and renders them in a consistent Markdown layout.
+	// Default: false
+	structuredExtraction bool
+	// StripByRole removes navigation, banner and footer landmarks and
+	// aria-hidden elements before the main content is selected.
+	// Default: true
+	stripByRole bool
+	// MultiArticleMode controls how a page with several <article> elements
+	// is extracted: "largest" keeps the one with the most text,
+	// "concatenate" merges them into one page and "separateOutputs" writes
+	// each as a page of its own, identified by a URL fragment.
+	// Default: "largest"
+	multiArticleMode string
 
 	//===============
 	// Hash Algorithm
 	//===============
 	hashAlgo string
 
+	//===============
+	// Markdown Flavor
+	//===============
+	// MarkdownFlavor selects the output dialect: "gfm" or "commonmark".
+	// CommonMark output downgrades GFM-only constructs such as tables.
+	// Default: "gfm"
+	markdownFlavor string
+
+	//===============
+	// Table of Contents
+	//===============
+	// GenerateToC inserts a table of contents below each page's H1 title.
+	// Default: false
+	generateToC bool
+
+	//===============
+	// Heading Deduplication
+	//===============
+	// DedupeHeadings removes a repeat of the H1 title and collapses
+	// immediately adjacent identical headings left by template quirks.
+	// Default: false
+	dedupeHeadings bool
+
+	//===============
+	// Invisible Characters
+	//===============
+	// StripInvisibleInCode also removes zero-width and control characters
+	// from fenced code blocks; they are always removed from the rest.
+	// Default: false
+	stripInvisibleInCode bool
+
+	//===============
+	// Chunking
+	//===============
+	// ChunkSize splits each page's Markdown into chunks of about this many
+	// bytes for RAG ingestion, written as a sidecar next to the page. Chunks
+	// only break between blocks. 0 disables chunking.
+	// Default: 0
+	chunkSize int
+	// ChunkOverlap is the number of bytes of trailing blocks each chunk
+	// repeats from the previous one. Must be lower than ChunkSize.
+	// Default: 0
+	chunkOverlap int
+
+	//===============
+	// Provenance
+	//===============
+	// WriteProvenance records how each page was reached, its referrer and
+	// the redirect chain of its fetch, in the frontmatter and in a sidecar
+	// next to the page.
+	// Default: false
+	writeProvenance bool
+
+	//===============
+	// Duplicate Content
+	//===============
+	// DuplicateContent controls pages whose content hash matches an already
+	// written page: "write" writes every page, "alias" writes the first one
+	// and records the others as aliases of it.
+	// Default: "write"
+	duplicateContent string
+
+	//===============
+	// Responsive Images
+	//===============
+	// ImageDensity is the pixel density used to choose among srcset and
+	// <picture> candidates. 0 picks the highest resolution.
+	// Default: 0
+	imageDensity float64
+
+	//===============
+	// Unknown HTML
+	//===============
+	// PreserveUnknownHTML keeps elements the converter has no rule for
+	// (e.g. <details>) as raw HTML blocks instead of unwrapping them.
+	// Default: false
+	preserveUnknownHTML bool
+
+	//===============
+	// Admonitions
+	//===============
+	// AdmonitionTypes maps admonition classes (e.g. "note", "danger") to
+	// GFM alert types (NOTE, TIP, IMPORTANT, WARNING, CAUTION), on top of
+	// the built-in mapping. An empty type drops a built-in class, so its
+	// admonitions become plain blockquotes.
+	// Default: none (built-in mapping only)
+	admonitionTypes map[string]string
+	// SpanStyles maps span classes (e.g. "kbd", "hljs-keyword") to the
+	// inline style their spans keep: "code", "strong", "em" or an inline
+	// HTML tag such as "<kbd>", on top of the built-in mapping. An empty
+	// style drops a built-in class, so its spans flatten to text.
+	// Default: none (built-in mapping only)
+	spanStyles map[string]string
+
 	//===============
 	// Selector Blacklist
 	//===============
@@ -145,44 +577,128 @@ type Config struct {
 	debugFile string
 	// Debug output format: "json" or "text"
 	debugFormat string
+
+	//===============
+	// Load Warnings
+	//===============
+	// Deprecation warnings collected while migrating an older config file
+	warnings []string
 }
 
 type configDTO struct {
-	SeedURLs               []string            `json:"seedUrls"`
-	AllowedHosts           map[string]struct{} `json:"allowedHosts,omitempty"`
-	AllowedPathPrefix      []string            `json:"allowedPathPrefix,omitempty"`
-	MaxDepth               *int                `json:"maxDepth,omitempty"`
-	MaxPages               *int                `json:"maxPages,omitempty"`
-	Concurrency            *int                `json:"concurrency,omitempty"`
-	BaseDelay              *string             `json:"baseDelay,omitempty"`
-	Jitter                 *string             `json:"jitter,omitempty"`
-	RandomSeed             *int64              `json:"randomSeed,omitempty"`
-	MaxAttempt             *int                `json:"maxAttempt,omitempty"`
-	BackoffInitialDuration *string             `json:"backoffInitialDuration,omitempty"`
-	BackoffMultiplier      *float64            `json:"backoffMultiplier,omitempty"`
-	BackoffMaxDuration     *string             `json:"backoffMaxDuration,omitempty"`
-	Timeout                *string             `json:"timeout,omitempty"`
-	MaxIdleConns           *int                `json:"maxIdleConns,omitempty"`
-	MaxIdleConnsPerHost    *int                `json:"maxIdleConnsPerHost,omitempty"`
-	IdleConnTimeout        *string             `json:"idleConnTimeout,omitempty"`
-	UserAgent              *string             `json:"userAgent,omitempty"`
-	MaxAssetSize           *int64              `json:"maxAssetSize,omitempty"`
-	OutputDir              *string             `json:"outputDir,omitempty"`
-	DryRun                 *bool               `json:"dryRun,omitempty"`
-	DumpStageOutput        *string             `json:"dumpStageOutput,omitempty"`
+	SeedURLs                []string            `json:"seedUrls"`
+	AllowedHosts            map[string]struct{} `json:"allowedHosts,omitempty"`
+	IncludeSubdomains       *bool               `json:"includeSubdomains,omitempty"`
+	OnlyHost                *string             `json:"onlyHost,omitempty"`
+	RedirectOutOfScope      *string             `json:"redirectOutOfScope,omitempty"`
+	AllowedPathPrefix       []string            `json:"allowedPathPrefix,omitempty"`
+	FollowPagination        *bool               `json:"followPagination,omitempty"`
+	SinglePage              *bool               `json:"singlePage,omitempty"`
+	ExcludedExtensions      *[]string           `json:"excludedExtensions,omitempty"`
+	DefaultDocuments        *[]string           `json:"defaultDocuments,omitempty"`
+	TrailingSlashPolicy     *string             `json:"trailingSlashPolicy,omitempty"`
+	DropQueryStrings        *bool               `json:"dropQueryStrings,omitempty"`
+	AllowedQueryParams      *[]string           `json:"allowedQueryParams,omitempty"`
+	SkipLinkTextPatterns    *[]string           `json:"skipLinkTextPatterns,omitempty"`
+	MaxDepth                *int                `json:"maxDepth,omitempty"`
+	HostMaxDepth            *map[string]int     `json:"hostMaxDepth,omitempty"`
+	SeedDepths              *map[string]int     `json:"seedDepths,omitempty"`
+	DepthMode               *string             `json:"depthMode,omitempty"`
+	MaxPages                *int                `json:"maxPages,omitempty"`
+	MaxPagesPerDepth        *int                `json:"maxPagesPerDepth,omitempty"`
+	MaxPatternRepeats       *int                `json:"maxPatternRepeats,omitempty"`
+	MaxOutputBytes          *int64              `json:"maxOutputBytes,omitempty"`
+	MaxDuration             *string             `json:"maxDuration,omitempty"`
+	MaxErrors               *int                `json:"maxErrors,omitempty"`
+	StopAfterFirstMatch     *bool               `json:"stopAfterFirstMatch,omitempty"`
+	MatchPattern            *string             `json:"matchPattern,omitempty"`
+	MaxIterations           *int                `json:"maxIterations,omitempty"`
+	RepeatInterval          *string             `json:"repeatInterval,omitempty"`
+	DeterministicOrder      *bool               `json:"deterministicOrder,omitempty"`
+	FrontierStrategy        *string             `json:"frontierStrategy,omitempty"`
+	Concurrency             *int                `json:"concurrency,omitempty"`
+	BaseDelay               *string             `json:"baseDelay,omitempty"`
+	DefaultCrawlDelay       *string             `json:"defaultCrawlDelay,omitempty"`
+	DepthDelayFactor        *float64            `json:"depthDelayFactor,omitempty"`
+	MaxDepthDelay           *string             `json:"maxDepthDelay,omitempty"`
+	Jitter                  *string             `json:"jitter,omitempty"`
+	JitterDistribution      *string             `json:"jitterDistribution,omitempty"`
+	RandomSeed              *int64              `json:"randomSeed,omitempty"`
+	MaxAttempts             *int                `json:"maxAttempts,omitempty"`
+	BackoffInitialDuration  *string             `json:"backoffInitialDuration,omitempty"`
+	BackoffMultiplier       *float64            `json:"backoffMultiplier,omitempty"`
+	BackoffMaxDuration      *string             `json:"backoffMaxDuration,omitempty"`
+	CrawlWindows            *[]timeWindowDTO    `json:"crawlWindows,omitempty"`
+	PageRetry               *pageRetryDTO       `json:"pageRetry,omitempty"`
+	AutoTune                *autoTuneDTO        `json:"autoTune,omitempty"`
+	CircuitBreakerThreshold *int                `json:"circuitBreakerThreshold,omitempty"`
+	StrictMode              *bool               `json:"strictMode,omitempty"`
+	Timeout                 *string             `json:"timeout,omitempty"`
+	MaxIdleConns            *int                `json:"maxIdleConns,omitempty"`
+	MaxIdleConnsPerHost     *int                `json:"maxIdleConnsPerHost,omitempty"`
+	IdleConnTimeout         *string             `json:"idleConnTimeout,omitempty"`
+	HTTP2                   *bool               `json:"http2,omitempty"`
+	UserAgent               *string             `json:"userAgent,omitempty"`
+	HostUserAgents          *map[string]string  `json:"hostUserAgents,omitempty"`
+	RobotsOverride          *map[string]string  `json:"robotsOverride,omitempty"`
+	DefaultHeaders          *map[string]string  `json:"defaultHeaders,omitempty"`
+	HeaderRules             *[]headerRuleDTO    `json:"headerRules,omitempty"`
+	TLS                     *map[string]tlsDTO  `json:"tls,omitempty"`
+	MaxAssetSize            *int64              `json:"maxAssetSize,omitempty"`
+	AssetNaming             *string             `json:"assetNaming,omitempty"`
+	ImageMaxWidth           *int                `json:"imageMaxWidth,omitempty"`
+	ImageFormat             *string             `json:"imageFormat,omitempty"`
+	PreflightHead           *bool               `json:"preflightHead,omitempty"`
+	AllowedContentTypes     *[]string           `json:"allowedContentTypes,omitempty"`
+	MaxResponseBytes        *int64              `json:"maxResponseBytes,omitempty"`
+	MaxHTMLParseBytes       *int64              `json:"maxHTMLParseBytes,omitempty"`
+	TraceRequests           *bool               `json:"traceRequests,omitempty"`
+	ReplayArchive           *string             `json:"replayArchive,omitempty"`
+	RobotsWarmup            *bool               `json:"robotsWarmup,omitempty"`
+	FetchCacheDir           *string             `json:"fetchCacheDir,omitempty"`
+	FetchCacheTTL           *string             `json:"fetchCacheTTL,omitempty"`
+	OfflineOnly             *bool               `json:"offlineOnly,omitempty"`
+	OutputDir               *string             `json:"outputDir,omitempty"`
+	DryRun                  *bool               `json:"dryRun,omitempty"`
+	LinkCheckOnly           *bool               `json:"linkCheckOnly,omitempty"`
+	DumpStageOutput         *string             `json:"dumpStageOutput,omitempty"`
+	SingleFileOutput        *string             `json:"singleFileOutput,omitempty"`
+	SingleFileOnly          *bool               `json:"singleFileOnly,omitempty"`
+	PartitionByLanguage     *bool               `json:"partitionByLanguage,omitempty"`
+	RelativeLinks           *bool               `json:"relativeLinks,omitempty"`
+	SaveRawHTML             *bool               `json:"saveRawHtml,omitempty"`
+	GraphFormat             *string             `json:"graphFormat,omitempty"`
+	OutputFormats           *[]string           `json:"outputFormats,omitempty"`
 	// Extraction parameters
-	BodySpecificityBias                 *float64 `json:"bodySpecificityBias,omitempty"`
-	LinkDensityThreshold                *float64 `json:"linkDensityThreshold,omitempty"`
-	ScoreMultiplierNonWhitespaceDivisor *float64 `json:"scoreMultiplierNonWhitespaceDivisor,omitempty"`
-	ScoreMultiplierParagraphs           *float64 `json:"scoreMultiplierParagraphs,omitempty"`
-	ScoreMultiplierHeadings             *float64 `json:"scoreMultiplierHeadings,omitempty"`
-	ScoreMultiplierCodeBlocks           *float64 `json:"scoreMultiplierCodeBlocks,omitempty"`
-	ScoreMultiplierListItems            *float64 `json:"scoreMultiplierListItems,omitempty"`
-	ThresholdMinNonWhitespace           *int     `json:"thresholdMinNonWhitespace,omitempty"`
-	ThresholdMinHeadings                *int     `json:"thresholdMinHeadings,omitempty"`
-	ThresholdMinParagraphsOrCode        *int     `json:"thresholdMinParagraphsOrCode,omitempty"`
-	ThresholdMaxLinkDensity             *float64 `json:"thresholdMaxLinkDensity,omitempty"`
-	HashAlgo                            *string  `json:"hashAlgo,omitempty"`
+	BodySpecificityBias                 *float64           `json:"bodySpecificityBias,omitempty"`
+	LinkDensityThreshold                *float64           `json:"linkDensityThreshold,omitempty"`
+	ScoreMultiplierNonWhitespaceDivisor *float64           `json:"scoreMultiplierNonWhitespaceDivisor,omitempty"`
+	ScoreMultiplierParagraphs           *float64           `json:"scoreMultiplierParagraphs,omitempty"`
+	ScoreMultiplierHeadings             *float64           `json:"scoreMultiplierHeadings,omitempty"`
+	ScoreMultiplierCodeBlocks           *float64           `json:"scoreMultiplierCodeBlocks,omitempty"`
+	ScoreMultiplierListItems            *float64           `json:"scoreMultiplierListItems,omitempty"`
+	ThresholdMinNonWhitespace           *int               `json:"thresholdMinNonWhitespace,omitempty"`
+	ThresholdMinHeadings                *int               `json:"thresholdMinHeadings,omitempty"`
+	ThresholdMinParagraphsOrCode        *int               `json:"thresholdMinParagraphsOrCode,omitempty"`
+	ThresholdMaxLinkDensity             *float64           `json:"thresholdMaxLinkDensity,omitempty"`
+	MinTextRatio                        *float64           `json:"minTextRatio,omitempty"`
+	NoscriptMode                        *string            `json:"noscriptMode,omitempty"`
+	StructuredExtraction                *bool              `json:"structuredExtraction,omitempty"`
+	StripByRole                         *bool              `json:"stripByRole,omitempty"`
+	MultiArticleMode                    *string            `json:"multiArticleMode,omitempty"`
+	HashAlgo                            *string            `json:"hashAlgo,omitempty"`
+	MarkdownFlavor                      *string            `json:"markdownFlavor,omitempty"`
+	GenerateToC                         *bool              `json:"generateToC,omitempty"`
+	DedupeHeadings                      *bool              `json:"dedupeHeadings,omitempty"`
+	StripInvisibleInCode                *bool              `json:"stripInvisibleInCode,omitempty"`
+	ChunkSize                           *int               `json:"chunkSize,omitempty"`
+	ChunkOverlap                        *int               `json:"chunkOverlap,omitempty"`
+	WriteProvenance                     *bool              `json:"writeProvenance,omitempty"`
+	DuplicateContent                    *string            `json:"duplicateContent,omitempty"`
+	ImageDensity                        *float64           `json:"imageDensity,omitempty"`
+	PreserveUnknownHTML                 *bool              `json:"preserveUnknownHTML,omitempty"`
+	AdmonitionTypes                     *map[string]string `json:"admonitionTypes,omitempty"`
+	SpanStyles                          *map[string]string `json:"spanStyles,omitempty"`
 	// Selector blacklist for noise suppression
 	SelectorBlacklist *[]string `json:"selectorBlacklist,omitempty"`
 	// Debug logging configuration
@@ -240,6 +756,10 @@ func WithConfigFileBuilder(path string) (*Config, error) {
 	if err != nil {
 		return nil, fmt.Errorf("%w: %s", ErrReadConfigFail, err.Error())
 	}
+	configContent, warnings, err := migrateConfig(configContent)
+	if err != nil {
+		return nil, err
+	}
 	cfgDTO := configDTO{}
 
 	err = json.Unmarshal(configContent, &cfgDTO)
@@ -251,6 +771,7 @@ func WithConfigFileBuilder(path string) (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
+	cfg.warnings = warnings
 	return cfg, nil
 }
 
@@ -270,16 +791,100 @@ func newConfigFromDTOBuilder(dto configDTO) (*Config, error) {
 		cfg.allowedHosts = dto.AllowedHosts
 	}
 
+	if dto.IncludeSubdomains != nil {
+		cfg.includeSubdomains = *dto.IncludeSubdomains
+	}
+	if dto.OnlyHost != nil {
+		cfg.onlyHost = *dto.OnlyHost
+	}
+	if dto.RedirectOutOfScope != nil {
+		cfg.redirectOutOfScope = *dto.RedirectOutOfScope
+	}
+
 	// AllowedPathPrefix can be empty - always use DTO values
 	cfg.allowedPathPrefix = dto.AllowedPathPrefix
 
 	// For pointer fields, check if nil (not provided) before overriding defaults
+	if dto.FollowPagination != nil {
+		cfg.followPagination = *dto.FollowPagination
+	}
+	if dto.SinglePage != nil {
+		cfg.singlePage = *dto.SinglePage
+	}
+	if dto.ExcludedExtensions != nil {
+		cfg.excludedExtensions = *dto.ExcludedExtensions
+	}
+	if dto.DefaultDocuments != nil {
+		cfg.defaultDocuments = *dto.DefaultDocuments
+	}
+	if dto.TrailingSlashPolicy != nil {
+		cfg.trailingSlashPolicy = *dto.TrailingSlashPolicy
+	}
+	if dto.DropQueryStrings != nil {
+		cfg.dropQueryStrings = *dto.DropQueryStrings
+	}
+	if dto.AllowedQueryParams != nil {
+		cfg.allowedQueryParams = *dto.AllowedQueryParams
+	}
+	if dto.SkipLinkTextPatterns != nil {
+		cfg.skipLinkTextPatterns = *dto.SkipLinkTextPatterns
+	}
 	if dto.MaxDepth != nil {
 		cfg.maxDepth = *dto.MaxDepth
 	}
+	if dto.HostMaxDepth != nil {
+		cfg.hostMaxDepth = *dto.HostMaxDepth
+	}
+	if dto.SeedDepths != nil {
+		cfg.seedDepths = *dto.SeedDepths
+	}
+	if dto.DepthMode != nil {
+		cfg.depthMode = *dto.DepthMode
+	}
 	if dto.MaxPages != nil {
 		cfg.maxPages = *dto.MaxPages
 	}
+	if dto.MaxPagesPerDepth != nil {
+		cfg.maxPagesPerDepth = *dto.MaxPagesPerDepth
+	}
+	if dto.MaxPatternRepeats != nil {
+		cfg.maxPatternRepeats = *dto.MaxPatternRepeats
+	}
+	if dto.MaxOutputBytes != nil {
+		cfg.maxOutputBytes = *dto.MaxOutputBytes
+	}
+	if dto.MaxDuration != nil {
+		d, err := parseDurationString(*dto.MaxDuration, "maxDuration")
+		if err != nil {
+			return nil, err
+		}
+		cfg.maxDuration = d
+	}
+	if dto.MaxErrors != nil {
+		cfg.maxErrors = *dto.MaxErrors
+	}
+	if dto.StopAfterFirstMatch != nil {
+		cfg.stopAfterFirstMatch = *dto.StopAfterFirstMatch
+	}
+	if dto.MatchPattern != nil {
+		cfg.matchPattern = *dto.MatchPattern
+	}
+	if dto.MaxIterations != nil {
+		cfg.maxIterations = *dto.MaxIterations
+	}
+	if dto.RepeatInterval != nil {
+		d, err := parseDurationString(*dto.RepeatInterval, "repeatInterval")
+		if err != nil {
+			return nil, err
+		}
+		cfg.repeatInterval = d
+	}
+	if dto.DeterministicOrder != nil {
+		cfg.deterministicOrder = *dto.DeterministicOrder
+	}
+	if dto.FrontierStrategy != nil {
+		cfg.frontierStrategy = *dto.FrontierStrategy
+	}
 	if dto.Concurrency != nil {
 		cfg.concurrency = *dto.Concurrency
 	}
@@ -290,6 +895,23 @@ func newConfigFromDTOBuilder(dto configDTO) (*Config, error) {
 		}
 		cfg.baseDelay = d
 	}
+	if dto.DefaultCrawlDelay != nil {
+		d, err := parseDurationString(*dto.DefaultCrawlDelay, "defaultCrawlDelay")
+		if err != nil {
+			return nil, err
+		}
+		cfg.defaultCrawlDelay = d
+	}
+	if dto.DepthDelayFactor != nil {
+		cfg.depthDelayFactor = *dto.DepthDelayFactor
+	}
+	if dto.MaxDepthDelay != nil {
+		d, err := parseDurationString(*dto.MaxDepthDelay, "maxDepthDelay")
+		if err != nil {
+			return nil, err
+		}
+		cfg.maxDepthDelay = d
+	}
 	if dto.Jitter != nil {
 		d, err := parseDurationString(*dto.Jitter, "jitter")
 		if err != nil {
@@ -297,11 +919,14 @@ func newConfigFromDTOBuilder(dto configDTO) (*Config, error) {
 		}
 		cfg.jitter = d
 	}
+	if dto.JitterDistribution != nil {
+		cfg.jitterDistribution = *dto.JitterDistribution
+	}
 	if dto.RandomSeed != nil {
 		cfg.randomSeed = *dto.RandomSeed
 	}
-	if dto.MaxAttempt != nil {
-		cfg.maxAttempt = *dto.MaxAttempt
+	if dto.MaxAttempts != nil {
+		cfg.maxAttempt = *dto.MaxAttempts
 	}
 	if dto.BackoffInitialDuration != nil {
 		d, err := parseDurationString(*dto.BackoffInitialDuration, "backoffInitialDuration")
@@ -321,6 +946,34 @@ func newConfigFromDTOBuilder(dto configDTO) (*Config, error) {
 		cfg.backoffMaxDuration = d
 	}
 
+	if dto.CrawlWindows != nil {
+		windows, err := parseTimeWindows(*dto.CrawlWindows)
+		if err != nil {
+			return nil, err
+		}
+		cfg.crawlWindows = windows
+	}
+	if dto.PageRetry != nil {
+		pageRetry, err := applyPageRetryDTO(cfg.pageRetry, *dto.PageRetry)
+		if err != nil {
+			return nil, err
+		}
+		cfg.pageRetry = pageRetry
+	}
+	if dto.AutoTune != nil {
+		autoTune, err := applyAutoTuneDTO(cfg.autoTune, *dto.AutoTune)
+		if err != nil {
+			return nil, err
+		}
+		cfg.autoTune = autoTune
+	}
+	if dto.CircuitBreakerThreshold != nil {
+		cfg.circuitBreakerThreshold = *dto.CircuitBreakerThreshold
+	}
+	if dto.StrictMode != nil {
+		cfg.strictMode = *dto.StrictMode
+	}
+
 	if dto.Timeout != nil {
 		d, err := parseDurationString(*dto.Timeout, "timeout")
 		if err != nil {
@@ -331,9 +984,67 @@ func newConfigFromDTOBuilder(dto configDTO) (*Config, error) {
 	if dto.UserAgent != nil {
 		cfg.userAgent = *dto.UserAgent
 	}
+	if dto.HostUserAgents != nil {
+		cfg.hostUserAgents = *dto.HostUserAgents
+	}
+	if dto.RobotsOverride != nil {
+		cfg.robotsOverride = *dto.RobotsOverride
+	}
+	if dto.DefaultHeaders != nil {
+		cfg.defaultHeaders = *dto.DefaultHeaders
+	}
+	if dto.HeaderRules != nil {
+		cfg.headerRules = parseHeaderRules(*dto.HeaderRules)
+	}
+	if dto.TLS != nil {
+		cfg.hostTLS = parseHostTLS(*dto.TLS)
+	}
 	if dto.MaxAssetSize != nil {
 		cfg.maxAssetSize = *dto.MaxAssetSize
 	}
+	if dto.AssetNaming != nil {
+		cfg.assetNaming = *dto.AssetNaming
+	}
+	if dto.ImageMaxWidth != nil {
+		cfg.imageMaxWidth = *dto.ImageMaxWidth
+	}
+	if dto.ImageFormat != nil {
+		cfg.imageFormat = *dto.ImageFormat
+	}
+	if dto.PreflightHead != nil {
+		cfg.preflightHead = *dto.PreflightHead
+	}
+	if dto.AllowedContentTypes != nil {
+		cfg.allowedContentTypes = *dto.AllowedContentTypes
+	}
+	if dto.MaxResponseBytes != nil {
+		cfg.maxResponseBytes = *dto.MaxResponseBytes
+	}
+	if dto.MaxHTMLParseBytes != nil {
+		cfg.maxHTMLParseBytes = *dto.MaxHTMLParseBytes
+	}
+	if dto.TraceRequests != nil {
+		cfg.traceRequests = *dto.TraceRequests
+	}
+	if dto.ReplayArchive != nil {
+		cfg.replayArchive = *dto.ReplayArchive
+	}
+	if dto.RobotsWarmup != nil {
+		cfg.robotsWarmup = *dto.RobotsWarmup
+	}
+	if dto.FetchCacheDir != nil {
+		cfg.fetchCacheDir = *dto.FetchCacheDir
+	}
+	if dto.FetchCacheTTL != nil {
+		d, err := parseDurationString(*dto.FetchCacheTTL, "fetchCacheTTL")
+		if err != nil {
+			return nil, err
+		}
+		cfg.fetchCacheTTL = d
+	}
+	if dto.OfflineOnly != nil {
+		cfg.offlineOnly = *dto.OfflineOnly
+	}
 	if dto.OutputDir != nil {
 		cfg.outputDir = *dto.OutputDir
 	}
@@ -341,10 +1052,34 @@ func newConfigFromDTOBuilder(dto configDTO) (*Config, error) {
 	if dto.DryRun != nil {
 		cfg.dryRun = *dto.DryRun
 	}
+	if dto.LinkCheckOnly != nil {
+		cfg.linkCheckOnly = *dto.LinkCheckOnly
+	}
 	// DumpStageOutput - directory for stage dumps
 	if dto.DumpStageOutput != nil {
 		cfg.dumpStageOutput = *dto.DumpStageOutput
 	}
+	if dto.SingleFileOutput != nil {
+		cfg.singleFileOutput = *dto.SingleFileOutput
+	}
+	if dto.SingleFileOnly != nil {
+		cfg.singleFileOnly = *dto.SingleFileOnly
+	}
+	if dto.PartitionByLanguage != nil {
+		cfg.partitionByLanguage = *dto.PartitionByLanguage
+	}
+	if dto.RelativeLinks != nil {
+		cfg.relativeLinks = *dto.RelativeLinks
+	}
+	if dto.SaveRawHTML != nil {
+		cfg.saveRawHTML = *dto.SaveRawHTML
+	}
+	if dto.GraphFormat != nil {
+		cfg.graphFormat = *dto.GraphFormat
+	}
+	if dto.OutputFormats != nil {
+		cfg.outputFormats = *dto.OutputFormats
+	}
 
 	// HTTP client parameters - check if pointer is not nil
 	if dto.MaxIdleConns != nil {
@@ -360,6 +1095,9 @@ func newConfigFromDTOBuilder(dto configDTO) (*Config, error) {
 		}
 		cfg.idleConnTimeout = d
 	}
+	if dto.HTTP2 != nil {
+		cfg.http2 = *dto.HTTP2
+	}
 
 	// Extraction parameters - check if pointer is not nil
 	if dto.BodySpecificityBias != nil {
@@ -396,10 +1134,61 @@ func newConfigFromDTOBuilder(dto configDTO) (*Config, error) {
 	if dto.ThresholdMaxLinkDensity != nil {
 		cfg.thresholdMaxLinkDensity = *dto.ThresholdMaxLinkDensity
 	}
+	if dto.MinTextRatio != nil {
+		cfg.minTextRatio = *dto.MinTextRatio
+	}
+	if dto.NoscriptMode != nil {
+		cfg.noscriptMode = *dto.NoscriptMode
+	}
+	if dto.StructuredExtraction != nil {
+		cfg.structuredExtraction = *dto.StructuredExtraction
+	}
+	if dto.StripByRole != nil {
+		cfg.stripByRole = *dto.StripByRole
+	}
+	if dto.MultiArticleMode != nil {
+		cfg.multiArticleMode = *dto.MultiArticleMode
+	}
 	// HashAlgo - override if provided (pointer not nil)
 	if dto.HashAlgo != nil {
 		cfg.hashAlgo = *dto.HashAlgo
 	}
+	if dto.MarkdownFlavor != nil {
+		cfg.markdownFlavor = *dto.MarkdownFlavor
+	}
+	if dto.GenerateToC != nil {
+		cfg.generateToC = *dto.GenerateToC
+	}
+	if dto.DedupeHeadings != nil {
+		cfg.dedupeHeadings = *dto.DedupeHeadings
+	}
+	if dto.StripInvisibleInCode != nil {
+		cfg.stripInvisibleInCode = *dto.StripInvisibleInCode
+	}
+	if dto.ChunkSize != nil {
+		cfg.chunkSize = *dto.ChunkSize
+	}
+	if dto.ChunkOverlap != nil {
+		cfg.chunkOverlap = *dto.ChunkOverlap
+	}
+	if dto.WriteProvenance != nil {
+		cfg.writeProvenance = *dto.WriteProvenance
+	}
+	if dto.DuplicateContent != nil {
+		cfg.duplicateContent = *dto.DuplicateContent
+	}
+	if dto.ImageDensity != nil {
+		cfg.imageDensity = *dto.ImageDensity
+	}
+	if dto.PreserveUnknownHTML != nil {
+		cfg.preserveUnknownHTML = *dto.PreserveUnknownHTML
+	}
+	if dto.AdmonitionTypes != nil {
+		cfg.admonitionTypes = *dto.AdmonitionTypes
+	}
+	if dto.SpanStyles != nil {
+		cfg.spanStyles = *dto.SpanStyles
+	}
 
 	// SelectorBlacklist - override if provided (pointer not nil)
 	if dto.SelectorBlacklist != nil {
@@ -420,6 +1209,163 @@ func newConfigFromDTOBuilder(dto configDTO) (*Config, error) {
 	return cfg, nil
 }
 
+// newDTOFromConfig returns the DTO newConfigFromDTOBuilder turns back into
+// c. Every field is set, so the DTO lists the defaults too.
+func newDTOFromConfig(c Config) configDTO {
+	seedURLs := make([]string, len(c.seedURLs))
+	for i, u := range c.seedURLs {
+		seedURLs[i] = u.String()
+	}
+	windows := make([]timeWindowDTO, len(c.crawlWindows))
+	for i, w := range c.crawlWindows {
+		windows[i] = timeWindowDTO{Start: formatTimeOfDay(w.start), End: formatTimeOfDay(w.end), Host: w.host}
+	}
+	headerRules := make([]headerRuleDTO, len(c.headerRules))
+	for i, rule := range c.headerRules {
+		headerRules[i] = headerRuleDTO{Pattern: rule.pattern, Headers: rule.Headers()}
+	}
+	hostTLS := make(map[string]tlsDTO, len(c.hostTLS))
+	for host, settings := range c.hostTLS {
+		hostTLS[host] = tlsDTO{CAFile: settings.caFile, InsecureSkipVerify: settings.insecureSkipVerify}
+	}
+
+	return configDTO{
+		SeedURLs:               seedURLs,
+		AllowedHosts:           c.AllowedHosts(),
+		IncludeSubdomains:      &c.includeSubdomains,
+		OnlyHost:               &c.onlyHost,
+		RedirectOutOfScope:     &c.redirectOutOfScope,
+		AllowedPathPrefix:      c.AllowedPathPrefix(),
+		FollowPagination:       &c.followPagination,
+		SinglePage:             &c.singlePage,
+		ExcludedExtensions:     ptrTo(c.ExcludedExtensions()),
+		DefaultDocuments:       ptrTo(c.DefaultDocuments()),
+		TrailingSlashPolicy:    &c.trailingSlashPolicy,
+		DropQueryStrings:       &c.dropQueryStrings,
+		AllowedQueryParams:     ptrTo(c.AllowedQueryParams()),
+		SkipLinkTextPatterns:   ptrTo(c.SkipLinkTextPatterns()),
+		MaxDepth:               &c.maxDepth,
+		HostMaxDepth:           ptrTo(c.HostMaxDepth()),
+		SeedDepths:             ptrTo(c.SeedDepths()),
+		DepthMode:              &c.depthMode,
+		MaxPages:               &c.maxPages,
+		MaxPagesPerDepth:       &c.maxPagesPerDepth,
+		MaxPatternRepeats:      &c.maxPatternRepeats,
+		MaxOutputBytes:         &c.maxOutputBytes,
+		MaxDuration:            ptrTo(c.maxDuration.String()),
+		MaxErrors:              &c.maxErrors,
+		StopAfterFirstMatch:    &c.stopAfterFirstMatch,
+		MatchPattern:           &c.matchPattern,
+		MaxIterations:          &c.maxIterations,
+		RepeatInterval:         ptrTo(c.repeatInterval.String()),
+		DeterministicOrder:     &c.deterministicOrder,
+		FrontierStrategy:       &c.frontierStrategy,
+		Concurrency:            &c.concurrency,
+		BaseDelay:              ptrTo(c.baseDelay.String()),
+		DefaultCrawlDelay:      ptrTo(c.defaultCrawlDelay.String()),
+		DepthDelayFactor:       &c.depthDelayFactor,
+		MaxDepthDelay:          ptrTo(c.maxDepthDelay.String()),
+		Jitter:                 ptrTo(c.jitter.String()),
+		JitterDistribution:     &c.jitterDistribution,
+		RandomSeed:             &c.randomSeed,
+		MaxAttempts:            &c.maxAttempt,
+		BackoffInitialDuration: ptrTo(c.backoffInitialDuration.String()),
+		BackoffMultiplier:      &c.backoffMultiplier,
+		BackoffMaxDuration:     ptrTo(c.backoffMaxDuration.String()),
+		CrawlWindows:           &windows,
+		PageRetry: &pageRetryDTO{
+			MaxAttempts: ptrTo(c.pageRetry.MaxAttempts()),
+			BaseBackoff: ptrTo(c.pageRetry.BaseBackoff().String()),
+		},
+		AutoTune: &autoTuneDTO{
+			Enabled:        &c.autoTune.enabled,
+			MinConcurrency: &c.autoTune.minConcurrency,
+			MaxConcurrency: &c.autoTune.maxConcurrency,
+			MinDelay:       ptrTo(c.autoTune.minDelay.String()),
+			MaxDelay:       ptrTo(c.autoTune.maxDelay.String()),
+		},
+		CircuitBreakerThreshold: &c.circuitBreakerThreshold,
+		StrictMode:              &c.strictMode,
+		Timeout:                 ptrTo(c.timeout.String()),
+		MaxIdleConns:            &c.maxIdleConns,
+		MaxIdleConnsPerHost:     &c.maxIdleConnsPerHost,
+		IdleConnTimeout:         ptrTo(c.idleConnTimeout.String()),
+		HTTP2:                   &c.http2,
+		UserAgent:               &c.userAgent,
+		HostUserAgents:          ptrTo(c.HostUserAgents()),
+		RobotsOverride:          ptrTo(c.RobotsOverride()),
+		DefaultHeaders:          ptrTo(c.DefaultHeaders()),
+		HeaderRules:             &headerRules,
+		TLS:                     &hostTLS,
+		MaxAssetSize:            &c.maxAssetSize,
+		AssetNaming:             &c.assetNaming,
+		ImageMaxWidth:           &c.imageMaxWidth,
+		ImageFormat:             &c.imageFormat,
+		PreflightHead:           &c.preflightHead,
+		AllowedContentTypes:     ptrTo(append([]string(nil), c.allowedContentTypes...)),
+		MaxResponseBytes:        &c.maxResponseBytes,
+		MaxHTMLParseBytes:       &c.maxHTMLParseBytes,
+		TraceRequests:           &c.traceRequests,
+		ReplayArchive:           &c.replayArchive,
+		RobotsWarmup:            &c.robotsWarmup,
+		FetchCacheDir:           &c.fetchCacheDir,
+		FetchCacheTTL:           ptrTo(c.fetchCacheTTL.String()),
+		OfflineOnly:             &c.offlineOnly,
+		OutputDir:               &c.outputDir,
+		DryRun:                  &c.dryRun,
+		LinkCheckOnly:           &c.linkCheckOnly,
+		DumpStageOutput:         &c.dumpStageOutput,
+		SingleFileOutput:        &c.singleFileOutput,
+		SingleFileOnly:          &c.singleFileOnly,
+		PartitionByLanguage:     &c.partitionByLanguage,
+		RelativeLinks:           &c.relativeLinks,
+		SaveRawHTML:             &c.saveRawHTML,
+		GraphFormat:             &c.graphFormat,
+		OutputFormats:           ptrTo(append([]string(nil), c.outputFormats...)),
+
+		BodySpecificityBias:                 &c.bodySpecificityBias,
+		LinkDensityThreshold:                &c.linkDensityThreshold,
+		ScoreMultiplierNonWhitespaceDivisor: &c.scoreMultiplierNonWhitespaceDivisor,
+		ScoreMultiplierParagraphs:           &c.scoreMultiplierParagraphs,
+		ScoreMultiplierHeadings:             &c.scoreMultiplierHeadings,
+		ScoreMultiplierCodeBlocks:           &c.scoreMultiplierCodeBlocks,
+		ScoreMultiplierListItems:            &c.scoreMultiplierListItems,
+		ThresholdMinNonWhitespace:           &c.thresholdMinNonWhitespace,
+		ThresholdMinHeadings:                &c.thresholdMinHeadings,
+		ThresholdMinParagraphsOrCode:        &c.thresholdMinParagraphsOrCode,
+		ThresholdMaxLinkDensity:             &c.thresholdMaxLinkDensity,
+		MinTextRatio:                        &c.minTextRatio,
+		NoscriptMode:                        &c.noscriptMode,
+		StructuredExtraction:                &c.structuredExtraction,
+		StripByRole:                         &c.stripByRole,
+		MultiArticleMode:                    &c.multiArticleMode,
+		HashAlgo:                            &c.hashAlgo,
+		MarkdownFlavor:                      &c.markdownFlavor,
+		GenerateToC:                         &c.generateToC,
+		DedupeHeadings:                      &c.dedupeHeadings,
+		StripInvisibleInCode:                &c.stripInvisibleInCode,
+		ChunkSize:                           &c.chunkSize,
+		ChunkOverlap:                        &c.chunkOverlap,
+		WriteProvenance:                     &c.writeProvenance,
+		DuplicateContent:                    &c.duplicateContent,
+		ImageDensity:                        &c.imageDensity,
+		PreserveUnknownHTML:                 &c.preserveUnknownHTML,
+		AdmonitionTypes:                     ptrTo(c.AdmonitionTypes()),
+		SpanStyles:                          ptrTo(c.SpanStyles()),
+
+		SelectorBlacklist: ptrTo(append([]string(nil), c.selectorBlacklist...)),
+
+		Debug:       &c.debug,
+		DebugFile:   &c.debugFile,
+		DebugFormat: &c.debugFormat,
+	}
+}
+
+// ptrTo returns a pointer to a copy of v.
+func ptrTo[T any](v T) *T {
+	return &v
+}
+
 // WithDefault creates a new Config with the provided seed URLs and default values for all other fields.
 // seedUrls is mandatory and must not be empty - an error will be returned if it is.
 func WithDefault(seedUrls []url.URL) *Config {
@@ -429,24 +1375,51 @@ func WithDefault(seedUrls []url.URL) *Config {
 		allowedPathPrefix: []string{
 			"/",
 		},
+		excludedExtensions:     DefaultExcludedExtensions(),
+		outputFormats:          []string{OutputFormatMarkdown},
+		dropQueryStrings:       true,
 		maxDepth:               3,
+		depthMode:              DepthModeLinkDistance,
+		frontierStrategy:       FrontierStrategyBreadthFirst,
+		redirectOutOfScope:     RedirectOutOfScopeSkip,
 		maxPages:               100,
+		maxPagesPerDepth:       0,
+		maxIterations:          1,
 		concurrency:            10,
 		baseDelay:              time.Second,
+		defaultCrawlDelay:      0,
+		depthDelayFactor:       0,
+		maxDepthDelay:          30 * time.Second,
 		jitter:                 time.Millisecond * 500,
+		jitterDistribution:     JitterUniform,
 		randomSeed:             time.Now().UnixNano(),
 		maxAttempt:             10,
 		backoffInitialDuration: 100 * time.Millisecond,
 		backoffMultiplier:      2.0,
 		backoffMaxDuration:     10 * time.Second,
+		pageRetry:              NewPageRetry(1, time.Second),
+		autoTune:               AutoTune{minConcurrency: 1, maxConcurrency: 4, maxDelay: 2 * time.Second},
 		timeout:                time.Second * 10,
 		maxIdleConns:           10,
 		maxIdleConnsPerHost:    3,
 		idleConnTimeout:        30 * time.Second,
+		http2:                  true,
 		userAgent:              "docs-crawler/1.0",
 		maxAssetSize:           0, // 0 means unlimited
+		assetNaming:            "nameHash",
+		trailingSlashPolicy:    "strip",
+		preflightHead:          false,
+		allowedContentTypes:    []string{"text/html", "application/xhtml+xml"},
+		maxResponseBytes:       0, // 0 means unlimited
+		maxHTMLParseBytes:      0, // 0 means unlimited
+		traceRequests:          false,
+		replayArchive:          "",
+		robotsWarmup:           false,
+		fetchCacheDir:          "",
+		fetchCacheTTL:          24 * time.Hour,
 		outputDir:              "output",
 		dryRun:                 false,
+		linkCheckOnly:          false,
 		// Extraction defaults
 		bodySpecificityBias:                 0.75,
 		linkDensityThreshold:                0.80,
@@ -459,8 +1432,15 @@ func WithDefault(seedUrls []url.URL) *Config {
 		thresholdMinHeadings:                0,
 		thresholdMinParagraphsOrCode:        1,
 		thresholdMaxLinkDensity:             0.8,
+		noscriptMode:                        "drop",
+		stripByRole:                         true,
+		multiArticleMode:                    "largest",
 		// Hash algorithm default
 		hashAlgo: string(hashutil.HashAlgoSHA256),
+		// Markdown flavor default
+		markdownFlavor: "gfm",
+		// Duplicate content default
+		duplicateContent: "write",
 	}
 	return &defaultConfig
 }
@@ -475,182 +1455,564 @@ func (c *Config) WithAllowedHosts(hosts map[string]struct{}) *Config {
 	return c
 }
 
-func (c *Config) WithAllowedPathPrefix(prefixes []string) *Config {
-	c.allowedPathPrefix = prefixes
+func (c *Config) WithIncludeSubdomains(enabled bool) *Config {
+	c.includeSubdomains = enabled
 	return c
 }
 
-func (c *Config) WithMaxDepth(depth int) *Config {
-	c.maxDepth = depth
+func (c *Config) WithOnlyHost(host string) *Config {
+	c.onlyHost = host
 	return c
 }
 
-func (c *Config) WithMaxPages(pages int) *Config {
-	c.maxPages = pages
+func (c *Config) WithRedirectOutOfScope(behavior string) *Config {
+	c.redirectOutOfScope = behavior
 	return c
 }
 
-func (c *Config) WithConcurrency(concurrency int) *Config {
-	c.concurrency = concurrency
+func (c *Config) WithAllowedPathPrefix(prefixes []string) *Config {
+	c.allowedPathPrefix = prefixes
 	return c
 }
 
-func (c *Config) WithBaseDelay(delay time.Duration) *Config {
-	c.baseDelay = delay
+func (c *Config) WithExcludedExtensions(extensions []string) *Config {
+	c.excludedExtensions = extensions
 	return c
 }
 
-func (c *Config) WithJitter(jitter time.Duration) *Config {
-	c.jitter = jitter
+func (c *Config) WithDefaultDocuments(documents []string) *Config {
+	c.defaultDocuments = documents
 	return c
 }
 
-func (c *Config) WithRandomSeed(seed int64) *Config {
-	c.randomSeed = seed
+func (c *Config) WithTrailingSlashPolicy(policy string) *Config {
+	c.trailingSlashPolicy = policy
 	return c
 }
 
-func (c *Config) WithMaxAttempt(attempts int) *Config {
-	c.maxAttempt = attempts
+func (c *Config) WithDropQueryStrings(drop bool) *Config {
+	c.dropQueryStrings = drop
 	return c
 }
 
-func (c *Config) WithBackoffInitialDuration(duration time.Duration) *Config {
-	c.backoffInitialDuration = duration
+func (c *Config) WithAllowedQueryParams(params []string) *Config {
+	c.allowedQueryParams = params
 	return c
 }
 
-func (c *Config) WithBackoffMultiplier(multiplier float64) *Config {
-	c.backoffMultiplier = multiplier
+func (c *Config) WithSkipLinkTextPatterns(patterns []string) *Config {
+	c.skipLinkTextPatterns = patterns
 	return c
 }
 
-func (c *Config) WithBackoffMaxDuration(duration time.Duration) *Config {
-	c.backoffMaxDuration = duration
+func (c *Config) WithFollowPagination(enabled bool) *Config {
+	c.followPagination = enabled
 	return c
 }
 
-func (c *Config) WithTimeout(timeout time.Duration) *Config {
-	c.timeout = timeout
+func (c *Config) WithSinglePage(enabled bool) *Config {
+	c.singlePage = enabled
 	return c
 }
 
-func (c *Config) WithUserAgent(agent string) *Config {
-	c.userAgent = agent
+func (c *Config) WithMaxDepth(depth int) *Config {
+	c.maxDepth = depth
 	return c
 }
 
-func (c *Config) WithMaxAssetSize(size int64) *Config {
-	c.maxAssetSize = size
+func (c *Config) WithHostMaxDepth(depths map[string]int) *Config {
+	c.hostMaxDepth = depths
 	return c
 }
 
-func (c *Config) WithOutputDir(outputDir string) *Config {
-	c.outputDir = outputDir
+func (c *Config) WithSeedDepths(depths map[string]int) *Config {
+	c.seedDepths = depths
 	return c
 }
 
-func (c *Config) WithDryRun(dryRun bool) *Config {
-	c.dryRun = dryRun
+func (c *Config) WithDepthMode(mode string) *Config {
+	c.depthMode = mode
 	return c
 }
 
-func (c *Config) WithDumpStageOutput(dumpStageOutput string) *Config {
-	c.dumpStageOutput = dumpStageOutput
+func (c *Config) WithMaxPages(pages int) *Config {
+	c.maxPages = pages
 	return c
 }
 
-func (c *Config) WithBodySpecificityBias(bias float64) *Config {
-	c.bodySpecificityBias = bias
+func (c *Config) WithMaxPagesPerDepth(pages int) *Config {
+	c.maxPagesPerDepth = pages
 	return c
 }
 
-func (c *Config) WithLinkDensityThreshold(threshold float64) *Config {
-	c.linkDensityThreshold = threshold
+func (c *Config) WithMaxPatternRepeats(repeats int) *Config {
+	c.maxPatternRepeats = repeats
 	return c
 }
 
-func (c *Config) WithScoreMultiplierNonWhitespaceDivisor(divisor float64) *Config {
-	c.scoreMultiplierNonWhitespaceDivisor = divisor
+func (c *Config) WithMaxOutputBytes(bytes int64) *Config {
+	c.maxOutputBytes = bytes
 	return c
 }
 
-func (c *Config) WithScoreMultiplierParagraphs(multiplier float64) *Config {
-	c.scoreMultiplierParagraphs = multiplier
+func (c *Config) WithMaxDuration(duration time.Duration) *Config {
+	c.maxDuration = duration
 	return c
 }
 
-func (c *Config) WithScoreMultiplierHeadings(multiplier float64) *Config {
-	c.scoreMultiplierHeadings = multiplier
+func (c *Config) WithMaxErrors(errors int) *Config {
+	c.maxErrors = errors
 	return c
 }
 
-func (c *Config) WithScoreMultiplierCodeBlocks(multiplier float64) *Config {
-	c.scoreMultiplierCodeBlocks = multiplier
+func (c *Config) WithStopAfterFirstMatch(stop bool) *Config {
+	c.stopAfterFirstMatch = stop
 	return c
 }
 
-func (c *Config) WithScoreMultiplierListItems(multiplier float64) *Config {
-	c.scoreMultiplierListItems = multiplier
+func (c *Config) WithMatchPattern(pattern string) *Config {
+	c.matchPattern = pattern
 	return c
 }
 
-func (c *Config) WithThresholdMinNonWhitespace(min int) *Config {
-	c.thresholdMinNonWhitespace = min
+func (c *Config) WithMaxIterations(iterations int) *Config {
+	c.maxIterations = iterations
 	return c
 }
 
-func (c *Config) WithThresholdMinHeadings(min int) *Config {
-	c.thresholdMinHeadings = min
+func (c *Config) WithRepeatInterval(interval time.Duration) *Config {
+	c.repeatInterval = interval
 	return c
 }
 
-func (c *Config) WithThresholdMinParagraphsOrCode(min int) *Config {
-	c.thresholdMinParagraphsOrCode = min
+func (c *Config) WithDeterministicOrder(enabled bool) *Config {
+	c.deterministicOrder = enabled
 	return c
 }
 
-func (c *Config) WithThresholdMaxLinkDensity(max float64) *Config {
-	c.thresholdMaxLinkDensity = max
+func (c *Config) WithFrontierStrategy(strategy string) *Config {
+	c.frontierStrategy = strategy
 	return c
 }
 
-func (c *Config) WithHashAlgo(algo hashutil.HashAlgo) *Config {
-	c.hashAlgo = string(algo)
+func (c *Config) WithConcurrency(concurrency int) *Config {
+	c.concurrency = concurrency
 	return c
 }
 
-func (c *Config) WithMaxIdleConns(maxIdleConns int) *Config {
-	c.maxIdleConns = maxIdleConns
+func (c *Config) WithBaseDelay(delay time.Duration) *Config {
+	c.baseDelay = delay
 	return c
 }
 
-func (c *Config) WithMaxIdleConnsPerHost(maxIdleConnsPerHost int) *Config {
-	c.maxIdleConnsPerHost = maxIdleConnsPerHost
+func (c *Config) WithDefaultCrawlDelay(delay time.Duration) *Config {
+	c.defaultCrawlDelay = delay
 	return c
 }
 
-func (c *Config) WithIdleConnTimeout(idleConnTimeout time.Duration) *Config {
-	c.idleConnTimeout = idleConnTimeout
+func (c *Config) WithDepthDelayFactor(factor float64) *Config {
+	c.depthDelayFactor = factor
 	return c
 }
 
-func (c *Config) WithSelectorBlacklist(selectors []string) *Config {
-	c.selectorBlacklist = selectors
+func (c *Config) WithMaxDepthDelay(delay time.Duration) *Config {
+	c.maxDepthDelay = delay
 	return c
 }
 
-func (c *Config) WithDebug(debug bool) *Config {
-	c.debug = debug
+func (c *Config) WithJitter(jitter time.Duration) *Config {
+	c.jitter = jitter
 	return c
 }
 
-func (c *Config) WithDebugFile(file string) *Config {
-	c.debugFile = file
+func (c *Config) WithJitterDistribution(distribution string) *Config {
+	c.jitterDistribution = distribution
 	return c
 }
 
-func (c *Config) WithDebugFormat(format string) *Config {
+func (c *Config) WithRandomSeed(seed int64) *Config {
+	c.randomSeed = seed
+	return c
+}
+
+func (c *Config) WithMaxAttempt(attempts int) *Config {
+	c.maxAttempt = attempts
+	return c
+}
+
+func (c *Config) WithBackoffInitialDuration(duration time.Duration) *Config {
+	c.backoffInitialDuration = duration
+	return c
+}
+
+func (c *Config) WithBackoffMultiplier(multiplier float64) *Config {
+	c.backoffMultiplier = multiplier
+	return c
+}
+
+func (c *Config) WithBackoffMaxDuration(duration time.Duration) *Config {
+	c.backoffMaxDuration = duration
+	return c
+}
+
+func (c *Config) WithTimeout(timeout time.Duration) *Config {
+	c.timeout = timeout
+	return c
+}
+
+func (c *Config) WithUserAgent(agent string) *Config {
+	c.userAgent = agent
+	return c
+}
+
+func (c *Config) WithHostUserAgents(agents map[string]string) *Config {
+	c.hostUserAgents = agents
+	return c
+}
+
+func (c *Config) WithRobotsOverride(overrides map[string]string) *Config {
+	c.robotsOverride = overrides
+	return c
+}
+
+func (c *Config) WithDefaultHeaders(headers map[string]string) *Config {
+	c.defaultHeaders = headers
+	return c
+}
+
+func (c *Config) WithHeaderRules(rules []HeaderRule) *Config {
+	c.headerRules = rules
+	return c
+}
+
+func (c *Config) WithTLS(hosts map[string]HostTLS) *Config {
+	c.hostTLS = hosts
+	return c
+}
+
+func (c *Config) WithMaxAssetSize(size int64) *Config {
+	c.maxAssetSize = size
+	return c
+}
+
+func (c *Config) WithAssetNaming(naming string) *Config {
+	c.assetNaming = naming
+	return c
+}
+
+func (c *Config) WithImageMaxWidth(width int) *Config {
+	c.imageMaxWidth = width
+	return c
+}
+
+func (c *Config) WithImageFormat(format string) *Config {
+	c.imageFormat = format
+	return c
+}
+
+func (c *Config) WithPreflightHead(enabled bool) *Config {
+	c.preflightHead = enabled
+	return c
+}
+
+func (c *Config) WithAllowedContentTypes(contentTypes []string) *Config {
+	c.allowedContentTypes = contentTypes
+	return c
+}
+
+func (c *Config) WithMaxResponseBytes(size int64) *Config {
+	c.maxResponseBytes = size
+	return c
+}
+
+func (c *Config) WithMaxHTMLParseBytes(size int64) *Config {
+	c.maxHTMLParseBytes = size
+	return c
+}
+
+func (c *Config) WithTraceRequests(enabled bool) *Config {
+	c.traceRequests = enabled
+	return c
+}
+
+func (c *Config) WithReplayArchive(dir string) *Config {
+	c.replayArchive = dir
+	return c
+}
+
+func (c *Config) WithRobotsWarmup(warmup bool) *Config {
+	c.robotsWarmup = warmup
+	return c
+}
+
+func (c *Config) WithFetchCache(dir string, ttl time.Duration) *Config {
+	c.fetchCacheDir = dir
+	c.fetchCacheTTL = ttl
+	return c
+}
+
+func (c *Config) WithOfflineOnly(offlineOnly bool) *Config {
+	c.offlineOnly = offlineOnly
+	return c
+}
+
+func (c *Config) WithOutputDir(outputDir string) *Config {
+	c.outputDir = outputDir
+	return c
+}
+
+func (c *Config) WithDryRun(dryRun bool) *Config {
+	c.dryRun = dryRun
+	return c
+}
+
+func (c *Config) WithLinkCheckOnly(linkCheckOnly bool) *Config {
+	c.linkCheckOnly = linkCheckOnly
+	return c
+}
+
+func (c *Config) WithDumpStageOutput(dumpStageOutput string) *Config {
+	c.dumpStageOutput = dumpStageOutput
+	return c
+}
+
+func (c *Config) WithSingleFileOutput(path string) *Config {
+	c.singleFileOutput = path
+	return c
+}
+
+func (c *Config) WithSingleFileOnly(only bool) *Config {
+	c.singleFileOnly = only
+	return c
+}
+
+func (c *Config) WithPartitionByLanguage(partition bool) *Config {
+	c.partitionByLanguage = partition
+	return c
+}
+
+func (c *Config) WithRelativeLinks(relative bool) *Config {
+	c.relativeLinks = relative
+	return c
+}
+
+func (c *Config) WithSaveRawHTML(save bool) *Config {
+	c.saveRawHTML = save
+	return c
+}
+
+func (c *Config) WithGraphFormat(format string) *Config {
+	c.graphFormat = format
+	return c
+}
+
+func (c *Config) WithOutputFormats(formats []string) *Config {
+	c.outputFormats = formats
+	return c
+}
+
+func (c *Config) WithBodySpecificityBias(bias float64) *Config {
+	c.bodySpecificityBias = bias
+	return c
+}
+
+func (c *Config) WithLinkDensityThreshold(threshold float64) *Config {
+	c.linkDensityThreshold = threshold
+	return c
+}
+
+func (c *Config) WithScoreMultiplierNonWhitespaceDivisor(divisor float64) *Config {
+	c.scoreMultiplierNonWhitespaceDivisor = divisor
+	return c
+}
+
+func (c *Config) WithScoreMultiplierParagraphs(multiplier float64) *Config {
+	c.scoreMultiplierParagraphs = multiplier
+	return c
+}
+
+func (c *Config) WithScoreMultiplierHeadings(multiplier float64) *Config {
+	c.scoreMultiplierHeadings = multiplier
+	return c
+}
+
+func (c *Config) WithScoreMultiplierCodeBlocks(multiplier float64) *Config {
+	c.scoreMultiplierCodeBlocks = multiplier
+	return c
+}
+
+func (c *Config) WithScoreMultiplierListItems(multiplier float64) *Config {
+	c.scoreMultiplierListItems = multiplier
+	return c
+}
+
+func (c *Config) WithThresholdMinNonWhitespace(min int) *Config {
+	c.thresholdMinNonWhitespace = min
+	return c
+}
+
+func (c *Config) WithThresholdMinHeadings(min int) *Config {
+	c.thresholdMinHeadings = min
+	return c
+}
+
+func (c *Config) WithThresholdMinParagraphsOrCode(min int) *Config {
+	c.thresholdMinParagraphsOrCode = min
+	return c
+}
+
+func (c *Config) WithThresholdMaxLinkDensity(max float64) *Config {
+	c.thresholdMaxLinkDensity = max
+	return c
+}
+
+func (c *Config) WithMinTextRatio(ratio float64) *Config {
+	c.minTextRatio = ratio
+	return c
+}
+
+func (c *Config) WithNoscriptMode(mode string) *Config {
+	c.noscriptMode = mode
+	return c
+}
+
+func (c *Config) WithStructuredExtraction(enabled bool) *Config {
+	c.structuredExtraction = enabled
+	return c
+}
+
+func (c *Config) WithStripByRole(enabled bool) *Config {
+	c.stripByRole = enabled
+	return c
+}
+
+func (c *Config) WithMultiArticleMode(mode string) *Config {
+	c.multiArticleMode = mode
+	return c
+}
+
+func (c *Config) WithHashAlgo(algo hashutil.HashAlgo) *Config {
+	c.hashAlgo = string(algo)
+	return c
+}
+
+func (c *Config) WithMarkdownFlavor(flavor string) *Config {
+	c.markdownFlavor = flavor
+	return c
+}
+
+func (c *Config) WithGenerateToC(enabled bool) *Config {
+	c.generateToC = enabled
+	return c
+}
+
+func (c *Config) WithDedupeHeadings(enabled bool) *Config {
+	c.dedupeHeadings = enabled
+	return c
+}
+
+func (c *Config) WithStripInvisibleInCode(enabled bool) *Config {
+	c.stripInvisibleInCode = enabled
+	return c
+}
+
+func (c *Config) WithChunking(size, overlap int) *Config {
+	c.chunkSize = size
+	c.chunkOverlap = overlap
+	return c
+}
+
+func (c *Config) WithWriteProvenance(enabled bool) *Config {
+	c.writeProvenance = enabled
+	return c
+}
+
+func (c *Config) WithDuplicateContent(mode string) *Config {
+	c.duplicateContent = mode
+	return c
+}
+
+func (c *Config) WithImageDensity(density float64) *Config {
+	c.imageDensity = density
+	return c
+}
+
+func (c *Config) WithPreserveUnknownHTML(preserve bool) *Config {
+	c.preserveUnknownHTML = preserve
+	return c
+}
+
+func (c *Config) WithAdmonitionTypes(types map[string]string) *Config {
+	c.admonitionTypes = types
+	return c
+}
+
+func (c *Config) WithSpanStyles(styles map[string]string) *Config {
+	c.spanStyles = styles
+	return c
+}
+
+func (c *Config) WithMaxIdleConns(maxIdleConns int) *Config {
+	c.maxIdleConns = maxIdleConns
+	return c
+}
+
+func (c *Config) WithMaxIdleConnsPerHost(maxIdleConnsPerHost int) *Config {
+	c.maxIdleConnsPerHost = maxIdleConnsPerHost
+	return c
+}
+
+func (c *Config) WithIdleConnTimeout(idleConnTimeout time.Duration) *Config {
+	c.idleConnTimeout = idleConnTimeout
+	return c
+}
+
+func (c *Config) WithHTTP2(enabled bool) *Config {
+	c.http2 = enabled
+	return c
+}
+
+func (c *Config) WithCrawlWindows(windows []TimeWindow) *Config {
+	c.crawlWindows = windows
+	return c
+}
+
+func (c *Config) WithPageRetry(pageRetry PageRetry) *Config {
+	c.pageRetry = pageRetry
+	return c
+}
+
+func (c *Config) WithAutoTune(autoTune AutoTune) *Config {
+	c.autoTune = autoTune
+	return c
+}
+
+func (c *Config) WithCircuitBreakerThreshold(threshold int) *Config {
+	c.circuitBreakerThreshold = threshold
+	return c
+}
+
+func (c *Config) WithStrictMode(enabled bool) *Config {
+	c.strictMode = enabled
+	return c
+}
+
+func (c *Config) WithSelectorBlacklist(selectors []string) *Config {
+	c.selectorBlacklist = selectors
+	return c
+}
+
+func (c *Config) WithDebug(debug bool) *Config {
+	c.debug = debug
+	return c
+}
+
+func (c *Config) WithDebugFile(file string) *Config {
+	c.debugFile = file
+	return c
+}
+
+func (c *Config) WithDebugFormat(format string) *Config {
 	c.debugFormat = format
 	return c
 }
@@ -659,6 +2021,155 @@ func (c *Config) Build() (Config, error) {
 	if len(c.seedURLs) == 0 {
 		return Config{}, fmt.Errorf("%w: seedUrls cannot be empty", ErrInvalidConfig)
 	}
+	if c.maxPagesPerDepth < 0 {
+		return Config{}, fmt.Errorf("%w: maxPagesPerDepth cannot be negative, got %d", ErrInvalidConfig, c.maxPagesPerDepth)
+	}
+	if c.maxPatternRepeats < 0 {
+		return Config{}, fmt.Errorf("%w: maxPatternRepeats cannot be negative, got %d", ErrInvalidConfig, c.maxPatternRepeats)
+	}
+	if c.maxOutputBytes < 0 {
+		return Config{}, fmt.Errorf("%w: maxOutputBytes cannot be negative, got %d", ErrInvalidConfig, c.maxOutputBytes)
+	}
+	if c.defaultCrawlDelay < 0 {
+		return Config{}, fmt.Errorf("%w: defaultCrawlDelay cannot be negative, got %s", ErrInvalidConfig, c.defaultCrawlDelay)
+	}
+	if c.depthDelayFactor < 0 {
+		return Config{}, fmt.Errorf("%w: depthDelayFactor cannot be negative, got %g", ErrInvalidConfig, c.depthDelayFactor)
+	}
+	if c.maxDepthDelay < 0 {
+		return Config{}, fmt.Errorf("%w: maxDepthDelay cannot be negative, got %s", ErrInvalidConfig, c.maxDepthDelay)
+	}
+	if c.maxHTMLParseBytes < 0 {
+		return Config{}, fmt.Errorf("%w: maxHTMLParseBytes cannot be negative, got %d", ErrInvalidConfig, c.maxHTMLParseBytes)
+	}
+	if c.maxDuration < 0 {
+		return Config{}, fmt.Errorf("%w: maxDuration cannot be negative, got %s", ErrInvalidConfig, c.maxDuration)
+	}
+	if c.maxErrors < 0 {
+		return Config{}, fmt.Errorf("%w: maxErrors cannot be negative, got %d", ErrInvalidConfig, c.maxErrors)
+	}
+	if c.maxIterations < 0 {
+		return Config{}, fmt.Errorf("%w: maxIterations cannot be negative, got %d", ErrInvalidConfig, c.maxIterations)
+	}
+	if c.repeatInterval < 0 {
+		return Config{}, fmt.Errorf("%w: repeatInterval cannot be negative, got %s", ErrInvalidConfig, c.repeatInterval)
+	}
+	if c.maxIterations == 0 && c.repeatInterval == 0 {
+		return Config{}, fmt.Errorf("%w: unlimited maxIterations needs a repeatInterval", ErrInvalidConfig)
+	}
+	if c.fetchCacheTTL < 0 {
+		return Config{}, fmt.Errorf("%w: fetchCacheTTL cannot be negative, got %s", ErrInvalidConfig, c.fetchCacheTTL)
+	}
+	if c.offlineOnly && c.fetchCacheDir == "" {
+		return Config{}, fmt.Errorf("%w: offlineOnly needs a fetchCacheDir", ErrInvalidConfig)
+	}
+	if c.trailingSlashPolicy != "strip" && c.trailingSlashPolicy != "preserve" && c.trailingSlashPolicy != "add" {
+		return Config{}, fmt.Errorf("%w: trailingSlashPolicy must be \"strip\", \"preserve\" or \"add\", got %q", ErrInvalidConfig, c.trailingSlashPolicy)
+	}
+	if c.multiArticleMode != "largest" && c.multiArticleMode != "concatenate" && c.multiArticleMode != "separateOutputs" {
+		return Config{}, fmt.Errorf("%w: multiArticleMode must be \"largest\", \"concatenate\" or \"separateOutputs\", got %q", ErrInvalidConfig, c.multiArticleMode)
+	}
+	if c.assetNaming != "nameHash" && c.assetNaming != "contentHash" && c.assetNaming != "mirror" {
+		return Config{}, fmt.Errorf("%w: assetNaming must be \"nameHash\", \"contentHash\" or \"mirror\", got %q", ErrInvalidConfig, c.assetNaming)
+	}
+	if c.imageMaxWidth < 0 {
+		return Config{}, fmt.Errorf("%w: imageMaxWidth cannot be negative, got %d", ErrInvalidConfig, c.imageMaxWidth)
+	}
+	if c.imageFormat == "webp" {
+		return Config{}, fmt.Errorf("%w: imageFormat \"webp\" is not supported, only \"png\" and \"jpeg\" can be written", ErrInvalidConfig)
+	}
+	if c.imageFormat != "" && c.imageFormat != "png" && c.imageFormat != "jpeg" {
+		return Config{}, fmt.Errorf("%w: imageFormat must be empty, \"png\" or \"jpeg\", got %q", ErrInvalidConfig, c.imageFormat)
+	}
+	if c.minTextRatio < 0 || c.minTextRatio > 1 {
+		return Config{}, fmt.Errorf("%w: minTextRatio must be between 0 and 1, got %g", ErrInvalidConfig, c.minTextRatio)
+	}
+	if c.circuitBreakerThreshold < 0 {
+		return Config{}, fmt.Errorf("%w: circuitBreakerThreshold cannot be negative, got %d", ErrInvalidConfig, c.circuitBreakerThreshold)
+	}
+	for host, depth := range c.hostMaxDepth {
+		if depth < 0 {
+			return Config{}, fmt.Errorf("%w: hostMaxDepth for %q cannot be negative, got %d", ErrInvalidConfig, host, depth)
+		}
+	}
+	if err := c.validateSeedDepths(); err != nil {
+		return Config{}, err
+	}
+	if c.frontierStrategy != FrontierStrategyBreadthFirst && c.frontierStrategy != FrontierStrategyBestFirst {
+		return Config{}, fmt.Errorf("%w: frontierStrategy must be %q or %q, got %q", ErrInvalidConfig, FrontierStrategyBreadthFirst, FrontierStrategyBestFirst, c.frontierStrategy)
+	}
+	if c.redirectOutOfScope != RedirectOutOfScopeSkip && c.redirectOutOfScope != RedirectOutOfScopeWrite {
+		return Config{}, fmt.Errorf("%w: redirectOutOfScope must be %q or %q, got %q", ErrInvalidConfig, RedirectOutOfScopeSkip, RedirectOutOfScopeWrite, c.redirectOutOfScope)
+	}
+	switch c.jitterDistribution {
+	case JitterUniform, JitterExponential, JitterNormal:
+	default:
+		return Config{}, fmt.Errorf("%w: jitterDistribution must be %q, %q or %q, got %q", ErrInvalidConfig, JitterUniform, JitterExponential, JitterNormal, c.jitterDistribution)
+	}
+	if c.depthMode != DepthModeLinkDistance && c.depthMode != DepthModePathDepth {
+		return Config{}, fmt.Errorf("%w: depthMode must be %q or %q, got %q", ErrInvalidConfig, DepthModeLinkDistance, DepthModePathDepth, c.depthMode)
+	}
+	for class, alert := range c.admonitionTypes {
+		switch strings.ToUpper(alert) {
+		case "", "NOTE", "TIP", "IMPORTANT", "WARNING", "CAUTION":
+		default:
+			return Config{}, fmt.Errorf("%w: admonitionTypes for %q must be NOTE, TIP, IMPORTANT, WARNING or CAUTION, got %q", ErrInvalidConfig, class, alert)
+		}
+	}
+	for class, style := range c.spanStyles {
+		if style != "" && !spanStylePattern.MatchString(strings.ToLower(style)) {
+			return Config{}, fmt.Errorf("%w: spanStyles for %q must be \"code\", \"strong\", \"em\" or an inline HTML tag like \"<kbd>\", got %q", ErrInvalidConfig, class, style)
+		}
+	}
+	if c.markdownFlavor != "gfm" && c.markdownFlavor != "commonmark" {
+		return Config{}, fmt.Errorf("%w: markdownFlavor must be \"gfm\" or \"commonmark\", got %q", ErrInvalidConfig, c.markdownFlavor)
+	}
+	if c.duplicateContent != "write" && c.duplicateContent != "alias" {
+		return Config{}, fmt.Errorf("%w: duplicateContent must be \"write\" or \"alias\", got %q", ErrInvalidConfig, c.duplicateContent)
+	}
+	if c.singleFileOnly && c.singleFileOutput == "" {
+		return Config{}, fmt.Errorf("%w: singleFileOnly requires singleFileOutput", ErrInvalidConfig)
+	}
+	if c.relativeLinks && c.singleFileOnly {
+		return Config{}, fmt.Errorf("%w: relativeLinks cannot be combined with singleFileOnly", ErrInvalidConfig)
+	}
+	if c.graphFormat != "" && c.graphFormat != GraphFormatJSON && c.graphFormat != GraphFormatDOT {
+		return Config{}, fmt.Errorf("%w: graphFormat must be empty, %q or %q, got %q", ErrInvalidConfig, GraphFormatJSON, GraphFormatDOT, c.graphFormat)
+	}
+	if err := validateOutputFormats(c.outputFormats); err != nil {
+		return Config{}, err
+	}
+	if c.singleFileOnly && len(c.outputFormats) > 1 {
+		return Config{}, fmt.Errorf("%w: outputFormats other than %q cannot be combined with singleFileOnly", ErrInvalidConfig, OutputFormatMarkdown)
+	}
+	if c.chunkSize < 0 || c.chunkOverlap < 0 {
+		return Config{}, fmt.Errorf("%w: chunkSize and chunkOverlap cannot be negative, got %d and %d", ErrInvalidConfig, c.chunkSize, c.chunkOverlap)
+	}
+	if c.chunkSize > 0 && c.chunkOverlap >= c.chunkSize {
+		return Config{}, fmt.Errorf("%w: chunkOverlap (%d) must be lower than chunkSize (%d)", ErrInvalidConfig, c.chunkOverlap, c.chunkSize)
+	}
+	if c.autoTune.Enabled() {
+		if err := c.autoTune.validate(); err != nil {
+			return Config{}, err
+		}
+	}
+
+	// Limiting the run to one host drops seeds elsewhere and replaces the
+	// configured scope with that host alone
+	if c.onlyHost != "" {
+		var seeds []url.URL
+		for _, u := range c.seedURLs {
+			if strings.EqualFold(u.Host, c.onlyHost) {
+				seeds = append(seeds, u)
+			}
+		}
+		if len(seeds) == 0 {
+			return Config{}, fmt.Errorf("%w: no seed URL is on onlyHost %q", ErrInvalidConfig, c.onlyHost)
+		}
+		c.seedURLs = seeds
+		c.allowedHosts = map[string]struct{}{strings.ToLower(c.onlyHost): {}}
+		c.includeSubdomains = false
+	}
 
 	// If allowedHosts is empty, default to seed URLs hostnames
 	if len(c.allowedHosts) == 0 {
@@ -670,6 +2181,44 @@ func (c *Config) Build() (Config, error) {
 		}
 	}
 
+	var subdomainsOf []string
+	if c.includeSubdomains {
+		for _, u := range c.seedURLs {
+			subdomainsOf = append(subdomainsOf, u.Host)
+		}
+	}
+	hostMatcher, err := compileHostMatcher(c.allowedHosts, subdomainsOf)
+	if err != nil {
+		return Config{}, err
+	}
+	c.hostMatcher = hostMatcher
+	c.extensionMatcher = compileExtensionMatcher(c.excludedExtensions)
+	c.queryFilter = compileQueryFilter(c.dropQueryStrings, c.allowedQueryParams)
+	linkTextMatcher, err := compileLinkTextMatcher(c.skipLinkTextPatterns)
+	if err != nil {
+		return Config{}, err
+	}
+	c.linkTextMatcher = linkTextMatcher
+	if c.matchPattern != "" {
+		re, err := regexp.Compile(c.matchPattern)
+		if err != nil {
+			return Config{}, fmt.Errorf("%w: matchPattern %q is invalid: %v", ErrInvalidConfig, c.matchPattern, err)
+		}
+		c.matchRegexp = re
+	} else if c.stopAfterFirstMatch {
+		return Config{}, fmt.Errorf("%w: stopAfterFirstMatch needs a matchPattern", ErrInvalidConfig)
+	}
+	headerRules, err := compileHeaderRules(c.headerRules)
+	if err != nil {
+		return Config{}, err
+	}
+	c.headerRules = headerRules
+	hostTLS, err := normalizeHostTLS(c.hostTLS)
+	if err != nil {
+		return Config{}, err
+	}
+	c.hostTLS = hostTLS
+
 	return *c, nil
 }
 
@@ -687,20 +2236,207 @@ func (c Config) AllowedHosts() map[string]struct{} {
 	return hosts
 }
 
+// SortedAllowedHosts returns the allowed hosts in sorted order, for listing
+// them the same way on every run.
+func (c Config) SortedAllowedHosts() []string {
+	return sortedHosts(c.allowedHosts)
+}
+
+func (c Config) IncludeSubdomains() bool {
+	return c.includeSubdomains
+}
+
+// OnlyHost returns the host the run is limited to; empty means no limit.
+func (c Config) OnlyHost() string {
+	return c.onlyHost
+}
+
+// RedirectOutOfScope returns how pages redirected out of scope are handled.
+func (c Config) RedirectOutOfScope() string {
+	return c.redirectOutOfScope
+}
+
+func (c Config) FollowPagination() bool {
+	return c.followPagination
+}
+
+func (c Config) SinglePage() bool {
+	return c.singlePage
+}
+
+// IsHostAllowed reports whether host matches an allowed host or host pattern.
+func (c Config) IsHostAllowed(host string) bool {
+	return c.hostMatcher.Matches(host)
+}
+
+// HostMatcher returns the allowed hosts compiled for matching.
+func (c Config) HostMatcher() HostMatcher {
+	return c.hostMatcher
+}
+
 func (c Config) AllowedPathPrefix() []string {
 	prefixes := make([]string, len(c.allowedPathPrefix))
 	copy(prefixes, c.allowedPathPrefix)
 	return prefixes
 }
 
+func (c Config) ExcludedExtensions() []string {
+	return append([]string(nil), c.excludedExtensions...)
+}
+
+// ExtensionMatcher returns the excluded extensions compiled for matching.
+func (c Config) ExtensionMatcher() ExtensionMatcher {
+	return c.extensionMatcher
+}
+
+func (c Config) DefaultDocuments() []string {
+	return append([]string(nil), c.defaultDocuments...)
+}
+
+// TrailingSlashPolicy returns whether a trailing slash tells URLs apart:
+// "strip", "preserve" or "add".
+func (c Config) TrailingSlashPolicy() string {
+	return c.trailingSlashPolicy
+}
+
+func (c Config) DropQueryStrings() bool {
+	return c.dropQueryStrings
+}
+
+func (c Config) AllowedQueryParams() []string {
+	return append([]string(nil), c.allowedQueryParams...)
+}
+
+// QueryFilter returns the query string settings compiled for filtering.
+func (c Config) QueryFilter() QueryFilter {
+	return c.queryFilter
+}
+
+func (c Config) SkipLinkTextPatterns() []string {
+	return append([]string(nil), c.skipLinkTextPatterns...)
+}
+
+// LinkTextMatcher returns the skip link text patterns compiled for matching.
+func (c Config) LinkTextMatcher() LinkTextMatcher {
+	return c.linkTextMatcher
+}
+
 func (c Config) MaxDepth() int {
 	return c.maxDepth
 }
 
+func (c Config) DepthMode() string {
+	return c.depthMode
+}
+
+// HostMaxDepth returns a copy of the per-host maxDepth overrides,
+// or nil when none are configured.
+func (c Config) HostMaxDepth() map[string]int {
+	if len(c.hostMaxDepth) == 0 {
+		return nil
+	}
+	depths := make(map[string]int, len(c.hostMaxDepth))
+	for host, depth := range c.hostMaxDepth {
+		depths[host] = depth
+	}
+	return depths
+}
+
+// MaxDepthFor returns the maximum depth for URLs on host, falling back to
+// MaxDepth when the host has no override.
+func (c Config) MaxDepthFor(host string) int {
+	if depth, ok := c.hostMaxDepth[host]; ok {
+		return depth
+	}
+	return c.maxDepth
+}
+
+// SeedDepths returns a copy of the starting depths of the seed URLs, or
+// nil when none are configured.
+func (c Config) SeedDepths() map[string]int {
+	if len(c.seedDepths) == 0 {
+		return nil
+	}
+	depths := make(map[string]int, len(c.seedDepths))
+	for seed, depth := range c.seedDepths {
+		depths[seed] = depth
+	}
+	return depths
+}
+
+// SeedDepth returns the depth the seed URL seed is admitted at, 0 unless
+// seedDepths lists it.
+func (c Config) SeedDepth(seed url.URL) int {
+	return c.seedDepths[seed.String()]
+}
+
 func (c Config) MaxPages() int {
 	return c.maxPages
 }
 
+func (c Config) MaxPagesPerDepth() int {
+	return c.maxPagesPerDepth
+}
+
+// MaxPatternRepeats returns the number of URLs admitted per numeric URL
+// pattern; 0 means unlimited.
+func (c Config) MaxPatternRepeats() int {
+	return c.maxPatternRepeats
+}
+
+// MaxOutputBytes returns the output size budget in bytes; 0 means unlimited.
+func (c Config) MaxOutputBytes() int64 {
+	return c.maxOutputBytes
+}
+
+// MaxDuration returns the crawl loop's time budget; 0 means unlimited.
+func (c Config) MaxDuration() time.Duration {
+	return c.maxDuration
+}
+
+// MaxErrors returns the page failure budget; 0 means unlimited.
+func (c Config) MaxErrors() int {
+	return c.maxErrors
+}
+
+// StopAfterFirstMatch reports whether the crawl stops once the first page
+// matching MatchPattern is written.
+func (c Config) StopAfterFirstMatch() bool {
+	return c.stopAfterFirstMatch
+}
+
+// MatchPattern returns the page match pattern; "" matches nothing.
+func (c Config) MatchPattern() string {
+	return c.matchPattern
+}
+
+// MatchesPage reports whether the page's URL or normalized content matches
+// MatchPattern. It is always false without a pattern.
+func (c Config) MatchesPage(pageURL string, content []byte) bool {
+	if c.matchRegexp == nil {
+		return false
+	}
+	return c.matchRegexp.MatchString(pageURL) || c.matchRegexp.Match(content)
+}
+
+// MaxIterations returns the number of crawl passes; 0 means unlimited.
+func (c Config) MaxIterations() int {
+	return c.maxIterations
+}
+
+// RepeatInterval returns the wait between two crawl passes.
+func (c Config) RepeatInterval() time.Duration {
+	return c.repeatInterval
+}
+
+func (c Config) DeterministicOrder() bool {
+	return c.deterministicOrder
+}
+
+func (c Config) FrontierStrategy() string {
+	return c.frontierStrategy
+}
+
 func (c Config) Concurrency() int {
 	return c.concurrency
 }
@@ -709,14 +2445,128 @@ func (c Config) BaseDelay() time.Duration {
 	return c.baseDelay
 }
 
+// DefaultCrawlDelay returns the crawl delay of hosts whose robots.txt sets
+// none, which is also the floor of the one it sets.
+func (c Config) DefaultCrawlDelay() time.Duration {
+	return c.defaultCrawlDelay
+}
+
+// DepthDelayFactor returns how much the delay grows per level of depth;
+// 0 keeps the base delay at every depth.
+func (c Config) DepthDelayFactor() float64 {
+	return c.depthDelayFactor
+}
+
+// MaxDepthDelay returns the cap of the depth-scaled delay; 0 means uncapped.
+func (c Config) MaxDepthDelay() time.Duration {
+	return c.maxDepthDelay
+}
+
 func (c Config) Jitter() time.Duration {
 	return c.jitter
 }
 
+func (c Config) JitterDistribution() string {
+	return c.jitterDistribution
+}
+
 func (c Config) RandomSeed() int64 {
 	return c.randomSeed
 }
 
+// Jitter distributions, see Config.jitterDistribution.
+const (
+	JitterUniform     = "uniform"
+	JitterExponential = "exponential"
+	JitterNormal      = "normal"
+)
+
+// Depth modes, see Config.depthMode.
+const (
+	DepthModeLinkDistance = "linkDistance"
+	DepthModePathDepth    = "pathDepth"
+)
+
+// Frontier strategies, see Config.frontierStrategy.
+const (
+	FrontierStrategyBreadthFirst = "breadthFirst"
+	FrontierStrategyBestFirst    = "bestFirst"
+)
+
+// Handling of out-of-scope redirects, see Config.redirectOutOfScope.
+const (
+	RedirectOutOfScopeSkip  = "skip"
+	RedirectOutOfScopeWrite = "write"
+)
+
+// spanStylePattern matches the span styles, see Config.spanStyles.
+var spanStylePattern = regexp.MustCompile(`^(code|strong|em|<[a-z][a-z0-9]*>)$`)
+
+// Crawl graph formats, see Config.graphFormat.
+const (
+	GraphFormatJSON = "json"
+	GraphFormatDOT  = "dot"
+)
+
+// Page output formats, see Config.outputFormats. Each is also the file
+// extension the format is written with.
+const (
+	OutputFormatMarkdown = "md"
+	OutputFormatHTML     = "html"
+	OutputFormatText     = "txt"
+)
+
+// validateOutputFormats checks that formats are known, listed once, and
+// include Markdown.
+func validateOutputFormats(formats []string) error {
+	seen := make(map[string]bool, len(formats))
+	for _, format := range formats {
+		switch format {
+		case OutputFormatMarkdown, OutputFormatHTML, OutputFormatText:
+		default:
+			return fmt.Errorf("%w: outputFormats entries must be %q, %q or %q, got %q", ErrInvalidConfig, OutputFormatMarkdown, OutputFormatHTML, OutputFormatText, format)
+		}
+		if seen[format] {
+			return fmt.Errorf("%w: outputFormats lists %q twice", ErrInvalidConfig, format)
+		}
+		seen[format] = true
+	}
+	if !seen[OutputFormatMarkdown] {
+		return fmt.Errorf("%w: outputFormats must include %q", ErrInvalidConfig, OutputFormatMarkdown)
+	}
+	return nil
+}
+
+// Sub-seed component names. Every randomized component derives its own
+// stream from RandomSeed via SubSeed using one of these names, so adding
+// randomness to one component never shifts the sequence seen by another.
+const (
+	SubSeedRateLimiterJitter = "ratelimiter.jitter"
+	SubSeedFetcherRetry      = "fetcher.retry"
+)
+
+// SubSeed deterministically derives an independent seed for the named
+// randomized component from the per-run RandomSeed.
+//
+// Derivation scheme:
+//
+//	digest  = SHA-256( bigEndianUint64(RandomSeed) || utf8(name) )
+//	subSeed = int64(bigEndianUint64(digest[0:8]))
+//
+// The same (RandomSeed, name) pair always yields the same sub-seed, and
+// distinct names yield unrelated streams.
+func (c Config) SubSeed(name string) int64 {
+	var seedBytes [8]byte
+	binary.BigEndian.PutUint64(seedBytes[:], uint64(c.randomSeed))
+
+	h := sha256.New()
+	h.Write(seedBytes[:])
+	h.Write([]byte(name))
+	digest := h.Sum(nil)
+
+	return int64(binary.BigEndian.Uint64(digest[:8]))
+}
+
 func (c Config) Timeout() time.Duration {
 	return c.timeout
 }
@@ -725,10 +2575,136 @@ func (c Config) UserAgent() string {
 	return c.userAgent
 }
 
+// HostUserAgents returns a copy of the per-host user agent overrides,
+// or nil when none are configured.
+func (c Config) HostUserAgents() map[string]string {
+	if len(c.hostUserAgents) == 0 {
+		return nil
+	}
+	agents := make(map[string]string, len(c.hostUserAgents))
+	for host, agent := range c.hostUserAgents {
+		agents[host] = agent
+	}
+	return agents
+}
+
+// RobotsOverride returns a copy of the inline robots.txt contents per host,
+// or nil when none are configured.
+func (c Config) RobotsOverride() map[string]string {
+	if len(c.robotsOverride) == 0 {
+		return nil
+	}
+	overrides := make(map[string]string, len(c.robotsOverride))
+	for host, content := range c.robotsOverride {
+		overrides[host] = content
+	}
+	return overrides
+}
+
+// DefaultHeaders returns a copy of the extra headers sent with every page
+// fetch, or nil when none are configured.
+func (c Config) DefaultHeaders() map[string]string {
+	if len(c.defaultHeaders) == 0 {
+		return nil
+	}
+	headers := make(map[string]string, len(c.defaultHeaders))
+	for key, value := range c.defaultHeaders {
+		headers[key] = value
+	}
+	return headers
+}
+
+// HeaderRules returns the per-URL header rules in the order they apply.
+func (c Config) HeaderRules() []HeaderRule {
+	return append([]HeaderRule(nil), c.headerRules...)
+}
+
+// TLS returns a copy of the per-host TLS settings, keyed by lower-case host
+// name, or nil when none are set.
+func (c Config) TLS() map[string]HostTLS {
+	if len(c.hostTLS) == 0 {
+		return nil
+	}
+	hosts := make(map[string]HostTLS, len(c.hostTLS))
+	for host, settings := range c.hostTLS {
+		hosts[host] = settings
+	}
+	return hosts
+}
+
+// UserAgentFor returns the user agent to present to host, falling back to
+// UserAgent when the host has no override.
+func (c Config) UserAgentFor(host string) string {
+	if agent, ok := c.hostUserAgents[host]; ok && agent != "" {
+		return agent
+	}
+	return c.userAgent
+}
+
 func (c Config) MaxAssetSize() int64 {
 	return c.maxAssetSize
 }
 
+func (c Config) AssetNaming() string {
+	return c.assetNaming
+}
+
+// ImageMaxWidth returns the width image assets are downscaled to; 0 keeps
+// their size.
+func (c Config) ImageMaxWidth() int {
+	return c.imageMaxWidth
+}
+
+// ImageFormat returns the format image assets are re-encoded in, "png" or
+// "jpeg"; empty keeps their format.
+func (c Config) ImageFormat() string {
+	return c.imageFormat
+}
+
+func (c Config) PreflightHead() bool {
+	return c.preflightHead
+}
+
+func (c Config) AllowedContentTypes() []string {
+	return append([]string(nil), c.allowedContentTypes...)
+}
+
+func (c Config) MaxResponseBytes() int64 {
+	return c.maxResponseBytes
+}
+
+// MaxHTMLParseBytes returns the largest HTML body, in bytes, handed to the
+// extractor; 0 means unlimited.
+func (c Config) MaxHTMLParseBytes() int64 {
+	return c.maxHTMLParseBytes
+}
+
+func (c Config) TraceRequests() bool {
+	return c.traceRequests
+}
+
+func (c Config) ReplayArchive() string {
+	return c.replayArchive
+}
+
+func (c Config) RobotsWarmup() bool {
+	return c.robotsWarmup
+}
+
+func (c Config) FetchCacheDir() string {
+	return c.fetchCacheDir
+}
+
+// FetchCacheTTL returns how long cached responses are served; 0 means forever.
+func (c Config) FetchCacheTTL() time.Duration {
+	return c.fetchCacheTTL
+}
+
+// OfflineOnly reports whether pages are served from the fetch cache only.
+func (c Config) OfflineOnly() bool {
+	return c.offlineOnly
+}
+
 func (c Config) OutputDir() string {
 	return c.outputDir
 }
@@ -737,10 +2713,45 @@ func (c Config) DryRun() bool {
 	return c.dryRun
 }
 
+func (c Config) LinkCheckOnly() bool {
+	return c.linkCheckOnly
+}
+
 func (c Config) DumpStageOutput() string {
 	return c.dumpStageOutput
 }
 
+func (c Config) SingleFileOutput() string {
+	return c.singleFileOutput
+}
+
+func (c Config) SingleFileOnly() bool {
+	return c.singleFileOnly
+}
+
+func (c Config) PartitionByLanguage() bool {
+	return c.partitionByLanguage
+}
+
+func (c Config) RelativeLinks() bool {
+	return c.relativeLinks
+}
+
+func (c Config) SaveRawHTML() bool {
+	return c.saveRawHTML
+}
+
+// GraphFormat returns the format of the crawl graph file; empty writes none.
+func (c Config) GraphFormat() string {
+	return c.graphFormat
+}
+
+// OutputFormats returns the formats every page is written in, always
+// including OutputFormatMarkdown.
+func (c Config) OutputFormats() []string {
+	return append([]string(nil), c.outputFormats...)
+}
+
 func (c Config) MaxAttempt() int {
 	return c.maxAttempt
 }
@@ -801,10 +2812,104 @@ func (c Config) ThresholdMaxLinkDensity() float64 {
 	return c.thresholdMaxLinkDensity
 }
 
+func (c Config) MinTextRatio() float64 {
+	return c.minTextRatio
+}
+
+func (c Config) NoscriptMode() string {
+	return c.noscriptMode
+}
+
+func (c Config) StructuredExtraction() bool {
+	return c.structuredExtraction
+}
+
+func (c Config) StripByRole() bool {
+	return c.stripByRole
+}
+
+func (c Config) MultiArticleMode() string {
+	return c.multiArticleMode
+}
+
 func (c Config) HashAlgo() hashutil.HashAlgo {
 	return hashutil.HashAlgo(c.hashAlgo)
 }
 
+func (c Config) MarkdownFlavor() string {
+	return c.markdownFlavor
+}
+
+func (c Config) GenerateToC() bool {
+	return c.generateToC
+}
+
+func (c Config) DedupeHeadings() bool {
+	return c.dedupeHeadings
+}
+
+func (c Config) StripInvisibleInCode() bool {
+	return c.stripInvisibleInCode
+}
+
+func (c Config) ChunkSize() int {
+	return c.chunkSize
+}
+
+func (c Config) ChunkOverlap() int {
+	return c.chunkOverlap
+}
+
+// WriteProvenance reports whether each page records its referrer and
+// redirect chain.
+func (c Config) WriteProvenance() bool {
+	return c.writeProvenance
+}
+
+func (c Config) DuplicateContent() string {
+	return c.duplicateContent
+}
+
+func (c Config) ImageDensity() float64 {
+	return c.imageDensity
+}
+
+func (c Config) PreserveUnknownHTML() bool {
+	return c.preserveUnknownHTML
+}
+
+// AdmonitionTypes returns a copy of the configured admonition class to
+// alert type overrides, or nil when none are configured.
+func (c Config) AdmonitionTypes() map[string]string {
+	if len(c.admonitionTypes) == 0 {
+		return nil
+	}
+	types := make(map[string]string, len(c.admonitionTypes))
+	for class, alert := range c.admonitionTypes {
+		types[class] = alert
+	}
+	return types
+}
+
+// SpanStyles returns a copy of the configured span class to style
+// overrides, or nil when none are configured.
+func (c Config) SpanStyles() map[string]string {
+	if len(c.spanStyles) == 0 {
+		return nil
+	}
+	styles := make(map[string]string, len(c.spanStyles))
+	for class, style := range c.spanStyles {
+		styles[class] = style
+	}
+	return styles
+}
+
+// Warnings returns the deprecation warnings raised while loading the config
+// file, such as renamed fields migrated from an older schema version.
+func (c Config) Warnings() []string {
+	return append([]string(nil), c.warnings...)
+}
+
 func (c Config) MaxIdleConns() int {
 	return c.maxIdleConns
 }
@@ -817,6 +2922,38 @@ func (c Config) IdleConnTimeout() time.Duration {
 	return c.idleConnTimeout
 }
 
+func (c Config) HTTP2() bool {
+	return c.http2
+}
+
+func (c Config) CrawlWindows() []TimeWindow {
+	windows := make([]TimeWindow, len(c.crawlWindows))
+	copy(windows, c.crawlWindows)
+	return windows
+}
+
+// PageRetry returns the page retry settings, with its backoff growing by
+// BackoffMultiplier up to BackoffMaxDuration.
+func (c Config) PageRetry() PageRetry {
+	return c.pageRetry.withBackoffLimits(c.backoffMultiplier, c.backoffMaxDuration)
+}
+
+func (c Config) AutoTune() AutoTune {
+	return c.autoTune
+}
+
+// CircuitBreakerThreshold returns the number of consecutive same-stage page
+// failures that abort the crawl; 0 means disabled.
+func (c Config) CircuitBreakerThreshold() int {
+	return c.circuitBreakerThreshold
+}
+
+// StrictMode reports whether a page failing in the pipeline after the
+// fetch aborts the crawl.
+func (c Config) StrictMode() bool {
+	return c.strictMode
+}
+
 func (c Config) SelectorBlacklist() []string {
 	selectors := make([]string, len(c.selectorBlacklist))
 	copy(selectors, c.selectorBlacklist)
@@ -841,3 +2978,27 @@ func (c Config) DebugFormat() string {
 func (c Config) SuppressDefaultOutput() bool {
 	return c.debug && c.debugFile == ""
 }
+
+// validateSeedDepths checks that every seedDepths entry names a seed URL
+// and a depth the seed can be admitted at.
+func (c *Config) validateSeedDepths() error {
+	seeds := make(map[string]url.URL, len(c.seedURLs))
+	for _, u := range c.seedURLs {
+		seeds[u.String()] = u
+	}
+	for seed, depth := range c.seedDepths {
+		u, ok := seeds[seed]
+		if !ok {
+			return fmt.Errorf("%w: seedDepths lists %q, which is not a seed URL", ErrInvalidConfig, seed)
+		}
+		if depth < 0 {
+			return fmt.Errorf("%w: seedDepths for %q cannot be negative, got %d", ErrInvalidConfig, seed, depth)
+		}
+		// In path depth mode the seed's path decides its depth instead
+		maxDepth := c.MaxDepthFor(u.Host)
+		if c.depthMode != DepthModePathDepth && maxDepth != 0 && depth > maxDepth {
+			return fmt.Errorf("%w: seedDepths for %q exceeds maxDepth %d, got %d", ErrInvalidConfig, seed, maxDepth, depth)
+		}
+	}
+	return nil
+}
@@ -2,9 +2,13 @@ package config_test
 
 import (
 	"errors"
+	"fmt"
+	"math/rand"
 	"net/url"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -295,25 +299,832 @@ func TestWithAllowedPathPrefix(t *testing.T) {
 	}
 }
 
+func TestWithDefaultDocuments(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if len(cfg.DefaultDocuments()) != 0 {
+		t.Errorf("expected no default documents by default, got %v", cfg.DefaultDocuments())
+	}
+
+	cfg, err = config.WithDefault(baseURL).WithDefaultDocuments([]string{"index.html", "index.htm"}).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if !reflect.DeepEqual(cfg.DefaultDocuments(), []string{"index.html", "index.htm"}) {
+		t.Errorf("expected configured default documents, got %v", cfg.DefaultDocuments())
+	}
+}
+
 func TestWithMaxDepth(t *testing.T) {
 	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
-	cfg, err := config.WithDefault(baseURL).WithMaxDepth(5).Build()
+	cfg, err := config.WithDefault(baseURL).WithMaxDepth(5).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.MaxDepth() != 5 {
+		t.Errorf("expected MaxDepth 5, got %d", cfg.MaxDepth())
+	}
+}
+
+func TestWithHostMaxDepth(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).
+		WithMaxDepth(5).
+		WithHostMaxDepth(map[string]int{"thirdparty.org": 1}).
+		Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if got := cfg.MaxDepthFor("thirdparty.org"); got != 1 {
+		t.Errorf("expected override depth 1 for thirdparty.org, got %d", got)
+	}
+	if got := cfg.MaxDepthFor("base.org"); got != 5 {
+		t.Errorf("expected global depth 5 for base.org, got %d", got)
+	}
+
+	_, err = config.WithDefault(baseURL).WithHostMaxDepth(map[string]int{"thirdparty.org": -1}).Build()
+	if !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for a negative host depth, got %v", err)
+	}
+}
+
+func TestWithMaxOutputBytes(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.MaxOutputBytes() != 0 {
+		t.Errorf("expected default MaxOutputBytes 0 (unlimited), got %d", cfg.MaxOutputBytes())
+	}
+
+	cfg, err = config.WithDefault(baseURL).WithMaxOutputBytes(1 << 20).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.MaxOutputBytes() != 1<<20 {
+		t.Errorf("expected MaxOutputBytes %d, got %d", 1<<20, cfg.MaxOutputBytes())
+	}
+
+	_, err = config.WithDefault(baseURL).WithMaxOutputBytes(-1).Build()
+	if !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for a negative budget, got %v", err)
+	}
+}
+
+func TestWithCircuitBreakerThreshold(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.CircuitBreakerThreshold() != 0 {
+		t.Errorf("expected default CircuitBreakerThreshold 0 (disabled), got %d", cfg.CircuitBreakerThreshold())
+	}
+
+	cfg, err = config.WithDefault(baseURL).WithCircuitBreakerThreshold(5).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.CircuitBreakerThreshold() != 5 {
+		t.Errorf("expected CircuitBreakerThreshold 5, got %d", cfg.CircuitBreakerThreshold())
+	}
+
+	_, err = config.WithDefault(baseURL).WithCircuitBreakerThreshold(-1).Build()
+	if !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for a negative threshold, got %v", err)
+	}
+}
+
+func TestWithMaxPages(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).WithMaxPages(500).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.MaxPages() != 500 {
+		t.Errorf("expected MaxPages 500, got %d", cfg.MaxPages())
+	}
+}
+
+func TestWithMaxPagesPerDepth(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.MaxPagesPerDepth() != 0 {
+		t.Errorf("expected default MaxPagesPerDepth 0 (unlimited), got %d", cfg.MaxPagesPerDepth())
+	}
+
+	cfg, err = config.WithDefault(baseURL).WithMaxPagesPerDepth(25).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.MaxPagesPerDepth() != 25 {
+		t.Errorf("expected MaxPagesPerDepth 25, got %d", cfg.MaxPagesPerDepth())
+	}
+
+	_, err = config.WithDefault(baseURL).WithMaxPagesPerDepth(-1).Build()
+	if !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for negative MaxPagesPerDepth, got %v", err)
+	}
+}
+
+func TestWithPreflightHead(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.PreflightHead() {
+		t.Error("expected PreflightHead to be disabled by default")
+	}
+	if cfg.MaxResponseBytes() != 0 {
+		t.Errorf("expected default MaxResponseBytes 0 (unlimited), got %d", cfg.MaxResponseBytes())
+	}
+	wantTypes := []string{"text/html", "application/xhtml+xml"}
+	if !reflect.DeepEqual(cfg.AllowedContentTypes(), wantTypes) {
+		t.Errorf("expected default AllowedContentTypes %v, got %v", wantTypes, cfg.AllowedContentTypes())
+	}
+
+	cfg, err = config.WithDefault(baseURL).
+		WithPreflightHead(true).
+		WithAllowedContentTypes([]string{"text/html"}).
+		WithMaxResponseBytes(1 << 20).
+		Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if !cfg.PreflightHead() {
+		t.Error("expected PreflightHead to be enabled")
+	}
+	if !reflect.DeepEqual(cfg.AllowedContentTypes(), []string{"text/html"}) {
+		t.Errorf("expected AllowedContentTypes [text/html], got %v", cfg.AllowedContentTypes())
+	}
+	if cfg.MaxResponseBytes() != 1<<20 {
+		t.Errorf("expected MaxResponseBytes %d, got %d", 1<<20, cfg.MaxResponseBytes())
+	}
+}
+
+func TestWithTraceRequests(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.TraceRequests() {
+		t.Error("expected TraceRequests to be disabled by default")
+	}
+
+	cfg, err = config.WithDefault(baseURL).WithTraceRequests(true).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if !cfg.TraceRequests() {
+		t.Error("expected TraceRequests to be enabled")
+	}
+}
+
+func TestWithReplayArchive(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.ReplayArchive() != "" {
+		t.Errorf("expected no replay archive by default, got %q", cfg.ReplayArchive())
+	}
+
+	cfg, err = config.WithDefault(baseURL).WithReplayArchive("testdata/archive").Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.ReplayArchive() != "testdata/archive" {
+		t.Errorf("expected replay archive %q, got %q", "testdata/archive", cfg.ReplayArchive())
+	}
+}
+
+func TestWithRobotsWarmup(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.RobotsWarmup() {
+		t.Error("expected RobotsWarmup to default to false")
+	}
+
+	cfg, err = config.WithDefault(baseURL).WithRobotsWarmup(true).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if !cfg.RobotsWarmup() {
+		t.Error("expected RobotsWarmup to be enabled")
+	}
+}
+
+func TestWithFetchCache(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.FetchCacheDir() != "" || cfg.FetchCacheTTL() != 24*time.Hour {
+		t.Errorf("expected no fetch cache and a 24h TTL by default, got %q and %s", cfg.FetchCacheDir(), cfg.FetchCacheTTL())
+	}
+
+	cfg, err = config.WithDefault(baseURL).WithFetchCache(".cache", time.Hour).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.FetchCacheDir() != ".cache" || cfg.FetchCacheTTL() != time.Hour {
+		t.Errorf("expected fetch cache %q with a 1h TTL, got %q and %s", ".cache", cfg.FetchCacheDir(), cfg.FetchCacheTTL())
+	}
+
+	if _, err := config.WithDefault(baseURL).WithFetchCache(".cache", -time.Second).Build(); err == nil {
+		t.Error("expected an error for a negative fetchCacheTTL")
+	}
+}
+
+func TestWithFollowPagination(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.FollowPagination() {
+		t.Error("expected FollowPagination to be disabled by default")
+	}
+
+	cfg, err = config.WithDefault(baseURL).WithFollowPagination(true).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if !cfg.FollowPagination() {
+		t.Error("expected FollowPagination to be enabled")
+	}
+}
+
+func TestWithSinglePage(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.SinglePage() {
+		t.Error("expected SinglePage to be disabled by default")
+	}
+
+	cfg, err = config.WithDefault(baseURL).WithSinglePage(true).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if !cfg.SinglePage() {
+		t.Error("expected SinglePage to be enabled")
+	}
+}
+
+func TestWithNoscriptMode(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.NoscriptMode() != "drop" {
+		t.Errorf("expected default NoscriptMode drop, got %q", cfg.NoscriptMode())
+	}
+
+	cfg, err = config.WithDefault(baseURL).WithNoscriptMode("preferWhenEmpty").Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.NoscriptMode() != "preferWhenEmpty" {
+		t.Errorf("expected NoscriptMode preferWhenEmpty, got %q", cfg.NoscriptMode())
+	}
+}
+
+func TestWithHostUserAgents(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).
+		WithUserAgent("global/1.0").
+		WithHostUserAgents(map[string]string{"special.org": "special/2.0"}).
+		Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if got := cfg.UserAgentFor("special.org"); got != "special/2.0" {
+		t.Errorf("expected override user agent for special.org, got %q", got)
+	}
+	if got := cfg.UserAgentFor("base.org"); got != "global/1.0" {
+		t.Errorf("expected global user agent for base.org, got %q", got)
+	}
+}
+
+func TestWithRobotsOverride(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.RobotsOverride() != nil {
+		t.Errorf("expected no robots overrides by default, got %v", cfg.RobotsOverride())
+	}
+
+	cfg, err = config.WithDefault(baseURL).
+		WithRobotsOverride(map[string]string{"staging.base.org": "User-agent: *\nDisallow: /admin\n"}).
+		Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	overrides := cfg.RobotsOverride()
+	if got := overrides["staging.base.org"]; got != "User-agent: *\nDisallow: /admin\n" {
+		t.Errorf("expected robots override for staging.base.org, got %q", got)
+	}
+	overrides["staging.base.org"] = ""
+	if cfg.RobotsOverride()["staging.base.org"] == "" {
+		t.Error("expected RobotsOverride to return a copy")
+	}
+}
+
+func TestWithHeaderRules(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).
+		WithDefaultHeaders(map[string]string{"X-Team": "docs"}).
+		WithHeaderRules([]config.HeaderRule{
+			config.NewHeaderRule(`^https://base\.org/private/`, map[string]string{"Authorization": "Bearer t"}),
+		}).
+		Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if got := cfg.DefaultHeaders()["X-Team"]; got != "docs" {
+		t.Errorf("expected default header X-Team docs, got %q", got)
+	}
+	rules := cfg.HeaderRules()
+	if len(rules) != 1 || rules[0].Regexp() == nil {
+		t.Fatalf("expected one compiled header rule, got %+v", rules)
+	}
+	if !rules[0].Regexp().MatchString("https://base.org/private/page") {
+		t.Error("expected the rule to match a private page")
+	}
+	if rules[0].Headers()["Authorization"] != "Bearer t" {
+		t.Errorf("expected the rule's Authorization header, got %v", rules[0].Headers())
+	}
+
+	_, err = config.WithDefault(baseURL).
+		WithHeaderRules([]config.HeaderRule{config.NewHeaderRule(`(`, map[string]string{"X-Api-Key": "k"})}).
+		Build()
+	if !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for an invalid pattern, got %v", err)
+	}
+}
+
+func TestWithMarkdownFlavor(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.MarkdownFlavor() != "gfm" {
+		t.Errorf("expected default MarkdownFlavor gfm, got %q", cfg.MarkdownFlavor())
+	}
+
+	cfg, err = config.WithDefault(baseURL).WithMarkdownFlavor("commonmark").Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.MarkdownFlavor() != "commonmark" {
+		t.Errorf("expected MarkdownFlavor commonmark, got %q", cfg.MarkdownFlavor())
+	}
+
+	_, err = config.WithDefault(baseURL).WithMarkdownFlavor("markdown-extra").Build()
+	if !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for unknown MarkdownFlavor, got %v", err)
+	}
+}
+
+func TestWithGenerateToC(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.GenerateToC() {
+		t.Error("expected GenerateToC to default to false")
+	}
+
+	cfg, err = config.WithDefault(baseURL).WithGenerateToC(true).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if !cfg.GenerateToC() {
+		t.Error("expected GenerateToC true")
+	}
+}
+
+func TestWithDedupeHeadings(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.DedupeHeadings() {
+		t.Error("expected DedupeHeadings to default to false")
+	}
+
+	cfg, err = config.WithDefault(baseURL).WithDedupeHeadings(true).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if !cfg.DedupeHeadings() {
+		t.Error("expected DedupeHeadings true")
+	}
+}
+
+func TestWithSeedDepths(t *testing.T) {
+	seed := url.URL{Scheme: "https", Host: "base.org", Path: "/docs/guide"}
+	baseURL := []url.URL{seed}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.SeedDepths() != nil || cfg.SeedDepth(seed) != 0 {
+		t.Errorf("expected seeds to start at depth 0, got %v", cfg.SeedDepths())
+	}
+
+	cfg, err = config.WithDefault(baseURL).
+		WithMaxDepth(3).
+		WithSeedDepths(map[string]int{"https://base.org/docs/guide": 2}).
+		Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.SeedDepth(seed) != 2 {
+		t.Errorf("expected seed depth 2, got %d", cfg.SeedDepth(seed))
+	}
+
+	invalid := []map[string]int{
+		{"https://base.org/docs/guide": -1},
+		{"https://base.org/docs/guide": 4},
+		{"https://base.org/other": 1},
+	}
+	for _, depths := range invalid {
+		_, err = config.WithDefault(baseURL).WithMaxDepth(3).WithSeedDepths(depths).Build()
+		if !errors.Is(err, config.ErrInvalidConfig) {
+			t.Errorf("expected ErrInvalidConfig for %v, got %v", depths, err)
+		}
+	}
+}
+
+func TestWithHTTP2(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if !cfg.HTTP2() {
+		t.Error("expected HTTP2 to default to true")
+	}
+
+	cfg, err = config.WithDefault(baseURL).WithHTTP2(false).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.HTTP2() {
+		t.Error("expected HTTP2 false")
+	}
+}
+
+func TestWithStripInvisibleInCode(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.StripInvisibleInCode() {
+		t.Error("expected StripInvisibleInCode to default to false")
+	}
+
+	cfg, err = config.WithDefault(baseURL).WithStripInvisibleInCode(true).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if !cfg.StripInvisibleInCode() {
+		t.Error("expected StripInvisibleInCode true")
+	}
+}
+
+func TestWithChunking(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
 	if err != nil {
 		t.Errorf("should not have any error, got %d", err)
 	}
-	if cfg.MaxDepth() != 5 {
-		t.Errorf("expected MaxDepth 5, got %d", cfg.MaxDepth())
+	if cfg.ChunkSize() != 0 || cfg.ChunkOverlap() != 0 {
+		t.Errorf("expected chunking disabled by default, got size %d overlap %d", cfg.ChunkSize(), cfg.ChunkOverlap())
+	}
+
+	cfg, err = config.WithDefault(baseURL).WithChunking(2000, 200).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.ChunkSize() != 2000 || cfg.ChunkOverlap() != 200 {
+		t.Errorf("expected size 2000 overlap 200, got size %d overlap %d", cfg.ChunkSize(), cfg.ChunkOverlap())
+	}
+
+	if _, err := config.WithDefault(baseURL).WithChunking(200, 200).Build(); !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for overlap not below size, got %v", err)
+	}
+	if _, err := config.WithDefault(baseURL).WithChunking(-1, 0).Build(); !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for negative size, got %v", err)
 	}
 }
 
-func TestWithMaxPages(t *testing.T) {
+func TestWithDeterministicOrder(t *testing.T) {
 	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
-	cfg, err := config.WithDefault(baseURL).WithMaxPages(500).Build()
+	cfg, err := config.WithDefault(baseURL).Build()
 	if err != nil {
 		t.Errorf("should not have any error, got %d", err)
 	}
-	if cfg.MaxPages() != 500 {
-		t.Errorf("expected MaxPages 500, got %d", cfg.MaxPages())
+	if cfg.DeterministicOrder() {
+		t.Error("expected DeterministicOrder to default to false")
+	}
+
+	cfg, err = config.WithDefault(baseURL).WithDeterministicOrder(true).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if !cfg.DeterministicOrder() {
+		t.Error("expected DeterministicOrder true")
+	}
+}
+
+func TestWithStructuredExtraction(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.StructuredExtraction() {
+		t.Error("expected StructuredExtraction to default to false")
+	}
+
+	cfg, err = config.WithDefault(baseURL).WithStructuredExtraction(true).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if !cfg.StructuredExtraction() {
+		t.Error("expected StructuredExtraction true")
+	}
+}
+
+func TestWithStripByRole(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if !cfg.StripByRole() {
+		t.Error("expected StripByRole to default to true")
+	}
+
+	cfg, err = config.WithDefault(baseURL).WithStripByRole(false).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.StripByRole() {
+		t.Error("expected StripByRole false")
+	}
+}
+
+func TestWithDepthMode(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.DepthMode() != config.DepthModeLinkDistance {
+		t.Errorf("expected default DepthMode %q, got %q", config.DepthModeLinkDistance, cfg.DepthMode())
+	}
+
+	cfg, err = config.WithDefault(baseURL).WithDepthMode(config.DepthModePathDepth).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.DepthMode() != config.DepthModePathDepth {
+		t.Errorf("expected DepthMode %q, got %q", config.DepthModePathDepth, cfg.DepthMode())
+	}
+
+	_, err = config.WithDefault(baseURL).WithDepthMode("segments").Build()
+	if !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for unknown DepthMode, got %v", err)
+	}
+}
+
+func TestWithJitterDistribution(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.JitterDistribution() != config.JitterUniform {
+		t.Errorf("expected default JitterDistribution %q, got %q", config.JitterUniform, cfg.JitterDistribution())
+	}
+
+	for _, distribution := range []string{config.JitterExponential, config.JitterNormal} {
+		cfg, err = config.WithDefault(baseURL).WithJitterDistribution(distribution).Build()
+		if err != nil {
+			t.Errorf("should not have any error, got %d", err)
+		}
+		if cfg.JitterDistribution() != distribution {
+			t.Errorf("expected JitterDistribution %q, got %q", distribution, cfg.JitterDistribution())
+		}
+	}
+
+	_, err = config.WithDefault(baseURL).WithJitterDistribution("poisson").Build()
+	if !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for unknown JitterDistribution, got %v", err)
+	}
+}
+
+func TestWithDuplicateContent(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.DuplicateContent() != "write" {
+		t.Errorf("expected default DuplicateContent write, got %q", cfg.DuplicateContent())
+	}
+
+	cfg, err = config.WithDefault(baseURL).WithDuplicateContent("alias").Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.DuplicateContent() != "alias" {
+		t.Errorf("expected DuplicateContent alias, got %q", cfg.DuplicateContent())
+	}
+
+	_, err = config.WithDefault(baseURL).WithDuplicateContent("skip").Build()
+	if !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for unknown DuplicateContent, got %v", err)
+	}
+}
+
+func TestWithImageDensity(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.ImageDensity() != 0 {
+		t.Errorf("expected default ImageDensity 0, got %v", cfg.ImageDensity())
+	}
+
+	cfg, err = config.WithDefault(baseURL).WithImageDensity(1.5).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.ImageDensity() != 1.5 {
+		t.Errorf("expected ImageDensity 1.5, got %v", cfg.ImageDensity())
+	}
+}
+
+func TestWithPreserveUnknownHTML(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.PreserveUnknownHTML() {
+		t.Error("expected PreserveUnknownHTML to default to false")
+	}
+
+	cfg, err = config.WithDefault(baseURL).WithPreserveUnknownHTML(true).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if !cfg.PreserveUnknownHTML() {
+		t.Error("expected PreserveUnknownHTML true")
+	}
+}
+
+func TestWithAdmonitionTypes(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.AdmonitionTypes() != nil {
+		t.Errorf("expected no AdmonitionTypes by default, got %v", cfg.AdmonitionTypes())
+	}
+
+	cfg, err = config.WithDefault(baseURL).
+		WithAdmonitionTypes(map[string]string{"example": "tip", "danger": ""}).
+		Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if got := cfg.AdmonitionTypes()["example"]; got != "tip" {
+		t.Errorf("expected example mapped to tip, got %q", got)
+	}
+
+	_, err = config.WithDefault(baseURL).WithAdmonitionTypes(map[string]string{"example": "aside"}).Build()
+	if !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for an unknown alert type, got %v", err)
+	}
+}
+
+func TestWithSpanStyles(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %v", err)
+	}
+	if cfg.SpanStyles() != nil {
+		t.Errorf("expected no SpanStyles by default, got %v", cfg.SpanStyles())
+	}
+
+	cfg, err = config.WithDefault(baseURL).
+		WithSpanStyles(map[string]string{"hljs-keyword": "code", "key": "<kbd>", "kbd": ""}).
+		Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %v", err)
+	}
+	if got := cfg.SpanStyles()["key"]; got != "<kbd>" {
+		t.Errorf("expected key mapped to <kbd>, got %q", got)
+	}
+
+	_, err = config.WithDefault(baseURL).WithSpanStyles(map[string]string{"key": "bogus"}).Build()
+	if !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for an unknown span style, got %v", err)
+	}
+}
+
+func TestWithTLS(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %v", err)
+	}
+	if cfg.TLS() != nil {
+		t.Errorf("expected no TLS settings by default, got %v", cfg.TLS())
+	}
+
+	cfg, err = config.WithDefault(baseURL).
+		WithTLS(map[string]config.HostTLS{
+			"Docs.Internal": config.NewHostTLS("/etc/ssl/internal-ca.pem", false),
+			"wiki.internal": config.NewHostTLS("", true),
+		}).
+		Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %v", err)
+	}
+	if got := cfg.TLS()["docs.internal"].CAFile(); got != "/etc/ssl/internal-ca.pem" {
+		t.Errorf("expected the CA file under the lower-cased host, got %q", got)
+	}
+	if !cfg.TLS()["wiki.internal"].InsecureSkipVerify() {
+		t.Error("expected skip-verify for wiki.internal")
+	}
+
+	invalid := []map[string]config.HostTLS{
+		{"https://docs.internal": config.NewHostTLS("", true)},
+		{"docs.internal:8443": config.NewHostTLS("", true)},
+		{"docs.internal": config.NewHostTLS("", false)},
+	}
+	for _, hosts := range invalid {
+		_, err = config.WithDefault(baseURL).WithTLS(hosts).Build()
+		if !errors.Is(err, config.ErrInvalidConfig) {
+			t.Errorf("expected ErrInvalidConfig for %v, got %v", hosts, err)
+		}
+	}
+}
+
+func TestWithTrailingSlashPolicy(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %v", err)
+	}
+	if cfg.TrailingSlashPolicy() != "strip" {
+		t.Errorf("expected TrailingSlashPolicy strip by default, got %q", cfg.TrailingSlashPolicy())
+	}
+
+	cfg, err = config.WithDefault(baseURL).WithTrailingSlashPolicy("preserve").Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %v", err)
+	}
+	if cfg.TrailingSlashPolicy() != "preserve" {
+		t.Errorf("expected TrailingSlashPolicy preserve, got %q", cfg.TrailingSlashPolicy())
+	}
+
+	_, err = config.WithDefault(baseURL).WithTrailingSlashPolicy("keep").Build()
+	if !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for an unknown policy, got %v", err)
 	}
 }
 
@@ -454,8 +1265,117 @@ func TestWithDryRun(t *testing.T) {
 	if err != nil {
 		t.Errorf("should not have any error, got %d", err)
 	}
-	if cfg.DryRun() != true {
-		t.Errorf("expected DryRun true, got %v", cfg.DryRun())
+	if cfg.DryRun() != true {
+		t.Errorf("expected DryRun true, got %v", cfg.DryRun())
+	}
+}
+
+func TestWithLinkCheckOnly(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.LinkCheckOnly() {
+		t.Errorf("expected LinkCheckOnly false by default")
+	}
+
+	cfg, err = config.WithDefault(baseURL).WithLinkCheckOnly(true).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if !cfg.LinkCheckOnly() {
+		t.Errorf("expected LinkCheckOnly true, got %v", cfg.LinkCheckOnly())
+	}
+}
+
+func TestWithSingleFileOutput(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.SingleFileOutput() != "" || cfg.SingleFileOnly() {
+		t.Errorf("expected no combined file by default, got %q (only=%v)", cfg.SingleFileOutput(), cfg.SingleFileOnly())
+	}
+
+	cfg, err = config.WithDefault(baseURL).WithSingleFileOutput("all.md").WithSingleFileOnly(true).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.SingleFileOutput() != "all.md" || !cfg.SingleFileOnly() {
+		t.Errorf("expected combined-only output to all.md, got %q (only=%v)", cfg.SingleFileOutput(), cfg.SingleFileOnly())
+	}
+
+	_, err = config.WithDefault(baseURL).WithSingleFileOnly(true).Build()
+	if !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for singleFileOnly without a path, got %v", err)
+	}
+}
+
+func TestWithPartitionByLanguage(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.PartitionByLanguage() {
+		t.Error("expected no language partitioning by default")
+	}
+
+	cfg, err = config.WithDefault(baseURL).WithPartitionByLanguage(true).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if !cfg.PartitionByLanguage() {
+		t.Error("expected language partitioning to be enabled")
+	}
+}
+
+func TestWithRelativeLinks(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.RelativeLinks() {
+		t.Error("expected links to be left as written by default")
+	}
+
+	cfg, err = config.WithDefault(baseURL).WithRelativeLinks(true).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if !cfg.RelativeLinks() {
+		t.Error("expected relative links to be enabled")
+	}
+
+	_, err = config.WithDefault(baseURL).
+		WithRelativeLinks(true).
+		WithSingleFileOutput("all.md").
+		WithSingleFileOnly(true).
+		Build()
+	if !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig with singleFileOnly, got %v", err)
+	}
+}
+
+func TestWithSaveRawHTML(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.SaveRawHTML() {
+		t.Error("expected raw HTML not to be saved by default")
+	}
+
+	cfg, err = config.WithDefault(baseURL).WithSaveRawHTML(true).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if !cfg.SaveRawHTML() {
+		t.Error("expected raw HTML to be saved")
 	}
 }
 
@@ -499,6 +1419,115 @@ func TestWithConfigFile_FileDoesNotExist(t *testing.T) {
 	}
 }
 
+func TestWithConfigFile_PageRetry(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.json")
+
+	content := `{"seedUrls": ["https://base.org"], "pageRetry": {"maxAttempts": 3, "baseBackoff": "500ms"}}`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	cfg, err := config.WithConfigFile(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pageRetry := cfg.PageRetry()
+	if pageRetry.MaxAttempts() != 3 {
+		t.Errorf("expected MaxAttempts 3, got %d", pageRetry.MaxAttempts())
+	}
+	if pageRetry.Backoff(1) != 500*time.Millisecond || pageRetry.Backoff(2) != time.Second {
+		t.Errorf("expected backoffs 500ms and 1s, got %v and %v", pageRetry.Backoff(1), pageRetry.Backoff(2))
+	}
+
+	defaults, err := config.WithDefault([]url.URL{{Scheme: "https", Host: "base.org"}}).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if defaults.PageRetry().MaxAttempts() != 1 {
+		t.Errorf("expected page retries disabled by default, got MaxAttempts %d", defaults.PageRetry().MaxAttempts())
+	}
+}
+
+func TestPageRetry_BackoffUsesMultiplierAndCap(t *testing.T) {
+	cfg, err := config.WithDefault([]url.URL{{Scheme: "https", Host: "base.org"}}).
+		WithPageRetry(config.NewPageRetry(10, time.Second)).
+		WithBackoffMultiplier(3).
+		WithBackoffMaxDuration(5 * time.Second).
+		Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pageRetry := cfg.PageRetry()
+	expected := []time.Duration{time.Second, 3 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, want := range expected {
+		if got := pageRetry.Backoff(i + 1); got != want {
+			t.Errorf("Backoff(%d) = %v, want %v", i+1, got, want)
+		}
+	}
+	if got := pageRetry.Backoff(100); got != 5*time.Second {
+		t.Errorf("expected late attempts capped at 5s, got %v", got)
+	}
+}
+
+func TestWithConfigFile_AutoTune(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.json")
+
+	content := `{"seedUrls": ["https://base.org"], "autoTune": {"enabled": true, "maxConcurrency": 8, "minDelay": "100ms", "maxDelay": "5s"}}`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	cfg, err := config.WithConfigFile(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	autoTune := cfg.AutoTune()
+	if !autoTune.Enabled() {
+		t.Error("expected auto-tune enabled")
+	}
+	if autoTune.MinConcurrency() != 1 || autoTune.MaxConcurrency() != 8 {
+		t.Errorf("expected concurrency bounds 1-8, got %d-%d", autoTune.MinConcurrency(), autoTune.MaxConcurrency())
+	}
+	if autoTune.MinDelay() != 100*time.Millisecond || autoTune.MaxDelay() != 5*time.Second {
+		t.Errorf("expected delay bounds 100ms-5s, got %v-%v", autoTune.MinDelay(), autoTune.MaxDelay())
+	}
+
+	defaults, err := config.WithDefault([]url.URL{{Scheme: "https", Host: "base.org"}}).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if defaults.AutoTune().Enabled() {
+		t.Error("expected auto-tune disabled by default")
+	}
+}
+
+func TestWithAutoTune_InvalidBounds(t *testing.T) {
+	tests := []struct {
+		name     string
+		autoTune config.AutoTune
+	}{
+		{name: "zero min concurrency", autoTune: config.NewAutoTune(0, 4, 0, time.Second)},
+		{name: "max below min concurrency", autoTune: config.NewAutoTune(4, 2, 0, time.Second)},
+		{name: "negative min delay", autoTune: config.NewAutoTune(1, 4, -time.Second, time.Second)},
+		{name: "max below min delay", autoTune: config.NewAutoTune(1, 4, 2*time.Second, time.Second)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := config.WithDefault([]url.URL{{Scheme: "https", Host: "base.org"}}).
+				WithAutoTune(tt.autoTune).
+				Build()
+			if !errors.Is(err, config.ErrInvalidConfig) {
+				t.Errorf("expected ErrInvalidConfig, got %v", err)
+			}
+		})
+	}
+}
+
 func TestWithConfigFile_InvalidJSON(t *testing.T) {
 	tmpDir := t.TempDir()
 	configPath := filepath.Join(tmpDir, "invalid.json")
@@ -520,6 +1549,91 @@ func TestWithConfigFile_InvalidJSON(t *testing.T) {
 	}
 }
 
+func TestWithConfigFile_MigratesVersion1RenamedField(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.json")
+	content := `{"version": 1, "seedUrls": ["https://base.org"], "maxAttempt": 7}`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	cfg, err := config.WithConfigFile(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.MaxAttempt() != 7 {
+		t.Errorf("expected maxAttempt migrated to MaxAttempt 7, got %d", cfg.MaxAttempt())
+	}
+	warnings := cfg.Warnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0], `"maxAttempt"`) || !strings.Contains(warnings[0], `"maxAttempts"`) {
+		t.Errorf("expected one deprecation warning naming maxAttempt and maxAttempts, got %v", warnings)
+	}
+}
+
+func TestWithConfigFile_CurrentVersionHasNoWarnings(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.json")
+	content := fmt.Sprintf(`{"version": %d, "seedUrls": ["https://base.org"], "maxAttempts": 4}`, config.CurrentConfigVersion)
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	cfg, err := config.WithConfigFile(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.MaxAttempt() != 4 {
+		t.Errorf("expected MaxAttempt 4, got %d", cfg.MaxAttempt())
+	}
+	if len(cfg.Warnings()) != 0 {
+		t.Errorf("expected no warnings, got %v", cfg.Warnings())
+	}
+}
+
+func TestWithConfigFile_UnsupportedVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr error
+	}{
+		{
+			name:    "newer than supported",
+			content: fmt.Sprintf(`{"version": %d, "seedUrls": ["https://base.org"]}`, config.CurrentConfigVersion+1),
+			wantErr: config.ErrUnsupportedConfigVersion,
+		},
+		{
+			name:    "zero",
+			content: `{"version": 0, "seedUrls": ["https://base.org"]}`,
+			wantErr: config.ErrUnsupportedConfigVersion,
+		},
+		{
+			name:    "not an integer",
+			content: `{"version": "two", "seedUrls": ["https://base.org"]}`,
+			wantErr: config.ErrConfigParsingFail,
+		},
+		{
+			name:    "old and renamed field both set",
+			content: `{"version": 1, "seedUrls": ["https://base.org"], "maxAttempt": 3, "maxAttempts": 5}`,
+			wantErr: config.ErrInvalidConfig,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.json")
+			if err := os.WriteFile(configPath, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("failed to write config file: %v", err)
+			}
+
+			_, err := config.WithConfigFile(configPath)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
 func TestWithConfigFile_ValidCompleteConfig(t *testing.T) {
 	tmpDir := t.TempDir()
 	configPath := filepath.Join(tmpDir, "config.json")
@@ -848,104 +1962,334 @@ func TestWithScoreMultiplierNonWhitespaceDivisor(t *testing.T) {
 	if err != nil {
 		t.Errorf("should not have any error, got %d", err)
 	}
-	if cfg.ScoreMultiplierNonWhitespaceDivisor() != testDivisor {
-		t.Errorf("expected ScoreMultiplierNonWhitespaceDivisor %f, got %f", testDivisor, cfg.ScoreMultiplierNonWhitespaceDivisor())
+	if cfg.ScoreMultiplierNonWhitespaceDivisor() != testDivisor {
+		t.Errorf("expected ScoreMultiplierNonWhitespaceDivisor %f, got %f", testDivisor, cfg.ScoreMultiplierNonWhitespaceDivisor())
+	}
+}
+
+func TestWithScoreMultiplierParagraphs(t *testing.T) {
+	testMultiplier := 8.0
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).WithScoreMultiplierParagraphs(testMultiplier).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.ScoreMultiplierParagraphs() != testMultiplier {
+		t.Errorf("expected ScoreMultiplierParagraphs %f, got %f", testMultiplier, cfg.ScoreMultiplierParagraphs())
+	}
+}
+
+func TestWithScoreMultiplierHeadings(t *testing.T) {
+	testMultiplier := 15.0
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).WithScoreMultiplierHeadings(testMultiplier).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.ScoreMultiplierHeadings() != testMultiplier {
+		t.Errorf("expected ScoreMultiplierHeadings %f, got %f", testMultiplier, cfg.ScoreMultiplierHeadings())
+	}
+}
+
+func TestWithScoreMultiplierCodeBlocks(t *testing.T) {
+	testMultiplier := 20.0
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).WithScoreMultiplierCodeBlocks(testMultiplier).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.ScoreMultiplierCodeBlocks() != testMultiplier {
+		t.Errorf("expected ScoreMultiplierCodeBlocks %f, got %f", testMultiplier, cfg.ScoreMultiplierCodeBlocks())
+	}
+}
+
+func TestWithScoreMultiplierListItems(t *testing.T) {
+	testMultiplier := 4.0
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).WithScoreMultiplierListItems(testMultiplier).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.ScoreMultiplierListItems() != testMultiplier {
+		t.Errorf("expected ScoreMultiplierListItems %f, got %f", testMultiplier, cfg.ScoreMultiplierListItems())
+	}
+}
+
+func TestWithThresholdMinNonWhitespace(t *testing.T) {
+	testMin := 100
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).WithThresholdMinNonWhitespace(testMin).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.ThresholdMinNonWhitespace() != testMin {
+		t.Errorf("expected ThresholdMinNonWhitespace %d, got %d", testMin, cfg.ThresholdMinNonWhitespace())
+	}
+}
+
+func TestWithThresholdMinHeadings(t *testing.T) {
+	testMin := 2
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).WithThresholdMinHeadings(testMin).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.ThresholdMinHeadings() != testMin {
+		t.Errorf("expected ThresholdMinHeadings %d, got %d", testMin, cfg.ThresholdMinHeadings())
+	}
+}
+
+func TestWithThresholdMinParagraphsOrCode(t *testing.T) {
+	testMin := 3
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).WithThresholdMinParagraphsOrCode(testMin).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.ThresholdMinParagraphsOrCode() != testMin {
+		t.Errorf("expected ThresholdMinParagraphsOrCode %d, got %d", testMin, cfg.ThresholdMinParagraphsOrCode())
+	}
+}
+
+func TestWithThresholdMaxLinkDensity(t *testing.T) {
+	testMax := 0.95
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).WithThresholdMaxLinkDensity(testMax).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.ThresholdMaxLinkDensity() != testMax {
+		t.Errorf("expected ThresholdMaxLinkDensity %f, got %f", testMax, cfg.ThresholdMaxLinkDensity())
+	}
+}
+
+func TestWithCrawlBudgets(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).WithMaxDuration(30 * time.Minute).WithMaxErrors(10).Build()
+	if err != nil {
+		t.Fatalf("should not have any error, got %v", err)
+	}
+	if cfg.MaxDuration() != 30*time.Minute || cfg.MaxErrors() != 10 {
+		t.Errorf("expected MaxDuration 30m and MaxErrors 10, got %s and %d", cfg.MaxDuration(), cfg.MaxErrors())
+	}
+
+	if _, err := config.WithDefault(baseURL).WithMaxDuration(-time.Second).Build(); !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for a negative maxDuration, got %v", err)
+	}
+	if _, err := config.WithDefault(baseURL).WithMaxErrors(-1).Build(); !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for negative maxErrors, got %v", err)
+	}
+}
+
+func TestWithStopAfterFirstMatch(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).WithStopAfterFirstMatch(true).WithMatchPattern(`(?i)flux`).Build()
+	if err != nil {
+		t.Fatalf("should not have any error, got %v", err)
+	}
+	if !cfg.StopAfterFirstMatch() || cfg.MatchPattern() != `(?i)flux` {
+		t.Errorf("expected StopAfterFirstMatch with pattern (?i)flux, got %v and %q", cfg.StopAfterFirstMatch(), cfg.MatchPattern())
+	}
+	if !cfg.MatchesPage("https://base.org/docs", []byte("The Flux capacitor")) {
+		t.Error("expected the content to match")
+	}
+	if !cfg.MatchesPage("https://base.org/flux", []byte("nothing here")) {
+		t.Error("expected the URL to match")
+	}
+	if cfg.MatchesPage("https://base.org/docs", []byte("nothing here")) {
+		t.Error("expected no match")
+	}
+
+	defaults, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Fatalf("should not have any error, got %v", err)
+	}
+	if defaults.StopAfterFirstMatch() || defaults.MatchesPage("https://base.org/docs", []byte("anything")) {
+		t.Error("expected no page matching by default")
+	}
+
+	if _, err := config.WithDefault(baseURL).WithStopAfterFirstMatch(true).Build(); !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for stopAfterFirstMatch without a matchPattern, got %v", err)
+	}
+	if _, err := config.WithDefault(baseURL).WithMatchPattern(`(unclosed`).Build(); !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for an invalid matchPattern, got %v", err)
+	}
+}
+
+func TestWithMaxPatternRepeats(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Fatalf("should not have any error, got %v", err)
+	}
+	if cfg.MaxPatternRepeats() != 0 {
+		t.Errorf("expected MaxPatternRepeats to default to 0, got %d", cfg.MaxPatternRepeats())
+	}
+
+	cfg, err = config.WithDefault(baseURL).WithMaxPatternRepeats(10).Build()
+	if err != nil {
+		t.Fatalf("should not have any error, got %v", err)
+	}
+	if cfg.MaxPatternRepeats() != 10 {
+		t.Errorf("expected MaxPatternRepeats 10, got %d", cfg.MaxPatternRepeats())
+	}
+
+	if _, err := config.WithDefault(baseURL).WithMaxPatternRepeats(-1).Build(); !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for negative maxPatternRepeats, got %v", err)
+	}
+}
+
+func TestWithRepetition(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	defaultCfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Fatalf("should not have any error, got %v", err)
+	}
+	if defaultCfg.MaxIterations() != 1 || defaultCfg.RepeatInterval() != 0 {
+		t.Errorf("expected a single pass by default, got MaxIterations %d and RepeatInterval %s", defaultCfg.MaxIterations(), defaultCfg.RepeatInterval())
+	}
+
+	cfg, err := config.WithDefault(baseURL).WithMaxIterations(3).WithRepeatInterval(time.Hour).Build()
+	if err != nil {
+		t.Fatalf("should not have any error, got %v", err)
+	}
+	if cfg.MaxIterations() != 3 || cfg.RepeatInterval() != time.Hour {
+		t.Errorf("expected MaxIterations 3 and RepeatInterval 1h, got %d and %s", cfg.MaxIterations(), cfg.RepeatInterval())
+	}
+	if _, err := config.WithDefault(baseURL).WithMaxIterations(0).WithRepeatInterval(time.Hour).Build(); err != nil {
+		t.Errorf("expected unlimited iterations with an interval to be valid, got %v", err)
+	}
+
+	if _, err := config.WithDefault(baseURL).WithMaxIterations(-1).Build(); !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for negative maxIterations, got %v", err)
+	}
+	if _, err := config.WithDefault(baseURL).WithRepeatInterval(-time.Second).Build(); !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for a negative repeatInterval, got %v", err)
+	}
+	if _, err := config.WithDefault(baseURL).WithMaxIterations(0).Build(); !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for unlimited iterations without an interval, got %v", err)
 	}
 }
 
-func TestWithScoreMultiplierParagraphs(t *testing.T) {
-	testMultiplier := 8.0
+func TestWithAssetNaming(t *testing.T) {
 	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
-	cfg, err := config.WithDefault(baseURL).WithScoreMultiplierParagraphs(testMultiplier).Build()
+	defaultCfg, err := config.WithDefault(baseURL).Build()
 	if err != nil {
-		t.Errorf("should not have any error, got %d", err)
+		t.Fatalf("should not have any error, got %v", err)
 	}
-	if cfg.ScoreMultiplierParagraphs() != testMultiplier {
-		t.Errorf("expected ScoreMultiplierParagraphs %f, got %f", testMultiplier, cfg.ScoreMultiplierParagraphs())
+	if defaultCfg.AssetNaming() != "nameHash" {
+		t.Errorf("expected default AssetNaming nameHash, got %q", defaultCfg.AssetNaming())
 	}
-}
 
-func TestWithScoreMultiplierHeadings(t *testing.T) {
-	testMultiplier := 15.0
-	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
-	cfg, err := config.WithDefault(baseURL).WithScoreMultiplierHeadings(testMultiplier).Build()
+	cfg, err := config.WithDefault(baseURL).WithAssetNaming("mirror").Build()
 	if err != nil {
-		t.Errorf("should not have any error, got %d", err)
+		t.Errorf("should not have any error, got %v", err)
 	}
-	if cfg.ScoreMultiplierHeadings() != testMultiplier {
-		t.Errorf("expected ScoreMultiplierHeadings %f, got %f", testMultiplier, cfg.ScoreMultiplierHeadings())
+	if cfg.AssetNaming() != "mirror" {
+		t.Errorf("expected AssetNaming mirror, got %q", cfg.AssetNaming())
+	}
+
+	_, err = config.WithDefault(baseURL).WithAssetNaming("random").Build()
+	if !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for an unknown scheme, got %v", err)
 	}
 }
 
-func TestWithScoreMultiplierCodeBlocks(t *testing.T) {
-	testMultiplier := 20.0
+func TestWithGraphFormat(t *testing.T) {
 	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
-	cfg, err := config.WithDefault(baseURL).WithScoreMultiplierCodeBlocks(testMultiplier).Build()
+	cfg, err := config.WithDefault(baseURL).WithGraphFormat(config.GraphFormatDOT).Build()
 	if err != nil {
-		t.Errorf("should not have any error, got %d", err)
+		t.Errorf("should not have any error, got %v", err)
 	}
-	if cfg.ScoreMultiplierCodeBlocks() != testMultiplier {
-		t.Errorf("expected ScoreMultiplierCodeBlocks %f, got %f", testMultiplier, cfg.ScoreMultiplierCodeBlocks())
+	if cfg.GraphFormat() != config.GraphFormatDOT {
+		t.Errorf("expected GraphFormat dot, got %q", cfg.GraphFormat())
+	}
+
+	_, err = config.WithDefault(baseURL).WithGraphFormat("svg").Build()
+	if !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for an unknown format, got %v", err)
 	}
 }
 
-func TestWithScoreMultiplierListItems(t *testing.T) {
-	testMultiplier := 4.0
+func TestWithOutputFormats(t *testing.T) {
 	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
-	cfg, err := config.WithDefault(baseURL).WithScoreMultiplierListItems(testMultiplier).Build()
+	cfg, err := config.WithDefault(baseURL).Build()
 	if err != nil {
-		t.Errorf("should not have any error, got %d", err)
+		t.Errorf("should not have any error, got %v", err)
 	}
-	if cfg.ScoreMultiplierListItems() != testMultiplier {
-		t.Errorf("expected ScoreMultiplierListItems %f, got %f", testMultiplier, cfg.ScoreMultiplierListItems())
+	if got := cfg.OutputFormats(); len(got) != 1 || got[0] != config.OutputFormatMarkdown {
+		t.Errorf("expected OutputFormats [md] by default, got %v", got)
 	}
-}
 
-func TestWithThresholdMinNonWhitespace(t *testing.T) {
-	testMin := 100
-	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
-	cfg, err := config.WithDefault(baseURL).WithThresholdMinNonWhitespace(testMin).Build()
+	cfg, err = config.WithDefault(baseURL).
+		WithOutputFormats([]string{config.OutputFormatMarkdown, config.OutputFormatHTML, config.OutputFormatText}).
+		Build()
 	if err != nil {
-		t.Errorf("should not have any error, got %d", err)
+		t.Errorf("should not have any error, got %v", err)
 	}
-	if cfg.ThresholdMinNonWhitespace() != testMin {
-		t.Errorf("expected ThresholdMinNonWhitespace %d, got %d", testMin, cfg.ThresholdMinNonWhitespace())
+	if got := cfg.OutputFormats(); len(got) != 3 {
+		t.Errorf("expected three OutputFormats, got %v", got)
 	}
-}
 
-func TestWithThresholdMinHeadings(t *testing.T) {
-	testMin := 2
-	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
-	cfg, err := config.WithDefault(baseURL).WithThresholdMinHeadings(testMin).Build()
-	if err != nil {
-		t.Errorf("should not have any error, got %d", err)
+	invalid := [][]string{
+		{config.OutputFormatText},
+		{config.OutputFormatMarkdown, "pdf"},
+		{config.OutputFormatMarkdown, config.OutputFormatText, config.OutputFormatText},
 	}
-	if cfg.ThresholdMinHeadings() != testMin {
-		t.Errorf("expected ThresholdMinHeadings %d, got %d", testMin, cfg.ThresholdMinHeadings())
+	for _, formats := range invalid {
+		_, err = config.WithDefault(baseURL).WithOutputFormats(formats).Build()
+		if !errors.Is(err, config.ErrInvalidConfig) {
+			t.Errorf("expected ErrInvalidConfig for %v, got %v", formats, err)
+		}
+	}
+
+	_, err = config.WithDefault(baseURL).
+		WithSingleFileOutput("all.md").
+		WithSingleFileOnly(true).
+		WithOutputFormats([]string{config.OutputFormatMarkdown, config.OutputFormatText}).
+		Build()
+	if !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig with singleFileOnly, got %v", err)
 	}
 }
 
-func TestWithThresholdMinParagraphsOrCode(t *testing.T) {
-	testMin := 3
+func TestWithImageTranscoding(t *testing.T) {
 	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
-	cfg, err := config.WithDefault(baseURL).WithThresholdMinParagraphsOrCode(testMin).Build()
+	cfg, err := config.WithDefault(baseURL).WithImageMaxWidth(800).WithImageFormat("jpeg").Build()
 	if err != nil {
-		t.Errorf("should not have any error, got %d", err)
+		t.Errorf("should not have any error, got %v", err)
 	}
-	if cfg.ThresholdMinParagraphsOrCode() != testMin {
-		t.Errorf("expected ThresholdMinParagraphsOrCode %d, got %d", testMin, cfg.ThresholdMinParagraphsOrCode())
+	if cfg.ImageMaxWidth() != 800 || cfg.ImageFormat() != "jpeg" {
+		t.Errorf("expected ImageMaxWidth 800 and ImageFormat jpeg, got %d and %q", cfg.ImageMaxWidth(), cfg.ImageFormat())
+	}
+
+	_, err = config.WithDefault(baseURL).WithImageFormat("webp").Build()
+	if !errors.Is(err, config.ErrInvalidConfig) || !strings.Contains(err.Error(), "webp") {
+		t.Errorf("expected ErrInvalidConfig naming webp as unsupported, got %v", err)
+	}
+	_, err = config.WithDefault(baseURL).WithImageFormat("gif").Build()
+	if !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for an unsupported format, got %v", err)
+	}
+	_, err = config.WithDefault(baseURL).WithImageMaxWidth(-1).Build()
+	if !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for a negative width, got %v", err)
 	}
 }
 
-func TestWithThresholdMaxLinkDensity(t *testing.T) {
-	testMax := 0.95
+func TestWithMinTextRatio(t *testing.T) {
 	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
-	cfg, err := config.WithDefault(baseURL).WithThresholdMaxLinkDensity(testMax).Build()
+	cfg, err := config.WithDefault(baseURL).WithMinTextRatio(0.3).Build()
 	if err != nil {
-		t.Errorf("should not have any error, got %d", err)
+		t.Errorf("should not have any error, got %v", err)
 	}
-	if cfg.ThresholdMaxLinkDensity() != testMax {
-		t.Errorf("expected ThresholdMaxLinkDensity %f, got %f", testMax, cfg.ThresholdMaxLinkDensity())
+	if cfg.MinTextRatio() != 0.3 {
+		t.Errorf("expected MinTextRatio 0.3, got %f", cfg.MinTextRatio())
+	}
+
+	_, err = config.WithDefault(baseURL).WithMinTextRatio(1.5).Build()
+	if !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for a ratio above 1, got %v", err)
 	}
 }
 
@@ -1561,3 +2905,310 @@ func TestSuppressDefaultOutput(t *testing.T) {
 		})
 	}
 }
+
+func TestSubSeed_SameSeedSameName_Identical(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+
+	names := []string{
+		config.SubSeedRateLimiterJitter,
+		config.SubSeedFetcherRetry,
+		"custom.component",
+	}
+
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			first, err := config.WithDefault(baseURL).WithRandomSeed(42).Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			second, err := config.WithDefault(baseURL).WithRandomSeed(42).Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if first.SubSeed(name) != second.SubSeed(name) {
+				t.Errorf("expected identical sub-seeds for %q, got %d and %d", name, first.SubSeed(name), second.SubSeed(name))
+			}
+		})
+	}
+}
+
+func TestSubSeed_DifferentNames_DifferentStreams(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).WithRandomSeed(42).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	limiterSeed := cfg.SubSeed(config.SubSeedRateLimiterJitter)
+	retrySeed := cfg.SubSeed(config.SubSeedFetcherRetry)
+	if limiterSeed == retrySeed {
+		t.Errorf("expected different sub-seeds for different names, both got %d", limiterSeed)
+	}
+
+	// The derived streams must diverge, not just their first value
+	limiterRand := rand.New(rand.NewSource(limiterSeed))
+	retryRand := rand.New(rand.NewSource(retrySeed))
+	identical := true
+	for i := 0; i < 8; i++ {
+		if limiterRand.Int63() != retryRand.Int63() {
+			identical = false
+		}
+	}
+	if identical {
+		t.Error("expected different random streams for different component names")
+	}
+}
+
+func TestSubSeed_DifferentRandomSeed_DifferentSubSeed(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	first, _ := config.WithDefault(baseURL).WithRandomSeed(1).Build()
+	second, _ := config.WithDefault(baseURL).WithRandomSeed(2).Build()
+
+	if first.SubSeed(config.SubSeedFetcherRetry) == second.SubSeed(config.SubSeedFetcherRetry) {
+		t.Error("expected different sub-seeds for different random seeds")
+	}
+}
+
+func TestWithStrictMode(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.StrictMode() {
+		t.Error("expected StrictMode to default to false")
+	}
+
+	cfg, err = config.WithDefault(baseURL).WithStrictMode(true).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if !cfg.StrictMode() {
+		t.Error("expected StrictMode true")
+	}
+}
+
+func TestWithOfflineOnly(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.OfflineOnly() {
+		t.Error("expected OfflineOnly to default to false")
+	}
+
+	cfg, err = config.WithDefault(baseURL).WithFetchCache("cache", 0).WithOfflineOnly(true).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if !cfg.OfflineOnly() {
+		t.Error("expected OfflineOnly true")
+	}
+
+	_, err = config.WithDefault(baseURL).WithOfflineOnly(true).Build()
+	if !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("expected offlineOnly without a fetch cache to be invalid, got %v", err)
+	}
+}
+
+func TestWithMultiArticleMode(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.MultiArticleMode() != "largest" {
+		t.Errorf("expected MultiArticleMode to default to largest, got %q", cfg.MultiArticleMode())
+	}
+
+	cfg, err = config.WithDefault(baseURL).WithMultiArticleMode("separateOutputs").Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.MultiArticleMode() != "separateOutputs" {
+		t.Errorf("expected MultiArticleMode separateOutputs, got %q", cfg.MultiArticleMode())
+	}
+
+	_, err = config.WithDefault(baseURL).WithMultiArticleMode("first").Build()
+	if !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("expected an unknown multiArticleMode to be invalid, got %v", err)
+	}
+}
+
+func TestWithWriteProvenance(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.WriteProvenance() {
+		t.Error("expected WriteProvenance to default to false")
+	}
+
+	cfg, err = config.WithDefault(baseURL).WithWriteProvenance(true).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if !cfg.WriteProvenance() {
+		t.Error("expected WriteProvenance true")
+	}
+}
+
+func TestWithMaxHTMLParseBytes(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.MaxHTMLParseBytes() != 0 {
+		t.Errorf("expected default MaxHTMLParseBytes 0 (unlimited), got %d", cfg.MaxHTMLParseBytes())
+	}
+
+	cfg, err = config.WithDefault(baseURL).WithMaxHTMLParseBytes(1 << 20).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.MaxHTMLParseBytes() != 1<<20 {
+		t.Errorf("expected MaxHTMLParseBytes %d, got %d", 1<<20, cfg.MaxHTMLParseBytes())
+	}
+
+	_, err = config.WithDefault(baseURL).WithMaxHTMLParseBytes(-1).Build()
+	if !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for a negative MaxHTMLParseBytes, got %v", err)
+	}
+}
+
+func TestWithDefaultCrawlDelay(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.DefaultCrawlDelay() != 0 {
+		t.Errorf("expected default DefaultCrawlDelay 0, got %s", cfg.DefaultCrawlDelay())
+	}
+
+	cfg, err = config.WithDefault(baseURL).WithDefaultCrawlDelay(2 * time.Second).Build()
+	if err != nil {
+		t.Errorf("should not have any error, got %d", err)
+	}
+	if cfg.DefaultCrawlDelay() != 2*time.Second {
+		t.Errorf("expected DefaultCrawlDelay 2s, got %s", cfg.DefaultCrawlDelay())
+	}
+
+	_, err = config.WithDefault(baseURL).WithDefaultCrawlDelay(-time.Second).Build()
+	if !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for a negative DefaultCrawlDelay, got %v", err)
+	}
+}
+
+func TestWithDepthDelayFactor(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Fatalf("should not have any error, got %v", err)
+	}
+	if cfg.DepthDelayFactor() != 0 || cfg.MaxDepthDelay() != 30*time.Second {
+		t.Errorf("expected default DepthDelayFactor 0 and MaxDepthDelay 30s, got %g and %s", cfg.DepthDelayFactor(), cfg.MaxDepthDelay())
+	}
+
+	cfg, err = config.WithDefault(baseURL).WithDepthDelayFactor(0.5).WithMaxDepthDelay(10 * time.Second).Build()
+	if err != nil {
+		t.Fatalf("should not have any error, got %v", err)
+	}
+	if cfg.DepthDelayFactor() != 0.5 || cfg.MaxDepthDelay() != 10*time.Second {
+		t.Errorf("expected DepthDelayFactor 0.5 and MaxDepthDelay 10s, got %g and %s", cfg.DepthDelayFactor(), cfg.MaxDepthDelay())
+	}
+
+	if _, err := config.WithDefault(baseURL).WithDepthDelayFactor(-0.1).Build(); !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for a negative depthDelayFactor, got %v", err)
+	}
+	if _, err := config.WithDefault(baseURL).WithMaxDepthDelay(-time.Second).Build(); !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for a negative maxDepthDelay, got %v", err)
+	}
+}
+
+func TestEncodeSnapshot_RedactsHeaderValues(t *testing.T) {
+	seeds := []url.URL{{Scheme: "https", Host: "docs.example.com"}}
+	cfg, err := config.WithDefault(seeds).
+		WithDefaultHeaders(map[string]string{"Authorization": "Bearer s3cr3t-token"}).
+		WithHeaderRules([]config.HeaderRule{
+			config.NewHeaderRule(`^https://docs\.example\.com/api/`, map[string]string{"X-Api-Key": "k3y-value"}),
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("should not have any error, got %v", err)
+	}
+
+	snapshot, err := config.EncodeSnapshot(cfg, "")
+	if err != nil {
+		t.Fatalf("should not have any error, got %v", err)
+	}
+	for _, secret := range []string{"s3cr3t-token", "k3y-value"} {
+		if strings.Contains(string(snapshot), secret) {
+			t.Errorf("expected the snapshot not to contain %q, got %s", secret, snapshot)
+		}
+	}
+	if !strings.Contains(string(snapshot), `"Authorization": "<redacted>"`) {
+		t.Errorf("expected the header name to be kept with a redacted value, got %s", snapshot)
+	}
+	// The config itself keeps its values
+	if cfg.DefaultHeaders()["Authorization"] != "Bearer s3cr3t-token" {
+		t.Errorf("expected redaction to leave the config untouched, got %v", cfg.DefaultHeaders())
+	}
+}
+
+func TestEncodeSnapshot_RoundTrips(t *testing.T) {
+	seeds := []url.URL{{Scheme: "https", Host: "docs.example.com", Path: "/guide"}}
+	original, err := config.WithDefault(seeds).
+		WithMaxDepth(4).
+		WithMaxPages(50).
+		WithBaseDelay(750 * time.Millisecond).
+		WithRandomSeed(42).
+		WithUserAgent("snapshot-test/1.0").
+		WithOutputDir("site-docs").
+		WithStopAfterFirstMatch(true).
+		WithMatchPattern(`(?i)install`).
+		Build()
+	if err != nil {
+		t.Fatalf("should not have any error, got %v", err)
+	}
+
+	snapshot, err := config.EncodeSnapshot(original, "0123456789abcdef")
+	if err != nil {
+		t.Fatalf("should not have any error, got %v", err)
+	}
+	if !strings.Contains(string(snapshot), `"runId": "0123456789abcdef"`) {
+		t.Errorf("expected the snapshot to record the run ID, got %s", snapshot)
+	}
+
+	path := filepath.Join(t.TempDir(), "effective-config.json")
+	if err := os.WriteFile(path, snapshot, 0644); err != nil {
+		t.Fatalf("failed to write snapshot: %v", err)
+	}
+	restored, err := config.WithConfigFile(path)
+	if err != nil {
+		t.Fatalf("expected the snapshot to load as a config file, got %v", err)
+	}
+	if len(restored.Warnings()) != 0 {
+		t.Errorf("expected no migration warnings, got %v", restored.Warnings())
+	}
+
+	// Equivalent configs encode to the same snapshot
+	resnapshot, err := config.EncodeSnapshot(restored, "0123456789abcdef")
+	if err != nil {
+		t.Fatalf("should not have any error, got %v", err)
+	}
+	if string(resnapshot) != string(snapshot) {
+		t.Errorf("expected the restored config to encode identically\noriginal: %s\nrestored: %s", snapshot, resnapshot)
+	}
+	if restored.MaxDepth() != 4 || restored.BaseDelay() != 750*time.Millisecond || restored.RandomSeed() != 42 {
+		t.Errorf("expected maxDepth 4, baseDelay 750ms and randomSeed 42, got %d, %s and %d",
+			restored.MaxDepth(), restored.BaseDelay(), restored.RandomSeed())
+	}
+	if !restored.MatchesPage("https://docs.example.com/guide/install", nil) {
+		t.Error("expected the restored match pattern to be compiled")
+	}
+}
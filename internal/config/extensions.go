@@ -0,0 +1,57 @@
+package config
+
+import "strings"
+
+// defaultExcludedExtensions are the file extensions of URLs that are almost
+// never documentation pages: archives, images, media, fonts and binaries.
+var defaultExcludedExtensions = []string{
+	".zip", ".tar", ".gz", ".tgz", ".bz2", ".xz", ".7z", ".rar",
+	".png", ".jpg", ".jpeg", ".gif", ".webp", ".svg", ".ico", ".bmp",
+	".mp3", ".mp4", ".webm", ".mov", ".avi", ".wav",
+	".woff", ".woff2", ".ttf", ".otf", ".eot",
+	".pdf", ".exe", ".dmg", ".msi", ".deb", ".rpm", ".iso", ".jar", ".apk",
+}
+
+// DefaultExcludedExtensions returns the extensions excluded by default.
+func DefaultExcludedExtensions() []string {
+	return append([]string(nil), defaultExcludedExtensions...)
+}
+
+// ExtensionMatcher decides whether a URL path ends in an excluded file
+// extension. It is compiled once from the excluded extensions when the config
+// is built.
+//
+// Entries are matched case-insensitively against the end of the path, with or
+// without their leading dot, so ".tar.gz" and "tar.gz" both exclude
+// "/dist/Release.TAR.GZ". A suffix only counts when it follows a dot, so
+// ".gz" does not exclude "/docs/bigz". A matcher with no entries excludes
+// nothing.
+type ExtensionMatcher struct {
+	suffixes []string
+}
+
+// compileExtensionMatcher builds an ExtensionMatcher from the excluded
+// extensions, ignoring blank entries.
+func compileExtensionMatcher(extensions []string) ExtensionMatcher {
+	var matcher ExtensionMatcher
+	for _, ext := range extensions {
+		ext = strings.ToLower(strings.TrimSpace(ext))
+		ext = strings.TrimPrefix(ext, ".")
+		if ext == "" {
+			continue
+		}
+		matcher.suffixes = append(matcher.suffixes, "."+ext)
+	}
+	return matcher
+}
+
+// Matches reports whether urlPath ends in one of the excluded extensions.
+func (m ExtensionMatcher) Matches(urlPath string) bool {
+	lower := strings.ToLower(urlPath)
+	for _, suffix := range m.suffixes {
+		if strings.HasSuffix(lower, suffix) {
+			return true
+		}
+	}
+	return false
+}
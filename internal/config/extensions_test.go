@@ -0,0 +1,65 @@
+package config_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+)
+
+func TestExtensionMatcher_Defaults(t *testing.T) {
+	seed := []url.URL{{Scheme: "https", Host: "docs.example.com"}}
+	cfg, err := config.WithDefault(seed).Build()
+	if err != nil {
+		t.Fatalf("unexpected build error: %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/downloads/release.zip", true},
+		{"/downloads/release.tar.gz", true},
+		{"/img/logo.png", true},
+		{"/IMG/LOGO.PNG", true},
+		{"/docs/guide.html", false},
+		{"/docs/guide", false},
+		{"/docs/README.md", false},
+		{"/docs/bigz", false},
+		{"/", false},
+	}
+	for _, tt := range tests {
+		if got := cfg.ExtensionMatcher().Matches(tt.path); got != tt.want {
+			t.Errorf("Matches(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestExtensionMatcher_CustomListNormalizesEntries(t *testing.T) {
+	seed := []url.URL{{Scheme: "https", Host: "docs.example.com"}}
+	cfg, err := config.WithDefault(seed).
+		WithExcludedExtensions([]string{"TAR.GZ", " .Csv ", ""}).
+		Build()
+	if err != nil {
+		t.Fatalf("unexpected build error: %v", err)
+	}
+
+	matcher := cfg.ExtensionMatcher()
+	if !matcher.Matches("/dist/app.tar.gz") || !matcher.Matches("/data/export.CSV") {
+		t.Error("expected entries to match with or without a leading dot, in any case")
+	}
+	if matcher.Matches("/img/logo.png") {
+		t.Error("expected a custom list to replace the defaults")
+	}
+}
+
+func TestExtensionMatcher_EmptyListExcludesNothing(t *testing.T) {
+	seed := []url.URL{{Scheme: "https", Host: "docs.example.com"}}
+	cfg, err := config.WithDefault(seed).WithExcludedExtensions(nil).Build()
+	if err != nil {
+		t.Fatalf("unexpected build error: %v", err)
+	}
+	if cfg.ExtensionMatcher().Matches("/downloads/release.zip") {
+		t.Error("expected no exclusions with an empty list")
+	}
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// HeaderRule adds request headers to the page fetches whose URL matches a
+// regular expression, such as an Authorization header for a protected path
+// prefix. The pattern is matched against the whole URL string and is
+// unanchored unless it says otherwise.
+type HeaderRule struct {
+	pattern string
+	headers map[string]string
+	// pattern compiled, set by Build
+	re *regexp.Regexp
+}
+
+// NewHeaderRule creates a HeaderRule. The pattern is compiled and validated
+// when the config is built.
+func NewHeaderRule(pattern string, headers map[string]string) HeaderRule {
+	return HeaderRule{
+		pattern: pattern,
+		headers: headers,
+	}
+}
+
+// Pattern returns the URL regular expression.
+func (r HeaderRule) Pattern() string {
+	return r.pattern
+}
+
+// Headers returns a copy of the headers the rule adds.
+func (r HeaderRule) Headers() map[string]string {
+	headers := make(map[string]string, len(r.headers))
+	for key, value := range r.headers {
+		headers[key] = value
+	}
+	return headers
+}
+
+// Regexp returns the compiled pattern, or nil for a rule not obtained
+// through a built Config.
+func (r HeaderRule) Regexp() *regexp.Regexp {
+	return r.re
+}
+
+// compileHeaderRules compiles the pattern of every rule.
+func compileHeaderRules(rules []HeaderRule) ([]HeaderRule, error) {
+	compiled := make([]HeaderRule, len(rules))
+	for i, rule := range rules {
+		re, err := regexp.Compile(rule.pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%w: headerRules[%d] has an invalid pattern %q: %v", ErrInvalidConfig, i, rule.pattern, err)
+		}
+		rule.re = re
+		compiled[i] = rule
+	}
+	return compiled, nil
+}
+
+// headerRuleDTO is the JSON form of a HeaderRule.
+type headerRuleDTO struct {
+	Pattern string            `json:"pattern"`
+	Headers map[string]string `json:"headers"`
+}
+
+// parseHeaderRules converts DTOs into HeaderRules.
+func parseHeaderRules(dtos []headerRuleDTO) []HeaderRule {
+	rules := make([]HeaderRule, 0, len(dtos))
+	for _, dto := range dtos {
+		rules = append(rules, NewHeaderRule(dto.Pattern, dto.Headers))
+	}
+	return rules
+}
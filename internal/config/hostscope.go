@@ -0,0 +1,181 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// HostMatcher decides whether a host is within the configured crawl scope.
+// It is compiled once from the allowed hosts when the config is built.
+//
+// Entries without a '*' are matched exactly (case-insensitive). Entries with
+// a '*' are glob patterns matched label by label: each pattern label matches
+// exactly one host label, so "*.example.com" matches "docs.example.com" but
+// neither "example.com" nor "a.b.example.com". Exact entries are checked first.
+//
+// With IncludeSubdomains, every subdomain of a seed host, at any depth, is
+// matched as well; these entries are reported as ".example.com".
+//
+// A matcher with no entries allows every host.
+type HostMatcher struct {
+	exact    map[string]struct{}
+	patterns [][]string
+	// hostnames whose subdomains are all in scope
+	subdomainsOf map[string]struct{}
+}
+
+// compileHostMatcher builds a HostMatcher from the allowed host set and the
+// hosts whose subdomains are in scope. Patterns are sorted so matching does
+// not depend on map iteration order.
+func compileHostMatcher(hosts map[string]struct{}, subdomainsOf []string) (HostMatcher, error) {
+	matcher := HostMatcher{exact: make(map[string]struct{}, len(hosts))}
+	for _, host := range subdomainsOf {
+		hostname := stripPort(strings.ToLower(strings.TrimSpace(host)))
+		if hostname == "" {
+			continue
+		}
+		if matcher.subdomainsOf == nil {
+			matcher.subdomainsOf = make(map[string]struct{})
+		}
+		matcher.subdomainsOf[hostname] = struct{}{}
+	}
+
+	// Sorted, so the first invalid pattern is the one reported every run
+	var patterns []string
+	for _, host := range sortedHosts(hosts) {
+		host = strings.ToLower(strings.TrimSpace(host))
+		if host == "" {
+			continue
+		}
+		if !strings.Contains(host, "*") {
+			matcher.exact[host] = struct{}{}
+			continue
+		}
+		for _, label := range strings.Split(host, ".") {
+			if label == "" {
+				return HostMatcher{}, fmt.Errorf("%w: invalid allowed host pattern %q", ErrInvalidConfig, host)
+			}
+			if _, err := path.Match(label, ""); err != nil {
+				return HostMatcher{}, fmt.Errorf("%w: invalid allowed host pattern %q", ErrInvalidConfig, host)
+			}
+		}
+		patterns = append(patterns, host)
+	}
+
+	sort.Strings(patterns)
+	for _, p := range patterns {
+		matcher.patterns = append(matcher.patterns, strings.Split(p, "."))
+	}
+	return matcher, nil
+}
+
+// IsEmpty reports whether the matcher has no entries and so allows every host.
+func (m HostMatcher) IsEmpty() bool {
+	return len(m.exact) == 0 && len(m.patterns) == 0 && len(m.subdomainsOf) == 0
+}
+
+// Matches reports whether host is in scope. host may carry a port; exact
+// entries are compared against both the full host and the bare hostname,
+// patterns only against the bare hostname.
+func (m HostMatcher) Matches(host string) bool {
+	if m.IsEmpty() {
+		return true
+	}
+
+	host = strings.ToLower(host)
+	if _, ok := m.exact[host]; ok {
+		return true
+	}
+	hostname := stripPort(host)
+	if _, ok := m.exact[hostname]; ok {
+		return true
+	}
+
+	labels := strings.Split(hostname, ".")
+	for _, pattern := range m.patterns {
+		if matchLabels(pattern, labels) {
+			return true
+		}
+	}
+	_, ok := m.subdomainParent(labels)
+	return ok
+}
+
+// subdomainParent returns the nearest parent of the host labels whose
+// subdomains are in scope.
+func (m HostMatcher) subdomainParent(labels []string) (string, bool) {
+	for i := 1; i < len(labels); i++ {
+		if labels[i-1] == "" {
+			return "", false
+		}
+		parent := strings.Join(labels[i:], ".")
+		if _, ok := m.subdomainsOf[parent]; ok {
+			return parent, true
+		}
+	}
+	return "", false
+}
+
+// MatchingEntries returns the allowed host entries that admit host: exact
+// entries first, then patterns in sorted order. An empty matcher returns nil
+// even though it allows every host.
+func (m HostMatcher) MatchingEntries(host string) []string {
+	var entries []string
+	host = strings.ToLower(host)
+	hostname := stripPort(host)
+	if _, ok := m.exact[host]; ok {
+		entries = append(entries, host)
+	}
+	if _, ok := m.exact[hostname]; ok && hostname != host {
+		entries = append(entries, hostname)
+	}
+
+	labels := strings.Split(hostname, ".")
+	for _, pattern := range m.patterns {
+		if matchLabels(pattern, labels) {
+			entries = append(entries, strings.Join(pattern, "."))
+		}
+	}
+	if parent, ok := m.subdomainParent(labels); ok {
+		entries = append(entries, "."+parent)
+	}
+	return entries
+}
+
+func matchLabels(pattern []string, labels []string) bool {
+	if len(pattern) != len(labels) {
+		return false
+	}
+	for i, p := range pattern {
+		if labels[i] == "" {
+			return false
+		}
+		ok, err := path.Match(p, labels[i])
+		if err != nil || !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// stripPort removes a trailing ":port" from host, leaving IPv6 literals intact.
+func stripPort(host string) string {
+	i := strings.LastIndexByte(host, ':')
+	if i < 0 || strings.HasSuffix(host, "]") {
+		return host
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(host[:i], "["), "]")
+}
+
+// sortedHosts returns the hosts of a host set in sorted order. Map order is
+// random, so anything derived from iterating a host set goes through it.
+func sortedHosts(hosts map[string]struct{}) []string {
+	sorted := make([]string, 0, len(hosts))
+	for host := range hosts {
+		sorted = append(sorted, host)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
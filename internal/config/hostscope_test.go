@@ -0,0 +1,194 @@
+package config_test
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+)
+
+func buildWithAllowedHosts(t *testing.T, hosts ...string) config.Config {
+	t.Helper()
+	set := make(map[string]struct{}, len(hosts))
+	for _, h := range hosts {
+		set[h] = struct{}{}
+	}
+	seed := []url.URL{{Scheme: "https", Host: "docs.example.com"}}
+	cfg, err := config.WithDefault(seed).WithAllowedHosts(set).Build()
+	if err != nil {
+		t.Fatalf("unexpected build error: %v", err)
+	}
+	return cfg
+}
+
+func TestIsHostAllowed_WildcardSubdomain(t *testing.T) {
+	cfg := buildWithAllowedHosts(t, "*.example.com")
+
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"a.example.com", true},
+		{"b.example.com", true},
+		{"B.Example.COM", true},
+		{"a.example.com:8443", true},
+		{"example.com", false},
+		{"evil-example.com", false},
+		{"a.b.example.com", false},
+		{"a.example.com.evil.org", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			if got := cfg.IsHostAllowed(tt.host); got != tt.want {
+				t.Errorf("IsHostAllowed(%q) = %v, want %v", tt.host, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsHostAllowed_ExplicitApexAlongsideWildcard(t *testing.T) {
+	cfg := buildWithAllowedHosts(t, "*.example.com", "example.com")
+
+	if !cfg.IsHostAllowed("example.com") {
+		t.Error("expected explicitly listed example.com to be allowed")
+	}
+	if !cfg.IsHostAllowed("a.example.com") {
+		t.Error("expected a.example.com to match *.example.com")
+	}
+	if cfg.IsHostAllowed("evil-example.com") {
+		t.Error("expected evil-example.com to be rejected")
+	}
+}
+
+func TestIsHostAllowed_MiddleWildcard(t *testing.T) {
+	cfg := buildWithAllowedHosts(t, "docs.*.example.com")
+
+	if !cfg.IsHostAllowed("docs.eu.example.com") {
+		t.Error("expected docs.eu.example.com to match docs.*.example.com")
+	}
+	if cfg.IsHostAllowed("api.eu.example.com") {
+		t.Error("expected api.eu.example.com to be rejected")
+	}
+	if cfg.IsHostAllowed("docs.example.com") {
+		t.Error("expected docs.example.com to be rejected (wildcard needs a label)")
+	}
+}
+
+func TestIsHostAllowed_ExactHostWithPort(t *testing.T) {
+	cfg := buildWithAllowedHosts(t, "localhost:8080")
+
+	if !cfg.IsHostAllowed("localhost:8080") {
+		t.Error("expected exact host with port to be allowed")
+	}
+	if cfg.IsHostAllowed("localhost:9090") {
+		t.Error("expected a different port to be rejected")
+	}
+}
+
+func TestIsHostAllowed_DefaultsToSeedHost(t *testing.T) {
+	seed := []url.URL{{Scheme: "https", Host: "docs.example.com"}}
+	cfg, err := config.WithDefault(seed).Build()
+	if err != nil {
+		t.Fatalf("unexpected build error: %v", err)
+	}
+
+	if !cfg.IsHostAllowed("docs.example.com") {
+		t.Error("expected seed host to be allowed")
+	}
+	if cfg.IsHostAllowed("api.example.com") {
+		t.Error("expected non-seed host to be rejected")
+	}
+}
+
+func TestIsHostAllowed_IncludeSubdomains(t *testing.T) {
+	seed := []url.URL{{Scheme: "https", Host: "example.com"}}
+	on, err := config.WithDefault(seed).WithIncludeSubdomains(true).Build()
+	if err != nil {
+		t.Fatalf("unexpected build error: %v", err)
+	}
+	off, err := config.WithDefault(seed).Build()
+	if err != nil {
+		t.Fatalf("unexpected build error: %v", err)
+	}
+
+	tests := []struct {
+		host    string
+		wantOn  bool
+		wantOff bool
+	}{
+		{"example.com", true, true},
+		{"docs.example.com", true, false},
+		{"a.b.example.com", true, false},
+		{"Docs.Example.com:8443", true, false},
+		{"evil-example.com", false, false},
+		{"example.com.evil.org", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			if got := on.IsHostAllowed(tt.host); got != tt.wantOn {
+				t.Errorf("with includeSubdomains, IsHostAllowed(%q) = %v, want %v", tt.host, got, tt.wantOn)
+			}
+			if got := off.IsHostAllowed(tt.host); got != tt.wantOff {
+				t.Errorf("without includeSubdomains, IsHostAllowed(%q) = %v, want %v", tt.host, got, tt.wantOff)
+			}
+		})
+	}
+
+	if entries := on.HostMatcher().MatchingEntries("docs.example.com"); len(entries) != 1 || entries[0] != ".example.com" {
+		t.Errorf("expected docs.example.com to be admitted by .example.com, got %v", entries)
+	}
+}
+
+func TestBuild_InvalidAllowedHostPattern(t *testing.T) {
+	seed := []url.URL{{Scheme: "https", Host: "docs.example.com"}}
+	_, err := config.WithDefault(seed).
+		WithAllowedHosts(map[string]struct{}{"[*.example.com": {}}).
+		Build()
+	if !errors.Is(err, config.ErrInvalidConfig) {
+		t.Fatalf("expected ErrInvalidConfig, got %v", err)
+	}
+}
+
+func TestAllowedHosts_DeterministicAcrossBuilds(t *testing.T) {
+	hosts := []string{"docs.example.com", "*.cdn.example.com", "api.example.com", "*.example.org", "blog.example.com"}
+	want := []string{"*.cdn.example.com", "*.example.org", "api.example.com", "blog.example.com", "docs.example.com"}
+
+	for i := 0; i < 20; i++ {
+		cfg := buildWithAllowedHosts(t, hosts...)
+
+		got := cfg.SortedAllowedHosts()
+		if len(got) != len(want) {
+			t.Fatalf("SortedAllowedHosts() = %v, want %v", got, want)
+		}
+		for j := range want {
+			if got[j] != want[j] {
+				t.Fatalf("SortedAllowedHosts() = %v, want %v", got, want)
+			}
+		}
+
+		if !cfg.IsHostAllowed("img.cdn.example.com") || cfg.IsHostAllowed("example.net") {
+			t.Fatalf("scope evaluation changed on build %d", i)
+		}
+		entries := cfg.HostMatcher().MatchingEntries("x.example.org")
+		if len(entries) != 1 || entries[0] != "*.example.org" {
+			t.Fatalf("MatchingEntries() = %v on build %d", entries, i)
+		}
+	}
+}
+
+func TestAllowedHosts_ReportsSameInvalidPatternAcrossBuilds(t *testing.T) {
+	set := map[string]struct{}{"*.b..example.com": {}, "*.a..example.com": {}, "*.c..example.com": {}}
+	seed := []url.URL{{Scheme: "https", Host: "docs.example.com"}}
+
+	for i := 0; i < 20; i++ {
+		_, err := config.WithDefault(seed).WithAllowedHosts(set).Build()
+		if !errors.Is(err, config.ErrInvalidConfig) {
+			t.Fatalf("expected ErrInvalidConfig, got %v", err)
+		}
+		if want := `invalid allowed host pattern "*.a..example.com"`; !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected the first pattern in sorted order to be reported, got %v", err)
+		}
+	}
+}
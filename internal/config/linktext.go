@@ -0,0 +1,51 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// LinkTextMatcher decides whether a discovered link is declined because of
+// its anchor text, such as "Download PDF" or "Edit on GitHub". It is
+// compiled once from the skip patterns when the config is built.
+//
+// Each pattern is a regular expression matched, unanchored, against the
+// link's text with surrounding whitespace trimmed and inner runs of
+// whitespace collapsed to one space. Matching is case-sensitive unless the
+// pattern says otherwise, e.g. "(?i)^download". A matcher with no patterns
+// declines nothing.
+type LinkTextMatcher struct {
+	patterns []*regexp.Regexp
+}
+
+// compileLinkTextMatcher builds a LinkTextMatcher from the skip patterns,
+// ignoring blank entries.
+func compileLinkTextMatcher(patterns []string) (LinkTextMatcher, error) {
+	var matcher LinkTextMatcher
+	for i, pattern := range patterns {
+		if strings.TrimSpace(pattern) == "" {
+			continue
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return LinkTextMatcher{}, fmt.Errorf("%w: skipLinkTextPatterns[%d] has an invalid pattern %q: %v", ErrInvalidConfig, i, pattern, err)
+		}
+		matcher.patterns = append(matcher.patterns, re)
+	}
+	return matcher, nil
+}
+
+// Matches reports whether the anchor text matches one of the patterns.
+func (m LinkTextMatcher) Matches(text string) bool {
+	if len(m.patterns) == 0 {
+		return false
+	}
+	text = strings.Join(strings.Fields(text), " ")
+	for _, re := range m.patterns {
+		if re.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
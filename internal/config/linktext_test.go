@@ -0,0 +1,61 @@
+package config_test
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+)
+
+func TestLinkTextMatcher_Matches(t *testing.T) {
+	seed := []url.URL{{Scheme: "https", Host: "docs.example.com"}}
+	cfg, err := config.WithDefault(seed).
+		WithSkipLinkTextPatterns([]string{"(?i)^download", "Edit on GitHub", "  "}).
+		Build()
+	if err != nil {
+		t.Fatalf("unexpected build error: %v", err)
+	}
+
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"Download PDF", true},
+		{"download", true},
+		{"  Download\n  the   PDF ", true},
+		{"Edit on GitHub", true},
+		{"Edit\non   GitHub", true},
+		{"edit on github", false},
+		{"How to download", false},
+		{"Installation guide", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := cfg.LinkTextMatcher().Matches(tt.text); got != tt.want {
+			t.Errorf("Matches(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestLinkTextMatcher_NoPatternsMatchesNothing(t *testing.T) {
+	seed := []url.URL{{Scheme: "https", Host: "docs.example.com"}}
+	cfg, err := config.WithDefault(seed).Build()
+	if err != nil {
+		t.Fatalf("unexpected build error: %v", err)
+	}
+	if len(cfg.SkipLinkTextPatterns()) != 0 {
+		t.Errorf("expected no skip patterns by default, got %v", cfg.SkipLinkTextPatterns())
+	}
+	if cfg.LinkTextMatcher().Matches("Download PDF") {
+		t.Error("expected no link text to be skipped by default")
+	}
+}
+
+func TestLinkTextMatcher_InvalidPattern(t *testing.T) {
+	seed := []url.URL{{Scheme: "https", Host: "docs.example.com"}}
+	_, err := config.WithDefault(seed).WithSkipLinkTextPatterns([]string{"Download (PDF"}).Build()
+	if !errors.Is(err, config.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig, got %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"time"
+
+	exponentialbackoff "github.com/rohmanhakim/exponential-backoff"
+)
+
+// PageRetry controls re-running the whole page pipeline (fetch through
+// write) after a page fails with a retryable classification. This sits on
+// top of the per-stage retries, which only repeat a single request.
+//
+// MaxAttempts counts the first run, so 1 disables page retries.
+// Each retry waits BaseBackoff * backoffMultiplier^(attempt-1), capped at
+// backoffMaxDuration; both limits are shared with the per-request retries
+// and filled in by Config.PageRetry.
+type PageRetry struct {
+	maxAttempts int
+	baseBackoff time.Duration
+	multiplier  float64
+	maxBackoff  time.Duration
+}
+
+// NewPageRetry creates a PageRetry. maxAttempts below 1 is treated as 1.
+func NewPageRetry(maxAttempts int, baseBackoff time.Duration) PageRetry {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	return PageRetry{
+		maxAttempts: maxAttempts,
+		baseBackoff: baseBackoff,
+	}
+}
+
+// MaxAttempts returns the total number of pipeline runs allowed per page.
+func (p PageRetry) MaxAttempts() int {
+	if p.maxAttempts < 1 {
+		return 1
+	}
+	return p.maxAttempts
+}
+
+// BaseBackoff returns the wait before the first retry.
+func (p PageRetry) BaseBackoff() time.Duration {
+	return p.baseBackoff
+}
+
+// withBackoffLimits returns p growing by multiplier up to maxBackoff.
+func (p PageRetry) withBackoffLimits(multiplier float64, maxBackoff time.Duration) PageRetry {
+	p.multiplier = multiplier
+	p.maxBackoff = maxBackoff
+	return p
+}
+
+// Backoff returns the wait after the given failed attempt (1-based).
+// A BaseBackoff above the cap is clamped to it; without limits (a PageRetry
+// not obtained through Config) every retry waits BaseBackoff.
+func (p PageRetry) Backoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	if p.baseBackoff <= 0 {
+		return 0
+	}
+	if p.multiplier <= 0 || p.maxBackoff <= 0 {
+		return p.baseBackoff
+	}
+	initial := min(p.baseBackoff, p.maxBackoff)
+	backoffConfig, err := exponentialbackoff.NewConfig(initial, p.maxBackoff, p.multiplier)
+	if err != nil {
+		return initial
+	}
+	return exponentialbackoff.CalculateDelay(attempt, 0, backoffConfig)
+}
+
+// pageRetryDTO is the JSON form of PageRetry. Omitted fields keep their defaults.
+type pageRetryDTO struct {
+	MaxAttempts *int    `json:"maxAttempts,omitempty"`
+	BaseBackoff *string `json:"baseBackoff,omitempty"`
+}
+
+// applyPageRetryDTO overlays the fields present in dto onto base.
+func applyPageRetryDTO(base PageRetry, dto pageRetryDTO) (PageRetry, error) {
+	maxAttempts := base.MaxAttempts()
+	if dto.MaxAttempts != nil {
+		maxAttempts = *dto.MaxAttempts
+	}
+	baseBackoff := base.BaseBackoff()
+	if dto.BaseBackoff != nil {
+		d, err := parseDurationString(*dto.BaseBackoff, "pageRetry.baseBackoff")
+		if err != nil {
+			return PageRetry{}, err
+		}
+		baseBackoff = d
+	}
+	return NewPageRetry(maxAttempts, baseBackoff), nil
+}
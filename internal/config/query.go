@@ -0,0 +1,39 @@
+package config
+
+// QueryFilter decides which query parameters of a URL survive
+// canonicalization, and so take part in admission and deduplication. It is
+// compiled once from dropQueryStrings and allowedQueryParams when the config
+// is built.
+//
+// With dropQueryStrings every parameter is dropped except the allowed ones;
+// without it every parameter is kept. Parameter names are matched exactly,
+// since servers treat them case-sensitively. The zero QueryFilter drops
+// every parameter.
+type QueryFilter struct {
+	keepAll bool
+	allowed map[string]struct{}
+}
+
+// compileQueryFilter builds a QueryFilter, ignoring blank parameter names.
+func compileQueryFilter(dropQueryStrings bool, allowedParams []string) QueryFilter {
+	filter := QueryFilter{keepAll: !dropQueryStrings}
+	for _, param := range allowedParams {
+		if param == "" {
+			continue
+		}
+		if filter.allowed == nil {
+			filter.allowed = make(map[string]struct{})
+		}
+		filter.allowed[param] = struct{}{}
+	}
+	return filter
+}
+
+// Keeps reports whether the query parameter named param survives.
+func (f QueryFilter) Keeps(param string) bool {
+	if f.keepAll {
+		return true
+	}
+	_, ok := f.allowed[param]
+	return ok
+}
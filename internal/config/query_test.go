@@ -0,0 +1,46 @@
+package config_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+)
+
+func TestQueryFilter(t *testing.T) {
+	seed := []url.URL{{Scheme: "https", Host: "docs.example.com"}}
+	tests := []struct {
+		name    string
+		drop    bool
+		allowed []string
+		param   string
+		want    bool
+	}{
+		{"drop without allowlist", true, nil, "page", false},
+		{"drop keeps allowlisted", true, []string{"version", ""}, "version", true},
+		{"drop matches names exactly", true, []string{"version"}, "Version", false},
+		{"keep all", false, nil, "page", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := config.WithDefault(seed).
+				WithDropQueryStrings(tt.drop).
+				WithAllowedQueryParams(tt.allowed).
+				Build()
+			if err != nil {
+				t.Fatalf("unexpected build error: %v", err)
+			}
+			if got := cfg.QueryFilter().Keeps(tt.param); got != tt.want {
+				t.Errorf("Keeps(%q) = %v, want %v", tt.param, got, tt.want)
+			}
+		})
+	}
+
+	cfg, err := config.WithDefault(seed).Build()
+	if err != nil {
+		t.Fatalf("unexpected build error: %v", err)
+	}
+	if !cfg.DropQueryStrings() || cfg.QueryFilter().Keeps("page") {
+		t.Error("expected query strings to be dropped by default")
+	}
+}
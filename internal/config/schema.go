@@ -0,0 +1,207 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/rohmanhakim/docs-crawler/pkg/hashutil"
+)
+
+/*
+JSON Schema
+
+JSONSchema describes the config file format as a JSON Schema (draft
+2020-12), for validating config files in CI and for editor autocompletion.
+The schema is generated from the config file fields, so it never misses a
+field; what the field types cannot express is listed in schemaHints:
+
+  - enums of the string settings (hash algorithms, modes, formats)
+  - patterns of durations ("1.5s"), times of day ("22:00") and seed URL
+    schemes
+  - defaults, taken from WithDefault
+
+Unknown fields are rejected, except for the deprecated names still migrated
+from older schema versions.
+*/
+
+// durationPattern matches the durations accepted by time.ParseDuration.
+const durationPattern = `^(0|[-+]?([0-9]*(\.[0-9]*)?(ns|us|µs|ms|s|m|h))+)$`
+
+// schemaHint is what the schema of one field adds to its type.
+type schemaHint struct {
+	enum        []string
+	pattern     string
+	minimum     *int
+	description string
+}
+
+// schemaHints are keyed by field path: nested fields are joined with ".",
+// array items and map values are "[]" and "{}".
+var schemaHints = map[string]schemaHint{
+	"seedUrls[]":             {pattern: `^https?://`},
+	"redirectOutOfScope":     {enum: []string{RedirectOutOfScopeSkip, RedirectOutOfScopeWrite}},
+	"trailingSlashPolicy":    {enum: []string{"strip", "preserve", "add"}},
+	"depthMode":              {enum: []string{DepthModeLinkDistance, DepthModePathDepth}},
+	"frontierStrategy":       {enum: []string{FrontierStrategyBreadthFirst, FrontierStrategyBestFirst}},
+	"jitterDistribution":     {enum: []string{JitterUniform, JitterExponential, JitterNormal}},
+	"assetNaming":            {enum: []string{"nameHash", "contentHash", "mirror"}},
+	"imageFormat":            {enum: []string{"", "png", "jpeg"}, description: "Format raster image assets are re-encoded in; empty keeps their format. WebP output is not supported."},
+	"graphFormat":            {enum: []string{"", GraphFormatJSON, GraphFormatDOT}},
+	"outputFormats[]":        {enum: []string{OutputFormatMarkdown, OutputFormatHTML, OutputFormatText}},
+	"noscriptMode":           {enum: []string{"drop", "inline", "preferWhenEmpty"}},
+	"multiArticleMode":       {enum: []string{"largest", "concatenate", "separateOutputs"}},
+	"hashAlgo":               {enum: []string{hashutil.HashAlgoSHA256, hashutil.HashAlgoBLAKE3}},
+	"markdownFlavor":         {enum: []string{"gfm", "commonmark"}},
+	"duplicateContent":       {enum: []string{"write", "alias"}},
+	"debugFormat":            {enum: []string{"", "json", "text"}},
+	"maxDuration":            {pattern: durationPattern},
+	"repeatInterval":         {pattern: durationPattern},
+	"baseDelay":              {pattern: durationPattern},
+	"defaultCrawlDelay":      {pattern: durationPattern},
+	"maxDepthDelay":          {pattern: durationPattern},
+	"depthDelayFactor":       {minimum: ptrTo(0)},
+	"jitter":                 {pattern: durationPattern},
+	"backoffInitialDuration": {pattern: durationPattern},
+	"backoffMaxDuration":     {pattern: durationPattern},
+	"timeout":                {pattern: durationPattern},
+	"idleConnTimeout":        {pattern: durationPattern},
+	"fetchCacheTTL":          {pattern: durationPattern},
+	"pageRetry.baseBackoff":  {pattern: durationPattern},
+	"autoTune.minDelay":      {pattern: durationPattern},
+	"autoTune.maxDelay":      {pattern: durationPattern},
+	"crawlWindows[].start":   {pattern: `^[0-9]{2}:[0-9]{2}$`},
+	"crawlWindows[].end":     {pattern: `^[0-9]{2}:[0-9]{2}$`},
+	"maxPagesPerDepth":       {minimum: ptrTo(0)},
+	"maxPatternRepeats":      {minimum: ptrTo(0)},
+	"maxErrors":              {minimum: ptrTo(0)},
+	"maxHTMLParseBytes":      {minimum: ptrTo(0)},
+	"maxIterations":          {minimum: ptrTo(0)},
+	"imageMaxWidth":          {minimum: ptrTo(0)},
+	"chunkSize":              {minimum: ptrTo(0)},
+	"chunkOverlap":           {minimum: ptrTo(0)},
+
+	// The auto-tuner's concurrency is advisory, the crawl fetches one page
+	// at a time
+	"autoTune.minConcurrency": {description: "Lowest concurrency the auto-tuner recommends. Advisory: pages are fetched one at a time."},
+	"autoTune.maxConcurrency": {description: "Highest concurrency the auto-tuner recommends. Advisory: pages are fetched one at a time."},
+}
+
+// noSchemaDefault lists the fields whose default depends on the seeds or
+// the run, so the schema states none.
+var noSchemaDefault = map[string]bool{
+	"seedUrls":     true,
+	"allowedHosts": true,
+	"randomSeed":   true,
+}
+
+// JSONSchema returns the JSON Schema of config files.
+func JSONSchema() []byte {
+	schema := typeSchema(reflect.TypeOf(configDTO{}), "")
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["title"] = "docs-crawler configuration"
+
+	properties := schema["properties"].(map[string]any)
+	properties["version"] = map[string]any{
+		"type":    "integer",
+		"minimum": 1,
+		"maximum": CurrentConfigVersion,
+	}
+	properties["runId"] = map[string]any{
+		"type":        "string",
+		"description": "Run ID of the crawl a config snapshot was written by; ignored when read.",
+	}
+	for version, renames := range configMigrations {
+		for _, rename := range renames {
+			deprecated := map[string]any{"deprecated": true}
+			for key, value := range properties[rename.to].(map[string]any) {
+				deprecated[key] = value
+			}
+			deprecated["description"] = fmt.Sprintf("Deprecated since version %d, use %s instead.", version+1, rename.to)
+			properties[rename.from] = deprecated
+		}
+	}
+
+	// Defaults, as a config file would list them
+	encoded, _ := json.Marshal(newDTOFromConfig(*WithDefault(nil)))
+	var defaults map[string]json.RawMessage
+	_ = json.Unmarshal(encoded, &defaults)
+	for name, value := range defaults {
+		if noSchemaDefault[name] || string(value) == "null" {
+			continue
+		}
+		if property, ok := properties[name].(map[string]any); ok {
+			property["default"] = value
+		}
+	}
+
+	out, _ := json.MarshalIndent(schema, "", "  ")
+	return append(out, '\n')
+}
+
+// typeSchema returns the schema of a config file field of type t, found at
+// path.
+func typeSchema(t reflect.Type, path string) map[string]any {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	schema := map[string]any{}
+	switch t.Kind() {
+	case reflect.Bool:
+		schema["type"] = "boolean"
+	case reflect.String:
+		schema["type"] = "string"
+	case reflect.Int, reflect.Int64:
+		schema["type"] = "integer"
+	case reflect.Float64:
+		schema["type"] = "number"
+	case reflect.Slice:
+		schema["type"] = "array"
+		schema["items"] = typeSchema(t.Elem(), path+"[]")
+	case reflect.Map:
+		schema["type"] = "object"
+		schema["additionalProperties"] = typeSchema(t.Elem(), path+"{}")
+	case reflect.Struct:
+		properties := map[string]any{}
+		var required []string
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+			fieldPath := name
+			if path != "" {
+				fieldPath = path + "." + name
+			}
+			properties[name] = typeSchema(field.Type, fieldPath)
+			if !strings.Contains(options, "omitempty") {
+				required = append(required, name)
+			}
+		}
+		schema["type"] = "object"
+		schema["properties"] = properties
+		schema["additionalProperties"] = false
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+	}
+
+	if hint, ok := schemaHints[path]; ok {
+		if hint.enum != nil {
+			schema["enum"] = hint.enum
+		}
+		if hint.pattern != "" {
+			schema["pattern"] = hint.pattern
+		}
+		if hint.minimum != nil {
+			schema["minimum"] = *hint.minimum
+		}
+		if hint.description != "" {
+			schema["description"] = hint.description
+		}
+	}
+	return schema
+}
@@ -0,0 +1,209 @@
+package config_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+)
+
+// validateAgainstSchema checks instance against the subset of JSON Schema
+// used by config.JSONSchema, returning the first violation.
+func validateAgainstSchema(schema map[string]any, instance any, path string) error {
+	if enum, ok := schema["enum"].([]any); ok {
+		found := false
+		for _, allowed := range enum {
+			if fmt.Sprint(allowed) == fmt.Sprint(instance) {
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s: %v is not one of %v", path, instance, enum)
+		}
+	}
+
+	switch schema["type"] {
+	case "boolean":
+		if _, ok := instance.(bool); !ok {
+			return fmt.Errorf("%s: expected boolean, got %T", path, instance)
+		}
+	case "string":
+		s, ok := instance.(string)
+		if !ok {
+			return fmt.Errorf("%s: expected string, got %T", path, instance)
+		}
+		if pattern, ok := schema["pattern"].(string); ok && !regexp.MustCompile(pattern).MatchString(s) {
+			return fmt.Errorf("%s: %q does not match %s", path, s, pattern)
+		}
+	case "integer", "number":
+		n, ok := instance.(json.Number)
+		if !ok {
+			return fmt.Errorf("%s: expected %s, got %T", path, schema["type"], instance)
+		}
+		if schema["type"] == "integer" && strings.ContainsAny(n.String(), ".eE") {
+			return fmt.Errorf("%s: expected integer, got %s", path, n)
+		}
+		value, _ := n.Float64()
+		if minimum, ok := schema["minimum"].(float64); ok && value < minimum {
+			return fmt.Errorf("%s: %s is below the minimum %g", path, n, minimum)
+		}
+		if maximum, ok := schema["maximum"].(float64); ok && value > maximum {
+			return fmt.Errorf("%s: %s is above the maximum %g", path, n, maximum)
+		}
+	case "array":
+		items, ok := instance.([]any)
+		if !ok {
+			return fmt.Errorf("%s: expected array, got %T", path, instance)
+		}
+		for i, item := range items {
+			if err := validateAgainstSchema(schema["items"].(map[string]any), item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case "object":
+		object, ok := instance.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: expected object, got %T", path, instance)
+		}
+		required, _ := schema["required"].([]any)
+		for _, name := range required {
+			if _, ok := object[name.(string)]; !ok {
+				return fmt.Errorf("%s: missing required %s", path, name)
+			}
+		}
+		properties, _ := schema["properties"].(map[string]any)
+		for name, value := range object {
+			if property, ok := properties[name].(map[string]any); ok {
+				if err := validateAgainstSchema(property, value, path+"."+name); err != nil {
+					return err
+				}
+				continue
+			}
+			switch additional := schema["additionalProperties"].(type) {
+			case bool:
+				if !additional {
+					return fmt.Errorf("%s: unknown property %s", path, name)
+				}
+			case map[string]any:
+				if err := validateAgainstSchema(additional, value, path+"."+name); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func loadSchemaForTest(t *testing.T) map[string]any {
+	t.Helper()
+	var schema map[string]any
+	if err := json.Unmarshal(config.JSONSchema(), &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+	return schema
+}
+
+func validateConfigForTest(t *testing.T, schema map[string]any, content string) error {
+	t.Helper()
+	decoder := json.NewDecoder(strings.NewReader(content))
+	decoder.UseNumber()
+	var instance any
+	if err := decoder.Decode(&instance); err != nil {
+		t.Fatalf("config is not valid JSON: %v", err)
+	}
+	return validateAgainstSchema(schema, instance, "$")
+}
+
+func TestJSONSchema_ValidatesExampleConfig(t *testing.T) {
+	schema := loadSchemaForTest(t)
+	content, err := os.ReadFile("../../example/config.json")
+	if err != nil {
+		t.Fatalf("failed to read example config: %v", err)
+	}
+	if err := validateConfigForTest(t, schema, string(content)); err != nil {
+		t.Errorf("expected the example config to validate, got %v", err)
+	}
+
+	nested := `{
+		"seedUrls": ["https://docs.example.com/"],
+		"hashAlgo": "blake3",
+		"outputFormats": ["md", "txt"],
+		"crawlWindows": [{"start": "22:00", "end": "06:00"}],
+		"autoTune": {"enabled": true, "maxDelay": "1.5s"},
+		"headerRules": [{"pattern": "^https://docs", "headers": {"X-Team": "docs"}}],
+		"maxAttempt": 3
+	}`
+	if err := validateConfigForTest(t, schema, nested); err != nil {
+		t.Errorf("expected nested settings to validate, got %v", err)
+	}
+}
+
+func TestJSONSchema_RejectsInvalidConfigs(t *testing.T) {
+	schema := loadSchemaForTest(t)
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"wrong-typed field", `{"seedUrls": ["https://docs.example.com/"], "maxDepth": "7"}`},
+		{"invalid enum value", `{"seedUrls": ["https://docs.example.com/"], "hashAlgo": "md5"}`},
+		{"invalid nested enum value", `{"seedUrls": ["https://docs.example.com/"], "outputFormats": ["md", "pdf"]}`},
+		{"unsupported image format", `{"seedUrls": ["https://docs.example.com/"], "imageFormat": "webp"}`},
+		{"invalid duration", `{"seedUrls": ["https://docs.example.com/"], "timeout": "ten seconds"}`},
+		{"unsupported scheme", `{"seedUrls": ["ftp://docs.example.com/"]}`},
+		{"unknown field", `{"seedUrls": ["https://docs.example.com/"], "maxDepht": 7}`},
+		{"missing seeds", `{"maxDepth": 7}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateConfigForTest(t, schema, tt.content); err == nil {
+				t.Errorf("expected %s to be rejected", tt.content)
+			}
+		})
+	}
+}
+
+func TestJSONSchema_ListsDefaults(t *testing.T) {
+	schema := loadSchemaForTest(t)
+	properties := schema["properties"].(map[string]any)
+
+	if got := properties["hashAlgo"].(map[string]any)["default"]; got != "sha256" {
+		t.Errorf("expected hashAlgo default sha256, got %v", got)
+	}
+	if got := properties["timeout"].(map[string]any)["default"]; got != "10s" {
+		t.Errorf("expected timeout default 10s, got %v", got)
+	}
+	if got, _ := properties["imageFormat"].(map[string]any)["description"].(string); !strings.Contains(got, "WebP") {
+		t.Errorf("expected the imageFormat description to state WebP is unsupported, got %q", got)
+	}
+	if _, ok := properties["randomSeed"].(map[string]any)["default"]; ok {
+		t.Errorf("expected no randomSeed default")
+	}
+	for name, property := range properties {
+		if value, ok := property.(map[string]any)["default"]; ok {
+			if err := validateAgainstSchema(property.(map[string]any), jsonNumbers(value), name); err != nil {
+				t.Errorf("default of %s does not validate: %v", name, err)
+			}
+		}
+	}
+}
+
+// jsonNumbers converts the float64 numbers in v to json.Number.
+func jsonNumbers(v any) any {
+	switch value := v.(type) {
+	case float64:
+		return json.Number(fmt.Sprint(value))
+	case []any:
+		for i := range value {
+			value[i] = jsonNumbers(value[i])
+		}
+	case map[string]any:
+		for key := range value {
+			value[key] = jsonNumbers(value[key])
+		}
+	}
+	return v
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+/*
+Config Snapshots
+
+A crawl writes the config it ran with, after the CLI flags, config file and
+defaults are merged, next to its output (see EncodeSnapshot). The snapshot
+is a complete config file: every field is listed, defaults included, so
+reading it back with WithConfigFile yields an equivalent Config even if a
+later build changes the defaults. It also records the run ID of the crawl,
+which is informational and ignored when the snapshot is read.
+
+Header values (defaultHeaders and headerRules) often carry credentials such
+as an Authorization or X-Api-Key header, and output directories are often
+committed. The snapshot keeps the header names but replaces every value
+with redactedHeaderValue, so a run reusing it must supply them again.
+*/
+
+// redactedHeaderValue replaces header values in a config snapshot.
+const redactedHeaderValue = "<redacted>"
+
+// snapshotDTO is a config file with the schema version and run ID of the
+// crawl it was written by.
+type snapshotDTO struct {
+	Version int    `json:"version"`
+	RunID   string `json:"runId,omitempty"`
+	configDTO
+}
+
+// EncodeSnapshot encodes c as an indented config file listing every field,
+// along with the run ID of the crawl it configures.
+// Header values are redacted.
+func EncodeSnapshot(c Config, runID string) ([]byte, error) {
+	dto := newDTOFromConfig(c)
+	redactHeaders(&dto)
+	// Unescaped, so the placeholder reads as "<redacted>" rather than
+	// "\u003credacted\u003e"
+	var encoded bytes.Buffer
+	encoder := json.NewEncoder(&encoded)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(snapshotDTO{
+		Version:   CurrentConfigVersion,
+		RunID:     runID,
+		configDTO: dto,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to encode config snapshot: %w", err)
+	}
+	return encoded.Bytes(), nil
+}
+
+// redactHeaders replaces the header values of dto with redactedHeaderValue.
+// The DTO holds copies, so the Config it was built from is left untouched.
+func redactHeaders(dto *configDTO) {
+	if dto.DefaultHeaders != nil {
+		dto.DefaultHeaders = ptrTo(redactedHeaderMap(*dto.DefaultHeaders))
+	}
+	if dto.HeaderRules != nil {
+		rules := make([]headerRuleDTO, len(*dto.HeaderRules))
+		for i, rule := range *dto.HeaderRules {
+			rules[i] = headerRuleDTO{Pattern: rule.Pattern, Headers: redactedHeaderMap(rule.Headers)}
+		}
+		dto.HeaderRules = &rules
+	}
+}
+
+// redactedHeaderMap returns headers with every value redacted.
+func redactedHeaderMap(headers map[string]string) map[string]string {
+	redacted := make(map[string]string, len(headers))
+	for name := range headers {
+		redacted[name] = redactedHeaderValue
+	}
+	return redacted
+}
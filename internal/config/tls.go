@@ -0,0 +1,72 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// HostTLS relaxes TLS certificate verification for the fetches to one host,
+// for internal documentation served with a private CA or a self-signed
+// certificate. Other hosts keep the default verification.
+type HostTLS struct {
+	caFile             string
+	insecureSkipVerify bool
+}
+
+// NewHostTLS creates a HostTLS trusting the PEM certificates in caFile, in
+// addition to the system roots, or skipping verification altogether.
+// An empty caFile adds no certificate.
+func NewHostTLS(caFile string, insecureSkipVerify bool) HostTLS {
+	return HostTLS{
+		caFile:             caFile,
+		insecureSkipVerify: insecureSkipVerify,
+	}
+}
+
+// CAFile returns the path of the PEM bundle trusted for the host.
+func (h HostTLS) CAFile() string {
+	return h.caFile
+}
+
+// InsecureSkipVerify reports whether any certificate from the host is
+// accepted.
+func (h HostTLS) InsecureSkipVerify() bool {
+	return h.insecureSkipVerify
+}
+
+// tlsDTO is the JSON form of a HostTLS.
+type tlsDTO struct {
+	CAFile             string `json:"caFile,omitempty"`
+	InsecureSkipVerify bool   `json:"insecureSkipVerify,omitempty"`
+}
+
+// parseHostTLS converts DTOs into HostTLS settings.
+func parseHostTLS(dtos map[string]tlsDTO) map[string]HostTLS {
+	hosts := make(map[string]HostTLS, len(dtos))
+	for host, dto := range dtos {
+		hosts[host] = NewHostTLS(dto.CAFile, dto.InsecureSkipVerify)
+	}
+	return hosts
+}
+
+// normalizeHostTLS validates the settings and lower-cases their hosts.
+func normalizeHostTLS(hosts map[string]HostTLS) (map[string]HostTLS, error) {
+	if len(hosts) == 0 {
+		return nil, nil
+	}
+	normalized := make(map[string]HostTLS, len(hosts))
+	for host, settings := range hosts {
+		key := strings.ToLower(strings.TrimSpace(host))
+		if key == "" || strings.ContainsAny(key, "/:") {
+			return nil, fmt.Errorf("%w: tls host must be a host name without scheme or port, got %q", ErrInvalidConfig, host)
+		}
+		if settings.caFile == "" && !settings.insecureSkipVerify {
+			return nil, fmt.Errorf("%w: tls for host %q sets neither caFile nor insecureSkipVerify", ErrInvalidConfig, host)
+		}
+		if _, duplicate := normalized[key]; duplicate {
+			return nil, fmt.Errorf("%w: tls lists host %q twice", ErrInvalidConfig, key)
+		}
+		normalized[key] = settings
+	}
+	return normalized, nil
+}
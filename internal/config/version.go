@@ -0,0 +1,87 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+/*
+Schema Versioning
+
+Config files carry a "version" field naming the schema they were written
+against. Files without one predate versioning and are read as version 1.
+
+Before decoding, a file is migrated one version at a time up to
+CurrentConfigVersion. Each migration step renames fields; every renamed
+field present in the file adds a deprecation warning, exposed through
+Config.Warnings. Files naming a version newer than CurrentConfigVersion are
+rejected, since their fields cannot be interpreted.
+
+Version history:
+  - 1: initial schema
+  - 2: maxAttempt renamed to maxAttempts
+*/
+
+// CurrentConfigVersion is the config file schema version this build reads.
+const CurrentConfigVersion = 2
+
+// fieldRename renames a top-level config field.
+type fieldRename struct {
+	from string
+	to   string
+}
+
+// configMigrations lists, for each version, the renames that upgrade a
+// file of that version to the next one.
+var configMigrations = map[int][]fieldRename{
+	1: {{from: "maxAttempt", to: "maxAttempts"}},
+}
+
+// migrateConfig upgrades a raw config file to CurrentConfigVersion and
+// returns the migrated JSON along with deprecation warnings.
+func migrateConfig(content []byte) ([]byte, []string, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(content, &doc); err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrConfigParsingFail, err.Error())
+	}
+
+	version := 1
+	if raw, ok := doc["version"]; ok {
+		if err := json.Unmarshal(raw, &version); err != nil {
+			return nil, nil, fmt.Errorf("%w: version must be an integer, got %s", ErrConfigParsingFail, string(raw))
+		}
+	}
+	if version < 1 {
+		return nil, nil, fmt.Errorf("%w: config version %d is not valid", ErrUnsupportedConfigVersion, version)
+	}
+	if version > CurrentConfigVersion {
+		return nil, nil, fmt.Errorf("%w: config version %d is newer than the supported version %d; upgrade docs-crawler to read it",
+			ErrUnsupportedConfigVersion, version, CurrentConfigVersion)
+	}
+	if version == CurrentConfigVersion {
+		return content, nil, nil
+	}
+
+	var warnings []string
+	for v := version; v < CurrentConfigVersion; v++ {
+		for _, rename := range configMigrations[v] {
+			value, ok := doc[rename.from]
+			if !ok {
+				continue
+			}
+			if _, exists := doc[rename.to]; exists {
+				return nil, nil, fmt.Errorf("%w: both %q and its replacement %q are set", ErrInvalidConfig, rename.from, rename.to)
+			}
+			doc[rename.to] = value
+			delete(doc, rename.from)
+			warnings = append(warnings, fmt.Sprintf("config field %q is deprecated since version %d, use %q instead", rename.from, v+1, rename.to))
+		}
+	}
+
+	doc["version"] = json.RawMessage(fmt.Sprint(CurrentConfigVersion))
+	migrated, err := json.Marshal(doc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrConfigParsingFail, err.Error())
+	}
+	return migrated, warnings, nil
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// TimeWindow is a daily time-of-day range during which crawling is allowed.
+// Start and End are offsets from midnight in the location of the time being
+// checked. A window whose End is before its Start wraps past midnight
+// (e.g. 22:00-06:00). Start == End means the whole day.
+// An empty host applies the window to every host.
+type TimeWindow struct {
+	start time.Duration
+	end   time.Duration
+	host  string
+}
+
+// NewTimeWindow creates a TimeWindow from offsets since midnight.
+func NewTimeWindow(start time.Duration, end time.Duration, host string) TimeWindow {
+	return TimeWindow{
+		start: start,
+		end:   end,
+		host:  host,
+	}
+}
+
+// Start returns the window opening time as an offset from midnight.
+func (w TimeWindow) Start() time.Duration {
+	return w.start
+}
+
+// End returns the window closing time as an offset from midnight.
+func (w TimeWindow) End() time.Duration {
+	return w.end
+}
+
+// Host returns the host the window applies to, or "" for all hosts.
+func (w TimeWindow) Host() string {
+	return w.host
+}
+
+// AppliesTo reports whether the window governs the given host.
+func (w TimeWindow) AppliesTo(host string) bool {
+	return w.host == "" || w.host == host
+}
+
+// Contains reports whether t falls inside the window.
+// The start is inclusive and the end is exclusive.
+func (w TimeWindow) Contains(t time.Time) bool {
+	if w.start == w.end {
+		return true
+	}
+	offset := sinceMidnight(t)
+	if w.start < w.end {
+		return offset >= w.start && offset < w.end
+	}
+	// Wraps past midnight
+	return offset >= w.start || offset < w.end
+}
+
+// NextOpen returns the earliest time at or after t when the window is open.
+func (w TimeWindow) NextOpen(t time.Time) time.Time {
+	if w.Contains(t) {
+		return t
+	}
+	year, month, day := t.Date()
+	opening := time.Date(year, month, day, 0, 0, 0, 0, t.Location()).Add(w.start)
+	if !opening.After(t) {
+		opening = time.Date(year, month, day+1, 0, 0, 0, 0, t.Location()).Add(w.start)
+	}
+	return opening
+}
+
+func sinceMidnight(t time.Time) time.Duration {
+	year, month, day := t.Date()
+	return t.Sub(time.Date(year, month, day, 0, 0, 0, 0, t.Location()))
+}
+
+type timeWindowDTO struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+	Host  string `json:"host,omitempty"`
+}
+
+// parseTimeWindows converts DTO windows with "HH:MM" bounds into TimeWindows.
+func parseTimeWindows(dtos []timeWindowDTO) ([]TimeWindow, error) {
+	windows := make([]TimeWindow, 0, len(dtos))
+	for _, dto := range dtos {
+		start, err := parseTimeOfDay(dto.Start, "crawlWindows.start")
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseTimeOfDay(dto.End, "crawlWindows.end")
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, NewTimeWindow(start, end, dto.Host))
+	}
+	return windows, nil
+}
+
+// parseTimeOfDay parses a "HH:MM" string into an offset from midnight.
+func parseTimeOfDay(s string, fieldName string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day for %s: %q (expected HH:MM)", fieldName, s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// formatTimeOfDay formats an offset from midnight as "HH:MM".
+func formatTimeOfDay(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d/time.Hour), int(d%time.Hour/time.Minute))
+}
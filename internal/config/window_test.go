@@ -0,0 +1,143 @@
+package config_test
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+)
+
+func at(hour, minute int) time.Time {
+	return time.Date(2026, 3, 10, hour, minute, 0, 0, time.UTC)
+}
+
+func TestTimeWindow_Contains(t *testing.T) {
+	tests := []struct {
+		name   string
+		window config.TimeWindow
+		now    time.Time
+		want   bool
+	}{
+		{"inside same-day window", config.NewTimeWindow(9*time.Hour, 17*time.Hour, ""), at(12, 0), true},
+		{"start is inclusive", config.NewTimeWindow(9*time.Hour, 17*time.Hour, ""), at(9, 0), true},
+		{"end is exclusive", config.NewTimeWindow(9*time.Hour, 17*time.Hour, ""), at(17, 0), false},
+		{"before same-day window", config.NewTimeWindow(9*time.Hour, 17*time.Hour, ""), at(8, 59), false},
+		{"wrapping window late evening", config.NewTimeWindow(22*time.Hour, 6*time.Hour, ""), at(23, 30), true},
+		{"wrapping window early morning", config.NewTimeWindow(22*time.Hour, 6*time.Hour, ""), at(5, 59), true},
+		{"wrapping window midday", config.NewTimeWindow(22*time.Hour, 6*time.Hour, ""), at(12, 0), false},
+		{"start equals end is all day", config.NewTimeWindow(0, 0, ""), at(12, 0), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.window.Contains(tt.now); got != tt.want {
+				t.Errorf("Contains(%v) = %v, want %v", tt.now, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimeWindow_NextOpen(t *testing.T) {
+	window := config.NewTimeWindow(22*time.Hour, 6*time.Hour, "")
+
+	// Inside the window: opens now
+	if got := window.NextOpen(at(23, 0)); !got.Equal(at(23, 0)) {
+		t.Errorf("expected NextOpen to be now when inside window, got %v", got)
+	}
+	// Before today's opening
+	if got := window.NextOpen(at(12, 0)); !got.Equal(at(22, 0)) {
+		t.Errorf("expected 22:00 today, got %v", got)
+	}
+
+	// After today's opening of a same-day window: opens tomorrow
+	dayWindow := config.NewTimeWindow(9*time.Hour, 17*time.Hour, "")
+	want := time.Date(2026, 3, 11, 9, 0, 0, 0, time.UTC)
+	if got := dayWindow.NextOpen(at(18, 0)); !got.Equal(want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestTimeWindow_AppliesTo(t *testing.T) {
+	global := config.NewTimeWindow(0, time.Hour, "")
+	scoped := config.NewTimeWindow(0, time.Hour, "partner.example.com")
+
+	if !global.AppliesTo("any.example.com") {
+		t.Error("expected host-less window to apply to every host")
+	}
+	if !scoped.AppliesTo("partner.example.com") {
+		t.Error("expected scoped window to apply to its host")
+	}
+	if scoped.AppliesTo("other.example.com") {
+		t.Error("expected scoped window not to apply to other hosts")
+	}
+}
+
+func TestWithCrawlWindows(t *testing.T) {
+	baseURL := []url.URL{{Scheme: "https", Host: "base.org"}}
+	cfg, err := config.WithDefault(baseURL).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.CrawlWindows()) != 0 {
+		t.Errorf("expected no crawl windows by default, got %d", len(cfg.CrawlWindows()))
+	}
+
+	windows := []config.TimeWindow{config.NewTimeWindow(22*time.Hour, 6*time.Hour, "base.org")}
+	cfg, err = config.WithDefault(baseURL).WithCrawlWindows(windows).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.CrawlWindows()) != 1 || cfg.CrawlWindows()[0] != windows[0] {
+		t.Errorf("expected crawl windows %v, got %v", windows, cfg.CrawlWindows())
+	}
+}
+
+func TestWithConfigFile_CrawlWindows(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.json")
+	configData := `{
+		"seedUrls": ["https://example.com"],
+		"crawlWindows": [
+			{"start": "22:00", "end": "06:30"},
+			{"start": "12:15", "end": "13:00", "host": "partner.example.com"}
+		]
+	}`
+	if err := os.WriteFile(configPath, []byte(configData), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	cfg, err := config.WithConfigFile(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	windows := cfg.CrawlWindows()
+	if len(windows) != 2 {
+		t.Fatalf("expected 2 windows, got %d", len(windows))
+	}
+	if windows[0].Start() != 22*time.Hour || windows[0].End() != 6*time.Hour+30*time.Minute || windows[0].Host() != "" {
+		t.Errorf("unexpected first window: start=%v end=%v host=%q", windows[0].Start(), windows[0].End(), windows[0].Host())
+	}
+	if windows[1].Start() != 12*time.Hour+15*time.Minute || windows[1].End() != 13*time.Hour || windows[1].Host() != "partner.example.com" {
+		t.Errorf("unexpected second window: start=%v end=%v host=%q", windows[1].Start(), windows[1].End(), windows[1].Host())
+	}
+}
+
+func TestWithConfigFile_InvalidCrawlWindow(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.json")
+	configData := `{
+		"seedUrls": ["https://example.com"],
+		"crawlWindows": [{"start": "25:00", "end": "06:00"}]
+	}`
+	if err := os.WriteFile(configPath, []byte(configData), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	if _, err := config.WithConfigFile(configPath); err == nil {
+		t.Fatal("expected error for invalid crawl window time, got nil")
+	}
+}
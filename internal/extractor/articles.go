@@ -0,0 +1,161 @@
+package extractor
+
+import (
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+/*
+Multiple Articles
+
+Aggregated pages, e.g. blog indexes or changelogs, carry several <article>
+elements side by side. Only meaningful articles not nested in another
+article count, and ExtractParam.MultiArticleMode decides what happens once
+there are two or more:
+
+  - largest (default): the semantic layer keeps its usual choice, except
+    that among several articles the one with the most text is chosen, not
+    the first
+  - concatenate: the articles become one content node. When the semantic
+    layer chose a container holding every article, e.g. a <main> wrapping
+    them, it is a copy of that container, and otherwise a <div> holding
+    copies of the articles in document order. Either way the articles
+    become <section> elements, since sibling articles are competing
+    document roots to the sanitizer, and every <h1> after the first is
+    demoted to <h2>, so the page keeps a single title
+  - separateOutputs: the articles are reported in
+    ExtractionResult.ArticleNodes, in document order, for the caller to
+    process as pages of their own; ContentNode is the first of them
+
+Pages with a single article are extracted the same in every mode.
+*/
+
+// MultiArticleMode selects how a page with several <article> elements is
+// extracted.
+type MultiArticleMode string
+
+const (
+	// MultiArticleLargest extracts the article with the most text.
+	MultiArticleLargest MultiArticleMode = "largest"
+	// MultiArticleConcatenate extracts every article as one content node.
+	MultiArticleConcatenate MultiArticleMode = "concatenate"
+	// MultiArticleSeparateOutputs reports every article as its own content
+	// node in ExtractionResult.ArticleNodes.
+	MultiArticleSeparateOutputs MultiArticleMode = "separateOutputs"
+)
+
+// topLevelArticles returns the meaningful <article> elements of doc that are
+// not nested in another <article>, in document order.
+func topLevelArticles(doc *html.Node, threshold MeaningfulThreshold) []*html.Node {
+	var articles []*html.Node
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "article" {
+			if isMeaningful(n, threshold) {
+				articles = append(articles, n)
+			}
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+	return articles
+}
+
+// largestNode returns the node of nodes with the most visible text, the
+// first of them on a tie.
+func largestNode(nodes []*html.Node) *html.Node {
+	var largest *html.Node
+	largestLength := -1
+	for _, node := range nodes {
+		if length := visibleTextLength(node); length > largestLength {
+			largest, largestLength = node, length
+		}
+	}
+	return largest
+}
+
+// containsAll reports whether every node of nodes is container or one of
+// its descendants.
+func containsAll(container *html.Node, nodes []*html.Node) bool {
+	for _, node := range nodes {
+		inside := false
+		for n := node; n != nil; n = n.Parent {
+			if n == container {
+				inside = true
+				break
+			}
+		}
+		if !inside {
+			return false
+		}
+	}
+	return true
+}
+
+// concatenateArticles returns a detached copy of contentNode when it holds
+// every article, and a <div> holding copies of articles in order otherwise.
+// In the copy, articles become sections and every <h1> after the first is
+// demoted to <h2>.
+func concatenateArticles(contentNode *html.Node, articles []*html.Node) *html.Node {
+	var merged *html.Node
+	if containsAll(contentNode, articles) {
+		merged = deepCloneNode(contentNode)
+	} else {
+		merged = &html.Node{Type: html.ElementNode, DataAtom: atom.Div, Data: "div"}
+		for _, article := range articles {
+			merged.AppendChild(deepCloneNode(article))
+		}
+	}
+
+	seenTitle := false
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch {
+			case n.Data == "article":
+				n.DataAtom = atom.Section
+				n.Data = "section"
+			case n.Data == "h1" && seenTitle:
+				n.DataAtom = atom.H2
+				n.Data = "h2"
+			case n.Data == "h1":
+				seenTitle = true
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(merged)
+	return merged
+}
+
+// applyMultiArticleMode returns the content node replacing contentNode, the
+// choice of the semantic layer, and the articles to output separately, for
+// a doc with several articles. It returns contentNode unchanged otherwise.
+func applyMultiArticleMode(
+	doc *html.Node,
+	contentNode *html.Node,
+	mode MultiArticleMode,
+	threshold MeaningfulThreshold,
+) (*html.Node, []*html.Node) {
+	articles := topLevelArticles(doc, threshold)
+	if len(articles) < 2 {
+		return contentNode, nil
+	}
+
+	switch mode {
+	case MultiArticleConcatenate:
+		return concatenateArticles(contentNode, articles), nil
+	case MultiArticleSeparateOutputs:
+		return articles[0], articles
+	default:
+		if contentNode.Data == "article" {
+			return largestNode(articles), nil
+		}
+		return contentNode, nil
+	}
+}
@@ -0,0 +1,131 @@
+package extractor_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/extractor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/html"
+)
+
+// headingTexts returns the tag and text of the headings of node, in
+// document order, as "h1 Title".
+func headingTexts(node *html.Node) []string {
+	var headings []string
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && len(n.Data) == 2 && n.Data[0] == 'h' && n.Data[1] >= '1' && n.Data[1] <= '6' {
+			var text strings.Builder
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				if c.Type == html.TextNode {
+					text.WriteString(c.Data)
+				}
+			}
+			headings = append(headings, n.Data+" "+strings.TrimSpace(text.String()))
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(node)
+	return headings
+}
+
+// findElement returns the first element with the given tag under node.
+func findElement(node *html.Node, tag string) *html.Node {
+	if node.Type == html.ElementNode && node.Data == tag {
+		return node
+	}
+	for c := node.FirstChild; c != nil; c = c.NextSibling {
+		if found := findElement(c, tag); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func extractMultiArticle(t *testing.T, mode extractor.MultiArticleMode) extractor.ExtractionResult {
+	t.Helper()
+	params := extractor.DefaultExtractParam()
+	params.MultiArticleMode = mode
+	ext, _ := setupExtractorWithParams(params)
+	result, err := ext.Extract(mustParseURL(t, "https://example.com/releases"), loadFixture(t, "case_multi_article.html"))
+	require.NoError(t, err)
+	require.NotNil(t, result.ContentNode)
+	return result
+}
+
+func TestExtract_MultiArticleMode_DefaultsToLargest(t *testing.T) {
+	assert.Equal(t, extractor.MultiArticleLargest, extractor.DefaultExtractParam().MultiArticleMode)
+}
+
+func TestExtract_MultiArticleMode_Largest(t *testing.T) {
+	result := extractMultiArticle(t, extractor.MultiArticleLargest)
+
+	// The longest article is kept, not the first
+	assert.Equal(t, []string{"h1 Version 1.1"}, headingTexts(result.ContentNode))
+	assert.Nil(t, result.ArticleNodes)
+}
+
+func TestExtract_MultiArticleMode_Concatenate(t *testing.T) {
+	result := extractMultiArticle(t, extractor.MultiArticleConcatenate)
+
+	// Every meaningful article in order, under the first one's title
+	assert.Equal(t, []string{"h1 Version 2.0", "h2 Version 1.1", "h2 Version 1.0"}, headingTexts(result.ContentNode))
+	assertElementNotExistsInNode(t, result.ContentNode, "class", "teaser")
+	assert.Nil(t, findElement(result.ContentNode, "article"))
+	assertElementNotExistsInNode(t, result.ContentNode, "class", "site-nav")
+	assert.Nil(t, result.ArticleNodes)
+
+	// The document itself is left as parsed
+	assert.Equal(t, []string{"h1 Version 2.0", "h1 Version 1.1", "h1 Version 1.0"}, headingTexts(result.DocumentRoot))
+}
+
+func TestExtract_MultiArticleMode_SeparateOutputs(t *testing.T) {
+	result := extractMultiArticle(t, extractor.MultiArticleSeparateOutputs)
+
+	require.Len(t, result.ArticleNodes, 3)
+	assert.Equal(t, []string{"h1 Version 2.0"}, headingTexts(result.ArticleNodes[0]))
+	assert.Equal(t, []string{"h1 Version 1.1"}, headingTexts(result.ArticleNodes[1]))
+	assert.Equal(t, []string{"h1 Version 1.0"}, headingTexts(result.ArticleNodes[2]))
+	assert.Same(t, result.ArticleNodes[0], result.ContentNode)
+}
+
+func TestExtract_MultiArticleMode_ConcatenateCopiesContainerOfAllArticles(t *testing.T) {
+	htmlBytes := []byte(`<html><body><main><h1>Blog</h1>
+<article><h2>First post</h2><p>The first post has enough text to be meaningful on its own.</p></article>
+<article><h2>Second post</h2><p>The second post has enough text to be meaningful on its own.</p></article>
+</main></body></html>`)
+	params := extractor.DefaultExtractParam()
+	params.MultiArticleMode = extractor.MultiArticleConcatenate
+	ext, _ := setupExtractorWithParams(params)
+
+	result, err := ext.Extract(mustParseURL(t, "https://example.com/blog"), htmlBytes)
+
+	require.NoError(t, err)
+	assert.True(t, isElementNode(result.ContentNode, "main"))
+	assert.Nil(t, result.ContentNode.Parent)
+	assert.Equal(t, []string{"h1 Blog", "h2 First post", "h2 Second post"}, headingTexts(result.ContentNode))
+	assert.Nil(t, findElement(result.ContentNode, "article"))
+}
+
+func TestExtract_MultiArticleMode_SingleArticleUnaffected(t *testing.T) {
+	htmlBytes := loadFixture(t, "case_d_article_fallback.html")
+	for _, mode := range []extractor.MultiArticleMode{
+		extractor.MultiArticleLargest,
+		extractor.MultiArticleConcatenate,
+		extractor.MultiArticleSeparateOutputs,
+	} {
+		params := extractor.DefaultExtractParam()
+		params.MultiArticleMode = mode
+		ext, _ := setupExtractorWithParams(params)
+
+		result, err := ext.Extract(mustParseURL(t, "https://example.com/guide"), htmlBytes)
+
+		require.NoError(t, err, mode)
+		assert.True(t, isElementNode(result.ContentNode, "article"), mode)
+		assert.Nil(t, result.ArticleNodes, mode)
+	}
+}
@@ -5,9 +5,63 @@ import "golang.org/x/net/html"
 // ExtractionResult holds the extraction outcome.
 // DocumentRoot is the original parsed HTML document.
 // ContentNode is the extracted meaningful content node (semantic container).
+// StructuredSections holds the API reference blocks detected in ContentNode
+// when ExtractParam.StructuredMode is enabled; it is nil otherwise.
+// Markdown holds the content of a page published as Markdown, which skips
+// sanitization and conversion; DocumentRoot and ContentNode are nil then.
+// ArticleNodes holds the articles of a page with several of them when
+// ExtractParam.MultiArticleMode is MultiArticleSeparateOutputs, each to be
+// output as a page of its own; it is nil otherwise.
 type ExtractionResult struct {
-	DocumentRoot *html.Node
-	ContentNode  *html.Node
+	DocumentRoot       *html.Node
+	ContentNode        *html.Node
+	StructuredSections []StructuredSection
+	Markdown           []byte
+	ArticleNodes       []*html.Node
+}
+
+// StructuredSectionKind identifies the kind of a structured section.
+type StructuredSectionKind string
+
+const (
+	// StructuredSectionAPI is an API reference block: a signature followed
+	// by parameter and/or return value definitions.
+	StructuredSectionAPI StructuredSectionKind = "api"
+)
+
+// StructuredFieldKind identifies the role of a field within a structured section.
+type StructuredFieldKind string
+
+const (
+	// StructuredFieldSignature holds the code signature; Value is the code text.
+	StructuredFieldSignature StructuredFieldKind = "signature"
+	// StructuredFieldParam is one parameter; Name is the parameter name and
+	// Value its description.
+	StructuredFieldParam StructuredFieldKind = "param"
+	// StructuredFieldReturns describes the return value in Value.
+	StructuredFieldReturns StructuredFieldKind = "returns"
+	// StructuredFieldDescription is a block of prose from the section, as
+	// plain text in Value.
+	StructuredFieldDescription StructuredFieldKind = "description"
+	// StructuredFieldExample is a code block following the signature; Value
+	// is the code text.
+	StructuredFieldExample StructuredFieldKind = "example"
+)
+
+// StructuredField is one labelled value of a structured section.
+type StructuredField struct {
+	Kind  StructuredFieldKind
+	Name  string
+	Value string
+}
+
+// StructuredSection is a definition-like block detected under a heading,
+// such as a method with its signature, parameters and return value.
+// Heading is the plain text of the heading that introduces the block.
+type StructuredSection struct {
+	Kind    StructuredSectionKind
+	Heading string
+	Fields  []StructuredField
 }
 
 // ContentScoreMultiplier holds the scoring weights for content elements.
@@ -81,6 +135,13 @@ type ExtractParam struct {
 	// Used by isMeaningful to validate extracted content.
 	Threshold MeaningfulThreshold
 
+	// MinTextRatio is the minimum ratio of the selected content node's text
+	// length to the whole body's text length. Below it the page is reported
+	// as having no substantial content, which keeps index and landing pages
+	// whose chosen node is a short blurb among link lists from being written.
+	// Default: 0 (disabled)
+	MinTextRatio float64
+
 	// SelectorBlacklist contains CSS selectors for elements to remove
 	// before content extraction. Applied early in extraction pipeline,
 	// before semantic container detection (Layer 1).
@@ -88,8 +149,47 @@ type ExtractParam struct {
 	// but should still be removed (e.g., promo banners, feedback widgets).
 	// Default: empty (no blacklisted selectors)
 	SelectorBlacklist []string
+
+	// NoscriptMode controls how <noscript> content is treated before
+	// content extraction. An empty value behaves like NoscriptModeDrop.
+	// Default: NoscriptModeDrop
+	NoscriptMode NoscriptMode
+
+	// StructuredMode enables detection of repeated API reference blocks
+	// (signature + parameters + returns) in the extracted content. Detected
+	// blocks are reported in ExtractionResult.StructuredSections.
+	// Default: false
+	StructuredMode bool
+
+	// StripByRole removes elements with role="navigation", role="banner" or
+	// role="contentinfo", and elements with aria-hidden="true", before
+	// content extraction. Applied after the selector blacklist.
+	// Default: true
+	StripByRole bool
+
+	// MultiArticleMode controls how a page with several <article> elements
+	// is extracted (see articles.go). An empty value behaves like
+	// MultiArticleLargest.
+	// Default: MultiArticleLargest
+	MultiArticleMode MultiArticleMode
 }
 
+// NoscriptMode selects how <noscript> elements are handled during extraction.
+// Some sites render their real content only inside <noscript> for non-JS
+// clients, while others put tracking pixels or "enable JavaScript" notices there.
+type NoscriptMode string
+
+const (
+	// NoscriptModeDrop removes <noscript> elements and everything inside them.
+	NoscriptModeDrop NoscriptMode = "drop"
+	// NoscriptModeInline replaces <noscript> elements with their parsed
+	// children so they are treated as normal content.
+	NoscriptModeInline NoscriptMode = "inline"
+	// NoscriptModePreferWhenEmpty inlines <noscript> content only when the
+	// rest of the body is nearly empty, and drops it otherwise.
+	NoscriptModePreferWhenEmpty NoscriptMode = "preferWhenEmpty"
+)
+
 // DefaultExtractParam returns an ExtractParam with sensible default values.
 func DefaultExtractParam() ExtractParam {
 	return ExtractParam{
@@ -109,5 +209,8 @@ func DefaultExtractParam() ExtractParam {
 			MaxLinkDensity:      0.8,
 		},
 		SelectorBlacklist: []string{},
+		NoscriptMode:      NoscriptModeDrop,
+		StripByRole:       true,
+		MultiArticleMode:  MultiArticleLargest,
 	}
 }
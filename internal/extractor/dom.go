@@ -93,6 +93,9 @@ func (d *DomExtractor) Extract(
 	htmlByte []byte,
 ) (ExtractionResult, failure.ClassifiedError) {
 	result, err := d.extract(htmlByte)
+	if err == nil {
+		err = d.checkTextRatio(result)
+	}
 	if err != nil {
 		var extractionError *ExtractionError
 		errors.As(err, &extractionError)
@@ -110,6 +113,14 @@ func (d *DomExtractor) Extract(
 		)
 		return ExtractionResult{}, extractionError
 	}
+	if d.params.StructuredMode {
+		result.StructuredSections = detectStructuredSections(result.ContentNode)
+		if d.debugLogger.Enabled() {
+			d.debugLogger.LogStep(context.TODO(), "extractor", "structured_sections", debug.FieldMap{
+				"count": len(result.StructuredSections),
+			})
+		}
+	}
 	// ExtractionResult does not carry a discovered-URL count;
 	// link extraction is a downstream concern. LinksFound is 0.
 	d.metadataSink.RecordPipelineStage(
@@ -150,6 +161,16 @@ func (d *DomExtractor) extract(htmlByte []byte) (ExtractionResult, error) {
 		)
 	}
 
+	// Resolve <noscript> content before any layer scores the document
+	noscriptCount, noscriptInlined := resolveNoscript(doc, d.params.NoscriptMode, d.params.Threshold)
+	if noscriptCount > 0 && d.debugLogger.Enabled() {
+		d.debugLogger.LogStep(context.TODO(), "extractor", "noscript", debug.FieldMap{
+			"mode":    string(d.params.NoscriptMode),
+			"count":   noscriptCount,
+			"inlined": noscriptInlined,
+		})
+	}
+
 	// Layer 0: Remove blacklisted elements before any extraction
 	// This ensures noise elements are removed regardless of which layer finds content
 	if len(d.params.SelectorBlacklist) > 0 {
@@ -162,9 +183,21 @@ func (d *DomExtractor) extract(htmlByte []byte) (ExtractionResult, error) {
 		}
 	}
 
+	// Remove navigation, banner and footer landmarks by their ARIA attributes
+	if d.params.StripByRole {
+		removedCount := removeByRole(doc)
+		if d.debugLogger.Enabled() {
+			d.debugLogger.LogStep(context.TODO(), "extractor", "layer_0_roles", debug.FieldMap{
+				"removed_count": removedCount,
+			})
+		}
+	}
+
 	// Layer 1: Extract semantic container (main, article, [role="main"])
 	contentNode, selector := extractSemanticContainerWithSelector(doc, d.params.Threshold)
 	if contentNode != nil {
+		// Pages with several articles keep one, merge them or split them
+		contentNode, articleNodes := applyMultiArticleMode(doc, contentNode, d.params.MultiArticleMode, d.params.Threshold)
 		if d.debugLogger.Enabled() {
 			d.debugLogger.LogStep(context.TODO(), "extractor", "layer_1_semantic", debug.FieldMap{
 				"found":    true,
@@ -178,6 +211,7 @@ func (d *DomExtractor) extract(htmlByte []byte) (ExtractionResult, error) {
 		return ExtractionResult{
 			DocumentRoot: doc,
 			ContentNode:  contentNode,
+			ArticleNodes: articleNodes,
 		}, nil
 	}
 
@@ -252,6 +286,37 @@ func (d *DomExtractor) extract(htmlByte []byte) (ExtractionResult, error) {
 	)
 }
 
+// checkTextRatio rejects a result whose content node holds less than
+// MinTextRatio of the body's visible text.
+func (d *DomExtractor) checkTextRatio(result ExtractionResult) error {
+	if d.params.MinTextRatio <= 0 {
+		return nil
+	}
+	body := findFirstElement(result.DocumentRoot, "body")
+	if body == nil {
+		body = result.DocumentRoot
+	}
+	total := visibleTextLength(body)
+	if total == 0 {
+		return nil
+	}
+
+	ratio := float64(visibleTextLength(result.ContentNode)) / float64(total)
+	if d.debugLogger.Enabled() {
+		d.debugLogger.LogStep(context.TODO(), "extractor", "text_ratio", debug.FieldMap{
+			"ratio":     ratio,
+			"min_ratio": d.params.MinTextRatio,
+		})
+	}
+	if ratio < d.params.MinTextRatio {
+		return NewExtractionError(
+			ErrCauseNoSubstantialContent,
+			fmt.Sprintf("content holds %.2f of the page text, below the minimum of %.2f", ratio, d.params.MinTextRatio),
+		)
+	}
+	return nil
+}
+
 // isValidHTML checks if the parsed document has a proper HTML structure
 func isValidHTML(doc *html.Node) bool {
 	// Walk the tree to find <html> element
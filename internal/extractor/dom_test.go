@@ -740,3 +740,100 @@ func TestSetExtractParam(t *testing.T) {
 	}
 	assert.True(t, hasClass, "ContentNode should have class 'content'")
 }
+
+// noscriptParams returns default extraction parameters with the given noscript mode.
+func noscriptParams(mode extractor.NoscriptMode) extractor.ExtractParam {
+	params := extractor.DefaultExtractParam()
+	params.NoscriptMode = mode
+	return params
+}
+
+// hasElement checks if the subtree contains an element with the given tag
+func hasElement(node *html.Node, tag string) bool {
+	if node == nil {
+		return false
+	}
+	if isElementNode(node, tag) {
+		return true
+	}
+	for c := node.FirstChild; c != nil; c = c.NextSibling {
+		if hasElement(c, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// TestExtract_Noscript_DropIsDefault tests: real content only inside <noscript>, default mode
+// Expected: <noscript> is dropped, leaving an empty page that fails with ErrCauseNoContent
+func TestExtract_Noscript_DropIsDefault(t *testing.T) {
+	assert.Equal(t, extractor.NoscriptModeDrop, extractor.DefaultExtractParam().NoscriptMode)
+
+	ext, sink := setupExtractor()
+	sourceURL := mustParseURL(t, "https://example.com/noscript")
+	htmlBytes := loadFixture(t, "case_noscript_only_content.html")
+
+	result, err := ext.Extract(sourceURL, htmlBytes)
+
+	require.Error(t, err, "Expected extraction to fail when noscript content is dropped")
+	assert.Nil(t, result.ContentNode, "ContentNode should be nil on error")
+	require.Len(t, sink.errors, 1, "Should have recorded one error")
+}
+
+// TestExtract_Noscript_Inline tests: real content only inside <noscript>, inline mode
+// Expected: noscript children are treated as normal content and <main> is chosen
+func TestExtract_Noscript_Inline(t *testing.T) {
+	ext, _ := setupExtractorWithParams(noscriptParams(extractor.NoscriptModeInline))
+	sourceURL := mustParseURL(t, "https://example.com/noscript")
+	htmlBytes := loadFixture(t, "case_noscript_only_content.html")
+
+	result, err := ext.Extract(sourceURL, htmlBytes)
+
+	require.NoError(t, err, "Expected successful extraction")
+	assert.True(t, isElementNode(result.ContentNode, "main"), "ContentNode should be <main> element")
+	assert.True(t, hasH1Element(result.ContentNode), "Expected inlined h1 element in extracted content")
+	assert.True(t, hasElement(result.ContentNode, "article"), "Expected inlined article element")
+	assert.False(t, hasElement(result.DocumentRoot, "noscript"), "No <noscript> element should remain")
+	assert.False(t, hasElement(result.DocumentRoot, "link"), "Head <noscript> content should be dropped, not inlined")
+}
+
+// TestExtract_Noscript_PreferWhenEmpty tests: real content only inside <noscript>, preferWhenEmpty mode
+// Expected: the body is nearly empty, so noscript content is inlined and extracted
+func TestExtract_Noscript_PreferWhenEmpty(t *testing.T) {
+	ext, _ := setupExtractorWithParams(noscriptParams(extractor.NoscriptModePreferWhenEmpty))
+	sourceURL := mustParseURL(t, "https://example.com/noscript")
+	htmlBytes := loadFixture(t, "case_noscript_only_content.html")
+
+	result, err := ext.Extract(sourceURL, htmlBytes)
+
+	require.NoError(t, err, "Expected successful extraction")
+	assert.True(t, isElementNode(result.ContentNode, "main"), "ContentNode should be <main> element")
+	assert.True(t, hasH1Element(result.ContentNode), "Expected inlined h1 element in extracted content")
+}
+
+// TestExtract_Noscript_PreferWhenEmpty_BodyHasContent tests: meaningful body plus a
+// junk <noscript> notice, preferWhenEmpty mode
+// Expected: the body is not empty, so the noscript content is dropped
+func TestExtract_Noscript_PreferWhenEmpty_BodyHasContent(t *testing.T) {
+	htmlContent := `<!DOCTYPE html>
+<html>
+<body>
+<main>
+    <h1>Configuration</h1>
+    <p>The configuration file controls every aspect of the crawler behaviour.</p>
+    <noscript><div class="js-warning">Please enable JavaScript to use the search.</div></noscript>
+    <p>Each option is documented below with its default value.</p>
+</main>
+</body>
+</html>`
+
+	ext, _ := setupExtractorWithParams(noscriptParams(extractor.NoscriptModePreferWhenEmpty))
+	sourceURL := mustParseURL(t, "https://example.com/config")
+
+	result, err := ext.Extract(sourceURL, []byte(htmlContent))
+
+	require.NoError(t, err)
+	assert.True(t, isElementNode(result.ContentNode, "main"), "ContentNode should be <main> element")
+	assertElementNotExistsInNode(t, result.ContentNode, "class", "js-warning")
+	assert.False(t, hasElement(result.ContentNode, "noscript"), "No <noscript> element should remain")
+}
@@ -10,8 +10,9 @@ import (
 type ExtractionErrorCause string
 
 const (
-	ErrCauseNoContent ExtractionErrorCause = "no content"
-	ErrCauseNotHTML   ExtractionErrorCause = "not an HTML content"
+	ErrCauseNoContent            ExtractionErrorCause = "no content"
+	ErrCauseNotHTML              ExtractionErrorCause = "not an HTML content"
+	ErrCauseNoSubstantialContent ExtractionErrorCause = "no substantial content"
 )
 
 // extractionErrorClassifications provides explicit retry policy and impact level
@@ -21,12 +22,14 @@ const (
 // Classification Rationale:
 // - NoContent: Never retry - no content to extract, retrying won't help
 // - NotHTML: Never retry - content type mismatch, retrying won't help
+// - NoSubstantialContent: Never retry - the page itself holds too little content
 var extractionErrorClassifications = map[ExtractionErrorCause]struct {
 	Policy failure.RetryPolicy
 	Impact failure.ImpactLevel
 }{
-	ErrCauseNoContent: {failure.RetryPolicyNever, failure.ImpactLevelContinue},
-	ErrCauseNotHTML:   {failure.RetryPolicyNever, failure.ImpactLevelContinue},
+	ErrCauseNoContent:            {failure.RetryPolicyNever, failure.ImpactLevelContinue},
+	ErrCauseNotHTML:              {failure.RetryPolicyNever, failure.ImpactLevelContinue},
+	ErrCauseNoSubstantialContent: {failure.RetryPolicyNever, failure.ImpactLevelContinue},
 }
 
 // ExtractionError represents an error that occurred during content extraction.
@@ -90,7 +93,7 @@ func (e *ExtractionError) Impact() failure.ImpactLevel {
 // to derive control-flow decisions.
 func mapExtractionErrorToMetadataCause(err *ExtractionError) metadata.ErrorCause {
 	switch err.Cause {
-	case ErrCauseNoContent:
+	case ErrCauseNoContent, ErrCauseNoSubstantialContent:
 		return metadata.CauseContentInvalid
 	default:
 		return metadata.CauseUnknown
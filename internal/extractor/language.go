@@ -0,0 +1,77 @@
+package extractor
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+/*
+Language Detection
+
+A page declares its language on the root element (<html lang="fr-CA">) or,
+on older sites, with <meta http-equiv="Content-Language" content="fr">.
+Language reports the primary subtag of the first declaration found, lower
+cased ("fr"), so regional variants of a language are grouped together.
+Pages without a declaration, or with one that is not a language tag, have
+no detected language.
+*/
+
+// Language returns the primary language subtag declared by the document,
+// or "" when none is declared.
+func (r ExtractionResult) Language() string {
+	if r.DocumentRoot == nil {
+		return ""
+	}
+	if root := findFirstElement(r.DocumentRoot, "html"); root != nil {
+		if lang := primaryLanguageSubtag(attrValue(root, "lang")); lang != "" {
+			return lang
+		}
+	}
+	return metaContentLanguage(r.DocumentRoot)
+}
+
+// metaContentLanguage returns the primary subtag of the first
+// Content-Language meta declaration under n, or "".
+func metaContentLanguage(n *html.Node) string {
+	if n.Type == html.ElementNode && n.Data == "meta" &&
+		strings.EqualFold(attrValue(n, "http-equiv"), "content-language") {
+		if lang := primaryLanguageSubtag(attrValue(n, "content")); lang != "" {
+			return lang
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if lang := metaContentLanguage(c); lang != "" {
+			return lang
+		}
+	}
+	return ""
+}
+
+// attrValue returns the value of n's attribute key, or "".
+func attrValue(n *html.Node, key string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+// primaryLanguageSubtag returns the lower-cased primary subtag of a language
+// tag such as "en-US", or "" when tag does not start with one. Of a
+// comma-separated list, only the first tag is used.
+func primaryLanguageSubtag(tag string) string {
+	tag, _, _ = strings.Cut(tag, ",")
+	primary, _, _ := strings.Cut(strings.TrimSpace(tag), "-")
+	primary, _, _ = strings.Cut(primary, "_")
+	if len(primary) < 2 || len(primary) > 8 {
+		return ""
+	}
+	for _, c := range primary {
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') {
+			return ""
+		}
+	}
+	return strings.ToLower(primary)
+}
@@ -0,0 +1,35 @@
+package extractor_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtract_Language(t *testing.T) {
+	body := `<main><h1>Title</h1><p>This paragraph has enough text to count as meaningful documentation content.</p></main>`
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"html lang", `<html lang="fr"><body>` + body + `</body></html>`, "fr"},
+		{"regional variant", `<html lang="en-US"><body>` + body + `</body></html>`, "en"},
+		{"upper case", `<html lang="DE"><body>` + body + `</body></html>`, "de"},
+		{"meta content-language", `<html><head><meta http-equiv="Content-Language" content="pt-BR, en"></head><body>` + body + `</body></html>`, "pt"},
+		{"html lang wins over meta", `<html lang="es"><head><meta http-equiv="content-language" content="fr"></head><body>` + body + `</body></html>`, "es"},
+		{"empty lang", `<html lang=""><body>` + body + `</body></html>`, ""},
+		{"not a language tag", `<html lang="{{ lang }}"><body>` + body + `</body></html>`, ""},
+		{"undeclared", `<html><body>` + body + `</body></html>`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ext, _ := setupExtractor()
+			result, err := ext.Extract(mustParseURL(t, "https://example.com/docs"), []byte(tt.html))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result.Language())
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package extractor
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/pkg/failure"
+)
+
+// MarkdownExtractor is the Extractor for pages published as Markdown
+// (text/markdown). The body is passed through unchanged as the
+// ExtractionResult's Markdown; there is no DOM to isolate content from, and
+// links in the body are not discovered.
+type MarkdownExtractor struct {
+	metadataSink metadata.MetadataSink
+}
+
+// NewMarkdownExtractor creates a new MarkdownExtractor.
+func NewMarkdownExtractor(metadataSink metadata.MetadataSink) MarkdownExtractor {
+	return MarkdownExtractor{
+		metadataSink: metadataSink,
+	}
+}
+
+// SetExtractParam is a no-op: the DOM extraction parameters do not apply
+// to Markdown.
+func (m *MarkdownExtractor) SetExtractParam(params ExtractParam) {}
+
+// Extract returns body as the page's Markdown. A body without any
+// non-whitespace content is an ErrCauseNoContent error.
+func (m *MarkdownExtractor) Extract(
+	sourceUrl url.URL,
+	body []byte,
+) (ExtractionResult, failure.ClassifiedError) {
+	if len(bytes.TrimSpace(body)) == 0 {
+		extractionError := NewExtractionError(ErrCauseNoContent, "empty Markdown document")
+		m.metadataSink.RecordError(
+			metadata.NewErrorRecord(
+				time.Now(),
+				"extractor",
+				"MarkdownExtractor.Extract",
+				mapExtractionErrorToMetadataCause(extractionError),
+				extractionError.Error(),
+				[]metadata.Attribute{
+					metadata.NewAttr(metadata.AttrURL, fmt.Sprintf("%v", sourceUrl)),
+				},
+			),
+		)
+		return ExtractionResult{}, extractionError
+	}
+
+	m.metadataSink.RecordPipelineStage(
+		metadata.NewPipelineEvent(
+			metadata.StageExtract,
+			sourceUrl.String(),
+			true,
+			time.Now(),
+			0,
+		),
+	)
+	return ExtractionResult{Markdown: body}, nil
+}
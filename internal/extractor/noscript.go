@@ -0,0 +1,155 @@
+package extractor
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+)
+
+// resolveNoscript applies mode to every <noscript> element in doc, modifying
+// the tree in place. It returns the number of <noscript> elements found and
+// whether their content was inlined.
+//
+// <noscript> elements inside <head> are always dropped since they can only
+// carry metadata such as stylesheets or tracking pixels.
+//
+// With NoscriptModePreferWhenEmpty, content is inlined only when the body,
+// excluding <noscript>, has fewer non-whitespace characters than
+// threshold.MinNonWhitespace (at least one character is always required).
+func resolveNoscript(doc *html.Node, mode NoscriptMode, threshold MeaningfulThreshold) (int, bool) {
+	noscripts := collectNoscriptNodes(doc)
+	if len(noscripts) == 0 {
+		return 0, false
+	}
+
+	inline := false
+	switch mode {
+	case NoscriptModeInline:
+		inline = true
+	case NoscriptModePreferWhenEmpty:
+		inline = isBodyNearlyEmpty(doc, threshold)
+	}
+
+	for _, node := range noscripts {
+		if node.Parent == nil {
+			continue
+		}
+		if inline && !hasAncestor(node, "head") {
+			inlineNoscript(node)
+			continue
+		}
+		node.Parent.RemoveChild(node)
+	}
+	return len(noscripts), inline
+}
+
+// collectNoscriptNodes returns all outermost <noscript> elements in document order.
+func collectNoscriptNodes(root *html.Node) []*html.Node {
+	var nodes []*html.Node
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "noscript" {
+			nodes = append(nodes, n)
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(root)
+	return nodes
+}
+
+// inlineNoscript replaces a <noscript> element with its children.
+// The HTML parser runs with scripting enabled, so <noscript> content is kept
+// as a single raw text node; that text is parsed as a fragment in the
+// context of the element's parent before being spliced in.
+func inlineNoscript(node *html.Node) {
+	parent := node.Parent
+
+	var children []*html.Node
+	if first := node.FirstChild; first != nil && first == node.LastChild && first.Type == html.TextNode {
+		parsed, err := html.ParseFragment(strings.NewReader(first.Data), parent)
+		if err != nil {
+			parent.RemoveChild(node)
+			return
+		}
+		children = parsed
+	} else {
+		for c := node.FirstChild; c != nil; c = c.NextSibling {
+			children = append(children, c)
+		}
+	}
+
+	for _, child := range children {
+		if child.Parent != nil {
+			child.Parent.RemoveChild(child)
+		}
+		parent.InsertBefore(child, node)
+	}
+	parent.RemoveChild(node)
+}
+
+// isBodyNearlyEmpty reports whether the visible text of <body>, ignoring
+// <noscript>, <script>, <style> and <template>, is below the meaningful
+// non-whitespace threshold.
+func isBodyNearlyEmpty(doc *html.Node, threshold MeaningfulThreshold) bool {
+	body := findFirstElement(doc, "body")
+	if body == nil {
+		body = doc
+	}
+
+	return visibleTextLength(body) < max(threshold.MinNonWhitespace, 1)
+}
+
+// visibleTextLength counts the non-whitespace characters of node's text,
+// ignoring <noscript>, <script>, <style> and <template>.
+func visibleTextLength(node *html.Node) int {
+	nonWhitespace := 0
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			for _, r := range n.Data {
+				if !unicode.IsSpace(r) {
+					nonWhitespace++
+				}
+			}
+		case html.ElementNode:
+			switch n.Data {
+			case "noscript", "script", "style", "template":
+				return
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(node)
+
+	return nonWhitespace
+}
+
+// findFirstElement returns the first element with the given tag in document order.
+func findFirstElement(root *html.Node, tag string) *html.Node {
+	if root.Type == html.ElementNode && root.Data == tag {
+		return root
+	}
+	for c := root.FirstChild; c != nil; c = c.NextSibling {
+		if found := findFirstElement(c, tag); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// hasAncestor reports whether node has an ancestor element with the given tag.
+func hasAncestor(node *html.Node, tag string) bool {
+	for p := node.Parent; p != nil; p = p.Parent {
+		if p.Type == html.ElementNode && p.Data == tag {
+			return true
+		}
+	}
+	return false
+}
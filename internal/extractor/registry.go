@@ -0,0 +1,73 @@
+package extractor
+
+import (
+	"mime"
+	"strings"
+)
+
+/*
+Extractor Registry
+
+Documentation is not always published as HTML. A Registry picks the
+Extractor for a response by its media type (the Content-Type without
+parameters, matched case-insensitively), so each format gets its own
+extraction: HTML goes through the DomExtractor, Markdown is passed through
+by the MarkdownExtractor, and embedders can register their own (an OpenAPI
+renderer, say) for other types.
+
+Media types without a registered extractor, and responses without a
+Content-Type, go to the fallback extractor. Which types reach extraction
+at all is decided earlier by the fetcher's allowed content types.
+*/
+
+// Registry maps media types to the Extractor that handles them.
+type Registry struct {
+	extractors map[string]Extractor
+	fallback   Extractor
+}
+
+// NewRegistry creates a Registry that uses fallback for every media type
+// without a registered extractor.
+func NewRegistry(fallback Extractor) *Registry {
+	return &Registry{
+		extractors: make(map[string]Extractor),
+		fallback:   fallback,
+	}
+}
+
+// Register sets the extractor for mediaType, replacing any previous one.
+// A nil extractor removes the registration.
+func (r *Registry) Register(mediaType string, extractor Extractor) {
+	key := normalizeMediaType(mediaType)
+	if extractor == nil {
+		delete(r.extractors, key)
+		return
+	}
+	r.extractors[key] = extractor
+}
+
+// For returns the extractor for a response with the given Content-Type.
+func (r *Registry) For(contentType string) Extractor {
+	if extractor, ok := r.extractors[normalizeMediaType(contentType)]; ok {
+		return extractor
+	}
+	return r.fallback
+}
+
+// SetExtractParam sets params on the fallback and every registered extractor.
+func (r *Registry) SetExtractParam(params ExtractParam) {
+	r.fallback.SetExtractParam(params)
+	for _, extractor := range r.extractors {
+		extractor.SetExtractParam(params)
+	}
+}
+
+// normalizeMediaType returns the lower-case media type of a Content-Type
+// value, without parameters.
+func normalizeMediaType(contentType string) string {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mediaType
+	}
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	return strings.ToLower(strings.TrimSpace(mediaType))
+}
@@ -0,0 +1,68 @@
+package extractor_test
+
+import (
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/extractor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRegistry() (*extractor.Registry, *extractor.DomExtractor, *extractor.MarkdownExtractor) {
+	dom, _ := setupExtractor()
+	markdown := extractor.NewMarkdownExtractor(&mockMetadataSink{})
+	registry := extractor.NewRegistry(dom)
+	registry.Register("text/markdown", &markdown)
+	return registry, dom, &markdown
+}
+
+func TestRegistry_For_DispatchesByMediaType(t *testing.T) {
+	registry, dom, markdown := newTestRegistry()
+
+	assert.Same(t, markdown, registry.For("text/markdown"))
+	assert.Same(t, markdown, registry.For("Text/Markdown; charset=utf-8"))
+	assert.Same(t, dom, registry.For("text/html; charset=utf-8"))
+	assert.Same(t, dom, registry.For(""), "no Content-Type falls back to the DOM extractor")
+	assert.Same(t, dom, registry.For("application/json"))
+
+	registry.Register("text/markdown", nil)
+	assert.Same(t, dom, registry.For("text/markdown"), "a removed registration falls back")
+}
+
+func TestRegistry_MarkdownPassesThroughUnchanged(t *testing.T) {
+	registry, _, _ := newTestRegistry()
+	body := []byte("# Guide\n\nSome <b>inline</b> text and a [link](other.md).\n")
+
+	result, err := registry.For("text/markdown").Extract(mustParseURL(t, "https://example.com/guide.md"), body)
+
+	require.Nil(t, err)
+	assert.Equal(t, body, result.Markdown)
+	assert.Nil(t, result.DocumentRoot)
+	assert.Nil(t, result.ContentNode)
+}
+
+func TestRegistry_HTMLUsesDomExtraction(t *testing.T) {
+	registry, _, _ := newTestRegistry()
+	body := []byte(`<html><body><nav>Menu</nav><main><h1>Guide</h1>
+<p>This paragraph has enough words in it to count as meaningful content.</p></main></body></html>`)
+
+	result, err := registry.For("text/html").Extract(mustParseURL(t, "https://example.com/guide"), body)
+
+	require.Nil(t, err)
+	assert.Nil(t, result.Markdown)
+	require.NotNil(t, result.ContentNode)
+	assert.True(t, isElementNode(result.ContentNode, "main"))
+}
+
+func TestMarkdownExtractor_EmptyBody(t *testing.T) {
+	sink := &mockMetadataSink{}
+	markdown := extractor.NewMarkdownExtractor(sink)
+
+	_, err := markdown.Extract(mustParseURL(t, "https://example.com/empty.md"), []byte(" \n\t"))
+
+	require.NotNil(t, err)
+	var extractionErr *extractor.ExtractionError
+	require.ErrorAs(t, err, &extractionErr)
+	assert.Equal(t, extractor.ErrCauseNoContent, extractionErr.Cause)
+	assert.Len(t, sink.errors, 1)
+}
@@ -0,0 +1,85 @@
+package extractor
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+/*
+Role Stripping
+
+Class names of site chrome differ between every documentation framework,
+while ARIA attributes name the same landmarks everywhere. With
+ExtractParam.StripByRole, these elements are removed before any layer
+selects the main content:
+
+  - role="navigation": menus, breadcrumbs, version and page switchers
+  - role="banner":     the site header
+  - role="contentinfo": the site footer
+  - aria-hidden="true": decorative or duplicate elements hidden from
+    assistive technology
+
+An element that is or holds the main content (<main>, <article> or
+role="main") is kept even when it carries one of these attributes, so a
+page wrapped in a hidden container does not lose its body.
+*/
+
+// strippedRoles are the landmark roles removed by role stripping.
+var strippedRoles = map[string]bool{
+	"navigation":  true,
+	"banner":      true,
+	"contentinfo": true,
+}
+
+// removeByRole removes the landmark and aria-hidden elements from doc and
+// returns the number of elements removed.
+func removeByRole(doc *html.Node) int {
+	var nodesToRemove []*html.Node
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && isStrippedByRole(n) && !holdsMainContent(n) {
+			// Descendants go with the element
+			nodesToRemove = append(nodesToRemove, n)
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	for _, node := range nodesToRemove {
+		if node.Parent != nil {
+			node.Parent.RemoveChild(node)
+		}
+	}
+	return len(nodesToRemove)
+}
+
+// isStrippedByRole reports whether n has a stripped landmark role or is
+// hidden with aria-hidden="true". Of a role list, the first token counts,
+// as in the ARIA fallback rules.
+func isStrippedByRole(n *html.Node) bool {
+	if strings.EqualFold(strings.TrimSpace(attrValue(n, "aria-hidden")), "true") {
+		return true
+	}
+	roles := strings.Fields(strings.ToLower(attrValue(n, "role")))
+	return len(roles) > 0 && strippedRoles[roles[0]]
+}
+
+// holdsMainContent reports whether n is, or contains, a main content
+// container.
+func holdsMainContent(n *html.Node) bool {
+	if n.Type == html.ElementNode {
+		if n.Data == "main" || n.Data == "article" || strings.EqualFold(attrValue(n, "role"), "main") {
+			return true
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if holdsMainContent(c) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package extractor_test
+
+import (
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/extractor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/html"
+)
+
+func TestExtract_StripByRole_DefaultsToTrue(t *testing.T) {
+	assert.True(t, extractor.DefaultExtractParam().StripByRole)
+}
+
+func TestExtract_StripByRole_RemovesLandmarksAndHiddenElements(t *testing.T) {
+	sourceURL := mustParseURL(t, "https://example.com/guide/configure")
+	htmlBytes := loadFixture(t, "case_aria_roles.html")
+
+	ext, _ := setupExtractor()
+	result, err := ext.Extract(sourceURL, htmlBytes)
+
+	require.NoError(t, err)
+	require.NotNil(t, result.ContentNode)
+
+	// role="main" content is selected and kept
+	assert.Contains(t, result.ContentNode.Attr, html.Attribute{Key: "class", Val: "page-body"})
+	assert.True(t, hasH1Element(result.ContentNode))
+	assertElementExistsInNode(t, result.ContentNode, "class", "edit-link")
+
+	// Navigation inside the content and hidden widgets are removed
+	assertElementNotExistsInNode(t, result.ContentNode, "class", "breadcrumbs")
+	assertElementNotExistsInNode(t, result.ContentNode, "class", "version-switcher")
+	assertElementNotExistsInNode(t, result.ContentNode, "aria-hidden", "true")
+
+	// Banner, sidebar navigation and footer are gone from the document
+	assertElementNotExistsInNode(t, result.DocumentRoot, "class", "site-header")
+	assertElementNotExistsInNode(t, result.DocumentRoot, "class", "sidebar-menu")
+	assertElementNotExistsInNode(t, result.DocumentRoot, "class", "site-footer")
+}
+
+func TestExtract_StripByRole_Disabled(t *testing.T) {
+	sourceURL := mustParseURL(t, "https://example.com/guide/configure")
+	htmlBytes := loadFixture(t, "case_aria_roles.html")
+
+	params := extractor.DefaultExtractParam()
+	params.StripByRole = false
+	ext, _ := setupExtractorWithParams(params)
+	result, err := ext.Extract(sourceURL, htmlBytes)
+
+	require.NoError(t, err)
+	assertElementExistsInNode(t, result.ContentNode, "class", "breadcrumbs")
+	assertElementExistsInNode(t, result.ContentNode, "class", "version-switcher")
+}
+
+func TestExtract_StripByRole_KeepsHiddenContainerOfMain(t *testing.T) {
+	htmlContent := `<!DOCTYPE html>
+<html>
+<body>
+<div aria-hidden="true" class="app-root">
+    <nav role="navigation" class="top-nav"><a href="/">Home</a></nav>
+    <main>
+        <h1>Getting Started</h1>
+        <p>This page stays even though a modal hid the whole application container.</p>
+        <p>Hidden containers of the main content are never removed by role stripping.</p>
+    </main>
+</div>
+</body>
+</html>`
+
+	ext, _ := setupExtractor()
+	result, err := ext.Extract(mustParseURL(t, "https://example.com/start"), []byte(htmlContent))
+
+	require.NoError(t, err)
+	assert.Equal(t, "main", result.ContentNode.Data)
+	assert.True(t, hasH1Element(result.ContentNode))
+	assertElementNotExistsInNode(t, result.DocumentRoot, "class", "top-nav")
+}
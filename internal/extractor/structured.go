@@ -0,0 +1,380 @@
+package extractor
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+/*
+Structured Sections
+
+API references repeat the same block shape for every documented member:
+
+	<h3>get</h3>
+	<pre><code>get(key string) (Value, error)</code></pre>
+	<p><strong>Parameters</strong></p>
+	<ul><li><code>key</code> – the lookup key</li></ul>
+	<p><strong>Returns:</strong> the stored value</p>
+
+After conversion these blocks lose their shape, so with
+ExtractParam.StructuredMode the extractor also reports them as
+StructuredSection values that normalize renders consistently.
+
+A heading's block is its following siblings up to the next heading of the
+same or higher level. A block is definition-like when it has a signature
+(the first <pre> in the block) and at least one parameter or return value:
+- parameters come from the list, definition list or table that follows a
+  "Parameters" / "Arguments" label
+- the return value is the text after a "Returns" label, either on the
+  label's own line ("Returns: ...") or in the next element
+- definition lists whose terms are these labels (Sphinx-style field lists)
+  are read pairwise
+- further code blocks become examples, and any other element becomes a
+  description holding its plain text, so the block can be re-rendered
+  without losing content
+
+Only repeated shapes are reported: at least minStructuredRepeat
+definition-like blocks must share a heading level, so a lone example with a
+parameter list in prose documentation is left alone.
+*/
+
+// minStructuredRepeat is the number of definition-like blocks at one heading
+// level required before any of them is reported.
+const minStructuredRepeat = 2
+
+// structuredLabel is the field a label element introduces.
+type structuredLabel int
+
+const (
+	labelNone structuredLabel = iota
+	labelParams
+	labelReturns
+)
+
+// detectStructuredSections returns the repeated API reference blocks under
+// content in document order.
+func detectStructuredSections(content *html.Node) []StructuredSection {
+	type candidate struct {
+		level   int
+		section StructuredSection
+	}
+	var candidates []candidate
+	perLevel := make(map[int]int)
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if level := headingLevel(n); level >= 2 {
+			if section, ok := parseStructuredBlock(n, level); ok {
+				candidates = append(candidates, candidate{level: level, section: section})
+				perLevel[level]++
+			}
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(content)
+
+	var sections []StructuredSection
+	for _, c := range candidates {
+		if perLevel[c.level] >= minStructuredRepeat {
+			sections = append(sections, c.section)
+		}
+	}
+	return sections
+}
+
+// parseStructuredBlock reads the block introduced by heading and reports
+// whether it is definition-like.
+func parseStructuredBlock(heading *html.Node, level int) (StructuredSection, bool) {
+	section := StructuredSection{
+		Kind:    StructuredSectionAPI,
+		Heading: collapseSpace(nodeText(heading)),
+	}
+	hasSignature, hasDefinition := false, false
+	add := func(fields ...StructuredField) {
+		for _, f := range fields {
+			if f.Kind == StructuredFieldParam || f.Kind == StructuredFieldReturns {
+				hasDefinition = true
+			}
+			section.Fields = append(section.Fields, f)
+		}
+	}
+
+	pending := labelNone
+	for n := heading.NextSibling; n != nil; n = n.NextSibling {
+		if n.Type != html.ElementNode {
+			continue
+		}
+		if l := headingLevel(n); l > 0 && l <= level {
+			break
+		}
+
+		if label, rest := matchStructuredLabel(n); label != labelNone {
+			pending = label
+			if label == labelReturns && rest != "" {
+				add(StructuredField{Kind: StructuredFieldReturns, Value: rest})
+				pending = labelNone
+			}
+			continue
+		}
+
+		if n.Data == "dl" && isFieldList(n) {
+			add(parseFieldList(n)...)
+			pending = labelNone
+			continue
+		}
+
+		switch pending {
+		case labelParams:
+			add(parseParamFields(n)...)
+			pending = labelNone
+			continue
+		case labelReturns:
+			if text := collapseSpace(nodeText(n)); text != "" {
+				add(StructuredField{Kind: StructuredFieldReturns, Value: text})
+			}
+			pending = labelNone
+			continue
+		}
+
+		if pre := findFirstElement(n, "pre"); pre != nil {
+			code := strings.Trim(nodeText(pre), "\n")
+			if hasSignature {
+				add(StructuredField{Kind: StructuredFieldExample, Value: code})
+				continue
+			}
+			// The signature leads the fields regardless of where it appears
+			signature := StructuredField{Kind: StructuredFieldSignature, Value: strings.TrimSpace(code)}
+			section.Fields = append([]StructuredField{signature}, section.Fields...)
+			hasSignature = true
+			continue
+		}
+		if text := collapseSpace(nodeText(n)); text != "" {
+			add(StructuredField{Kind: StructuredFieldDescription, Value: text})
+		}
+	}
+
+	return section, hasSignature && hasDefinition
+}
+
+// matchStructuredLabel reports whether n is a "Parameters" or "Returns"
+// label. For "Returns: text" on one line, rest holds the text after the colon.
+func matchStructuredLabel(n *html.Node) (structuredLabel, string) {
+	switch n.Data {
+	case "p", "div", "span", "strong", "b", "dt", "h4", "h5", "h6":
+	default:
+		return labelNone, ""
+	}
+	text := collapseSpace(nodeText(n))
+	name, rest, hasColon := strings.Cut(text, ":")
+	name = strings.ToLower(strings.TrimSpace(name))
+	rest = strings.TrimSpace(rest)
+
+	switch name {
+	case "parameters", "params", "arguments", "args":
+		if rest == "" {
+			return labelParams, ""
+		}
+	case "returns", "return", "return value":
+		if rest == "" || hasColon {
+			return labelReturns, rest
+		}
+	}
+	return labelNone, ""
+}
+
+// isFieldList reports whether dl is a field list whose terms are labels.
+func isFieldList(dl *html.Node) bool {
+	for c := dl.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && c.Data == "dt" {
+			label, _ := matchStructuredLabel(c)
+			return label != labelNone
+		}
+	}
+	return false
+}
+
+// parseFieldList reads a definition list whose terms are labels and whose
+// definitions hold the parameters or the return value.
+func parseFieldList(dl *html.Node) []StructuredField {
+	var fields []StructuredField
+	pending := labelNone
+	for c := dl.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		switch c.Data {
+		case "dt":
+			pending, _ = matchStructuredLabel(c)
+		case "dd":
+			switch pending {
+			case labelParams:
+				params := parseParamFields(c)
+				if len(params) == 0 {
+					params = []StructuredField{parseParamItem(c)}
+				}
+				fields = append(fields, params...)
+			case labelReturns:
+				if text := collapseSpace(nodeText(c)); text != "" {
+					fields = append(fields, StructuredField{Kind: StructuredFieldReturns, Value: text})
+				}
+			}
+			pending = labelNone
+		}
+	}
+	return fields
+}
+
+// parseParamFields reads parameters from a list, definition list or table,
+// looking through wrapper elements to the first one found.
+func parseParamFields(n *html.Node) []StructuredField {
+	var fields []StructuredField
+	switch n.Data {
+	case "ul", "ol":
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode && c.Data == "li" {
+				fields = append(fields, parseParamItem(c))
+			}
+		}
+		return fields
+	case "dl":
+		name := ""
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			switch c.Data {
+			case "dt":
+				name = collapseSpace(nodeText(c))
+			case "dd":
+				fields = append(fields, StructuredField{
+					Kind:  StructuredFieldParam,
+					Name:  name,
+					Value: collapseSpace(nodeText(c)),
+				})
+			}
+		}
+		return fields
+	case "table":
+		// The first cell names the parameter; the others describe it
+		for _, row := range collectElements(n, "tr") {
+			cells := collectElements(row, "td")
+			if len(cells) == 0 {
+				continue
+			}
+			var rest []string
+			for _, cell := range cells[1:] {
+				if text := collapseSpace(nodeText(cell)); text != "" {
+					rest = append(rest, text)
+				}
+			}
+			fields = append(fields, StructuredField{
+				Kind:  StructuredFieldParam,
+				Name:  collapseSpace(nodeText(cells[0])),
+				Value: strings.Join(rest, " – "),
+			})
+		}
+		return fields
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		if fields = parseParamFields(c); len(fields) > 0 {
+			return fields
+		}
+	}
+	return nil
+}
+
+// parseParamItem splits one parameter entry into name and description. A
+// leading <code>, <strong> or <b> names the parameter; otherwise the text is
+// split at the first dash or colon separator.
+func parseParamItem(n *html.Node) StructuredField {
+	field := StructuredField{Kind: StructuredFieldParam}
+	text := collapseSpace(nodeText(n))
+
+	if first := firstMeaningfulChild(n); first != nil && first.Type == html.ElementNode {
+		switch first.Data {
+		case "code", "strong", "b":
+			field.Name = collapseSpace(nodeText(first))
+			rest := strings.TrimPrefix(text, field.Name)
+			field.Value = strings.TrimSpace(strings.TrimLeft(rest, " :–—-"))
+			return field
+		}
+	}
+
+	for _, sep := range []string{" – ", " — ", " - ", ": "} {
+		if name, value, ok := strings.Cut(text, sep); ok {
+			field.Name = strings.TrimSpace(name)
+			field.Value = strings.TrimSpace(value)
+			return field
+		}
+	}
+	field.Name = text
+	return field
+}
+
+// firstMeaningfulChild returns the first child that is an element or
+// non-whitespace text.
+func firstMeaningfulChild(n *html.Node) *html.Node {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode || (c.Type == html.TextNode && strings.TrimSpace(c.Data) != "") {
+			return c
+		}
+	}
+	return nil
+}
+
+// headingLevel returns 1-6 for <h1>-<h6> elements and 0 otherwise.
+func headingLevel(n *html.Node) int {
+	if n.Type != html.ElementNode || len(n.Data) != 2 || n.Data[0] != 'h' {
+		return 0
+	}
+	if level := int(n.Data[1] - '0'); level >= 1 && level <= 6 {
+		return level
+	}
+	return 0
+}
+
+// collectElements returns all descendants of root with the given tag, in
+// document order.
+func collectElements(root *html.Node, tag string) []*html.Node {
+	var nodes []*html.Node
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode && c.Data == tag {
+				nodes = append(nodes, c)
+			}
+			walk(c)
+		}
+	}
+	walk(root)
+	return nodes
+}
+
+// nodeText concatenates the text of all descendants of n.
+func nodeText(n *html.Node) string {
+	var sb strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return sb.String()
+}
+
+// collapseSpace trims s and replaces runs of whitespace with single spaces.
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
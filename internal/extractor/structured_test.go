@@ -0,0 +1,107 @@
+package extractor_test
+
+import (
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/extractor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func structuredParams() extractor.ExtractParam {
+	params := extractor.DefaultExtractParam()
+	params.StructuredMode = true
+	return params
+}
+
+// countFields counts the fields of section with the given kind.
+func countFields(section extractor.StructuredSection, kind extractor.StructuredFieldKind) int {
+	count := 0
+	for _, f := range section.Fields {
+		if f.Kind == kind {
+			count++
+		}
+	}
+	return count
+}
+
+func TestExtract_StructuredMode_DetectsAPIReferenceBlocks(t *testing.T) {
+	ext, _ := setupExtractorWithParams(structuredParams())
+	sourceURL := mustParseURL(t, "https://example.com/api/cache")
+
+	result, err := ext.Extract(sourceURL, loadFixture(t, "case_api_reference.html"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		heading      string
+		params       int
+		returns      int
+		descriptions int
+	}{
+		{heading: "Get", params: 2, returns: 1, descriptions: 1},
+		{heading: "Set", params: 3, returns: 1, descriptions: 1},
+		{heading: "Delete", params: 1, returns: 1, descriptions: 0},
+	}
+	require.Len(t, result.StructuredSections, len(tests))
+	for i, tt := range tests {
+		section := result.StructuredSections[i]
+		assert.Equal(t, extractor.StructuredSectionAPI, section.Kind)
+		assert.Equal(t, tt.heading, section.Heading)
+		assert.Equal(t, 1, countFields(section, extractor.StructuredFieldSignature), tt.heading)
+		assert.Equal(t, tt.params, countFields(section, extractor.StructuredFieldParam), tt.heading)
+		assert.Equal(t, tt.returns, countFields(section, extractor.StructuredFieldReturns), tt.heading)
+		assert.Equal(t, tt.descriptions, countFields(section, extractor.StructuredFieldDescription), tt.heading)
+		assert.Len(t, section.Fields, 1+tt.params+tt.returns+tt.descriptions, tt.heading)
+	}
+}
+
+func TestExtract_StructuredMode_ReadsFieldValues(t *testing.T) {
+	ext, _ := setupExtractorWithParams(structuredParams())
+	sourceURL := mustParseURL(t, "https://example.com/api/cache")
+
+	result, err := ext.Extract(sourceURL, loadFixture(t, "case_api_reference.html"))
+	require.NoError(t, err)
+	require.NotEmpty(t, result.StructuredSections)
+
+	get := result.StructuredSections[0]
+	assert.Equal(t, []extractor.StructuredField{
+		{Kind: extractor.StructuredFieldSignature, Value: "func (c *Client) Get(ctx context.Context, key string) (Value, error)"},
+		{Kind: extractor.StructuredFieldDescription, Value: "Looks up a value."},
+		{Kind: extractor.StructuredFieldParam, Name: "ctx", Value: "controls cancellation of the lookup"},
+		{Kind: extractor.StructuredFieldParam, Name: "key", Value: "the key to look up"},
+		{Kind: extractor.StructuredFieldReturns, Value: "the stored value, or ErrNotFound."},
+	}, get.Fields)
+
+	set := result.StructuredSections[1]
+	assert.Contains(t, set.Fields, extractor.StructuredField{
+		Kind: extractor.StructuredFieldParam, Name: "ttl", Value: "time.Duration – expiry; zero keeps the value forever",
+	})
+}
+
+func TestExtract_StructuredMode_IgnoresSingleBlock(t *testing.T) {
+	ext, _ := setupExtractorWithParams(structuredParams())
+	sourceURL := mustParseURL(t, "https://example.com/guide")
+	htmlBytes := []byte(`<html><body><main>
+		<h1>Guide</h1>
+		<p>This guide walks through configuring the client for the first time.</p>
+		<h2>Connect</h2>
+		<pre><code>client.Connect(addr)</code></pre>
+		<p><strong>Parameters</strong></p>
+		<ul><li><code>addr</code> – the server address</li></ul>
+	</main></body></html>`)
+
+	result, err := ext.Extract(sourceURL, htmlBytes)
+
+	require.NoError(t, err)
+	assert.Empty(t, result.StructuredSections)
+}
+
+func TestExtract_StructuredModeDisabled_ReportsNoSections(t *testing.T) {
+	ext, _ := setupExtractor()
+	sourceURL := mustParseURL(t, "https://example.com/api/cache")
+
+	result, err := ext.Extract(sourceURL, loadFixture(t, "case_api_reference.html"))
+
+	require.NoError(t, err)
+	assert.Nil(t, result.StructuredSections)
+}
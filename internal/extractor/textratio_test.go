@@ -0,0 +1,66 @@
+package extractor_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/extractor"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func minTextRatioParams(ratio float64) extractor.ExtractParam {
+	params := extractor.DefaultExtractParam()
+	params.MinTextRatio = ratio
+	return params
+}
+
+func TestExtract_MinTextRatio_RejectsLinkFarmLandingPage(t *testing.T) {
+	ext, sink := setupExtractorWithParams(minTextRatioParams(0.3))
+	sourceURL := mustParseURL(t, "https://example.com/docs/")
+
+	result, err := ext.Extract(sourceURL, loadFixture(t, "case_link_farm_landing.html"))
+
+	require.Error(t, err)
+	var extractionErr *extractor.ExtractionError
+	require.True(t, errors.As(err, &extractionErr))
+	assert.Equal(t, extractor.ErrCauseNoSubstantialContent, extractionErr.Cause)
+	assert.Nil(t, result.ContentNode)
+
+	require.Len(t, sink.errors, 1, "the rejection should be recorded")
+	assert.Equal(t, metadata.ErrorCause(metadata.CauseContentInvalid), sink.errors[0].Cause())
+}
+
+func TestExtract_MinTextRatio_ExtractsArticle(t *testing.T) {
+	ext, sink := setupExtractorWithParams(minTextRatioParams(0.3))
+	sourceURL := mustParseURL(t, "https://example.com/docs/guide")
+
+	result, err := ext.Extract(sourceURL, loadFixture(t, "case_a_main_valid.html"))
+
+	require.NoError(t, err)
+	assert.True(t, isElementNode(result.ContentNode, "main"))
+	assert.Empty(t, sink.errors)
+}
+
+func TestExtract_MinTextRatio_ThresholdGovernsDecision(t *testing.T) {
+	tests := []struct {
+		name    string
+		ratio   float64
+		wantErr bool
+	}{
+		{name: "disabled", ratio: 0, wantErr: false},
+		{name: "below the page's ratio", ratio: 0.01, wantErr: false},
+		{name: "above the page's ratio", ratio: 0.3, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ext, _ := setupExtractorWithParams(minTextRatioParams(tt.ratio))
+			sourceURL := mustParseURL(t, "https://example.com/docs/")
+
+			_, err := ext.Extract(sourceURL, loadFixture(t, "case_link_farm_landing.html"))
+			assert.Equal(t, tt.wantErr, err != nil, "err = %v", err)
+		})
+	}
+}
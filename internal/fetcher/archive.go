@@ -0,0 +1,182 @@
+package fetcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/pkg/hashutil"
+)
+
+/*
+Replay Archive
+
+A replay archive is a directory of saved responses keyed by URL, so a crawl
+can be replayed offline, e.g. to test extraction changes without hitting the
+site again. Each response is stored as two files named after the SHA-256 of
+its URL:
+
+	<url_hash>.json   {"url": "...", "status": 200, "headers": {"Content-Type": ["text/html"]}}
+	<url_hash>.body   the raw response body
+
+The URL is the exact string requested, so pages must be archived under their
+canonical URL. A URL missing from the archive is answered with 404 Not Found,
+which robots.txt treats as "no rules" and page fetches as a client error.
+Redirects are replayed like any other response: an archived 3xx with a
+Location header is followed by the HTTP client.
+
+Responses are served through an http.RoundTripper, so status, content type,
+size and redirect handling are exactly those of a live fetch.
+*/
+
+// ArchiveEntry is one saved response of a replay archive.
+type ArchiveEntry struct {
+	URL     string
+	Status  int
+	Headers http.Header
+	Body    []byte
+}
+
+type archiveEntryDTO struct {
+	URL     string      `json:"url"`
+	Status  int         `json:"status"`
+	Headers http.Header `json:"headers,omitempty"`
+}
+
+// archiveKey returns the file name stem under which url is archived.
+func archiveKey(url string) string {
+	key, _ := hashutil.HashBytes([]byte(url), hashutil.HashAlgoSHA256)
+	return key
+}
+
+// WriteArchiveEntry saves entry to the archive in dir, replacing any entry
+// for the same URL. The directory is created if needed.
+func WriteArchiveEntry(dir string, entry ArchiveEntry) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(archiveEntryDTO{
+		URL:     entry.URL,
+		Status:  entry.Status,
+		Headers: entry.Headers,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode archive entry for %s: %w", entry.URL, err)
+	}
+
+	stem := filepath.Join(dir, archiveKey(entry.URL))
+	if err := os.WriteFile(stem+".json", append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write archive entry for %s: %w", entry.URL, err)
+	}
+	if err := os.WriteFile(stem+".body", entry.Body, 0644); err != nil {
+		return fmt.Errorf("failed to write archive body for %s: %w", entry.URL, err)
+	}
+	return nil
+}
+
+// ReadArchiveEntry loads the entry archived for url from dir. It returns an
+// error wrapping fs.ErrNotExist when the URL is not archived.
+func ReadArchiveEntry(dir string, url string) (ArchiveEntry, error) {
+	stem := filepath.Join(dir, archiveKey(url))
+	data, err := os.ReadFile(stem + ".json")
+	if err != nil {
+		return ArchiveEntry{}, fmt.Errorf("failed to read archive entry for %s: %w", url, err)
+	}
+
+	var dto archiveEntryDTO
+	if err := json.Unmarshal(data, &dto); err != nil {
+		return ArchiveEntry{}, fmt.Errorf("failed to decode archive entry for %s: %w", url, err)
+	}
+
+	body, err := os.ReadFile(stem + ".body")
+	if err != nil {
+		return ArchiveEntry{}, fmt.Errorf("failed to read archive body for %s: %w", url, err)
+	}
+
+	return ArchiveEntry{
+		URL:     dto.URL,
+		Status:  dto.Status,
+		Headers: dto.Headers,
+		Body:    body,
+	}, nil
+}
+
+// ArchiveTransport is an http.RoundTripper answering requests from the
+// replay archive in dir instead of the network.
+type ArchiveTransport struct {
+	dir string
+}
+
+func NewArchiveTransport(dir string) *ArchiveTransport {
+	return &ArchiveTransport{dir: dir}
+}
+
+// RoundTrip serves the archived response for req.URL, or 404 Not Found when
+// the URL is not archived. HEAD requests get the headers without the body.
+func (t *ArchiveTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	entry, err := ReadArchiveEntry(t.dir, req.URL.String())
+	if errors.Is(err, fs.ErrNotExist) {
+		entry = ArchiveEntry{Status: http.StatusNotFound}
+	} else if err != nil {
+		return nil, err
+	}
+
+	headers := entry.Headers.Clone()
+	if headers == nil {
+		headers = http.Header{}
+	}
+	body := entry.Body
+	if req.Method == http.MethodHead {
+		body = nil
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", entry.Status, http.StatusText(entry.Status)),
+		StatusCode:    entry.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        headers,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(entry.Body)),
+		Request:       req,
+	}, nil
+}
+
+// ArchiveFetcher is a Fetcher that replays pages from a replay archive. It
+// is an HtmlFetcher whose HTTP client reads from an ArchiveTransport, so
+// archived responses go through the same classification, size limits and
+// metadata recording as live ones.
+type ArchiveFetcher struct {
+	HtmlFetcher
+	archiveDir string
+}
+
+func NewArchiveFetcher(
+	metadataSink metadata.MetadataSink,
+	archiveDir string,
+) ArchiveFetcher {
+	return ArchiveFetcher{
+		HtmlFetcher: NewHtmlFetcher(metadataSink),
+		archiveDir:  archiveDir,
+	}
+}
+
+// Init initializes the fetcher with a copy of httpClient whose transport
+// reads from the archive. Redirect policy and timeout are kept.
+func (a *ArchiveFetcher) Init(httpClient *http.Client, userAgent string) {
+	client := &http.Client{}
+	if httpClient != nil {
+		*client = *httpClient
+	}
+	client.Transport = NewArchiveTransport(a.archiveDir)
+	a.HtmlFetcher.Init(client, userAgent)
+}
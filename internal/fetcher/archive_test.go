@@ -0,0 +1,79 @@
+package fetcher_test
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+)
+
+func TestArchiveEntry_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	entry := fetcher.ArchiveEntry{
+		URL:     "https://docs.example.com/guide?lang=en",
+		Status:  http.StatusOK,
+		Headers: http.Header{"Content-Type": {"text/html"}, "Etag": {`"v1"`}},
+		Body:    []byte("<html><body>\x00binary-safe</body></html>"),
+	}
+	if err := fetcher.WriteArchiveEntry(dir, entry); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	got, err := fetcher.ReadArchiveEntry(dir, entry.URL)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if got.URL != entry.URL || got.Status != entry.Status || string(got.Body) != string(entry.Body) {
+		t.Errorf("expected %+v, got %+v", entry, got)
+	}
+	if got.Headers.Get("ETag") != `"v1"` {
+		t.Errorf("expected ETag to round-trip, got %q", got.Headers.Get("ETag"))
+	}
+
+	if _, err := fetcher.ReadArchiveEntry(dir, "https://docs.example.com/other"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected fs.ErrNotExist for an unarchived URL, got: %v", err)
+	}
+}
+
+func TestArchiveFetcher_Fetch(t *testing.T) {
+	dir := t.TempDir()
+	pageURL, _ := url.Parse("https://docs.example.com/guide")
+	if err := fetcher.WriteArchiveEntry(dir, fetcher.ArchiveEntry{
+		URL:     pageURL.String(),
+		Status:  http.StatusOK,
+		Headers: http.Header{"Content-Type": {"text/html; charset=utf-8"}},
+		Body:    []byte("<html><body>Archived</body></html>"),
+	}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	sink := &mockMetadataSink{}
+	f := fetcher.NewArchiveFetcher(sink, dir)
+	f.Init(&http.Client{}, "test-user-agent")
+
+	result, err := f.Fetch(context.Background(), 0, *pageURL, createTestRetryOptions(1))
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if string(result.Body()) != "<html><body>Archived</body></html>" {
+		t.Errorf("expected the archived body, got %q", result.Body())
+	}
+	if result.Code() != http.StatusOK || result.Header("Content-Type") != "text/html; charset=utf-8" {
+		t.Errorf("expected archived status and headers, got %d %q", result.Code(), result.Header("Content-Type"))
+	}
+	if len(sink.FetchEvents) != 1 || sink.FetchEvents[0].HTTPStatus() != http.StatusOK {
+		t.Errorf("expected one recorded fetch with status 200, got %+v", sink.FetchEvents)
+	}
+
+	// A URL missing from the archive fails like a live 404
+	missingURL, _ := url.Parse("https://docs.example.com/missing")
+	_, err = f.Fetch(context.Background(), 0, *missingURL, createTestRetryOptions(1))
+	var fetchErr *fetcher.FetchError
+	if !errors.As(err, &fetchErr) || fetchErr.Cause != fetcher.ErrCauseRequestPageForbidden {
+		t.Errorf("expected a client error for an unarchived URL, got: %v", err)
+	}
+}
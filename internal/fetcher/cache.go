@@ -0,0 +1,285 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/pkg/failure"
+	"github.com/rohmanhakim/docs-crawler/pkg/urlutil"
+	"github.com/rohmanhakim/retrier"
+)
+
+/*
+Fetch Cache
+
+The fetch cache keeps successful page responses on disk so repeated runs
+over the same site, e.g. while iterating on extraction, do not hit the
+network again. Entries are stored like replay archive entries, named after
+the SHA-256 of the page's canonical URL:
+
+	<url_hash>.json   {"url": "...", "status": 200, "headers": {...}, "fetchedAt": "..."}
+	<url_hash>.body   the raw response body
+
+An entry is served until its TTL has passed since it was fetched. Failed
+fetches and responses marked Cache-Control: no-store are never cached. A
+cache hit is recorded as a fetch event marked CacheHit, with no HTTP call.
+
+In offline-only mode (SetOfflineOnly) the wrapped fetcher is never called:
+cached entries are served whatever their age, and a page missing from the
+cache fails with a non-retryable FetchError matching ErrCacheMiss.
+*/
+
+type cacheEntryDTO struct {
+	URL           string      `json:"url"`
+	FinalURL      string      `json:"finalUrl,omitempty"`
+	RedirectChain []string    `json:"redirectChain,omitempty"`
+	Status        int         `json:"status"`
+	Headers       http.Header `json:"headers,omitempty"`
+	FetchedAt     time.Time   `json:"fetchedAt"`
+}
+
+// CachingFetcher is a Fetcher serving pages from a disk cache in dir,
+// falling back to the wrapped fetcher on a miss or an expired entry.
+type CachingFetcher struct {
+	inner        Fetcher
+	metadataSink metadata.MetadataSink
+	dir          string
+	ttl          time.Duration
+	offlineOnly  bool
+	now          func() time.Time
+}
+
+// NewCachingFetcher wraps inner with the fetch cache in dir. Cached
+// responses are served for ttl after they were fetched; 0 means forever.
+func NewCachingFetcher(
+	inner Fetcher,
+	metadataSink metadata.MetadataSink,
+	dir string,
+	ttl time.Duration,
+) CachingFetcher {
+	return CachingFetcher{
+		inner:        inner,
+		metadataSink: metadataSink,
+		dir:          dir,
+		ttl:          ttl,
+		now:          time.Now,
+	}
+}
+
+// Init initializes the wrapped fetcher.
+func (c *CachingFetcher) Init(httpClient *http.Client, userAgent string) {
+	c.inner.Init(httpClient, userAgent)
+}
+
+// SetFetchParam sets the fetch param of the wrapped fetcher.
+func (c *CachingFetcher) SetFetchParam(param FetchParam) {
+	c.inner.SetFetchParam(param)
+}
+
+// SetClock sets the time source used to stamp and expire entries.
+// If now is nil, time.Now is used.
+func (c *CachingFetcher) SetClock(now func() time.Time) {
+	if now == nil {
+		now = time.Now
+	}
+	c.now = now
+}
+
+// SetOfflineOnly sets whether pages are served from the cache only, without
+// ever fetching them through the wrapped fetcher.
+func (c *CachingFetcher) SetOfflineOnly(offlineOnly bool) {
+	c.offlineOnly = offlineOnly
+}
+
+// Fetch serves fetchUrl from the cache when a fresh entry exists, and
+// otherwise fetches it through the wrapped fetcher and caches the result.
+// In offline-only mode a missing entry fails with ErrCacheMiss instead.
+func (c *CachingFetcher) Fetch(
+	ctx context.Context,
+	crawlDepth int,
+	fetchUrl url.URL,
+	retryOptions []retrier.RetryOption,
+) (FetchResult, failure.ClassifiedError) {
+	// Pages are fetched at their canonical URL already, so any query and
+	// trailing slash the crawl kept are part of the key
+	canonicalURL := urlutil.ApplyTrailingSlash(
+		urlutil.CanonicalizeKeepingQuery(fetchUrl, keepAllQuery),
+		fetchUrl,
+		urlutil.TrailingSlashPreserve,
+	)
+	key := canonicalURL.String()
+
+	if result, ok := c.lookup(key, fetchUrl); ok {
+		c.metadataSink.RecordFetch(metadata.NewFetchEvent(
+			c.now(),
+			fetchUrl.String(),
+			result.Code(),
+			0,
+			result.Header("Content-Type"),
+			0,
+			crawlDepth,
+			metadata.KindPage,
+		).WithCacheHit())
+		return result, nil
+	}
+	if c.offlineOnly {
+		missErr := NewFetchError(
+			ErrCauseCacheMiss,
+			fmt.Sprintf("%s is not in the fetch cache", key),
+		)
+		c.metadataSink.RecordError(metadata.NewErrorRecord(
+			c.now(),
+			"fetcher",
+			"CachingFetcher.Fetch",
+			metadata.CauseUnknown,
+			missErr.Error(),
+			[]metadata.Attribute{
+				metadata.NewAttr(metadata.AttrURL, fetchUrl.String()),
+			},
+		))
+		return FetchResult{}, missErr
+	}
+
+	result, err := c.inner.Fetch(ctx, crawlDepth, fetchUrl, retryOptions)
+	if err != nil {
+		return result, err
+	}
+	if isNoStore(result.Header("Cache-Control")) {
+		return result, nil
+	}
+	if storeErr := c.store(key, result); storeErr != nil {
+		// The cache is best-effort: the fetched page is still returned
+		c.metadataSink.RecordError(metadata.NewErrorRecord(
+			time.Now(),
+			"fetcher",
+			"CachingFetcher.Fetch",
+			metadata.CauseStorageFailure,
+			storeErr.Error(),
+			[]metadata.Attribute{
+				metadata.NewAttr(metadata.AttrURL, fetchUrl.String()),
+			},
+		))
+	}
+	return result, nil
+}
+
+// lookup returns the cached response for key when it exists and has not
+// expired, or has expired in offline-only mode. Unreadable entries are
+// treated as misses.
+func (c *CachingFetcher) lookup(key string, fetchUrl url.URL) (FetchResult, bool) {
+	stem := filepath.Join(c.dir, archiveKey(key))
+	data, err := os.ReadFile(stem + ".json")
+	if err != nil {
+		return FetchResult{}, false
+	}
+	var dto cacheEntryDTO
+	if err := json.Unmarshal(data, &dto); err != nil {
+		return FetchResult{}, false
+	}
+	if c.ttl > 0 && !c.offlineOnly && c.now().Sub(dto.FetchedAt) >= c.ttl {
+		return FetchResult{}, false
+	}
+	body, err := os.ReadFile(stem + ".body")
+	if err != nil {
+		return FetchResult{}, false
+	}
+
+	headers := dto.Headers
+	if headers == nil {
+		headers = http.Header{}
+	}
+	var finalURL url.URL
+	if dto.FinalURL != "" {
+		if parsed, err := url.Parse(dto.FinalURL); err == nil {
+			finalURL = *parsed
+		}
+	}
+	var chain []url.URL
+	for _, raw := range dto.RedirectChain {
+		if parsed, err := url.Parse(raw); err == nil {
+			chain = append(chain, *parsed)
+		}
+	}
+	return FetchResult{
+		url:           fetchUrl,
+		finalURL:      finalURL,
+		redirectChain: chain,
+		body:          body,
+		fetchedAt:     dto.FetchedAt,
+		meta: ResponseMeta{
+			statusCode:      dto.Status,
+			responseHeaders: headers,
+		},
+	}, true
+}
+
+// finalURLString returns the final URL of result when it was redirected,
+// and "" otherwise.
+func finalURLString(result FetchResult) string {
+	finalURL := result.FinalURL()
+	requested := result.URL()
+	if finalURL.String() == requested.String() {
+		return ""
+	}
+	return finalURL.String()
+}
+
+// redirectChainStrings returns the redirect chain of result as strings,
+// or nil when it was not redirected.
+func redirectChainStrings(result FetchResult) []string {
+	var chain []string
+	for _, u := range result.RedirectChain() {
+		chain = append(chain, u.String())
+	}
+	return chain
+}
+
+// store saves result under key, replacing any previous entry.
+func (c *CachingFetcher) store(key string, result FetchResult) error {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create fetch cache directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(cacheEntryDTO{
+		URL:           key,
+		FinalURL:      finalURLString(result),
+		RedirectChain: redirectChainStrings(result),
+		Status:        result.Code(),
+		Headers:       result.Headers(),
+		FetchedAt:     c.now(),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode fetch cache entry for %s: %w", key, err)
+	}
+
+	// The body is written first, so an entry is only visible once complete
+	stem := filepath.Join(c.dir, archiveKey(key))
+	if err := os.WriteFile(stem+".body", result.Body(), 0644); err != nil {
+		return fmt.Errorf("failed to write fetch cache body for %s: %w", key, err)
+	}
+	if err := os.WriteFile(stem+".json", append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write fetch cache entry for %s: %w", key, err)
+	}
+	return nil
+}
+
+func keepAllQuery(string) bool { return true }
+
+// isNoStore reports whether a Cache-Control header value forbids storing
+// the response.
+func isNoStore(cacheControl string) bool {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		if strings.EqualFold(strings.TrimSpace(directive), "no-store") {
+			return true
+		}
+	}
+	return false
+}
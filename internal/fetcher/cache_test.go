@@ -0,0 +1,191 @@
+package fetcher_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/pkg/failure"
+)
+
+func newCachingFetcher(t *testing.T, sink *mockMetadataSink, ttl time.Duration, now *time.Time) *fetcher.CachingFetcher {
+	t.Helper()
+	inner := fetcher.NewHtmlFetcher(sink)
+	cached := fetcher.NewCachingFetcher(&inner, sink, t.TempDir(), ttl)
+	cached.SetClock(func() time.Time { return *now })
+	cached.Init(&http.Client{}, "test-user-agent")
+	return &cached
+}
+
+func TestCachingFetcher_ServesFromCacheWithinTTL(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte("<html><body>Cached</body></html>"))
+	}))
+	defer server.Close()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	sink := &mockMetadataSink{}
+	f := newCachingFetcher(t, sink, time.Hour, &now)
+	pageURL, _ := url.Parse(server.URL + "/guide")
+
+	if _, err := f.Fetch(context.Background(), 0, *pageURL, createTestRetryOptions(1)); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	now = now.Add(30 * time.Minute)
+	result, err := f.Fetch(context.Background(), 0, *pageURL, createTestRetryOptions(1))
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("expected the second fetch to be served from cache, got %d requests", requests.Load())
+	}
+	if string(result.Body()) != "<html><body>Cached</body></html>" {
+		t.Errorf("expected the cached body, got %q", result.Body())
+	}
+	if result.Code() != http.StatusOK || result.Header("Content-Type") != "text/html; charset=utf-8" {
+		t.Errorf("expected cached status and headers, got %d %q", result.Code(), result.Header("Content-Type"))
+	}
+	if len(sink.FetchEvents) != 2 || sink.FetchEvents[0].CacheHit() || !sink.FetchEvents[1].CacheHit() {
+		t.Errorf("expected a network fetch followed by a cache hit, got %+v", sink.FetchEvents)
+	}
+}
+
+func TestCachingFetcher_RefetchesAfterExpiry(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+		w.Header().Set("Content-Type", "text/html")
+		if n == 1 {
+			w.Write([]byte("<html><body>v1</body></html>"))
+			return
+		}
+		w.Write([]byte("<html><body>v2</body></html>"))
+	}))
+	defer server.Close()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	sink := &mockMetadataSink{}
+	f := newCachingFetcher(t, sink, time.Hour, &now)
+	pageURL, _ := url.Parse(server.URL)
+
+	if _, err := f.Fetch(context.Background(), 0, *pageURL, createTestRetryOptions(1)); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	now = now.Add(time.Hour)
+	result, err := f.Fetch(context.Background(), 0, *pageURL, createTestRetryOptions(1))
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if requests.Load() != 2 {
+		t.Errorf("expected an expired entry to be refetched, got %d requests", requests.Load())
+	}
+	if string(result.Body()) != "<html><body>v2</body></html>" {
+		t.Errorf("expected the refetched body, got %q", result.Body())
+	}
+
+	// The refetch refreshed the entry
+	now = now.Add(time.Minute)
+	result, _ = f.Fetch(context.Background(), 0, *pageURL, createTestRetryOptions(1))
+	if requests.Load() != 2 || string(result.Body()) != "<html><body>v2</body></html>" {
+		t.Errorf("expected the refreshed entry to be served, got %d requests and %q", requests.Load(), result.Body())
+	}
+}
+
+func TestCachingFetcher_RespectsNoStore(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("Cache-Control", "private, No-Store")
+		w.Write([]byte("<html><body>Secret</body></html>"))
+	}))
+	defer server.Close()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	sink := &mockMetadataSink{}
+	f := newCachingFetcher(t, sink, 0, &now)
+	pageURL, _ := url.Parse(server.URL)
+
+	for i := 0; i < 2; i++ {
+		if _, err := f.Fetch(context.Background(), 0, *pageURL, createTestRetryOptions(1)); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+	}
+	if requests.Load() != 2 {
+		t.Errorf("expected no-store responses to be fetched every time, got %d requests", requests.Load())
+	}
+}
+
+func TestCachingFetcher_KeysByCanonicalURL(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><body>Page</body></html>"))
+	}))
+	defer server.Close()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	sink := &mockMetadataSink{}
+	f := newCachingFetcher(t, sink, 0, &now)
+	first, _ := url.Parse(server.URL + "/docs/guide")
+	second, _ := url.Parse(server.URL + "/docs/guide#install")
+
+	f.Fetch(context.Background(), 0, *first, createTestRetryOptions(1))
+	f.Fetch(context.Background(), 0, *second, createTestRetryOptions(1))
+	if requests.Load() != 1 {
+		t.Errorf("expected URLs with the same canonical form to share an entry, got %d requests", requests.Load())
+	}
+}
+
+func TestCachingFetcher_OfflineOnly_ServesCachedAndFailsUncached(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><body>Cached</body></html>"))
+	}))
+	defer server.Close()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	sink := &mockMetadataSink{}
+	f := newCachingFetcher(t, sink, time.Hour, &now)
+	cachedURL, _ := url.Parse(server.URL + "/cached")
+	uncachedURL, _ := url.Parse(server.URL + "/uncached")
+	if _, err := f.Fetch(context.Background(), 0, *cachedURL, createTestRetryOptions(1)); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	// Offline, even an expired entry is served without a request
+	f.SetOfflineOnly(true)
+	now = now.Add(2 * time.Hour)
+	result, err := f.Fetch(context.Background(), 0, *cachedURL, createTestRetryOptions(1))
+	if err != nil {
+		t.Fatalf("expected the cached page to be served, got: %v", err)
+	}
+	if string(result.Body()) != "<html><body>Cached</body></html>" {
+		t.Errorf("expected the cached body, got %q", result.Body())
+	}
+
+	_, err = f.Fetch(context.Background(), 0, *uncachedURL, createTestRetryOptions(3))
+	if !errors.Is(err, fetcher.ErrCacheMiss) {
+		t.Fatalf("expected ErrCacheMiss, got: %v", err)
+	}
+	if err.RetryPolicy() != failure.RetryPolicyNever {
+		t.Errorf("expected a cache miss not to be retried, got policy %v", err.RetryPolicy())
+	}
+	if requests.Load() != 1 {
+		t.Errorf("expected no request while offline, got %d requests in total", requests.Load())
+	}
+}
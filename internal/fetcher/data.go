@@ -1,23 +1,50 @@
 package fetcher
 
 import (
+	"net/http"
 	"net/url"
+	"regexp"
 	"time"
+
+	"github.com/rohmanhakim/docs-crawler/pkg/retryjitter"
 )
 
 // HTTP boundary
 
 type FetchResult struct {
-	url       url.URL
-	body      []byte
-	meta      ResponseMeta
-	fetchedAt time.Time
+	url url.URL
+	// finalURL is the URL of the response after redirects; empty when it
+	// was not recorded, in which case it is url
+	finalURL url.URL
+	// redirectChain lists every URL requested, from url to finalURL; nil
+	// when the request was not redirected
+	redirectChain []url.URL
+	body          []byte
+	meta          ResponseMeta
+	fetchedAt     time.Time
+	timings       RequestTimings
 }
 
 func (f *FetchResult) URL() url.URL {
 	return f.url
 }
 
+// FinalURL returns the URL the response was served from after following
+// redirects. It equals URL when the request was not redirected.
+func (f *FetchResult) FinalURL() url.URL {
+	if f.finalURL.Host == "" {
+		return f.url
+	}
+	return f.finalURL
+}
+
+// RedirectChain returns every URL requested while fetching, in order: URL,
+// each redirect target, and FinalURL last. It is empty when the request was
+// not redirected.
+func (f *FetchResult) RedirectChain() []url.URL {
+	return append([]url.URL(nil), f.redirectChain...)
+}
+
 func (f *FetchResult) Body() []byte {
 	return f.body
 }
@@ -30,17 +57,84 @@ func (f *FetchResult) SizeByte() uint64 {
 	return uint64(len(f.body))
 }
 
-func (f *FetchResult) Headers() map[string]string {
-	return f.meta.responseHeaders
+// Headers returns a copy of the full response headers, including every value
+// of multi-valued headers. Keys are in canonical MIME form, so lookups through
+// http.Header.Get are case-insensitive.
+func (f *FetchResult) Headers() http.Header {
+	return f.meta.responseHeaders.Clone()
+}
+
+// Header returns the first value of the named response header, or "" if absent.
+// The lookup is case-insensitive.
+func (f *FetchResult) Header(name string) string {
+	return f.meta.responseHeaders.Get(name)
 }
 
 func (f *FetchResult) FetchedAt() time.Time {
 	return f.fetchedAt
 }
 
+// Timings returns the connection timings and TLS details of the request
+// that produced the result. They are only captured when
+// FetchParam.TraceRequests is set; otherwise Timings().Traced() is false.
+func (f *FetchResult) Timings() RequestTimings {
+	return f.timings
+}
+
+// FetchParam holds the per-crawl options that shape how pages are fetched.
+type FetchParam struct {
+	// PreflightHead issues a HEAD request before GET so resources with a
+	// disallowed content type or an oversized body are skipped without
+	// downloading them.
+	PreflightHead bool
+
+	// AllowedContentTypes lists the media types (without parameters) a page
+	// response may have. Empty falls back to HTML and XHTML.
+	AllowedContentTypes []string
+
+	// MaxResponseBytes caps the size of a page response body. 0 means unlimited.
+	MaxResponseBytes int64
+
+	// HostUserAgents overrides the user agent for specific hosts, keyed by
+	// URL host (including any port). Other hosts use the Init user agent.
+	HostUserAgents map[string]string
+
+	// TraceRequests captures DNS, connect, TLS handshake and time-to-first-byte
+	// timings and the negotiated TLS parameters of every GET (see trace.go).
+	TraceRequests bool
+
+	// DefaultHeaders are set on every page request, over the built-in
+	// browser-like headers.
+	DefaultHeaders map[string]string
+
+	// HeaderRules add headers to the requests whose URL matches their
+	// pattern, over DefaultHeaders. Later rules override earlier ones.
+	HeaderRules []HeaderRule
+
+	// RetryJitter is waited before every retry of a GET, one draw per
+	// retry. Nil waits none.
+	RetryJitter retryjitter.Source
+}
+
+// HeaderRule adds Headers to the requests whose URL matches Pattern.
+type HeaderRule struct {
+	Pattern *regexp.Regexp
+	Headers map[string]string
+}
+
+// DefaultFetchParam returns the FetchParam used when none is set:
+// no preflight, HTML and XHTML only, and no size cap.
+func DefaultFetchParam() FetchParam {
+	return FetchParam{
+		PreflightHead:       false,
+		AllowedContentTypes: []string{"text/html", "application/xhtml+xml"},
+		MaxResponseBytes:    0,
+	}
+}
+
 type ResponseMeta struct {
 	statusCode      int
-	responseHeaders map[string]string
+	responseHeaders http.Header
 }
 
 // NewFetchResultForTest creates a FetchResult for testing purposes.
@@ -54,13 +148,17 @@ func NewFetchResultForTest(
 	responseHeaders map[string]string,
 	fetchedAt time.Time,
 ) FetchResult {
+	headers := make(http.Header, len(responseHeaders))
+	for key, value := range responseHeaders {
+		headers.Set(key, value)
+	}
 	return FetchResult{
 		url:       url,
 		body:      body,
 		fetchedAt: fetchedAt,
 		meta: ResponseMeta{
 			statusCode:      statusCode,
-			responseHeaders: responseHeaders,
+			responseHeaders: headers,
 		},
 	}
 }
@@ -1,12 +1,17 @@
 package fetcher
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/rohmanhakim/docs-crawler/internal/metadata"
 	"github.com/rohmanhakim/docs-crawler/pkg/failure"
 )
 
+// ErrCacheMiss is matched (with errors.Is) by the error a CachingFetcher
+// returns in offline-only mode for a page missing from the fetch cache.
+var ErrCacheMiss = errors.New("fetch cache miss")
+
 type FetchErrorCause string
 
 const (
@@ -19,6 +24,8 @@ const (
 	ErrCauseRequestTooMany        = "too many requests"
 	ErrCauseRequest5xx            = "5xx"
 	ErrCauseRepeated403           = "repeated 403s"
+	ErrCauseResponseTooLarge      = "response too large"
+	ErrCauseCacheMiss             = "not in fetch cache"
 )
 
 // fetchErrorClassifications provides explicit retry policy and impact level
@@ -37,6 +44,8 @@ var fetchErrorClassifications = map[FetchErrorCause]struct {
 	ErrCauseRequestTooMany:        {failure.RetryPolicyAuto, failure.ImpactLevelContinue},
 	ErrCauseRequest5xx:            {failure.RetryPolicyAuto, failure.ImpactLevelContinue},
 	ErrCauseRepeated403:           {failure.RetryPolicyNever, failure.ImpactLevelContinue},
+	ErrCauseResponseTooLarge:      {failure.RetryPolicyNever, failure.ImpactLevelContinue},
+	ErrCauseCacheMiss:             {failure.RetryPolicyNever, failure.ImpactLevelContinue},
 }
 
 // FetchError represents an error that occurred during HTTP fetch operations.
@@ -45,8 +54,11 @@ var fetchErrorClassifications = map[FetchErrorCause]struct {
 type FetchError struct {
 	Message string
 	Cause   FetchErrorCause
-	policy  failure.RetryPolicy
-	impact  failure.ImpactLevel
+	// StatusCode is the HTTP status of the response the error was raised
+	// for, or 0 when there was no response
+	StatusCode int
+	policy     failure.RetryPolicy
+	impact     failure.ImpactLevel
 }
 
 // NewFetchError creates a new FetchError with explicit classification based on cause.
@@ -61,6 +73,12 @@ func NewFetchError(cause FetchErrorCause, message string) *FetchError {
 	}
 }
 
+// withStatus records the HTTP status of the response e was raised for.
+func (e *FetchError) withStatus(statusCode int) *FetchError {
+	e.StatusCode = statusCode
+	return e
+}
+
 func (e *FetchError) Error() string {
 	return fmt.Sprintf("fetcher error: %s", e.Cause)
 }
@@ -93,6 +111,15 @@ func (e *FetchError) Impact() failure.ImpactLevel {
 	return e.impact
 }
 
+// Unwrap exposes ErrCacheMiss for a page missing from the fetch cache in
+// offline-only mode.
+func (e *FetchError) Unwrap() error {
+	if e.Cause == ErrCauseCacheMiss {
+		return ErrCacheMiss
+	}
+	return nil
+}
+
 // mapFetchErrorToMetadataCause maps fetcher-local error semantics
 // to the canonical metadata.ErrorCause table.
 //
@@ -106,7 +133,25 @@ func mapFetchErrorToMetadataCause(err *FetchError) metadata.ErrorCause {
 		return metadata.CausePolicyDisallow
 	case ErrCauseRepeated403:
 		return metadata.CausePolicyDisallow
+	case ErrCauseResponseTooLarge:
+		return metadata.CausePolicyDisallow
 	default:
 		return metadata.CauseUnknown
 	}
 }
+
+// mapFetchErrorToSkipReason reports whether the error is a deliberate,
+// policy-driven skip rather than a failure, and which reason describes it.
+//
+// This mapping is observational only and MUST NOT be used
+// to derive control-flow decisions.
+func mapFetchErrorToSkipReason(err *FetchError) (metadata.SkipReason, bool) {
+	switch err.Cause {
+	case ErrCauseContentTypeInvalid:
+		return metadata.SkipReasonContentType, true
+	case ErrCauseResponseTooLarge:
+		return metadata.SkipReasonResponseTooLarge, true
+	default:
+		return "", false
+	}
+}
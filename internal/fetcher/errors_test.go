@@ -90,6 +90,14 @@ func TestFetchError_Classifications(t *testing.T) {
 			wantImpact:   failure.ImpactLevelContinue,
 			wantSeverity: failure.SeverityRecoverable,
 		},
+		// ErrCauseResponseTooLarge - size cap is a policy, never retry
+		{
+			name:         "ErrCauseResponseTooLarge should be RetryPolicyNever",
+			cause:        ErrCauseResponseTooLarge,
+			wantPolicy:   failure.RetryPolicyNever,
+			wantImpact:   failure.ImpactLevelContinue,
+			wantSeverity: failure.SeverityRecoverable,
+		},
 	}
 
 	for _, tt := range tests {
@@ -126,6 +134,7 @@ func TestFetchError_AllCausesCovered(t *testing.T) {
 		ErrCauseRequestTooMany,
 		ErrCauseRequest5xx,
 		ErrCauseRepeated403,
+		ErrCauseResponseTooLarge,
 	}
 
 	for _, cause := range allCauses {
@@ -189,6 +198,11 @@ func TestMapFetchErrorToMetadataCause(t *testing.T) {
 			err:       NewFetchError(ErrCauseRepeated403, "test"),
 			wantCause: metadata.CausePolicyDisallow,
 		},
+		{
+			name:      "ErrCauseResponseTooLarge maps to CausePolicyDisallow",
+			err:       NewFetchError(ErrCauseResponseTooLarge, "test"),
+			wantCause: metadata.CausePolicyDisallow,
+		},
 		{
 			name:      "unknown cause maps to CauseUnknown",
 			err:       &FetchError{Cause: "unknown cause"},
@@ -6,13 +6,16 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptrace"
 	"net/url"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/rohmanhakim/docs-crawler/internal/metadata"
 	"github.com/rohmanhakim/docs-crawler/pkg/debug"
 	"github.com/rohmanhakim/docs-crawler/pkg/failure"
+	"github.com/rohmanhakim/docs-crawler/pkg/retryjitter"
 	"github.com/rohmanhakim/retrier"
 )
 
@@ -35,6 +38,7 @@ The fetcher never parses content; it only returns bytes and metadata.
 */
 type Fetcher interface {
 	Init(httpClient *http.Client, userAgent string)
+	SetFetchParam(param FetchParam)
 	Fetch(
 		ctx context.Context,
 		crawlDepth int,
@@ -47,6 +51,7 @@ type HtmlFetcher struct {
 	metadataSink metadata.MetadataSink
 	httpClient   *http.Client
 	userAgent    string
+	param        FetchParam
 	debugLogger  debug.DebugLogger
 }
 
@@ -56,6 +61,7 @@ func NewHtmlFetcher(
 	return HtmlFetcher{
 		metadataSink: metadataSink,
 		httpClient:   nil,
+		param:        DefaultFetchParam(),
 		debugLogger:  debug.NewNoOpLogger(),
 	}
 }
@@ -67,6 +73,12 @@ func (h *HtmlFetcher) Init(httpClient *http.Client, userAgent string) {
 	h.userAgent = userAgent
 }
 
+// SetFetchParam sets the content type, size, and preflight options
+// applied to every subsequent Fetch.
+func (h *HtmlFetcher) SetFetchParam(param FetchParam) {
+	h.param = param
+}
+
 // SetDebugLogger sets the debug logger for the fetcher.
 // This is optional and defaults to NoOpLogger.
 // If logger is nil, NoOpLogger is used as a safe default.
@@ -86,8 +98,30 @@ func (h *HtmlFetcher) Fetch(
 ) (FetchResult, failure.ClassifiedError) {
 	callerMethod := "HtmlFetcher.Fetch"
 	startTime := time.Now()
+	userAgent := h.userAgentFor(fetchUrl.Host)
+
+	// The HEAD preflight runs once per fetch, before the GET retry loop
+	if h.param.PreflightHead {
+		if err := h.preflight(ctx, fetchUrl, userAgent); err != nil {
+			h.metadataSink.RecordFetch(metadata.NewFetchEvent(
+				startTime,
+				fetchUrl.String(),
+				0,
+				time.Since(startTime),
+				"",
+				0,
+				crawlDepth,
+				metadata.KindPage,
+			))
+			if !h.recordSkip(fetchUrl, crawlDepth, err) {
+				h.recordFetchError(callerMethod, fetchUrl, err)
+			}
+			return FetchResult{}, err
+		}
+	}
 
-	retryResult := h.fetchWithRetry(ctx, fetchUrl, h.userAgent, retryOptions)
+	var timings RequestTimings
+	retryResult := h.fetchWithRetry(ctx, fetchUrl, userAgent, retryOptions, &timings)
 	result := retryResult.Value()
 	err := retryResult.Err()
 
@@ -103,11 +137,11 @@ func (h *HtmlFetcher) Fetch(
 		retryCount = retryResult.Attempts()
 	} else {
 		statusCode = result.Code()
-		contentType = h.extractContentType(result.Headers())
+		contentType = result.Header("Content-Type")
 		retryCount = retryResult.Attempts()
 	}
 
-	h.metadataSink.RecordFetch(metadata.NewFetchEvent(
+	fetchEvent := metadata.NewFetchEvent(
 		startTime,
 		fetchUrl.String(),
 		statusCode,
@@ -116,7 +150,11 @@ func (h *HtmlFetcher) Fetch(
 		retryCount,
 		crawlDepth,
 		metadata.KindPage,
-	))
+	)
+	if timings.Traced() {
+		fetchEvent = fetchEvent.WithTrace(timings.toMetadata())
+	}
+	h.metadataSink.RecordFetch(fetchEvent)
 
 	if err != nil {
 		// Check if it's a RetryError (retries exhausted)
@@ -139,6 +177,9 @@ func (h *HtmlFetcher) Fetch(
 
 			// Wrap in RetryExhaustedError to preserve full error chain
 			wrappedErr := failure.AsRetryExhaustedError(retryErr, classifiedErr)
+			if h.recordSkip(fetchUrl, crawlDepth, err) {
+				return FetchResult{}, wrappedErr
+			}
 			h.recordRetryError(callerMethod, fetchUrl, wrappedErr)
 			return FetchResult{}, wrappedErr
 		}
@@ -156,18 +197,22 @@ func (h *HtmlFetcher) Fetch(
 			}
 		}
 
-		h.recordFetchError(callerMethod, fetchUrl, classifiedErr)
+		if !h.recordSkip(fetchUrl, crawlDepth, err) {
+			h.recordFetchError(callerMethod, fetchUrl, classifiedErr)
+		}
 		return FetchResult{}, classifiedErr
 	}
 
 	return result, nil
 }
 
-func (h *HtmlFetcher) extractContentType(headers map[string]string) string {
-	if ct, ok := headers["Content-Type"]; ok {
-		return ct
+// userAgentFor returns the host-specific user agent from the fetch param,
+// falling back to the user agent passed to Init.
+func (h *HtmlFetcher) userAgentFor(host string) string {
+	if agent, ok := h.param.HostUserAgents[host]; ok && agent != "" {
+		return agent
 	}
-	return ""
+	return h.userAgent
 }
 
 func (h *HtmlFetcher) recordFetchError(callerMethod string, fetchUrl url.URL, err failure.ClassifiedError) {
@@ -189,6 +234,32 @@ func (h *HtmlFetcher) recordFetchError(callerMethod string, fetchUrl url.URL, er
 	}
 }
 
+// recordSkip records policy-driven outcomes (disallowed content type,
+// oversized response) as skips instead of errors.
+// It reports whether a skip was recorded.
+func (h *HtmlFetcher) recordSkip(fetchUrl url.URL, crawlDepth int, err error) bool {
+	var fetchError *FetchError
+	if !errors.As(err, &fetchError) {
+		return false
+	}
+	reason, ok := mapFetchErrorToSkipReason(fetchError)
+	if !ok {
+		return false
+	}
+	h.metadataSink.RecordSkip(
+		metadata.NewSkipEvent(
+			fetchUrl.String(),
+			reason,
+			time.Now(),
+			[]metadata.Attribute{
+				metadata.NewAttr(metadata.AttrMessage, fetchError.Error()),
+				metadata.NewInt64Attr(metadata.AttrDepth, int64(crawlDepth)),
+			},
+		),
+	)
+	return true
+}
+
 func (h *HtmlFetcher) recordRetryError(callerMethod string, fetchUrl url.URL, err failure.ClassifiedError) {
 	var retryError *retrier.RetryError
 	if errors.As(err, &retryError) {
@@ -214,22 +285,26 @@ func (h *HtmlFetcher) fetchWithRetry(
 	fetchUrl url.URL,
 	userAgent string,
 	retryOptions []retrier.RetryOption,
+	timings *RequestTimings,
 ) retrier.Result[FetchResult] {
 	fetchTask := func() (FetchResult, error) {
-		result, err := h.performFetch(ctx, fetchUrl, userAgent)
+		result, err := h.performFetch(ctx, fetchUrl, userAgent, timings)
 		if err != nil {
 			return result, err
 		}
 		return result, nil
 	}
 
-	return retrier.Retry(ctx, debug.AsRetryLogger(h.debugLogger), fetchTask, retryOptions...)
+	return retrier.Retry(ctx, debug.AsRetryLogger(h.debugLogger), retryjitter.Wrap(ctx, h.param.RetryJitter, fetchTask), retryOptions...)
 }
 
+// performFetch issues one GET. When tracing is enabled, the attempt's timings
+// are stored in timings whether or not it succeeds.
 func (h *HtmlFetcher) performFetch(
 	ctx context.Context,
 	fetchUrl url.URL,
 	userAgent string,
+	timings *RequestTimings,
 ) (FetchResult, failure.ClassifiedError) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fetchUrl.String(), nil)
 	if err != nil {
@@ -239,9 +314,8 @@ func (h *HtmlFetcher) performFetch(
 		)
 	}
 
-	// Apply browser-like headers
-	headers := requestHeaders(userAgent)
-	for key, value := range headers {
+	// Apply browser-like headers, then the configured ones
+	for key, value := range h.headersFor(fetchUrl, userAgent) {
 		req.Header.Set(key, value)
 	}
 
@@ -254,7 +328,16 @@ func (h *HtmlFetcher) performFetch(
 		})
 	}
 
+	var tracer *requestTracer
+	if h.param.TraceRequests {
+		tracer = newRequestTracer()
+		req = req.WithContext(httptrace.WithClientTrace(req.Context(), tracer.clientTrace()))
+	}
+
 	resp, err := h.httpClient.Do(req)
+	if tracer != nil {
+		*timings = tracer.finish(resp)
+	}
 	if err != nil {
 		// Network/transport errors are retryable
 		return FetchResult{}, NewFetchError(
@@ -280,28 +363,28 @@ func (h *HtmlFetcher) performFetch(
 		return FetchResult{}, NewFetchError(
 			ErrCauseRequest5xx,
 			fmt.Sprintf("server error: %d", resp.StatusCode),
-		)
+		).withStatus(resp.StatusCode)
 
 	case resp.StatusCode == 429:
 		// Too Many Requests is retryable
 		return FetchResult{}, NewFetchError(
 			ErrCauseRequestTooMany,
 			"rate limited (429)",
-		)
+		).withStatus(resp.StatusCode)
 
 	case resp.StatusCode == 403:
 		// Forbidden is not retryable
 		return FetchResult{}, NewFetchError(
 			ErrCauseRequestPageForbidden,
 			"access forbidden (403)",
-		)
+		).withStatus(resp.StatusCode)
 
 	case resp.StatusCode >= 400 && resp.StatusCode < 500:
 		// Other client errors are not retryable
 		return FetchResult{}, NewFetchError(
 			ErrCauseRequestPageForbidden,
 			fmt.Sprintf("client error: %d", resp.StatusCode),
-		)
+		).withStatus(resp.StatusCode)
 
 	case resp.StatusCode >= 300 && resp.StatusCode < 400:
 		// Redirects should be handled by http.Client, but if we get here,
@@ -309,25 +392,44 @@ func (h *HtmlFetcher) performFetch(
 		return FetchResult{}, NewFetchError(
 			ErrCauseRedirectLimitExceeded,
 			fmt.Sprintf("redirect error: %d", resp.StatusCode),
-		)
+		).withStatus(resp.StatusCode)
 	}
 
-	// Check Content-Type for HTML
+	// Check Content-Type against the allowed media types
 	contentType := resp.Header.Get("Content-Type")
-	if !isHTMLContent(contentType) {
+	if !isAllowedContentType(contentType, h.param.AllowedContentTypes) {
 		return FetchResult{}, NewFetchError(
 			ErrCauseContentTypeInvalid,
 			fmt.Sprintf("non-HTML content type: %s", contentType),
-		)
+		).withStatus(resp.StatusCode)
+	}
+
+	maxBytes := h.param.MaxResponseBytes
+	if maxBytes > 0 && resp.ContentLength > maxBytes {
+		return FetchResult{}, NewFetchError(
+			ErrCauseResponseTooLarge,
+			fmt.Sprintf("content length %d exceeds limit of %d bytes", resp.ContentLength, maxBytes),
+		).withStatus(resp.StatusCode)
 	}
 
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
+	// Read response body, stopping one byte past the cap so servers that
+	// omit or understate Content-Length are still caught
+	var bodyReader io.Reader = resp.Body
+	if maxBytes > 0 {
+		bodyReader = io.LimitReader(resp.Body, maxBytes+1)
+	}
+	body, err := io.ReadAll(bodyReader)
 	if err != nil {
 		return FetchResult{}, NewFetchError(
 			ErrCauseReadResponseBodyError,
 			fmt.Sprintf("failed to read response body: %v", err),
-		)
+		).withStatus(resp.StatusCode)
+	}
+	if maxBytes > 0 && int64(len(body)) > maxBytes {
+		return FetchResult{}, NewFetchError(
+			ErrCauseResponseTooLarge,
+			fmt.Sprintf("response body exceeds limit of %d bytes", maxBytes),
+		).withStatus(resp.StatusCode)
 	}
 
 	// Log body read if debug enabled
@@ -337,28 +439,144 @@ func (h *HtmlFetcher) performFetch(
 		})
 	}
 
-	// Build response headers map
-	responseHeaders := make(map[string]string)
-	for key, values := range resp.Header {
-		if len(values) > 0 {
-			responseHeaders[key] = values[0]
-		}
+	// Keep the full response headers (all values, canonical keys) so downstream
+	// stages can inspect ETag, X-Robots-Tag, Retry-After, etc.
+	responseHeaders := resp.Header.Clone()
+
+	// Record where redirects ended, so callers can check the final scope
+	var finalURL url.URL
+	if resp.Request != nil && resp.Request.URL != nil {
+		finalURL = *resp.Request.URL
 	}
 
 	// Create FetchResult with timestamp
 	result := FetchResult{
-		url:       fetchUrl,
-		body:      body,
-		fetchedAt: time.Now(),
+		url:           fetchUrl,
+		finalURL:      finalURL,
+		redirectChain: redirectChain(resp),
+		body:          body,
+		fetchedAt:     time.Now(),
 		meta: ResponseMeta{
 			statusCode:      resp.StatusCode,
 			responseHeaders: responseHeaders,
 		},
+		timings: *timings,
 	}
 
 	return result, nil
 }
 
+// redirectChain returns the URLs requested on the way to resp, oldest
+// first, or nil when resp answers the original request. The HTTP client
+// links each redirected request to the response that caused it.
+func redirectChain(resp *http.Response) []url.URL {
+	if resp.Request == nil || resp.Request.Response == nil {
+		return nil
+	}
+	var chain []url.URL
+	for req := resp.Request; req != nil; {
+		if req.URL != nil {
+			chain = append(chain, *req.URL)
+		}
+		if req.Response == nil {
+			break
+		}
+		req = req.Response.Request
+	}
+	slices.Reverse(chain)
+	return chain
+}
+
+// preflight issues a HEAD request and returns an error when the advertised
+// Content-Type is not allowed or Content-Length exceeds the size cap.
+// HEAD is advisory: when it fails, is unsupported (405/501), or returns a
+// non-2xx status, preflight returns nil and the GET decides.
+func (h *HtmlFetcher) preflight(
+	ctx context.Context,
+	fetchUrl url.URL,
+	userAgent string,
+) failure.ClassifiedError {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, fetchUrl.String(), nil)
+	if err != nil {
+		return nil
+	}
+	for key, value := range h.headersFor(fetchUrl, userAgent) {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		h.logPreflight(ctx, "preflight_failed", debug.FieldMap{
+			"url":   fetchUrl.String(),
+			"error": err.Error(),
+		})
+		return nil
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		h.logPreflight(ctx, "preflight_unsupported", debug.FieldMap{
+			"url":         fetchUrl.String(),
+			"status_code": resp.StatusCode,
+		})
+		return nil
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType != "" && !isAllowedContentType(contentType, h.param.AllowedContentTypes) {
+		h.logPreflight(ctx, "preflight_skipped", debug.FieldMap{
+			"url":          fetchUrl.String(),
+			"reason":       "content_type",
+			"content_type": contentType,
+		})
+		return NewFetchError(
+			ErrCauseContentTypeInvalid,
+			fmt.Sprintf("preflight: non-HTML content type: %s", contentType),
+		).withStatus(resp.StatusCode)
+	}
+
+	maxBytes := h.param.MaxResponseBytes
+	if maxBytes > 0 && resp.ContentLength > maxBytes {
+		h.logPreflight(ctx, "preflight_skipped", debug.FieldMap{
+			"url":            fetchUrl.String(),
+			"reason":         "content_length",
+			"content_length": resp.ContentLength,
+		})
+		return NewFetchError(
+			ErrCauseResponseTooLarge,
+			fmt.Sprintf("preflight: content length %d exceeds limit of %d bytes", resp.ContentLength, maxBytes),
+		).withStatus(resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (h *HtmlFetcher) logPreflight(ctx context.Context, step string, fields debug.FieldMap) {
+	if h.debugLogger.Enabled() {
+		h.debugLogger.LogStep(ctx, "fetcher", step, fields)
+	}
+}
+
+// isAllowedContentType reports whether the media type of contentType is in
+// allowed. Parameters such as charset are ignored. An empty allowed list
+// falls back to HTML detection.
+func isAllowedContentType(contentType string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return isHTMLContent(contentType)
+	}
+	mediaType := contentType
+	if i := strings.IndexByte(mediaType, ';'); i >= 0 {
+		mediaType = mediaType[:i]
+	}
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	for _, a := range allowed {
+		if strings.ToLower(strings.TrimSpace(a)) == mediaType {
+			return true
+		}
+	}
+	return false
+}
+
 func isHTMLContent(contentType string) bool {
 	// Check if content type is HTML
 	contentType = strings.ToLower(contentType)
@@ -375,3 +593,25 @@ func requestHeaders(userAgent string) map[string]string {
 		"Connection":      "keep-alive",
 	}
 }
+
+// headersFor returns the headers of a request to fetchUrl: the
+// browser-like defaults, overridden by the fetch param's DefaultHeaders and
+// then by every matching HeaderRule in order. Keys are canonicalized so
+// differently cased spellings of a header override each other.
+func (h *HtmlFetcher) headersFor(fetchUrl url.URL, userAgent string) map[string]string {
+	headers := make(map[string]string)
+	merge := func(overrides map[string]string) {
+		for key, value := range overrides {
+			headers[http.CanonicalHeaderKey(key)] = value
+		}
+	}
+	merge(requestHeaders(userAgent))
+	merge(h.param.DefaultHeaders)
+	target := fetchUrl.String()
+	for _, rule := range h.param.HeaderRules {
+		if rule.Pattern != nil && rule.Pattern.MatchString(target) {
+			merge(rule.Headers)
+		}
+	}
+	return headers
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -143,6 +144,80 @@ func TestHtmlFetcher_Fetch_Success(t *testing.T) {
 	}
 }
 
+func TestHtmlFetcher_Fetch_FinalURLAfterRedirect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte("<html><body>Moved here</body></html>"))
+	}))
+	defer server.Close()
+
+	f := fetcher.NewHtmlFetcher(&mockMetadataSink{})
+	f.Init(&http.Client{}, "test-user-agent")
+
+	fetchUrl, _ := url.Parse(server.URL + "/old")
+	result, err := f.Fetch(context.Background(), 0, *fetchUrl, createTestRetryOptions(1))
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	requested := result.URL()
+	if requested.Path != "/old" {
+		t.Errorf("expected URL to stay the requested /old, got %s", requested.String())
+	}
+	final := result.FinalURL()
+	if final.Path != "/new" {
+		t.Errorf("expected FinalURL /new, got %s", final.String())
+	}
+}
+
+func TestHtmlFetcher_Fetch_RedirectChain(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, "/moved", http.StatusMovedPermanently)
+			return
+		case "/moved":
+			http.Redirect(w, r, "/new", http.StatusFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte("<html><body>Moved here</body></html>"))
+	}))
+	defer server.Close()
+
+	f := fetcher.NewHtmlFetcher(&mockMetadataSink{})
+	f.Init(&http.Client{}, "test-user-agent")
+
+	fetchUrl, _ := url.Parse(server.URL + "/old")
+	result, err := f.Fetch(context.Background(), 0, *fetchUrl, createTestRetryOptions(1))
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	var chain []string
+	for _, u := range result.RedirectChain() {
+		chain = append(chain, u.Path)
+	}
+	expected := []string{"/old", "/moved", "/new"}
+	if strings.Join(chain, " ") != strings.Join(expected, " ") {
+		t.Errorf("expected redirect chain %v, got %v", expected, chain)
+	}
+
+	// A direct fetch has no chain
+	fetchUrl, _ = url.Parse(server.URL + "/new")
+	result, err = f.Fetch(context.Background(), 0, *fetchUrl, createTestRetryOptions(1))
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(result.RedirectChain()) != 0 {
+		t.Errorf("expected no redirect chain, got %v", result.RedirectChain())
+	}
+}
+
 func TestHtmlFetcher_Fetch_NonHTMLContent(t *testing.T) {
 	// Create a test server that returns non-HTML content
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -181,14 +256,15 @@ func TestHtmlFetcher_Fetch_NonHTMLContent(t *testing.T) {
 		t.Fatalf("expected 1 fetch event, got %d", len(sink.FetchEvents))
 	}
 
-	// Verify error event was recorded
-	if len(sink.ErrorRecords) != 1 {
-		t.Fatalf("expected 1 error event, got %d", len(sink.ErrorRecords))
+	// Verify a content-type skip was recorded instead of an error
+	if len(sink.ErrorRecords) != 0 {
+		t.Errorf("expected 0 error events, got %d", len(sink.ErrorRecords))
 	}
-
-	errorEvt := sink.ErrorRecords[0]
-	if errorEvt.PackageName() != "fetcher" {
-		t.Errorf("expected package name 'fetcher', got %s", errorEvt.PackageName())
+	if len(sink.SkipEvents) != 1 {
+		t.Fatalf("expected 1 skip event, got %d", len(sink.SkipEvents))
+	}
+	if sink.SkipEvents[0].Reason() != metadata.SkipReasonContentType {
+		t.Errorf("expected skip reason %q, got %q", metadata.SkipReasonContentType, sink.SkipEvents[0].Reason())
 	}
 }
 
@@ -222,6 +298,9 @@ func TestHtmlFetcher_Fetch_HTTP404(t *testing.T) {
 	if fetchErr.RetryPolicy() != failure.RetryPolicyManual {
 		t.Error("expected non-retryable error (RetryPolicyManual) for 404")
 	}
+	if fetchErr.StatusCode != http.StatusNotFound {
+		t.Errorf("expected StatusCode 404, got %d", fetchErr.StatusCode)
+	}
 }
 
 func TestHtmlFetcher_Fetch_HTTP403(t *testing.T) {
@@ -368,6 +447,52 @@ func TestHtmlFetcher_Fetch_HTTP429_Retryable(t *testing.T) {
 	}
 }
 
+func TestHtmlFetcher_Fetch_WaitsRetryJitterBeforeEveryRetry(t *testing.T) {
+	// Create a test server that fails twice then succeeds
+	requestCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		if requestCount <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("<html>Success</html>"))
+	}))
+	defer server.Close()
+
+	draws := 0
+	f := fetcher.NewHtmlFetcher(&mockMetadataSink{})
+	f.SetDebugLogger(debugtest.NewLoggerMock())
+	f.Init(&http.Client{}, "test-user-agent")
+	f.SetFetchParam(fetcher.FetchParam{
+		RetryJitter: func() time.Duration {
+			draws++
+			return time.Millisecond
+		},
+	})
+
+	fetchUrl, _ := url.Parse(server.URL)
+	retryOptions := []retrier.RetryOption{
+		retrier.WithMaxAttempts(3),
+		retrier.WithInitialDuration(time.Millisecond),
+		retrier.WithJitter(0),
+	}
+
+	_, err := f.Fetch(context.Background(), 0, *fetchUrl, retryOptions)
+	if err != nil {
+		t.Fatalf("expected success after retries, got error: %v", err)
+	}
+
+	if requestCount != 3 {
+		t.Errorf("expected 3 requests, got %d", requestCount)
+	}
+	if draws != 2 {
+		t.Errorf("expected one jitter draw per retry (2), got %d", draws)
+	}
+}
+
 func TestHtmlFetcher_Fetch_SuccessAfterRetry(t *testing.T) {
 	// Create a test server that fails once then succeeds
 	requestCount := 0
@@ -485,11 +610,87 @@ func TestHtmlFetcher_FetchResult_Accessors(t *testing.T) {
 
 	// Test Headers accessor
 	headers := result.Headers()
-	if headers["Content-Type"] != "text/html; charset=utf-8" {
-		t.Errorf("unexpected Content-Type header: %s", headers["Content-Type"])
+	if headers.Get("Content-Type") != "text/html; charset=utf-8" {
+		t.Errorf("unexpected Content-Type header: %s", headers.Get("Content-Type"))
 	}
-	if headers["X-Custom-Header"] != "test-value" {
-		t.Errorf("unexpected X-Custom-Header: %s", headers["X-Custom-Header"])
+	if headers.Get("X-Custom-Header") != "test-value" {
+		t.Errorf("unexpected X-Custom-Header: %s", headers.Get("X-Custom-Header"))
+	}
+}
+
+func TestHtmlFetcher_FetchResult_HeadersMultiValueAndCaseInsensitive(t *testing.T) {
+	body := "<html>Test</html>"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("ETag", `"abc123"`)
+		w.Header().Add("X-Robots-Tag", "noindex")
+		w.Header().Add("X-Robots-Tag", "nofollow")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	sink := &mockMetadataSink{}
+	f := fetcher.NewHtmlFetcher(sink)
+	f.Init(&http.Client{}, "test-user-agent")
+
+	fetchUrl, _ := url.Parse(server.URL)
+	result, err := f.Fetch(context.Background(), 0, *fetchUrl, createTestRetryOptions(1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Multi-valued headers keep every value in order
+	robotsTags := result.Headers().Values("X-Robots-Tag")
+	if len(robotsTags) != 2 || robotsTags[0] != "noindex" || robotsTags[1] != "nofollow" {
+		t.Errorf("expected X-Robots-Tag [noindex nofollow], got %v", robotsTags)
+	}
+
+	// Lookups are case-insensitive
+	for _, name := range []string{"etag", "ETAG", "ETag", "Etag"} {
+		if got := result.Header(name); got != `"abc123"` {
+			t.Errorf("Header(%q) = %q, want %q", name, got, `"abc123"`)
+		}
+	}
+	if got := result.Header("x-robots-tag"); got != "noindex" {
+		t.Errorf("expected first X-Robots-Tag value, got %q", got)
+	}
+	if got := result.Header("X-Missing"); got != "" {
+		t.Errorf("expected empty value for missing header, got %q", got)
+	}
+
+	// Mutating the returned copy does not affect the result
+	headers := result.Headers()
+	headers.Set("ETag", "mutated")
+	if result.Header("ETag") != `"abc123"` {
+		t.Error("expected Headers() to return an independent copy")
+	}
+
+	// Body and status are unaffected
+	if string(result.Body()) != body {
+		t.Errorf("expected body %q, got %q", body, string(result.Body()))
+	}
+	if result.Code() != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, result.Code())
+	}
+}
+
+func TestNewFetchResultForTest_HeadersCaseInsensitive(t *testing.T) {
+	u, _ := url.Parse("https://example.com/page")
+	result := fetcher.NewFetchResultForTest(
+		*u,
+		[]byte("<html></html>"),
+		http.StatusOK,
+		"text/html",
+		map[string]string{"content-type": "text/html", "x-custom": "value"},
+		time.Now(),
+	)
+
+	if result.Header("Content-Type") != "text/html" {
+		t.Errorf("expected Content-Type text/html, got %q", result.Header("Content-Type"))
+	}
+	if result.Headers().Get("X-CUSTOM") != "value" {
+		t.Errorf("expected X-Custom value, got %q", result.Headers().Get("X-CUSTOM"))
 	}
 }
 
@@ -774,3 +975,290 @@ func TestHtmlFetcher_Fetch_GzippedResponse(t *testing.T) {
 		t.Errorf("expected decompressed body '%s', got '%s'", htmlContent, string(result.Body()))
 	}
 }
+
+// newPreflightTestServer serves headHandler for HEAD requests and a small HTML
+// page for GET, counting requests per method.
+func newPreflightTestServer(t *testing.T, headHandler http.HandlerFunc) (*httptest.Server, map[string]int) {
+	t.Helper()
+	counts := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		counts[r.Method]++
+		if r.Method == http.MethodHead {
+			headHandler(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("<html><body>Hello</body></html>"))
+	}))
+	t.Cleanup(server.Close)
+	return server, counts
+}
+
+func newPreflightFetcher(sink *mockMetadataSink, maxResponseBytes int64) fetcher.HtmlFetcher {
+	f := fetcher.NewHtmlFetcher(sink)
+	f.Init(&http.Client{}, "test-user-agent")
+	param := fetcher.DefaultFetchParam()
+	param.PreflightHead = true
+	param.MaxResponseBytes = maxResponseBytes
+	f.SetFetchParam(param)
+	return f
+}
+
+func TestHtmlFetcher_Preflight_SkipsDisallowedContentType(t *testing.T) {
+	server, counts := newPreflightTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		w.WriteHeader(http.StatusOK)
+	})
+
+	sink := &mockMetadataSink{}
+	f := newPreflightFetcher(sink, 0)
+	fetchUrl, _ := url.Parse(server.URL)
+
+	_, err := f.Fetch(context.Background(), 0, *fetchUrl, createTestRetryOptions(3))
+
+	var fetchErr *fetcher.FetchError
+	if !errors.As(err, &fetchErr) {
+		t.Fatalf("expected FetchError, got %T (%v)", err, err)
+	}
+	if fetchErr.Cause != fetcher.ErrCauseContentTypeInvalid {
+		t.Errorf("expected cause %q, got %q", fetcher.ErrCauseContentTypeInvalid, fetchErr.Cause)
+	}
+	if counts[http.MethodHead] == 0 {
+		t.Error("expected a HEAD request")
+	}
+	if counts[http.MethodGet] != 0 {
+		t.Errorf("expected no GET request, got %d", counts[http.MethodGet])
+	}
+	if len(sink.ErrorRecords) != 0 {
+		t.Errorf("expected 0 error events, got %d", len(sink.ErrorRecords))
+	}
+	if len(sink.SkipEvents) != 1 {
+		t.Fatalf("expected 1 skip event, got %d", len(sink.SkipEvents))
+	}
+	if sink.SkipEvents[0].Reason() != metadata.SkipReasonContentType {
+		t.Errorf("expected skip reason %q, got %q", metadata.SkipReasonContentType, sink.SkipEvents[0].Reason())
+	}
+}
+
+func TestHtmlFetcher_Preflight_SkipsOversizedResponse(t *testing.T) {
+	server, counts := newPreflightTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("Content-Length", "5000")
+		w.WriteHeader(http.StatusOK)
+	})
+
+	sink := &mockMetadataSink{}
+	f := newPreflightFetcher(sink, 1000)
+	fetchUrl, _ := url.Parse(server.URL)
+
+	_, err := f.Fetch(context.Background(), 0, *fetchUrl, createTestRetryOptions(3))
+
+	var fetchErr *fetcher.FetchError
+	if !errors.As(err, &fetchErr) {
+		t.Fatalf("expected FetchError, got %T (%v)", err, err)
+	}
+	if fetchErr.Cause != fetcher.ErrCauseResponseTooLarge {
+		t.Errorf("expected cause %q, got %q", fetcher.ErrCauseResponseTooLarge, fetchErr.Cause)
+	}
+	if fetchErr.RetryPolicy() != failure.RetryPolicyNever {
+		t.Errorf("expected RetryPolicyNever, got %v", fetchErr.RetryPolicy())
+	}
+	if counts[http.MethodGet] != 0 {
+		t.Errorf("expected no GET request, got %d", counts[http.MethodGet])
+	}
+	if len(sink.ErrorRecords) != 0 {
+		t.Errorf("expected 0 error events, got %d", len(sink.ErrorRecords))
+	}
+	if len(sink.SkipEvents) != 1 {
+		t.Fatalf("expected 1 skip event, got %d", len(sink.SkipEvents))
+	}
+	if sink.SkipEvents[0].Reason() != metadata.SkipReasonResponseTooLarge {
+		t.Errorf("expected skip reason %q, got %q", metadata.SkipReasonResponseTooLarge, sink.SkipEvents[0].Reason())
+	}
+}
+
+func TestHtmlFetcher_Preflight_HeadNotAllowedFallsBackToGet(t *testing.T) {
+	server, counts := newPreflightTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	})
+
+	sink := &mockMetadataSink{}
+	f := newPreflightFetcher(sink, 1000)
+	fetchUrl, _ := url.Parse(server.URL)
+
+	result, err := f.Fetch(context.Background(), 0, *fetchUrl, createTestRetryOptions(3))
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if string(result.Body()) != "<html><body>Hello</body></html>" {
+		t.Errorf("unexpected body: %q", result.Body())
+	}
+	if counts[http.MethodHead] != 1 || counts[http.MethodGet] != 1 {
+		t.Errorf("expected 1 HEAD and 1 GET, got %d HEAD and %d GET", counts[http.MethodHead], counts[http.MethodGet])
+	}
+}
+
+func TestHtmlFetcher_Preflight_RunsOnceAcrossGetRetries(t *testing.T) {
+	var gets int
+	counts := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		counts[r.Method]++
+		if r.Method == http.MethodHead {
+			w.Header().Set("Content-Type", "text/html")
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		gets++
+		if gets < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("<html><body>Hello</body></html>"))
+	}))
+	defer server.Close()
+
+	sink := &mockMetadataSink{}
+	f := newPreflightFetcher(sink, 0)
+	fetchUrl, _ := url.Parse(server.URL)
+
+	_, err := f.Fetch(context.Background(), 0, *fetchUrl, createTestRetryOptions(3))
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if counts[http.MethodHead] != 1 || counts[http.MethodGet] != 3 {
+		t.Errorf("expected 1 HEAD and 3 GET, got %d HEAD and %d GET", counts[http.MethodHead], counts[http.MethodGet])
+	}
+}
+
+func TestHtmlFetcher_Fetch_StreamingCapWithoutContentLength(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusOK)
+		// Flushing forces chunked encoding, so no Content-Length is sent
+		w.Write([]byte("<html><body>"))
+		w.(http.Flusher).Flush()
+		w.Write([]byte(strings.Repeat("x", 2000)))
+		w.Write([]byte("</body></html>"))
+	}))
+	defer server.Close()
+
+	sink := &mockMetadataSink{}
+	f := fetcher.NewHtmlFetcher(sink)
+	f.Init(&http.Client{}, "test-user-agent")
+	param := fetcher.DefaultFetchParam()
+	param.MaxResponseBytes = 1000
+	f.SetFetchParam(param)
+	fetchUrl, _ := url.Parse(server.URL)
+
+	_, err := f.Fetch(context.Background(), 0, *fetchUrl, createTestRetryOptions(3))
+
+	var fetchErr *fetcher.FetchError
+	if !errors.As(err, &fetchErr) {
+		t.Fatalf("expected FetchError, got %T (%v)", err, err)
+	}
+	if fetchErr.Cause != fetcher.ErrCauseResponseTooLarge {
+		t.Errorf("expected cause %q, got %q", fetcher.ErrCauseResponseTooLarge, fetchErr.Cause)
+	}
+	if len(sink.ErrorRecords) != 0 {
+		t.Errorf("expected 0 error events, got %d", len(sink.ErrorRecords))
+	}
+	if len(sink.SkipEvents) != 1 {
+		t.Fatalf("expected 1 skip event, got %d", len(sink.SkipEvents))
+	}
+	if sink.SkipEvents[0].Reason() != metadata.SkipReasonResponseTooLarge {
+		t.Errorf("expected skip reason %q, got %q", metadata.SkipReasonResponseTooLarge, sink.SkipEvents[0].Reason())
+	}
+}
+
+func TestHtmlFetcher_Fetch_HostUserAgentOverride(t *testing.T) {
+	var seen []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, r.Header.Get("User-Agent"))
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("<html><body>ok</body></html>"))
+	})
+	overridden := httptest.NewServer(handler)
+	defer overridden.Close()
+	other := httptest.NewServer(handler)
+	defer other.Close()
+
+	overriddenURL, _ := url.Parse(overridden.URL)
+	otherURL, _ := url.Parse(other.URL)
+
+	f := fetcher.NewHtmlFetcher(&mockMetadataSink{})
+	f.Init(&http.Client{}, "global-agent/1.0")
+	param := fetcher.DefaultFetchParam()
+	param.HostUserAgents = map[string]string{overriddenURL.Host: "special-agent/2.0"}
+	f.SetFetchParam(param)
+
+	if _, err := f.Fetch(context.Background(), 0, *overriddenURL, createTestRetryOptions(1)); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if _, err := f.Fetch(context.Background(), 0, *otherURL, createTestRetryOptions(1)); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	expected := []string{"special-agent/2.0", "global-agent/1.0"}
+	if len(seen) != len(expected) || seen[0] != expected[0] || seen[1] != expected[1] {
+		t.Errorf("expected user agents %v, got %v", expected, seen)
+	}
+}
+
+func TestHtmlFetcher_Fetch_HeaderRules(t *testing.T) {
+	seen := make(map[string]http.Header)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen[r.URL.Path] = r.Header.Clone()
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("<html><body>ok</body></html>"))
+	}))
+	defer server.Close()
+
+	f := fetcher.NewHtmlFetcher(&mockMetadataSink{})
+	f.Init(&http.Client{}, "global-agent/1.0")
+	param := fetcher.DefaultFetchParam()
+	param.DefaultHeaders = map[string]string{"X-Team": "docs", "x-api-key": "public"}
+	param.HeaderRules = []fetcher.HeaderRule{
+		{Pattern: regexp.MustCompile(`/private/`), Headers: map[string]string{"Authorization": "Bearer first", "X-Api-Key": "secret"}},
+		{Pattern: regexp.MustCompile(`/private/admin`), Headers: map[string]string{"Authorization": "Bearer admin"}},
+	}
+	f.SetFetchParam(param)
+
+	for _, path := range []string{"/private/page", "/private/admin", "/public/page"} {
+		target, _ := url.Parse(server.URL + path)
+		if _, err := f.Fetch(context.Background(), 0, *target, createTestRetryOptions(1)); err != nil {
+			t.Fatalf("%s: expected no error, got: %v", path, err)
+		}
+	}
+
+	tests := []struct {
+		path          string
+		authorization string
+		apiKey        string
+	}{
+		{"/private/page", "Bearer first", "secret"},
+		{"/private/admin", "Bearer admin", "secret"},
+		{"/public/page", "", "public"},
+	}
+	for _, tt := range tests {
+		headers := seen[tt.path]
+		if got := headers.Get("Authorization"); got != tt.authorization {
+			t.Errorf("%s: expected Authorization %q, got %q", tt.path, tt.authorization, got)
+		}
+		if got := headers.Get("X-Api-Key"); got != tt.apiKey {
+			t.Errorf("%s: expected X-Api-Key %q, got %q", tt.path, tt.apiKey, got)
+		}
+		if got := headers.Get("X-Team"); got != "docs" {
+			t.Errorf("%s: expected the default X-Team header, got %q", tt.path, got)
+		}
+		if got := headers.Get("User-Agent"); got != "global-agent/1.0" {
+			t.Errorf("%s: expected the built-in User-Agent, got %q", tt.path, got)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package fetcher_test
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+)
+
+// writeServerCAForTest writes the certificate of server as a PEM bundle and
+// returns its path.
+func writeServerCAForTest(t *testing.T, server *httptest.Server) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	block := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(path, block, 0644); err != nil {
+		t.Fatalf("failed to write CA file: %v", err)
+	}
+	return path
+}
+
+// fetchWithHostTLSForTest fetches server with a client whose transport
+// applies hosts, and returns the fetch error.
+func fetchWithHostTLSForTest(t *testing.T, server *httptest.Server, hosts map[string]fetcher.HostTLS) error {
+	t.Helper()
+	transport, err := fetcher.NewHostTLSTransport(&http.Transport{}, hosts)
+	if err != nil {
+		t.Fatalf("NewHostTLSTransport failed: %v", err)
+	}
+	f := fetcher.NewHtmlFetcher(&mockMetadataSink{})
+	f.Init(&http.Client{Transport: transport}, "test-user-agent")
+
+	fetchURL, _ := url.Parse(server.URL)
+	_, fetchErr := f.Fetch(context.Background(), 0, *fetchURL, createTestRetryOptions(1))
+	// Avoid returning a nil ClassifiedError as a non-nil error
+	if fetchErr != nil {
+		return fetchErr
+	}
+	return nil
+}
+
+func TestNewHostTLSTransport(t *testing.T) {
+	server := newTracingTLSServer(t, http.StatusOK)
+	caFile := writeServerCAForTest(t, server)
+
+	tests := []struct {
+		name    string
+		hosts   map[string]fetcher.HostTLS
+		wantErr bool
+	}{
+		{name: "untrusted certificate", hosts: nil, wantErr: true},
+		{name: "CA for the host", hosts: map[string]fetcher.HostTLS{"127.0.0.1": {CAFile: caFile}}},
+		{name: "skip verify for the host", hosts: map[string]fetcher.HostTLS{"127.0.0.1": {InsecureSkipVerify: true}}},
+		{name: "settings scoped to another host", hosts: map[string]fetcher.HostTLS{"docs.internal": {InsecureSkipVerify: true}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := fetchWithHostTLSForTest(t, server, tt.hosts)
+			if tt.wantErr && err == nil {
+				t.Fatal("expected the fetch to fail")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("expected the fetch to succeed, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestNewHostTLSTransport_InvalidCAFile(t *testing.T) {
+	emptyCA := filepath.Join(t.TempDir(), "empty.pem")
+	if err := os.WriteFile(emptyCA, []byte("not a certificate"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, caFile := range []string{filepath.Join(t.TempDir(), "missing.pem"), emptyCA} {
+		_, err := fetcher.NewHostTLSTransport(&http.Transport{}, map[string]fetcher.HostTLS{"docs.internal": {CAFile: caFile}})
+		if err == nil {
+			t.Errorf("expected an error for CA file %s", caFile)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package fetcher
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+)
+
+/*
+Request Tracing
+
+With FetchParam.TraceRequests, every GET attempt is traced through
+net/http/httptrace:
+- DNS lookup, TCP connect and TLS handshake durations
+- time to first byte, from sending the request to the first response byte
+- the negotiated TLS version and cipher suite, and whether the connection
+  was reused from the pool
+
+The timings of the final attempt are exposed on FetchResult.Timings and
+attached to the recorded metadata.FetchEvent, for failed fetches too, so
+slow or failing TLS hosts show up in the crawl metadata. When a request is
+redirected, the connection phases describe the last connection made.
+Tracing is off by default because the hooks run on every request.
+*/
+
+// RequestTimings holds the connection timings and TLS details of a traced
+// request. It is the zero value when tracing is disabled.
+type RequestTimings struct {
+	traced          bool
+	dnsLookup       time.Duration
+	connect         time.Duration
+	tlsHandshake    time.Duration
+	timeToFirstByte time.Duration
+	tlsVersion      string
+	tlsCipherSuite  string
+	reusedConn      bool
+}
+
+// Traced reports whether the request was traced.
+func (r RequestTimings) Traced() bool {
+	return r.traced
+}
+
+// DNSLookup returns the time spent resolving the host. It is zero when the
+// connection was reused or the host is an IP address.
+func (r RequestTimings) DNSLookup() time.Duration {
+	return r.dnsLookup
+}
+
+// Connect returns the time spent establishing the TCP connection.
+func (r RequestTimings) Connect() time.Duration {
+	return r.connect
+}
+
+// TLSHandshake returns the time spent in the TLS handshake.
+func (r RequestTimings) TLSHandshake() time.Duration {
+	return r.tlsHandshake
+}
+
+// TimeToFirstByte returns the time from starting the request to receiving
+// the first response byte, including the connection phases.
+func (r RequestTimings) TimeToFirstByte() time.Duration {
+	return r.timeToFirstByte
+}
+
+// TLSVersion returns the negotiated TLS version, such as "TLS 1.3", or ""
+// for plain HTTP.
+func (r RequestTimings) TLSVersion() string {
+	return r.tlsVersion
+}
+
+// TLSCipherSuite returns the name of the negotiated cipher suite, or "" for
+// plain HTTP.
+func (r RequestTimings) TLSCipherSuite() string {
+	return r.tlsCipherSuite
+}
+
+// ReusedConn reports whether the request reused a pooled connection.
+func (r RequestTimings) ReusedConn() bool {
+	return r.reusedConn
+}
+
+// toMetadata converts the timings into their metadata record.
+func (r RequestTimings) toMetadata() metadata.RequestTrace {
+	return metadata.NewRequestTrace(
+		r.dnsLookup,
+		r.connect,
+		r.tlsHandshake,
+		r.timeToFirstByte,
+		r.tlsVersion,
+		r.tlsCipherSuite,
+		r.reusedConn,
+	)
+}
+
+// requestTracer collects RequestTimings from httptrace hooks. The hooks can
+// run on the transport's dialing goroutines, so access is guarded.
+type requestTracer struct {
+	mu           sync.Mutex
+	start        time.Time
+	dnsStart     time.Time
+	connectStart time.Time
+	tlsStart     time.Time
+	timings      RequestTimings
+}
+
+func newRequestTracer() *requestTracer {
+	return &requestTracer{
+		start:   time.Now(),
+		timings: RequestTimings{traced: true},
+	}
+}
+
+// clientTrace returns the httptrace hooks that feed the tracer.
+func (t *requestTracer) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.dnsStart = time.Now()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.timings.dnsLookup = time.Since(t.dnsStart)
+		},
+		ConnectStart: func(string, string) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.connectStart = time.Now()
+		},
+		ConnectDone: func(_, _ string, err error) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if err == nil {
+				t.timings.connect = time.Since(t.connectStart)
+			}
+		},
+		TLSHandshakeStart: func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(state tls.ConnectionState, _ error) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.timings.tlsHandshake = time.Since(t.tlsStart)
+			t.setTLSState(state)
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.timings.reusedConn = info.Reused
+		},
+		GotFirstResponseByte: func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.timings.timeToFirstByte = time.Since(t.start)
+		},
+	}
+}
+
+// setTLSState records the negotiated TLS parameters. Callers hold t.mu.
+func (t *requestTracer) setTLSState(state tls.ConnectionState) {
+	if state.Version == 0 {
+		return
+	}
+	t.timings.tlsVersion = tls.VersionName(state.Version)
+	t.timings.tlsCipherSuite = tls.CipherSuiteName(state.CipherSuite)
+}
+
+// finish completes the timings from the response, which carries the TLS
+// state even when a reused connection skipped the handshake hooks.
+func (t *requestTracer) finish(resp *http.Response) RequestTimings {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if resp != nil && resp.TLS != nil {
+		t.setTLSState(*resp.TLS)
+	}
+	return t.timings
+}
@@ -0,0 +1,161 @@
+package fetcher_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+)
+
+// newTracingTLSServer starts an HTTPS server answering every request with
+// status and a small HTML page.
+func newTracingTLSServer(t *testing.T, status int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(status)
+		w.Write([]byte("<html><body>Traced</body></html>"))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newTracingFetcher returns a fetcher for server with tracing set as given.
+// The server is addressed as localhost so the request goes through DNS; the
+// TLS server name is pinned to one the test certificate covers.
+func newTracingFetcher(t *testing.T, server *httptest.Server, sink *mockMetadataSink, trace bool) (fetcher.HtmlFetcher, url.URL) {
+	t.Helper()
+	client := server.Client()
+	transport := client.Transport.(*http.Transport).Clone()
+	transport.TLSClientConfig.ServerName = "example.com"
+	client.Transport = transport
+
+	f := fetcher.NewHtmlFetcher(sink)
+	f.Init(client, "test-user-agent")
+	param := fetcher.DefaultFetchParam()
+	param.TraceRequests = trace
+	f.SetFetchParam(param)
+
+	fetchURL, _ := url.Parse(strings.Replace(server.URL, "127.0.0.1", "localhost", 1))
+	return f, *fetchURL
+}
+
+func TestHtmlFetcher_Fetch_TraceRequests_PopulatesTimingsAndTLS(t *testing.T) {
+	server := newTracingTLSServer(t, http.StatusOK)
+	sink := &mockMetadataSink{}
+	f, fetchURL := newTracingFetcher(t, server, sink, true)
+
+	result, err := f.Fetch(context.Background(), 0, fetchURL, createTestRetryOptions(1))
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	timings := result.Timings()
+	if !timings.Traced() {
+		t.Fatal("expected the request to be traced")
+	}
+	if timings.DNSLookup() <= 0 {
+		t.Errorf("expected DNS lookup time, got %s", timings.DNSLookup())
+	}
+	if timings.Connect() <= 0 {
+		t.Errorf("expected connect time, got %s", timings.Connect())
+	}
+	if timings.TLSHandshake() <= 0 {
+		t.Errorf("expected TLS handshake time, got %s", timings.TLSHandshake())
+	}
+	if timings.TimeToFirstByte() < timings.TLSHandshake() {
+		t.Errorf("expected time to first byte (%s) to include the handshake (%s)", timings.TimeToFirstByte(), timings.TLSHandshake())
+	}
+	if !strings.HasPrefix(timings.TLSVersion(), "TLS 1.") {
+		t.Errorf("expected a TLS version, got %q", timings.TLSVersion())
+	}
+	if timings.TLSCipherSuite() == "" {
+		t.Error("expected a TLS cipher suite")
+	}
+	if timings.ReusedConn() {
+		t.Error("expected a fresh connection for the first request")
+	}
+
+	// The same details are recorded on the fetch event
+	if len(sink.FetchEvents) != 1 {
+		t.Fatalf("expected 1 fetch event, got %d", len(sink.FetchEvents))
+	}
+	trace := sink.FetchEvents[0].Trace()
+	if trace == nil {
+		t.Fatal("expected the fetch event to carry the trace")
+	}
+	if trace.TLSVersion() != timings.TLSVersion() || trace.TimeToFirstByte() != timings.TimeToFirstByte() {
+		t.Errorf("expected recorded trace to match result timings, got %s / %s", trace.TLSVersion(), trace.TimeToFirstByte())
+	}
+}
+
+func TestHtmlFetcher_Fetch_TraceRequests_ReusedConnKeepsTLSInfo(t *testing.T) {
+	server := newTracingTLSServer(t, http.StatusOK)
+	sink := &mockMetadataSink{}
+	f, fetchURL := newTracingFetcher(t, server, sink, true)
+
+	if _, err := f.Fetch(context.Background(), 0, fetchURL, createTestRetryOptions(1)); err != nil {
+		t.Fatalf("first fetch failed: %v", err)
+	}
+	result, err := f.Fetch(context.Background(), 0, fetchURL, createTestRetryOptions(1))
+	if err != nil {
+		t.Fatalf("second fetch failed: %v", err)
+	}
+
+	timings := result.Timings()
+	if !timings.ReusedConn() {
+		t.Fatal("expected the second request to reuse the connection")
+	}
+	if timings.TLSHandshake() != 0 || timings.Connect() != 0 {
+		t.Errorf("expected no handshake or connect on a reused connection, got %s / %s", timings.TLSHandshake(), timings.Connect())
+	}
+	if timings.TLSVersion() == "" || timings.TLSCipherSuite() == "" {
+		t.Error("expected TLS details from the reused connection")
+	}
+}
+
+func TestHtmlFetcher_Fetch_TraceRequests_RecordedForFailedFetch(t *testing.T) {
+	server := newTracingTLSServer(t, http.StatusServiceUnavailable)
+	sink := &mockMetadataSink{}
+	f, fetchURL := newTracingFetcher(t, server, sink, true)
+
+	if _, err := f.Fetch(context.Background(), 0, fetchURL, createTestRetryOptions(1)); err == nil {
+		t.Fatal("expected an error for a 503 response")
+	}
+
+	if len(sink.FetchEvents) != 1 {
+		t.Fatalf("expected 1 fetch event, got %d", len(sink.FetchEvents))
+	}
+	trace := sink.FetchEvents[0].Trace()
+	if trace == nil {
+		t.Fatal("expected the failed fetch to carry its trace")
+	}
+	if trace.TimeToFirstByte() <= 0 || trace.TLSVersion() == "" {
+		t.Errorf("expected timings and TLS details, got ttfb=%s tls=%q", trace.TimeToFirstByte(), trace.TLSVersion())
+	}
+}
+
+func TestHtmlFetcher_Fetch_TraceRequestsDisabledByDefault(t *testing.T) {
+	server := newTracingTLSServer(t, http.StatusOK)
+	sink := &mockMetadataSink{}
+	f, fetchURL := newTracingFetcher(t, server, sink, false)
+
+	result, err := f.Fetch(context.Background(), 0, fetchURL, createTestRetryOptions(1))
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if result.Timings() != (fetcher.RequestTimings{}) {
+		t.Errorf("expected zero timings without tracing, got %+v", result.Timings())
+	}
+	if sink.FetchEvents[0].Trace() != nil {
+		t.Error("expected no trace on the fetch event")
+	}
+	if fetcher.DefaultFetchParam().TraceRequests {
+		t.Error("expected tracing to be off in the default fetch param")
+	}
+}
@@ -0,0 +1,46 @@
+package fetcher
+
+import (
+	"net/http"
+	"time"
+)
+
+/*
+Transport Tuning
+
+Fast crawls against a single host depend on reusing connections. NewTransport
+builds the HTTP transport of the crawl from TransportOptions: the idle
+connection pool sizes, how long idle connections are kept alive, and whether
+HTTP/2 is negotiated with servers offering it over TLS.
+
+With HTTP2 disabled every request uses HTTP/1.1. The protocols are set on the
+transport itself, so they survive the clones made by NewHostTLSTransport.
+*/
+
+// TransportOptions tunes the connections of the transport built by
+// NewTransport.
+type TransportOptions struct {
+	// MaxIdleConns is the maximum number of idle connections across all hosts
+	MaxIdleConns int
+	// MaxIdleConnsPerHost is the maximum number of idle connections per host
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout is how long an idle connection is kept alive
+	IdleConnTimeout time.Duration
+	// HTTP2 negotiates HTTP/2 with servers supporting it
+	HTTP2 bool
+}
+
+// NewTransport returns an HTTP transport tuned by opts.
+func NewTransport(opts TransportOptions) *http.Transport {
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetHTTP2(opts.HTTP2)
+
+	return &http.Transport{
+		MaxIdleConns:        opts.MaxIdleConns,
+		MaxIdleConnsPerHost: opts.MaxIdleConnsPerHost,
+		IdleConnTimeout:     opts.IdleConnTimeout,
+		ForceAttemptHTTP2:   opts.HTTP2,
+		Protocols:           protocols,
+	}
+}
@@ -0,0 +1,83 @@
+package fetcher_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+)
+
+func TestNewTransport_AppliesOptions(t *testing.T) {
+	transport := fetcher.NewTransport(fetcher.TransportOptions{
+		MaxIdleConns:        20,
+		MaxIdleConnsPerHost: 8,
+		IdleConnTimeout:     90 * time.Second,
+		HTTP2:               true,
+	})
+
+	if transport.MaxIdleConns != 20 {
+		t.Errorf("expected MaxIdleConns 20, got %d", transport.MaxIdleConns)
+	}
+	if transport.MaxIdleConnsPerHost != 8 {
+		t.Errorf("expected MaxIdleConnsPerHost 8, got %d", transport.MaxIdleConnsPerHost)
+	}
+	if transport.IdleConnTimeout != 90*time.Second {
+		t.Errorf("expected IdleConnTimeout 90s, got %v", transport.IdleConnTimeout)
+	}
+	if !transport.Protocols.HTTP2() || !transport.Protocols.HTTP1() {
+		t.Errorf("expected HTTP/1.1 and HTTP/2, got %v", transport.Protocols)
+	}
+
+	transport = fetcher.NewTransport(fetcher.TransportOptions{})
+	if transport.Protocols.HTTP2() || !transport.Protocols.HTTP1() {
+		t.Errorf("expected HTTP/1.1 only, got %v", transport.Protocols)
+	}
+}
+
+func TestNewTransport_NegotiatesProtocol(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Proto", r.Proto)
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	caFile := writeServerCAForTest(t, server)
+
+	tests := []struct {
+		name      string
+		http2     bool
+		wantProto string
+	}{
+		{name: "HTTP/2 enabled", http2: true, wantProto: "HTTP/2.0"},
+		{name: "HTTP/2 disabled", http2: false, wantProto: "HTTP/1.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Trusting the server goes through a clone of the transport, as
+			// with the per-host TLS settings of a crawl
+			transport, err := fetcher.NewHostTLSTransport(
+				fetcher.NewTransport(fetcher.TransportOptions{HTTP2: tt.http2}),
+				map[string]fetcher.HostTLS{"127.0.0.1": {CAFile: caFile}},
+			)
+			if err != nil {
+				t.Fatalf("NewHostTLSTransport failed: %v", err)
+			}
+			client := &http.Client{Transport: transport}
+
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.Proto != tt.wantProto {
+				t.Errorf("expected %s, got %s", tt.wantProto, resp.Proto)
+			}
+			if got := resp.Header.Get("X-Proto"); got != tt.wantProto {
+				t.Errorf("expected the server to see %s, got %s", tt.wantProto, got)
+			}
+		})
+	}
+}
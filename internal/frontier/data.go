@@ -15,8 +15,15 @@ import (
 // It contains no semantic policy decisions.
 // It represents ordering + depth metadata only.
 type CrawlToken struct {
-	url   url.URL
-	depth int
+	url      url.URL
+	depth    int
+	priority int
+	// best-first score from the scheduler's URL scorer; higher is dequeued first
+	score float64
+	// position in submission order, assigned by the frontier on enqueue
+	sequence uint64
+	// the page the URL was discovered on; empty for seeds
+	referrer url.URL
 }
 
 // NewCrawlToken creates a new CrawlToken with the given URL and depth.
@@ -36,6 +43,71 @@ func (c *CrawlToken) Depth() int {
 	return c.depth
 }
 
+// Priority returns the priority the URL was submitted with.
+// Higher priorities are dequeued first within a depth.
+func (c *CrawlToken) Priority() int {
+	return c.priority
+}
+
+// Score returns the score the URL was submitted with. It orders tokens of
+// equal priority under config.FrontierStrategyBestFirst.
+func (c *CrawlToken) Score() float64 {
+	return c.score
+}
+
+// Referrer returns the page the URL was discovered on, or an empty URL
+// when it was not discovered from a page (seeds, sitemaps, recrawls).
+func (c *CrawlToken) Referrer() url.URL {
+	return c.referrer
+}
+
+// Sequence returns the token's position in submission order.
+// It breaks ties between tokens of equal depth and priority.
+func (c *CrawlToken) Sequence() uint64 {
+	return c.sequence
+}
+
+// TokenLess reports whether a is dequeued before b when both are at the
+// same depth: higher priority first, then earlier submission.
+// Tokens at different depths are never compared; lower depths always
+// drain first.
+func TokenLess(a, b CrawlToken) bool {
+	if a.priority != b.priority {
+		return a.priority > b.priority
+	}
+	return a.sequence < b.sequence
+}
+
+// CanonicalTokenLess is TokenLess with the canonical URL string in place of
+// submission order: higher priority first, then URLs in lexical order. The
+// sequence only breaks ties between identical URLs, which deduplication
+// prevents. It is the ordering used with config.DeterministicOrder.
+func CanonicalTokenLess(a, b CrawlToken) bool {
+	if a.priority != b.priority {
+		return a.priority > b.priority
+	}
+	aURL, bURL := a.url.String(), b.url.String()
+	if aURL != bURL {
+		return aURL < bURL
+	}
+	return a.sequence < b.sequence
+}
+
+// BestFirstLess orders tokens of the same depth by priority, then by score
+// (highest first), leaving equal scores to less, the breadth-first ordering.
+// It is the ordering used with config.FrontierStrategyBestFirst.
+func BestFirstLess(less func(a, b CrawlToken) bool) func(a, b CrawlToken) bool {
+	return func(a, b CrawlToken) bool {
+		if a.priority != b.priority {
+			return a.priority > b.priority
+		}
+		if a.score != b.score {
+			return a.score > b.score
+		}
+		return less(a, b)
+	}
+}
+
 // CrawlAdmissionCandidate represents a URL that has already been
 // admitted by the scheduler.
 //
@@ -92,6 +164,12 @@ type DiscoveryMetadata struct {
 	// TODO: implement delay overriding in both scheduler and frontier
 	depth         int
 	delayOverride *time.Duration
+	// tie-break priority within a depth; higher is dequeued first
+	priority int
+	// best-first score within a priority; higher is dequeued first
+	score float64
+	// the page the URL was discovered on; empty when not found on a page
+	referrer url.URL
 }
 
 func NewDiscoveryMetadata(
@@ -111,3 +189,36 @@ func (d DiscoveryMetadata) Depth() int {
 func (d DiscoveryMetadata) DelayOverride() *time.Duration {
 	return d.delayOverride
 }
+
+// WithPriority returns a copy of d with the given tie-break priority.
+// The default priority is 0.
+func (d DiscoveryMetadata) WithPriority(priority int) DiscoveryMetadata {
+	d.priority = priority
+	return d
+}
+
+func (d DiscoveryMetadata) Priority() int {
+	return d.priority
+}
+
+// WithScore returns a copy of d with the given best-first score.
+// The default score is 0.
+func (d DiscoveryMetadata) WithScore(score float64) DiscoveryMetadata {
+	d.score = score
+	return d
+}
+
+func (d DiscoveryMetadata) Score() float64 {
+	return d.score
+}
+
+// WithReferrer returns a copy of d discovered on the page referrer.
+// The default referrer is empty.
+func (d DiscoveryMetadata) WithReferrer(referrer url.URL) DiscoveryMetadata {
+	d.referrer = referrer
+	return d
+}
+
+func (d DiscoveryMetadata) Referrer() url.URL {
+	return d.referrer
+}
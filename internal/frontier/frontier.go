@@ -3,9 +3,12 @@ package frontier
 import (
 	"context"
 	"net/url"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
 	"github.com/rohmanhakim/docs-crawler/pkg/collections"
 	"github.com/rohmanhakim/docs-crawler/pkg/debug"
 	"github.com/rohmanhakim/docs-crawler/pkg/urlutil"
@@ -26,6 +29,37 @@ import (
  Frontier MUST NOT influence crawl control flow and MUST NOT reject
  URLs for policy reasons.
 
+ The one mechanical drop the frontier makes on its own, the per-depth
+ quota, is recorded as a skip so it shows up alongside the scheduler's
+ policy skips. Recording is write-only; metadata never feeds back into
+ frontier decisions.
+
+ Tie-breaking:
+ Lower depths always drain first. Within a depth, tokens are dequeued by
+ priority (highest first, see DiscoveryMetadata.WithPriority), then in
+ submission order. Every enqueued token gets the next sequence number, so
+ the order is total and identical across runs with identical submissions.
+ TokenLess is the comparison Dequeue and PendingTokens follow.
+
+ Depth is the hop count from a seed carried by the submission, unless
+ config.DepthMode is config.DepthModePathDepth: the depth of a URL is then
+ the number of segments in its path ("/a/b/c" is 3), whatever link led to
+ it, and drives maxDepth, the per-depth quota and the depth levels alike.
+ A seed is never dropped for its path depth.
+
+ With config.DeterministicOrder, CanonicalTokenLess replaces TokenLess:
+ within a priority, tokens are dequeued in canonical-URL sort order, so the
+ processing order no longer depends on the order links were discovered in.
+ Since a depth is only drained once every lower depth is empty, all of its
+ tokens are queued by then and the whole level comes out sorted. Page
+ limits (maxPages, maxPagesPerDepth) are still applied at submission, in
+ discovery order.
+
+ With config.FrontierStrategyBestFirst, BestFirstLess wraps the ordering:
+ within a priority, tokens with a higher score (see DiscoveryMetadata.WithScore)
+ are dequeued first, and equal scores fall back to the ordering above. Depth
+ levels still drain in order, so depth limits keep their meaning.
+
  Frontier Responsibilities:
  - Maintain BFS ordering
  - Deduplicate URLs
@@ -58,22 +92,57 @@ type CrawlFrontier struct {
 	queuesByDepth map[int]*collections.FIFOQueue[CrawlToken]
 	visitedUrl    collections.Set[string]
 	maxDepth      int
-	currentDepth  int
-	maxPages      int
-	debugLogger   debug.DebugLogger
+	// config.DepthModeLinkDistance or config.DepthModePathDepth
+	depthMode string
+	// per-host maxDepth overrides keyed by URL host; others use maxDepth
+	hostMaxDepth map[string]int
+	// directory index file names; "/dir/index.html" dedupes with "/dir"
+	defaultDocuments []string
+	// whether canonical paths keep, drop or gain a trailing slash
+	trailingSlash urlutil.TrailingSlashPolicy
+	// query parameters that survive canonicalization; zero value drops all
+	queryFilter  config.QueryFilter
+	currentDepth int
+	maxPages     int
+	// per-depth admission quota; 0 means unlimited
+	maxPagesPerDepth int
+	// number of unique URLs admitted so far at each depth
+	admittedByDepth map[int]int
+	// next submission sequence number, the final tie-breaker within a depth
+	nextSequence uint64
+	// orders tokens within a depth; TokenLess or CanonicalTokenLess
+	less         func(a, b CrawlToken) bool
+	metadataSink metadata.MetadataSink
+	debugLogger  debug.DebugLogger
 }
 
 func NewCrawlFrontier() CrawlFrontier {
 	return CrawlFrontier{
-		queuesByDepth: make(map[int]*collections.FIFOQueue[CrawlToken]),
-		visitedUrl:    collections.NewSet[string](),
-		debugLogger:   debug.NewNoOpLogger(),
+		queuesByDepth:   make(map[int]*collections.FIFOQueue[CrawlToken]),
+		visitedUrl:      collections.NewSet[string](),
+		admittedByDepth: make(map[int]int),
+		less:            TokenLess,
+		metadataSink:    &metadata.NoopSink{},
+		debugLogger:     debug.NewNoOpLogger(),
 	}
 }
 
 func (f *CrawlFrontier) Init(cfg config.Config) {
 	f.maxDepth = cfg.MaxDepth()
+	f.hostMaxDepth = cfg.HostMaxDepth()
+	f.depthMode = cfg.DepthMode()
+	f.defaultDocuments = cfg.DefaultDocuments()
+	f.queryFilter = cfg.QueryFilter()
+	f.trailingSlash = urlutil.TrailingSlashPolicy(cfg.TrailingSlashPolicy())
 	f.maxPages = cfg.MaxPages()
+	f.maxPagesPerDepth = cfg.MaxPagesPerDepth()
+	f.less = TokenLess
+	if cfg.DeterministicOrder() {
+		f.less = CanonicalTokenLess
+	}
+	if cfg.FrontierStrategy() == config.FrontierStrategyBestFirst {
+		f.less = BestFirstLess(f.less)
+	}
 }
 
 // SetDebugLogger sets the debug logger for the frontier.
@@ -87,6 +156,17 @@ func (f *CrawlFrontier) SetDebugLogger(logger debug.DebugLogger) {
 	f.debugLogger = logger
 }
 
+// SetMetadataSink sets the sink that receives per-depth quota skips.
+// This is optional and defaults to NoopSink.
+// If sink is nil, NoopSink is used as a safe default.
+func (f *CrawlFrontier) SetMetadataSink(sink metadata.MetadataSink) {
+	if sink == nil {
+		f.metadataSink = &metadata.NoopSink{}
+		return
+	}
+	f.metadataSink = sink
+}
+
 /*
 Submit
 - Assumes the URL is already admitted.
@@ -110,32 +190,63 @@ func (f *CrawlFrontier) Submit(admission CrawlAdmissionCandidate) {
 		return
 	}
 
-	// return if new URL depth is higher than the allowed max depth from config
+	// In path depth mode the URL alone decides its depth
+	pathMode := f.depthMode == config.DepthModePathDepth
+	if pathMode {
+		admission.discoveryMetadata.depth = pathDepth(admission.targetURL)
+	}
+
+	// return if new URL depth is higher than the allowed max depth of its host
 	// maxDepth = 0 means unlimited
-	if admission.discoveryMetadata.depth > f.maxDepth && f.maxDepth != 0 {
+	maxDepth := f.maxDepthFor(admission.targetURL.Host)
+	exempt := pathMode && admission.sourceContext == SourceSeed
+	if admission.discoveryMetadata.depth > maxDepth && maxDepth != 0 && !exempt {
 		// Log skip due to depth exceeded
 		if f.debugLogger.Enabled() {
 			f.debugLogger.LogStep(context.TODO(), "frontier", "submit_skipped_depth", debug.FieldMap{
 				"url":       admission.targetURL.String(),
 				"depth":     admission.discoveryMetadata.depth,
-				"max_depth": f.maxDepth,
+				"max_depth": maxDepth,
 			})
 		}
 		return
 	}
 
-	// canonicalize the target URL before dedeuplication
-	canonicalized := urlutil.Canonicalize(admission.targetURL)
+	// canonicalize the target URL before dedeuplication, keeping only the
+	// query parameters the config allows
+	canonicalized := f.canonicalize(admission.targetURL)
 
 	// deduplicate canonicalized URL
-	f.deduplicate(canonicalized, admission.discoveryMetadata.depth)
+	f.deduplicate(canonicalized, admission.discoveryMetadata)
+}
+
+// pathDepth returns the number of non-empty segments in u's path.
+func pathDepth(u url.URL) int {
+	depth := 0
+	for _, segment := range strings.Split(u.Path, "/") {
+		if segment != "" {
+			depth++
+		}
+	}
+	return depth
+}
+
+// maxDepthFor returns the depth cap for URLs on host, falling back to the
+// global maxDepth when the host has no override.
+func (f *CrawlFrontier) maxDepthFor(host string) int {
+	if depth, ok := f.hostMaxDepth[host]; ok {
+		return depth
+	}
+	return f.maxDepth
 }
 
 func (f *CrawlFrontier) Enqueue(incomingToken CrawlToken) {
 	if f.queuesByDepth[incomingToken.depth] == nil {
 		f.queuesByDepth[incomingToken.depth] = collections.NewFIFOQueue[CrawlToken]()
 	}
-	f.queuesByDepth[incomingToken.depth].Enqueue(incomingToken)
+	incomingToken.sequence = f.nextSequence
+	f.nextSequence++
+	f.queuesByDepth[incomingToken.depth].EnqueueOrdered(incomingToken, f.less)
 	if incomingToken.depth > f.currentDepth {
 		// Log depth advancement
 		if f.debugLogger.Enabled() {
@@ -176,6 +287,19 @@ func (f *CrawlFrontier) CurrentMinDepth() int {
 	return -1
 }
 
+// Reset forgets every queued and admitted URL, so the same URLs can be
+// crawled again. The configuration set by Init is kept.
+func (f *CrawlFrontier) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.queuesByDepth = make(map[int]*collections.FIFOQueue[CrawlToken])
+	f.visitedUrl = collections.NewSet[string]()
+	f.admittedByDepth = make(map[int]int)
+	f.currentDepth = 0
+	f.nextSequence = 0
+}
+
 // VisitedCount returns the total number of unique URLs that have been
 // submitted to the frontier (i.e., the size of the visited URL set).
 // This represents the total unique URLs admitted for crawling.
@@ -186,6 +310,51 @@ func (f *CrawlFrontier) VisitedCount() int {
 	return f.visitedUrl.Size()
 }
 
+// IsAdmitted reports whether u, or another spelling of the same canonical
+// URL, has been admitted for crawling.
+func (f *CrawlFrontier) IsAdmitted(u url.URL) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	visitKey := f.visitKey(f.canonicalize(u))
+	return f.visitedUrl.Contains(visitKey.String())
+}
+
+// canonicalize returns the canonical form of u under the query filter and
+// trailing slash policy.
+func (f *CrawlFrontier) canonicalize(u url.URL) url.URL {
+	canonical := urlutil.CanonicalizeKeepingQuery(u, f.queryFilter.Keeps)
+	return urlutil.ApplyTrailingSlash(canonical, u, f.trailingSlash)
+}
+
+// visitKey folds a default document into its directory URL. The directory
+// ends in a slash unless the trailing slash policy strips it.
+func (f *CrawlFrontier) visitKey(canonical url.URL) url.URL {
+	key := urlutil.StripDefaultDocument(canonical, f.defaultDocuments)
+	if key.Path != canonical.Path {
+		directory := key
+		directory.Path += "/"
+		key = urlutil.ApplyTrailingSlash(key, directory, f.trailingSlash)
+	}
+	return key
+}
+
+// PendingTokens returns a snapshot of every token still waiting to be
+// dequeued, in the order Dequeue would return them (lowest depth first,
+// then the frontier's token ordering within a depth). The queues are left untouched.
+func (f *CrawlFrontier) PendingTokens() []CrawlToken {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	var tokens []CrawlToken
+	for d := 0; d <= f.currentDepth; d++ {
+		if queue := f.queuesByDepth[d]; queue != nil {
+			tokens = append(tokens, (*queue)...)
+		}
+	}
+	return tokens
+}
+
 // Get next URL from the queue,
 // returns false on the second returned values if empty
 func (f *CrawlFrontier) Dequeue() (CrawlToken, bool) {
@@ -210,9 +379,13 @@ func (f *CrawlFrontier) Dequeue() (CrawlToken, bool) {
 
 // Check is canonicalized URL has been visited before
 // return true if visited; false if has not been visited
-func (f *CrawlFrontier) deduplicate(canonicalizedUrl url.URL, depth int) {
+func (f *CrawlFrontier) deduplicate(canonicalizedUrl url.URL, discovery DiscoveryMetadata) {
+	depth := discovery.depth
+	// the visited key also folds default documents into their directory,
+	// while the token keeps the spelling that was seen first
+	visitKey := f.visitKey(canonicalizedUrl)
 	// if already visited skip
-	if f.visitedUrl.Contains(canonicalizedUrl.String()) {
+	if f.visitedUrl.Contains(visitKey.String()) {
 		// Log skip due to duplicate URL
 		if f.debugLogger.Enabled() {
 			f.debugLogger.LogStep(context.TODO(), "frontier", "submit_skipped_duplicate", debug.FieldMap{
@@ -221,10 +394,36 @@ func (f *CrawlFrontier) deduplicate(canonicalizedUrl url.URL, depth int) {
 		}
 		return
 	}
-	f.visitedUrl.Add(canonicalizedUrl.String())
+	// skip if this depth has already admitted its quota
+	// maxPagesPerDepth = 0 means unlimited
+	if f.maxPagesPerDepth != 0 && f.admittedByDepth[depth] >= f.maxPagesPerDepth {
+		// Log skip due to per-depth quota reached
+		if f.debugLogger.Enabled() {
+			f.debugLogger.LogStep(context.TODO(), "frontier", "submit_skipped_max_pages_per_depth", debug.FieldMap{
+				"url":                 canonicalizedUrl.String(),
+				"depth":               depth,
+				"max_pages_per_depth": f.maxPagesPerDepth,
+			})
+		}
+		f.metadataSink.RecordSkip(metadata.NewSkipEvent(
+			canonicalizedUrl.String(),
+			metadata.SkipReasonDepthQuota,
+			time.Now(),
+			[]metadata.Attribute{
+				metadata.NewAttr(metadata.AttrHost, canonicalizedUrl.Host),
+				metadata.NewInt64Attr(metadata.AttrDepth, int64(depth)),
+			},
+		))
+		return
+	}
+	f.visitedUrl.Add(visitKey.String())
+	f.admittedByDepth[depth]++
 	token := CrawlToken{
-		url:   canonicalizedUrl,
-		depth: depth,
+		url:      canonicalizedUrl,
+		depth:    depth,
+		priority: discovery.priority,
+		score:    discovery.score,
+		referrer: discovery.referrer,
 	}
 	f.Enqueue(token)
 }
@@ -298,3 +298,57 @@ func mustURLDebug(t *testing.T, raw string) url.URL {
 	}
 	return *u
 }
+
+// TestFrontier_DebugLogger_MaxPagesPerDepthReached verifies that debug logging
+// is called when a URL is skipped because its depth has reached its quota.
+func TestFrontier_DebugLogger_MaxPagesPerDepthReached(t *testing.T) {
+	// GIVEN a frontier with a per-depth cap of 1 and a mock logger
+	seedURL, _ := url.Parse("https://example.com/seed")
+	cfg, err := config.WithDefault([]url.URL{*seedURL}).
+		WithMaxPagesPerDepth(1).
+		Build()
+	if err != nil {
+		t.Fatalf("failed to build config: %v", err)
+	}
+
+	f := frontier.NewCrawlFrontier()
+	f.Init(cfg)
+
+	mockLogger := debugtest.NewLoggerMock()
+	f.SetDebugLogger(mockLogger)
+
+	f.Submit(frontier.NewCrawlAdmissionCandidate(
+		mustURLDebug(t, "https://example.com/page1"),
+		frontier.SourceCrawl,
+		frontier.NewDiscoveryMetadata(1, nil),
+	))
+
+	// Reset the mock to check the second submission
+	mockLogger.Reset()
+
+	// WHEN a second URL is submitted at the same depth
+	f.Submit(frontier.NewCrawlAdmissionCandidate(
+		mustURLDebug(t, "https://example.com/page2"),
+		frontier.SourceCrawl,
+		frontier.NewDiscoveryMetadata(1, nil),
+	))
+
+	// THEN debug logging should be called
+	if !mockLogger.LogStepCalled {
+		t.Fatal("Expected LogStep to be called for per-depth quota skip")
+	}
+
+	step := mockLogger.LastStep()
+	if step.Step != "submit_skipped_max_pages_per_depth" {
+		t.Errorf("Expected step 'submit_skipped_max_pages_per_depth', got %q", step.Step)
+	}
+	if step.Fields["depth"] != 1 {
+		t.Errorf("Expected depth 1, got %v", step.Fields["depth"])
+	}
+	if step.Fields["max_pages_per_depth"] != 1 {
+		t.Errorf("Expected max_pages_per_depth 1, got %v", step.Fields["max_pages_per_depth"])
+	}
+	if step.Fields["url"] != "https://example.com/page2" {
+		t.Errorf("Expected url 'https://example.com/page2', got %v", step.Fields["url"])
+	}
+}
@@ -3,6 +3,7 @@ package frontier_test
 import (
 	"fmt"
 	"net/url"
+	"reflect"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -10,6 +11,8 @@ import (
 
 	"github.com/rohmanhakim/docs-crawler/internal/config"
 	"github.com/rohmanhakim/docs-crawler/internal/frontier"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
 )
 
 // Helper to must-parse URLs in tests
@@ -277,6 +280,106 @@ func TestFrontier_DepthLimitEnforced(t *testing.T) {
 	}
 }
 
+// TestFrontier_HostMaxDepthOverridesGlobalDepth proves that a per-host depth
+// cap applies only to its host while other hosts keep the global MaxDepth
+func TestFrontier_HostMaxDepthOverridesGlobalDepth(t *testing.T) {
+	// GIVEN a global max depth of 3 and a cap of 1 for the third-party host
+	seedURL, _ := url.Parse("https://docs.example.com/")
+	cfg, err := config.WithDefault([]url.URL{*seedURL}).
+		WithMaxDepth(3).
+		WithHostMaxDepth(map[string]int{"thirdparty.example.org": 1}).
+		Build()
+	if err != nil {
+		t.Fatalf("failed to build config: %v", err)
+	}
+
+	f := frontier.NewCrawlFrontier()
+	f.Init(cfg)
+
+	// WHEN both hosts submit URLs at depths 1 through 4
+	for depth := 1; depth <= 4; depth++ {
+		for _, host := range []string{"docs.example.com", "thirdparty.example.org"} {
+			f.Submit(frontier.NewCrawlAdmissionCandidate(
+				mustURL(t, fmt.Sprintf("https://%s/page-%d", host, depth)),
+				frontier.SourceCrawl,
+				frontier.NewDiscoveryMetadata(depth, nil),
+			))
+		}
+	}
+
+	// THEN the third-party host stops at depth 1 and the primary at depth 3
+	var got []string
+	for {
+		token, ok := f.Dequeue()
+		if !ok {
+			break
+		}
+		tokenURL := token.URL()
+		got = append(got, tokenURL.String())
+	}
+	expected := []string{
+		"https://docs.example.com/page-1",
+		"https://thirdparty.example.org/page-1",
+		"https://docs.example.com/page-2",
+		"https://docs.example.com/page-3",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+// TestFrontier_PathDepthModeCountsPathSegments proves that in path depth
+// mode a URL's depth is its path segment count, however it was discovered,
+// and that the depth limit is applied to that count.
+func TestFrontier_PathDepthModeCountsPathSegments(t *testing.T) {
+	// GIVEN a path depth frontier with a max depth of 3 and a deep seed
+	seedURL, _ := url.Parse("https://example.com/docs/v2/guide/intro")
+	cfg, err := config.WithDefault([]url.URL{*seedURL}).
+		WithMaxDepth(3).
+		WithDepthMode(config.DepthModePathDepth).
+		Build()
+	if err != nil {
+		t.Fatalf("failed to build config: %v", err)
+	}
+
+	f := frontier.NewCrawlFrontier()
+	f.Init(cfg)
+
+	// WHEN the seed and links at link distance 1 are submitted
+	f.Submit(frontier.NewCrawlAdmissionCandidate(*seedURL, frontier.SourceSeed, frontier.NewDiscoveryMetadata(0, nil)))
+	for _, link := range []string{
+		"https://example.com/a/b/c",
+		"https://example.com/a/b/c/d",
+		"https://example.com/a/",
+	} {
+		f.Submit(frontier.NewCrawlAdmissionCandidate(
+			mustURL(t, link),
+			frontier.SourceCrawl,
+			frontier.NewDiscoveryMetadata(1, nil),
+		))
+	}
+
+	// THEN depths come from the paths, the 4-segment link is rejected and
+	// the seed is kept despite its depth
+	got := map[string]int{}
+	for {
+		token, ok := f.Dequeue()
+		if !ok {
+			break
+		}
+		tokenURL := token.URL()
+		got[tokenURL.String()] = token.Depth()
+	}
+	expected := map[string]int{
+		"https://example.com/a":                   1,
+		"https://example.com/a/b/c":               3,
+		"https://example.com/docs/v2/guide/intro": 4,
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
 // TestFrontier_WideTreeBFMaintained demonstrates BFS ordering is maintained
 // in a wide tree scenario where many depth-1 URLs should be
 // processed before any depth-2 URL
@@ -1361,6 +1464,100 @@ func TestFrontier_VisitedCount_Canonicalization(t *testing.T) {
 	}
 }
 
+// TestFrontier_FragmentOnlyDifferencesDedupe proves that URLs differing only
+// in their fragment are admitted once
+func TestFrontier_FragmentOnlyDifferencesDedupe(t *testing.T) {
+	f := frontier.NewCrawlFrontier()
+	f.Init(config.Config{})
+
+	for _, raw := range []string{
+		"https://example.com/guide",
+		"https://example.com/guide#install",
+		"https://example.com/guide/#usage",
+	} {
+		f.Submit(frontier.NewCrawlAdmissionCandidate(
+			mustURL(t, raw), frontier.SourceCrawl, frontier.NewDiscoveryMetadata(1, nil),
+		))
+	}
+
+	if count := f.VisitedCount(); count != 1 {
+		t.Fatalf("expected fragment-only differences to dedupe to 1 URL, got %d", count)
+	}
+	token, _ := f.Dequeue()
+	if tokenURL := token.URL(); tokenURL.String() != "https://example.com/guide" {
+		t.Errorf("expected the fragment to be dropped, got %q", tokenURL.String())
+	}
+}
+
+// TestFrontier_DefaultDocumentsCollapseWithDirectory proves that configured
+// default documents dedupe with their directory URL, keeping the spelling
+// that was submitted first
+func TestFrontier_DefaultDocumentsCollapseWithDirectory(t *testing.T) {
+	seedURL, _ := url.Parse("https://example.com/")
+	cfg, err := config.WithDefault([]url.URL{*seedURL}).
+		WithDefaultDocuments([]string{"index.html"}).
+		Build()
+	if err != nil {
+		t.Fatalf("failed to build config: %v", err)
+	}
+
+	f := frontier.NewCrawlFrontier()
+	f.Init(cfg)
+
+	for _, raw := range []string{
+		"https://example.com/docs/index.html",
+		"https://example.com/docs/",
+		"https://example.com/docs",
+		"https://example.com/",
+		"https://example.com/index.html",
+		"https://example.com/docs/intro.html",
+	} {
+		f.Submit(frontier.NewCrawlAdmissionCandidate(
+			mustURL(t, raw), frontier.SourceCrawl, frontier.NewDiscoveryMetadata(1, nil),
+		))
+	}
+
+	var got []string
+	for {
+		token, ok := f.Dequeue()
+		if !ok {
+			break
+		}
+		tokenURL := token.URL()
+		got = append(got, tokenURL.String())
+	}
+	expected := []string{
+		"https://example.com/docs/index.html",
+		"https://example.com/",
+		"https://example.com/docs/intro.html",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	// Every spelling of an admitted page is recognized
+	for _, raw := range []string{"https://example.com/docs", "https://example.com/index.html", "https://EXAMPLE.com/docs/intro.html#setup"} {
+		if !f.IsAdmitted(mustURL(t, raw)) {
+			t.Errorf("expected %s to be admitted", raw)
+		}
+	}
+	if f.IsAdmitted(mustURL(t, "https://example.com/blog")) {
+		t.Error("expected a URL never submitted not to be admitted")
+	}
+
+	// Without default documents the index file is a page of its own
+	plain := frontier.NewCrawlFrontier()
+	plain.Init(config.Config{})
+	for _, raw := range []string{"https://example.com/docs/index.html", "https://example.com/docs/"} {
+		plain.Submit(frontier.NewCrawlAdmissionCandidate(
+			mustURL(t, raw), frontier.SourceCrawl, frontier.NewDiscoveryMetadata(1, nil),
+		))
+	}
+	if count := plain.VisitedCount(); count != 2 {
+		t.Errorf("expected 2 URLs without default documents, got %d", count)
+	}
+}
+
 // TestFrontier_VisitedCount_Integration provides an integration test that
 // verifies VisitedCount works correctly throughout a realistic crawl scenario.
 func TestFrontier_VisitedCount_Integration(t *testing.T) {
@@ -1436,3 +1633,560 @@ func TestFrontier_VisitedCount_Integration(t *testing.T) {
 		t.Logf("Integration test passed: VisitedCount() correctly tracked %d unique URLs", finalCount)
 	}
 }
+
+func TestFrontier_MaxPagesPerDepthEnforced(t *testing.T) {
+	// GIVEN a frontier with a per-depth cap of 2
+	seedURL, _ := url.Parse("https://example.com/seed")
+	cfg, err := config.WithDefault([]url.URL{*seedURL}).
+		WithMaxPagesPerDepth(2).
+		Build()
+	if err != nil {
+		t.Fatalf("failed to build config: %v", err)
+	}
+
+	f := frontier.NewCrawlFrontier()
+	f.Init(cfg)
+
+	// WHEN submitting 5 URLs at depth 1
+	for i := 1; i <= 5; i++ {
+		u := mustURL(t, fmt.Sprintf("https://example.com/page%d", i))
+		f.Submit(frontier.NewCrawlAdmissionCandidate(
+			u, frontier.SourceCrawl, frontier.NewDiscoveryMetadata(1, nil),
+		))
+	}
+
+	// THEN only the first 2 are admitted
+	if got := f.VisitedCount(); got != 2 {
+		t.Errorf("expected 2 admitted URLs, got %d", got)
+	}
+
+	var dequeued []string
+	for {
+		token, ok := f.Dequeue()
+		if !ok {
+			break
+		}
+		if token.Depth() != 1 {
+			t.Errorf("expected depth 1, got %d", token.Depth())
+		}
+		tokenURL := token.URL()
+		dequeued = append(dequeued, tokenURL.String())
+	}
+
+	expected := []string{"https://example.com/page1", "https://example.com/page2"}
+	if len(dequeued) != len(expected) {
+		t.Fatalf("expected %d dequeuable URLs at depth 1, got %d: %v", len(expected), len(dequeued), dequeued)
+	}
+	for i := range expected {
+		if dequeued[i] != expected[i] {
+			t.Errorf("position %d: expected %s, got %s", i, expected[i], dequeued[i])
+		}
+	}
+
+	// AND the depth is exhausted once its quota has been dequeued
+	if !f.IsDepthExhausted(1) {
+		t.Error("expected depth 1 to be exhausted")
+	}
+}
+
+func TestFrontier_MaxPagesPerDepthRecordsSkips(t *testing.T) {
+	// GIVEN a frontier with a per-depth cap of 1 and a metadata sink
+	seedURL, _ := url.Parse("https://example.com/seed")
+	cfg, err := config.WithDefault([]url.URL{*seedURL}).
+		WithMaxPagesPerDepth(1).
+		Build()
+	if err != nil {
+		t.Fatalf("failed to build config: %v", err)
+	}
+
+	sink := &metadatatest.SinkMock{}
+	f := frontier.NewCrawlFrontier()
+	f.SetMetadataSink(sink)
+	f.Init(cfg)
+
+	// WHEN three URLs are submitted at depth 1, one of them twice
+	for _, raw := range []string{
+		"https://example.com/a",
+		"https://example.com/a",
+		"https://example.com/b",
+		"https://example.com/c",
+	} {
+		f.Submit(frontier.NewCrawlAdmissionCandidate(
+			mustURL(t, raw), frontier.SourceCrawl, frontier.NewDiscoveryMetadata(1, nil),
+		))
+	}
+
+	// THEN only the URLs dropped by the quota are recorded, not the duplicate
+	if len(sink.SkipEvents) != 2 {
+		t.Fatalf("expected 2 skip events, got %d", len(sink.SkipEvents))
+	}
+	for i, want := range []string{"https://example.com/b", "https://example.com/c"} {
+		event := sink.SkipEvents[i]
+		if event.SkippedURL() != want {
+			t.Errorf("skip %d: expected %s, got %s", i, want, event.SkippedURL())
+		}
+		if event.Reason() != metadata.SkipReasonDepthQuota {
+			t.Errorf("skip %d: expected reason %s, got %s", i, metadata.SkipReasonDepthQuota, event.Reason())
+		}
+	}
+}
+
+func TestFrontier_MaxPagesPerDepthIsPerLevel(t *testing.T) {
+	// GIVEN a frontier with a per-depth cap of 1
+	seedURL, _ := url.Parse("https://example.com/seed")
+	cfg, err := config.WithDefault([]url.URL{*seedURL}).
+		WithMaxPagesPerDepth(1).
+		Build()
+	if err != nil {
+		t.Fatalf("failed to build config: %v", err)
+	}
+
+	f := frontier.NewCrawlFrontier()
+	f.Init(cfg)
+
+	// WHEN two URLs are submitted at each of depths 0, 1 and 2
+	for depth := 0; depth <= 2; depth++ {
+		for i := 0; i < 2; i++ {
+			u := mustURL(t, fmt.Sprintf("https://example.com/d%d/p%d", depth, i))
+			f.Submit(frontier.NewCrawlAdmissionCandidate(
+				u, frontier.SourceCrawl, frontier.NewDiscoveryMetadata(depth, nil),
+			))
+		}
+	}
+
+	// THEN each depth keeps its own quota
+	for depth := 0; depth <= 2; depth++ {
+		token, ok := f.Dequeue()
+		if !ok {
+			t.Fatalf("expected a URL at depth %d", depth)
+		}
+		if token.Depth() != depth {
+			t.Errorf("expected depth %d, got %d", depth, token.Depth())
+		}
+	}
+	if _, ok := f.Dequeue(); ok {
+		t.Error("expected frontier to be empty")
+	}
+}
+
+func TestFrontier_MaxPagesPerDepthIgnoresDuplicates(t *testing.T) {
+	// GIVEN a frontier with a per-depth cap of 2
+	seedURL, _ := url.Parse("https://example.com/seed")
+	cfg, err := config.WithDefault([]url.URL{*seedURL}).
+		WithMaxPagesPerDepth(2).
+		Build()
+	if err != nil {
+		t.Fatalf("failed to build config: %v", err)
+	}
+
+	f := frontier.NewCrawlFrontier()
+	f.Init(cfg)
+
+	// WHEN a duplicate is submitted between two unique URLs
+	for _, raw := range []string{
+		"https://example.com/a",
+		"https://example.com/a/",
+		"https://example.com/b",
+	} {
+		f.Submit(frontier.NewCrawlAdmissionCandidate(
+			mustURL(t, raw), frontier.SourceCrawl, frontier.NewDiscoveryMetadata(1, nil),
+		))
+	}
+
+	// THEN the duplicate does not consume quota
+	if got := f.VisitedCount(); got != 2 {
+		t.Errorf("expected 2 admitted URLs, got %d", got)
+	}
+}
+
+func TestFrontier_PendingTokensDoesNotConsume(t *testing.T) {
+	// GIVEN a frontier with tokens submitted at mixed depths, deepest first
+	f := frontier.NewCrawlFrontier()
+	f.Init(config.Config{})
+
+	submissions := []struct {
+		raw   string
+		depth int
+	}{
+		{"https://example.com/c", 2},
+		{"https://example.com/b1", 1},
+		{"https://example.com/a", 0},
+		{"https://example.com/b2", 1},
+	}
+	for _, s := range submissions {
+		f.Submit(frontier.NewCrawlAdmissionCandidate(
+			mustURL(t, s.raw), frontier.SourceCrawl, frontier.NewDiscoveryMetadata(s.depth, nil),
+		))
+	}
+
+	// WHEN inspecting the pending tokens
+	pending := f.PendingTokens()
+
+	// THEN the snapshot is depth-ordered and FIFO within a depth
+	var snapshot []string
+	for _, token := range pending {
+		u := token.URL()
+		snapshot = append(snapshot, u.String())
+	}
+	expected := []string{
+		"https://example.com/a",
+		"https://example.com/b1",
+		"https://example.com/b2",
+		"https://example.com/c",
+	}
+	if fmt.Sprint(snapshot) != fmt.Sprint(expected) {
+		t.Fatalf("expected snapshot %v, got %v", expected, snapshot)
+	}
+
+	// AND dequeuing afterwards returns the same tokens in the same order
+	for i, want := range pending {
+		got, ok := f.Dequeue()
+		if !ok {
+			t.Fatalf("expected token %d to still be pending", i)
+		}
+		if got != want {
+			t.Fatalf("dequeue %d: expected %v, got %v", i, want, got)
+		}
+	}
+	if _, ok := f.Dequeue(); ok {
+		t.Fatal("expected frontier to be empty after draining the snapshot")
+	}
+	if got := f.PendingTokens(); len(got) != 0 {
+		t.Fatalf("expected no pending tokens, got %d", len(got))
+	}
+}
+
+func TestFrontier_TieBreakPriorityThenSubmissionOrder(t *testing.T) {
+	// GIVEN URLs at one depth submitted with mixed priorities
+	f := frontier.NewCrawlFrontier()
+	f.Init(config.Config{})
+
+	submissions := []struct {
+		path     string
+		priority int
+	}{
+		{"/low-1", 0},
+		{"/high-1", 5},
+		{"/low-2", 0},
+		{"/mid", 2},
+		{"/high-2", 5},
+		{"/negative", -1},
+	}
+	for _, s := range submissions {
+		f.Submit(frontier.NewCrawlAdmissionCandidate(
+			mustURL(t, "https://example.com"+s.path),
+			frontier.SourceCrawl,
+			frontier.NewDiscoveryMetadata(1, nil).WithPriority(s.priority),
+		))
+	}
+
+	// WHEN inspecting and then draining the frontier
+	pending := f.PendingTokens()
+	var dequeued []string
+	for {
+		token, ok := f.Dequeue()
+		if !ok {
+			break
+		}
+		u := token.URL()
+		dequeued = append(dequeued, u.Path)
+	}
+
+	// THEN higher priorities come first and ties keep submission order
+	want := []string{"/high-1", "/high-2", "/mid", "/low-1", "/low-2", "/negative"}
+	if fmt.Sprint(dequeued) != fmt.Sprint(want) {
+		t.Fatalf("expected dequeue order %v, got %v", want, dequeued)
+	}
+
+	// AND the snapshot follows TokenLess
+	for i := 1; i < len(pending); i++ {
+		if frontier.TokenLess(pending[i], pending[i-1]) {
+			t.Errorf("pending tokens out of order at %d: %v before %v", i, pending[i-1].URL(), pending[i].URL())
+		}
+	}
+	if pending[0].Priority() != 5 || pending[0].Sequence() >= pending[1].Sequence() {
+		t.Errorf("expected first token priority 5 submitted before the second, got priority %d sequences %d, %d",
+			pending[0].Priority(), pending[0].Sequence(), pending[1].Sequence())
+	}
+}
+
+func TestFrontier_DeterministicOrderSortsByCanonicalURL(t *testing.T) {
+	// GIVEN a frontier configured for deterministic ordering
+	cfg, err := config.WithDefault([]url.URL{mustURL(t, "https://example.com/")}).
+		WithDeterministicOrder(true).
+		Build()
+	if err != nil {
+		t.Fatalf("unexpected config error: %v", err)
+	}
+	f := frontier.NewCrawlFrontier()
+	f.Init(cfg)
+
+	// WHEN URLs at one depth are submitted out of order, one with a priority
+	for _, s := range []struct {
+		path     string
+		priority int
+	}{
+		{"/zeta", 0},
+		{"/alpha", 0},
+		{"/release-notes", 3},
+		{"/Beta", 0},
+		{"/beta", 0},
+	} {
+		f.Submit(frontier.NewCrawlAdmissionCandidate(
+			mustURL(t, "https://example.com"+s.path),
+			frontier.SourceCrawl,
+			frontier.NewDiscoveryMetadata(1, nil).WithPriority(s.priority),
+		))
+	}
+
+	var dequeued []string
+	for {
+		token, ok := f.Dequeue()
+		if !ok {
+			break
+		}
+		u := token.URL()
+		dequeued = append(dequeued, u.Path)
+	}
+
+	// THEN priority still wins, and ties are dequeued in canonical URL order
+	want := []string{"/release-notes", "/Beta", "/alpha", "/beta", "/zeta"}
+	if fmt.Sprint(dequeued) != fmt.Sprint(want) {
+		t.Fatalf("expected dequeue order %v, got %v", want, dequeued)
+	}
+}
+
+func TestFrontier_BestFirstOrdersByScore(t *testing.T) {
+	// GIVEN a best-first frontier
+	cfg, err := config.WithDefault([]url.URL{mustURL(t, "https://example.com/")}).
+		WithFrontierStrategy(config.FrontierStrategyBestFirst).
+		Build()
+	if err != nil {
+		t.Fatalf("unexpected config error: %v", err)
+	}
+	f := frontier.NewCrawlFrontier()
+	f.Init(cfg)
+
+	// WHEN URLs at one depth are submitted with scores
+	for _, s := range []struct {
+		path  string
+		score float64
+	}{
+		{"/blog/a", 0},
+		{"/reference/a", 2},
+		{"/blog/b", 0},
+		{"/guide", 1},
+		{"/reference/b", 2},
+	} {
+		f.Submit(frontier.NewCrawlAdmissionCandidate(
+			mustURL(t, "https://example.com"+s.path),
+			frontier.SourceCrawl,
+			frontier.NewDiscoveryMetadata(1, nil).WithScore(s.score),
+		))
+	}
+
+	// THEN higher scores come first, equal scores in submission order
+	want := []string{
+		"https://example.com/reference/a",
+		"https://example.com/reference/b",
+		"https://example.com/guide",
+		"https://example.com/blog/a",
+		"https://example.com/blog/b",
+	}
+	if got := dequeueAll(&f); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("expected dequeue order %v, got %v", want, got)
+	}
+}
+
+// dequeueAll drains the frontier and returns the URLs of its tokens in order.
+func dequeueAll(f *frontier.CrawlFrontier) []string {
+	var got []string
+	for {
+		token, ok := f.Dequeue()
+		if !ok {
+			return got
+		}
+		tokenURL := token.URL()
+		got = append(got, tokenURL.String())
+	}
+}
+
+// TestFrontier_DropQueryStringsCollapsesFacets proves that with
+// DropQueryStrings every faceted variant of a page collapses to its path
+func TestFrontier_DropQueryStringsCollapsesFacets(t *testing.T) {
+	seedURL, _ := url.Parse("https://example.com/")
+	cfg, err := config.WithDefault([]url.URL{*seedURL}).
+		WithDropQueryStrings(true).
+		Build()
+	if err != nil {
+		t.Fatalf("failed to build config: %v", err)
+	}
+
+	f := frontier.NewCrawlFrontier()
+	f.Init(cfg)
+	for _, raw := range []string{
+		"https://example.com/search?sort=asc&page=2",
+		"https://example.com/search?page=3",
+		"https://example.com/search",
+	} {
+		f.Submit(frontier.NewCrawlAdmissionCandidate(
+			mustURL(t, raw), frontier.SourceCrawl, frontier.NewDiscoveryMetadata(1, nil),
+		))
+	}
+
+	expected := []string{"https://example.com/search"}
+	if got := dequeueAll(&f); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+// TestFrontier_AllowedQueryParamsSurviveDedup proves that only allowlisted
+// query parameters survive, so URLs differing in other parameters dedupe
+// as one page while allowlisted values stay distinct
+func TestFrontier_AllowedQueryParamsSurviveDedup(t *testing.T) {
+	seedURL, _ := url.Parse("https://example.com/")
+	cfg, err := config.WithDefault([]url.URL{*seedURL}).
+		WithAllowedQueryParams([]string{"version", "lang"}).
+		Build()
+	if err != nil {
+		t.Fatalf("failed to build config: %v", err)
+	}
+
+	f := frontier.NewCrawlFrontier()
+	f.Init(cfg)
+	for _, raw := range []string{
+		"https://example.com/api?version=2&sort=asc",
+		"https://example.com/api?sort=desc&version=2",
+		"https://example.com/api?lang=en&version=2&page=4",
+		"https://example.com/api?version=2&lang=en",
+		"https://example.com/api?version=3",
+		"https://example.com/api?page=1",
+	} {
+		f.Submit(frontier.NewCrawlAdmissionCandidate(
+			mustURL(t, raw), frontier.SourceCrawl, frontier.NewDiscoveryMetadata(1, nil),
+		))
+	}
+
+	expected := []string{
+		"https://example.com/api?version=2",
+		"https://example.com/api?lang=en&version=2",
+		"https://example.com/api?version=3",
+		"https://example.com/api",
+	}
+	if got := dequeueAll(&f); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	if !f.IsAdmitted(mustURL(t, "https://example.com/api?utm_source=x&version=3")) {
+		t.Error("expected a spelling differing only in a dropped parameter to be admitted")
+	}
+
+	// Without DropQueryStrings every parameter is kept
+	keepAll, err := config.WithDefault([]url.URL{*seedURL}).WithDropQueryStrings(false).Build()
+	if err != nil {
+		t.Fatalf("failed to build config: %v", err)
+	}
+	plain := frontier.NewCrawlFrontier()
+	plain.Init(keepAll)
+	for _, raw := range []string{"https://example.com/search?page=2&sort=asc", "https://example.com/search?sort=asc&page=2"} {
+		plain.Submit(frontier.NewCrawlAdmissionCandidate(
+			mustURL(t, raw), frontier.SourceCrawl, frontier.NewDiscoveryMetadata(1, nil),
+		))
+	}
+	expected = []string{"https://example.com/search?page=2&sort=asc"}
+	if got := dequeueAll(&plain); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+// TestFrontier_TrailingSlashPolicy proves that the trailing slash policy
+// decides whether "/docs" and "/docs/" are one URL, also for default
+// documents folded into their directory
+func TestFrontier_TrailingSlashPolicy(t *testing.T) {
+	tests := []struct {
+		policy   string
+		expected []string
+	}{
+		{policy: "strip", expected: []string{"https://example.com/docs", "https://example.com/docs/intro.html"}},
+		{policy: "preserve", expected: []string{"https://example.com/docs", "https://example.com/docs/", "https://example.com/docs/intro.html"}},
+		{policy: "add", expected: []string{"https://example.com/docs/", "https://example.com/docs/intro.html"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			seedURL, _ := url.Parse("https://example.com/")
+			cfg, err := config.WithDefault([]url.URL{*seedURL}).
+				WithDefaultDocuments([]string{"index.html"}).
+				WithTrailingSlashPolicy(tt.policy).
+				Build()
+			if err != nil {
+				t.Fatalf("failed to build config: %v", err)
+			}
+
+			f := frontier.NewCrawlFrontier()
+			f.Init(cfg)
+			for _, raw := range []string{
+				"https://example.com/docs",
+				"https://example.com/docs/",
+				"https://example.com/docs/index.html",
+				"https://example.com/docs/intro.html",
+			} {
+				f.Submit(frontier.NewCrawlAdmissionCandidate(
+					mustURL(t, raw), frontier.SourceCrawl, frontier.NewDiscoveryMetadata(1, nil),
+				))
+			}
+
+			var got []string
+			for {
+				token, ok := f.Dequeue()
+				if !ok {
+					break
+				}
+				tokenURL := token.URL()
+				got = append(got, tokenURL.String())
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFrontier_Reset(t *testing.T) {
+	seedURL, _ := url.Parse("https://example.com/")
+	cfg, err := config.WithDefault([]url.URL{*seedURL}).Build()
+	if err != nil {
+		t.Fatalf("failed to build config: %v", err)
+	}
+
+	f := frontier.NewCrawlFrontier()
+	f.Init(cfg)
+	submit := func(raw string) {
+		f.Submit(frontier.NewCrawlAdmissionCandidate(
+			mustURL(t, raw), frontier.SourceCrawl, frontier.NewDiscoveryMetadata(1, nil),
+		))
+	}
+	submit("https://example.com/docs")
+	submit("https://example.com/guide")
+	if _, ok := f.Dequeue(); !ok {
+		t.Fatal("expected a queued URL before reset")
+	}
+
+	f.Reset()
+	if f.VisitedCount() != 0 {
+		t.Fatalf("expected no visited URL after reset, got %d", f.VisitedCount())
+	}
+	if _, ok := f.Dequeue(); ok {
+		t.Fatal("expected an empty queue after reset")
+	}
+
+	// URLs admitted before the reset are admitted again
+	submit("https://example.com/docs")
+	token, ok := f.Dequeue()
+	if !ok {
+		t.Fatal("expected the URL to be admitted again after reset")
+	}
+	tokenURL := token.URL()
+	if tokenURL.String() != "https://example.com/docs" {
+		t.Fatalf("expected https://example.com/docs, got %s", tokenURL.String())
+	}
+}
@@ -0,0 +1,219 @@
+package mdconvert
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"golang.org/x/net/html"
+)
+
+/*
+Admonitions
+
+Docs frameworks render callouts as classed containers, which would
+otherwise flatten into plain paragraphs:
+
+	MkDocs:     <div class="admonition note"><p class="admonition-title">Note</p>...</div>
+	Docusaurus: <div class="theme-admonition theme-admonition-warning admonition_x1">
+	              <div class="admonitionHeading_y2">warning</div>
+	              <div class="admonitionContent_z3">...</div>
+	            </div>
+
+A container is an admonition when one of its class tokens is "admonition",
+"theme-admonition", "callout" or starts with "admonition_" (CSS modules).
+Its type comes from the first other class token found in the type mapping,
+after stripping a "theme-admonition-" or "callout-" prefix. It is written
+as a GFM alert:
+
+	> [!WARNING]
+	> content
+
+The title element is dropped when it only repeats the type, and kept as a
+bold paragraph below the marker otherwise. An admonition whose type is not
+in the mapping is written as a plain blockquote, title included.
+*/
+
+// GFM alert types an admonition class can map to.
+const (
+	AdmonitionNote      = "NOTE"
+	AdmonitionTip       = "TIP"
+	AdmonitionImportant = "IMPORTANT"
+	AdmonitionWarning   = "WARNING"
+	AdmonitionCaution   = "CAUTION"
+)
+
+// DefaultAdmonitionTypes returns the built-in mapping from admonition class
+// to GFM alert type, covering the MkDocs and Docusaurus type names.
+func DefaultAdmonitionTypes() map[string]string {
+	return map[string]string{
+		"note":      AdmonitionNote,
+		"info":      AdmonitionNote,
+		"seealso":   AdmonitionNote,
+		"abstract":  AdmonitionNote,
+		"tip":       AdmonitionTip,
+		"hint":      AdmonitionTip,
+		"success":   AdmonitionTip,
+		"important": AdmonitionImportant,
+		"warning":   AdmonitionWarning,
+		"attention": AdmonitionWarning,
+		"caution":   AdmonitionCaution,
+		"danger":    AdmonitionCaution,
+		"error":     AdmonitionCaution,
+	}
+}
+
+// admonitionTypePrefixes are stripped from class tokens before the lookup.
+var admonitionTypePrefixes = []string{"theme-admonition-", "callout-"}
+
+var blankLinesPattern = regexp.MustCompile(`\n{3,}`)
+
+// admonitionRenderer writes admonition containers as GFM alerts.
+type admonitionRenderer struct {
+	types map[string]string // class -> alert type, lower-case keys
+}
+
+// render is an early-priority render handler claiming admonition containers.
+func (a admonitionRenderer) render(ctx converter.Context, w converter.Writer, n *html.Node) converter.RenderStatus {
+	if n.Type != html.ElementNode || !isAdmonitionContainer(n) {
+		return converter.RenderTryNext
+	}
+
+	class, alert := a.admonitionType(n)
+	children := childNodes(n)
+	var header, title string
+	if alert != "" {
+		header = "[!" + alert + "]"
+		if titleNode := findAdmonitionTitle(n); titleNode != nil {
+			children = withoutNode(children, titleNode)
+			text := strings.TrimSpace(nodeText(titleNode))
+			if text != "" && !strings.EqualFold(text, class) && !strings.EqualFold(text, alert) {
+				title = "**" + text + "**"
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	ctx.RenderNodes(ctx, &buf, children...)
+	body := strings.TrimSpace(blankLinesPattern.ReplaceAllString(buf.String(), "\n\n"))
+
+	// The title is a paragraph of its own, right below the alert marker
+	content := header
+	if title != "" {
+		content += "\n" + title
+	}
+	if body != "" {
+		switch {
+		case title != "":
+			content += "\n\n" + body
+		case header != "":
+			content += "\n" + body
+		default:
+			content = body
+		}
+	}
+	if content == "" {
+		return converter.RenderSuccess
+	}
+
+	w.WriteString("\n\n")
+	w.WriteString(quoteLines(content))
+	w.WriteString("\n\n")
+	return converter.RenderSuccess
+}
+
+// admonitionType returns the matched class and its alert type, or empty
+// strings when no class token is in the mapping.
+func (a admonitionRenderer) admonitionType(n *html.Node) (string, string) {
+	for _, token := range classTokens(n) {
+		token = strings.ToLower(token)
+		for _, prefix := range admonitionTypePrefixes {
+			token = strings.TrimPrefix(token, prefix)
+		}
+		if alert := a.types[token]; alert != "" {
+			return token, alert
+		}
+	}
+	return "", ""
+}
+
+// isAdmonitionContainer reports whether n carries an admonition class.
+func isAdmonitionContainer(n *html.Node) bool {
+	for _, token := range classTokens(n) {
+		switch {
+		case token == "admonition", token == "theme-admonition", token == "callout":
+			return true
+		case strings.HasPrefix(token, "admonition_"):
+			return true
+		}
+	}
+	return false
+}
+
+// findAdmonitionTitle returns the first child of n that is an admonition
+// title, or nil.
+func findAdmonitionTitle(n *html.Node) *html.Node {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		for _, token := range classTokens(c) {
+			if token == "admonition-title" || token == "callout-title" || strings.HasPrefix(token, "admonitionHeading") {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// quoteLines prefixes every line of s with a blockquote marker.
+func quoteLines(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = ">"
+		} else {
+			lines[i] = "> " + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func classTokens(n *html.Node) []string {
+	for _, attr := range n.Attr {
+		if attr.Key == "class" {
+			return strings.Fields(attr.Val)
+		}
+	}
+	return nil
+}
+
+func childNodes(n *html.Node) []*html.Node {
+	var nodes []*html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		nodes = append(nodes, c)
+	}
+	return nodes
+}
+
+func withoutNode(nodes []*html.Node, skip *html.Node) []*html.Node {
+	kept := make([]*html.Node, 0, len(nodes))
+	for _, node := range nodes {
+		if node != skip {
+			kept = append(kept, node)
+		}
+	}
+	return kept
+}
+
+func nodeText(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(nodeText(c))
+	}
+	return b.String()
+}
@@ -5,6 +5,7 @@ package mdconvert
 type ConversionResult struct {
 	markdownContent []byte
 	linkRefs        []LinkRef
+	preservedTags   []string
 }
 
 func NewConversionResult(
@@ -25,6 +26,13 @@ func (c *ConversionResult) GetLinkRefs() []LinkRef {
 	return c.linkRefs
 }
 
+// PreservedTags returns the sorted names of elements that were kept as raw
+// HTML because the converter has no rule for them. It is empty unless
+// PreserveUnknownHTML is enabled on the rule.
+func (c *ConversionResult) PreservedTags() []string {
+	return c.preservedTags
+}
+
 type LinkKind string
 
 const (
@@ -0,0 +1,92 @@
+package mdconvert
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"golang.org/x/net/html"
+)
+
+/*
+Unknown HTML Preservation
+
+By default, elements the converter has no rule for are unwrapped: their
+tags are dropped and only their text survives. With PreserveUnknownHTML
+enabled, such elements (e.g. <details>/<summary>) are emitted verbatim as
+raw HTML blocks instead, which GFM renders as-is.
+
+An element counts as known when one of the converter plugins renders it,
+or when it is a structural or phrasing container whose children convert
+without loss (div, section, span, ...).
+*/
+
+// knownTags lists elements that convert without needing raw HTML.
+var knownTags = map[string]struct{}{
+	// document structure and generic containers
+	"html": {}, "body": {}, "main": {}, "article": {}, "section": {}, "div": {},
+	"header": {}, "footer": {}, "nav": {}, "aside": {}, "figure": {}, "figcaption": {},
+	"address": {}, "p": {}, "span": {}, "center": {}, "font": {},
+	// <picture> sources are resolved into the <img> before conversion
+	"picture": {}, "source": {},
+	// tags the converter removes on purpose
+	"head": {}, "script": {}, "style": {}, "link": {}, "meta": {}, "iframe": {},
+	"noscript": {}, "input": {}, "textarea": {},
+	// commonmark plugin
+	"h1": {}, "h2": {}, "h3": {}, "h4": {}, "h5": {}, "h6": {},
+	"strong": {}, "b": {}, "em": {}, "i": {}, "a": {}, "img": {}, "br": {}, "hr": {},
+	"ul": {}, "ol": {}, "li": {}, "pre": {}, "code": {}, "var": {}, "samp": {},
+	"kbd": {}, "tt": {}, "blockquote": {},
+	// table plugin
+	"table": {}, "thead": {}, "tbody": {}, "tfoot": {}, "tr": {}, "th": {}, "td": {},
+	"caption": {}, "colgroup": {}, "col": {},
+	// inline phrasing whose text converts without loss
+	"abbr": {}, "cite": {}, "dfn": {}, "q": {}, "small": {}, "sub": {}, "sup": {},
+	"time": {}, "u": {}, "mark": {}, "ins": {}, "del": {}, "s": {}, "strike": {},
+	"label": {}, "bdi": {}, "bdo": {}, "wbr": {},
+}
+
+// rawHTMLRecorder collects the tags preserved as raw HTML during one conversion.
+type rawHTMLRecorder struct {
+	tags map[string]struct{}
+}
+
+func newRawHTMLRecorder() *rawHTMLRecorder {
+	return &rawHTMLRecorder{tags: make(map[string]struct{})}
+}
+
+// render is a late-priority render handler. It only claims elements no
+// other handler or known container covers, writing them as raw HTML blocks.
+func (r *rawHTMLRecorder) render(_ converter.Context, w converter.Writer, n *html.Node) converter.RenderStatus {
+	if n.Type != html.ElementNode {
+		return converter.RenderTryNext
+	}
+	name := n.Data
+	if _, ok := knownTags[name]; ok {
+		return converter.RenderTryNext
+	}
+
+	var buf strings.Builder
+	if err := html.Render(&buf, n); err != nil {
+		return converter.RenderTryNext
+	}
+	r.tags[name] = struct{}{}
+
+	w.WriteString("\n\n")
+	w.WriteString(buf.String())
+	w.WriteString("\n\n")
+	return converter.RenderSuccess
+}
+
+// preservedTags returns the recorded tag names in sorted order.
+func (r *rawHTMLRecorder) preservedTags() []string {
+	if len(r.tags) == 0 {
+		return nil
+	}
+	tags := make([]string, 0, len(r.tags))
+	for tag := range r.tags {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
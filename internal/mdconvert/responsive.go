@@ -0,0 +1,197 @@
+package mdconvert
+
+import (
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+/*
+Responsive Images
+
+Markdown images carry a single URL, while responsive HTML offers several:
+- <img srcset="a.png 1x, b.png 2x"> lists candidates by pixel density
+  ("2x") or intrinsic width ("800w")
+- <picture><source srcset type media>...<img></picture> lists alternative
+  sources the browser tries in order before falling back to the <img>
+
+Before conversion, each <img> gets the src of the chosen candidate, so the
+Markdown output, the extracted LinkRefs and therefore the downloaded asset
+all agree. Within <picture>, the first <source> without a media query and
+with an image type (or no type) wins, as a browser without viewport
+information would pick it; otherwise the <img> itself is used.
+
+Within a srcset, a target density of 0 picks the highest resolution. A
+positive target picks the smallest density at or above it, falling back to
+the highest. Width descriptors cannot be mapped to a density without layout
+information, so the widest candidate is used.
+*/
+
+// srcsetCandidate is one entry of a srcset attribute.
+type srcsetCandidate struct {
+	url string
+	// density is the "x" descriptor; 0 when a width descriptor is used
+	density float64
+	// width is the "w" descriptor; 0 when a density descriptor is used
+	width int
+}
+
+// selectResponsiveImages rewrites the src of every <img> under root to the
+// chosen responsive candidate. Images without srcset or <picture> sources
+// are left untouched.
+func selectResponsiveImages(root *html.Node, targetDensity float64) {
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "img" {
+			if chosen := chooseImageSource(n, targetDensity); chosen != "" {
+				setAttr(n, "src", chosen)
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(root)
+}
+
+// chooseImageSource returns the URL img should use, or "" to keep its src.
+func chooseImageSource(img *html.Node, targetDensity float64) string {
+	if picture := img.Parent; picture != nil && picture.Type == html.ElementNode && picture.Data == "picture" {
+		for c := picture.FirstChild; c != nil && c != img; c = c.NextSibling {
+			if c.Type != html.ElementNode || c.Data != "source" || !isSelectableSource(c) {
+				continue
+			}
+			if chosen := chooseFromSrcset(getAttr(c, "srcset"), targetDensity); chosen != "" {
+				return chosen
+			}
+		}
+	}
+	return chooseFromSrcset(getAttr(img, "srcset"), targetDensity)
+}
+
+// isSelectableSource reports whether a <picture> <source> can be chosen
+// without evaluating media queries.
+func isSelectableSource(source *html.Node) bool {
+	if strings.TrimSpace(getAttr(source, "media")) != "" {
+		return false
+	}
+	mediaType := strings.ToLower(strings.TrimSpace(getAttr(source, "type")))
+	return mediaType == "" || strings.HasPrefix(mediaType, "image/")
+}
+
+// chooseFromSrcset picks a candidate URL from a srcset attribute value.
+func chooseFromSrcset(srcset string, targetDensity float64) string {
+	candidates := parseSrcset(srcset)
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	var widest, highest, target *srcsetCandidate
+	for i := range candidates {
+		c := &candidates[i]
+		if c.width > 0 {
+			if widest == nil || c.width > widest.width {
+				widest = c
+			}
+			continue
+		}
+		if highest == nil || c.density > highest.density {
+			highest = c
+		}
+		if targetDensity > 0 && c.density >= targetDensity && (target == nil || c.density < target.density) {
+			target = c
+		}
+	}
+
+	switch {
+	case target != nil:
+		return target.url
+	case highest != nil:
+		return highest.url
+	default:
+		return widest.url
+	}
+}
+
+// parseSrcset splits a srcset attribute into candidates. A candidate
+// without a descriptor counts as 1x; invalid descriptors drop the candidate.
+func parseSrcset(srcset string) []srcsetCandidate {
+	var candidates []srcsetCandidate
+	rest := srcset
+	for {
+		rest = strings.TrimLeft(rest, " \t\n\r\f,")
+		if rest == "" {
+			return candidates
+		}
+
+		end := strings.IndexAny(rest, " \t\n\r\f")
+		if end < 0 {
+			end = len(rest)
+		}
+		rawURL := rest[:end]
+		rest = rest[end:]
+
+		descriptor := ""
+		if trimmed := strings.TrimRight(rawURL, ","); trimmed != rawURL {
+			// A trailing comma ends the candidate without a descriptor
+			rawURL = trimmed
+		} else {
+			comma := strings.IndexByte(rest, ',')
+			if comma < 0 {
+				comma = len(rest)
+			}
+			descriptor = strings.TrimSpace(rest[:comma])
+			rest = rest[comma:]
+		}
+
+		if candidate, ok := newSrcsetCandidate(rawURL, descriptor); ok {
+			candidates = append(candidates, candidate)
+		}
+	}
+}
+
+func newSrcsetCandidate(rawURL, descriptor string) (srcsetCandidate, bool) {
+	if rawURL == "" {
+		return srcsetCandidate{}, false
+	}
+	if descriptor == "" {
+		return srcsetCandidate{url: rawURL, density: 1}, true
+	}
+
+	value, unit := descriptor[:len(descriptor)-1], descriptor[len(descriptor)-1]
+	switch unit {
+	case 'x', 'X':
+		density, err := strconv.ParseFloat(value, 64)
+		if err != nil || density <= 0 {
+			return srcsetCandidate{}, false
+		}
+		return srcsetCandidate{url: rawURL, density: density}, true
+	case 'w', 'W':
+		width, err := strconv.Atoi(value)
+		if err != nil || width <= 0 {
+			return srcsetCandidate{}, false
+		}
+		return srcsetCandidate{url: rawURL, width: width}, true
+	}
+	return srcsetCandidate{}, false
+}
+
+func getAttr(n *html.Node, key string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+func setAttr(n *html.Node, key, val string) {
+	for i := range n.Attr {
+		if n.Attr[i].Key == key {
+			n.Attr[i].Val = val
+			return
+		}
+	}
+	n.Attr = append(n.Attr, html.Attribute{Key: key, Val: val})
+}
@@ -29,10 +29,14 @@ Conversion Rules
 - Headings map directly (h1-h6 to # - ######)
 - Code blocks preserved verbatim
 - Tables converted structurally (GFM)
-- Links and images preserved as-is (no resolution)
+- Links and images preserved as-is (no resolution); responsive images
+  use their chosen srcset / <picture> candidate
+- Admonition / callout containers become GFM alerts (see admonition.go)
+- Spans with a mapped class keep their inline meaning (see spans.go)
 - DOM order preserved
 
-Inline styles and raw HTML are avoided.
+Inline styles and raw HTML are avoided, unless PreserveUnknownHTML is
+enabled (see rawhtml.go).
 */
 
 // ConvertRule defines the interface for converting sanitized HTML to Markdown.
@@ -47,12 +51,19 @@ var _ ConvertRule = (*StrictConversionRule)(nil)
 type StrictConversionRule struct {
 	metadataSink metadata.MetadataSink
 	debugLogger  debug.DebugLogger
+
+	preserveUnknownHTML bool
+	imageDensity        float64
+	admonitionTypes     map[string]string
+	spanStyles          map[string]string
 }
 
 func NewRule(metadataSink metadata.MetadataSink) *StrictConversionRule {
 	return &StrictConversionRule{
-		metadataSink: metadataSink,
-		debugLogger:  debug.NewNoOpLogger(),
+		metadataSink:    metadataSink,
+		debugLogger:     debug.NewNoOpLogger(),
+		admonitionTypes: DefaultAdmonitionTypes(),
+		spanStyles:      DefaultSpanStyles(),
 	}
 }
 
@@ -67,6 +78,69 @@ func (s *StrictConversionRule) SetDebugLogger(logger debug.DebugLogger) {
 	s.debugLogger = logger
 }
 
+// SetPreserveUnknownHTML controls whether elements the converter has no
+// rule for are kept as raw HTML blocks instead of being unwrapped to text.
+// Defaults to false.
+func (s *StrictConversionRule) SetPreserveUnknownHTML(preserve bool) {
+	s.preserveUnknownHTML = preserve
+}
+
+// PreserveUnknownHTML reports whether unknown elements are kept as raw HTML.
+func (s *StrictConversionRule) PreserveUnknownHTML() bool {
+	return s.preserveUnknownHTML
+}
+
+// SetImageDensity sets the pixel density used to choose among srcset
+// candidates (see responsive.go). 0, the default, picks the highest
+// resolution; negative values are treated as 0.
+func (s *StrictConversionRule) SetImageDensity(density float64) {
+	if density < 0 {
+		density = 0
+	}
+	s.imageDensity = density
+}
+
+// ImageDensity returns the target pixel density for srcset candidates.
+func (s *StrictConversionRule) ImageDensity() float64 {
+	return s.imageDensity
+}
+
+// SetAdmonitionTypes overrides entries of the admonition class to GFM alert
+// type mapping (see admonition.go). Classes are matched case-insensitively;
+// an empty type removes the class, so its admonitions fall back to a plain
+// blockquote.
+func (s *StrictConversionRule) SetAdmonitionTypes(overrides map[string]string) {
+	types := DefaultAdmonitionTypes()
+	for class, alert := range overrides {
+		class = strings.ToLower(class)
+		if alert == "" {
+			delete(types, class)
+			continue
+		}
+		types[class] = strings.ToUpper(alert)
+	}
+	s.admonitionTypes = types
+}
+
+// SetSpanStyles overrides entries of the span class to style mapping (see
+// spans.go). Classes are matched case-insensitively; an empty style removes
+// the class, so its spans are flattened to text. Unknown styles are ignored.
+func (s *StrictConversionRule) SetSpanStyles(overrides map[string]string) {
+	styles := DefaultSpanStyles()
+	for class, style := range overrides {
+		class = strings.ToLower(class)
+		style = strings.ToLower(style)
+		if style == "" {
+			delete(styles, class)
+			continue
+		}
+		if IsValidSpanStyle(style) {
+			styles[class] = style
+		}
+	}
+	s.spanStyles = styles
+}
+
 func (s *StrictConversionRule) Convert(
 	sanitizedHTMLDoc sanitizer.SanitizedHTMLDoc,
 	pageURL string,
@@ -110,6 +184,9 @@ func (s *StrictConversionRule) convert(htmlDoc *html.Node) (ConversionResult, *C
 		)
 	}
 
+	// Point each <img> at its chosen srcset / <picture> candidate
+	selectResponsiveImages(htmlDoc, s.imageDensity)
+
 	// Log converter creation if debug enabled
 	if s.debugLogger.Enabled() {
 		s.debugLogger.LogStep(context.TODO(), "mdconvert", "create_converter", debug.FieldMap{
@@ -126,6 +203,17 @@ func (s *StrictConversionRule) convert(htmlDoc *html.Node) (ConversionResult, *C
 		),
 	)
 
+	admonitions := admonitionRenderer{types: s.admonitionTypes}
+	conv.Register.Renderer(admonitions.render, converter.PriorityEarly)
+	spans := spanRenderer{styles: s.spanStyles}
+	conv.Register.Renderer(spans.render, converter.PriorityEarly)
+
+	var rawHTML *rawHTMLRecorder
+	if s.preserveUnknownHTML {
+		rawHTML = newRawHTMLRecorder()
+		conv.Register.Renderer(rawHTML.render, converter.PriorityLate)
+	}
+
 	// Convert the HTML node to markdown
 	markdown, err := conv.ConvertNode(htmlDoc)
 	if err != nil {
@@ -146,7 +234,17 @@ func (s *StrictConversionRule) convert(htmlDoc *html.Node) (ConversionResult, *C
 		})
 	}
 
-	return NewConversionResult(markdown, linkRefs), nil
+	result := NewConversionResult(markdown, linkRefs)
+	if rawHTML != nil {
+		result.preservedTags = rawHTML.preservedTags()
+		if len(result.preservedTags) > 0 && s.debugLogger.Enabled() {
+			s.debugLogger.LogStep(context.TODO(), "mdconvert", "preserve_raw_html", debug.FieldMap{
+				"tags": result.preservedTags,
+			})
+		}
+	}
+
+	return result, nil
 }
 
 // extractLinkRefs walks the HTML DOM and extracts all link references.
@@ -87,6 +87,16 @@ func TestConvert_TableDriven(t *testing.T) {
 			fixture: "mdconvert_image_passthrough",
 			desc:    "M9",
 		},
+		{
+			name:    "ResponsiveImagesHighestCandidate",
+			fixture: "mdconvert_responsive_images",
+			desc:    "srcset and <picture> resolve to the highest-resolution candidate",
+		},
+		{
+			name:    "AdmonitionsBecomeAlerts",
+			fixture: "mdconvert_admonitions",
+			desc:    "MkDocs and Docusaurus admonitions map to GFM alerts",
+		},
 		{
 			name:    "UnknownTagTextOnly",
 			fixture: "mdconvert_unknown_tag_text_only",
@@ -341,3 +351,128 @@ func TestConvert_DebugLoggingDisabled(t *testing.T) {
 
 	// Test passes if no panic or error occurs with NoOpLogger
 }
+
+const detailsHTML = `<html><body>
+<h2>FAQ</h2>
+<details><summary>Why?</summary><p>Because.</p></details>
+<p>After.</p>
+</body></html>`
+
+// TestConvert_PreserveUnknownHTML verifies that elements without a
+// conversion rule are emitted as raw HTML blocks when enabled.
+func TestConvert_PreserveUnknownHTML(t *testing.T) {
+	rule := createTestRule()
+	rule.SetPreserveUnknownHTML(true)
+
+	result, err := rule.Convert(createSanitizedDoc(t, detailsHTML), "https://example.com/page")
+	require.NoError(t, err)
+
+	md := string(result.GetMarkdownContent())
+	assert.Contains(t, md, "## FAQ")
+	assert.Contains(t, md, "\n\n<details><summary>Why?</summary><p>Because.</p></details>\n\n")
+	assert.Contains(t, md, "After.")
+	assert.Equal(t, []string{"details"}, result.PreservedTags())
+}
+
+const unknownAdmonitionHTML = `<div class="admonition example"><p class="admonition-title">Example</p><p>Run it twice.</p></div>`
+
+// TestConvert_Admonition_UnknownClassFallsBackToBlockquote verifies that an
+// admonition whose type is not mapped keeps its title in a plain blockquote.
+func TestConvert_Admonition_UnknownClassFallsBackToBlockquote(t *testing.T) {
+	rule := createTestRule()
+
+	result, err := rule.Convert(createSanitizedDoc(t, unknownAdmonitionHTML), "https://example.com/page")
+	require.NoError(t, err)
+
+	assert.Equal(t, "> Example\n>\n> Run it twice.", string(result.GetMarkdownContent()))
+}
+
+// TestConvert_Admonition_CustomTypes verifies that configured classes extend
+// and override the built-in mapping.
+func TestConvert_Admonition_CustomTypes(t *testing.T) {
+	rule := createTestRule()
+	rule.SetAdmonitionTypes(map[string]string{"Example": "tip", "note": ""})
+
+	result, err := rule.Convert(createSanitizedDoc(t, unknownAdmonitionHTML), "https://example.com/page")
+	require.NoError(t, err)
+	assert.Equal(t, "> [!TIP]\n> Run it twice.", string(result.GetMarkdownContent()))
+
+	// A class mapped to no type falls back to a blockquote
+	noteHTML := `<div class="admonition note"><p class="admonition-title">Note</p><p>Read once.</p></div>`
+	result, err = rule.Convert(createSanitizedDoc(t, noteHTML), "https://example.com/page")
+	require.NoError(t, err)
+	assert.Equal(t, "> Note\n>\n> Read once.", string(result.GetMarkdownContent()))
+}
+
+const spansHTML = `<p>Press <span class="kbd">Ctrl</span>, then <span class="hljs-keyword">return</span> and <span class="note">read</span>.</p>`
+
+// TestConvert_SpanStyles_Default verifies that keyboard spans become <kbd>
+// and that unmapped spans are flattened to their text.
+func TestConvert_SpanStyles_Default(t *testing.T) {
+	rule := createTestRule()
+
+	result, err := rule.Convert(createSanitizedDoc(t, spansHTML), "https://example.com/page")
+	require.NoError(t, err)
+	assert.Equal(t, "Press <kbd>Ctrl</kbd>, then return and read.", string(result.GetMarkdownContent()))
+}
+
+// TestConvert_SpanStyles_Custom verifies that configured classes extend and
+// override the built-in mapping.
+func TestConvert_SpanStyles_Custom(t *testing.T) {
+	rule := createTestRule()
+	rule.SetSpanStyles(map[string]string{"HLJS-Keyword": "code", "note": "em", "kbd": ""})
+
+	result, err := rule.Convert(createSanitizedDoc(t, spansHTML), "https://example.com/page")
+	require.NoError(t, err)
+	assert.Equal(t, "Press Ctrl, then `return` and *read*.", string(result.GetMarkdownContent()))
+}
+
+// TestConvert_UnknownHTMLStrippedByDefault verifies that unknown elements
+// are unwrapped to their text when preservation is disabled.
+func TestConvert_UnknownHTMLStrippedByDefault(t *testing.T) {
+	rule := createTestRule()
+
+	result, err := rule.Convert(createSanitizedDoc(t, detailsHTML), "https://example.com/page")
+	require.NoError(t, err)
+
+	md := string(result.GetMarkdownContent())
+	assert.NotContains(t, md, "<details>")
+	assert.NotContains(t, md, "<summary>")
+	assert.Contains(t, md, "Why?")
+	assert.Contains(t, md, "Because.")
+	assert.Empty(t, result.PreservedTags())
+}
+
+// TestConvert_ResponsiveImages_TargetDensity verifies that a configured
+// density picks the closest candidate at or above it.
+func TestConvert_ResponsiveImages_TargetDensity(t *testing.T) {
+	htmlContent := loadHtmlFixture(t, "mdconvert_responsive_images.html")
+	rule := createTestRule()
+	rule.SetImageDensity(1)
+
+	result, err := rule.Convert(createSanitizedDoc(t, string(htmlContent)), "https://example.com/page")
+	require.NoError(t, err)
+
+	assert.Equal(t, "# Responsive Images\n\n![Diagram](/img/diagram-1x.png)\n\n![Hero](/img/hero.webp)",
+		string(result.GetMarkdownContent()))
+
+	var images []string
+	for _, ref := range result.GetLinkRefs() {
+		if ref.GetKind() == mdconvert.KindImage {
+			images = append(images, ref.GetRaw())
+		}
+	}
+	assert.Equal(t, []string{"/img/diagram-1x.png", "/img/hero.webp"}, images)
+}
+
+// TestConvert_ResponsiveImages_WidthDescriptors verifies that width
+// descriptors resolve to the widest candidate.
+func TestConvert_ResponsiveImages_WidthDescriptors(t *testing.T) {
+	htmlContent := `<img src="/a-320.png" srcset="/a-320.png 320w, /a-1280.png 1280w, /a-640.png 640w" alt="A">`
+	rule := createTestRule()
+
+	result, err := rule.Convert(createSanitizedDoc(t, htmlContent), "https://example.com/page")
+	require.NoError(t, err)
+
+	assert.Equal(t, "![A](/a-1280.png)", string(result.GetMarkdownContent()))
+}
@@ -0,0 +1,126 @@
+package mdconvert
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"golang.org/x/net/html"
+)
+
+/*
+Span Styles
+
+Spans carry no meaning of their own and are flattened to their text. Some
+classes do mark meaningful inline elements, such as keyboard keys or
+language keywords:
+
+	<span class="kbd">Ctrl</span>            -> <kbd>Ctrl</kbd>
+	<span class="hljs-keyword">return</span> -> `return`
+
+A span whose first class token in the style mapping has a style is written
+in that style instead:
+
+  - "code":   inline code
+  - "strong": bold
+  - "em":     italic
+  - "<tag>":  the text wrapped in the inline HTML element tag, e.g. "<kbd>"
+
+Classes are matched case-insensitively. Spans with no mapped class are
+flattened as before. Code blocks are written verbatim and are not affected.
+*/
+
+// Span styles a class can map to, besides an inline HTML tag.
+const (
+	SpanStyleCode   = "code"
+	SpanStyleStrong = "strong"
+	SpanStyleEm     = "em"
+)
+
+// spanTagPattern matches an inline HTML tag style such as "<kbd>".
+var spanTagPattern = regexp.MustCompile(`^<([a-z][a-z0-9]*)>$`)
+
+// DefaultSpanStyles returns the built-in mapping from span class to style.
+func DefaultSpanStyles() map[string]string {
+	return map[string]string{
+		"kbd":      "<kbd>",
+		"keyboard": "<kbd>",
+	}
+}
+
+// IsValidSpanStyle reports whether style is a known span style or an
+// inline HTML tag such as "<kbd>".
+func IsValidSpanStyle(style string) bool {
+	switch style {
+	case SpanStyleCode, SpanStyleStrong, SpanStyleEm:
+		return true
+	}
+	return spanTagPattern.MatchString(style)
+}
+
+// spanRenderer writes classed spans in their mapped style.
+type spanRenderer struct {
+	styles map[string]string // class -> style, lower-case keys
+}
+
+// render is an early-priority render handler claiming mapped spans.
+func (r spanRenderer) render(ctx converter.Context, w converter.Writer, n *html.Node) converter.RenderStatus {
+	if n.Type != html.ElementNode || n.Data != "span" {
+		return converter.RenderTryNext
+	}
+	style := r.spanStyle(n)
+	if style == "" {
+		return converter.RenderTryNext
+	}
+
+	switch style {
+	case SpanStyleCode:
+		text := strings.TrimSpace(nodeText(n))
+		if text != "" {
+			w.WriteString(inlineCode(text))
+		}
+	case SpanStyleStrong, SpanStyleEm:
+		var buf bytes.Buffer
+		ctx.RenderNodes(ctx, &buf, childNodes(n)...)
+		content := strings.TrimSpace(buf.String())
+		if content == "" {
+			return converter.RenderSuccess
+		}
+		delimiter := "**"
+		if style == SpanStyleEm {
+			delimiter = "*"
+		}
+		w.WriteString(delimiter + content + delimiter)
+	default:
+		tag := spanTagPattern.FindStringSubmatch(style)[1]
+		text := strings.TrimSpace(nodeText(n))
+		if text != "" {
+			w.WriteString("<" + tag + ">" + html.EscapeString(text) + "</" + tag + ">")
+		}
+	}
+	return converter.RenderSuccess
+}
+
+// spanStyle returns the style of the first mapped class token of n, or ""
+// when none is mapped.
+func (r spanRenderer) spanStyle(n *html.Node) string {
+	for _, token := range classTokens(n) {
+		if style := r.styles[strings.ToLower(token)]; style != "" {
+			return style
+		}
+	}
+	return ""
+}
+
+// inlineCode wraps text in enough backticks to hold the backticks it contains.
+func inlineCode(text string) string {
+	fence := "`"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	if fence == "`" {
+		return fence + text + fence
+	}
+	return fence + " " + text + " " + fence
+}
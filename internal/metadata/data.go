@@ -1,6 +1,8 @@
 package metadata
 
 import (
+	"encoding/json"
+	"strconv"
 	"time"
 )
 
@@ -23,6 +25,10 @@ type FetchEvent struct {
 	retryCount  int
 	crawlDepth  int
 	kind        FetchKind
+	// trace is set only for fetches made with request tracing enabled
+	trace *RequestTrace
+	// cacheHit is set when the response was served from the fetch cache
+	cacheHit bool
 }
 
 // NewFetchEvent constructs an immutable FetchEvent.
@@ -57,6 +63,69 @@ func (f FetchEvent) RetryCount() int         { return f.retryCount }
 func (f FetchEvent) CrawlDepth() int         { return f.crawlDepth }
 func (f FetchEvent) Kind() FetchKind         { return f.kind }
 
+// Trace returns the connection timings and TLS details of the fetch, or nil
+// when the fetch was not traced.
+func (f FetchEvent) Trace() *RequestTrace { return f.trace }
+
+// WithTrace returns a copy of the event carrying trace.
+func (f FetchEvent) WithTrace(trace RequestTrace) FetchEvent {
+	f.trace = &trace
+	return f
+}
+
+// CacheHit reports whether the response was served from the fetch cache
+// instead of the network.
+func (f FetchEvent) CacheHit() bool { return f.cacheHit }
+
+// WithCacheHit returns a copy of the event marked as served from the fetch cache.
+func (f FetchEvent) WithCacheHit() FetchEvent {
+	f.cacheHit = true
+	return f
+}
+
+// RequestTrace describes how a traced request reached its server: the time
+// spent in each connection phase and the negotiated TLS parameters. Phases
+// that did not happen, such as DNS and connect on a reused connection or TLS
+// over plain HTTP, are zero.
+type RequestTrace struct {
+	dnsLookup       time.Duration
+	connect         time.Duration
+	tlsHandshake    time.Duration
+	timeToFirstByte time.Duration
+	tlsVersion      string
+	tlsCipherSuite  string
+	reusedConn      bool
+}
+
+// NewRequestTrace constructs an immutable RequestTrace.
+func NewRequestTrace(
+	dnsLookup time.Duration,
+	connect time.Duration,
+	tlsHandshake time.Duration,
+	timeToFirstByte time.Duration,
+	tlsVersion string,
+	tlsCipherSuite string,
+	reusedConn bool,
+) RequestTrace {
+	return RequestTrace{
+		dnsLookup:       dnsLookup,
+		connect:         connect,
+		tlsHandshake:    tlsHandshake,
+		timeToFirstByte: timeToFirstByte,
+		tlsVersion:      tlsVersion,
+		tlsCipherSuite:  tlsCipherSuite,
+		reusedConn:      reusedConn,
+	}
+}
+
+func (r RequestTrace) DNSLookup() time.Duration       { return r.dnsLookup }
+func (r RequestTrace) Connect() time.Duration         { return r.connect }
+func (r RequestTrace) TLSHandshake() time.Duration    { return r.tlsHandshake }
+func (r RequestTrace) TimeToFirstByte() time.Duration { return r.timeToFirstByte }
+func (r RequestTrace) TLSVersion() string             { return r.tlsVersion }
+func (r RequestTrace) TLSCipherSuite() string         { return r.tlsCipherSuite }
+func (r RequestTrace) ReusedConn() bool               { return r.reusedConn }
+
 /*
 CrawlStats represents a terminal, derived summary of a completed crawl.
   - Contains only aggregate counts and timestamps.
@@ -109,6 +178,9 @@ type ArtifactKind string
 const (
 	ArtifactMarkdown ArtifactKind = "markdown"
 	ArtifactAsset    ArtifactKind = "asset"
+	ArtifactRawHTML  ArtifactKind = "raw_html"
+	// ArtifactOutputFormat is a page written in an additional output format
+	ArtifactOutputFormat ArtifactKind = "output_format"
 )
 
 type ArtifactRecord struct {
@@ -194,27 +266,52 @@ func (p PipelineEvent) RecordedAt() time.Time { return p.recordedAt }
 func (p PipelineEvent) LinksFound() int       { return p.linksFound }
 
 // SkipReason classifies why a URL was not crawled.
+// A skip is a deliberate, policy-driven outcome and is recorded via
+// RecordSkip rather than RecordError.
 type SkipReason string
 
 const (
-	SkipReasonRobotsDisallow SkipReason = "robots_disallow"
-	SkipReasonOutOfScope     SkipReason = "out_of_scope"
-	SkipReasonAlreadyVisited SkipReason = "already_visited"
+	SkipReasonRobotsDisallow    SkipReason = "robots_disallow"
+	SkipReasonOutOfScope        SkipReason = "out_of_scope"
+	SkipReasonAlreadyVisited    SkipReason = "already_visited"
+	SkipReasonContentType       SkipReason = "content_type"
+	SkipReasonResponseTooLarge  SkipReason = "response_too_large"
+	SkipReasonAssetTooLarge     SkipReason = "asset_too_large"
+	SkipReasonDuplicateContent  SkipReason = "duplicate_content"
+	SkipReasonDepthQuota        SkipReason = "depth_quota"
+	SkipReasonExcludedExtension SkipReason = "excluded_extension"
+	SkipReasonLinkText          SkipReason = "link_text"
+	// SkipReasonRedirectOutOfScope marks a page whose fetch redirected to
+	// a host outside the crawl scope; the skipped URL is the final URL
+	SkipReasonRedirectOutOfScope SkipReason = "redirect_out_of_scope"
+	// SkipReasonPatternRepeat marks a URL differing from config.MaxPatternRepeats
+	// admitted URLs only in numeric path segments or query values, such as
+	// endless calendar or pagination links
+	SkipReasonPatternRepeat SkipReason = "pattern_repeat"
+	// SkipReasonTooLargeToParse marks a fetched page whose HTML body exceeds
+	// config.MaxHTMLParseBytes, so it is not handed to the extractor
+	SkipReasonTooLargeToParse SkipReason = "too_large_to_parse"
 )
 
 // SkipEvent records that a URL was admitted to the frontier but not crawled.
+// attrs holds any ad-hoc contextual key/value pairs (e.g. AttrHost, AttrPageURL).
 type SkipEvent struct {
 	skippedURL string
 	reason     SkipReason
 	recordedAt time.Time
+	attrs      []Attribute
 }
 
 // NewSkipEvent constructs an immutable SkipEvent.
-func NewSkipEvent(skippedURL string, reason SkipReason, recordedAt time.Time) SkipEvent {
+// attrs is copied to prevent external mutation.
+func NewSkipEvent(skippedURL string, reason SkipReason, recordedAt time.Time, attrs []Attribute) SkipEvent {
+	cp := make([]Attribute, len(attrs))
+	copy(cp, attrs)
 	return SkipEvent{
 		skippedURL: skippedURL,
 		reason:     reason,
 		recordedAt: recordedAt,
+		attrs:      cp,
 	}
 }
 
@@ -222,6 +319,50 @@ func (s SkipEvent) SkippedURL() string    { return s.skippedURL }
 func (s SkipEvent) Reason() SkipReason    { return s.reason }
 func (s SkipEvent) RecordedAt() time.Time { return s.recordedAt }
 
+// Attrs returns a copy of the attribute slice to prevent external mutation.
+func (s SkipEvent) Attrs() []Attribute {
+	cp := make([]Attribute, len(s.attrs))
+	copy(cp, s.attrs)
+	return cp
+}
+
+// TuningReason explains why the crawl auto-tuner changed its settings.
+type TuningReason string
+
+const (
+	// TuningReasonInitial reports the starting settings.
+	TuningReasonInitial TuningReason = "initial"
+	// TuningReasonThrottled reports a multiplicative back-off after a 429 or 5xx.
+	TuningReasonThrottled TuningReason = "throttled"
+	// TuningReasonRecovered reports an additive step up after sustained success.
+	TuningReasonRecovered TuningReason = "recovered"
+)
+
+// TuningEvent records the crawl auto-tuner's settings after it changed
+// them. The delay is applied to requests; the recommended concurrency is
+// advisory, since pages are fetched one at a time.
+type TuningEvent struct {
+	recommendedConcurrency int
+	delay                  time.Duration
+	reason                 TuningReason
+	recordedAt             time.Time
+}
+
+// NewTuningEvent constructs an immutable TuningEvent.
+func NewTuningEvent(recommendedConcurrency int, delay time.Duration, reason TuningReason, recordedAt time.Time) TuningEvent {
+	return TuningEvent{
+		recommendedConcurrency: recommendedConcurrency,
+		delay:                  delay,
+		reason:                 reason,
+		recordedAt:             recordedAt,
+	}
+}
+
+func (t TuningEvent) RecommendedConcurrency() int { return t.recommendedConcurrency }
+func (t TuningEvent) Delay() time.Duration        { return t.delay }
+func (t TuningEvent) Reason() TuningReason        { return t.reason }
+func (t TuningEvent) RecordedAt() time.Time       { return t.recordedAt }
+
 // ErrorRecord is the typed struct accepted by RecordError. It follows the same
 // constructor + accessor pattern as FetchEvent, ArtifactRecord, PipelineEvent,
 // and SkipEvent, making RecordError consistent with every other MetadataSink method.
@@ -282,6 +423,7 @@ const (
 	EventKindSkip     EventKind = "skip"
 	EventKindError    EventKind = "error"
 	EventKindStats    EventKind = "stats"
+	EventKindTuning   EventKind = "tuning"
 )
 
 // Event is a sealed discriminated union of all event types recorded by the Recorder.
@@ -294,6 +436,7 @@ type Event struct {
 	skip     *SkipEvent
 	error    *ErrorRecord
 	stats    *CrawlStats
+	tuning   *TuningEvent
 }
 
 func (e Event) Kind() EventKind           { return e.kind }
@@ -303,6 +446,7 @@ func (e Event) Pipeline() *PipelineEvent  { return e.pipeline }
 func (e Event) Skip() *SkipEvent          { return e.skip }
 func (e Event) Error() *ErrorRecord       { return e.error }
 func (e Event) Stats() *CrawlStats        { return e.stats }
+func (e Event) Tuning() *TuningEvent      { return e.tuning }
 
 /*
 	ErrorCause is a closed, canonical classification used exclusively for
@@ -401,20 +545,145 @@ const (
 	CauseRetryFailure
 )
 
+// AttributeType is the type of an attribute's value.
+type AttributeType int
+
+const (
+	AttrTypeString AttributeType = iota
+	AttrTypeInt64
+	AttrTypeFloat64
+	AttrTypeBool
+	AttrTypeDuration
+)
+
+/*
+Attribute is a key/value pair attached to an event or record.
+
+Values keep their type, so sinks encoding attributes, e.g. as JSON, do not
+have to parse numbers back out of strings. Value always returns the string
+form for sinks that only deal in text.
+*/
 type Attribute struct {
-	key   AttributeKey
-	value string
+	key      AttributeKey
+	kind     AttributeType
+	value    string
+	intValue int64
+	fltValue float64
 }
 
+// NewAttr creates a string attribute.
 func NewAttr(key AttributeKey, val string) Attribute {
 	return Attribute{
 		key:   key,
+		kind:  AttrTypeString,
 		value: val,
 	}
 }
 
-func (a Attribute) Key() AttributeKey { return a.key }
-func (a Attribute) Value() string     { return a.value }
+// NewInt64Attr creates an integer attribute.
+func NewInt64Attr(key AttributeKey, val int64) Attribute {
+	return Attribute{
+		key:      key,
+		kind:     AttrTypeInt64,
+		value:    strconv.FormatInt(val, 10),
+		intValue: val,
+	}
+}
+
+// NewFloat64Attr creates a floating point attribute.
+func NewFloat64Attr(key AttributeKey, val float64) Attribute {
+	return Attribute{
+		key:      key,
+		kind:     AttrTypeFloat64,
+		value:    strconv.FormatFloat(val, 'g', -1, 64),
+		fltValue: val,
+	}
+}
+
+// NewBoolAttr creates a boolean attribute.
+func NewBoolAttr(key AttributeKey, val bool) Attribute {
+	attr := Attribute{
+		key:   key,
+		kind:  AttrTypeBool,
+		value: strconv.FormatBool(val),
+	}
+	if val {
+		attr.intValue = 1
+	}
+	return attr
+}
+
+// NewDurationAttr creates a duration attribute. Its string form is the
+// duration's String, e.g. "1.5s".
+func NewDurationAttr(key AttributeKey, val time.Duration) Attribute {
+	return Attribute{
+		key:      key,
+		kind:     AttrTypeDuration,
+		value:    val.String(),
+		intValue: int64(val),
+	}
+}
+
+func (a Attribute) Key() AttributeKey   { return a.key }
+func (a Attribute) Type() AttributeType { return a.kind }
+
+// Value returns the attribute's value as a string, whatever its type.
+func (a Attribute) Value() string { return a.value }
+
+// Int64 returns the value of an integer attribute, and false for other types.
+func (a Attribute) Int64() (int64, bool) {
+	return a.intValue, a.kind == AttrTypeInt64
+}
+
+// Float64 returns the value of a floating point attribute, and false for
+// other types.
+func (a Attribute) Float64() (float64, bool) {
+	return a.fltValue, a.kind == AttrTypeFloat64
+}
+
+// Bool returns the value of a boolean attribute, and false for other types.
+func (a Attribute) Bool() (bool, bool) {
+	return a.intValue == 1, a.kind == AttrTypeBool
+}
+
+// Duration returns the value of a duration attribute, and false for other
+// types.
+func (a Attribute) Duration() (time.Duration, bool) {
+	return time.Duration(a.intValue), a.kind == AttrTypeDuration
+}
+
+// Any returns the attribute's value as its Go type.
+func (a Attribute) Any() any {
+	switch a.kind {
+	case AttrTypeInt64:
+		return a.intValue
+	case AttrTypeFloat64:
+		return a.fltValue
+	case AttrTypeBool:
+		return a.intValue == 1
+	case AttrTypeDuration:
+		return time.Duration(a.intValue)
+	default:
+		return a.value
+	}
+}
+
+// MarshalJSON encodes the attribute as {"key": ..., "value": ...} with the
+// value in its JSON type. Durations are encoded as a number of milliseconds,
+// so JSONL consumers can aggregate them without parsing.
+func (a Attribute) MarshalJSON() ([]byte, error) {
+	var value any
+	switch a.kind {
+	case AttrTypeDuration:
+		value = float64(a.intValue) / float64(time.Millisecond)
+	default:
+		value = a.Any()
+	}
+	return json.Marshal(struct {
+		Key   AttributeKey `json:"key"`
+		Value any          `json:"value"`
+	}{a.key, value})
+}
 
 type AttributeKey string
 
@@ -1,6 +1,7 @@
 package metadata_test
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -102,6 +103,117 @@ func TestNewAttr(t *testing.T) {
 	}
 }
 
+func TestTypedAttr(t *testing.T) {
+	tests := []struct {
+		name     string
+		attr     metadata.Attribute
+		wantType metadata.AttributeType
+		wantVal  string
+		wantAny  any
+	}{
+		{
+			name:     "string attribute",
+			attr:     metadata.NewAttr(metadata.AttrURL, "https://example.com"),
+			wantType: metadata.AttrTypeString,
+			wantVal:  "https://example.com",
+			wantAny:  "https://example.com",
+		},
+		{
+			name:     "int64 attribute",
+			attr:     metadata.NewInt64Attr(metadata.AttrDepth, 3),
+			wantType: metadata.AttrTypeInt64,
+			wantVal:  "3",
+			wantAny:  int64(3),
+		},
+		{
+			name:     "float64 attribute",
+			attr:     metadata.NewFloat64Attr(metadata.AttrMessage, 0.25),
+			wantType: metadata.AttrTypeFloat64,
+			wantVal:  "0.25",
+			wantAny:  0.25,
+		},
+		{
+			name:     "bool attribute",
+			attr:     metadata.NewBoolAttr(metadata.AttrMessage, true),
+			wantType: metadata.AttrTypeBool,
+			wantVal:  "true",
+			wantAny:  true,
+		},
+		{
+			name:     "duration attribute",
+			attr:     metadata.NewDurationAttr(metadata.AttrMessage, 1500*time.Millisecond),
+			wantType: metadata.AttrTypeDuration,
+			wantVal:  "1.5s",
+			wantAny:  1500 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.attr.Type() != tt.wantType {
+				t.Errorf("Type() = %v, want %v", tt.attr.Type(), tt.wantType)
+			}
+			if tt.attr.Value() != tt.wantVal {
+				t.Errorf("Value() = %q, want %q", tt.attr.Value(), tt.wantVal)
+			}
+			if tt.attr.Any() != tt.wantAny {
+				t.Errorf("Any() = %v, want %v", tt.attr.Any(), tt.wantAny)
+			}
+		})
+	}
+}
+
+func TestTypedAttr_AccessorsRejectOtherTypes(t *testing.T) {
+	attr := metadata.NewAttr(metadata.AttrDepth, "3")
+	if _, ok := attr.Int64(); ok {
+		t.Error("expected Int64 to reject a string attribute")
+	}
+	if d, ok := metadata.NewDurationAttr(metadata.AttrMessage, time.Second).Duration(); !ok || d != time.Second {
+		t.Errorf("Duration() = %v, %v, want 1s, true", d, ok)
+	}
+}
+
+func TestAttribute_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		attr metadata.Attribute
+		want string
+	}{
+		{
+			name: "string value stays a string",
+			attr: metadata.NewAttr(metadata.AttrURL, "https://example.com"),
+			want: `{"key":"url","value":"https://example.com"}`,
+		},
+		{
+			name: "int value is a number",
+			attr: metadata.NewInt64Attr(metadata.AttrDepth, 2),
+			want: `{"key":"depth","value":2}`,
+		},
+		{
+			name: "duration value is a number of milliseconds",
+			attr: metadata.NewDurationAttr(metadata.AttrMessage, 1500*time.Millisecond),
+			want: `{"key":"message","value":1500}`,
+		},
+		{
+			name: "bool value is a boolean",
+			attr: metadata.NewBoolAttr(metadata.AttrMessage, false),
+			want: `{"key":"message","value":false}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.attr)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Marshal() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestArtifactKind(t *testing.T) {
 	tests := []struct {
 		name string
@@ -565,6 +677,11 @@ func TestSkipReason(t *testing.T) {
 		{name: "SkipReasonRobotsDisallow has correct value", reason: metadata.SkipReasonRobotsDisallow, want: "robots_disallow"},
 		{name: "SkipReasonOutOfScope has correct value", reason: metadata.SkipReasonOutOfScope, want: "out_of_scope"},
 		{name: "SkipReasonAlreadyVisited has correct value", reason: metadata.SkipReasonAlreadyVisited, want: "already_visited"},
+		{name: "SkipReasonContentType has correct value", reason: metadata.SkipReasonContentType, want: "content_type"},
+		{name: "SkipReasonResponseTooLarge has correct value", reason: metadata.SkipReasonResponseTooLarge, want: "response_too_large"},
+		{name: "SkipReasonAssetTooLarge has correct value", reason: metadata.SkipReasonAssetTooLarge, want: "asset_too_large"},
+		{name: "SkipReasonDuplicateContent has correct value", reason: metadata.SkipReasonDuplicateContent, want: "duplicate_content"},
+		{name: "SkipReasonDepthQuota has correct value", reason: metadata.SkipReasonDepthQuota, want: "depth_quota"},
 	}
 
 	for _, tt := range tests {
@@ -582,6 +699,7 @@ func TestSkipEventConstruction(t *testing.T) {
 		"https://example.com/disallowed",
 		metadata.SkipReasonRobotsDisallow,
 		now,
+		nil,
 	)
 
 	if e.SkippedURL() != "https://example.com/disallowed" {
@@ -593,6 +711,23 @@ func TestSkipEventConstruction(t *testing.T) {
 	if e.RecordedAt() != now {
 		t.Errorf("SkipEvent.RecordedAt() = %v, want %v", e.RecordedAt(), now)
 	}
+	if len(e.Attrs()) != 0 {
+		t.Errorf("SkipEvent.Attrs() len = %v, want 0", len(e.Attrs()))
+	}
+}
+
+func TestSkipEventAttrsImmutability(t *testing.T) {
+	// Verify that both the constructor and Attrs() copy the attribute slice.
+	attrs := []metadata.Attribute{metadata.NewAttr(metadata.AttrHost, "example.com")}
+	e := metadata.NewSkipEvent("https://example.com/a", metadata.SkipReasonOutOfScope, time.Now(), attrs)
+
+	attrs[0] = metadata.NewAttr(metadata.AttrPath, "mutated")
+	got := e.Attrs()
+	got[0] = metadata.NewAttr(metadata.AttrPath, "mutated")
+
+	if e.Attrs()[0].Key() != metadata.AttrHost {
+		t.Error("SkipEvent.Attrs() is not a copy — external mutation affected internal state")
+	}
 }
 
 func TestCrawlStatsConstruction(t *testing.T) {
@@ -726,7 +861,7 @@ func TestEventConstruction(t *testing.T) {
 	})
 
 	t.Run("skip event getters", func(t *testing.T) {
-		se := metadata.NewSkipEvent("https://example.com/disallowed", metadata.SkipReasonRobotsDisallow, time.Now())
+		se := metadata.NewSkipEvent("https://example.com/disallowed", metadata.SkipReasonRobotsDisallow, time.Now(), nil)
 		if se.Reason() != metadata.SkipReasonRobotsDisallow {
 			t.Errorf("SkipEvent.Reason() = %v, want %v", se.Reason(), metadata.SkipReasonRobotsDisallow)
 		}
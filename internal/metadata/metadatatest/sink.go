@@ -23,6 +23,7 @@ type SinkMock struct {
 	RecordPipelineCalled bool
 	RecordSkipCalled     bool
 	RecordErrorCalled    bool
+	RecordTuningCalled   bool
 
 	// Recorded events (slices for inspection)
 	FetchEvents    []metadata.FetchEvent
@@ -30,6 +31,7 @@ type SinkMock struct {
 	PipelineEvents []metadata.PipelineEvent
 	SkipEvents     []metadata.SkipEvent
 	ErrorRecords   []metadata.ErrorRecord
+	TuningEvents   []metadata.TuningEvent
 }
 
 // Compile-time interface check
@@ -60,6 +62,11 @@ func (m *SinkMock) RecordError(record metadata.ErrorRecord) {
 	m.ErrorRecords = append(m.ErrorRecords, record)
 }
 
+func (m *SinkMock) RecordTuning(event metadata.TuningEvent) {
+	m.RecordTuningCalled = true
+	m.TuningEvents = append(m.TuningEvents, event)
+}
+
 // Reset clears all recorded state, returning the mock to its zero state.
 // This is useful for reusing the same mock across multiple test cases.
 func (m *SinkMock) Reset() {
@@ -68,11 +75,13 @@ func (m *SinkMock) Reset() {
 	m.RecordPipelineCalled = false
 	m.RecordSkipCalled = false
 	m.RecordErrorCalled = false
+	m.RecordTuningCalled = false
 	m.FetchEvents = nil
 	m.Artifacts = nil
 	m.PipelineEvents = nil
 	m.SkipEvents = nil
 	m.ErrorRecords = nil
+	m.TuningEvents = nil
 }
 
 // LastFetch returns the most recent FetchEvent, or nil if none recorded.
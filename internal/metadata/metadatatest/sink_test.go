@@ -171,6 +171,7 @@ func TestSinkMock_RecordSkip(t *testing.T) {
 		"https://example.com/disallowed",
 		metadata.SkipReasonRobotsDisallow,
 		now,
+		nil,
 	)
 	mock.RecordSkip(skip1)
 
@@ -190,6 +191,7 @@ func TestSinkMock_RecordSkip(t *testing.T) {
 		"https://example.com/out-of-scope",
 		metadata.SkipReasonOutOfScope,
 		now,
+		nil,
 	)
 	mock.RecordSkip(skip2)
 
@@ -273,6 +275,7 @@ func TestSinkMock_Reset(t *testing.T) {
 	))
 	mock.RecordSkip(metadata.NewSkipEvent(
 		"https://example.com/skip", metadata.SkipReasonRobotsDisallow, now,
+		nil,
 	))
 	mock.RecordError(metadata.NewErrorRecord(
 		now, "pkg", "action", metadata.CauseUnknown, "error", nil,
@@ -482,6 +485,7 @@ func TestSinkMock_LastSkip(t *testing.T) {
 	// One event
 	mock.RecordSkip(metadata.NewSkipEvent(
 		"https://example.com/first", metadata.SkipReasonRobotsDisallow, now,
+		nil,
 	))
 	last := mock.LastSkip()
 	if last == nil {
@@ -495,6 +499,7 @@ func TestSinkMock_LastSkip(t *testing.T) {
 	// Multiple events - returns most recent
 	mock.RecordSkip(metadata.NewSkipEvent(
 		"https://example.com/second", metadata.SkipReasonOutOfScope, now,
+		nil,
 	))
 	last = mock.LastSkip()
 	if last == nil {
@@ -678,6 +683,7 @@ func TestSinkMock_MultipleRecordTypes(t *testing.T) {
 	))
 	mock.RecordSkip(metadata.NewSkipEvent(
 		"https://example.com/skip", metadata.SkipReasonRobotsDisallow, now,
+		nil,
 	))
 	mock.RecordError(metadata.NewErrorRecord(
 		now, "pkg", "action", metadata.CauseUnknown, "error", nil,
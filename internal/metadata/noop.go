@@ -15,4 +15,6 @@ func (n *NoopSink) RecordPipelineStage(event PipelineEvent) {}
 
 func (n *NoopSink) RecordSkip(event SkipEvent) {}
 
+func (n *NoopSink) RecordTuning(event TuningEvent) {}
+
 var _ MetadataSink = (*NoopSink)(nil)
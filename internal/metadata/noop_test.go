@@ -127,17 +127,22 @@ func TestNoopSink_RecordSkip(t *testing.T) {
 		metadata.SkipReasonRobotsDisallow,
 		metadata.SkipReasonOutOfScope,
 		metadata.SkipReasonAlreadyVisited,
+		metadata.SkipReasonContentType,
+		metadata.SkipReasonResponseTooLarge,
+		metadata.SkipReasonAssetTooLarge,
+		metadata.SkipReasonDuplicateContent,
+		metadata.SkipReasonDepthQuota,
 	}
 
 	for _, reason := range reasons {
 		event := metadata.NewSkipEvent(
-			"https://example.com/skip", reason, now,
+			"https://example.com/skip", reason, now, nil,
 		)
 		sink.RecordSkip(event)
 	}
 
 	// Should not panic with zero values
-	zeroEvent := metadata.NewSkipEvent("", "", time.Time{})
+	zeroEvent := metadata.NewSkipEvent("", "", time.Time{}, nil)
 	sink.RecordSkip(zeroEvent)
 }
 
@@ -214,7 +219,7 @@ func TestNoopSink_AllMethods(t *testing.T) {
 	))
 
 	sink.RecordSkip(metadata.NewSkipEvent(
-		"https://example.com/skip", metadata.SkipReasonRobotsDisallow, now,
+		"https://example.com/skip", metadata.SkipReasonRobotsDisallow, now, nil,
 	))
 
 	sink.RecordError(metadata.NewErrorRecord(
@@ -246,7 +251,7 @@ func TestNoopSink_NilReceiver(t *testing.T) {
 	))
 
 	sink.RecordSkip(metadata.NewSkipEvent(
-		"https://example.com/skip", metadata.SkipReasonRobotsDisallow, now,
+		"https://example.com/skip", metadata.SkipReasonRobotsDisallow, now, nil,
 	))
 
 	sink.RecordError(metadata.NewErrorRecord(
@@ -275,7 +280,7 @@ func TestNoopSink_EmptySink(t *testing.T) {
 			metadata.StageExtract, "https://example.com", true, now, 5,
 		))
 		sink.RecordSkip(metadata.NewSkipEvent(
-			"https://example.com/skip", metadata.SkipReasonRobotsDisallow, now,
+			"https://example.com/skip", metadata.SkipReasonRobotsDisallow, now, nil,
 		))
 		sink.RecordError(metadata.NewErrorRecord(
 			now, "package", "action", metadata.CauseUnknown, "error", nil,
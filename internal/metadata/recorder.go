@@ -64,6 +64,7 @@ type MetadataSink interface {
 	RecordPipelineStage(event PipelineEvent)
 	RecordSkip(event SkipEvent)
 	RecordError(record ErrorRecord)
+	RecordTuning(event TuningEvent)
 }
 
 type CrawlFinalizer interface {
@@ -179,6 +180,10 @@ func (r *Recorder) RecordError(record ErrorRecord) {
 	r.append(Event{kind: EventKindError, error: &record})
 }
 
+func (r *Recorder) RecordTuning(event TuningEvent) {
+	r.append(Event{kind: EventKindTuning, tuning: &event})
+}
+
 /*
 RecordFinalCrawlStats records a terminal, derived summary of a completed crawl.
 
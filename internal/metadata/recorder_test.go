@@ -106,6 +106,7 @@ func TestRecorder_EachMethodAppendsOneEvent(t *testing.T) {
 					"https://example.com/disallowed",
 					metadata.SkipReasonRobotsDisallow,
 					now,
+					nil,
 				))
 			},
 			wantKind: metadata.EventKindSkip,
@@ -230,7 +231,7 @@ func TestRecorder_SnapshotIsolation(t *testing.T) {
 	r := newTestRecorder(t)
 
 	r.RecordFetch(metadata.NewFetchEvent(now, "https://example.com", 200, time.Second, "text/html", 0, 0, metadata.KindPage))
-	r.RecordSkip(metadata.NewSkipEvent("https://example.com/skip", metadata.SkipReasonRobotsDisallow, now))
+	r.RecordSkip(metadata.NewSkipEvent("https://example.com/skip", metadata.SkipReasonRobotsDisallow, now, nil))
 
 	snapshot := r.Events()
 	if len(snapshot) != 2 {
@@ -262,7 +263,7 @@ func TestRecorder_ConcurrentSafety(t *testing.T) {
 		}()
 		go func() {
 			defer wg.Done()
-			r.RecordSkip(metadata.NewSkipEvent("https://example.com/skip", metadata.SkipReasonRobotsDisallow, now))
+			r.RecordSkip(metadata.NewSkipEvent("https://example.com/skip", metadata.SkipReasonRobotsDisallow, now, nil))
 		}()
 		go func() {
 			defer wg.Done()
@@ -303,7 +304,7 @@ func TestRecorder_Subscribe_ReceivesEvents(t *testing.T) {
 	defer done()
 
 	r.RecordFetch(metadata.NewFetchEvent(now, "https://example.com", 200, time.Second, "text/html", 0, 0, metadata.KindPage))
-	r.RecordSkip(metadata.NewSkipEvent("https://example.com/skip", metadata.SkipReasonRobotsDisallow, now))
+	r.RecordSkip(metadata.NewSkipEvent("https://example.com/skip", metadata.SkipReasonRobotsDisallow, now, nil))
 
 	if len(ch) != 2 {
 		t.Fatalf("subscriber channel len = %v, want 2", len(ch))
@@ -359,7 +360,7 @@ func TestRecorder_Subscribe_ForwardOnly(t *testing.T) {
 	defer done()
 
 	// Record a second event AFTER subscribing.
-	r.RecordSkip(metadata.NewSkipEvent("https://example.com/after", metadata.SkipReasonRobotsDisallow, now))
+	r.RecordSkip(metadata.NewSkipEvent("https://example.com/after", metadata.SkipReasonRobotsDisallow, now, nil))
 
 	// The internal log must contain both events.
 	if len(r.Events()) != 2 {
@@ -0,0 +1,210 @@
+package normalize
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+Chunking
+
+With NormalizeParam.ChunkSize set, Normalize also splits the final content
+into chunks for RAG ingestion and returns them alongside it as
+NormalizedMarkdownDoc.Chunks. The content itself is not changed.
+
+Chunks only break between blocks:
+- a block is a run of non-blank lines, so paragraphs, lists and tables
+  are never cut
+- a fenced code block is one block, blank lines included
+- every ATX heading is a block of its own, and a chunk never ends on a
+  heading, so a heading always opens the chunk holding its first paragraph
+
+Blocks are packed greedily: a chunk grows while its bytes, from the start of
+its first block to the end of its last, stay within ChunkSize. A block
+larger than ChunkSize becomes a chunk on its own. Every chunk after the
+first then also starts with as many trailing blocks of the previous chunk
+as fit within ChunkOverlap bytes, never the whole previous chunk. The
+overlap comes on top of ChunkSize, so a chunk holds up to
+ChunkSize + ChunkOverlap bytes.
+
+A chunk's ID is "<hash>-<index>", where hash is the first chunkIDHashLength
+hex characters of the document's content_hash and index is the zero-based
+position of the chunk. Identical content therefore yields identical IDs on
+every run, and any change to the content yields new ones.
+*/
+
+// chunkIDHashLength is the number of content hash characters in a chunk ID.
+const chunkIDHashLength = 16
+
+// Chunk is a contiguous slice of a normalized document's content.
+type Chunk struct {
+	id      string
+	index   int
+	start   int
+	end     int
+	content []byte
+}
+
+// ID returns the deterministic chunk ID.
+func (c Chunk) ID() string {
+	return c.id
+}
+
+// Index returns the zero-based position of the chunk in the document.
+func (c Chunk) Index() int {
+	return c.index
+}
+
+// Start returns the byte offset in the document content where the chunk starts.
+func (c Chunk) Start() int {
+	return c.start
+}
+
+// End returns the byte offset in the document content where the chunk ends
+// (exclusive).
+func (c Chunk) End() int {
+	return c.end
+}
+
+// Content returns the chunk's Markdown.
+func (c Chunk) Content() []byte {
+	return c.content
+}
+
+// chunkBlock is the byte range of a block in the content.
+type chunkBlock struct {
+	start   int
+	end     int
+	heading bool
+}
+
+// RefreshChunks recomputes doc's chunks from its current content and
+// content_hash using the chunk settings of param. It returns doc without
+// chunks when param.ChunkSize is not positive. Call it after
+// RefreshFrontmatter when the content of a normalized document is rewritten.
+func RefreshChunks(doc NormalizedMarkdownDoc, param NormalizeParam) NormalizedMarkdownDoc {
+	doc.chunks = nil
+	if param.chunkSize > 0 {
+		doc.chunks = chunkContent(doc.content, doc.frontmatter.contentHash, param.chunkSize, param.chunkOverlap)
+	}
+	return doc
+}
+
+// chunkContent splits content into chunks of about size bytes that repeat
+// up to overlap bytes of the previous chunk.
+func chunkContent(content []byte, contentHash string, size, overlap int) []Chunk {
+	blocks := scanChunkBlocks(string(content))
+	if len(blocks) == 0 {
+		return nil
+	}
+
+	// Pack each block into exactly one chunk first, then widen every chunk
+	// but the first backwards by the overlap
+	type blockRange struct{ first, last int }
+	var packed []blockRange
+	for first := 0; first < len(blocks); {
+		last := first + 1
+		for last < len(blocks) && blocks[last].end-blocks[first].start <= size {
+			last++
+		}
+		// Keep a trailing heading with the block it introduces, even when
+		// that block alone would overflow the chunk
+		for last < len(blocks) && last-1 > first && blocks[last-1].heading {
+			last--
+		}
+		for last < len(blocks) && blocks[last-1].heading {
+			last++
+		}
+		packed = append(packed, blockRange{first: first, last: last})
+		first = last
+	}
+
+	chunks := make([]Chunk, 0, len(packed))
+	for i, r := range packed {
+		from := r.first
+		if i > 0 {
+			prev := packed[i-1]
+			prevEnd := blocks[prev.last-1].end
+			for from-1 > prev.first && prevEnd-blocks[from-1].start <= overlap {
+				from--
+			}
+		}
+		start, end := blocks[from].start, blocks[r.last-1].end
+		chunks = append(chunks, Chunk{
+			id:      chunkID(contentHash, i),
+			index:   i,
+			start:   start,
+			end:     end,
+			content: content[start:end],
+		})
+	}
+	return chunks
+}
+
+// scanChunkBlocks returns the blocks of content in order. A block's range
+// ends after the newline of its last line.
+func scanChunkBlocks(content string) []chunkBlock {
+	var blocks []chunkBlock
+	open := -1 // start of the block being read, or -1 between blocks
+	fence := ""
+	closeBlock := func(end int) {
+		if open >= 0 {
+			blocks = append(blocks, chunkBlock{start: open, end: end})
+			open = -1
+		}
+	}
+
+	for offset := 0; offset < len(content); {
+		lineEnd := len(content)
+		if i := strings.IndexByte(content[offset:], '\n'); i >= 0 {
+			lineEnd = offset + i + 1
+		}
+		trimmed := strings.TrimSpace(content[offset:lineEnd])
+
+		switch {
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+		case fenceMarker(trimmed) != "":
+			fence = fenceMarker(trimmed)
+			if open < 0 {
+				open = offset
+			}
+		case trimmed == "":
+			closeBlock(offset)
+		case isATXHeadingLine(trimmed):
+			closeBlock(offset)
+			blocks = append(blocks, chunkBlock{start: offset, end: lineEnd, heading: true})
+		default:
+			if open < 0 {
+				open = offset
+			}
+		}
+		offset = lineEnd
+	}
+	closeBlock(len(content))
+	return blocks
+}
+
+// isATXHeadingLine reports whether a trimmed line is an ATX heading.
+func isATXHeadingLine(trimmed string) bool {
+	level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+	if level < 1 || level > 6 {
+		return false
+	}
+	return len(trimmed) == level || trimmed[level] == ' ' || trimmed[level] == '\t'
+}
+
+// chunkID derives the ID of the chunk at index from the document's
+// "<algo>:<hash>" content_hash.
+func chunkID(contentHash string, index int) string {
+	hash := contentHash
+	if _, value, ok := strings.Cut(contentHash, ":"); ok {
+		hash = value
+	}
+	if len(hash) > chunkIDHashLength {
+		hash = hash[:chunkIDHashLength]
+	}
+	return fmt.Sprintf("%s-%d", hash, index)
+}
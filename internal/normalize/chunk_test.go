@@ -0,0 +1,152 @@
+package normalize_test
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/assets"
+	"github.com/rohmanhakim/docs-crawler/internal/normalize"
+	"github.com/rohmanhakim/docs-crawler/pkg/hashutil"
+)
+
+const chunkedContent = "# Guide\n\n" +
+	"Intro paragraph.\n\n" +
+	"## Setup\n\n" +
+	"Install the tool.\n\n" +
+	"```go\nrun()\n\nstop()\n```\n\n" +
+	"## Usage\n\n" +
+	"Call it.\n"
+
+func normalizeWithChunking(t *testing.T, content string, size, overlap int) normalize.NormalizedMarkdownDoc {
+	t.Helper()
+	constraint := normalize.NewMarkdownConstraint(&metadataSinkMock{})
+	fetchURL, _ := url.Parse("https://docs.example.com/guide/chunks")
+	param := normalize.NewNormalizeParam(
+		"v1.0.0",
+		time.Date(2026, 2, 12, 10, 15, 0, 0, time.UTC),
+		hashutil.HashAlgoSHA256,
+		1,
+		[]string{},
+	).WithChunking(size, overlap)
+
+	result, err := constraint.Normalize(*fetchURL, assets.NewAssetfulMarkdownDoc([]byte(content), nil, nil, nil), param)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	return result
+}
+
+func chunkContents(doc normalize.NormalizedMarkdownDoc) []string {
+	var contents []string
+	for _, c := range doc.Chunks() {
+		contents = append(contents, string(c.Content()))
+	}
+	return contents
+}
+
+func assertChunks(t *testing.T, doc normalize.NormalizedMarkdownDoc, expected []string) {
+	t.Helper()
+	got := chunkContents(doc)
+	if len(got) != len(expected) {
+		t.Fatalf("expected %d chunks, got %d: %q", len(expected), len(got), got)
+	}
+	for i, c := range doc.Chunks() {
+		if got[i] != expected[i] {
+			t.Errorf("chunk %d: expected %q, got %q", i, expected[i], got[i])
+		}
+		if c.Index() != i {
+			t.Errorf("chunk %d: expected index %d, got %d", i, i, c.Index())
+		}
+		if string(doc.Content()[c.Start():c.End()]) != got[i] {
+			t.Errorf("chunk %d: offsets [%d:%d] do not match its content", i, c.Start(), c.End())
+		}
+	}
+}
+
+func TestNormalize_Chunks_SplitOnBlockBoundaries(t *testing.T) {
+	doc := normalizeWithChunking(t, chunkedContent, 40, 0)
+
+	// "## Setup" would fit in the first chunk, but a chunk never ends on a
+	// heading; the fenced block keeps its blank line
+	assertChunks(t, doc, []string{
+		"# Guide\n\nIntro paragraph.\n",
+		"## Setup\n\nInstall the tool.\n",
+		"```go\nrun()\n\nstop()\n```\n",
+		"## Usage\n\nCall it.\n",
+	})
+	if string(doc.Content()) != chunkedContent {
+		t.Errorf("expected content unchanged by chunking, got:\n%s", doc.Content())
+	}
+}
+
+func TestNormalize_Chunks_OverlapRepeatsTrailingBlocks(t *testing.T) {
+	doc := normalizeWithChunking(t, chunkedContent, 40, 20)
+
+	// The last block of each chunk is repeated when it fits in 20 bytes; the
+	// 24-byte code block does not
+	assertChunks(t, doc, []string{
+		"# Guide\n\nIntro paragraph.\n",
+		"Intro paragraph.\n\n## Setup\n\nInstall the tool.\n",
+		"Install the tool.\n\n```go\nrun()\n\nstop()\n```\n",
+		"## Usage\n\nCall it.\n",
+	})
+}
+
+func TestNormalize_Chunks_OversizedBlockStaysWhole(t *testing.T) {
+	long := strings.Repeat("word ", 20) + "end."
+	content := "# Guide\n\n## Long\n\n" + long + "\n\nShort.\n"
+
+	doc := normalizeWithChunking(t, content, 30, 0)
+
+	assertChunks(t, doc, []string{
+		"# Guide\n\n## Long\n\n" + long + "\n",
+		"Short.\n",
+	})
+}
+
+func TestNormalize_Chunks_IDsStableAcrossRuns(t *testing.T) {
+	first := normalizeWithChunking(t, chunkedContent, 40, 20)
+	second := normalizeWithChunking(t, chunkedContent, 40, 20)
+	changed := normalizeWithChunking(t, chunkedContent+"\nMore.\n", 40, 20)
+
+	hash := strings.TrimPrefix(first.Frontmatter().ContentHash(), "sha256:")[:16]
+	for i, c := range first.Chunks() {
+		if c.ID() != second.Chunks()[i].ID() {
+			t.Errorf("chunk %d: expected identical IDs across runs, got %q and %q", i, c.ID(), second.Chunks()[i].ID())
+		}
+		if expected := fmt.Sprintf("%s-%d", hash, i); c.ID() != expected {
+			t.Errorf("chunk %d: expected ID %q, got %q", i, expected, c.ID())
+		}
+		if c.ID() == changed.Chunks()[i].ID() {
+			t.Errorf("chunk %d: expected a new ID when the content changes", i)
+		}
+	}
+}
+
+func TestNormalize_Chunks_DisabledByDefault(t *testing.T) {
+	doc := normalizeWithChunking(t, chunkedContent, 0, 0)
+
+	if len(doc.Chunks()) != 0 {
+		t.Errorf("expected no chunks when chunking is disabled, got %d", len(doc.Chunks()))
+	}
+}
+
+func TestRefreshChunks_FollowsRewrittenContent(t *testing.T) {
+	doc := normalizeWithChunking(t, chunkedContent, 40, 0)
+	param := normalize.NewNormalizeParam("v1.0.0", time.Time{}, hashutil.HashAlgoSHA256, 1, nil).WithChunking(40, 0)
+
+	rewritten := normalize.NewNormalizedMarkdownDoc(doc.Frontmatter(), []byte("# Guide\n\nOnly this.\n"))
+	refreshed, err := normalize.RefreshFrontmatter(rewritten, hashutil.HashAlgoSHA256)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	refreshed = normalize.RefreshChunks(refreshed, param)
+
+	assertChunks(t, refreshed, []string{"# Guide\n\nOnly this.\n"})
+	if refreshed.Chunks()[0].ID() == doc.Chunks()[0].ID() {
+		t.Error("expected the chunk ID to follow the refreshed content hash")
+	}
+}
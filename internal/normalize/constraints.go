@@ -145,19 +145,54 @@ func normalize(
 ) (NormalizedMarkdownDoc, failure.ClassifiedError) {
 	content := inputDoc.Content()
 
+	// Step 0.25: Remove zero-width and control characters. Done first, since
+	// a leading byte order mark hides the title heading from every later step.
+	content = stripInvisible(content, normalizeParam.stripInvisibleInCode)
+
+	// Step 0.5: Optionally remove repeated headings. Done before validation,
+	// since a repeated H1 or an empty repeated section would fail it.
+	if normalizeParam.dedupeHeadings {
+		content = dedupeHeadings(content)
+	}
+
 	// Step 1: Validate structure before generating frontmatter
 	if err := validateStructure(content); err != nil {
 		return NormalizedMarkdownDoc{}, err
 	}
 
-	// Step 2: Generate frontmatter (assumes valid structure)
-	frontmatter, err := generateFrontmatter(fetchUrl, inputDoc, normalizeParam)
+	// Step 2: Re-render detected API reference blocks in a consistent layout.
+	// Done before the ToC, which only depends on the headings that are kept.
+	content = renderStructuredSections(content, normalizeParam.structuredSections)
+
+	// Step 2.5: Optionally point links at the output files of their pages
+	if normalizeParam.linkResolver != nil {
+		content = rewriteInternalLinks(content, fetchUrl, normalizeParam.pagePath, normalizeParam.linkResolver)
+	}
+
+	// Step 3: Optionally insert a table of contents below the title.
+	// Done before frontmatter generation so content_hash covers the final content.
+	if normalizeParam.generateToC {
+		content = insertToC(content)
+	}
+
+	// Step 4: Downgrade GFM-only constructs when CommonMark output is requested.
+	// Also done before frontmatter generation so content_hash matches the output.
+	if normalizeParam.MarkdownFlavor() == MarkdownFlavorCommonMark {
+		content = downgradeToCommonMark(content)
+	}
+
+	// Step 5: Generate frontmatter (assumes valid structure)
+	frontmatter, err := generateFrontmatter(fetchUrl, content, normalizeParam)
 	if err != nil {
 		return NormalizedMarkdownDoc{}, err
 	}
 
+	// Step 6: Optionally split the final content into chunks. Done last so
+	// the chunk IDs derive from the final content_hash.
+	doc := RefreshChunks(NewNormalizedMarkdownDoc(frontmatter, content), normalizeParam)
+
 	// Return normalized document with both frontmatter and content
-	return NewNormalizedMarkdownDoc(frontmatter, content), nil
+	return doc, nil
 }
 
 // validateStructure validates the Markdown document structure according to
@@ -312,11 +347,9 @@ func validateStructure(content []byte) failure.ClassifiedError {
 
 func generateFrontmatter(
 	fetchUrl url.URL,
-	inputDoc assets.AssetfulMarkdownDoc,
+	content []byte,
 	normalizeParam NormalizeParam,
 ) (Frontmatter, failure.ClassifiedError) {
-	content := inputDoc.Content()
-
 	// Extract title from content (assumes exactly one H1 exists after validation)
 	title, err := extractTitle(content)
 	if err != nil {
@@ -327,7 +360,14 @@ func generateFrontmatter(
 	sourceURL := fetchUrl.String()
 
 	// Compute canonical URL
-	canonicalURL := urlutil.Canonicalize(fetchUrl)
+	canonicalURL := urlutil.ApplyTrailingSlash(
+		urlutil.CanonicalizeKeepingQuery(fetchUrl, normalizeParam.keepQuery),
+		fetchUrl,
+		normalizeParam.trailingSlash,
+	)
+	if normalizeParam.keepFragment {
+		canonicalURL.Fragment = fetchUrl.Fragment
+	}
 
 	// Derive section from canonical URL path (stripping allowedPathPrefixes first)
 	section, err := deriveSection(canonicalURL, normalizeParam.allowedPathPrefixes)
@@ -347,14 +387,16 @@ func generateFrontmatter(
 	docID := string(normalizeParam.hashAlgo) + ":" + docIDHash
 
 	// Compute contentHash (hash of markdown content)
-	contentHashValue, hashErr := hashutil.HashBytes(content, normalizeParam.hashAlgo)
-	if hashErr != nil {
-		return Frontmatter{}, NewNormalizationError(
-			ErrCauseHashComputationFailed,
-			fmt.Sprintf("failed to compute content_hash: %v", hashErr),
-		)
+	contentHash, err := computeContentHash(content, normalizeParam.hashAlgo)
+	if err != nil {
+		return Frontmatter{}, err
+	}
+
+	// Compute structuralHash (hash of heading outline and internal links)
+	structuralHash, err := computeStructuralHash(content, fetchUrl, normalizeParam.hashAlgo)
+	if err != nil {
+		return Frontmatter{}, err
 	}
-	contentHash := string(normalizeParam.hashAlgo) + ":" + contentHashValue
 
 	// Gather remaining fields from normalizeParam
 	fetchedAt := normalizeParam.fetchedAt
@@ -362,7 +404,7 @@ func generateFrontmatter(
 	crawlDepth := normalizeParam.crawlDepth
 
 	// Construct immutable Frontmatter
-	return NewFrontmatter(
+	frontmatter := NewFrontmatter(
 		title,
 		sourceURL,
 		canonicalURLStr,
@@ -372,7 +414,54 @@ func generateFrontmatter(
 		contentHash,
 		fetchedAt,
 		crawlerVersion,
-	), nil
+	)
+	frontmatter.runID = normalizeParam.runID
+	frontmatter.structuralHash = structuralHash
+	frontmatter.provenance = normalizeParam.provenance
+	return frontmatter, nil
+}
+
+// computeContentHash returns the "<algo>:<hash>" content_hash of content.
+func computeContentHash(content []byte, hashAlgo hashutil.HashAlgo) (string, failure.ClassifiedError) {
+	contentHashValue, hashErr := hashutil.HashBytes(content, hashAlgo)
+	if hashErr != nil {
+		return "", NewNormalizationError(
+			ErrCauseHashComputationFailed,
+			fmt.Sprintf("failed to compute content_hash: %v", hashErr),
+		)
+	}
+	return string(hashAlgo) + ":" + contentHashValue, nil
+}
+
+// RefreshFrontmatter recomputes the content-derived frontmatter fields
+// (title, content_hash and the structural hash) from doc's current content, keeping the fields
+// derived from the URL and the fetch. Call it after rewriting the content
+// of a normalized document so its frontmatter describes the bytes written.
+func RefreshFrontmatter(doc NormalizedMarkdownDoc, hashAlgo hashutil.HashAlgo) (NormalizedMarkdownDoc, failure.ClassifiedError) {
+	content := doc.Content()
+	title, err := extractTitle(content)
+	if err != nil {
+		return NormalizedMarkdownDoc{}, err
+	}
+	contentHash, err := computeContentHash(content, hashAlgo)
+	if err != nil {
+		return NormalizedMarkdownDoc{}, err
+	}
+
+	fm := doc.Frontmatter()
+	pageURL, parseErr := url.Parse(fm.sourceURL)
+	if parseErr != nil {
+		pageURL = &url.URL{}
+	}
+	structuralHash, err := computeStructuralHash(content, *pageURL, hashAlgo)
+	if err != nil {
+		return NormalizedMarkdownDoc{}, err
+	}
+
+	fm.title = title
+	fm.contentHash = contentHash
+	fm.structuralHash = structuralHash
+	return NewNormalizedMarkdownDoc(fm, content), nil
 }
 
 // deriveSection extracts the first meaningful path segment from the URL.
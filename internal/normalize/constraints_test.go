@@ -32,7 +32,7 @@ func TestNormalize_SuccessfulFrontmatterGeneration(t *testing.T) {
 		hashutil.HashAlgoSHA256,
 		2,
 		[]string{"/docs"},
-	)
+	).WithRunID("0123456789abcdef")
 
 	// Act
 	result, err := constraint.Normalize(*fetchURL, assetfulDoc, normalizeParam)
@@ -74,6 +74,11 @@ func TestNormalize_SuccessfulFrontmatterGeneration(t *testing.T) {
 		t.Errorf("expected crawlerVersion 'v1.0.0', got: %s", frontmatter.CrawlerVersion())
 	}
 
+	// Verify runID from param
+	if frontmatter.RunID() != "0123456789abcdef" {
+		t.Errorf("expected runID '0123456789abcdef', got: %s", frontmatter.RunID())
+	}
+
 	// Verify fetchedAt from param
 	expectedTime := time.Date(2026, 2, 12, 10, 15, 0, 0, time.UTC)
 	if !frontmatter.FetchedAt().Equal(expectedTime) {
@@ -589,3 +594,48 @@ func TestNormalize_ContentHashDeterminism(t *testing.T) {
 		t.Error("content should be identical between runs")
 	}
 }
+
+func TestRefreshFrontmatter_RecomputesContentDerivedFields(t *testing.T) {
+	// Arrange - a normalized document whose content is then rewritten
+	constraint := normalize.NewMarkdownConstraint(&metadataSinkMock{})
+	fetchURL, _ := url.Parse("https://example.com/docs/page")
+	assetfulDoc := assets.NewAssetfulMarkdownDoc([]byte("# Original\n\nBody."), nil, nil, nil)
+	normalizeParam := normalize.NewNormalizeParam("v1.0.0", time.Now(), hashutil.HashAlgoSHA256, 1, nil)
+	doc, err := constraint.Normalize(*fetchURL, assetfulDoc, normalizeParam)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	rewritten := []byte("# Rewritten\n\nBody.\n\nExtra line.")
+	changed := normalize.NewNormalizedMarkdownDoc(doc.Frontmatter(), rewritten)
+
+	// Act
+	refreshed, err := normalize.RefreshFrontmatter(changed, hashutil.HashAlgoSHA256)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	expectedHash, _ := hashutil.HashBytes(rewritten, hashutil.HashAlgoSHA256)
+	if got := refreshed.Frontmatter().ContentHash(); got != "sha256:"+expectedHash {
+		t.Errorf("expected content hash of rewritten content, got %s", got)
+	}
+	if got := refreshed.Frontmatter().Title(); got != "Rewritten" {
+		t.Errorf("expected title Rewritten, got %q", got)
+	}
+	if refreshed.Frontmatter().DocID() != doc.Frontmatter().DocID() ||
+		refreshed.Frontmatter().SourceURL() != doc.Frontmatter().SourceURL() ||
+		refreshed.Frontmatter().Section() != doc.Frontmatter().Section() {
+		t.Error("URL-derived fields should be kept")
+	}
+	if string(refreshed.Content()) != string(rewritten) {
+		t.Error("content should be unchanged")
+	}
+}
+
+func TestRefreshFrontmatter_MissingTitle(t *testing.T) {
+	doc := normalize.NewNormalizedMarkdownDoc(normalize.Frontmatter{}, []byte("No heading left."))
+
+	if _, err := normalize.RefreshFrontmatter(doc, hashutil.HashAlgoSHA256); err == nil {
+		t.Error("expected an error when the content has no H1")
+	}
+}
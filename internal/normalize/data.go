@@ -1,10 +1,13 @@
 package normalize
 
 import (
+	"net/url"
 	"time"
 
 	"github.com/gomarkdown/markdown/ast"
+	"github.com/rohmanhakim/docs-crawler/internal/extractor"
 	"github.com/rohmanhakim/docs-crawler/pkg/hashutil"
+	"github.com/rohmanhakim/docs-crawler/pkg/urlutil"
 )
 
 // RAG Shaping
@@ -12,6 +15,8 @@ import (
 type NormalizedMarkdownDoc struct {
 	frontmatter Frontmatter
 	content     []byte
+	// chunks split content for RAG ingestion; empty unless chunking is enabled
+	chunks []Chunk
 }
 
 // Frontmatter returns the frontmatter of the normalized document.
@@ -24,6 +29,12 @@ func (n NormalizedMarkdownDoc) Content() []byte {
 	return n.content
 }
 
+// Chunks returns the chunks of the content, in order. It is empty unless
+// NormalizeParam.ChunkSize was set.
+func (n NormalizedMarkdownDoc) Chunks() []Chunk {
+	return n.chunks
+}
+
 // NewNormalizedMarkdownDoc creates a new immutable NormalizedMarkdownDoc.
 func NewNormalizedMarkdownDoc(frontmatter Frontmatter, content []byte) NormalizedMarkdownDoc {
 	return NormalizedMarkdownDoc{
@@ -42,6 +53,12 @@ type Frontmatter struct {
 	contentHash    string
 	fetchedAt      time.Time
 	crawlerVersion string
+	// runID identifies the crawl run; empty when the run set none
+	runID string
+	// structuralHash covers the heading outline and internal links (see structure.go)
+	structuralHash string
+	// provenance records how the page was reached (see provenance.go)
+	provenance *Provenance
 }
 
 // NewFrontmatter creates a new immutable Frontmatter with all fields populated.
@@ -115,12 +132,78 @@ func (f Frontmatter) CrawlerVersion() string {
 	return f.crawlerVersion
 }
 
+// RunID returns the ID of the crawl run that produced the document.
+func (f Frontmatter) RunID() string {
+	return f.runID
+}
+
+// StructuralHash returns the hash of the heading outline and internal link
+// targets of the content, which ignores changes to prose.
+func (f Frontmatter) StructuralHash() string {
+	return f.structuralHash
+}
+
+// Provenance returns how the page was reached, and false when provenance
+// was not recorded.
+func (f Frontmatter) Provenance() (Provenance, bool) {
+	if f.provenance == nil {
+		return Provenance{}, false
+	}
+	return *f.provenance, true
+}
+
+// Referrer returns the page the document was discovered on, or "" when it
+// has none or provenance was not recorded.
+func (f Frontmatter) Referrer() string {
+	if f.provenance == nil {
+		return ""
+	}
+	return f.provenance.referrer
+}
+
+// RedirectChain returns the URLs requested while fetching the document, or
+// nil when it was not redirected or provenance was not recorded.
+func (f Frontmatter) RedirectChain() []string {
+	if f.provenance == nil {
+		return nil
+	}
+	return append([]string(nil), f.provenance.redirectChain...)
+}
+
 type NormalizeParam struct {
 	appVersion          string
 	fetchedAt           time.Time
 	hashAlgo            hashutil.HashAlgo
 	crawlDepth          int
 	allowedPathPrefixes []string
+	// generateToC inserts a table of contents below the H1 title when true
+	generateToC bool
+	// markdownFlavor selects the Markdown dialect of the output; empty means GFM
+	markdownFlavor MarkdownFlavor
+	// structuredSections are re-rendered in a fixed layout under their headings
+	structuredSections []extractor.StructuredSection
+	// chunkSize is the maximum chunk length in bytes; 0 disables chunking
+	chunkSize int
+	// chunkOverlap is the number of bytes repeated from the previous chunk
+	chunkOverlap int
+	// pagePath is the output path of the page, for relative links
+	pagePath string
+	// linkResolver maps link targets to output paths; nil leaves links as written
+	linkResolver LinkResolver
+	// dedupeHeadings removes repeats of the title H1 and of adjacent headings when true
+	dedupeHeadings bool
+	// stripInvisibleInCode removes invisible characters from fenced code too when true
+	stripInvisibleInCode bool
+	// keepQuery reports which query parameters survive in the canonical URL; nil drops all
+	keepQuery func(param string) bool
+	// trailingSlash sets the trailing slash of the canonical URL; empty strips it
+	trailingSlash urlutil.TrailingSlashPolicy
+	// keepFragment keeps the fetch URL's fragment in the canonical URL when true
+	keepFragment bool
+	// runID is recorded in the frontmatter of every document of the run
+	runID string
+	// provenance is recorded in the frontmatter when not nil
+	provenance *Provenance
 }
 
 func NewNormalizeParam(
@@ -163,6 +246,142 @@ func (p NormalizeParam) AllowedPathPrefixes() []string {
 	return prefixes
 }
 
+func (p NormalizeParam) GenerateToC() bool {
+	return p.generateToC
+}
+
+// WithGenerateToC returns a copy of the param with table of contents
+// generation enabled or disabled. Disabled by default.
+func (p NormalizeParam) WithGenerateToC(enabled bool) NormalizeParam {
+	p.generateToC = enabled
+	return p
+}
+
+func (p NormalizeParam) MarkdownFlavor() MarkdownFlavor {
+	if p.markdownFlavor == "" {
+		return MarkdownFlavorGFM
+	}
+	return p.markdownFlavor
+}
+
+// WithMarkdownFlavor returns a copy of the param with the given output flavor.
+// Defaults to MarkdownFlavorGFM.
+func (p NormalizeParam) WithMarkdownFlavor(flavor MarkdownFlavor) NormalizeParam {
+	p.markdownFlavor = flavor
+	return p
+}
+
+func (p NormalizeParam) StructuredSections() []extractor.StructuredSection {
+	return p.structuredSections
+}
+
+// WithStructuredSections returns a copy of the param that re-renders the
+// given API reference blocks, as detected by the extractor's structured
+// mode, in a consistent layout.
+func (p NormalizeParam) WithStructuredSections(sections []extractor.StructuredSection) NormalizeParam {
+	p.structuredSections = sections
+	return p
+}
+
+func (p NormalizeParam) ChunkSize() int {
+	return p.chunkSize
+}
+
+func (p NormalizeParam) ChunkOverlap() int {
+	return p.chunkOverlap
+}
+
+// WithChunking returns a copy of the param that splits the normalized content
+// into chunks of about size bytes, each also repeating up to overlap bytes
+// of the previous one. A size of 0 disables chunking, which is the default.
+func (p NormalizeParam) WithChunking(size, overlap int) NormalizeParam {
+	p.chunkSize = size
+	p.chunkOverlap = overlap
+	return p
+}
+
+func (p NormalizeParam) RunID() string {
+	return p.runID
+}
+
+// WithRunID returns a copy of the param that records runID in the
+// frontmatter. Empty by default.
+func (p NormalizeParam) WithRunID(runID string) NormalizeParam {
+	p.runID = runID
+	return p
+}
+
+// WithProvenance returns a copy of the param that records in the
+// frontmatter how the page was reached: referrer, the page it was
+// discovered on, empty when it has none, and redirectChain, the URLs
+// requested while fetching it. Not recorded by default.
+func (p NormalizeParam) WithProvenance(referrer url.URL, redirectChain []url.URL) NormalizeParam {
+	p.provenance = newProvenance(referrer, redirectChain)
+	return p
+}
+
+func (p NormalizeParam) PagePath() string {
+	return p.pagePath
+}
+
+// WithLinkRewriting returns a copy of the param that rewrites links to pages
+// in the output relative to pagePath, the page's own output path, and makes
+// the other relative links absolute (see links.go). Links are left as
+// written by default.
+func (p NormalizeParam) WithLinkRewriting(pagePath string, resolve LinkResolver) NormalizeParam {
+	p.pagePath = pagePath
+	p.linkResolver = resolve
+	return p
+}
+
+func (p NormalizeParam) DedupeHeadings() bool {
+	return p.dedupeHeadings
+}
+
+// WithQueryFilter returns a copy of the param whose canonical URL keeps the
+// query parameters for which keep returns true (see
+// urlutil.CanonicalizeKeepingQuery). All parameters are dropped by default.
+func (p NormalizeParam) WithQueryFilter(keep func(param string) bool) NormalizeParam {
+	p.keepQuery = keep
+	return p
+}
+
+// WithTrailingSlash returns a copy of the param whose canonical URL ends in
+// a slash as policy says (see urlutil.ApplyTrailingSlash). The slash is
+// stripped by default.
+func (p NormalizeParam) WithTrailingSlash(policy urlutil.TrailingSlashPolicy) NormalizeParam {
+	p.trailingSlash = policy
+	return p
+}
+
+// WithKeepFragment returns a copy of the param whose canonical URL keeps the
+// fragment of the fetch URL, so each part of a page split into several
+// documents, e.g. its articles, gets a document of its own. The fragment is
+// dropped by default.
+func (p NormalizeParam) WithKeepFragment(enabled bool) NormalizeParam {
+	p.keepFragment = enabled
+	return p
+}
+
+// WithDedupeHeadings returns a copy of the param that removes repeated
+// headings (see headings.go). Disabled by default.
+func (p NormalizeParam) WithDedupeHeadings(enabled bool) NormalizeParam {
+	p.dedupeHeadings = enabled
+	return p
+}
+
+func (p NormalizeParam) StripInvisibleInCode() bool {
+	return p.stripInvisibleInCode
+}
+
+// WithStripInvisibleInCode returns a copy of the param that also removes
+// zero-width and control characters from fenced code (see invisible.go).
+// Fenced code is left as written by default.
+func (p NormalizeParam) WithStripInvisibleInCode(enabled bool) NormalizeParam {
+	p.stripInvisibleInCode = enabled
+	return p
+}
+
 // headingInfo tracks a heading and its position for N5 validation
 type headingInfo struct {
 	node  *ast.Heading
@@ -0,0 +1,224 @@
+package normalize
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/gomarkdown/markdown"
+	mdhtml "github.com/gomarkdown/markdown/html"
+	"github.com/gomarkdown/markdown/parser"
+)
+
+/*
+Markdown Flavor
+
+Output defaults to GitHub Flavored Markdown. Some downstream tools only
+understand CommonMark, so in CommonMark mode the GFM-only constructs the
+pipeline may produce are downgraded:
+- pipe tables become HTML <table> blocks (CommonMark passes raw HTML through).
+  CommonMark does not parse Markdown inside an HTML block, so the inline
+  Markdown of each cell (links, emphasis, code spans) is rendered to HTML;
+  cells that would not render as a single paragraph are escaped as text
+- ~~strikethrough~~ markers are removed, keeping the text
+
+Fenced code blocks and inline code spans are left untouched.
+*/
+
+// MarkdownFlavor selects the Markdown dialect of normalized output.
+type MarkdownFlavor string
+
+const (
+	MarkdownFlavorGFM        MarkdownFlavor = "gfm"
+	MarkdownFlavorCommonMark MarkdownFlavor = "commonmark"
+)
+
+var (
+	tableDelimiterRowPattern = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+	strikethroughPattern     = regexp.MustCompile(`~~([^~\n]+?)~~`)
+)
+
+// downgradeToCommonMark rewrites GFM-only constructs in content so the
+// document renders the same way under a plain CommonMark parser.
+func downgradeToCommonMark(content []byte) []byte {
+	lines := strings.Split(string(content), "\n")
+
+	var out []string
+	fence := ""
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			out = append(out, line)
+			continue
+		}
+		if marker := fenceMarker(trimmed); marker != "" {
+			fence = marker
+			out = append(out, line)
+			continue
+		}
+
+		if i+1 < len(lines) && isTableHeaderRow(line, lines[i+1]) {
+			end := i + 2
+			for end < len(lines) && strings.TrimSpace(lines[end]) != "" && strings.Contains(lines[end], "|") {
+				end++
+			}
+			out = append(out, renderHTMLTable(line, lines[i+1], lines[i+2:end])...)
+			// An HTML block runs until a blank line, so keep following text out of it
+			if end < len(lines) && strings.TrimSpace(lines[end]) != "" {
+				out = append(out, "")
+			}
+			i = end - 1
+			continue
+		}
+
+		out = append(out, stripStrikethrough(line))
+	}
+
+	return []byte(strings.Join(out, "\n"))
+}
+
+// fenceMarker returns the opening fence of a fenced code block, or "".
+func fenceMarker(trimmed string) string {
+	for _, marker := range []string{"```", "~~~"} {
+		if strings.HasPrefix(trimmed, marker) {
+			return marker
+		}
+	}
+	return ""
+}
+
+// isTableHeaderRow reports whether line and next form the header and
+// delimiter rows of a GFM pipe table.
+func isTableHeaderRow(line, next string) bool {
+	if !strings.Contains(line, "|") || !strings.Contains(next, "|") {
+		return false
+	}
+	return tableDelimiterRowPattern.MatchString(next) &&
+		len(splitTableRow(line)) == len(splitTableRow(next))
+}
+
+// splitTableRow splits a pipe table row into trimmed cells.
+// Escaped pipes (\|) stay inside their cell.
+func splitTableRow(row string) []string {
+	row = strings.TrimSpace(row)
+	row = strings.TrimPrefix(row, "|")
+	if strings.HasSuffix(row, "|") && !strings.HasSuffix(row, `\|`) {
+		row = strings.TrimSuffix(row, "|")
+	}
+
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(row); i++ {
+		if row[i] == '\\' && i+1 < len(row) && row[i+1] == '|' {
+			cell.WriteByte('|')
+			i++
+			continue
+		}
+		if row[i] == '|' {
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+			continue
+		}
+		cell.WriteByte(row[i])
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// tableAlignments derives the align attribute of each column from the
+// delimiter row. Columns without an explicit alignment get "".
+func tableAlignments(delimiter string) []string {
+	var aligns []string
+	for _, cell := range splitTableRow(delimiter) {
+		left := strings.HasPrefix(cell, ":")
+		right := strings.HasSuffix(cell, ":")
+		switch {
+		case left && right:
+			aligns = append(aligns, "center")
+		case right:
+			aligns = append(aligns, "right")
+		case left:
+			aligns = append(aligns, "left")
+		default:
+			aligns = append(aligns, "")
+		}
+	}
+	return aligns
+}
+
+// renderHTMLTable renders a pipe table as HTML lines.
+func renderHTMLTable(header, delimiter string, rows []string) []string {
+	aligns := tableAlignments(delimiter)
+
+	var buf bytes.Buffer
+	buf.WriteString("<table>\n<thead>\n")
+	writeHTMLTableRow(&buf, "th", splitTableRow(header), aligns)
+	buf.WriteString("</thead>\n")
+	if len(rows) > 0 {
+		buf.WriteString("<tbody>\n")
+		for _, row := range rows {
+			writeHTMLTableRow(&buf, "td", splitTableRow(row), aligns)
+		}
+		buf.WriteString("</tbody>\n")
+	}
+	buf.WriteString("</table>")
+
+	return strings.Split(buf.String(), "\n")
+}
+
+// writeHTMLTableRow writes one <tr>, padding or truncating cells to the
+// column count as GFM does.
+func writeHTMLTableRow(buf *bytes.Buffer, tag string, cells []string, aligns []string) {
+	buf.WriteString("<tr>\n")
+	for col, align := range aligns {
+		text := ""
+		if col < len(cells) {
+			text = cells[col]
+		}
+		buf.WriteString("<" + tag)
+		if align != "" {
+			buf.WriteString(` align="` + align + `"`)
+		}
+		buf.WriteString(">" + renderTableCell(stripStrikethrough(text)) + "</" + tag + ">\n")
+	}
+	buf.WriteString("</tr>\n")
+}
+
+// renderTableCell renders the inline Markdown of a table cell to HTML.
+// Cells that render as anything but one paragraph, such as "1. step" or
+// "# title", are escaped as plain text instead.
+func renderTableCell(text string) string {
+	if text == "" {
+		return ""
+	}
+	p := parser.NewWithExtensions(parser.NoIntraEmphasis)
+	renderer := mdhtml.NewRenderer(mdhtml.RendererOptions{Flags: mdhtml.FlagsNone})
+	rendered := strings.TrimSuffix(string(markdown.ToHTML([]byte(text), p, renderer)), "\n")
+
+	inner, ok := strings.CutPrefix(rendered, "<p>")
+	if ok {
+		inner, ok = strings.CutSuffix(inner, "</p>")
+	}
+	if !ok || strings.Contains(inner, "<p>") {
+		return html.EscapeString(text)
+	}
+	return inner
+}
+
+// stripStrikethrough removes ~~ markers outside inline code spans.
+func stripStrikethrough(line string) string {
+	if !strings.Contains(line, "~~") {
+		return line
+	}
+	// Segments at odd indexes are inside backticks
+	segments := strings.Split(line, "`")
+	for i := 0; i < len(segments); i += 2 {
+		segments[i] = strikethroughPattern.ReplaceAllString(segments[i], "$1")
+	}
+	return strings.Join(segments, "`")
+}
@@ -0,0 +1,117 @@
+package normalize_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/assets"
+	"github.com/rohmanhakim/docs-crawler/internal/normalize"
+	"github.com/rohmanhakim/docs-crawler/pkg/hashutil"
+)
+
+const flavorContent = "# Release Notes\n\n" +
+	"Status of each feature:\n\n" +
+	"| Feature | Status |\n" +
+	"| :--- | ---: |\n" +
+	"| Search | ~~beta~~ stable |\n" +
+	"| Export | planned |\n" +
+	"Trailing paragraph.\n\n" +
+	"The ~~old~~ new behavior keeps `~~literal~~` code.\n\n" +
+	"```\n" +
+	"| a | b |\n" +
+	"| - | - |\n" +
+	"~~kept~~\n" +
+	"```\n"
+
+func normalizeWithFlavor(t *testing.T, content string, flavor normalize.MarkdownFlavor) normalize.NormalizedMarkdownDoc {
+	t.Helper()
+	constraint := normalize.NewMarkdownConstraint(&metadataSinkMock{})
+	fetchURL, _ := url.Parse("https://docs.example.com/guide/flavor")
+	param := normalize.NewNormalizeParam(
+		"v1.0.0",
+		time.Date(2026, 2, 12, 10, 15, 0, 0, time.UTC),
+		hashutil.HashAlgoSHA256,
+		1,
+		[]string{},
+	).WithMarkdownFlavor(flavor)
+
+	result, err := constraint.Normalize(*fetchURL, assets.NewAssetfulMarkdownDoc([]byte(content), nil, nil, nil), param)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	return result
+}
+
+func TestNormalize_MarkdownFlavor_DefaultsToGFM(t *testing.T) {
+	param := normalize.NewNormalizeParam("v1.0.0", time.Now(), hashutil.HashAlgoSHA256, 0, nil)
+	if param.MarkdownFlavor() != normalize.MarkdownFlavorGFM {
+		t.Errorf("expected default flavor %q, got %q", normalize.MarkdownFlavorGFM, param.MarkdownFlavor())
+	}
+}
+
+func TestNormalize_MarkdownFlavor_GFMKeepsTablesAndStrikethrough(t *testing.T) {
+	result := normalizeWithFlavor(t, flavorContent, normalize.MarkdownFlavorGFM)
+
+	if string(result.Content()) != flavorContent {
+		t.Errorf("expected GFM content unchanged.\nexpected:\n%s\ngot:\n%s", flavorContent, string(result.Content()))
+	}
+}
+
+func TestNormalize_MarkdownFlavor_CommonMarkDowngradesGFM(t *testing.T) {
+	result := normalizeWithFlavor(t, flavorContent, normalize.MarkdownFlavorCommonMark)
+	content := string(result.Content())
+
+	expectedTable := "<table>\n" +
+		"<thead>\n" +
+		"<tr>\n" +
+		"<th align=\"left\">Feature</th>\n" +
+		"<th align=\"right\">Status</th>\n" +
+		"</tr>\n" +
+		"</thead>\n" +
+		"<tbody>\n" +
+		"<tr>\n" +
+		"<td align=\"left\">Search</td>\n" +
+		"<td align=\"right\">beta stable</td>\n" +
+		"</tr>\n" +
+		"<tr>\n" +
+		"<td align=\"left\">Export</td>\n" +
+		"<td align=\"right\">planned</td>\n" +
+		"</tr>\n" +
+		"</tbody>\n" +
+		"</table>\n" +
+		"\n" +
+		"Trailing paragraph.\n"
+	if !strings.Contains(content, expectedTable) {
+		t.Errorf("expected table converted to HTML block.\nexpected:\n%s\ngot:\n%s", expectedTable, content)
+	}
+	if !strings.Contains(content, "The old new behavior keeps `~~literal~~` code.") {
+		t.Errorf("expected strikethrough markers removed outside code spans, got:\n%s", content)
+	}
+	if !strings.Contains(content, "```\n| a | b |\n| - | - |\n~~kept~~\n```\n") {
+		t.Errorf("expected fenced code block untouched, got:\n%s", content)
+	}
+}
+
+func TestNormalize_MarkdownFlavor_CommonMarkRendersInlineMarkdownInCells(t *testing.T) {
+	content := "# Options\n\n" +
+		"| Option | Description |\n" +
+		"| --- | --- |\n" +
+		"| `timeout` | See [the guide](https://example.com/guide) for **details** |\n" +
+		"| 1. first | a < b |\n"
+
+	result := normalizeWithFlavor(t, content, normalize.MarkdownFlavorCommonMark)
+	got := string(result.Content())
+
+	for _, want := range []string{
+		"<td><code>timeout</code></td>",
+		`<td>See <a href="https://example.com/guide">the guide</a> for <strong>details</strong></td>`,
+		"<td>1. first</td>",
+		"<td>a &lt; b</td>",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, got)
+		}
+	}
+}
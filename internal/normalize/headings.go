@@ -0,0 +1,93 @@
+package normalize
+
+import (
+	"regexp"
+	"strings"
+)
+
+/*
+Heading Deduplication
+
+Template quirks sometimes repeat headings in the extracted content. With
+DedupeHeadings set on NormalizeParam, two kinds of repeats are removed
+before the structure is validated, so such pages no longer fail it:
+- an H1 whose text exactly matches the page title, the first H1 that the
+  front matter title is taken from, after that first H1
+- a heading immediately following a heading of the same level and text,
+  with only blank lines between them
+
+A removed heading takes the blank lines that follow it along, and headings
+inside fenced code are never touched. Headings that repeat further apart
+are kept, since they usually are distinct sections. Output is fully
+deterministic.
+*/
+
+// atxHeadingPattern matches an ATX heading: the level markers and the text,
+// without any closing sequence.
+var atxHeadingPattern = regexp.MustCompile(`^ {0,3}(#{1,6})[ \t]+(.*?)(?:[ \t]+#+)?[ \t]*$`)
+
+// parseHeading returns the level and text of an ATX heading line, or
+// false when line is not a heading.
+func parseHeading(line string) (int, string, bool) {
+	parts := atxHeadingPattern.FindStringSubmatch(line)
+	if parts == nil {
+		return 0, "", false
+	}
+	return len(parts[1]), strings.TrimSpace(parts[2]), true
+}
+
+// dedupeHeadings removes the repeated headings of content.
+func dedupeHeadings(content []byte) []byte {
+	lines := strings.Split(string(content), "\n")
+	out := make([]string, 0, len(lines))
+
+	title := ""
+	prevLevel, prevText := 0, ""
+	// prevIsHeading is true while only blank lines follow the last heading
+	prevIsHeading := false
+	dropBlanks := false
+	fence := ""
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			out = append(out, line)
+			continue
+		}
+		if marker := fenceMarker(trimmed); marker != "" {
+			fence = marker
+			out = append(out, line)
+			prevIsHeading, dropBlanks = false, false
+			continue
+		}
+		if trimmed == "" {
+			if !dropBlanks {
+				out = append(out, line)
+			}
+			continue
+		}
+		dropBlanks = false
+
+		level, text, ok := parseHeading(line)
+		if !ok {
+			out = append(out, line)
+			prevIsHeading = false
+			continue
+		}
+
+		repeatsTitle := level == 1 && title != "" && stripInlineMarkdown(text) == title
+		repeatsPrevious := prevIsHeading && level == prevLevel && text == prevText
+		if repeatsTitle || repeatsPrevious {
+			dropBlanks = true
+			continue
+		}
+		if level == 1 && title == "" {
+			title = stripInlineMarkdown(text)
+		}
+		out = append(out, line)
+		prevLevel, prevText, prevIsHeading = level, text, true
+	}
+	return []byte(strings.Join(out, "\n"))
+}
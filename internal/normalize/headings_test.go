@@ -0,0 +1,113 @@
+package normalize_test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/assets"
+	"github.com/rohmanhakim/docs-crawler/internal/normalize"
+	"github.com/rohmanhakim/docs-crawler/pkg/hashutil"
+)
+
+func normalizeWithDedupeHeadings(t *testing.T, content string) normalize.NormalizedMarkdownDoc {
+	t.Helper()
+	constraint := normalize.NewMarkdownConstraint(&metadataSinkMock{})
+	fetchURL, _ := url.Parse("https://docs.example.com/guide/headings")
+	param := normalize.NewNormalizeParam(
+		"v1.0.0",
+		time.Date(2026, 2, 12, 10, 15, 0, 0, time.UTC),
+		hashutil.HashAlgoSHA256,
+		1,
+		[]string{},
+	).WithDedupeHeadings(true)
+
+	result, err := constraint.Normalize(*fetchURL, assets.NewAssetfulMarkdownDoc([]byte(content), nil, nil, nil), param)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	return result
+}
+
+func TestNormalize_DedupeHeadings_RemovesRepeatedTitle(t *testing.T) {
+	content := "# Getting Started\n\n# Getting Started\n\nIntro.\n\n## Install\n\nText.\n"
+
+	result := normalizeWithDedupeHeadings(t, content)
+
+	expected := "# Getting Started\n\nIntro.\n\n## Install\n\nText.\n"
+	if string(result.Content()) != expected {
+		t.Errorf("unexpected content.\nexpected:\n%s\ngot:\n%s", expected, string(result.Content()))
+	}
+	if result.Frontmatter().Title() != "Getting Started" {
+		t.Errorf("expected title %q, got %q", "Getting Started", result.Frontmatter().Title())
+	}
+}
+
+func TestNormalize_DedupeHeadings_RemovesLaterTitleRepeat(t *testing.T) {
+	content := "# **Getting Started**\n\nIntro.\n\n# Getting Started\n\nMore.\n"
+
+	result := normalizeWithDedupeHeadings(t, content)
+
+	expected := "# **Getting Started**\n\nIntro.\n\nMore.\n"
+	if string(result.Content()) != expected {
+		t.Errorf("unexpected content.\nexpected:\n%s\ngot:\n%s", expected, string(result.Content()))
+	}
+}
+
+func TestNormalize_DedupeHeadings_CollapsesAdjacentHeadings(t *testing.T) {
+	content := "# Guide\n\n" +
+		"## Overview\n\n## Overview\n\nFirst overview.\n\n" +
+		"## Usage\n\nText.\n\n" +
+		"## Overview\n\nSecond overview.\n"
+
+	result := normalizeWithDedupeHeadings(t, content)
+
+	expected := "# Guide\n\n" +
+		"## Overview\n\nFirst overview.\n\n" +
+		"## Usage\n\nText.\n\n" +
+		"## Overview\n\nSecond overview.\n"
+	if string(result.Content()) != expected {
+		t.Errorf("unexpected content.\nexpected:\n%s\ngot:\n%s", expected, string(result.Content()))
+	}
+}
+
+func TestNormalize_DedupeHeadings_KeepsDifferentLevelsAndCode(t *testing.T) {
+	content := "# Guide\n\n" +
+		"## Overview\n\n### Overview\n\nText.\n\n" +
+		"```\n## Overview\n## Overview\n```\n"
+
+	result := normalizeWithDedupeHeadings(t, content)
+
+	if string(result.Content()) != content {
+		t.Errorf("expected content unchanged.\nexpected:\n%s\ngot:\n%s", content, string(result.Content()))
+	}
+}
+
+func TestNormalize_DedupeHeadings_DisabledByDefault(t *testing.T) {
+	constraint := normalize.NewMarkdownConstraint(&metadataSinkMock{})
+	fetchURL, _ := url.Parse("https://docs.example.com/guide/headings")
+	param := normalize.NewNormalizeParam(
+		"v1.0.0",
+		time.Date(2026, 2, 12, 10, 15, 0, 0, time.UTC),
+		hashutil.HashAlgoSHA256,
+		1,
+		[]string{},
+	)
+	content := "# Guide\n\n# Guide\n\nIntro.\n"
+
+	// Left as is, the repeated H1 breaks the single-H1 invariant
+	if _, err := constraint.Normalize(*fetchURL, assets.NewAssetfulMarkdownDoc([]byte(content), nil, nil, nil), param); err == nil {
+		t.Error("expected the repeated H1 to fail validation without DedupeHeadings")
+	}
+}
+
+func TestNormalize_DedupeHeadings_Deterministic(t *testing.T) {
+	content := "# Guide\n\n# Guide\n\n## A\n\n## A\n\nText.\n"
+
+	first := normalizeWithDedupeHeadings(t, content)
+	second := normalizeWithDedupeHeadings(t, content)
+
+	if string(first.Content()) != string(second.Content()) {
+		t.Error("expected identical output for identical input")
+	}
+}
@@ -0,0 +1,66 @@
+package normalize
+
+import (
+	"strings"
+	"unicode"
+)
+
+/*
+Invisible Characters
+
+Pages carry characters that render as nothing but still end up in the
+Markdown: a byte order mark at the start of the text, zero-width spaces
+inserted as line break hints, stray control characters from copy-paste.
+They break heading parsing, search and diffs, so Normalize removes them
+before anything else:
+- the zero-width space, non-joiner and word joiner, and the byte order mark
+  (U+200B, U+200C, U+2060, U+FEFF)
+- control characters other than tab and newline
+
+The zero-width joiner is kept, since emoji sequences depend on it.
+
+Fenced code is left as written by default, since code examples may be about
+these very characters; WithStripInvisibleInCode strips it too. Output is
+fully deterministic.
+*/
+
+// isInvisible reports whether r is removed by stripInvisible.
+func isInvisible(r rune) bool {
+	switch r {
+	case '\t', '\n':
+		return false
+	case '\u200b', '\u200c', '\u2060', '\ufeff':
+		return true
+	}
+	return unicode.IsControl(r)
+}
+
+// stripInvisible removes the invisible characters of content, inside fenced
+// code too when includeCode is true.
+func stripInvisible(content []byte, includeCode bool) []byte {
+	if includeCode {
+		return []byte(strings.Map(dropInvisible, string(content)))
+	}
+
+	lines := strings.Split(string(content), "\n")
+	fence := ""
+	for i, line := range lines {
+		if fence != "" {
+			if strings.HasPrefix(strings.TrimSpace(line), fence) {
+				fence = ""
+			}
+			continue
+		}
+		lines[i] = strings.Map(dropInvisible, line)
+		fence = fenceMarker(strings.TrimSpace(lines[i]))
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+// dropInvisible is the strings.Map mapping of stripInvisible.
+func dropInvisible(r rune) rune {
+	if isInvisible(r) {
+		return -1
+	}
+	return r
+}
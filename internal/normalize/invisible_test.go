@@ -0,0 +1,88 @@
+package normalize_test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/assets"
+	"github.com/rohmanhakim/docs-crawler/internal/normalize"
+	"github.com/rohmanhakim/docs-crawler/pkg/hashutil"
+)
+
+func normalizeInvisible(t *testing.T, content string, includeCode bool) normalize.NormalizedMarkdownDoc {
+	t.Helper()
+	constraint := normalize.NewMarkdownConstraint(&metadataSinkMock{})
+	fetchURL, _ := url.Parse("https://docs.example.com/guide/invisible")
+	param := normalize.NewNormalizeParam(
+		"v1.0.0",
+		time.Date(2026, 2, 12, 10, 15, 0, 0, time.UTC),
+		hashutil.HashAlgoSHA256,
+		1,
+		[]string{},
+	).WithStripInvisibleInCode(includeCode)
+
+	result, err := constraint.Normalize(*fetchURL, assets.NewAssetfulMarkdownDoc([]byte(content), nil, nil, nil), param)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	return result
+}
+
+func TestNormalize_StripsInvisibleCharacters(t *testing.T) {
+	content := "\ufeff# Getting Started\n\n" +
+		"Install the zero\u200bwidth package.\n\n" +
+		"Run it\x07 now\x1b.\n\n" +
+		"\tIndented text\u200c and a\u2060 word joiner.\n"
+
+	result := normalizeInvisible(t, content, false)
+
+	expected := "# Getting Started\n\n" +
+		"Install the zerowidth package.\n\n" +
+		"Run it now.\n\n" +
+		"\tIndented text and a word joiner.\n"
+	if string(result.Content()) != expected {
+		t.Errorf("unexpected content.\nexpected:\n%q\ngot:\n%q", expected, string(result.Content()))
+	}
+	if result.Frontmatter().Title() != "Getting Started" {
+		t.Errorf("expected title %q, got %q", "Getting Started", result.Frontmatter().Title())
+	}
+}
+
+func TestNormalize_StripsInvisibleCharacters_KeepsZeroWidthJoiner(t *testing.T) {
+	content := "# Emoji\n\nA family: \U0001F468\u200d\U0001F469\u200d\U0001F467.\n"
+
+	result := normalizeInvisible(t, content, false)
+
+	if string(result.Content()) != content {
+		t.Errorf("unexpected content.\nexpected:\n%q\ngot:\n%q", content, string(result.Content()))
+	}
+}
+
+func TestNormalize_StripsInvisibleCharacters_KeepsCodeFences(t *testing.T) {
+	content := "# Guide\n\nText\u200b here.\n\n" +
+		"```go\nconst bom = \"\ufeff\"\t// byte order mark\x00\n```\n\n" +
+		"After\u200b the fence.\n"
+
+	result := normalizeInvisible(t, content, false)
+
+	expected := "# Guide\n\nText here.\n\n" +
+		"```go\nconst bom = \"\ufeff\"\t// byte order mark\x00\n```\n\n" +
+		"After the fence.\n"
+	if string(result.Content()) != expected {
+		t.Errorf("unexpected content.\nexpected:\n%q\ngot:\n%q", expected, string(result.Content()))
+	}
+}
+
+func TestNormalize_StripsInvisibleCharacters_InCodeWhenEnabled(t *testing.T) {
+	content := "# Guide\n\n" +
+		"~~~\nvalue\u200b = 1\x7f\n\tindented\n~~~\n"
+
+	result := normalizeInvisible(t, content, true)
+
+	expected := "# Guide\n\n" +
+		"~~~\nvalue = 1\n\tindented\n~~~\n"
+	if string(result.Content()) != expected {
+		t.Errorf("unexpected content.\nexpected:\n%q\ngot:\n%q", expected, string(result.Content()))
+	}
+}
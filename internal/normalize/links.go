@@ -0,0 +1,98 @@
+package normalize
+
+import (
+	"net/url"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+/*
+Internal Link Rewriting
+
+A browsable export needs links between pages to point at the written files
+rather than back at the site. With a LinkResolver set on NormalizeParam,
+every Markdown link outside fenced code is resolved against the page URL
+and looked up:
+- a target with a page in the output is rewritten to the path of that page
+  relative to the current page's output path, keeping any fragment, e.g.
+  "../fr/3f2a9c1b7d4e.md#usage"
+- any other target is unresolved and left as an absolute URL, so it keeps
+  working outside the output directory
+
+Images, fragment-only links and non-HTTP links (mailto:, ...) are left as
+they are. Paths are joined with "/" whatever the OS, so the output is the
+same on every platform.
+*/
+
+// LinkResolver returns the output path of the page written for target, or
+// false when the output has no page for it.
+type LinkResolver func(target url.URL) (string, bool)
+
+// markdownLinkPattern matches an inline link that is not an image:
+// the character before it, the text, the destination and an optional title.
+var markdownLinkPattern = regexp.MustCompile(`(^|[^!\\])\[([^\]]*)\]\(([^)\s]+)(\s+"[^"]*")?\)`)
+
+// rewriteInternalLinks rewrites the links of content written to pagePath
+// for the page at pageURL.
+func rewriteInternalLinks(content []byte, pageURL url.URL, pagePath string, resolve LinkResolver) []byte {
+	lines := strings.Split(string(content), "\n")
+	fence := ""
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if marker := fenceMarker(trimmed); marker != "" {
+			fence = marker
+			continue
+		}
+		lines[i] = markdownLinkPattern.ReplaceAllStringFunc(line, func(match string) string {
+			parts := markdownLinkPattern.FindStringSubmatch(match)
+			destination, ok := rewriteLinkDestination(parts[3], pageURL, pagePath, resolve)
+			if !ok {
+				return match
+			}
+			return parts[1] + "[" + parts[2] + "](" + destination + parts[4] + ")"
+		})
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+// rewriteLinkDestination returns the rewritten destination of a link, or
+// false when it is left as written.
+func rewriteLinkDestination(raw string, pageURL url.URL, pagePath string, resolve LinkResolver) (string, bool) {
+	if strings.HasPrefix(raw, "#") {
+		return "", false
+	}
+	ref, err := url.Parse(raw)
+	if err != nil {
+		return "", false
+	}
+	target := pageURL.ResolveReference(ref)
+	if target.Scheme != "http" && target.Scheme != "https" {
+		return "", false
+	}
+
+	targetPath, ok := resolve(*target)
+	if !ok {
+		if ref.IsAbs() {
+			return "", false
+		}
+		return target.String(), true
+	}
+
+	relative, err := filepath.Rel(filepath.Dir(pagePath), targetPath)
+	if err != nil {
+		return target.String(), true
+	}
+	destination := path.Clean(filepath.ToSlash(relative))
+	if target.Fragment != "" {
+		destination += "#" + target.EscapedFragment()
+	}
+	return destination, true
+}
@@ -0,0 +1,70 @@
+package normalize_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/assets"
+	"github.com/rohmanhakim/docs-crawler/internal/normalize"
+	"github.com/rohmanhakim/docs-crawler/pkg/hashutil"
+)
+
+func TestNormalize_LinkRewriting(t *testing.T) {
+	// The page and its target are written to different subdirectories
+	written := map[string]string{
+		"https://docs.example.com/guide/target":  "out/ab/target.md",
+		"https://docs.example.com/guide/sibling": "out/cd/sibling.md",
+	}
+	resolve := func(target url.URL) (string, bool) {
+		target.Fragment = ""
+		path, ok := written[target.String()]
+		return path, ok
+	}
+
+	content := "# Guide\n\n" +
+		"See [the target](target#usage) and [a sibling](https://docs.example.com/guide/sibling \"Sibling\").\n\n" +
+		"Not written: [missing](/guide/missing) and [external](https://other.example.com/x).\n\n" +
+		"Kept: [top](#guide), [mail](mailto:docs@example.com) and ![diagram](target).\n\n" +
+		"```\n[in code](target)\n```\n"
+
+	constraint := normalize.NewMarkdownConstraint(&metadataSinkMock{})
+	fetchURL, _ := url.Parse("https://docs.example.com/guide/page")
+	param := normalize.NewNormalizeParam(
+		"v1.0.0",
+		time.Date(2026, 2, 12, 10, 15, 0, 0, time.UTC),
+		hashutil.HashAlgoSHA256,
+		1,
+		[]string{},
+	).WithLinkRewriting("out/cd/page.md", resolve)
+
+	result, err := constraint.Normalize(*fetchURL, assets.NewAssetfulMarkdownDoc([]byte(content), nil, nil, nil), param)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	got := string(result.Content())
+
+	for _, want := range []string{
+		"[the target](../ab/target.md#usage)",
+		"[a sibling](sibling.md \"Sibling\")",
+		"[missing](https://docs.example.com/guide/missing)",
+		"[external](https://other.example.com/x)",
+		"[top](#guide)",
+		"[mail](mailto:docs@example.com)",
+		"![diagram](target)",
+		"[in code](target)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestNormalize_LinkRewriting_DisabledByDefault(t *testing.T) {
+	content := "# Guide\n\nSee [the target](target).\n"
+	result := normalizeWithToC(t, content, false)
+	if !strings.Contains(string(result.Content()), "[the target](target)") {
+		t.Errorf("expected links to be left as written, got:\n%s", result.Content())
+	}
+}
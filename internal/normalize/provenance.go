@@ -0,0 +1,46 @@
+package normalize
+
+import "net/url"
+
+/*
+Provenance
+
+For auditing, the frontmatter can record how a page was reached (see
+NormalizeParam.WithProvenance):
+- referrer: the page the URL was discovered on; empty for seeds and for
+  URLs not found on a page, such as sitemap entries
+- redirect chain: every URL requested while fetching the page, from the
+  URL taken from the frontier to the one that answered; empty when the
+  fetch was not redirected
+
+Together with crawl_depth this traces each document back to a seed.
+*/
+
+// Provenance records how a page was reached.
+type Provenance struct {
+	referrer      string
+	redirectChain []string
+}
+
+func newProvenance(referrer url.URL, redirectChain []url.URL) *Provenance {
+	p := &Provenance{}
+	if referrer.Host != "" {
+		p.referrer = referrer.String()
+	}
+	for _, u := range redirectChain {
+		p.redirectChain = append(p.redirectChain, u.String())
+	}
+	return p
+}
+
+// Referrer returns the page the URL was discovered on, or "" when it has
+// none.
+func (p Provenance) Referrer() string {
+	return p.referrer
+}
+
+// RedirectChain returns the URLs requested while fetching the page, in
+// order, or nil when it was not redirected.
+func (p Provenance) RedirectChain() []string {
+	return append([]string(nil), p.redirectChain...)
+}
@@ -0,0 +1,105 @@
+package normalize
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/rohmanhakim/docs-crawler/pkg/failure"
+	"github.com/rohmanhakim/docs-crawler/pkg/hashutil"
+)
+
+/*
+Structural Hash
+
+content_hash changes with every edit, down to a fixed typo. For incremental
+processing that only cares about meaningful changes, the frontmatter also
+carries a structural hash over the skeleton of the page:
+- the heading outline: the level and text of every ATX heading, in order,
+  with inline formatting removed
+- the internal link targets: every link resolving to the page's own host,
+  without fragment, as a sorted set
+
+Prose, code and external links do not contribute, so two pages differing
+only in wording share a structural hash, while adding, removing, renaming
+or moving a heading, or linking to another page of the site, changes it.
+Headings and links inside fenced code are ignored.
+*/
+
+// computeStructuralHash returns the "<algo>:<hash>" structural hash of
+// content for the page at pageURL.
+func computeStructuralHash(content []byte, pageURL url.URL, hashAlgo hashutil.HashAlgo) (string, failure.ClassifiedError) {
+	hash, err := hashutil.HashBytes([]byte(structuralOutline(content, pageURL)), hashAlgo)
+	if err != nil {
+		return "", NewNormalizationError(
+			ErrCauseHashComputationFailed,
+			fmt.Sprintf("failed to compute structural hash: %v", err),
+		)
+	}
+	return string(hashAlgo) + ":" + hash, nil
+}
+
+// structuralOutline renders the headings and the internal link targets of
+// content, one per line: headings as "h<level> <text>", then links as
+// "link <url>".
+func structuralOutline(content []byte, pageURL url.URL) string {
+	var outline []string
+	links := make(map[string]struct{})
+
+	fence := ""
+	for _, line := range strings.Split(string(content), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if marker := fenceMarker(trimmed); marker != "" {
+			fence = marker
+			continue
+		}
+		if level, text, ok := parseHeading(line); ok {
+			outline = append(outline, fmt.Sprintf("h%d %s", level, stripInlineMarkdown(text)))
+		}
+		for _, parts := range markdownLinkPattern.FindAllStringSubmatch(line, -1) {
+			if target, ok := internalLinkTarget(parts[3], pageURL); ok {
+				links[target] = struct{}{}
+			}
+		}
+	}
+
+	targets := make([]string, 0, len(links))
+	for target := range links {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+	for _, target := range targets {
+		outline = append(outline, "link "+target)
+	}
+	return strings.Join(outline, "\n")
+}
+
+// internalLinkTarget resolves a link destination against pageURL and returns
+// it without fragment, or false when it leads off the page's host.
+// Fragment-only links point into the page itself and are skipped.
+func internalLinkTarget(raw string, pageURL url.URL) (string, bool) {
+	if strings.HasPrefix(raw, "#") {
+		return "", false
+	}
+	ref, err := url.Parse(raw)
+	if err != nil {
+		return "", false
+	}
+	target := pageURL.ResolveReference(ref)
+	if target.Scheme != "http" && target.Scheme != "https" {
+		return "", false
+	}
+	if !strings.EqualFold(target.Host, pageURL.Host) {
+		return "", false
+	}
+	target.Fragment = ""
+	target.RawFragment = ""
+	return target.String(), true
+}
@@ -0,0 +1,68 @@
+package normalize_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/assets"
+	"github.com/rohmanhakim/docs-crawler/internal/normalize"
+	"github.com/rohmanhakim/docs-crawler/pkg/hashutil"
+)
+
+func structuralHashForTest(t *testing.T, content string) string {
+	t.Helper()
+	constraint := normalize.NewMarkdownConstraint(&metadataSinkMock{})
+	fetchURL, _ := url.Parse("https://docs.example.com/guide/install")
+	param := normalize.NewNormalizeParam(
+		"v1.0.0",
+		time.Date(2026, 2, 12, 10, 15, 0, 0, time.UTC),
+		hashutil.HashAlgoSHA256,
+		1,
+		[]string{},
+	)
+
+	result, err := constraint.Normalize(*fetchURL, assets.NewAssetfulMarkdownDoc([]byte(content), nil, nil, nil), param)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	return result.Frontmatter().StructuralHash()
+}
+
+const structurePage = "# Install\n\n" +
+	"Download the package from [the downloads page](/downloads).\n\n" +
+	"## Linux\n\nRun the installer, then read [the guide](https://docs.example.com/guide/start#linux).\n\n" +
+	"## Windows\n\nSee [Microsoft](https://www.microsoft.com/) for details.\n"
+
+func TestStructuralHash_IgnoresProse(t *testing.T) {
+	reworded := "# Install\n\n" +
+		"Get the **package** from [downloads](https://docs.example.com/downloads).\n\n" +
+		"## Linux\n\nUse the installer, and see [the guide](../guide/start) afterwards.\n\n" +
+		"```sh\n# not a heading\ncurl https://docs.example.com/other\n```\n\n" +
+		"## Windows\n\nSee [the vendor](https://learn.microsoft.com/) for details.\n"
+
+	hash := structuralHashForTest(t, structurePage)
+	if !strings.HasPrefix(hash, "sha256:") {
+		t.Errorf("expected a sha256 structural hash, got %q", hash)
+	}
+	if got := structuralHashForTest(t, reworded); got != hash {
+		t.Errorf("expected pages differing in prose to share a structural hash, got %q and %q", hash, got)
+	}
+}
+
+func TestStructuralHash_ChangesWithStructure(t *testing.T) {
+	hash := structuralHashForTest(t, structurePage)
+
+	changes := map[string]string{
+		"renamed heading": strings.Replace(structurePage, "## Windows", "## Microsoft Windows", 1),
+		"heading level":   strings.Replace(structurePage, "## Windows", "### Windows", 1),
+		"added heading":   structurePage + "\n## macOS\n\nSoon.\n",
+		"internal link":   strings.Replace(structurePage, "(/downloads)", "(/releases)", 1),
+	}
+	for name, content := range changes {
+		if got := structuralHashForTest(t, content); got == hash {
+			t.Errorf("%s: expected a different structural hash", name)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package normalize
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gomarkdown/markdown"
+	"github.com/gomarkdown/markdown/ast"
+	"github.com/gomarkdown/markdown/parser"
+	"github.com/rohmanhakim/docs-crawler/internal/extractor"
+)
+
+/*
+Structured Sections
+
+When the extractor reports API reference blocks (extractor.StructuredSection),
+the body of each matching heading is re-rendered in one fixed layout, so
+every documented member reads the same regardless of the source markup:
+
+	### Get
+
+	```
+	func (c *Client) Get(key string) (Value, error)
+	```
+
+	Looks up a value.
+
+	**Parameters**
+
+	- `key`: the key to look up
+
+	**Returns:** the stored value
+
+	**Example**
+
+	```
+	v, err := c.Get("greeting")
+	```
+
+A section matches the first ATX heading after the previous match whose plain
+text equals its Heading; sections without a match are skipped. The body runs
+to the next heading of the same or higher level, as in the extractor, so
+sub-headings inside it are replaced along with the rest of the block.
+*/
+
+// structuredHeading is an ATX heading line found outside fenced code.
+type structuredHeading struct {
+	line  int
+	level int
+	text  string
+}
+
+// renderStructuredSections replaces the body of each heading matching one of
+// sections with its rendered fields.
+func renderStructuredSections(content []byte, sections []extractor.StructuredSection) []byte {
+	if len(sections) == 0 {
+		return content
+	}
+	lines := strings.SplitAfter(string(content), "\n")
+	headings := scanStructuredHeadings(lines)
+
+	var out strings.Builder
+	copied, searchFrom := 0, 0
+	for _, section := range sections {
+		for i := searchFrom; i < len(headings); i++ {
+			heading := headings[i]
+			if heading.text != section.Heading {
+				continue
+			}
+
+			end, next := len(lines), len(headings)
+			for j := i + 1; j < len(headings); j++ {
+				if headings[j].level <= heading.level {
+					end, next = headings[j].line, j
+					break
+				}
+			}
+
+			for _, l := range lines[copied : heading.line+1] {
+				out.WriteString(l)
+			}
+			if !strings.HasSuffix(lines[heading.line], "\n") {
+				out.WriteString("\n")
+			}
+			out.WriteString("\n")
+			out.WriteString(renderStructuredBody(section))
+			if end < len(lines) {
+				out.WriteString("\n")
+			}
+			copied, searchFrom = end, next
+			break
+		}
+	}
+	for _, l := range lines[copied:] {
+		out.WriteString(l)
+	}
+	return []byte(out.String())
+}
+
+// scanStructuredHeadings returns the ATX headings of lines with their plain
+// text, skipping lines inside fenced code blocks.
+func scanStructuredHeadings(lines []string) []structuredHeading {
+	var headings []structuredHeading
+	fence := ""
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if marker := fenceMarker(trimmed); marker != "" {
+			fence = marker
+			continue
+		}
+		if !strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		doc := markdown.Parse([]byte(trimmed), parser.New())
+		if len(doc.GetChildren()) == 0 {
+			continue
+		}
+		if heading, ok := doc.GetChildren()[0].(*ast.Heading); ok {
+			headings = append(headings, structuredHeading{
+				line:  i,
+				level: heading.Level,
+				text:  strings.Join(strings.Fields(headingPlainText(heading)), " "),
+			})
+		}
+	}
+	return headings
+}
+
+// renderStructuredBody renders the fields of section in the fixed order
+// signature, descriptions, parameters, return value, examples.
+func renderStructuredBody(section extractor.StructuredSection) string {
+	var (
+		signatures, descriptions, params, returns, examples []extractor.StructuredField
+	)
+	for _, f := range section.Fields {
+		switch f.Kind {
+		case extractor.StructuredFieldSignature:
+			signatures = append(signatures, f)
+		case extractor.StructuredFieldDescription:
+			descriptions = append(descriptions, f)
+		case extractor.StructuredFieldParam:
+			params = append(params, f)
+		case extractor.StructuredFieldReturns:
+			returns = append(returns, f)
+		case extractor.StructuredFieldExample:
+			examples = append(examples, f)
+		}
+	}
+
+	var blocks []string
+	for _, f := range signatures {
+		blocks = append(blocks, fencedCode(f.Value))
+	}
+	for _, f := range descriptions {
+		blocks = append(blocks, escapeStructuredText(f.Value))
+	}
+	if len(params) > 0 {
+		var list strings.Builder
+		for i, f := range params {
+			if i > 0 {
+				list.WriteString("\n")
+			}
+			list.WriteString("- " + codeSpan(f.Name))
+			if f.Value != "" {
+				list.WriteString(": " + escapeStructuredText(f.Value))
+			}
+		}
+		blocks = append(blocks, "**Parameters**", list.String())
+	}
+	for _, f := range returns {
+		blocks = append(blocks, "**Returns:** "+escapeStructuredText(f.Value))
+	}
+	if len(examples) > 0 {
+		blocks = append(blocks, "**Example**")
+		for _, f := range examples {
+			blocks = append(blocks, fencedCode(f.Value))
+		}
+	}
+	return strings.Join(blocks, "\n\n") + "\n"
+}
+
+// fencedCode wraps code in a backtick fence longer than any backtick run
+// inside it.
+func fencedCode(code string) string {
+	fence := strings.Repeat("`", max(3, longestBacktickRun(code)+1))
+	return fmt.Sprintf("%s\n%s\n%s", fence, strings.TrimRight(code, "\n"), fence)
+}
+
+// codeSpan wraps text in an inline code span that survives backticks in text.
+func codeSpan(text string) string {
+	ticks := strings.Repeat("`", longestBacktickRun(text)+1)
+	if strings.HasPrefix(text, "`") || strings.HasSuffix(text, "`") {
+		return ticks + " " + text + " " + ticks
+	}
+	return ticks + text + ticks
+}
+
+func longestBacktickRun(s string) int {
+	longest, run := 0, 0
+	for _, r := range s {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+			continue
+		}
+		run = 0
+	}
+	return longest
+}
+
+// structuredTextEscaper escapes the characters that would start inline
+// Markdown in plain text taken from HTML.
+var structuredTextEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	"*", `\*`,
+	"_", `\_`,
+	"[", `\[`,
+	"]", `\]`,
+	"<", `\<`,
+)
+
+// escapeStructuredText escapes text for use as a paragraph or list item. A
+// leading block marker is escaped too, so the text cannot become a heading,
+// quote or nested list.
+func escapeStructuredText(text string) string {
+	text = structuredTextEscaper.Replace(text)
+	if strings.HasPrefix(text, "#") || strings.HasPrefix(text, ">") ||
+		strings.HasPrefix(text, "-") || strings.HasPrefix(text, "+") {
+		text = `\` + text
+	}
+	return text
+}
@@ -0,0 +1,141 @@
+package normalize_test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/assets"
+	"github.com/rohmanhakim/docs-crawler/internal/extractor"
+	"github.com/rohmanhakim/docs-crawler/internal/normalize"
+	"github.com/rohmanhakim/docs-crawler/pkg/hashutil"
+)
+
+const structuredContent = "# Cache API Reference\n\n" +
+	"The cache client stores values by key.\n\n" +
+	"## Methods\n\n" +
+	"### Get\n\n" +
+	"Looks up a value.\n\n" +
+	"```\nfunc (c *Client) Get(key string) (Value, error)\n```\n\n" +
+	"**Parameters**\n\n" +
+	"- `key` – the key to look up\n\n" +
+	"**Returns:** the stored value.\n\n" +
+	"### Delete\n\n" +
+	"```\nfunc (c *Client) Delete(key string) bool\n```\n\n" +
+	"Parameters:\n\n" +
+	"key\n\n" +
+	": the key to remove\n\n" +
+	"#### Notes\n\n" +
+	"Deleting is idempotent.\n\n" +
+	"## Examples\n\n" +
+	"Use the client.\n"
+
+func normalizeWithStructuredSections(t *testing.T, content string, sections []extractor.StructuredSection) normalize.NormalizedMarkdownDoc {
+	t.Helper()
+	constraint := normalize.NewMarkdownConstraint(&metadataSinkMock{})
+	fetchURL, _ := url.Parse("https://docs.example.com/api/cache")
+	param := normalize.NewNormalizeParam(
+		"v1.0.0",
+		time.Date(2026, 2, 12, 10, 15, 0, 0, time.UTC),
+		hashutil.HashAlgoSHA256,
+		1,
+		[]string{},
+	).WithStructuredSections(sections)
+
+	result, err := constraint.Normalize(*fetchURL, assets.NewAssetfulMarkdownDoc([]byte(content), nil, nil, nil), param)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	return result
+}
+
+func TestNormalize_StructuredSections_RenderedInConsistentLayout(t *testing.T) {
+	sections := []extractor.StructuredSection{
+		{
+			Kind:    extractor.StructuredSectionAPI,
+			Heading: "Get",
+			Fields: []extractor.StructuredField{
+				{Kind: extractor.StructuredFieldSignature, Value: "func (c *Client) Get(key string) (Value, error)"},
+				{Kind: extractor.StructuredFieldDescription, Value: "Looks up a value."},
+				{Kind: extractor.StructuredFieldParam, Name: "key", Value: "the key to look up"},
+				{Kind: extractor.StructuredFieldReturns, Value: "the stored value."},
+			},
+		},
+		{
+			Kind:    extractor.StructuredSectionAPI,
+			Heading: "Delete",
+			Fields: []extractor.StructuredField{
+				{Kind: extractor.StructuredFieldSignature, Value: "func (c *Client) Delete(key string) bool"},
+				{Kind: extractor.StructuredFieldParam, Name: "key", Value: "the key to remove"},
+				{Kind: extractor.StructuredFieldDescription, Value: "Notes"},
+				{Kind: extractor.StructuredFieldDescription, Value: "Deleting is idempotent."},
+			},
+		},
+	}
+
+	result := normalizeWithStructuredSections(t, structuredContent, sections)
+
+	expected := "# Cache API Reference\n\n" +
+		"The cache client stores values by key.\n\n" +
+		"## Methods\n\n" +
+		"### Get\n\n" +
+		"```\nfunc (c *Client) Get(key string) (Value, error)\n```\n\n" +
+		"Looks up a value.\n\n" +
+		"**Parameters**\n\n" +
+		"- `key`: the key to look up\n\n" +
+		"**Returns:** the stored value.\n\n" +
+		"### Delete\n\n" +
+		"```\nfunc (c *Client) Delete(key string) bool\n```\n\n" +
+		"Notes\n\n" +
+		"Deleting is idempotent.\n\n" +
+		"**Parameters**\n\n" +
+		"- `key`: the key to remove\n\n" +
+		"## Examples\n\n" +
+		"Use the client.\n"
+	if string(result.Content()) != expected {
+		t.Errorf("unexpected content.\nexpected:\n%s\ngot:\n%s", expected, string(result.Content()))
+	}
+}
+
+func TestNormalize_StructuredSections_UnmatchedHeadingLeavesContent(t *testing.T) {
+	sections := []extractor.StructuredSection{
+		{
+			Kind:    extractor.StructuredSectionAPI,
+			Heading: "Missing",
+			Fields: []extractor.StructuredField{
+				{Kind: extractor.StructuredFieldSignature, Value: "func Missing()"},
+				{Kind: extractor.StructuredFieldReturns, Value: "nothing"},
+			},
+		},
+	}
+
+	result := normalizeWithStructuredSections(t, structuredContent, sections)
+
+	if string(result.Content()) != structuredContent {
+		t.Errorf("expected content unchanged.\nexpected:\n%s\ngot:\n%s", structuredContent, string(result.Content()))
+	}
+}
+
+func TestNormalize_StructuredSections_EscapesText(t *testing.T) {
+	content := "# API\n\n## Run\n\nRuns.\n\n## Stop\n\nStops.\n"
+	sections := []extractor.StructuredSection{
+		{
+			Kind:    extractor.StructuredSectionAPI,
+			Heading: "Stop",
+			Fields: []extractor.StructuredField{
+				{Kind: extractor.StructuredFieldSignature, Value: "stop(`force`)"},
+				{Kind: extractor.StructuredFieldParam, Name: "`force`", Value: "# of *retries*"},
+			},
+		},
+	}
+
+	result := normalizeWithStructuredSections(t, content, sections)
+
+	expected := "# API\n\n## Run\n\nRuns.\n\n## Stop\n\n" +
+		"```\nstop(`force`)\n```\n\n" +
+		"**Parameters**\n\n" +
+		"- `` `force` ``: \\# of \\*retries\\*\n"
+	if string(result.Content()) != expected {
+		t.Errorf("unexpected content.\nexpected:\n%s\ngot:\n%s", expected, string(result.Content()))
+	}
+}
@@ -0,0 +1,195 @@
+package normalize
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/gomarkdown/markdown"
+	"github.com/gomarkdown/markdown/ast"
+	"github.com/gomarkdown/markdown/parser"
+)
+
+/*
+Table of Contents
+
+The ToC is derived from the heading outline of the (already validated)
+Markdown document and inserted directly below the H1 title:
+
+	# Title
+
+	- [Section](#section)
+	  - [Subsection](#subsection)
+
+Anchors follow GitHub Flavored Markdown slugging so the links resolve when
+the document is rendered by GFM-compatible viewers:
+- lowercase the heading text
+- drop every character that is not a letter, number, mark, '_', '-' or space
+- replace each space with '-'
+- repeated slugs get '-1', '-2', ... suffixes in document order
+
+The H1 takes part in slug deduplication (it has an anchor too) but is not
+listed, since it is the document title. Output is fully deterministic.
+*/
+
+// tocHeading is a heading entry used for ToC generation.
+type tocHeading struct {
+	level  int
+	text   string
+	anchor string
+}
+
+// insertToC returns content with a generated ToC inserted after the H1 line;
+// "# " lines inside fenced code are not headings and are skipped.
+// Content is returned unchanged when there are no headings below the H1.
+func insertToC(content []byte) []byte {
+	headings := collectToCHeadings(content)
+
+	var toc bytes.Buffer
+	for _, h := range headings {
+		if h.level == 1 {
+			continue
+		}
+		indent := strings.Repeat("  ", h.level-2)
+		fmt.Fprintf(&toc, "%s- [%s](#%s)\n", indent, escapeLinkText(h.text), h.anchor)
+	}
+	if toc.Len() == 0 {
+		return content
+	}
+
+	lines := bytes.SplitAfter(content, []byte("\n"))
+	fence := ""
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(string(line))
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if marker := fenceMarker(trimmed); marker != "" {
+			fence = marker
+			continue
+		}
+		if !strings.HasPrefix(trimmed, "# ") {
+			continue
+		}
+
+		var out bytes.Buffer
+		for _, l := range lines[:i+1] {
+			out.Write(l)
+		}
+		if !bytes.HasSuffix(line, []byte("\n")) {
+			out.WriteString("\n")
+		}
+		out.WriteString("\n")
+		out.Write(toc.Bytes())
+
+		rest := lines[i+1:]
+		// Keep exactly one blank line between the ToC and the following content
+		for len(rest) > 0 && len(bytes.TrimSpace(rest[0])) == 0 {
+			rest = rest[1:]
+		}
+		if len(rest) > 0 {
+			out.WriteString("\n")
+			for _, l := range rest {
+				out.Write(l)
+			}
+		}
+		return out.Bytes()
+	}
+
+	// No ATX H1 line found; leave the document untouched
+	return content
+}
+
+// collectToCHeadings walks the Markdown AST and returns every heading in
+// document order with its deduplicated GFM anchor.
+func collectToCHeadings(content []byte) []tocHeading {
+	doc := markdown.Parse(content, parser.New())
+
+	var headings []tocHeading
+	slugger := newGFMSlugger()
+	ast.WalkFunc(doc, func(node ast.Node, entering bool) ast.WalkStatus {
+		heading, ok := node.(*ast.Heading)
+		if !ok || !entering {
+			return ast.GoToNext
+		}
+		text := strings.TrimSpace(headingPlainText(heading))
+		headings = append(headings, tocHeading{
+			level:  heading.Level,
+			text:   text,
+			anchor: slugger.slug(text),
+		})
+		return ast.SkipChildren
+	})
+	return headings
+}
+
+// headingPlainText concatenates the literal text of all inline descendants,
+// dropping emphasis, link and code span markup.
+func headingPlainText(node ast.Node) string {
+	var sb strings.Builder
+	ast.WalkFunc(node, func(n ast.Node, entering bool) ast.WalkStatus {
+		if !entering {
+			return ast.GoToNext
+		}
+		switch leaf := n.(type) {
+		case *ast.Text:
+			sb.Write(leaf.Literal)
+		case *ast.Code:
+			sb.Write(leaf.Literal)
+		}
+		return ast.GoToNext
+	})
+	return sb.String()
+}
+
+// escapeLinkText escapes characters that would terminate Markdown link text.
+func escapeLinkText(text string) string {
+	text = strings.ReplaceAll(text, `\`, `\\`)
+	text = strings.ReplaceAll(text, "[", `\[`)
+	text = strings.ReplaceAll(text, "]", `\]`)
+	return text
+}
+
+// gfmSlugger produces GitHub-compatible heading anchors and tracks
+// previously issued slugs to deduplicate repeated headings.
+type gfmSlugger struct {
+	seen map[string]int
+}
+
+func newGFMSlugger() *gfmSlugger {
+	return &gfmSlugger{seen: make(map[string]int)}
+}
+
+func (s *gfmSlugger) slug(text string) string {
+	base := gfmSlug(text)
+	slug := base
+	count, taken := s.seen[base]
+	for taken {
+		count++
+		slug = fmt.Sprintf("%s-%d", base, count)
+		_, taken = s.seen[slug]
+	}
+	s.seen[base] = count
+	s.seen[slug] = 0
+	return slug
+}
+
+// gfmSlug converts heading text into a GitHub Flavored Markdown anchor.
+func gfmSlug(text string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case r == ' ':
+			sb.WriteRune('-')
+		case r == '-' || r == '_':
+			sb.WriteRune(r)
+		case unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.IsMark(r):
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
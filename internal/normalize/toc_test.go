@@ -0,0 +1,140 @@
+package normalize_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/assets"
+	"github.com/rohmanhakim/docs-crawler/internal/normalize"
+	"github.com/rohmanhakim/docs-crawler/pkg/hashutil"
+)
+
+func normalizeWithToC(t *testing.T, content string, generateToC bool) normalize.NormalizedMarkdownDoc {
+	t.Helper()
+	constraint := normalize.NewMarkdownConstraint(&metadataSinkMock{})
+	fetchURL, _ := url.Parse("https://docs.example.com/guide/toc")
+	param := normalize.NewNormalizeParam(
+		"v1.0.0",
+		time.Date(2026, 2, 12, 10, 15, 0, 0, time.UTC),
+		hashutil.HashAlgoSHA256,
+		1,
+		[]string{},
+	).WithGenerateToC(generateToC)
+
+	result, err := constraint.Normalize(*fetchURL, assets.NewAssetfulMarkdownDoc([]byte(content), nil, nil, nil), param)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	return result
+}
+
+func TestNormalize_ToC_LinksMatchGFMAnchors(t *testing.T) {
+	content := "# Guide\n\n" +
+		"Intro.\n\n" +
+		"## Getting Started\n\nText.\n\n" +
+		"### Install the `cli` Tool\n\nText.\n\n" +
+		"## What's New?\n\nText.\n\n" +
+		"## C++ & Go\n\nText.\n\n" +
+		"## **Bold** and [Linked](https://example.com) Text\n\nText.\n\n" +
+		"## snake_case-and-dashes\n\nText.\n"
+
+	result := normalizeWithToC(t, content, true)
+
+	expected := "# Guide\n\n" +
+		"- [Getting Started](#getting-started)\n" +
+		"  - [Install the cli Tool](#install-the-cli-tool)\n" +
+		"- [What's New?](#whats-new)\n" +
+		"- [C++ & Go](#c--go)\n" +
+		"- [Bold and Linked Text](#bold-and-linked-text)\n" +
+		"- [snake_case-and-dashes](#snake_case-and-dashes)\n" +
+		"\n" +
+		"Intro.\n"
+
+	if !strings.HasPrefix(string(result.Content()), expected) {
+		t.Errorf("unexpected ToC.\nexpected prefix:\n%s\ngot:\n%s", expected, string(result.Content()))
+	}
+}
+
+func TestNormalize_ToC_DuplicateHeadingsGetSuffixes(t *testing.T) {
+	content := "# Examples\n\n" +
+		"Intro.\n\n" +
+		"## Example\n\nOne.\n\n" +
+		"## Example\n\nTwo.\n\n" +
+		"## Example\n\nThree.\n\n" +
+		"## Examples\n\nFour.\n"
+
+	result := normalizeWithToC(t, content, true)
+
+	// The H1 "Examples" claims #examples, so the H2 "Examples" becomes #examples-1
+	expected := "- [Example](#example)\n" +
+		"- [Example](#example-1)\n" +
+		"- [Example](#example-2)\n" +
+		"- [Examples](#examples-1)\n"
+
+	if !strings.Contains(string(result.Content()), expected) {
+		t.Errorf("expected ToC with deduplicated anchors:\n%s\ngot:\n%s", expected, string(result.Content()))
+	}
+}
+
+func TestNormalize_ToC_SkipsFencedCodeAboveTitle(t *testing.T) {
+	content := "```sh\n# install the tool\n```\n\n" +
+		"# Guide\n\n" +
+		"Intro.\n\n" +
+		"## Section\n\nText.\n"
+
+	result := normalizeWithToC(t, content, true)
+
+	expected := "```sh\n# install the tool\n```\n\n" +
+		"# Guide\n\n" +
+		"- [Section](#section)\n" +
+		"\n" +
+		"Intro.\n"
+
+	if !strings.HasPrefix(string(result.Content()), expected) {
+		t.Errorf("expected the ToC after the title, not the fenced comment.\nexpected prefix:\n%s\ngot:\n%s", expected, string(result.Content()))
+	}
+}
+
+func TestNormalize_ToC_DisabledByDefault(t *testing.T) {
+	content := "# Guide\n\nIntro.\n\n## Section\n\nText.\n"
+
+	result := normalizeWithToC(t, content, false)
+
+	if string(result.Content()) != content {
+		t.Errorf("expected content unchanged when ToC disabled, got:\n%s", string(result.Content()))
+	}
+}
+
+func TestNormalize_ToC_NoSubheadings(t *testing.T) {
+	content := "# Guide\n\nOnly an intro.\n"
+
+	result := normalizeWithToC(t, content, true)
+
+	if string(result.Content()) != content {
+		t.Errorf("expected content unchanged without subheadings, got:\n%s", string(result.Content()))
+	}
+}
+
+func TestNormalize_ToC_Deterministic(t *testing.T) {
+	content := "# Guide\n\nIntro.\n\n## A\n\nText.\n\n### B\n\nText.\n\n## A\n\nText.\n"
+
+	first := normalizeWithToC(t, content, true)
+	second := normalizeWithToC(t, content, true)
+	without := normalizeWithToC(t, content, false)
+
+	if string(first.Content()) != string(second.Content()) {
+		t.Error("expected identical output across runs")
+	}
+	if first.Frontmatter().ContentHash() != second.Frontmatter().ContentHash() {
+		t.Error("expected identical content hash across runs")
+	}
+	// content_hash covers the final content including the ToC
+	if first.Frontmatter().ContentHash() == without.Frontmatter().ContentHash() {
+		t.Error("expected content hash to change when ToC is inserted")
+	}
+	if first.Frontmatter().Title() != "Guide" {
+		t.Errorf("expected title 'Guide', got %q", first.Frontmatter().Title())
+	}
+}
@@ -30,9 +30,11 @@ It does not make decisions about URL permissions.
 
 // RobotsFetcher fetches and parses robots.txt files from hosts.
 type RobotsFetcher struct {
-	httpClient *http.Client
-	userAgent  string
-	cache      cache.Cache
+	httpClient     *http.Client
+	userAgent      string
+	hostUserAgents map[string]string
+	overrides      map[string]string
+	cache          cache.Cache
 }
 
 // RobotsFetchResult represents the result of fetching a robots.txt file.
@@ -117,6 +119,17 @@ func deserializeResult(data string) (RobotsFetchResult, error) {
 // The scheme (http/https) must be provided to construct the URL.
 // If a cache is configured, it will check the cache first and store results after fetching.
 func (f *RobotsFetcher) Fetch(ctx context.Context, scheme, hostname string) (RobotsFetchResult, *RobotsError) {
+	// Inline overrides replace the robots.txt of their host entirely
+	if content, ok := f.overrides[strings.ToLower(hostname)]; ok {
+		return RobotsFetchResult{
+			Response:  ParseRobotsTxt(content, hostname),
+			FetchedAt: time.Now(),
+			SourceURL: fmt.Sprintf("%s://%s/robots.txt", scheme, hostname),
+			// Not a network fetch, so no fetch event is recorded
+			FromCache: true,
+		}, nil
+	}
+
 	// Check cache first if available
 	if f.cache != nil {
 		key := cacheKey(scheme, hostname)
@@ -140,7 +153,7 @@ func (f *RobotsFetcher) Fetch(ctx context.Context, scheme, hostname string) (Rob
 	}
 
 	// Set browser-like headers
-	req.Header.Set("User-Agent", f.userAgent)
+	req.Header.Set("User-Agent", f.UserAgentFor(hostname))
 	req.Header.Set("Accept", "text/plain,text/html,*/*")
 	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
 
@@ -377,6 +390,29 @@ func (f *RobotsFetcher) UserAgent() string {
 	return f.userAgent
 }
 
+// SetHostUserAgents sets per-host user agent overrides, keyed by URL host
+// (including any port). Hosts not listed use the default user agent.
+func (f *RobotsFetcher) SetHostUserAgents(agents map[string]string) {
+	f.hostUserAgents = agents
+}
+
+// SetOverrides sets inline robots.txt contents, keyed by URL host (including
+// any port). Listed hosts are never fetched and use their override instead.
+func (f *RobotsFetcher) SetOverrides(overrides map[string]string) {
+	f.overrides = make(map[string]string, len(overrides))
+	for host, content := range overrides {
+		f.overrides[strings.ToLower(host)] = content
+	}
+}
+
+// UserAgentFor returns the user agent presented to hostname.
+func (f *RobotsFetcher) UserAgentFor(hostname string) string {
+	if agent, ok := f.hostUserAgents[hostname]; ok && agent != "" {
+		return agent
+	}
+	return f.userAgent
+}
+
 func (f *RobotsFetcher) HttpClient() *http.Client {
 	return f.httpClient
 }
@@ -26,6 +26,7 @@ func (m *mockMetadataSink) RecordArtifact(record metadata.ArtifactRecord)    {}
 func (m *mockMetadataSink) RecordPipelineStage(event metadata.PipelineEvent) {}
 func (m *mockMetadataSink) RecordSkip(event metadata.SkipEvent)              {}
 func (m *mockMetadataSink) RecordError(record metadata.ErrorRecord)          {}
+func (m *mockMetadataSink) RecordTuning(event metadata.TuningEvent)          {}
 
 func TestNewRobotsFetcher(t *testing.T) {
 	sink := &mockMetadataSink{}
@@ -352,6 +353,30 @@ Disallow: /no-bing/`,
 				},
 			},
 		},
+		{
+			name: "consecutive user agents share one group",
+			content: `User-agent: bot1
+User-agent: bot2
+Disallow: /x
+
+User-agent: *
+Allow: /`,
+			host: "example.com",
+			expected: robots.RobotsResponse{
+				Host:     "example.com",
+				Sitemaps: []string{},
+				UserAgents: []robots.UserAgentGroup{
+					{
+						UserAgents: []string{"bot1", "bot2"},
+						Disallows:  []robots.PathRule{{Path: "/x"}},
+					},
+					{
+						UserAgents: []string{"*"},
+						Allows:     []robots.PathRule{{Path: "/"}},
+					},
+				},
+			},
+		},
 		{
 			name: "with sitemap",
 			content: `User-agent: *
@@ -752,3 +777,4 @@ func (m *countingMetadataSink) RecordArtifact(record metadata.ArtifactRecord)
 func (m *countingMetadataSink) RecordPipelineStage(event metadata.PipelineEvent) {}
 func (m *countingMetadataSink) RecordSkip(event metadata.SkipEvent)              {}
 func (m *countingMetadataSink) RecordError(record metadata.ErrorRecord)          {}
+func (m *countingMetadataSink) RecordTuning(event metadata.TuningEvent)          {}
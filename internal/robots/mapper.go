@@ -1,6 +1,7 @@
 package robots
 
 import (
+	"math"
 	"strings"
 	"time"
 )
@@ -60,42 +61,72 @@ func MapResponseToRuleSet(response RobotsResponse, targetUserAgent string, fetch
 // 1. Exact matches take precedence over wildcard matches
 // 2. More specific user-agent strings take precedence over less specific ones
 // 3. The wildcard (*) matches all user agents
+// 4. Groups matching equally well are combined into one group (RFC 9309
+// 2.2.1), e.g. when the crawler is named in two separate groups
+//
+// A group matches through any of its user agents, so a group listing several
+// User-agent lines applies to each of them.
 func findBestMatchingGroup(groups []UserAgentGroup, targetUserAgent string) *UserAgentGroup {
-	var bestMatch *UserAgentGroup
 	targetLower := strings.ToLower(targetUserAgent)
-	bestMatchLength := 0
+	var matched []*UserAgentGroup
+	bestScore := 0
 
 	for i := range groups {
-		group := &groups[i]
-
-		for _, ua := range group.UserAgents {
-			uaLower := strings.ToLower(ua)
-
-			// Check for exact match (case-insensitive)
-			if uaLower == targetLower {
-				return group // Exact match is the best possible
-			}
+		score := groupMatchScore(groups[i], targetLower)
+		if score == 0 || score < bestScore {
+			continue
+		}
+		if score > bestScore {
+			bestScore = score
+			matched = matched[:0]
+		}
+		matched = append(matched, &groups[i])
+	}
 
-			// Check for wildcard
-			if ua == "*" {
-				if bestMatch == nil {
-					bestMatch = group
-				}
-				continue
-			}
+	switch len(matched) {
+	case 0:
+		return nil
+	case 1:
+		return matched[0]
+	default:
+		return mergeGroups(matched)
+	}
+}
 
-			// Check if this user agent string matches the beginning of the target
-			// e.g., "Googlebot" matches "Googlebot-Image" (case-insensitive)
-			if strings.HasPrefix(targetLower, uaLower) {
-				if len(uaLower) > bestMatchLength {
-					bestMatch = group
-					bestMatchLength = len(uaLower)
-				}
-			}
+// groupMatchScore rates how specifically group names the lower-cased target
+// user agent: 0 for no match, 1 for the wildcard, 1 + the token length for a
+// prefix match (e.g., "Googlebot" matches "Googlebot-Image") and
+// math.MaxInt for an exact match.
+func groupMatchScore(group UserAgentGroup, targetLower string) int {
+	score := 0
+	for _, ua := range group.UserAgents {
+		uaLower := strings.ToLower(ua)
+		switch {
+		case uaLower == targetLower:
+			return math.MaxInt
+		case ua == "*":
+			score = max(score, 1)
+		case uaLower != "" && strings.HasPrefix(targetLower, uaLower):
+			score = max(score, 1+len(uaLower))
 		}
 	}
+	return score
+}
 
-	return bestMatch
+// mergeGroups combines the rules of groups, in order, into a new group.
+// The longest crawl delay wins.
+func mergeGroups(groups []*UserAgentGroup) *UserAgentGroup {
+	merged := &UserAgentGroup{
+		Allows:    []PathRule{},
+		Disallows: []PathRule{},
+	}
+	for _, group := range groups {
+		merged.UserAgents = append(merged.UserAgents, group.UserAgents...)
+		merged.Allows = append(merged.Allows, group.Allows...)
+		merged.Disallows = append(merged.Disallows, group.Disallows...)
+		merged.CrawlDelay = max(merged.CrawlDelay, group.CrawlDelay)
+	}
+	return merged
 }
 
 // normalizePath ensures the path starts with "/" and handles special cases.
@@ -452,6 +452,50 @@ func TestMapResponseToRuleSet_MultipleUserAgentsInGroup(t *testing.T) {
 	}
 }
 
+func TestMapResponseToRuleSet_MergesGroupsNamingTheSameAgent(t *testing.T) {
+	response := RobotsResponse{
+		Host: "example.com",
+		UserAgents: []UserAgentGroup{
+			{
+				UserAgents: []string{"docs-crawler"},
+				Disallows:  []PathRule{{Path: "/drafts/"}},
+				CrawlDelay: time.Second,
+			},
+			{
+				UserAgents: []string{"*"},
+				Disallows:  []PathRule{{Path: "/private/"}},
+			},
+			{
+				UserAgents: []string{"other-bot", "Docs-Crawler"},
+				Disallows:  []PathRule{{Path: "/internal/"}},
+				Allows:     []PathRule{{Path: "/internal/public/"}},
+				CrawlDelay: 2 * time.Second,
+			},
+		},
+	}
+
+	rs := MapResponseToRuleSet(response, "docs-crawler", time.Now())
+
+	var disallows []string
+	for _, rule := range rs.DisallowRules() {
+		disallows = append(disallows, rule.Prefix())
+	}
+	if strings.Join(disallows, ",") != "/drafts/,/internal/" {
+		t.Errorf("expected the rules of both docs-crawler groups and not the wildcard group, got %v", disallows)
+	}
+	if len(rs.AllowRules()) != 1 {
+		t.Errorf("expected 1 allow rule, got %d", len(rs.AllowRules()))
+	}
+	if rs.CrawlDelay() != 2*time.Second {
+		t.Errorf("expected the longest crawl delay 2s, got %v", rs.CrawlDelay())
+	}
+
+	// The groups in the response are left untouched
+	if len(response.UserAgents[0].Disallows) != 1 {
+		t.Errorf("expected the first group to keep 1 disallow rule, got %d", len(response.UserAgents[0].Disallows))
+	}
+}
+
 func TestMapResponseToRuleSet_UserAgentPrefixMatching(t *testing.T) {
 	fetchTime := time.Now()
 	response := RobotsResponse{
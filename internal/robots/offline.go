@@ -0,0 +1,18 @@
+package robots
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/pkg/debug"
+)
+
+// DecideFromContent evaluates targetURL against robots.txt content that was
+// obtained out of band, without any network access or metadata recording.
+// Empty content behaves like a missing robots.txt and allows everything.
+func DecideFromContent(content, userAgent string, targetURL url.URL) (Decision, *RobotsError) {
+	response := ParseRobotsTxt(content, targetURL.Host)
+	rs := MapResponseToRuleSet(response, userAgent, time.Time{})
+	r := CachedRobot{debugLogger: debug.NewNoOpLogger()}
+	return r.decide(rs, targetURL)
+}
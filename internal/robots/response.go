@@ -1,9 +1,6 @@
 package robots
 
-import (
-	"strings"
-	"time"
-)
+import "time"
 
 // RobotsResponse represents the parsed content of a robots.txt file.
 // This struct is used for parsing the fetch response and should not be
@@ -55,42 +52,8 @@ func (r RobotsResponse) IsEmpty() bool {
 
 // GetGroupForUserAgent returns the most specific user agent group for the given user agent.
 // Returns nil if no matching group is found.
-// Matching is case-insensitive as per robots.txt spec.
+// Matching is case-insensitive as per robots.txt spec. When several groups
+// match equally well, their rules are merged into one group.
 func (r RobotsResponse) GetGroupForUserAgent(userAgent string) *UserAgentGroup {
-	userAgentLower := strings.ToLower(userAgent)
-
-	// Look for exact match first (case-insensitive)
-	for i, group := range r.UserAgents {
-		for _, ua := range group.UserAgents {
-			if strings.ToLower(ua) == userAgentLower {
-				return &r.UserAgents[i]
-			}
-		}
-	}
-
-	// Look for prefix match (e.g., "Googlebot" matches "Googlebot-Image")
-	var bestMatch *UserAgentGroup
-	bestMatchLength := 0
-
-	for i, group := range r.UserAgents {
-		for _, ua := range group.UserAgents {
-			uaLower := strings.ToLower(ua)
-
-			if ua == "*" {
-				if bestMatch == nil {
-					bestMatch = &r.UserAgents[i]
-				}
-				continue
-			}
-
-			if strings.HasPrefix(userAgentLower, uaLower) {
-				if len(uaLower) > bestMatchLength {
-					bestMatch = &r.UserAgents[i]
-					bestMatchLength = len(uaLower)
-				}
-			}
-		}
-	}
-
-	return bestMatch
+	return findBestMatchingGroup(r.UserAgents, userAgent)
 }
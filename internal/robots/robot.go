@@ -31,6 +31,8 @@ Split robots API into:
 // Robot handles robots.txt fetching and decision making for URL crawling permissions.
 type Robot interface {
 	Init(userAgent string, httpClient *http.Client)
+	SetHostUserAgents(agents map[string]string)
+	SetOverrides(overrides map[string]string)
 	Decide(targetURL url.URL) (Decision, *RobotsError)
 }
 
@@ -72,6 +74,20 @@ func (r *CachedRobot) InitWithCache(userAgent string, httpClient *http.Client, c
 	r.userAgent = userAgent
 }
 
+// SetHostUserAgents sets per-host user agent overrides used both when
+// fetching robots.txt and when selecting the matching rule group.
+// It must be called after Init or InitWithCache.
+func (r *CachedRobot) SetHostUserAgents(agents map[string]string) {
+	r.fetcher.SetHostUserAgents(agents)
+}
+
+// SetOverrides sets inline robots.txt contents per host, used instead of
+// fetching robots.txt from the listed hosts.
+// It must be called after Init or InitWithCache.
+func (r *CachedRobot) SetOverrides(overrides map[string]string) {
+	r.fetcher.SetOverrides(overrides)
+}
+
 // SetDebugLogger sets the debug logger for the robot.
 // This is optional and defaults to NoOpLogger.
 // If logger is nil, NoOpLogger is used as a safe default.
@@ -105,20 +121,7 @@ func (r *CachedRobot) Decide(targetURL url.URL) (Decision, *RobotsError) {
 		return Decision{}, err
 	}
 
-	// Emit a fetch event only for actual network fetches, not cache hits.
-	// This prevents inflating fetch metrics when multiple URLs share the same host.
-	if !fetchResult.FromCache {
-		r.metadataSink.RecordFetch(metadata.NewFetchEvent(
-			fetchResult.FetchedAt,
-			fetchResult.SourceURL,
-			fetchResult.HTTPStatus,
-			fetchResult.Duration,
-			fetchResult.ContentType,
-			0, // retryCount — not tracked for robots.txt fetches
-			0, // crawlDepth — not applicable for robots.txt fetches
-			metadata.KindRobots,
-		))
-	}
+	r.recordFetch(fetchResult)
 
 	// Log robots fetch if debug enabled
 	if r.debugLogger.Enabled() {
@@ -130,7 +133,7 @@ func (r *CachedRobot) Decide(targetURL url.URL) (Decision, *RobotsError) {
 	}
 
 	// Map the fetch result to a ruleSet for decision making
-	rs := MapResponseToRuleSet(fetchResult.Response, r.userAgent, fetchResult.FetchedAt)
+	rs := MapResponseToRuleSet(fetchResult.Response, r.fetcher.UserAgentFor(targetURL.Host), fetchResult.FetchedAt)
 
 	// Log parse rules if debug enabled
 	if r.debugLogger.Enabled() {
@@ -170,6 +173,25 @@ func (r *CachedRobot) Decide(targetURL url.URL) (Decision, *RobotsError) {
 	return decision, nil
 }
 
+// recordFetch emits a fetch event only for actual network fetches, not cache
+// hits. This prevents inflating fetch metrics when multiple URLs share the
+// same host.
+func (r *CachedRobot) recordFetch(fetchResult RobotsFetchResult) {
+	if fetchResult.FromCache {
+		return
+	}
+	r.metadataSink.RecordFetch(metadata.NewFetchEvent(
+		fetchResult.FetchedAt,
+		fetchResult.SourceURL,
+		fetchResult.HTTPStatus,
+		fetchResult.Duration,
+		fetchResult.ContentType,
+		0, // retryCount — not tracked for robots.txt fetches
+		0, // crawlDepth — not applicable for robots.txt fetches
+		metadata.KindRobots,
+	))
+}
+
 // decide determines whether a URL is allowed based on the provided ruleSet.
 // This is the internal decision-making logic that works with ruleSet directly.
 // It implements the robots.txt matching algorithm according to the spec:
@@ -204,10 +226,15 @@ func (r *CachedRobot) decide(rs ruleSet, targetURL url.URL) (Decision, *RobotsEr
 		return decision, nil
 	}
 
-	path := targetURL.Path
+	// Rules are matched against the encoded path plus query string, so that
+	// a $-anchored rule like /*.pdf$ does not match /file.pdf?x=1
+	path := targetURL.EscapedPath()
 	if path == "" {
 		path = "/"
 	}
+	if targetURL.RawQuery != "" || targetURL.ForceQuery {
+		path += "?" + targetURL.RawQuery
+	}
 
 	// Find the best matching rule
 	// According to robots.txt spec:
@@ -228,9 +255,6 @@ func (r *CachedRobot) decide(rs ruleSet, targetURL url.URL) (Decision, *RobotsEr
 
 		// Allow rules have higher precedence than disallow of same length
 		priority := float64(length)
-		if matchType == exactMatch {
-			priority += 1000 // Boost exact matches
-		}
 
 		if !hasMatch || priority > bestMatch.priority {
 			bestMatch = matchRule{
@@ -261,9 +285,6 @@ func (r *CachedRobot) decide(rs ruleSet, targetURL url.URL) (Decision, *RobotsEr
 
 		// Disallow rules have lower precedence than allow of same length
 		priority := float64(length)
-		if matchType == exactMatch {
-			priority += 1000 // Boost exact matches
-		}
 		// Disallow gets a slight penalty compared to allow of same length
 		priority -= 0.5
 
@@ -365,88 +386,115 @@ func matchTypeString(mt matchType) string {
 
 // matchesRule checks if a path matches a rule pattern.
 // Returns the match type and the match length (for priority calculation).
-// The length represents how specific the match is (longer = more specific).
+//
+// Matching follows RFC 9309 / Google's robots.txt specification:
+//   - Rules are prefix matches unless they end with $, which anchors the
+//     rule to the end of the path (including any query string).
+//   - * matches any sequence of characters, including the empty sequence
+//     and "/", anywhere in the rule.
+//   - Both path and rule are percent-encoding normalized before matching,
+//     so /%7Euser matches /~user and a raw UTF-8 rule matches its encoded form.
+//
+// The length is the octet length of the normalized rule, which is what the
+// spec uses to pick the most specific (longest) matching rule.
 func matchesRule(path, pattern string) (matchType, int) {
-	// Handle exact match with $ suffix
-	if strings.HasSuffix(pattern, "$") {
-		patternWithoutSuffix := pattern[:len(pattern)-1]
-		if path == patternWithoutSuffix {
-			return exactMatch, len(patternWithoutSuffix)
-		}
-		// Check if pattern has wildcards that could match exactly
-		if matchesWildcard(path, patternWithoutSuffix) {
-			return exactMatch, len(patternWithoutSuffix)
-		}
-		return noMatch, 0
-	}
+	normalizedPath := normalizePercentEncoding(path)
+	normalizedPattern := normalizePercentEncoding(pattern)
 
-	// Handle wildcard patterns
-	if strings.Contains(pattern, "*") {
-		if matchesWildcard(path, pattern) {
-			// Calculate match length as the literal parts of the pattern
-			literalParts := strings.ReplaceAll(pattern, "*", "")
-			return prefixMatch, len(literalParts)
-		}
+	if !matchesWildcard(normalizedPath, normalizedPattern) {
 		return noMatch, 0
 	}
-
-	// Simple prefix match (most common case)
-	// According to robots.txt spec, paths match as prefixes by default
-	if strings.HasPrefix(path, pattern) {
-		// Check if this is an exact match
-		if path == pattern {
-			return exactMatch, len(pattern)
-		}
-		return prefixMatch, len(pattern)
+	if strings.HasSuffix(normalizedPattern, "$") {
+		return exactMatch, len(normalizedPattern)
 	}
-
-	return noMatch, 0
+	return prefixMatch, len(normalizedPattern)
 }
 
-// matchesWildcard checks if a path matches a pattern containing * wildcards.
-// The * wildcard matches any sequence of characters (including empty).
+// matchesWildcard checks if a path matches a robots.txt pattern.
+// The * wildcard matches any sequence of characters (including empty), and a
+// trailing $ anchors the pattern to the end of the path. Without a trailing $
+// the pattern only needs to match a prefix of the path.
+//
+// The implementation tracks every path position the pattern prefix can end at,
+// so it never backtracks and runs in O(len(path) * len(pattern)).
 func matchesWildcard(path, pattern string) bool {
-	// Split pattern by *
-	parts := strings.Split(pattern, "*")
-
-	// Empty pattern matches empty path
-	if len(parts) == 0 {
-		return path == ""
-	}
-
-	// Track current position in path
-	pos := 0
-
-	for i, part := range parts {
-		if part == "" {
-			// Consecutive * or * at start/end
-			continue
+	positions := []int{0}
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		if c == '$' && i == len(pattern)-1 {
+			return positions[len(positions)-1] == len(path)
 		}
-
-		if i == 0 {
-			// First part must match at the beginning
-			if !strings.HasPrefix(path, part) {
-				return false
+		if c == '*' {
+			// Any position from the earliest one to the end of the path is reachable
+			start := positions[0]
+			positions = positions[:0]
+			for p := start; p <= len(path); p++ {
+				positions = append(positions, p)
 			}
-			pos = len(part)
-		} else {
-			// Subsequent parts must appear somewhere after current position
-			idx := strings.Index(path[pos:], part)
-			if idx == -1 {
-				return false
+			continue
+		}
+		next := positions[:0]
+		for _, p := range positions {
+			if p < len(path) && path[p] == c {
+				next = append(next, p+1)
 			}
-			pos += idx + len(part)
 		}
+		if len(next) == 0 {
+			return false
+		}
+		positions = next
 	}
+	return true
+}
 
-	// If pattern doesn't end with *, the last part must be at the end
-	if !strings.HasSuffix(pattern, "*") && pos < len(path) {
-		// Check if the remaining path just contains the last part
-		lastPart := parts[len(parts)-1]
-		if lastPart != "" && !strings.HasSuffix(path, lastPart) {
-			return false
+// normalizePercentEncoding brings a path or rule into a canonical encoded form:
+// escapes of unreserved characters are decoded, remaining escapes use
+// upper-case hex, and non-ASCII or control bytes are percent-encoded.
+func normalizePercentEncoding(s string) string {
+	const hex = "0123456789ABCDEF"
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '%' && i+2 < len(s) && isHexDigit(s[i+1]) && isHexDigit(s[i+2]) {
+			decoded := unhex(s[i+1])<<4 | unhex(s[i+2])
+			if isUnreserved(decoded) {
+				sb.WriteByte(decoded)
+			} else {
+				sb.WriteByte('%')
+				sb.WriteByte(hex[decoded>>4])
+				sb.WriteByte(hex[decoded&0x0f])
+			}
+			i += 2
+			continue
+		}
+		if c <= 0x20 || c >= 0x7f {
+			sb.WriteByte('%')
+			sb.WriteByte(hex[c>>4])
+			sb.WriteByte(hex[c&0x0f])
+			continue
 		}
+		sb.WriteByte(c)
 	}
+	return sb.String()
+}
 
-	return true
+func isUnreserved(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') ||
+		c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+func isHexDigit(c byte) bool {
+	return ('0' <= c && c <= '9') || ('a' <= c && c <= 'f') || ('A' <= c && c <= 'F')
+}
+
+func unhex(c byte) byte {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0'
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10
+	default:
+		return c - 'A' + 10
+	}
 }
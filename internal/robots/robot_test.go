@@ -702,3 +702,265 @@ Allow: /`
 		t.Errorf("Expected still 1 FetchEvent after third Decide (cache hit), got %d", len(sink.FetchEvents))
 	}
 }
+
+// decideAll runs Decide for each path against a server serving robotsContent
+// and asserts the expected Allowed outcome.
+func decideAll(t *testing.T, robotsContent string, cases map[string]bool) {
+	t.Helper()
+	server := setupTestServer(robotsContent)
+	defer server.Close()
+
+	sink := &robotTestMetadataSink{}
+	robot := robots.NewCachedRobot(sink)
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	robot.Init("test-agent/1.0", httpClient)
+
+	for path, expected := range cases {
+		t.Run(path, func(t *testing.T) {
+			testURL, parseErr := url.Parse(server.URL + path)
+			if parseErr != nil {
+				t.Fatalf("invalid test path %q: %v", path, parseErr)
+			}
+			decision, err := robot.Decide(*testURL)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if decision.Allowed != expected {
+				t.Errorf("Expected Allowed=%v for path %s, got Allowed=%v", expected, path, decision.Allowed)
+			}
+		})
+	}
+}
+
+func TestRobot_Decide_MidPathWildcard(t *testing.T) {
+	decideAll(t, `User-agent: *
+Disallow: /a/*/b.html`, map[string]bool{
+		"/a/x/b.html":     false,
+		"/a/x/y/b.html":   false, // * spans path segments
+		"/a/x/b.html.bak": false, // rules are prefix matches without $
+		"/a/x/b.html?q=1": false,
+		"/a/b.html":       true, // the literal "/" on both sides of * must be present
+		"/a/x/c.html":     true,
+		"/b/x/b.html":     true,
+	})
+}
+
+func TestRobot_Decide_WildcardIsPrefixMatch(t *testing.T) {
+	decideAll(t, `User-agent: *
+Disallow: /a/*/b`, map[string]bool{
+		"/a/x/b":      false,
+		"/a/x/b.html": false,
+		"/a/x/bc/d":   false,
+		"/a/x/c":      true,
+	})
+}
+
+func TestRobot_Decide_EndAnchorIncludesQuery(t *testing.T) {
+	decideAll(t, `User-agent: *
+Disallow: /*.pdf$`, map[string]bool{
+		"/file.pdf":      false,
+		"/dir/file.pdf":  false,
+		"/a.pdf.pdf":     false,
+		"/file.pdf?x=1":  true, // $ anchors the end of path+query
+		"/file.pdf.html": true,
+		"/file.PDF":      true, // matching is case-sensitive
+	})
+}
+
+func TestRobot_Decide_QueryRules(t *testing.T) {
+	decideAll(t, `User-agent: *
+Disallow: /*?sessionid=`, map[string]bool{
+		"/page?sessionid=abc":     false,
+		"/dir/page?sessionid=abc": false,
+		"/page":                   true,
+		"/page?other=1":           true,
+	})
+}
+
+func TestRobot_Decide_PercentEncoding(t *testing.T) {
+	decideAll(t, `User-agent: *
+Disallow: /%7Euser/
+Disallow: /über/
+Disallow: /caf%c3%a9`, map[string]bool{
+		"/~user/page":       false, // %7E in rule is the unreserved "~"
+		"/%7Euser/page":     false,
+		"/%C3%BCber/page":   false, // raw UTF-8 rule matches encoded path
+		"/%c3%bcber/page":   false, // escape hex case is insignificant
+		"/über/page":        false,
+		"/café":             false, // lower-case escapes in rule match the encoded path
+		"/%E2%82%ACuro":     true,
+		"/user/page":        true,
+		"/uber/page":        true,
+		"/caf%C3%A9-recipe": false,
+	})
+}
+
+func TestRobot_Decide_LongestMatchPrecedence(t *testing.T) {
+	decideAll(t, `User-agent: *
+Disallow: /*.pdf$
+Allow: /docs/public/
+Disallow: /docs/
+Allow: /page
+Disallow: /page`, map[string]bool{
+		"/docs/public/x.pdf": true,  // Allow /docs/public/ (13) is longer than /*.pdf$ (7)
+		"/docs/x.pdf":        false, // Disallow /docs/ (6) and /*.pdf$ (7) both disallow
+		"/docs/public/x":     true,
+		"/docs/private/x":    false,
+		"/page":              true, // equal length: allow wins
+	})
+}
+
+func TestRobot_Decide_HostUserAgentOverride(t *testing.T) {
+	// robots.txt that only restricts SpecialBot
+	robotsContent := `User-agent: SpecialBot
+Disallow: /private/
+
+User-agent: *
+Allow: /`
+
+	var seenAgents []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seenAgents = append(seenAgents, r.Header.Get("User-Agent"))
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(robotsContent))
+	})
+	overridden := httptest.NewServer(handler)
+	defer overridden.Close()
+	other := httptest.NewServer(handler)
+	defer other.Close()
+
+	overriddenURL, _ := url.Parse(overridden.URL + "/private/page.html")
+	otherURL, _ := url.Parse(other.URL + "/private/page.html")
+
+	sink := &robotTestMetadataSink{}
+	robot := robots.NewCachedRobot(sink)
+	robot.Init("test-agent/1.0", &http.Client{Timeout: 30 * time.Second})
+	robot.SetHostUserAgents(map[string]string{overriddenURL.Host: "SpecialBot/2.0"})
+
+	// The overridden host is judged by the SpecialBot group
+	decision, err := robot.Decide(*overriddenURL)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if decision.Allowed {
+		t.Error("Expected /private/ to be disallowed for the overridden host")
+	}
+
+	// Other hosts keep the global user agent and its wildcard group
+	decision, err = robot.Decide(*otherURL)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !decision.Allowed {
+		t.Error("Expected /private/ to be allowed for a host without override")
+	}
+
+	expected := []string{"SpecialBot/2.0", "test-agent/1.0"}
+	if len(seenAgents) != 2 || seenAgents[0] != expected[0] || seenAgents[1] != expected[1] {
+		t.Errorf("Expected robots.txt requests with user agents %v, got %v", expected, seenAgents)
+	}
+}
+
+func TestRobot_Decide_RobotsOverride(t *testing.T) {
+	var requests []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Host+r.URL.Path)
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("User-agent: *\nAllow: /\n"))
+	})
+	staging := httptest.NewServer(handler)
+	defer staging.Close()
+	other := httptest.NewServer(handler)
+	defer other.Close()
+
+	stagingURL, _ := url.Parse(staging.URL + "/admin")
+	otherURL, _ := url.Parse(other.URL + "/admin")
+
+	sink := &robotTestMetadataSink{}
+	robot := robots.NewCachedRobot(sink)
+	robot.Init("test-agent/1.0", &http.Client{Timeout: 30 * time.Second})
+	robot.SetOverrides(map[string]string{stagingURL.Host: "User-agent: *\nDisallow: /admin\n"})
+
+	// The override disallows /admin without fetching robots.txt
+	decision, err := robot.Decide(*stagingURL)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if decision.Allowed {
+		t.Error("Expected /admin to be disallowed by the override")
+	}
+	if len(requests) != 0 {
+		t.Errorf("Expected no robots.txt request for the overridden host, got %v", requests)
+	}
+	if len(sink.FetchEvents) != 0 {
+		t.Errorf("Expected no fetch event for the overridden host, got %d", len(sink.FetchEvents))
+	}
+
+	// Hosts without override still fetch their robots.txt
+	decision, err = robot.Decide(*otherURL)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !decision.Allowed {
+		t.Error("Expected /admin to be allowed for a host without override")
+	}
+	expected := otherURL.Host + "/robots.txt"
+	if len(requests) != 1 || requests[0] != expected {
+		t.Errorf("Expected a single request for %s, got %v", expected, requests)
+	}
+}
+
+func TestDecideFromContent_GroupedUserAgents(t *testing.T) {
+	content := "User-agent: bot1\nUser-agent: bot2\nDisallow: /x\n\nUser-agent: *\nAllow: /\n"
+	target := url.URL{Scheme: "https", Host: "example.com", Path: "/x/page"}
+
+	for _, userAgent := range []string{"bot1", "bot2", "bot2/1.0"} {
+		decision, err := robots.DecideFromContent(content, userAgent, target)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", userAgent, err)
+		}
+		if decision.Allowed {
+			t.Errorf("%s: expected /x/page to be disallowed by the grouped block", userAgent)
+		}
+	}
+
+	decision, err := robots.DecideFromContent(content, "bot3", target)
+	if err != nil {
+		t.Fatalf("bot3: unexpected error: %v", err)
+	}
+	if !decision.Allowed {
+		t.Error("bot3: expected the wildcard group to allow /x/page")
+	}
+}
+
+func TestDecideFromContent(t *testing.T) {
+	content := "User-agent: *\nDisallow: /private/\nAllow: /private/public\n"
+
+	tests := []struct {
+		name    string
+		content string
+		path    string
+		allowed bool
+		reason  robots.DecisionReason
+	}{
+		{"disallowed path", content, "/private/secret", false, robots.DisallowedByRobots},
+		{"allow overrides", content, "/private/public", true, robots.AllowedByRobots},
+		{"no matching rule", content, "/docs", true, robots.NoMatchingRules},
+		{"empty content", "", "/private/secret", true, robots.EmptyRuleSet},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := url.URL{Scheme: "https", Host: "example.com", Path: tt.path}
+			decision, err := robots.DecideFromContent(tt.content, "docs-crawler", target)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if decision.Allowed != tt.allowed || decision.Reason != tt.reason {
+				t.Errorf("got allowed=%v reason=%s, want allowed=%v reason=%s",
+					decision.Allowed, decision.Reason, tt.allowed, tt.reason)
+			}
+		})
+	}
+}
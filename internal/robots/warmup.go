@@ -0,0 +1,91 @@
+package robots
+
+import (
+	"context"
+	"net/url"
+	"sync"
+
+	"github.com/rohmanhakim/docs-crawler/pkg/debug"
+)
+
+/*
+Warmup
+
+By default robots.txt is fetched the first time a URL of its host is
+decided, so a crawl spanning many hosts pays for each fetch serially as
+the hosts are met. Warmup fetches the robots.txt of a set of origins
+concurrently before the crawl starts and leaves them in the cache, so the
+first Decide for each host is a cache hit.
+
+Fetch events are recorded by the calling goroutine once all fetches are
+done. A failed fetch is not cached and is left for Decide to retry, which
+reports the error against the URL being decided.
+*/
+
+// warmupResult is the outcome of one warmup fetch.
+type warmupResult struct {
+	origin url.URL
+	result RobotsFetchResult
+	err    *RobotsError
+}
+
+// Warmup fetches robots.txt for every distinct scheme and host among
+// origins, using at most workers concurrent fetches, and caches the results.
+// It returns the number of robots.txt files fetched successfully.
+// It must be called after Init or InitWithCache.
+func (r *CachedRobot) Warmup(ctx context.Context, origins []url.URL, workers int) int {
+	var distinct []url.URL
+	seen := make(map[string]struct{})
+	for _, origin := range origins {
+		if origin.Host == "" {
+			continue
+		}
+		key := cacheKey(origin.Scheme, origin.Host)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		distinct = append(distinct, url.URL{Scheme: origin.Scheme, Host: origin.Host})
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	results := make([]warmupResult, len(distinct))
+	slots := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i, origin := range distinct {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			result, err := r.fetcher.Fetch(ctx, origin.Scheme, origin.Host)
+			results[i] = warmupResult{origin: origin, result: result, err: err}
+		}()
+	}
+	wg.Wait()
+
+	fetched := 0
+	for _, warmup := range results {
+		if warmup.err != nil {
+			if r.debugLogger.Enabled() {
+				r.debugLogger.LogStep(ctx, "robots", "warmup_failed", debug.FieldMap{
+					"host":  warmup.origin.Host,
+					"error": warmup.err.Error(),
+				})
+			}
+			continue
+		}
+		r.recordFetch(warmup.result)
+		fetched++
+	}
+
+	if r.debugLogger.Enabled() {
+		r.debugLogger.LogStep(ctx, "robots", "warmup", debug.FieldMap{
+			"hosts":   len(distinct),
+			"fetched": fetched,
+		})
+	}
+	return fetched
+}
@@ -0,0 +1,88 @@
+package robots_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/robots"
+)
+
+func TestRobot_Warmup_FetchesEachHostOnce(t *testing.T) {
+	var counts [3]atomic.Int32
+	var origins []url.URL
+	for i := range counts {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/robots.txt" {
+				counts[i].Add(1)
+				w.Header().Set("Content-Type", "text/plain")
+				w.Write([]byte("User-agent: *\nDisallow: /private/"))
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+		origin, _ := url.Parse(server.URL)
+		// Every host is listed twice to check that origins are deduplicated
+		origins = append(origins, *origin, *origin)
+	}
+
+	sink := &robotTestMetadataSink{}
+	robot := robots.NewCachedRobot(sink)
+	robot.Init("test-agent/1.0", &http.Client{Timeout: 30 * time.Second})
+
+	fetched := robot.Warmup(context.Background(), origins, 2)
+	if fetched != 3 {
+		t.Errorf("expected 3 robots.txt fetched, got %d", fetched)
+	}
+	if len(sink.FetchEvents) != 3 {
+		t.Errorf("expected 3 fetch events from the warmup, got %d", len(sink.FetchEvents))
+	}
+
+	// Decisions after the warmup are served from the cache
+	for i, origin := range origins {
+		target := origin
+		target.Path = "/private/page"
+		decision, err := robot.Decide(target)
+		if err != nil {
+			t.Fatalf("origin %d: expected no error, got: %v", i, err)
+		}
+		if decision.Allowed {
+			t.Errorf("origin %d: expected the cached rules to disallow %s", i, target.String())
+		}
+	}
+	for i := range counts {
+		if got := counts[i].Load(); got != 1 {
+			t.Errorf("host %d: expected robots.txt to be fetched once, got %d", i, got)
+		}
+	}
+	if len(sink.FetchEvents) != 3 {
+		t.Errorf("expected no fetch events after the warmup, got %d in total", len(sink.FetchEvents))
+	}
+}
+
+func TestRobot_Warmup_FailureIsLeftForDecide(t *testing.T) {
+	server := setupTestServerWithStatus(http.StatusServiceUnavailable, "")
+	defer server.Close()
+
+	sink := &robotTestMetadataSink{}
+	robot := robots.NewCachedRobot(sink)
+	robot.Init("test-agent/1.0", &http.Client{Timeout: 30 * time.Second})
+
+	origin, _ := url.Parse(server.URL)
+	if fetched := robot.Warmup(context.Background(), []url.URL{*origin}, 4); fetched != 0 {
+		t.Errorf("expected no robots.txt fetched, got %d", fetched)
+	}
+	if len(sink.ErrorRecords) != 0 {
+		t.Errorf("expected the warmup to record no errors, got %d", len(sink.ErrorRecords))
+	}
+
+	origin.Path = "/page"
+	if _, err := robot.Decide(*origin); err == nil {
+		t.Error("expected Decide to retry the fetch and report the server error")
+	}
+}
@@ -7,8 +7,31 @@ import (
 )
 
 type SanitizedHTMLDoc struct {
-	contentNode    *html.Node
-	discoveredUrls []url.URL
+	contentNode     *html.Node
+	discoveredUrls  []url.URL
+	discoveredLinks []DiscoveredLink
+}
+
+// DiscoveredLink is a hyperlink found in the sanitized document: its URL
+// exactly as authored and the text of its anchor.
+type DiscoveredLink struct {
+	url  url.URL
+	text string
+}
+
+// NewDiscoveredLink creates a DiscoveredLink.
+func NewDiscoveredLink(u url.URL, text string) DiscoveredLink {
+	return DiscoveredLink{url: u, text: text}
+}
+
+// URL returns the link's URL as authored, possibly relative.
+func (l DiscoveredLink) URL() url.URL {
+	return l.url
+}
+
+// Text returns the anchor text, with whitespace as found in the document.
+func (l DiscoveredLink) Text() string {
+	return l.text
 }
 
 func (s *SanitizedHTMLDoc) GetContentNode() *html.Node {
@@ -19,12 +42,24 @@ func (s *SanitizedHTMLDoc) GetDiscoveredURLs() []url.URL {
 	return s.discoveredUrls
 }
 
+// GetDiscoveredLinks returns the discovered links with their anchor text,
+// in the same order as GetDiscoveredURLs.
+func (s *SanitizedHTMLDoc) GetDiscoveredLinks() []DiscoveredLink {
+	return s.discoveredLinks
+}
+
 // NewSanitizedHTMLDoc creates a SanitizedHTMLDoc for testing purposes.
 // The fields remain private to maintain immutability.
+// The discovered links carry no anchor text.
 func NewSanitizedHTMLDoc(contentNode *html.Node, discoveredUrls []url.URL) SanitizedHTMLDoc {
+	links := make([]DiscoveredLink, 0, len(discoveredUrls))
+	for _, u := range discoveredUrls {
+		links = append(links, NewDiscoveredLink(u, ""))
+	}
 	return SanitizedHTMLDoc{
-		contentNode:    contentNode,
-		discoveredUrls: discoveredUrls,
+		contentNode:     contentNode,
+		discoveredUrls:  discoveredUrls,
+		discoveredLinks: links,
 	}
 }
 
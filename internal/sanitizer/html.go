@@ -209,7 +209,7 @@ func (h *HtmlSanitizer) sanitize(doc *html.Node) (SanitizedHTMLDoc, *Sanitizatio
 
 	// Step 6: Extract URLs from the document
 	// Extracts hyperlinks exactly as authored, preserving relative URLs
-	discoveredUrls, urlStats := extractUrlWithStats(cleanedDoc)
+	discoveredLinks, urlStats := extractUrlWithStats(cleanedDoc)
 	if h.debugLogger.Enabled() {
 		h.debugLogger.LogStep(context.TODO(), "sanitizer", "extract_urls", debug.FieldMap{
 			"urls_found":       urlStats.found,
@@ -218,9 +218,15 @@ func (h *HtmlSanitizer) sanitize(doc *html.Node) (SanitizedHTMLDoc, *Sanitizatio
 		})
 	}
 
+	discoveredUrls := make([]url.URL, 0, len(discoveredLinks))
+	for _, link := range discoveredLinks {
+		discoveredUrls = append(discoveredUrls, link.URL())
+	}
+
 	return SanitizedHTMLDoc{
-		contentNode:    cleanedDoc,
-		discoveredUrls: discoveredUrls,
+		contentNode:     cleanedDoc,
+		discoveredUrls:  discoveredUrls,
+		discoveredLinks: discoveredLinks,
 	}, nil
 }
 
@@ -436,12 +442,14 @@ type urlStats struct {
 	skippedInvalid  int
 }
 
-// extractUrlWithStats is like extractUrl but also returns stats.
-func extractUrlWithStats(doc *html.Node) ([]url.URL, urlStats) {
+// extractUrlWithStats is like extractUrl but also returns stats, and
+// returns each URL with its anchor text. A URL linked more than once keeps
+// the text of its first anchor.
+func extractUrlWithStats(doc *html.Node) ([]DiscoveredLink, urlStats) {
 	stats := urlStats{}
 
 	if doc == nil {
-		return []url.URL{}, stats
+		return []DiscoveredLink{}, stats
 	}
 
 	// Use goquery as convenience wrapper
@@ -449,7 +457,7 @@ func extractUrlWithStats(doc *html.Node) ([]url.URL, urlStats) {
 
 	// Track seen URLs for deduplication
 	seen := make(map[string]bool)
-	var urls []url.URL
+	var links []DiscoveredLink
 
 	// Find all anchor elements with href attributes
 	docQuery.Find("a[href]").Each(func(i int, s *goquery.Selection) {
@@ -489,9 +497,9 @@ func extractUrlWithStats(doc *html.Node) ([]url.URL, urlStats) {
 		}
 		seen[href] = true
 
-		urls = append(urls, *parsedURL)
+		links = append(links, NewDiscoveredLink(*parsedURL, s.Text()))
 		stats.found++
 	})
 
-	return urls, stats
+	return links, stats
 }
@@ -418,6 +418,35 @@ func TestSanitize_URLExtraction(t *testing.T) {
 	assert.Equal(t, 5, relativeCount, "Should have 5 relative URLs (including the deduplicated duplicate)")
 }
 
+// TestSanitize_DiscoveredLinksCarryAnchorText verifies that each discovered
+// link carries the text of its anchor, in the order of GetDiscoveredURLs,
+// and that a URL linked twice keeps the text of its first anchor.
+func TestSanitize_DiscoveredLinksCarryAnchorText(t *testing.T) {
+	mockSink := &mockMetadataSink{}
+	s := sanitizer.NewHTMLSanitizer(mockSink)
+
+	doc, err := html.Parse(strings.NewReader(`<html><body><main>
+<h1>Guide</h1>
+<p>Read the <a href="/docs/install">installation <em>guide</em></a> first.</p>
+<p><a href="/docs/guide.pdf">Download PDF</a></p>
+<p>Or see the <a href="/docs/install">install page</a> again.</p>
+</main></body></html>`))
+	require.NoError(t, err)
+
+	result, sanitizationErr := s.Sanitize(doc)
+	require.NoError(t, sanitizationErr)
+
+	links := result.GetDiscoveredLinks()
+	require.Len(t, links, 2)
+	urls := result.GetDiscoveredURLs()
+	for i, link := range links {
+		linkURL := link.URL()
+		assert.Equal(t, urls[i].String(), linkURL.String())
+	}
+	assert.Equal(t, "installation guide", links[0].Text())
+	assert.Equal(t, "Download PDF", links[1].Text())
+}
+
 // TestSanitize_Determinism verifies that the sanitizer produces identical output
 // when run multiple times on the same input HTML.
 //
@@ -0,0 +1,154 @@
+package sanitizer
+
+import (
+	"bytes"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+/*
+Renderings
+
+Besides feeding Markdown conversion, a sanitized document can be written as
+is, for consumers that want HTML or plain text instead of Markdown:
+
+  - RenderHTML serializes the content node.
+  - PlainText keeps the text only. Block elements are separated by a blank
+    line, <br> breaks the line, and whitespace elsewhere is collapsed to
+    single spaces. Preformatted text keeps its whitespace.
+*/
+
+// textBlockElements are the elements PlainText separates with a blank line.
+var textBlockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true,
+	"dd": true, "details": true, "div": true, "dl": true, "dt": true,
+	"figcaption": true, "figure": true, "footer": true, "h1": true,
+	"h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"header": true, "hr": true, "li": true, "main": true, "nav": true,
+	"ol": true, "p": true, "pre": true, "section": true, "summary": true,
+	"table": true, "tr": true, "ul": true,
+}
+
+// RenderHTML returns the serialized content node.
+func (s *SanitizedHTMLDoc) RenderHTML() ([]byte, error) {
+	if s.contentNode == nil {
+		return nil, nil
+	}
+	var buf bytes.Buffer
+	if err := html.Render(&buf, s.contentNode); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// PlainText returns the text of the content node, one paragraph per block
+// element, ending in a newline unless empty.
+func (s *SanitizedHTMLDoc) PlainText() []byte {
+	if s.contentNode == nil {
+		return nil
+	}
+	w := &textWriter{}
+	w.walk(s.contentNode, false)
+
+	var lines []string
+	for _, line := range strings.Split(w.buf.String(), "\n") {
+		lines = append(lines, strings.TrimRight(line, " "))
+	}
+	text := strings.Trim(strings.Join(lines, "\n"), "\n")
+	if text == "" {
+		return nil
+	}
+	return []byte(text + "\n")
+}
+
+// textWriter accumulates plain text, tracking pending breaks so runs of
+// blocks and whitespace collapse.
+type textWriter struct {
+	buf strings.Builder
+	// pendingBreaks is the number of newlines owed before the next text
+	pendingBreaks int
+	// pendingSpace is set when collapsed whitespace precedes the next text
+	pendingSpace bool
+}
+
+func (w *textWriter) walk(n *html.Node, preformatted bool) {
+	switch n.Type {
+	case html.TextNode:
+		if preformatted {
+			w.write(n.Data)
+			return
+		}
+		w.writeCollapsed(n.Data)
+		return
+	case html.ElementNode:
+		switch n.Data {
+		case "script", "style", "template":
+			return
+		case "br":
+			w.lineBreak(1)
+			return
+		}
+	}
+
+	block := n.Type == html.ElementNode && textBlockElements[n.Data]
+	if block {
+		w.lineBreak(2)
+	}
+	inPre := preformatted || (n.Type == html.ElementNode && n.Data == "pre")
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		w.walk(c, inPre)
+	}
+	if block {
+		w.lineBreak(2)
+	}
+}
+
+// lineBreak owes at least count newlines before the next text.
+func (w *textWriter) lineBreak(count int) {
+	if count > w.pendingBreaks {
+		w.pendingBreaks = count
+	}
+	w.pendingSpace = false
+}
+
+// writeCollapsed writes text with runs of whitespace collapsed to one space.
+func (w *textWriter) writeCollapsed(text string) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		if text != "" {
+			w.pendingSpace = true
+		}
+		return
+	}
+	if startsWithSpace(text) {
+		w.pendingSpace = true
+	}
+	w.write(strings.Join(fields, " "))
+	w.pendingSpace = endsWithSpace(text)
+}
+
+// write emits text after any owed breaks or space.
+func (w *textWriter) write(text string) {
+	if text == "" {
+		return
+	}
+	if w.buf.Len() > 0 {
+		if w.pendingBreaks > 0 {
+			w.buf.WriteString(strings.Repeat("\n", w.pendingBreaks))
+		} else if w.pendingSpace {
+			w.buf.WriteByte(' ')
+		}
+	}
+	w.pendingBreaks = 0
+	w.pendingSpace = false
+	w.buf.WriteString(text)
+}
+
+func startsWithSpace(text string) bool {
+	return strings.TrimLeft(text, " \t\r\n\f") != text
+}
+
+func endsWithSpace(text string) bool {
+	return strings.TrimRight(text, " \t\r\n\f") != text
+}
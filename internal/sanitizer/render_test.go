@@ -0,0 +1,65 @@
+package sanitizer
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+func TestSanitizedHTMLDoc_PlainText(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		expected string
+	}{
+		{
+			name:     "blocks separated by blank lines",
+			html:     `<h1>Title</h1><p>First   <em>para</em>graph.</p><p>Second.</p>`,
+			expected: "Title\n\nFirst paragraph.\n\nSecond.\n",
+		},
+		{
+			name:     "line breaks and lists",
+			html:     `<p>one<br>two</p><ul><li>a</li><li>b</li></ul>`,
+			expected: "one\ntwo\n\na\n\nb\n",
+		},
+		{
+			name:     "preformatted text keeps whitespace",
+			html:     `<p>Run:</p><pre><code>go  test\n  ./...</code></pre>`,
+			expected: "Run:\n\ngo  test\n  ./...\n",
+		},
+		{
+			name:     "empty",
+			html:     `<div>  </div>`,
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := html.Parse(strings.NewReader(strings.ReplaceAll(tt.html, `\n`, "\n")))
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			doc := NewSanitizedHTMLDoc(node, nil)
+			if got := string(doc.PlainText()); got != tt.expected {
+				t.Errorf("PlainText() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSanitizedHTMLDoc_RenderHTML(t *testing.T) {
+	node, err := html.Parse(strings.NewReader(`<p>Hi <b>there</b></p>`))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	doc := NewSanitizedHTMLDoc(node, nil)
+	rendered, err := doc.RenderHTML()
+	if err != nil {
+		t.Fatalf("RenderHTML: %v", err)
+	}
+	if !strings.Contains(string(rendered), "<p>Hi <b>there</b></p>") {
+		t.Errorf("RenderHTML() = %q", rendered)
+	}
+}
@@ -0,0 +1,87 @@
+package scheduler
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/extractor"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/frontier"
+	"github.com/rohmanhakim/docs-crawler/pkg/failure"
+	"golang.org/x/net/html"
+)
+
+/*
+Separate Article Outputs
+
+With config.MultiArticleMode "separateOutputs", the extractor reports each
+article of a page with several of them (see extractor.MultiArticleMode).
+The rest of the pipeline, from sanitization to writing, then runs once per
+article, and each is written as a page of its own at the page URL with a
+fragment naming the article: its id attribute, or "article-<n>" counting
+from 1 when it has none. The fragment is kept in the canonical URL, so
+every article gets its own document and file.
+
+The articles are one page for the crawl: the page is fetched, counted and
+retried once. Each article is otherwise handled like a page: it fails,
+is deduplicated or written on its own.
+*/
+
+// processArticles runs the pipeline from sanitization to writing for each
+// article of extractionResult. The first article's attempt is returned,
+// with the others as its parts; fetchErrors are the non-fatal errors of the
+// page before extraction.
+func (s *Scheduler) processArticles(
+	cfg config.Config,
+	seedScheme string,
+	token frontier.CrawlToken,
+	fetchResult fetcher.FetchResult,
+	extractionResult extractor.ExtractionResult,
+	fetchErrors int,
+) (pageAttempt, failure.ClassifiedError) {
+	var attempt pageAttempt
+	for i, article := range extractionResult.ArticleNodes {
+		articleResult := extractionResult
+		articleResult.ContentNode = article
+		articleResult.ArticleNodes = nil
+		// Structured sections were detected in the first article only
+		if i > 0 {
+			articleResult.StructuredSections = nil
+		}
+
+		part, err := s.processContent(cfg, seedScheme, token, fetchResult, articleResult, articleURL(fetchResult.URL(), article, i))
+		if err != nil {
+			return pageAttempt{}, err
+		}
+		if i == 0 {
+			attempt = part
+			continue
+		}
+		attempt.parts = append(attempt.parts, part)
+	}
+	attempt.errors += fetchErrors
+	return attempt, nil
+}
+
+// keepArticleFragment returns canonical with the fragment of pageURL, which
+// only article URLs carry, the way the normalizer names an article's
+// document. It keeps the articles of a page apart wherever written pages
+// are keyed by canonical URL.
+func keepArticleFragment(canonical url.URL, pageURL url.URL) url.URL {
+	canonical.Fragment = pageURL.Fragment
+	return canonical
+}
+
+// articleURL returns the URL the article at index of a page at pageURL is
+// written under.
+func articleURL(pageURL url.URL, article *html.Node, index int) url.URL {
+	pageURL.Fragment = fmt.Sprintf("article-%d", index+1)
+	pageURL.RawFragment = ""
+	for _, attr := range article.Attr {
+		if attr.Key == "id" && attr.Val != "" {
+			pageURL.Fragment = attr.Val
+		}
+	}
+	return pageURL
+}
@@ -0,0 +1,60 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+)
+
+/*
+Authentication
+
+Some documentation sites sit behind a form login. An Authenticator runs once
+per crawl, during initialization, right after the HTTP client is created and
+before robots.txt or any page is fetched. It receives that client, which the
+robots fetcher, the page fetcher and the asset resolver all share. The client
+is given a cookie jar first, so a session cookie set during login is sent
+with every later request.
+
+An authenticator error aborts initialization: crawling without a session
+would only produce login pages.
+*/
+
+// Authenticator performs a login flow (form POST, redirects, ...) with client.
+type Authenticator func(ctx context.Context, client *http.Client) error
+
+// SetAuthenticator sets the login hook run once before crawling begins.
+// A nil authenticator disables authentication.
+func (s *Scheduler) SetAuthenticator(fn Authenticator) {
+	s.authenticator = fn
+}
+
+// authenticate runs the authenticator, if any, with the crawl's HTTP client.
+func (s *Scheduler) authenticate() error {
+	if s.authenticator == nil {
+		return nil
+	}
+	if s.httpClient.Jar == nil {
+		// cookiejar.New only fails on a bad PublicSuffixList, and none is given
+		jar, _ := cookiejar.New(nil)
+		s.httpClient.Jar = jar
+	}
+
+	if err := s.authenticator(s.ctx, s.httpClient); err != nil {
+		err = fmt.Errorf("authentication failed: %w", err)
+		s.metadataSink.RecordError(metadata.NewErrorRecord(
+			time.Now(),
+			"scheduler",
+			"authenticate",
+			metadata.CauseNetworkFailure,
+			err.Error(),
+			[]metadata.Attribute{},
+		))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package scheduler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/pkg/failure"
+)
+
+/*
+Auto-Tuning
+
+With config.AutoTune enabled, an AIMD controller adjusts how hard the crawl
+pushes a site based on the fetches it observes:
+- it starts conservatively, at the minimum concurrency and maximum delay
+- a 429 or 5xx response (from a page fetch or robots.txt) halves the
+  recommended concurrency and doubles the delay: multiplicative decrease
+- every autoTuneRecoveryWindow consecutive successful page fetches add one
+  to the recommended concurrency and take one step off the delay: additive
+  increase
+- other failures (timeouts, 404s, ...) say nothing about server load and
+  leave both the settings and the success streak alone
+
+Both values stay within the configured bounds. Only the delay takes
+effect: it is applied as the rate limiter's base delay, so a robots.txt
+crawl-delay or an active backoff still wins when it is longer. Pages are
+processed one at a time, so the concurrency is advisory. It is the number
+of in-flight requests the tuner would allow, reported as
+recommended_concurrency, and never limits the crawl. The settings are
+recorded as a metadata.TuningEvent at the start, on every throttle and on
+every recovery step.
+*/
+
+const (
+	// autoTuneRecoveryWindow is the number of consecutive successful fetches
+	// that earn one additive step.
+	autoTuneRecoveryWindow = 10
+	// autoTuneDelaySteps is the number of additive steps between the maximum
+	// and the minimum delay.
+	autoTuneDelaySteps = 10
+)
+
+// autoTuner is the AIMD controller behind config.AutoTune.
+type autoTuner struct {
+	bounds config.AutoTune
+	// recommendedConcurrency is advisory, see above; only delay is applied
+	recommendedConcurrency int
+	delay                  time.Duration
+	// consecutive successes since the last adjustment or throttle
+	streak int
+}
+
+// newAutoTuner creates a tuner at its most conservative settings.
+func newAutoTuner(bounds config.AutoTune) *autoTuner {
+	return &autoTuner{
+		bounds:                 bounds,
+		recommendedConcurrency: bounds.MinConcurrency(),
+		delay:                  bounds.MaxDelay(),
+	}
+}
+
+// delayStep is the additive delay decrement.
+func (t *autoTuner) delayStep() time.Duration {
+	return (t.bounds.MaxDelay() - t.bounds.MinDelay()) / autoTuneDelaySteps
+}
+
+// throttled backs off multiplicatively.
+func (t *autoTuner) throttled() {
+	t.streak = 0
+	t.recommendedConcurrency = max(t.recommendedConcurrency/2, t.bounds.MinConcurrency())
+	t.delay = min(max(t.delay*2, t.bounds.MinDelay()+t.delayStep()), t.bounds.MaxDelay())
+}
+
+// succeeded counts a success and steps up additively once the streak fills
+// the recovery window. It reports whether the settings changed.
+func (t *autoTuner) succeeded() bool {
+	t.streak++
+	if t.streak < autoTuneRecoveryWindow {
+		return false
+	}
+	t.streak = 0
+	recommended := min(t.recommendedConcurrency+1, t.bounds.MaxConcurrency())
+	delay := max(t.delay-t.delayStep(), t.bounds.MinDelay())
+	changed := recommended != t.recommendedConcurrency || delay != t.delay
+	t.recommendedConcurrency, t.delay = recommended, delay
+	return changed
+}
+
+// isThrottleError reports whether err is a 429 or 5xx fetch failure.
+func isThrottleError(err error) bool {
+	var fetchErr *fetcher.FetchError
+	if !errors.As(err, &fetchErr) {
+		return false
+	}
+	return fetchErr.Cause == fetcher.ErrCauseRequestTooMany || fetchErr.Cause == fetcher.ErrCauseRequest5xx
+}
+
+// initAutoTune starts the auto-tuner when cfg enables it and applies its
+// initial delay. It must run after the configured base delay is set.
+func (s *Scheduler) initAutoTune(cfg config.Config) {
+	s.autoTuner = nil
+	if !cfg.AutoTune().Enabled() {
+		return
+	}
+	s.autoTuner = newAutoTuner(cfg.AutoTune())
+	s.applyTuning(metadata.TuningReasonInitial)
+}
+
+// observeFetch feeds the outcome of a page fetch to the auto-tuner.
+func (s *Scheduler) observeFetch(err failure.ClassifiedError) {
+	if s.autoTuner == nil {
+		return
+	}
+	switch {
+	case err == nil:
+		if s.autoTuner.succeeded() {
+			s.applyTuning(metadata.TuningReasonRecovered)
+		}
+	case isThrottleError(err):
+		s.observeThrottle()
+	}
+}
+
+// observeThrottle reports a 429 or 5xx response to the auto-tuner. The
+// settings are recorded even when already at their bounds, so every
+// throttling episode shows up in the metadata.
+func (s *Scheduler) observeThrottle() {
+	if s.autoTuner == nil {
+		return
+	}
+	s.autoTuner.throttled()
+	s.applyTuning(metadata.TuningReasonThrottled)
+}
+
+// applyTuning pushes the tuner's delay to the rate limiter and records the
+// current settings, the advisory concurrency included.
+func (s *Scheduler) applyTuning(reason metadata.TuningReason) {
+	s.rateLimiter.SetBaseDelay(s.autoTuner.delay)
+	s.metadataSink.RecordTuning(metadata.NewTuningEvent(
+		s.autoTuner.recommendedConcurrency,
+		s.autoTuner.delay,
+		reason,
+		time.Now(),
+	))
+}
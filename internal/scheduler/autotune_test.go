@@ -0,0 +1,138 @@
+package scheduler
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/rohmanhakim/docs-crawler/pkg/failure"
+	ratelimiter "github.com/rohmanhakim/rate-limiter"
+)
+
+// throttlingHost answers 429 whenever a client following the tuner's
+// advisory concurrency would run above threshold concurrent requests.
+type throttlingHost struct {
+	threshold int
+}
+
+func (h throttlingHost) fetch(concurrency int) failure.ClassifiedError {
+	if concurrency > h.threshold {
+		return fetcher.NewFetchError(fetcher.ErrCauseRequestTooMany, "429 Too Many Requests")
+	}
+	return nil
+}
+
+func newAutoTuneSchedulerForTest(t *testing.T, bounds config.AutoTune) (*Scheduler, *metadatatest.SinkMock, *ratelimiter.ConcurrentRateLimiter) {
+	t.Helper()
+	cfg, err := config.WithDefault([]url.URL{{Scheme: "https", Host: "example.com"}}).
+		WithAutoTune(bounds).
+		Build()
+	if err != nil {
+		t.Fatalf("unexpected config error: %v", err)
+	}
+	sink := &metadatatest.SinkMock{}
+	limiter := ratelimiter.NewConcurrentRateLimiter()
+	s := &Scheduler{metadataSink: sink, rateLimiter: limiter}
+	s.initAutoTune(cfg)
+	return s, sink, limiter
+}
+
+func TestAutoTune_RecommendationBacksOffUnder429sAndRecovers(t *testing.T) {
+	s, sink, limiter := newAutoTuneSchedulerForTest(t, config.NewAutoTune(1, 8, 0, time.Second))
+
+	// GIVEN the tuner starts conservatively
+	if s.autoTuner.recommendedConcurrency != 1 || limiter.BaseDelay() != time.Second {
+		t.Fatalf("expected start at recommended concurrency 1, delay 1s; got %d, %v", s.autoTuner.recommendedConcurrency, limiter.BaseDelay())
+	}
+
+	// WHEN the host throttles above 3 concurrent requests
+	host := throttlingHost{threshold: 3}
+	throttles, peak := 0, 0
+	for i := 0; i < 300; i++ {
+		before := s.autoTuner.recommendedConcurrency
+		peak = max(peak, before)
+		err := host.fetch(before)
+		s.observeFetch(err)
+		if err != nil {
+			throttles++
+			// THEN every 429 halves the recommendation and raises the delay
+			if s.autoTuner.recommendedConcurrency != max(before/2, 1) {
+				t.Fatalf("request %d: expected recommended concurrency %d after a 429 at %d, got %d",
+					i, max(before/2, 1), before, s.autoTuner.recommendedConcurrency)
+			}
+		}
+	}
+	if throttles == 0 {
+		t.Fatal("expected the tuner to probe past the threshold and get throttled")
+	}
+	if peak > host.threshold+1 {
+		t.Errorf("expected concurrency to stay at most one above the threshold, peaked at %d", peak)
+	}
+
+	// WHEN the throttling stops
+	host.threshold = 100
+	for i := 0; i < 200; i++ {
+		s.observeFetch(host.fetch(s.autoTuner.recommendedConcurrency))
+	}
+
+	// THEN the tuner recovers to its upper concurrency and lower delay bounds
+	if s.autoTuner.recommendedConcurrency != 8 {
+		t.Errorf("expected the recommended concurrency to recover to 8, got %d", s.autoTuner.recommendedConcurrency)
+	}
+	if limiter.BaseDelay() != 0 {
+		t.Errorf("expected delay to recover to 0, got %v", limiter.BaseDelay())
+	}
+
+	// AND every change was reported through the metadata sink
+	events := sink.TuningEvents
+	if len(events) == 0 || events[0].Reason() != metadata.TuningReasonInitial {
+		t.Fatalf("expected an initial tuning event first, got %v", events)
+	}
+	reasons := make(map[metadata.TuningReason]int)
+	for _, e := range events {
+		reasons[e.Reason()]++
+	}
+	if reasons[metadata.TuningReasonThrottled] != throttles {
+		t.Errorf("expected %d throttled events, got %d", throttles, reasons[metadata.TuningReasonThrottled])
+	}
+	last := events[len(events)-1]
+	if last.RecommendedConcurrency() != 8 || last.Delay() != 0 || last.Reason() != metadata.TuningReasonRecovered {
+		t.Errorf("expected last event recovered at 8, 0s; got %d, %v, %s", last.RecommendedConcurrency(), last.Delay(), last.Reason())
+	}
+}
+
+func TestAutoTune_IgnoresNonThrottlingErrors(t *testing.T) {
+	s, sink, _ := newAutoTuneSchedulerForTest(t, config.NewAutoTune(1, 4, 0, time.Second))
+
+	for i := 0; i < 50; i++ {
+		s.observeFetch(fetcher.NewFetchError(fetcher.ErrCauseTimeout, "timeout"))
+	}
+
+	if s.autoTuner.recommendedConcurrency != 1 || s.autoTuner.delay != time.Second {
+		t.Errorf("expected settings unchanged, got %d, %v", s.autoTuner.recommendedConcurrency, s.autoTuner.delay)
+	}
+	if len(sink.TuningEvents) != 1 {
+		t.Errorf("expected only the initial tuning event, got %d", len(sink.TuningEvents))
+	}
+}
+
+func TestAutoTune_DisabledRecordsNothing(t *testing.T) {
+	cfg, err := config.WithDefault([]url.URL{{Scheme: "https", Host: "example.com"}}).Build()
+	if err != nil {
+		t.Fatalf("unexpected config error: %v", err)
+	}
+	sink := &metadatatest.SinkMock{}
+	s := &Scheduler{metadataSink: sink, rateLimiter: ratelimiter.NewConcurrentRateLimiter()}
+	s.initAutoTune(cfg)
+
+	s.observeFetch(fetcher.NewFetchError(fetcher.ErrCauseRequestTooMany, "429"))
+	s.observeFetch(nil)
+
+	if s.autoTuner != nil || sink.RecordTuningCalled {
+		t.Error("expected no tuner and no tuning events when auto-tune is disabled")
+	}
+}
@@ -0,0 +1,76 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+)
+
+/*
+Crawl Budgets
+
+A crawl can be bounded by several budgets at once: pages (cfg.MaxPages),
+output bytes (cfg.MaxOutputBytes), wall-clock time (cfg.MaxDuration) and
+page failures (cfg.MaxErrors). All configured budgets are evaluated before
+every page, and the crawl stops gracefully on the first one spent, like a
+finished crawl. The budget that stopped it is reported by
+CrawlingExecution.StopReason.
+
+A crawl searching for one page can also stop once the first page matching
+cfg.MatchPattern is written (cfg.StopAfterFirstMatch); the pages written
+before it are kept.
+*/
+
+// StopReason is why the crawl loop stopped.
+type StopReason string
+
+const (
+	// StopReasonCompleted means the frontier ran out of URLs.
+	StopReasonCompleted StopReason = "completed"
+	// StopReasonMaxPages means cfg.MaxPages pages were processed.
+	StopReasonMaxPages StopReason = "maxPages"
+	// StopReasonMaxBytes means cfg.MaxOutputBytes was exceeded.
+	StopReasonMaxBytes StopReason = "maxOutputBytes"
+	// StopReasonMaxDuration means the crawl ran for cfg.MaxDuration.
+	StopReasonMaxDuration StopReason = "maxDuration"
+	// StopReasonMaxErrors means cfg.MaxErrors pages failed.
+	StopReasonMaxErrors StopReason = "maxErrors"
+	// StopReasonCrawlWindow means no crawl window will open again.
+	StopReasonCrawlWindow StopReason = "crawlWindow"
+	// StopReasonFirstMatch means a page matching cfg.MatchPattern was
+	// written and cfg.StopAfterFirstMatch is set.
+	StopReasonFirstMatch StopReason = "firstMatch"
+)
+
+// budgetUsage is what the crawl has spent so far.
+type budgetUsage struct {
+	pages       int
+	outputBytes int64
+	// failedPages counts pages that were not written; non-fatal errors of
+	// written pages, such as a missing asset, do not count
+	failedPages int
+	elapsed     time.Duration
+	// matched is set once a page matching cfg.MatchPattern was written
+	matched bool
+}
+
+// exhaustedBudget returns the first budget of cfg that usage has spent.
+// Budgets set to 0 are unlimited.
+func exhaustedBudget(cfg config.Config, usage budgetUsage) (StopReason, bool) {
+	if cfg.StopAfterFirstMatch() && usage.matched {
+		return StopReasonFirstMatch, true
+	}
+	if maxPages := cfg.MaxPages(); maxPages > 0 && usage.pages >= maxPages {
+		return StopReasonMaxPages, true
+	}
+	if maxBytes := cfg.MaxOutputBytes(); maxBytes > 0 && usage.outputBytes > maxBytes {
+		return StopReasonMaxBytes, true
+	}
+	if maxDuration := cfg.MaxDuration(); maxDuration > 0 && usage.elapsed >= maxDuration {
+		return StopReasonMaxDuration, true
+	}
+	if maxErrors := cfg.MaxErrors(); maxErrors > 0 && usage.failedPages >= maxErrors {
+		return StopReasonMaxErrors, true
+	}
+	return "", false
+}
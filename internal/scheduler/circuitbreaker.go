@@ -0,0 +1,85 @@
+package scheduler
+
+import (
+	"fmt"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/pkg/failurejournal"
+)
+
+/*
+Stage Circuit Breaker
+
+When every page fails in the same stage (a read-only output disk makes
+every Write fail, an unreachable site makes every fetch fail), retrying
+page after page only wastes time. With config.CircuitBreakerThreshold set,
+the scheduler counts consecutive page failures per failure journal stage:
+- a page failing in a stage adds one to that stage's count
+- a page that completes resets every count
+- a page failing without a stage (an unextractable page, ...) does neither
+- a count reaching the threshold trips the breaker: the crawl aborts with a
+  fatal SchedulerError matching ErrStageCircuitOpen
+
+Pages are processed one at a time, so tripping the breaker is the same as
+failing every later operation of the stage immediately.
+*/
+
+// stageCircuitBreaker counts consecutive page failures per stage.
+type stageCircuitBreaker struct {
+	threshold int
+	failures  map[failurejournal.Stage]int
+}
+
+// newStageCircuitBreaker creates a breaker tripping after threshold
+// consecutive failures of a stage.
+func newStageCircuitBreaker(threshold int) *stageCircuitBreaker {
+	return &stageCircuitBreaker{
+		threshold: threshold,
+		failures:  make(map[failurejournal.Stage]int),
+	}
+}
+
+// failed counts a page failure in stage and reports whether the breaker
+// tripped.
+func (b *stageCircuitBreaker) failed(stage failurejournal.Stage) bool {
+	b.failures[stage]++
+	return b.failures[stage] >= b.threshold
+}
+
+// succeeded resets every stage's count.
+func (b *stageCircuitBreaker) succeeded() {
+	clear(b.failures)
+}
+
+// initCircuitBreaker creates the stage circuit breaker when cfg enables it.
+func (s *Scheduler) initCircuitBreaker(cfg config.Config) {
+	s.circuitBreaker = nil
+	if cfg.CircuitBreakerThreshold() <= 0 {
+		return
+	}
+	s.circuitBreaker = newStageCircuitBreaker(cfg.CircuitBreakerThreshold())
+}
+
+// observePageFailure feeds a page failing in stage to the circuit breaker.
+// It returns a fatal error once stage has failed on too many consecutive
+// pages. Failures without a stage (an unextractable page, ...) are
+// deterministic and say nothing about the health of a stage.
+func (s *Scheduler) observePageFailure(stage failurejournal.Stage) *SchedulerError {
+	if s.circuitBreaker == nil || stage == "" {
+		return nil
+	}
+	if !s.circuitBreaker.failed(stage) {
+		return nil
+	}
+	return NewSchedulerError(ErrCauseStageCircuitOpen, fmt.Sprintf(
+		"%s failed on %d consecutive pages", stage, s.circuitBreaker.threshold,
+	))
+}
+
+// observePageSuccess resets the circuit breaker after a completed page.
+func (s *Scheduler) observePageSuccess() {
+	if s.circuitBreaker == nil {
+		return
+	}
+	s.circuitBreaker.succeeded()
+}
@@ -0,0 +1,62 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+)
+
+// Clock provides the current time to time-dependent scheduling decisions.
+// It is injectable so tests can control wall-clock time.
+type Clock interface {
+	Now() time.Time
+}
+
+// Sleeper blocks for the given duration or until ctx is done,
+// returning ctx.Err() in the latter case.
+type Sleeper interface {
+	Sleep(ctx context.Context, d time.Duration) error
+}
+
+// systemClock is the default Clock backed by time.Now.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// contextSleeper is the default Sleeper backed by a timer.
+type contextSleeper struct{}
+
+func (contextSleeper) Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// SetClock sets the clock used for time-dependent scheduling.
+// If clock is nil, the system clock is used as a safe default.
+func (s *Scheduler) SetClock(clock Clock) {
+	if clock == nil {
+		s.clock = systemClock{}
+		return
+	}
+	s.clock = clock
+}
+
+// SetSleeper sets the sleeper used when the scheduler has to pause.
+// If sleeper is nil, a timer-based sleeper is used as a safe default.
+func (s *Scheduler) SetSleeper(sleeper Sleeper) {
+	if sleeper == nil {
+		s.sleeper = contextSleeper{}
+		return
+	}
+	s.sleeper = sleeper
+}
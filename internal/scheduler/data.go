@@ -12,6 +12,18 @@ type CrawlingExecution struct {
 	totalWebPages int
 	totalAssets   int
 	totalErrors   int
+	// pageAttempts holds the pipeline run count of pages that needed page retries
+	pageAttempts map[string]int
+	// stopReason is why the crawl loop stopped
+	stopReason StopReason
+	// graph holds the links submitted to the frontier, in discovery order
+	graph []Edge
+	// unchangedPages counts the write results kept from the previous pass
+	unchangedPages int
+	// iterations holds the stats of every pass of a repeated crawl
+	iterations []IterationStats
+	// brokenLinks holds the broken links found by a link check
+	brokenLinks []BrokenLink
 }
 
 func NewCrawlingExecution(
@@ -36,6 +48,36 @@ func (c *CrawlingExecution) TotalAssets() int {
 	return c.totalAssets
 }
 
+// StopReason returns why the crawl stopped: the crawl budget that was spent,
+// or StopReasonCompleted when every admitted URL was crawled.
+func (c *CrawlingExecution) StopReason() StopReason {
+	return c.stopReason
+}
+
+// Graph returns the crawl graph: one edge per link submitted to the
+// frontier, including links to URLs that were already known.
+func (c *CrawlingExecution) Graph() []Edge {
+	return c.graph
+}
+
+// UnchangedPages returns the number of write results kept from the previous
+// pass of a repeated crawl because the page did not change.
+func (c *CrawlingExecution) UnchangedPages() int {
+	return c.unchangedPages
+}
+
+// Iterations returns the stats of every pass of a repeated crawl, in order,
+// or nil when the crawl ran once.
+func (c *CrawlingExecution) Iterations() []IterationStats {
+	return append([]IterationStats(nil), c.iterations...)
+}
+
+// BrokenLinks returns the broken links found by a link check, in discovery
+// order, or nil when the crawl was not a link check.
+func (c *CrawlingExecution) BrokenLinks() []BrokenLink {
+	return c.brokenLinks
+}
+
 type PipelineOutcome struct {
 	Continue bool
 	Retry    bool
@@ -89,6 +131,17 @@ func (c *CrawlingExecution) TotalVisitedPages() int {
 	return c.totalWebPages
 }
 
+// PageAttempts returns how many times the page pipeline ran for each URL
+// that needed more than one attempt. Pages that succeeded or failed on the
+// first run are not listed.
+func (c *CrawlingExecution) PageAttempts() map[string]int {
+	attempts := make(map[string]int, len(c.pageAttempts))
+	for pageURL, count := range c.pageAttempts {
+		attempts[pageURL] = count
+	}
+	return attempts
+}
+
 // TotalErrors returns the total number of errors encountered during crawling.
 func (c *CrawlingExecution) TotalErrors() int {
 	return c.totalErrors
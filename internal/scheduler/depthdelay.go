@@ -0,0 +1,47 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+)
+
+/*
+Depth Delay
+
+With config.DepthDelayFactor set, the crawl slows down as it goes deeper,
+leaving deep sections for later at a gentler pace and favouring breadth.
+The delay before fetching a page at depth d becomes
+
+	baseDelay * (1 + factor*d)
+
+capped at config.MaxDepthDelay. The base is the rate limiter's base delay:
+config.BaseDelay, or the auto-tuner's current delay when it is enabled.
+The rate limiter still waits the base delay (or a longer crawl-delay or
+backoff) between requests; the scheduler sleeps the depth-scaled excess
+over the base right before the page is fetched. A zero factor leaves the
+delay as it was.
+*/
+
+// resolveDepthDelay returns the depth-scaled delay for a page at the given
+// depth. A positive maxDelay caps the scaling, but never below baseDelay.
+func resolveDepthDelay(baseDelay time.Duration, factor float64, maxDelay time.Duration, depth int) time.Duration {
+	if factor <= 0 || depth <= 0 || baseDelay <= 0 {
+		return baseDelay
+	}
+	scaled := float64(baseDelay) * (1 + factor*float64(depth))
+	if maxDelay > 0 && scaled > float64(maxDelay) {
+		return max(maxDelay, baseDelay)
+	}
+	return time.Duration(scaled)
+}
+
+// depthDelay returns how much longer than the base delay the scheduler
+// waits before fetching a page at the given depth.
+func (s *Scheduler) depthDelay(cfg config.Config, depth int) time.Duration {
+	baseDelay := cfg.BaseDelay()
+	if s.autoTuner != nil {
+		baseDelay = s.autoTuner.delay
+	}
+	return resolveDepthDelay(baseDelay, cfg.DepthDelayFactor(), cfg.MaxDepthDelay(), depth) - baseDelay
+}
@@ -0,0 +1,43 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResolveDepthDelay_DeeperTokenWaitsLonger(t *testing.T) {
+	shallow := resolveDepthDelay(time.Second, 0.5, 0, 0)
+	deep := resolveDepthDelay(time.Second, 0.5, 0, 2)
+
+	if shallow != time.Second {
+		t.Errorf("expected depth 0 to resolve to the base delay, got %v", shallow)
+	}
+	if deep != 2*time.Second {
+		t.Errorf("expected depth 2 to resolve to 2s, got %v", deep)
+	}
+	if deep <= shallow {
+		t.Errorf("expected depth 2 (%v) to wait longer than depth 0 (%v)", deep, shallow)
+	}
+}
+
+func TestResolveDepthDelay_ZeroFactorKeepsBaseDelay(t *testing.T) {
+	for depth := 0; depth <= 5; depth++ {
+		if got := resolveDepthDelay(time.Second, 0, 30*time.Second, depth); got != time.Second {
+			t.Errorf("depth %d: expected the base delay 1s, got %v", depth, got)
+		}
+	}
+}
+
+func TestResolveDepthDelay_CappedAtMax(t *testing.T) {
+	if got := resolveDepthDelay(time.Second, 1, 3*time.Second, 10); got != 3*time.Second {
+		t.Errorf("expected the delay capped at 3s, got %v", got)
+	}
+	// The cap never lowers the base delay
+	if got := resolveDepthDelay(5*time.Second, 1, 3*time.Second, 2); got != 5*time.Second {
+		t.Errorf("expected the base delay 5s to win over a lower cap, got %v", got)
+	}
+	// A zero cap leaves the scaling uncapped
+	if got := resolveDepthDelay(time.Second, 1, 0, 10); got != 11*time.Second {
+		t.Errorf("expected an uncapped 11s, got %v", got)
+	}
+}
@@ -0,0 +1,127 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/pkg/failure"
+)
+
+// ErrStageCircuitOpen is matched (with errors.Is) by the fatal error that
+// aborts a crawl once a stage's circuit breaker trips.
+var ErrStageCircuitOpen = errors.New("stage circuit open")
+
+// ErrStrictMode is matched (with errors.Is) by the fatal error that aborts
+// a crawl in strict mode once a page fails in the pipeline.
+var ErrStrictMode = errors.New("strict mode")
+
+// ErrOutputBudgetExceeded is returned, with the crawl's execution result,
+// when the crawl stopped because config.MaxOutputBytes was exceeded.
+var ErrOutputBudgetExceeded = errors.New("output budget exceeded")
+
+// ErrOutputNotWritable is matched (with errors.Is) by the error returned
+// from initialization when the output directory cannot be created or
+// written to.
+var ErrOutputNotWritable = errors.New("output directory not writable")
+
+type SchedulerErrorCause string
+
+const (
+	// ErrCauseTransformFailed indicates that a registered transformer returned
+	// an unclassified error for a document.
+	ErrCauseTransformFailed SchedulerErrorCause = "transform failed"
+	// ErrCauseStageCircuitOpen indicates that too many consecutive pages
+	// failed in the same stage.
+	ErrCauseStageCircuitOpen SchedulerErrorCause = "stage circuit open"
+	// ErrCauseStrictMode indicates that a page failed in the pipeline while
+	// config.StrictMode makes every such failure fatal.
+	ErrCauseStrictMode SchedulerErrorCause = "strict mode"
+)
+
+// schedulerErrorClassifications provides explicit retry policy and impact level
+// for each SchedulerErrorCause.
+//
+// Classification Rationale:
+// - TransformFailed: Never retry - transformers are deterministic over the same document
+// - StageCircuitOpen: Abort - the failures are systemic, further pages would fail the same way
+// - StrictMode: Abort - the run asked for any pipeline failure to fail it
+var schedulerErrorClassifications = map[SchedulerErrorCause]struct {
+	Policy failure.RetryPolicy
+	Impact failure.ImpactLevel
+}{
+	ErrCauseTransformFailed:  {failure.RetryPolicyNever, failure.ImpactLevelContinue},
+	ErrCauseStageCircuitOpen: {failure.RetryPolicyNever, failure.ImpactLevelAbort},
+	ErrCauseStrictMode:       {failure.RetryPolicyNever, failure.ImpactLevelAbort},
+}
+
+// SchedulerError represents an error raised by the scheduler itself rather
+// than by one of the pipeline stages it orchestrates.
+// It implements failure.ClassifiedError interface.
+type SchedulerError struct {
+	Message string
+	Cause   SchedulerErrorCause
+	policy  failure.RetryPolicy
+	impact  failure.ImpactLevel
+}
+
+// NewSchedulerError creates a new SchedulerError with explicit classification based on cause.
+func NewSchedulerError(cause SchedulerErrorCause, message string) *SchedulerError {
+	classification := schedulerErrorClassifications[cause]
+	return &SchedulerError{
+		Message: message,
+		Cause:   cause,
+		policy:  classification.Policy,
+		impact:  classification.Impact,
+	}
+}
+
+func (e *SchedulerError) Error() string {
+	return fmt.Sprintf("scheduler error: %s: %s", e.Cause, e.Message)
+}
+
+func (e *SchedulerError) Severity() failure.Severity {
+	if e.impact == failure.ImpactLevelAbort {
+		return failure.SeverityFatal
+	}
+	if e.policy == failure.RetryPolicyManual {
+		return failure.SeverityRetryExhausted
+	}
+	return failure.SeverityRecoverable
+}
+
+// RetryPolicy returns the automatic retry behavior for this error.
+func (e *SchedulerError) RetryPolicy() failure.RetryPolicy {
+	return e.policy
+}
+
+// Impact returns how the scheduler should respond to this error.
+func (e *SchedulerError) Impact() failure.ImpactLevel {
+	return e.impact
+}
+
+// Unwrap exposes ErrStageCircuitOpen for a tripped circuit breaker and
+// ErrStrictMode for a page failing in strict mode.
+func (e *SchedulerError) Unwrap() error {
+	switch e.Cause {
+	case ErrCauseStageCircuitOpen:
+		return ErrStageCircuitOpen
+	case ErrCauseStrictMode:
+		return ErrStrictMode
+	}
+	return nil
+}
+
+// mapSchedulerErrorToMetadataCause maps scheduler-local error semantics
+// to the canonical metadata.ErrorCause table.
+//
+// This mapping is observational only and MUST NOT be used
+// to derive control-flow decisions.
+func mapSchedulerErrorToMetadataCause(err *SchedulerError) metadata.ErrorCause {
+	switch err.Cause {
+	case ErrCauseTransformFailed:
+		return metadata.CauseContentInvalid
+	default:
+		return metadata.CauseUnknown
+	}
+}
@@ -0,0 +1,71 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/pkg/failure"
+)
+
+// TestSchedulerError_Classifications tests that all SchedulerErrorCause values
+// have the correct RetryPolicy and Impact classification.
+func TestSchedulerError_Classifications(t *testing.T) {
+	tests := []struct {
+		name         string
+		cause        SchedulerErrorCause
+		wantPolicy   failure.RetryPolicy
+		wantImpact   failure.ImpactLevel
+		wantSeverity failure.Severity
+	}{
+		{
+			name:         "ErrCauseTransformFailed should be RetryPolicyNever",
+			cause:        ErrCauseTransformFailed,
+			wantPolicy:   failure.RetryPolicyNever,
+			wantImpact:   failure.ImpactLevelContinue,
+			wantSeverity: failure.SeverityRecoverable,
+		},
+		{
+			name:         "ErrCauseStageCircuitOpen should abort",
+			cause:        ErrCauseStageCircuitOpen,
+			wantPolicy:   failure.RetryPolicyNever,
+			wantImpact:   failure.ImpactLevelAbort,
+			wantSeverity: failure.SeverityFatal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewSchedulerError(tt.cause, "test message")
+			if got := err.RetryPolicy(); got != tt.wantPolicy {
+				t.Errorf("RetryPolicy() = %v, want %v", got, tt.wantPolicy)
+			}
+			if got := err.Impact(); got != tt.wantImpact {
+				t.Errorf("Impact() = %v, want %v", got, tt.wantImpact)
+			}
+			if got := err.Severity(); got != tt.wantSeverity {
+				t.Errorf("Severity() = %v, want %v", got, tt.wantSeverity)
+			}
+		})
+	}
+}
+
+// TestMapSchedulerErrorToMetadataCause verifies the observational mapping
+// from scheduler error causes to metadata causes.
+func TestMapSchedulerErrorToMetadataCause(t *testing.T) {
+	tests := []struct {
+		cause SchedulerErrorCause
+		want  metadata.ErrorCause
+	}{
+		{ErrCauseTransformFailed, metadata.CauseContentInvalid},
+		{SchedulerErrorCause("unknown"), metadata.CauseUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.cause), func(t *testing.T) {
+			err := NewSchedulerError(tt.cause, "test message")
+			if got := mapSchedulerErrorToMetadataCause(err); got != tt.want {
+				t.Errorf("mapSchedulerErrorToMetadataCause() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+)
+
+/*
+Embedding
+
+Programs embedding the crawler build their config.Config in code with the
+config builder instead of writing a config file. ExecuteWithConfig runs the
+same pipeline as the file-based path on such a config: the config is
+initialized with InitializeWithConfig and crawled with
+ExecuteCrawlingWithState, so writes and stats are identical to loading the
+equivalent file.
+
+ctx becomes the crawl context: it bounds rate-limit waits, retry backoffs
+and crawl-window pauses, so cancelling it stops the crawl at the next wait.
+A nil ctx keeps the context the scheduler was constructed with.
+*/
+
+// ExecuteWithConfig crawls with cfg under ctx and returns the execution.
+func (s *Scheduler) ExecuteWithConfig(ctx context.Context, cfg config.Config) (CrawlingExecution, error) {
+	if ctx != nil {
+		s.ctx = ctx
+		s.crawlCtx = ctx
+	}
+
+	init, err := s.InitializeWithConfig(cfg)
+	if err != nil {
+		return CrawlingExecution{}, fmt.Errorf("error initializing crawl: %w", err)
+	}
+	return s.ExecuteCrawlingWithState(init)
+}
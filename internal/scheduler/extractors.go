@@ -0,0 +1,29 @@
+package scheduler
+
+import (
+	"github.com/rohmanhakim/docs-crawler/internal/extractor"
+)
+
+// markdownMediaTypes are the media types passed through as Markdown.
+var markdownMediaTypes = []string{"text/markdown", "text/x-markdown"}
+
+// RegisterExtractor sets the extractor for pages served with mediaType,
+// replacing the built-in one if any. HTML goes to the DOM extractor and
+// Markdown is passed through unless overridden. The fetcher must also allow
+// the media type (config.AllowedContentTypes) for pages to reach it.
+func (s *Scheduler) RegisterExtractor(mediaType string, ext extractor.Extractor) {
+	s.extractorRegistry().Register(mediaType, ext)
+}
+
+// extractorRegistry returns the scheduler's extractor registry, building
+// the built-in one around the DOM extractor on first use.
+func (s *Scheduler) extractorRegistry() *extractor.Registry {
+	if s.extractors == nil {
+		s.extractors = extractor.NewRegistry(s.domExtractor)
+		markdown := extractor.NewMarkdownExtractor(s.metadataSink)
+		for _, mediaType := range markdownMediaTypes {
+			s.extractors.Register(mediaType, &markdown)
+		}
+	}
+	return s.extractors
+}
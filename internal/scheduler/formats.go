@@ -0,0 +1,62 @@
+package scheduler
+
+import (
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/sanitizer"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/rohmanhakim/docs-crawler/pkg/failure"
+)
+
+/*
+Output Formats
+
+Besides Markdown, config.OutputFormats can ask for every page to be written
+as sanitized HTML and as plain text (see storage.FormatWriter). Both are
+rendered from the sanitized document the Markdown was converted from, so
+they carry the same content without the frontmatter, and links and images
+keep the URLs of the fetched page.
+
+Pages published as Markdown have no sanitized document and are written as
+Markdown only. So are pages handed to a writer that does not implement
+storage.FormatWriter.
+*/
+
+// writeFormats writes the page of result in every configured format other
+// than Markdown, and returns result with their paths recorded and the
+// number of bytes written, which count against config.MaxOutputBytes.
+func (s *Scheduler) writeFormats(
+	cfg config.Config,
+	result storage.WriteResult,
+	sanitized *sanitizer.SanitizedHTMLDoc,
+) (storage.WriteResult, int64, failure.ClassifiedError) {
+	if sanitized == nil {
+		return result, 0, nil
+	}
+	formatWriter, ok := s.storageSink.(storage.FormatWriter)
+	if !ok {
+		return result, 0, nil
+	}
+	var writtenBytes int64
+	for _, format := range cfg.OutputFormats() {
+		var content []byte
+		switch format {
+		case config.OutputFormatHTML:
+			rendered, err := sanitized.RenderHTML()
+			if err != nil {
+				return result, writtenBytes, storage.NewStorageError(storage.ErrCauseWriteFailure, err.Error(), storage.FormatPath(result.Path(), format))
+			}
+			content = rendered
+		case config.OutputFormatText:
+			content = sanitized.PlainText()
+		default:
+			continue
+		}
+		written, err := formatWriter.WriteFormat(result, format, content)
+		if err != nil {
+			return result, writtenBytes, err
+		}
+		result = written
+		writtenBytes += int64(len(content))
+	}
+	return result, writtenBytes, nil
+}
@@ -0,0 +1,110 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/frontier"
+	"github.com/rohmanhakim/docs-crawler/pkg/failure"
+	"github.com/rohmanhakim/docs-crawler/pkg/fileutil"
+)
+
+/*
+Crawl Graph
+
+Every link the scheduler hands to the frontier is recorded as an Edge from
+the page it was found on to the canonical target URL. Only submitted links
+count: links skipped as out of scope, by extension or by robots.txt leave no
+edge. A link to a URL the frontier has already seen still records an edge,
+so the graph shows every in-scope reference and not only the crawl tree.
+
+The edges are returned by CrawlingExecution.Graph and, when
+config.GraphFormat is set, written to outputDir as graph.json:
+
+	[
+	  {"from": "https://example.com/docs", "to": "https://example.com/docs/a", "depth": 1},
+	  ...
+	]
+
+or as graph.dot, a Graphviz digraph labelled with the target depth.
+*/
+
+// Graph file names written into the output directory.
+const (
+	GraphJSONFileName = "graph.json"
+	GraphDOTFileName  = "graph.dot"
+)
+
+// Edge is a link from a crawled page to a URL submitted to the frontier.
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Depth is the crawl depth the target was submitted at
+	Depth int `json:"depth"`
+}
+
+// submitLink submits a link found on the page at from and records the edge
+// when the target reaches the frontier.
+func (s *Scheduler) submitLink(from url.URL, to url.URL, depth int) failure.ClassifiedError {
+	source := s.canonicalize(from)
+	admitted, err := s.submitForAdmission(to, frontier.SourceCrawl, frontier.NewDiscoveryMetadata(depth, nil).WithReferrer(source))
+	if admitted != nil {
+		s.graph = append(s.graph, Edge{
+			From:  source.String(),
+			To:    admitted.String(),
+			Depth: depth,
+		})
+	}
+	return err
+}
+
+// writeGraph writes edges to outputDir in the given graph format.
+func writeGraph(outputDir string, format string, edges []Edge) error {
+	var (
+		name string
+		data []byte
+	)
+	switch format {
+	case config.GraphFormatJSON:
+		name = GraphJSONFileName
+		if edges == nil {
+			edges = []Edge{}
+		}
+		encoded, err := json.MarshalIndent(edges, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode graph: %w", err)
+		}
+		data = append(encoded, '\n')
+	case config.GraphFormatDOT:
+		name = GraphDOTFileName
+		data = encodeDOT(edges)
+	default:
+		return fmt.Errorf("unknown graph format %q", format)
+	}
+
+	if err := fileutil.EnsureDir(outputDir); err != nil {
+		return err
+	}
+	path := filepath.Join(outputDir, name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write graph %s: %w", path, err)
+	}
+	return nil
+}
+
+// encodeDOT renders edges as a Graphviz digraph.
+func encodeDOT(edges []Edge) []byte {
+	var b strings.Builder
+	b.WriteString("digraph crawl {\n")
+	for _, edge := range edges {
+		fmt.Fprintf(&b, "  %s -> %s [label=%q];\n", strconv.Quote(edge.From), strconv.Quote(edge.To), strconv.Itoa(edge.Depth))
+	}
+	b.WriteString("}\n")
+	return []byte(b.String())
+}
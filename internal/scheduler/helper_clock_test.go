@@ -0,0 +1,43 @@
+package scheduler_test
+
+import (
+	"context"
+	"time"
+)
+
+// fakeClock is a manually advanced Clock for tests.
+type fakeClock struct {
+	now time.Time
+}
+
+func newFakeClock(now time.Time) *fakeClock {
+	return &fakeClock{now: now}
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// fakeSleeper records requested sleeps and advances the paired fakeClock
+// instead of blocking.
+type fakeSleeper struct {
+	clock  *fakeClock
+	sleeps []time.Duration
+}
+
+func newFakeSleeper(clock *fakeClock) *fakeSleeper {
+	return &fakeSleeper{clock: clock}
+}
+
+func (s *fakeSleeper) Sleep(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.sleeps = append(s.sleeps, d)
+	s.clock.Advance(d)
+	return nil
+}
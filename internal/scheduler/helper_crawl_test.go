@@ -0,0 +1,113 @@
+package scheduler_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/frontier"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/internal/robots"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// crawlSinglePageForTest crawls only the first seed of cfg, serving body as
+// its HTML, and returns the URLs submitted to the frontier for the links
+// discovered on that page, in submission order.
+func crawlSinglePageForTest(t *testing.T, cfg config.Config, body string) []string {
+	t.Helper()
+	var submitted []string
+	for _, candidate := range crawlSinglePageCandidatesForTest(t, cfg, body, nil) {
+		target := candidate.TargetURL()
+		submitted = append(submitted, target.String())
+	}
+	return submitted
+}
+
+// crawlSinglePageCandidatesForTest is like crawlSinglePageForTest but also
+// sends the given response headers and returns the full admission candidates.
+func crawlSinglePageCandidatesForTest(
+	t *testing.T,
+	cfg config.Config,
+	body string,
+	headers map[string]string,
+) []frontier.CrawlAdmissionCandidate {
+	t.Helper()
+	return crawlSinglePageWithSinkForTest(t, cfg, body, headers, &metadata.NoopSink{})
+}
+
+// crawlSinglePageWithSinkForTest is like crawlSinglePageCandidatesForTest but
+// records metadata into the given sink.
+func crawlSinglePageWithSinkForTest(
+	t *testing.T,
+	cfg config.Config,
+	body string,
+	headers map[string]string,
+	sink metadata.MetadataSink,
+) []frontier.CrawlAdmissionCandidate {
+	t.Helper()
+	seedURL := cfg.SeedURLs()[0]
+	responseHeaders := map[string]string{"Content-Type": "text/html"}
+	for k, v := range headers {
+		responseHeaders[k] = v
+	}
+
+	mockRobot := NewRobotsMockForTest(t)
+	mockRobot.On("Init", mock.Anything, mock.Anything).Return()
+	mockRobot.OnDecide(mock.Anything, robots.Decision{Allowed: true, Reason: robots.EmptyRuleSet}, nil)
+
+	mockFetcher := new(fetcherMock)
+	mockFetcher.On("Init", mock.Anything, mock.Anything).Return()
+	mockFetcher.On("Fetch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
+		fetcher.NewFetchResultForTest(
+			seedURL,
+			[]byte(body),
+			200,
+			"text/html",
+			responseHeaders,
+			time.Now(),
+		), nil)
+
+	mockFrontier := newFrontierMockForTest(t)
+	mockFrontier.disableAutoEnqueue = true
+	mockFrontier.OnDequeue(frontier.NewCrawlToken(seedURL, 0), true).Once()
+	mockFrontier.OnDequeue(frontier.CrawlToken{}, false)
+
+	mockStorage := newStorageMockForTest(t)
+	mockStorage.On("Write", mock.Anything, mock.Anything, mock.Anything).Return(storage.WriteResult{}, nil)
+
+	s := createSchedulerForTest(
+		t,
+		context.Background(),
+		newMockFinalizer(t),
+		sink,
+		newRateLimiterMockForTest(t),
+		mockFrontier,
+		mockRobot,
+		mockFetcher,
+		nil,
+		nil,
+		nil,
+		nil,
+		mockStorage,
+		newFailureJournalMockForTest(t),
+	)
+
+	init, err := s.InitializeWithConfig(cfg)
+	assert.NoError(t, err)
+	_, err = s.ExecuteCrawlingWithState(init)
+	assert.NoError(t, err)
+
+	var discovered []frontier.CrawlAdmissionCandidate
+	for _, candidate := range mockFrontier.submittedCandidates {
+		if candidate.SourceContext() != frontier.SourceCrawl {
+			continue
+		}
+		discovered = append(discovered, candidate)
+	}
+	return discovered
+}
@@ -16,12 +16,18 @@ import (
 // fetcherMock is a testify mock for the Fetcher
 type fetcherMock struct {
 	mock.Mock
+	param fetcher.FetchParam
 }
 
 func (f *fetcherMock) Init(httpClient *http.Client, userAgent string) {
 	f.Called(httpClient, userAgent)
 }
 
+func (f *fetcherMock) SetFetchParam(param fetcher.FetchParam) {
+	// Note: We don't require f.Called() here to avoid panic when no expectation is set
+	f.param = param
+}
+
 func (f *fetcherMock) Fetch(
 	ctx context.Context,
 	crawlDepth int,
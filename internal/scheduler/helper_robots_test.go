@@ -11,12 +11,23 @@ import (
 
 type robotsMock struct {
 	mock.Mock
+	hostUserAgents map[string]string
+	overrides      map[string]string
 }
 
 func (r *robotsMock) Init(userAgent string, httpClient *http.Client) {
 	r.Called(userAgent, httpClient)
 }
 
+func (r *robotsMock) SetHostUserAgents(agents map[string]string) {
+	// Note: We don't require r.Called() here to avoid panic when no expectation is set
+	r.hostUserAgents = agents
+}
+
+func (r *robotsMock) SetOverrides(overrides map[string]string) {
+	r.overrides = overrides
+}
+
 func (r *robotsMock) Decide(targetURL url.URL) (robots.Decision, *robots.RobotsError) {
 	args := r.Called(targetURL)
 
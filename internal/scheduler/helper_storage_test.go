@@ -12,6 +12,12 @@ import (
 
 type storageMock struct {
 	mock.Mock
+	// manifestDir and manifest capture the last WriteManifest call;
+	// it needs no expectation so every crawl test can ignore it
+	manifestDir string
+	manifest    []storage.WriteResult
+	// configSnapshot captures the last WriteConfigSnapshot call
+	configSnapshot []byte
 }
 
 func (s *storageMock) Write(
@@ -28,6 +34,17 @@ func (s *storageMock) Write(
 	return res, err
 }
 
+func (s *storageMock) WriteManifest(outputDir string, results []storage.WriteResult) failure.ClassifiedError {
+	s.manifestDir = outputDir
+	s.manifest = append([]storage.WriteResult(nil), results...)
+	return nil
+}
+
+func (s *storageMock) WriteConfigSnapshot(outputDir string, snapshot []byte) failure.ClassifiedError {
+	s.configSnapshot = append([]byte(nil), snapshot...)
+	return nil
+}
+
 func newStorageMockForTest(t *testing.T) *storageMock {
 	t.Helper()
 	m := new(storageMock)
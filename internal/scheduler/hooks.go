@@ -0,0 +1,59 @@
+package scheduler
+
+import (
+	"net/url"
+
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+)
+
+/*
+Lifecycle Hooks
+
+Hooks let callers observe the crawl for instrumentation and side effects,
+such as notifying a queue whenever a page is written. They run
+synchronously on the crawl loop, in registration order, so a slow hook
+slows the crawl down.
+
+Before-fetch hooks run before every fetch of a page, including page retries.
+After-write hooks run once a page and its additional formats were written;
+duplicates and pages kept unchanged from a previous pass are not written
+and do not trigger them.
+*/
+
+// BeforeFetchHook is called with the URL and crawl depth of a page about
+// to be fetched.
+type BeforeFetchHook func(u url.URL, depth int)
+
+// AfterWriteHook is called with the write result and fetched URL of a page
+// that was written.
+type AfterWriteHook func(result storage.WriteResult, u url.URL)
+
+// OnBeforeFetch registers a hook run before every page fetch.
+// A nil hook is ignored.
+func (s *Scheduler) OnBeforeFetch(fn BeforeFetchHook) {
+	if fn == nil {
+		return
+	}
+	s.beforeFetchHooks = append(s.beforeFetchHooks, fn)
+}
+
+// OnAfterWrite registers a hook run after every page write.
+// A nil hook is ignored.
+func (s *Scheduler) OnAfterWrite(fn AfterWriteHook) {
+	if fn == nil {
+		return
+	}
+	s.afterWriteHooks = append(s.afterWriteHooks, fn)
+}
+
+func (s *Scheduler) runBeforeFetchHooks(u url.URL, depth int) {
+	for _, hook := range s.beforeFetchHooks {
+		hook(u, depth)
+	}
+}
+
+func (s *Scheduler) runAfterWriteHooks(result storage.WriteResult, u url.URL) {
+	for _, hook := range s.afterWriteHooks {
+		hook(result, u)
+	}
+}
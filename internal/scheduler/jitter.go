@@ -0,0 +1,144 @@
+package scheduler
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	ratelimiter "github.com/rohmanhakim/rate-limiter"
+	"github.com/rohmanhakim/retrier"
+)
+
+/*
+Seeded Jitter
+
+The rate limiter and retrier libraries draw jitter from the global
+math/rand source, which cannot be seeded. To make a run reproducible from
+config.RandomSeed, the scheduler draws jitter itself from per-component
+streams derived with config.SubSeed and passes the libraries a zero jitter:
+
+- rate limiter: seededRateLimiter waits an extra seeded jitter after the
+  wrapped limiter's Wait
+- retrier: every retry of a page or asset fetch waits its own seeded
+  jitter draw before the attempt (see pkg/retryjitter)
+
+Every draw lies in [0, max) whatever config.JitterDistribution selects:
+
+- uniform: evenly spread over the range
+- exponential: exponential with a mean of max/4, truncated at max; short
+  waits are common and long ones rare, like a person clicking through
+- normal: normal around max/2 with a standard deviation of max/6, clamped
+  to the range
+
+Each draw consumes a fixed number of values from the stream, so a given
+seed and distribution always produce the same sequence.
+*/
+
+// exponentialJitterRate is the rate of the exponential distribution in
+// units of the maximum jitter, giving a mean of max/4.
+const exponentialJitterRate = 4.0
+
+// seededJitter draws durations in [0, max) from a seeded source.
+// A nil or unconfigured seededJitter always returns 0.
+type seededJitter struct {
+	mu           sync.Mutex
+	rng          *rand.Rand
+	max          time.Duration
+	distribution string
+}
+
+// newSeededJitter creates a jitter source seeded with seed, drawing from
+// distribution; an empty distribution is uniform.
+func newSeededJitter(seed int64, max time.Duration, distribution string) *seededJitter {
+	j := &seededJitter{}
+	j.reset(seed, max, distribution)
+	return j
+}
+
+// reset re-seeds the source and sets the maximum jitter and distribution.
+func (j *seededJitter) reset(seed int64, max time.Duration, distribution string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.rng = rand.New(rand.NewSource(seed))
+	j.max = max
+	j.distribution = distribution
+}
+
+// next returns the next jitter duration.
+func (j *seededJitter) next() time.Duration {
+	if j == nil {
+		return 0
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.rng == nil || j.max <= 0 {
+		return 0
+	}
+
+	var fraction float64
+	switch j.distribution {
+	case config.JitterExponential:
+		// Inverse CDF of the exponential truncated to [0, 1)
+		truncation := 1 - math.Exp(-exponentialJitterRate)
+		fraction = -math.Log(1-j.rng.Float64()*truncation) / exponentialJitterRate
+	case config.JitterNormal:
+		fraction = math.Max(0, 0.5+j.rng.NormFloat64()/6)
+	default:
+		return time.Duration(j.rng.Int63n(int64(j.max)))
+	}
+
+	jitter := time.Duration(fraction * float64(j.max))
+	if jitter >= j.max {
+		jitter = j.max - 1
+	}
+	return jitter
+}
+
+// seededRateLimiter wraps a rate limiter so its jitter comes from a seeded
+// stream instead of the wrapped limiter's global source.
+type seededRateLimiter struct {
+	ratelimiter.RateLimiter
+	jitter *seededJitter
+}
+
+func newSeededRateLimiter(inner ratelimiter.RateLimiter, jitter *seededJitter) *seededRateLimiter {
+	inner.SetJitter(0)
+	return &seededRateLimiter{RateLimiter: inner, jitter: jitter}
+}
+
+// SetJitter keeps the wrapped limiter jitter-free; the seeded stream's
+// maximum is configured through the scheduler's init instead.
+func (l *seededRateLimiter) SetJitter(time.Duration) {
+	l.RateLimiter.SetJitter(0)
+}
+
+// Wait waits on the wrapped limiter, then for one seeded jitter draw.
+func (l *seededRateLimiter) Wait(ctx context.Context, resource string) error {
+	if err := l.RateLimiter.Wait(ctx, resource); err != nil {
+		return err
+	}
+	return contextSleeper{}.Sleep(ctx, l.jitter.next())
+}
+
+// initJitter seeds the scheduler's jitter streams from cfg.
+func (s *Scheduler) initJitter(cfg config.Config) {
+	if s.limiterJitter != nil {
+		s.limiterJitter.reset(cfg.SubSeed(config.SubSeedRateLimiterJitter), cfg.Jitter(), cfg.JitterDistribution())
+	}
+	s.retryJitter = newSeededJitter(cfg.SubSeed(config.SubSeedFetcherRetry), cfg.Jitter(), cfg.JitterDistribution())
+}
+
+// retryOptions builds the retry options for one request. Their jitter is
+// zero: the fetchers wait a seeded retry jitter draw before every retry.
+func (s *Scheduler) retryOptions(cfg config.Config) []retrier.RetryOption {
+	return []retrier.RetryOption{
+		retrier.WithMaxAttempts(cfg.MaxAttempt()),
+		retrier.WithInitialDuration(cfg.BackoffInitialDuration()),
+		retrier.WithJitter(0),
+		retrier.WithMultiplier(cfg.BackoffMultiplier()),
+		retrier.WithMaxDuration(cfg.BackoffMaxDuration()),
+	}
+}
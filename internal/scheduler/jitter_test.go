@@ -0,0 +1,166 @@
+package scheduler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/pkg/debug/debugtest"
+)
+
+func TestSeededJitter_SameSeedSameSequence(t *testing.T) {
+	first := newSeededJitter(42, time.Second, "")
+	second := newSeededJitter(42, time.Second, "")
+	other := newSeededJitter(43, time.Second, "")
+
+	differs := false
+	for i := 0; i < 10; i++ {
+		a, b, c := first.next(), second.next(), other.next()
+		if a != b {
+			t.Fatalf("draw %d: same seed produced %v and %v", i, a, b)
+		}
+		if a < 0 || a >= time.Second {
+			t.Fatalf("draw %d: %v outside [0, 1s)", i, a)
+		}
+		if a != c {
+			differs = true
+		}
+	}
+	if !differs {
+		t.Error("expected a different seed to produce a different sequence")
+	}
+}
+
+func TestSeededJitter_ZeroWhenUnconfigured(t *testing.T) {
+	var nilJitter *seededJitter
+	if got := nilJitter.next(); got != 0 {
+		t.Errorf("nil jitter: got %v, want 0", got)
+	}
+	if got := (&seededJitter{}).next(); got != 0 {
+		t.Errorf("unseeded jitter: got %v, want 0", got)
+	}
+	if got := newSeededJitter(1, 0, "").next(); got != 0 {
+		t.Errorf("zero max: got %v, want 0", got)
+	}
+}
+
+func TestSeededJitter_DistributionSequences(t *testing.T) {
+	tests := []struct {
+		distribution string
+		want         []time.Duration
+	}{
+		{distribution: config.JitterUniform, want: []time.Duration{231278675, 543856411, 101878760, 526624009, 743547657}},
+		{distribution: config.JitterExponential, want: []time.Duration{114003922, 16746486, 224753636, 57351421, 10992124}},
+		{distribution: config.JitterNormal, want: []time.Duration{758938426, 520876014, 417604197, 707335835, 521996414}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.distribution, func(t *testing.T) {
+			for run := 0; run < 2; run++ {
+				jitter := newSeededJitter(42, time.Second, tt.distribution)
+				for i, want := range tt.want {
+					if got := jitter.next(); got != want {
+						t.Fatalf("run %d, draw %d: got %d, want %d", run, i, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSeededJitter_DistributionsStayInBounds(t *testing.T) {
+	const draws = 10000
+	max := 100 * time.Millisecond
+
+	means := map[string]time.Duration{}
+	for _, distribution := range []string{config.JitterUniform, config.JitterExponential, config.JitterNormal} {
+		jitter := newSeededJitter(7, max, distribution)
+		var total time.Duration
+		for i := 0; i < draws; i++ {
+			got := jitter.next()
+			if got < 0 || got >= max {
+				t.Fatalf("%s draw %d: %v outside [0, %v)", distribution, i, got, max)
+			}
+			total += got
+		}
+		means[distribution] = total / draws
+	}
+
+	// Exponential draws favour short waits; uniform and normal center on max/2
+	if means[config.JitterExponential] >= max/4 {
+		t.Errorf("exponential mean %v, want below %v", means[config.JitterExponential], max/4)
+	}
+	for _, distribution := range []string{config.JitterUniform, config.JitterNormal} {
+		if mean := means[distribution]; mean < 45*time.Millisecond || mean > 55*time.Millisecond {
+			t.Errorf("%s mean %v, want about %v", distribution, mean, max/2)
+		}
+	}
+}
+
+// TestRetryJitter_SameSeedSameRetryDelays fetches a page that always fails
+// through a real fetcher and records the jitter each retry waits: the same
+// seed repeats the sequence, and each retry draws anew.
+func TestRetryJitter_SameSeedSameRetryDelays(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	fetchUrl, _ := url.Parse(server.URL)
+
+	retryDelays := func(seed int64) []time.Duration {
+		cfg, err := config.WithDefault([]url.URL{*fetchUrl}).
+			WithRandomSeed(seed).
+			WithJitter(5 * time.Millisecond).
+			WithMaxAttempt(5).
+			WithBackoffInitialDuration(time.Millisecond).
+			WithBackoffMaxDuration(time.Millisecond).
+			Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		s := &Scheduler{}
+		s.initJitter(cfg)
+
+		var delays []time.Duration
+		f := fetcher.NewHtmlFetcher(&metadata.NoopSink{})
+		f.SetDebugLogger(debugtest.NewLoggerMock())
+		f.Init(&http.Client{}, "test-user-agent")
+		f.SetFetchParam(fetcher.FetchParam{
+			RetryJitter: func() time.Duration {
+				delay := s.retryJitter.next()
+				delays = append(delays, delay)
+				return delay
+			},
+		})
+
+		if _, err := f.Fetch(context.Background(), 0, *fetchUrl, s.retryOptions(cfg)); err == nil {
+			t.Fatal("expected the fetch to fail after its retries")
+		}
+		return delays
+	}
+
+	first := retryDelays(42)
+	second := retryDelays(42)
+	other := retryDelays(43)
+
+	if len(first) != 4 {
+		t.Fatalf("expected 4 retry delays for 5 attempts, got %v", first)
+	}
+	if !slices.Equal(first, second) {
+		t.Errorf("same seed produced different retry delays: %v and %v", first, second)
+	}
+	if slices.Equal(first, other) {
+		t.Errorf("different seeds produced the same retry delays: %v", first)
+	}
+	if slices.Min(first) == slices.Max(first) {
+		t.Errorf("expected every retry to draw its own jitter, got %v", first)
+	}
+}
@@ -0,0 +1,336 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/frontier"
+	"github.com/rohmanhakim/docs-crawler/internal/robots"
+	"github.com/rohmanhakim/docs-crawler/pkg/failure"
+	"github.com/rohmanhakim/docs-crawler/pkg/fileutil"
+	ratelimiter "github.com/rohmanhakim/rate-limiter"
+)
+
+/*
+Link Checking
+
+With config.LinkCheckOnly the crawl validates links instead of producing a
+corpus. Pages are fetched and their <a href> links discovered from the
+fetched HTML, resolved against the URL the page was served from after
+redirects; extraction, conversion and writing are skipped.
+
+Every discovered link is checked once, sending no more requests than the
+crawl would:
+- in-scope links are submitted to the frontier as usual, and their status
+  is the one the crawl's own fetch of the page ended with
+- out-of-scope links are only checked, never crawled: each gets a HEAD
+  request, falling back to GET when the server does not support HEAD,
+  after waiting on the rate limiter for the link's host
+- in-scope links the crawl does not fetch (beyond the maximum depth, with
+  an excluded extension, or left in the frontier when the crawl stopped)
+  are checked like out-of-scope ones once the crawl is done, unless
+  robots.txt disallows them
+
+A link is broken when it answers 4xx or 5xx, or when the request fails.
+Links that were never checked (disallowed, or the crawl context ended
+first) are not counted.
+
+The broken links are returned by CrawlingExecution.BrokenLinks and written
+to outputDir as linkcheck.json, each with the pages referring to it:
+
+	{
+	  "checkedLinks": 42,
+	  "brokenLinks": [
+	    {"url": "https://example.com/docs/gone", "status": 404, "referrers": ["https://example.com/docs"]}
+	  ]
+	}
+*/
+
+// LinkCheckFileName is the broken-links report written into the output
+// directory by a link check.
+const LinkCheckFileName = "linkcheck.json"
+
+// BrokenLink is a discovered link whose target could not be fetched.
+type BrokenLink struct {
+	URL string `json:"url"`
+	// Status is the HTTP status answered, or 0 when the request failed
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+	// Referrers are the pages linking to URL, in discovery order
+	Referrers []string `json:"referrers"`
+}
+
+// LinkCheckReport is the content of linkcheck.json.
+type LinkCheckReport struct {
+	CheckedLinks int          `json:"checkedLinks"`
+	BrokenLinks  []BrokenLink `json:"brokenLinks"`
+}
+
+// linkOutcome is the answer a checked URL got.
+type linkOutcome struct {
+	status int
+	err    string
+}
+
+func (o linkOutcome) broken() bool {
+	return o.err != "" || o.status >= 400
+}
+
+// linkStatus is a discovered link and the pages referring to it.
+type linkStatus struct {
+	// target is the URL probed when the crawl does not fetch the link
+	target    url.URL
+	inScope   bool
+	outcome   *linkOutcome
+	referrers []string
+}
+
+// linkChecker records every discovered link once, with who refers to it,
+// and the outcome of the crawl's own page fetches.
+// A nil linkChecker means the crawl is not a link check.
+type linkChecker struct {
+	httpClient *http.Client
+	userAgent  func(host string) string
+	// links and fetched are keyed by canonical URL
+	links   map[string]*linkStatus
+	fetched map[string]linkOutcome
+	// order lists the discovered links in discovery order
+	order []string
+}
+
+// newLinkChecker returns a checker sending requests with httpClient, or nil
+// unless cfg.LinkCheckOnly is set.
+func newLinkChecker(cfg config.Config, httpClient *http.Client) *linkChecker {
+	if !cfg.LinkCheckOnly() {
+		return nil
+	}
+	return &linkChecker{
+		httpClient: httpClient,
+		userAgent:  cfg.UserAgentFor,
+		links:      make(map[string]*linkStatus),
+		fetched:    make(map[string]linkOutcome),
+	}
+}
+
+// refer records referrer as linking to target, keyed by its canonical URL,
+// and returns the link.
+func (c *linkChecker) refer(key string, referrer url.URL, target url.URL, inScope bool) *linkStatus {
+	link, ok := c.links[key]
+	if !ok {
+		target.Fragment = ""
+		target.RawFragment = ""
+		link = &linkStatus{target: target, inScope: inScope}
+		c.links[key] = link
+		c.order = append(c.order, key)
+	}
+	from := referrer.String()
+	for _, known := range link.referrers {
+		if known == from {
+			return link
+		}
+	}
+	link.referrers = append(link.referrers, from)
+	return link
+}
+
+// recordFetch records the outcome of the crawl fetching the page at the
+// canonical URL key. A page fetched more than once keeps its last outcome.
+func (c *linkChecker) recordFetch(key string, outcome linkOutcome) {
+	c.fetched[key] = outcome
+}
+
+// outcomeOf returns the outcome of the link at key: its own check, or the
+// crawl's fetch of it.
+func (c *linkChecker) outcomeOf(key string) (linkOutcome, bool) {
+	if link := c.links[key]; link.outcome != nil {
+		return *link.outcome, true
+	}
+	outcome, ok := c.fetched[key]
+	return outcome, ok
+}
+
+// probe returns the outcome of target answering HEAD, or GET when HEAD is
+// not supported, after waiting on limiter for target's host.
+func (c *linkChecker) probe(ctx context.Context, limiter ratelimiter.RateLimiter, target url.URL) (linkOutcome, error) {
+	if err := limiter.Wait(ctx, target.Host); err != nil {
+		return linkOutcome{}, err
+	}
+	status, err := c.request(ctx, http.MethodHead, target)
+	if err == nil && (status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented) {
+		status, err = c.request(ctx, http.MethodGet, target)
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return linkOutcome{}, ctxErr
+	}
+	if err != nil {
+		return linkOutcome{err: err.Error()}, nil
+	}
+	return linkOutcome{status: status}, nil
+}
+
+func (c *linkChecker) request(ctx context.Context, method string, target url.URL) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", c.userAgent(target.Host))
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
+
+// report returns the checked link count and the broken links, in
+// discovery order. Links without an outcome are left out.
+func (c *linkChecker) report() LinkCheckReport {
+	report := LinkCheckReport{
+		BrokenLinks: []BrokenLink{},
+	}
+	for _, key := range c.order {
+		outcome, ok := c.outcomeOf(key)
+		if !ok {
+			continue
+		}
+		report.CheckedLinks++
+		if !outcome.broken() {
+			continue
+		}
+		report.BrokenLinks = append(report.BrokenLinks, BrokenLink{
+			URL:       key,
+			Status:    outcome.status,
+			Error:     outcome.err,
+			Referrers: append([]string(nil), c.links[key].referrers...),
+		})
+	}
+	return report
+}
+
+// checkLinks records the links of a fetched page, probes the out-of-scope
+// ones and submits the in-scope ones to the frontier. It returns the number
+// of submission errors.
+func (s *Scheduler) checkLinks(cfg config.Config, token frontier.CrawlToken, fetchResult fetcher.FetchResult) int {
+	pageURL := fetchResult.FinalURL()
+	links := s.dedupeCanonical(documentLinks(pageURL, fetchResult.Body()))
+	for _, link := range links {
+		canonical := s.canonicalize(link)
+		key := canonical.String()
+		checked := s.linkChecker.refer(key, pageURL, link, s.isInScope(link))
+		if checked.inScope || checked.outcome != nil {
+			continue
+		}
+		outcome, err := s.linkChecker.probe(s.crawlContext(), s.rateLimiter, checked.target)
+		if err != nil {
+			// The crawl context ended; the link is left unchecked
+			continue
+		}
+		checked.outcome = &outcome
+	}
+	if !s.followsLinks(cfg) {
+		return 0
+	}
+
+	submissionErrors := 0
+	for _, link := range s.filterInScope(links, token.Depth()+1) {
+		if submissionErr := s.submitLink(pageURL, link, token.Depth()+1); submissionErr != nil {
+			if robotsErr, ok := submissionErr.(*robots.RobotsError); ok {
+				s.recordRobotsErrorAndBackoff(robotsErr, link)
+			}
+			submissionErrors++
+		}
+	}
+	return submissionErrors
+}
+
+// recordLinkFetch records the outcome of the crawl fetching token for the
+// link check, so links to the page take their status from it.
+func (s *Scheduler) recordLinkFetch(token frontier.CrawlToken, fetchResult fetcher.FetchResult, err failure.ClassifiedError) {
+	if s.linkChecker == nil {
+		return
+	}
+	outcome := linkOutcome{status: fetchResult.Code()}
+	if err != nil {
+		outcome = linkOutcome{err: err.Error()}
+		var fetchErr *fetcher.FetchError
+		if errors.As(err, &fetchErr) && fetchErr.StatusCode != 0 {
+			outcome = linkOutcome{status: fetchErr.StatusCode}
+		}
+	}
+	canonical := s.canonicalize(token.URL())
+	s.linkChecker.recordFetch(canonical.String(), outcome)
+}
+
+// checkUnfetchedLinks probes the in-scope links the crawl did not fetch,
+// skipping those robots.txt disallows, and returns the link check report.
+func (s *Scheduler) checkUnfetchedLinks() LinkCheckReport {
+	ctx := s.crawlContext()
+	for _, key := range s.linkChecker.order {
+		if _, ok := s.linkChecker.outcomeOf(key); ok {
+			continue
+		}
+		link := s.linkChecker.links[key]
+		decision, robotsErr := s.robot.Decide(link.target)
+		if robotsErr != nil || !decision.Allowed {
+			continue
+		}
+		outcome, err := s.linkChecker.probe(ctx, s.rateLimiter, link.target)
+		if err != nil {
+			break
+		}
+		link.outcome = &outcome
+	}
+	return s.linkChecker.report()
+}
+
+// documentLinks returns the http(s) targets of the <a href> elements of
+// body, resolved against pageURL, in document order.
+func documentLinks(pageURL url.URL, body []byte) []url.URL {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return nil
+	}
+	var links []url.URL
+	doc.Find("a[href]").Each(func(_ int, a *goquery.Selection) {
+		href, _ := a.Attr("href")
+		ref, err := url.Parse(strings.TrimSpace(href))
+		if err != nil {
+			return
+		}
+		resolved := pageURL.ResolveReference(ref)
+		if resolved.Scheme != "http" && resolved.Scheme != "https" {
+			return
+		}
+		links = append(links, *resolved)
+	})
+	return links
+}
+
+// writeLinkCheckReport writes report to outputDir as linkcheck.json.
+func writeLinkCheckReport(outputDir string, report LinkCheckReport) error {
+	encoded, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode link check report: %w", err)
+	}
+	if err := fileutil.EnsureDir(outputDir); err != nil {
+		return err
+	}
+	path := filepath.Join(outputDir, LinkCheckFileName)
+	if err := os.WriteFile(path, append(encoded, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write link check report %s: %w", path, err)
+	}
+	return nil
+}
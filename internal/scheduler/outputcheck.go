@@ -0,0 +1,83 @@
+package scheduler
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/rohmanhakim/docs-crawler/pkg/fileutil"
+)
+
+/*
+Output Pre-flight Check
+
+A crawl that cannot write its output would otherwise fetch every page
+before failing at the first write. Before anything is fetched, the output
+directory is created if needed and a probe file is written to it and
+removed again. Any failure aborts initialization with an error matching
+ErrOutputNotWritable (with errors.Is).
+
+Only the writers that put files on disk (storage.LocalSink and
+storage.SingleFileWriter) are checked. Dry runs and writers set with
+SetWriter, such as storage.MemoryWriter, skip the check.
+*/
+
+// probeFilePattern names the temporary file written by the check.
+const probeFilePattern = ".write-probe-*"
+
+// checkOutputWritable creates dir if needed and writes and deletes a probe
+// file in it.
+func checkOutputWritable(dir string) error {
+	if err := fileutil.EnsureDir(dir); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrOutputNotWritable, dir, err)
+	}
+	probe, err := os.CreateTemp(dir, probeFilePattern)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrOutputNotWritable, dir, err)
+	}
+	_, writeErr := probe.Write([]byte("probe"))
+	closeErr := probe.Close()
+	removeErr := os.Remove(probe.Name())
+	for _, err := range []error{writeErr, closeErr, removeErr} {
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrOutputNotWritable, dir, err)
+		}
+	}
+	return nil
+}
+
+// writesToDisk reports whether w writes its files to the local filesystem.
+func writesToDisk(w storage.Writer) bool {
+	switch w.(type) {
+	case *storage.LocalSink, *storage.SingleFileWriter:
+		return true
+	default:
+		return false
+	}
+}
+
+// preflightOutput runs checkOutputWritable for the configured output
+// directory unless cfg.DryRun() is set or the storage writer does not
+// write to disk, recording any failure.
+func (s *Scheduler) preflightOutput(cfg config.Config) error {
+	if cfg.DryRun() || !writesToDisk(s.storageSink) {
+		return nil
+	}
+	err := checkOutputWritable(cfg.OutputDir())
+	if err != nil {
+		s.metadataSink.RecordError(metadata.NewErrorRecord(
+			time.Now(),
+			"scheduler",
+			"checkOutputWritable",
+			metadata.CauseStorageFailure,
+			err.Error(),
+			[]metadata.Attribute{
+				metadata.NewAttr(metadata.AttrPath, cfg.OutputDir()),
+			},
+		))
+	}
+	return err
+}
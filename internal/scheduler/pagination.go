@@ -0,0 +1,101 @@
+package scheduler
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// paginationLinks returns the rel="next" and rel="prev" targets a page
+// declares, from its Link response headers followed by its <head> <link>
+// elements, resolved against pageURL. Order is declaration order;
+// duplicates are left to the caller.
+func paginationLinks(pageURL url.URL, headers http.Header, body []byte) []url.URL {
+	var hrefs []string
+	for _, value := range headers.Values("Link") {
+		hrefs = append(hrefs, parseLinkHeader(value)...)
+	}
+	hrefs = append(hrefs, headPaginationHrefs(body)...)
+
+	links := make([]url.URL, 0, len(hrefs))
+	for _, href := range hrefs {
+		ref, err := url.Parse(strings.TrimSpace(href))
+		if err != nil {
+			continue
+		}
+		resolved := pageURL.ResolveReference(ref)
+		if resolved.Scheme != "http" && resolved.Scheme != "https" {
+			continue
+		}
+		links = append(links, *resolved)
+	}
+	return links
+}
+
+// parseLinkHeader extracts the targets of pagination relations from an
+// RFC 8288 Link header value such as `<https://x/2>; rel="next", <...>; rel=prev`.
+func parseLinkHeader(value string) []string {
+	var hrefs []string
+	for {
+		start := strings.IndexByte(value, '<')
+		if start < 0 {
+			return hrefs
+		}
+		end := strings.IndexByte(value[start:], '>')
+		if end < 0 {
+			return hrefs
+		}
+		target := value[start+1 : start+end]
+		value = value[start+end+1:]
+
+		// Parameters run until the next link-value
+		params := value
+		if next := strings.IndexByte(value, '<'); next >= 0 {
+			params = value[:next]
+		}
+		for _, param := range strings.Split(params, ";") {
+			name, rel, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || !strings.EqualFold(strings.TrimSpace(name), "rel") {
+				continue
+			}
+			rel = strings.Trim(strings.TrimSpace(rel), `",`)
+			if isPaginationRel(rel) {
+				hrefs = append(hrefs, target)
+			}
+			break
+		}
+	}
+}
+
+// headPaginationHrefs returns the hrefs of <link rel="next|prev"> elements
+// in the document head.
+func headPaginationHrefs(body []byte) []string {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return nil
+	}
+	var hrefs []string
+	doc.Find("head link[rel][href]").Each(func(_ int, sel *goquery.Selection) {
+		rel, _ := sel.Attr("rel")
+		href, _ := sel.Attr("href")
+		if isPaginationRel(rel) && strings.TrimSpace(href) != "" {
+			hrefs = append(hrefs, href)
+		}
+	})
+	return hrefs
+}
+
+// isPaginationRel reports whether a space-separated rel value contains
+// next, prev, or its synonym previous.
+func isPaginationRel(rel string) bool {
+	for _, token := range strings.Fields(strings.ToLower(rel)) {
+		switch token {
+		case "next", "prev", "previous":
+			return true
+		}
+	}
+	return false
+}
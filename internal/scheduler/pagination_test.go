@@ -0,0 +1,32 @@
+package scheduler
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLinkHeader(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  []string
+	}{
+		{"quoted next", `<https://x.test/2>; rel="next"`, []string{"https://x.test/2"}},
+		{"unquoted prev", `</1>; rel=prev`, []string{"/1"}},
+		{"previous synonym", `</1>; rel="previous"`, []string{"/1"}},
+		{"multi-token rel", `</2>; rel="next archives"`, []string{"/2"}},
+		{"case insensitive", `</2>; REL="Next"`, []string{"/2"}},
+		{"ignores other rels", `</a.css>; rel="stylesheet", </2>; rel="next"`, []string{"/2"}},
+		{"title before rel", `</2>; title="a, b"; rel="next"`, []string{"/2"}},
+		{"no rel", `</2>; type="text/html"`, nil},
+		{"malformed", `</2; rel="next"`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseLinkHeader(tt.value); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseLinkHeader(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
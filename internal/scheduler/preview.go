@@ -0,0 +1,156 @@
+package scheduler
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/robots"
+	"github.com/rohmanhakim/docs-crawler/pkg/urlutil"
+)
+
+/*
+Scope Preview
+
+PreviewScope answers "what would a crawl with this config admit?" without
+touching the network. robots.txt bodies are supplied by the caller, keyed by
+URL host (including the port, if any); hosts without an entry are treated
+like a missing robots.txt, which allows everything.
+
+Seeds bypass the allowed-host filter during a crawl, so a seed is allowed
+exactly when robots.txt allows it. The matching hosts and path prefixes are
+reported to show which configured entries would admit the seed's
+neighbourhood once discovery starts.
+*/
+
+// SeedPreview is the offline scope verdict for one seed URL.
+type SeedPreview struct {
+	url              url.URL
+	allowed          bool
+	reason           robots.DecisionReason
+	robotsProvided   bool
+	userAgent        string
+	matchingHosts    []string
+	matchingPrefixes []string
+	err              error
+}
+
+// URL returns the canonicalized seed URL that was evaluated.
+func (p SeedPreview) URL() url.URL {
+	return p.url
+}
+
+// Allowed reports whether robots.txt would admit the seed.
+func (p SeedPreview) Allowed() bool {
+	return p.allowed
+}
+
+// Reason returns the robots decision reason.
+func (p SeedPreview) Reason() robots.DecisionReason {
+	return p.reason
+}
+
+// RobotsProvided reports whether robots.txt content was supplied for the seed's host.
+func (p SeedPreview) RobotsProvided() bool {
+	return p.robotsProvided
+}
+
+// UserAgent returns the user agent the seed was evaluated as.
+func (p SeedPreview) UserAgent() string {
+	return p.userAgent
+}
+
+// MatchingHosts returns the allowed host entries that admit the seed's host.
+// It is empty when no allowed hosts are configured.
+func (p SeedPreview) MatchingHosts() []string {
+	return p.matchingHosts
+}
+
+// MatchingPrefixes returns the allowed path prefixes the seed's path starts with.
+func (p SeedPreview) MatchingPrefixes() []string {
+	return p.matchingPrefixes
+}
+
+// Err returns the robots evaluation error, if any. Seeds with an error are not allowed.
+func (p SeedPreview) Err() error {
+	return p.err
+}
+
+// ScopePreview holds the offline scope verdicts for every configured seed,
+// in seed order.
+type ScopePreview struct {
+	seeds []SeedPreview
+}
+
+// Seeds returns the per-seed verdicts.
+func (p ScopePreview) Seeds() []SeedPreview {
+	return append([]SeedPreview(nil), p.seeds...)
+}
+
+// Allowed returns the seeds robots.txt would admit.
+func (p ScopePreview) Allowed() []url.URL {
+	return p.filter(true)
+}
+
+// Blocked returns the seeds robots.txt would reject.
+func (p ScopePreview) Blocked() []url.URL {
+	return p.filter(false)
+}
+
+func (p ScopePreview) filter(allowed bool) []url.URL {
+	var urls []url.URL
+	for _, seed := range p.seeds {
+		if seed.allowed == allowed {
+			urls = append(urls, seed.url)
+		}
+	}
+	return urls
+}
+
+// PreviewScope evaluates every seed in cfg against the supplied robots.txt
+// bodies and the configured hosts and path prefixes, without network access.
+func PreviewScope(cfg config.Config, robotsContent map[string]string) ScopePreview {
+	var preview ScopePreview
+	for _, seed := range cfg.SeedURLs() {
+		canonicalURL := urlutil.Canonicalize(seed)
+		content, provided := robotsContent[canonicalURL.Host]
+
+		seedPreview := SeedPreview{
+			url:              canonicalURL,
+			robotsProvided:   provided,
+			userAgent:        cfg.UserAgentFor(canonicalURL.Host),
+			matchingHosts:    cfg.HostMatcher().MatchingEntries(canonicalURL.Host),
+			matchingPrefixes: matchingPathPrefixes(canonicalURL.Path, cfg.AllowedPathPrefix()),
+		}
+
+		decision, err := robots.DecideFromContent(content, seedPreview.userAgent, canonicalURL)
+		if err != nil {
+			seedPreview.err = err
+		} else {
+			seedPreview.allowed = decision.Allowed
+			seedPreview.reason = decision.Reason
+		}
+		preview.seeds = append(preview.seeds, seedPreview)
+	}
+	return preview
+}
+
+// matchingPathPrefixes returns the prefixes path starts with. Prefixes
+// without a leading slash are matched as if they had one, as in section
+// derivation.
+func matchingPathPrefixes(path string, prefixes []string) []string {
+	var matches []string
+	for _, prefix := range prefixes {
+		if prefix == "" {
+			continue
+		}
+		normalized := prefix
+		if !strings.HasPrefix(normalized, "/") {
+			normalized = "/" + normalized
+		}
+		if strings.HasPrefix(path, normalized) {
+			matches = append(matches, prefix)
+		}
+	}
+	return matches
+}
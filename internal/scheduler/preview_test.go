@@ -0,0 +1,74 @@
+package scheduler_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/robots"
+	"github.com/rohmanhakim/docs-crawler/internal/scheduler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingTransport fails the test on any HTTP round trip.
+type failingTransport struct {
+	t *testing.T
+}
+
+func (f failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.t.Errorf("unexpected HTTP request to %s", req.URL)
+	return nil, http.ErrHandlerTimeout
+}
+
+func TestPreviewScope_ClassifiesSeedsOffline(t *testing.T) {
+	original := http.DefaultTransport
+	http.DefaultTransport = failingTransport{t: t}
+	defer func() { http.DefaultTransport = original }()
+
+	// GIVEN seeds on three hosts, two of which have canned robots.txt
+	seeds := []url.URL{
+		*mustParseURL("https://docs.example.com/guide/intro"),
+		*mustParseURL("https://docs.example.com/private/notes"),
+		*mustParseURL("https://api.example.com/reference"),
+		*mustParseURL("https://other.org/docs/start"),
+	}
+	robotsContent := map[string]string{
+		"docs.example.com": "User-agent: *\nDisallow: /private/\n",
+		"api.example.com":  "User-agent: docs-crawler\nDisallow: /\n",
+	}
+	cfg, err := config.WithDefault(seeds).
+		WithAllowedHosts(map[string]struct{}{"*.example.com": {}, "docs.example.com": {}}).
+		WithAllowedPathPrefix([]string{"/guide", "docs"}).
+		Build()
+	require.NoError(t, err)
+
+	// WHEN previewing the crawl scope
+	preview := scheduler.PreviewScope(cfg, robotsContent)
+
+	// THEN each seed is classified by its host's robots.txt
+	previews := preview.Seeds()
+	require.Len(t, previews, 4)
+
+	assert.True(t, previews[0].Allowed())
+	assert.Equal(t, []string{"docs.example.com", "*.example.com"}, previews[0].MatchingHosts())
+	assert.Equal(t, []string{"/guide"}, previews[0].MatchingPrefixes())
+
+	assert.False(t, previews[1].Allowed())
+	assert.Equal(t, robots.DisallowedByRobots, previews[1].Reason())
+	assert.Empty(t, previews[1].MatchingPrefixes())
+
+	assert.False(t, previews[2].Allowed())
+	assert.Equal(t, []string{"*.example.com"}, previews[2].MatchingHosts())
+
+	// AND a host without robots content is treated as having none
+	assert.True(t, previews[3].Allowed())
+	assert.False(t, previews[3].RobotsProvided())
+	assert.Equal(t, robots.EmptyRuleSet, previews[3].Reason())
+	assert.Empty(t, previews[3].MatchingHosts())
+	assert.Equal(t, []string{"docs"}, previews[3].MatchingPrefixes())
+
+	assert.Equal(t, []url.URL{previews[0].URL(), previews[3].URL()}, preview.Allowed())
+	assert.Equal(t, []url.URL{previews[1].URL(), previews[2].URL()}, preview.Blocked())
+}
@@ -0,0 +1,54 @@
+package scheduler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/rohmanhakim/docs-crawler/pkg/hashutil"
+)
+
+// saveRawHTML saves the fetched body of a page to the raw HTML archive
+// when config.SaveRawHTML is set (see storage.RawHTMLPath). The archive is
+// a debugging aid, so a failed save is recorded but does not fail the page.
+func (s *Scheduler) saveRawHTML(cfg config.Config, fetchResult fetcher.FetchResult) {
+	if !cfg.SaveRawHTML() || cfg.DryRun() {
+		return
+	}
+	pageURL := fetchResult.URL()
+	canonical := canonicalizeFor(cfg, pageURL)
+	path, err := storage.RawHTMLPath(cfg.OutputDir(), canonical.String(), cfg.HashAlgo())
+	if err == nil {
+		err = storage.WriteRawHTML(path, fetchResult.Body())
+	}
+	if err != nil {
+		var storageError *storage.StorageError
+		errors.As(err, &storageError)
+		s.metadataSink.RecordError(metadata.NewErrorRecord(
+			time.Now(),
+			"scheduler",
+			"saveRawHTML",
+			metadata.CauseStorageFailure,
+			err.Error(),
+			[]metadata.Attribute{
+				metadata.NewAttr(metadata.AttrURL, pageURL.String()),
+				metadata.NewAttr(metadata.AttrWritePath, storageError.Path),
+			},
+		))
+		return
+	}
+
+	contentHash, _ := hashutil.HashBytes(fetchResult.Body(), cfg.HashAlgo())
+	s.metadataSink.RecordArtifact(metadata.NewArtifactRecord(
+		metadata.ArtifactRawHTML,
+		path,
+		pageURL.String(),
+		contentHash,
+		false,
+		int64(len(fetchResult.Body())),
+		time.Now(),
+	))
+}
@@ -0,0 +1,59 @@
+package scheduler
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/frontier"
+)
+
+/*
+Page Re-crawl
+
+RecrawlPages re-fetches a fixed list of pages, such as the pages behind the
+missing or corrupt entries found by verify. Every page is admitted as a seed
+at depth 0 through the usual robots checks, and the page limits of cfg are
+lifted so none of them is cut off. Links and pagination found on the pages
+are not followed, and the pages are crawled once even when cfg repeats.
+
+No manifest is written: the results only cover part of the original run, so
+the caller merges them into its own manifest.
+*/
+
+// RecrawlPages re-fetches and rewrites exactly pages using cfg. The returned
+// execution's write results cover the pages that were rewritten; pages that
+// fail are counted as errors like in a regular crawl.
+func (s *Scheduler) RecrawlPages(cfg config.Config, pages []url.URL) (CrawlingExecution, error) {
+	if len(pages) == 0 {
+		return CrawlingExecution{}, nil
+	}
+	s.recrawlOnly = true
+
+	recrawlCfg := cfg
+	recrawlCfg.WithSeedUrls(pages[:1]).
+		WithMaxPages(0).
+		WithMaxPagesPerDepth(0).
+		WithMaxIterations(1)
+
+	init, err := s.InitializeWithConfig(recrawlCfg)
+	if err != nil {
+		return CrawlingExecution{}, fmt.Errorf("error initializing re-crawl: %w", err)
+	}
+
+	candidates := make([]frontier.CrawlAdmissionCandidate, 0, len(pages)-1)
+	for _, page := range pages[1:] {
+		candidates = append(candidates, frontier.NewCrawlAdmissionCandidate(
+			page,
+			frontier.SourceSeed,
+			frontier.NewDiscoveryMetadata(0, nil),
+		))
+	}
+	// Pages that can no longer be admitted are skipped; the rest are still re-crawled
+	if seedErr := s.SeedFrontier(candidates); seedErr != nil {
+		log.Printf("failed to admit page for re-crawl: %v", seedErr)
+	}
+
+	return s.ExecuteCrawlingWithState(init)
+}
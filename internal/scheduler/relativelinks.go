@@ -0,0 +1,83 @@
+package scheduler
+
+import (
+	"net/url"
+	"path/filepath"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/frontier"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+)
+
+/*
+Relative Links
+
+With config.RelativeLinks, links between crawled pages are rewritten by the
+normalizer to the relative path between the output files. The scheduler
+supplies the resolver, which knows a target's output path when:
+- the target has already been written (or aliased to a written page), and
+  its path was recorded by the crawl loop
+- the target has been admitted to the frontier but not written yet; its
+  path is predicted from the file naming scheme, in the output directory of
+  the current page
+
+A prediction cannot know the language partition of a page not fetched yet,
+so with config.PartitionByLanguage only written pages are resolved. Every
+other link is left to the normalizer as an absolute URL.
+*/
+
+// pageOutputPath returns the path the page at pageURL, or the article when
+// pageURL has a fragment, is written to in outputDir.
+func pageOutputPath(cfg config.Config, outputDir string, pageURL url.URL) (string, bool) {
+	canonical := keepArticleFragment(canonicalizeFor(cfg, pageURL), pageURL)
+	name, err := storage.PageFileName(canonical.String(), cfg.HashAlgo())
+	if err != nil {
+		return "", false
+	}
+	return filepath.Join(outputDir, name), true
+}
+
+// recordPagePath records the output path of the page at pageURL. An
+// article is recorded under its own URL, and under the page URL unless an
+// earlier article of the page was, so links to the page resolve to its
+// first article.
+func (s *Scheduler) recordPagePath(pageURL url.URL, path string) {
+	if s.pagePaths == nil {
+		s.pagePaths = make(map[string]string)
+	}
+	canonical := s.canonicalize(pageURL)
+	if pageURL.Fragment != "" {
+		article := keepArticleFragment(canonical, pageURL)
+		s.pagePaths[article.String()] = path
+		if _, ok := s.pagePaths[canonical.String()]; ok {
+			return
+		}
+	}
+	s.pagePaths[canonical.String()] = path
+}
+
+// linkResolver returns the resolver of internal links for a page written
+// to outputDir.
+func (s *Scheduler) linkResolver(cfg config.Config, outputDir string) func(url.URL) (string, bool) {
+	return func(target url.URL) (string, bool) {
+		canonical := s.canonicalize(target)
+		// A fragment naming an article resolves to the article's file
+		if target.Fragment != "" {
+			article := keepArticleFragment(canonical, target)
+			if path, ok := s.pagePaths[article.String()]; ok {
+				return path, true
+			}
+		}
+		if path, ok := s.pagePaths[canonical.String()]; ok {
+			return path, true
+		}
+		if cfg.PartitionByLanguage() {
+			return "", false
+		}
+		crawlFrontier, ok := s.frontier.(*frontier.CrawlFrontier)
+		if !ok || !crawlFrontier.IsAdmitted(target) {
+			return "", false
+		}
+		return pageOutputPath(cfg, outputDir, canonical)
+	}
+}
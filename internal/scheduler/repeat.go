@@ -0,0 +1,171 @@
+package scheduler
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/frontier"
+	"github.com/rohmanhakim/docs-crawler/internal/robots"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/rohmanhakim/docs-crawler/pkg/debug"
+)
+
+/*
+Repeated Crawls
+
+For monitoring a documentation site, config.MaxIterations runs the whole
+crawl several times in one invocation, waiting config.RepeatInterval between
+the end of a pass and the start of the next. Every pass starts over from
+the seeds with an empty frontier, so pages added or removed since the last
+pass are picked up.
+
+Passes after the first are incremental: a page whose normalized content
+hash matches the previous pass keeps its files from that pass and is not
+written again. Only new and changed pages are rewritten. The manifest still
+lists every page of the pass, and a combined single-file export is
+rewritten every pass with the unchanged pages included.
+
+With MaxIterations 0 the crawl repeats until the crawl context is done;
+cancelling it while waiting for the next pass ends the crawl with the last
+complete pass. A pass stopped by a crawl budget, the first match or the
+crawl window ends the crawl too, with no further passes. The returned execution is the last pass, with the stats of
+every pass in Iterations.
+*/
+
+// IterationStats are the stats of one crawl pass.
+type IterationStats struct {
+	// Iteration is the 1-based number of the pass
+	Iteration int
+	StartedAt time.Time
+	Duration  time.Duration
+	// VisitedPages is the number of URLs admitted by the pass
+	VisitedPages int
+	// WrittenPages is the number of pages written by the pass
+	WrittenPages int
+	// UnchangedPages is the number of pages kept from the previous pass
+	UnchangedPages int
+	Errors         int
+	StopReason     StopReason
+}
+
+// passPage is a page written by a crawl pass.
+type passPage struct {
+	contentHash string
+	writeResult storage.WriteResult
+}
+
+// executeIterations crawls the frontier seeded by init, then repeats the
+// crawl from the seeds until cfg.MaxIterations passes have run.
+func (s *Scheduler) executeIterations(init *CrawlInitialization) (CrawlingExecution, error) {
+	cfg := init.config
+	var last CrawlingExecution
+	var iterations []IterationStats
+
+	for iteration := 1; ; iteration++ {
+		if iteration > 1 {
+			if err := s.sleeper.Sleep(s.crawlContext(), cfg.RepeatInterval()); err != nil {
+				// Cancelled between passes: the last pass is complete
+				return last, nil
+			}
+			if err := s.restartCrawlPass(cfg); err != nil {
+				return last, fmt.Errorf("error initializing crawl pass %d: %w", iteration, err)
+			}
+		}
+
+		s.previousPages, s.passPages = s.passPages, make(map[string]passPage)
+		startedAt := s.clock.Now()
+		execution, err := s.executeCrawlPass(init)
+
+		stats := IterationStats{
+			Iteration:      iteration,
+			StartedAt:      startedAt,
+			Duration:       s.clock.Now().Sub(startedAt),
+			VisitedPages:   execution.TotalVisitedPages(),
+			WrittenPages:   execution.TotalPages() - execution.UnchangedPages(),
+			UnchangedPages: execution.UnchangedPages(),
+			Errors:         execution.TotalErrors(),
+			StopReason:     execution.StopReason(),
+		}
+		iterations = append(iterations, stats)
+		execution.iterations = append([]IterationStats(nil), iterations...)
+		last = execution
+		s.debugLogger.LogStep(s.crawlContext(), "scheduler", "crawl_pass_done", debug.FieldMap{
+			"iteration":       stats.Iteration,
+			"written_pages":   stats.WrittenPages,
+			"unchanged_pages": stats.UnchangedPages,
+			"errors":          stats.Errors,
+		})
+
+		if err != nil {
+			return execution, err
+		}
+		if execution.StopReason() != StopReasonCompleted {
+			return execution, nil
+		}
+		if maxIterations := cfg.MaxIterations(); maxIterations > 0 && iteration >= maxIterations {
+			return execution, nil
+		}
+	}
+}
+
+// restartCrawlPass prepares the next pass: it forgets the finished pass,
+// starts the combined single-file export over and submits the seed again.
+// The rest of the initialization, such as the HTTP client, the login
+// session and the robots.txt cache, carries over from the first pass.
+func (s *Scheduler) restartCrawlPass(cfg config.Config) error {
+	s.resetCrawlState()
+	s.traps = newTrapDetector(cfg.MaxPatternRepeats())
+	s.linkChecker = newLinkChecker(cfg, s.httpClient)
+	if combined, ok := s.storageSink.(*storage.SingleFileWriter); ok {
+		combined.Restart()
+	}
+
+	seed := cfg.SeedURLs()[0]
+	if err := s.SubmitUrlForAdmission(seed, frontier.SourceSeed, cfg.SeedDepth(seed)); err != nil {
+		if robotsErr, ok := err.(*robots.RobotsError); ok {
+			s.recordRobotsErrorAndBackoff(robotsErr, seed)
+		}
+		return err
+	}
+	return s.rateLimiter.Wait(s.ctx, seed.Host)
+}
+
+// resetCrawlState forgets the pages of the finished pass, so the next pass
+// starts from the seeds.
+func (s *Scheduler) resetCrawlState() {
+	s.writeResults = nil
+	s.writtenContent = nil
+	s.pagePaths = nil
+	s.graph = nil
+	if resettable, ok := s.frontier.(interface{ Reset() }); ok {
+		resettable.Reset()
+	}
+}
+
+// recordPassPage remembers a written or kept page for the next pass.
+func (s *Scheduler) recordPassPage(attempt pageAttempt) {
+	if s.passPages == nil {
+		return
+	}
+	canonical := keepArticleFragment(s.canonicalize(attempt.pageURL), attempt.pageURL)
+	s.passPages[canonical.String()] = passPage{
+		contentHash: attempt.contentHash,
+		writeResult: attempt.writeResult,
+	}
+}
+
+// unchangedPage returns the previous pass's write result of the page at u
+// when its content hash is still contentHash.
+func (s *Scheduler) unchangedPage(u url.URL, contentHash string) (storage.WriteResult, bool) {
+	if s.previousPages == nil {
+		return storage.WriteResult{}, false
+	}
+	canonical := keepArticleFragment(s.canonicalize(u), u)
+	previous, ok := s.previousPages[canonical.String()]
+	if !ok || previous.contentHash != contentHash {
+		return storage.WriteResult{}, false
+	}
+	return previous.writeResult, true
+}
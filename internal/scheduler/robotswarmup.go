@@ -0,0 +1,62 @@
+package scheduler
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/robots"
+)
+
+/*
+Robots Warmup
+
+With config.RobotsWarmup enabled, robots.txt of every seed host and every
+literal allowed host is fetched concurrently before the seed is submitted
+(see robots.CachedRobot.Warmup), so the admission checks of the crawl loop
+find it cached instead of fetching it the first time each host is met.
+
+Allowed hosts use the scheme of a seed on the same host, or of the first
+seed otherwise. Wildcard patterns such as "*.example.com" name no host to
+fetch from and are skipped. The crawler does not read sitemaps, so there
+is nothing else to warm up.
+*/
+
+// warmupRobots pre-fetches robots.txt for the crawl's hosts when enabled.
+// Robots implementations other than CachedRobot are left alone.
+func (s *Scheduler) warmupRobots(cfg config.Config) {
+	if !cfg.RobotsWarmup() {
+		return
+	}
+	robot, ok := s.robot.(*robots.CachedRobot)
+	if !ok {
+		return
+	}
+	robot.Warmup(s.ctx, warmupOrigins(cfg), cfg.Concurrency())
+}
+
+// warmupOrigins returns the seed origins followed by the literal allowed
+// hosts, in sorted order.
+func warmupOrigins(cfg config.Config) []url.URL {
+	seeds := cfg.SeedURLs()
+	schemes := make(map[string]string, len(seeds))
+	var origins []url.URL
+	for _, seed := range seeds {
+		if _, ok := schemes[seed.Host]; !ok {
+			schemes[seed.Host] = seed.Scheme
+		}
+		origins = append(origins, url.URL{Scheme: seed.Scheme, Host: seed.Host})
+	}
+
+	for _, host := range cfg.SortedAllowedHosts() {
+		if strings.Contains(host, "*") {
+			continue
+		}
+		scheme, ok := schemes[host]
+		if !ok {
+			scheme = seeds[0].Scheme
+		}
+		origins = append(origins, url.URL{Scheme: scheme, Host: host})
+	}
+	return origins
+}
@@ -0,0 +1,112 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/pkg/hashutil"
+)
+
+/*
+Run IDs
+
+Every crawl run is identified by a RunID derived from its configuration, not
+from the wall clock: the same seeds and scope always give the same ID, so
+outputs can be traced back to the configuration that produced them and two
+runs can be told apart by what they crawled. The ID is recorded in the
+frontmatter of every page and in every manifest entry.
+
+Only fields that decide which pages are crawled and how they are written
+count. Politeness, retries, output location and debugging options do not,
+nor does the random seed. Seeds and list fields are canonicalized and
+sorted, so listing them in another order gives the same ID. An empty path
+prefix list counts as the default "/", which admits the same paths.
+*/
+
+// runIDLength is the number of hex characters kept from the hash.
+const runIDLength = 16
+
+// runIDFields is the normalized form of the config fields a RunID covers.
+type runIDFields struct {
+	Seeds                []string          `json:"seeds"`
+	AllowedHosts         []string          `json:"allowedHosts"`
+	IncludeSubdomains    bool              `json:"includeSubdomains"`
+	OnlyHost             string            `json:"onlyHost"`
+	AllowedPathPrefix    []string          `json:"allowedPathPrefix"`
+	ExcludedExtensions   []string          `json:"excludedExtensions"`
+	DropQueryStrings     bool              `json:"dropQueryStrings"`
+	AllowedQueryParams   []string          `json:"allowedQueryParams"`
+	SkipLinkTextPatterns []string          `json:"skipLinkTextPatterns"`
+	FollowPagination     bool              `json:"followPagination"`
+	SinglePage           bool              `json:"singlePage"`
+	RedirectOutOfScope   string            `json:"redirectOutOfScope"`
+	MaxDepth             int               `json:"maxDepth"`
+	DepthMode            string            `json:"depthMode"`
+	HostMaxDepth         map[string]int    `json:"hostMaxDepth"`
+	SeedDepths           map[string]int    `json:"seedDepths,omitempty"`
+	MaxPages             int               `json:"maxPages"`
+	MaxPagesPerDepth     int               `json:"maxPagesPerDepth"`
+	HashAlgo             hashutil.HashAlgo `json:"hashAlgo"`
+	MarkdownFlavor       string            `json:"markdownFlavor"`
+	TrailingSlashPolicy  string            `json:"trailingSlashPolicy"`
+}
+
+// RunID returns the deterministic ID of a crawl run with cfg: a hash over
+// its seeds and its scope and output fields.
+func RunID(cfg config.Config) string {
+	var seeds []string
+	// Seeds starting deeper than the root; absent from runs without any
+	var seedDepths map[string]int
+	for _, seed := range cfg.SeedURLs() {
+		canonical := canonicalizeFor(cfg, seed)
+		seeds = append(seeds, canonical.String())
+		if depth := cfg.SeedDepth(seed); depth != 0 {
+			if seedDepths == nil {
+				seedDepths = make(map[string]int)
+			}
+			seedDepths[canonical.String()] = depth
+		}
+	}
+	// No prefix admits every path, like the default "/"
+	prefixes := cfg.AllowedPathPrefix()
+	if len(prefixes) == 0 {
+		prefixes = []string{"/"}
+	}
+
+	fields := runIDFields{
+		Seeds:                sortedCopy(seeds),
+		AllowedHosts:         cfg.SortedAllowedHosts(),
+		IncludeSubdomains:    cfg.IncludeSubdomains(),
+		OnlyHost:             cfg.OnlyHost(),
+		AllowedPathPrefix:    sortedCopy(prefixes),
+		ExcludedExtensions:   sortedCopy(cfg.ExcludedExtensions()),
+		DropQueryStrings:     cfg.DropQueryStrings(),
+		AllowedQueryParams:   sortedCopy(cfg.AllowedQueryParams()),
+		SkipLinkTextPatterns: sortedCopy(cfg.SkipLinkTextPatterns()),
+		FollowPagination:     cfg.FollowPagination(),
+		SinglePage:           cfg.SinglePage(),
+		RedirectOutOfScope:   cfg.RedirectOutOfScope(),
+		MaxDepth:             cfg.MaxDepth(),
+		DepthMode:            cfg.DepthMode(),
+		HostMaxDepth:         cfg.HostMaxDepth(),
+		SeedDepths:           seedDepths,
+		MaxPages:             cfg.MaxPages(),
+		MaxPagesPerDepth:     cfg.MaxPagesPerDepth(),
+		HashAlgo:             cfg.HashAlgo(),
+		MarkdownFlavor:       cfg.MarkdownFlavor(),
+		TrailingSlashPolicy:  cfg.TrailingSlashPolicy(),
+	}
+
+	// Maps are encoded with sorted keys, so the encoding is stable
+	data, _ := json.Marshal(fields)
+	hash, _ := hashutil.HashBytes(data, hashutil.HashAlgoSHA256)
+	return hash[:runIDLength]
+}
+
+// sortedCopy returns a sorted copy of values.
+func sortedCopy(values []string) []string {
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	return sorted
+}
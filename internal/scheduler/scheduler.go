@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/rohmanhakim/docs-crawler/internal/assets"
@@ -27,7 +28,7 @@ import (
 	"github.com/rohmanhakim/docs-crawler/pkg/failurejournal"
 	"github.com/rohmanhakim/docs-crawler/pkg/urlutil"
 	ratelimiter "github.com/rohmanhakim/rate-limiter"
-	"github.com/rohmanhakim/retrier"
+	"go.opentelemetry.io/otel/trace"
 )
 
 /*
@@ -64,6 +65,7 @@ import (
 
 type Scheduler struct {
 	ctx                    context.Context
+	crawlCtx               context.Context // caller-injected crawl context; nil means no crawl deadline
 	httpClient             *http.Client
 	metadataSink           metadata.MetadataSink
 	crawlFinalizer         metadata.CrawlFinalizer
@@ -72,22 +74,53 @@ type Scheduler struct {
 	failureJournal         failurejournal.Journal
 	htmlFetcher            fetcher.Fetcher
 	domExtractor           extractor.Extractor
+	extractors             *extractor.Registry // built on first use, see extractorRegistry
 	htmlSanitizer          sanitizer.Sanitizer
 	markdownConversionRule mdconvert.ConvertRule
 	assetResolver          assets.Resolver
 	markdownConstraint     normalize.Constraint
-	storageSink            storage.Sink
+	storageSink            storage.Writer
 	writeResults           []storage.WriteResult
+	writtenContent         map[string]int    // content hash -> index in writeResults
+	pagePaths              map[string]string // canonical URL -> output path, with config.RelativeLinks
 	currentHost            string
+	hostMatcher            config.HostMatcher
+	extensionMatcher       config.ExtensionMatcher
+	queryFilter            config.QueryFilter
+	trailingSlash          urlutil.TrailingSlashPolicy
+	traps                  *trapDetector // nil unless config.MaxPatternRepeats is set
+	linkChecker            *linkChecker  // nil unless config.LinkCheckOnly is set
 	rateLimiter            ratelimiter.RateLimiter
+	defaultCrawlDelay      time.Duration // floor of robots.txt crawl delays, see config.DefaultCrawlDelay
 	stageDumper            stagedump.Dumper
 	debugLogger            debug.DebugLogger
+	clock                  Clock
+	sleeper                Sleeper
+	transformers           []Transformer
+	beforeFetchHooks       []BeforeFetchHook
+	afterWriteHooks        []AfterWriteHook
+	limiterJitter          *seededJitter // nil when the rate limiter was injected
+	retryJitter            *seededJitter
+	recrawlOnly            bool // set by RecrawlPages: no link discovery, no manifest
+	authenticator          Authenticator
+	urlScorer              URLScorer            // nil unless SetURLScorer was called
+	graph                  []Edge               // links submitted to the frontier, see graph.go
+	runID                  string               // RunID of the config being crawled
+	autoTuner              *autoTuner           // nil unless config.AutoTune is enabled
+	circuitBreaker         *stageCircuitBreaker // nil unless config.CircuitBreakerThreshold is set
+	tracer                 trace.Tracer         // nil unless SetTracer was called
+	pageSpan               trace.Span           // span of the page being processed, nil when not tracing
+	pageSpanCtx            context.Context      // context of pageSpan
+	pause                  *pauseGate           // holds the crawl loop between pages, see Pause
+	passPages              map[string]passPage  // pages written by the current pass when repeating, see repeat.go
+	previousPages          map[string]passPage  // pages written by the previous pass
 }
 
 func NewScheduler() Scheduler {
 	recorder := metadata.NewRecorder("sample-single-sync-worker")
 	cachedRobot := robots.NewCachedRobot(&recorder)
 	frontier := frontier.NewCrawlFrontier()
+	frontier.SetMetadataSink(&recorder)
 	fetcher := fetcher.NewHtmlFetcher(&recorder)
 	ext := extractor.NewDomExtractor(&recorder)
 	sanitizer := sanitizer.NewHTMLSanitizer(&recorder)
@@ -95,7 +128,8 @@ func NewScheduler() Scheduler {
 	resolver := assets.NewLocalResolver(&recorder)
 	markdownConstraint := normalize.NewMarkdownConstraint(&recorder)
 	storageSink := storage.NewLocalSink(&recorder)
-	rateLimiter := ratelimiter.NewConcurrentRateLimiter()
+	limiterJitter := &seededJitter{}
+	rateLimiter := newSeededRateLimiter(ratelimiter.NewConcurrentRateLimiter(), limiterJitter)
 	return Scheduler{
 		metadataSink:           &recorder,
 		crawlFinalizer:         &recorder,
@@ -109,6 +143,10 @@ func NewScheduler() Scheduler {
 		markdownConstraint:     &markdownConstraint,
 		storageSink:            storageSink,
 		rateLimiter:            rateLimiter,
+		limiterJitter:          limiterJitter,
+		clock:                  systemClock{},
+		sleeper:                contextSleeper{},
+		pause:                  newPauseGate(),
 	}
 }
 
@@ -129,13 +167,14 @@ func NewSchedulerWithDeps(
 	rule mdconvert.ConvertRule,
 	resolver assets.Resolver,
 	constraint normalize.Constraint,
-	storageSink storage.Sink,
+	storageSink storage.Writer,
 	failureJournal failurejournal.Journal,
 	stageDumper stagedump.Dumper,
 	debugLogger debug.DebugLogger,
 ) Scheduler {
 	return Scheduler{
 		ctx:                    ctx,
+		crawlCtx:               ctx,
 		metadataSink:           metadataSink,
 		crawlFinalizer:         crawlFinalizer,
 		robot:                  robot,
@@ -151,9 +190,58 @@ func NewSchedulerWithDeps(
 		rateLimiter:            rateLimiter,
 		stageDumper:            stageDumper,
 		debugLogger:            debugLogger,
+		clock:                  systemClock{},
+		sleeper:                contextSleeper{},
+		pause:                  newPauseGate(),
 	}
 }
 
+// initSingleFileOutput wraps the storage backend in a
+// storage.SingleFileWriter when cfg.SingleFileOutput() is set. With
+// cfg.SingleFileOnly() the combined file replaces the per-page files.
+// Dry runs write nothing, so they are left unwrapped.
+func (s *Scheduler) initSingleFileOutput(cfg config.Config) {
+	if cfg.SingleFileOutput() == "" || cfg.DryRun() {
+		return
+	}
+	if _, ok := s.storageSink.(*storage.SingleFileWriter); ok {
+		return
+	}
+	perPage := s.storageSink
+	if cfg.SingleFileOnly() {
+		perPage = nil
+	}
+	writer := storage.NewSingleFileWriter(s.metadataSink, cfg.SingleFileOutput(), perPage)
+	writer.SetDebugLogger(s.debugLogger)
+	s.storageSink = writer
+}
+
+// initFetchCache wraps the page fetcher in a fetcher.CachingFetcher when
+// cfg.FetchCacheDir() is set. Dry runs use the cache too, since it only
+// stores fetched responses, never crawl output.
+func (s *Scheduler) initFetchCache(cfg config.Config) {
+	if cfg.FetchCacheDir() == "" {
+		return
+	}
+	if cached, ok := s.htmlFetcher.(*fetcher.CachingFetcher); ok {
+		cached.SetOfflineOnly(cfg.OfflineOnly())
+		return
+	}
+	cached := fetcher.NewCachingFetcher(s.htmlFetcher, s.metadataSink, cfg.FetchCacheDir(), cfg.FetchCacheTTL())
+	cached.SetOfflineOnly(cfg.OfflineOnly())
+	s.htmlFetcher = &cached
+}
+
+// SetWriter replaces the storage backend used to persist normalized documents,
+// e.g. with storage.NewMemoryWriter() to keep results in memory.
+// If writer is nil, the current backend is kept.
+func (s *Scheduler) SetWriter(writer storage.Writer) {
+	if writer == nil {
+		return
+	}
+	s.storageSink = writer
+}
+
 // SubmitUrlForAdmission performs all semantic checks required for a URL
 // to enter the crawl frontier.
 //
@@ -170,17 +258,53 @@ func (s *Scheduler) SubmitUrlForAdmission(
 	sourceContext frontier.SourceContext,
 	depth int,
 ) failure.ClassifiedError {
+	_, err := s.submitForAdmission(url, sourceContext, frontier.NewDiscoveryMetadata(depth, nil))
+	return err
+}
+
+// submitForAdmission is SubmitUrlForAdmission with full discovery metadata,
+// so callers that carry a tie-break priority keep it on the way to the frontier.
+// It returns the canonical URL handed to the frontier, or nil when the URL
+// was skipped before submission.
+func (s *Scheduler) submitForAdmission(
+	url url.URL,
+	sourceContext frontier.SourceContext,
+	discovery frontier.DiscoveryMetadata,
+) (*url.URL, failure.ClassifiedError) {
+	depth := discovery.Depth()
+
 	// Canonicalize the URL before any checks to ensure:
 	// - Consistent robots.txt enforcement (e.g., /docs/ and /docs are the same)
 	// - Proper deduplication (query params and fragments are normalized)
 	// - Deterministic crawl behavior
-	canonicalURL := urlutil.Canonicalize(url)
+	// Only the query parameters allowed by the config survive, and the
+	// trailing slash follows config.TrailingSlashPolicy.
+	canonicalURL := s.canonicalize(url)
+
+	// Seeds are always in scope; discovered URLs must match an allowed host
+	if sourceContext != frontier.SourceSeed && !s.hostMatcher.Matches(canonicalURL.Host) {
+		s.recordSkip(canonicalURL, metadata.SkipReasonOutOfScope, depth)
+		return nil, nil
+	}
+
+	// Discovered links to archives, images and other binaries are dropped
+	// before robots.txt is consulted or anything is fetched
+	if sourceContext != frontier.SourceSeed && s.extensionMatcher.Matches(canonicalURL.Path) {
+		s.recordSkip(canonicalURL, metadata.SkipReasonExcludedExtension, depth)
+		return nil, nil
+	}
+
+	// Endless numeric URL patterns, such as calendars, are cut off (see traps.go)
+	if sourceContext != frontier.SourceSeed && !s.traps.admit(canonicalURL) {
+		s.recordSkip(canonicalURL, metadata.SkipReasonPatternRepeat, depth)
+		return nil, nil
+	}
 
 	// Fetch robots.txt using the canonicalized URL
 	robotsDecision, robotsError := s.robot.Decide(canonicalURL)
 	// Robots infrastructure failure → scheduler-level error
 	if robotsError != nil {
-		return robotsError
+		return nil, robotsError
 	}
 
 	// Reset backoff after successful robots request
@@ -188,8 +312,12 @@ func (s *Scheduler) SubmitUrlForAdmission(
 		s.rateLimiter.ResetBackoff(canonicalURL.Host)
 	}
 
-	if robotsDecision.CrawlDelay > 0 && s.rateLimiter != nil {
-		s.rateLimiter.SetResourceDelay(s.currentHost, robotsDecision.CrawlDelay)
+	// Hosts without a robots.txt Crawl-delay get the configured default,
+	// which also floors the delays robots.txt sets. The delay belongs to
+	// the URL's own host, which differs from the seed host in a multi-host scope.
+	crawlDelay := max(robotsDecision.CrawlDelay, s.defaultCrawlDelay)
+	if crawlDelay > 0 && s.rateLimiter != nil {
+		s.rateLimiter.SetResourceDelay(canonicalURL.Host, crawlDelay)
 	}
 
 	// Robots explicitly disallowed -> normal, terminal outcome
@@ -199,12 +327,13 @@ func (s *Scheduler) SubmitUrlForAdmission(
 		// - NO retry
 		// - NO abort
 		// - NO frontier submission
-		s.metadataSink.RecordSkip(metadata.NewSkipEvent(
-			canonicalURL.String(),
-			metadata.SkipReasonRobotsDisallow,
-			time.Now(),
-		))
-		return nil
+		s.recordSkip(canonicalURL, metadata.SkipReasonRobotsDisallow, depth)
+		return nil, nil
+	}
+
+	// Rate the URL for best-first ordering
+	if s.urlScorer != nil {
+		discovery = discovery.WithScore(s.urlScorer(robotsDecision.Url, depth))
 	}
 
 	// Only submit to frontier if robots allowed
@@ -212,15 +341,44 @@ func (s *Scheduler) SubmitUrlForAdmission(
 	candidate := frontier.NewCrawlAdmissionCandidate(
 		robotsDecision.Url,
 		sourceContext,
-		frontier.NewDiscoveryMetadata(
-			depth,
-			nil,
-		),
+		discovery,
 	)
 
 	// Submit Allowed URL for Admission by Frontier
 	s.frontier.Submit(candidate)
-	return nil
+	admitted := robotsDecision.Url
+	return &admitted, nil
+}
+
+// canonicalize returns the canonical form of u: only the query parameters
+// the config allows survive, and the trailing slash follows
+// config.TrailingSlashPolicy.
+func (s *Scheduler) canonicalize(u url.URL) url.URL {
+	canonical := urlutil.CanonicalizeKeepingQuery(u, s.queryFilter.Keeps)
+	return urlutil.ApplyTrailingSlash(canonical, u, s.trailingSlash)
+}
+
+// dedupeCanonical drops the URLs whose canonical form is listed earlier,
+// keeping the first spelling of each in order.
+func (s *Scheduler) dedupeCanonical(urls []url.URL) []url.URL {
+	seen := make(map[string]struct{}, len(urls))
+	deduped := make([]url.URL, 0, len(urls))
+	for _, u := range urls {
+		canonical := s.canonicalize(u)
+		key := canonical.String()
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		deduped = append(deduped, u)
+	}
+	return deduped
+}
+
+// canonicalizeFor is canonicalize for callers holding only the config.
+func canonicalizeFor(cfg config.Config, u url.URL) url.URL {
+	canonical := urlutil.CanonicalizeKeepingQuery(u, cfg.QueryFilter().Keeps)
+	return urlutil.ApplyTrailingSlash(canonical, u, urlutil.TrailingSlashPolicy(cfg.TrailingSlashPolicy()))
 }
 
 // InitializeCrawling performs all initialization steps up to just before the crawl loop.
@@ -299,22 +457,54 @@ func (s *Scheduler) InitializeCrawling(configPath string) (init *CrawlInitializa
 		return nil, err
 	}
 
+	// Fail fast before any fetching when the output cannot be written
+	if err = s.preflightOutput(cfg); err != nil {
+		return nil, err
+	}
+
 	// 1.1 Initialize HTTP Client
 	s.httpClient = createHttpClient(
-		cfg.MaxIdleConns(),
-		cfg.MaxIdleConnsPerHost(),
-		cfg.IdleConnTimeout(),
+		fetcher.TransportOptions{
+			MaxIdleConns:        cfg.MaxIdleConns(),
+			MaxIdleConnsPerHost: cfg.MaxIdleConnsPerHost(),
+			IdleConnTimeout:     cfg.IdleConnTimeout(),
+			HTTP2:               cfg.HTTP2(),
+		},
 		cfg.Timeout(),
 	)
+	// Replayed crawls read robots.txt, pages and assets from the archive
+	if cfg.ReplayArchive() != "" {
+		s.httpClient.Transport = fetcher.NewArchiveTransport(cfg.ReplayArchive())
+	} else if cfg.OfflineOnly() {
+		// Offline-only crawls send no request either: the fetch cache only
+		// holds pages, so anything else is answered as missing
+		s.httpClient.Transport = fetcher.NewArchiveTransport(cfg.FetchCacheDir())
+	} else if err = s.configureTLS(cfg); err != nil {
+		return nil, err
+	}
+
+	// 1.1.1 Log in before anything is fetched, so every request carries the session
+	if err = s.authenticate(); err != nil {
+		return nil, err
+	}
 
 	// 1.2 Initialize rate limiter
 	s.rateLimiter.SetBaseDelay(cfg.BaseDelay())
 	s.rateLimiter.SetJitter(cfg.Jitter())
+	s.defaultCrawlDelay = cfg.DefaultCrawlDelay()
+	s.initJitter(cfg)
+	s.initAutoTune(cfg)
+	s.initCircuitBreaker(cfg)
 
 	// 1.3 Initialize Robots and Frontier
 	s.robot.Init(cfg.UserAgent(), s.httpClient)
+	s.robot.SetHostUserAgents(cfg.HostUserAgents())
+	s.robot.SetOverrides(cfg.RobotsOverride())
 	s.frontier.Init(cfg)
 
+	// Append written pages to the combined single-file export, if configured
+	s.initSingleFileOutput(cfg)
+
 	// 1.4 Configure DOM Extractor with extraction parameters from config
 	extractParam := extractor.ExtractParam{
 		BodySpecificityBias:  cfg.BodySpecificityBias(),
@@ -332,20 +522,53 @@ func (s *Scheduler) InitializeCrawling(configPath string) (init *CrawlInitializa
 			MinParagraphsOrCode: cfg.ThresholdMinParagraphsOrCode(),
 			MaxLinkDensity:      cfg.ThresholdMaxLinkDensity(),
 		},
+		MinTextRatio:      cfg.MinTextRatio(),
 		SelectorBlacklist: cfg.SelectorBlacklist(),
+		NoscriptMode:      extractor.NoscriptMode(cfg.NoscriptMode()),
+		StructuredMode:    cfg.StructuredExtraction(),
+		StripByRole:       cfg.StripByRole(),
+		MultiArticleMode:  extractor.MultiArticleMode(cfg.MultiArticleMode()),
 	}
 	s.domExtractor.SetExtractParam(extractParam)
 
-	// 1.5 Initialize Fetcher
+	// Configure Markdown conversion
+	if rule, ok := s.markdownConversionRule.(*mdconvert.StrictConversionRule); ok {
+		rule.SetImageDensity(cfg.ImageDensity())
+		rule.SetPreserveUnknownHTML(cfg.PreserveUnknownHTML())
+		rule.SetAdmonitionTypes(cfg.AdmonitionTypes())
+		rule.SetSpanStyles(cfg.SpanStyles())
+	}
+
+	// 1.5 Initialize Fetcher, serving pages from the fetch cache if configured
+	s.initFetchCache(cfg)
 	s.htmlFetcher.Init(s.httpClient, cfg.UserAgent())
+	s.htmlFetcher.SetFetchParam(fetcher.FetchParam{
+		PreflightHead:       cfg.PreflightHead(),
+		AllowedContentTypes: cfg.AllowedContentTypes(),
+		MaxResponseBytes:    cfg.MaxResponseBytes(),
+		HostUserAgents:      cfg.HostUserAgents(),
+		TraceRequests:       cfg.TraceRequests(),
+		DefaultHeaders:      cfg.DefaultHeaders(),
+		HeaderRules:         fetchHeaderRules(cfg),
+		RetryJitter:         s.retryJitter.next,
+	})
 
 	// 1.6 Initialize Asset Resolver
 	s.assetResolver.Init(s.httpClient, cfg.UserAgent())
 
+	// 1.7 Pre-fetch robots.txt of every known host, if configured
+	s.warmupRobots(cfg)
+
 	// 2. Fetch robots.txt & decide the crawling policy for this hostname based on that
 	s.currentHost = cfg.SeedURLs()[0].Host
+	s.hostMatcher = cfg.HostMatcher()
+	s.extensionMatcher = cfg.ExtensionMatcher()
+	s.queryFilter = cfg.QueryFilter()
+	s.trailingSlash = urlutil.TrailingSlashPolicy(cfg.TrailingSlashPolicy())
+	s.traps = newTrapDetector(cfg.MaxPatternRepeats())
+	s.linkChecker = newLinkChecker(cfg, s.httpClient)
 	seedScheme := cfg.SeedURLs()[0].Scheme
-	err = s.SubmitUrlForAdmission(cfg.SeedURLs()[0], frontier.SourceSeed, 0)
+	err = s.SubmitUrlForAdmission(cfg.SeedURLs()[0], frontier.SourceSeed, cfg.SeedDepth(cfg.SeedURLs()[0]))
 	if err != nil {
 		// Check if this is a robots error that requires backoff
 		if robotsErr, ok := err.(*robots.RobotsError); ok {
@@ -372,13 +595,38 @@ func (s *Scheduler) InitializeCrawling(configPath string) (init *CrawlInitializa
 // ExecuteCrawlingWithState runs the crawl execution loop using the provided initialization state.
 // This method handles the actual page fetching, extraction, and processing.
 // It manages its own deferred stat recording to ensure accurate execution timing.
+// The crawl stops like a finished one, writing its manifest, once any crawl
+// budget is spent; the execution's StopReason names the budget. When
+// cfg.MaxOutputBytes is exceeded the execution is returned together with an
+// error matching ErrOutputBudgetExceeded. With cfg.MaxIterations other
+// than 1 the whole crawl is repeated, see repeat.go.
 func (s *Scheduler) ExecuteCrawlingWithState(init *CrawlInitialization) (CrawlingExecution, error) {
+	if init.config.MaxIterations() != 1 {
+		return s.executeIterations(init)
+	}
+	return s.executeCrawlPass(init)
+}
+
+// executeCrawlPass crawls the frontier seeded by init once.
+func (s *Scheduler) executeCrawlPass(init *CrawlInitialization) (CrawlingExecution, error) {
 	// Track execution start time for duration calculation
 	execStartTime := time.Now()
 
 	// Statistics tracking
 	var totalErrors int
+	// Pages that failed and were not written, checked against cfg.MaxErrors
+	var failedPages int
 	var totalAssets int
+	// Pages run through the pipeline, checked against cfg.MaxPages
+	var processedPages int
+	// Bytes of pages and assets written, checked against cfg.MaxOutputBytes
+	var outputBytes int64
+	// Pages kept from the previous crawl pass, see repeat.go
+	var unchangedPages int
+	// Set once a page matching cfg.MatchPattern is written
+	var matched bool
+	var budgetErr error
+	stopReason := StopReasonCompleted
 
 	// Ensure the failure journal is flushed to disk on crawl completion,
 	// regardless of whether execution succeeds or fails.
@@ -405,253 +653,747 @@ func (s *Scheduler) ExecuteCrawlingWithState(init *CrawlInitialization) (Crawlin
 	}()
 
 	cfg := init.config
+	s.runID = RunID(cfg)
+
+	// Snapshot the config next to the output, so the run can be reproduced.
+	// A re-crawl only covers part of a run and keeps the run's snapshot;
+	// a link check writes its report only. Later crawl passes of a repeated
+	// crawl keep the first pass's snapshot.
+	if !s.recrawlOnly && s.linkChecker == nil && !cfg.DryRun() && s.previousPages == nil {
+		if snapshot, err := config.EncodeSnapshot(cfg, s.runID); err != nil {
+			log.Printf("failed to encode effective config: %v", err)
+		} else if err := s.storageSink.WriteConfigSnapshot(cfg.OutputDir(), snapshot); err != nil {
+			log.Printf("failed to write effective config: %v", err)
+		}
+	}
 	seedScheme := init.seedScheme
+	pageAttempts := make(map[string]int)
 
 	// If frontier still has URL to be crawl...
 	for {
-		nextCrawlToken, ok := s.frontier.Dequeue()
-		if !ok {
+		// Stop gracefully once any crawl budget is spent
+		if reason, spent := exhaustedBudget(cfg, budgetUsage{
+			pages:       processedPages,
+			outputBytes: outputBytes,
+			failedPages: failedPages,
+			elapsed:     time.Since(execStartTime),
+			matched:     matched,
+		}); spent {
+			stopReason = reason
+			if reason == StopReasonMaxBytes {
+				budgetErr = fmt.Errorf("%w: wrote %d bytes, budget is %d", ErrOutputBudgetExceeded, outputBytes, cfg.MaxOutputBytes())
+			}
+			s.debugLogger.LogStep(s.crawlContext(), "scheduler", "budget_exhausted", debug.FieldMap{
+				"reason": string(reason),
+			})
 			break
 		}
 
-		urlStr := getURLString(nextCrawlToken.URL())
-
-		// Log pipeline start for this URL
-		s.debugLogger.LogStage(s.ctx, "pipeline", debug.StageEvent{
-			Type: debug.EventTypeStart,
-			URL:  urlStr,
-		})
+		// Hold here while paused; the previous page is already done
+		if err := s.waitWhilePaused(); err != nil {
+			return CrawlingExecution{}, err
+		}
 
-		// 3. Fetch Page URL
-		fetchStartTime := time.Now()
-		s.debugLogger.LogStage(s.ctx, "fetcher", debug.StageEvent{
-			Type: debug.EventTypeStart,
-			URL:  urlStr,
-		})
+		nextCrawlToken, ok := s.frontier.Dequeue()
+		if !ok {
+			break
+		}
 
-		fetchResult, err := s.htmlFetcher.Fetch(s.ctx, nextCrawlToken.Depth(), nextCrawlToken.URL(), RetryOptions(cfg))
+		// Pause while outside every crawl window allowed for the page's host
+		tokenURL := nextCrawlToken.URL()
+		open, err := s.waitForCrawlWindow(cfg.CrawlWindows(), tokenURL.Host)
 		if err != nil {
-			if err.Impact() == failure.ImpactLevelAbort {
+			return CrawlingExecution{}, err
+		}
+		if !open {
+			stopReason = StopReasonCrawlWindow
+			break
+		}
+
+		// Deeper pages wait longer than the base delay the rate limiter
+		// already applied
+		if extra := s.depthDelay(cfg, nextCrawlToken.Depth()); extra > 0 {
+			if err := s.sleeper.Sleep(s.ctx, extra); err != nil {
 				return CrawlingExecution{}, err
 			}
-			// Log fetcher error
-			s.debugLogger.LogStage(s.ctx, "fetcher", debug.StageEvent{
-				Type:     debug.EventTypeError,
-				URL:      urlStr,
-				Duration: time.Since(fetchStartTime),
-			})
+		}
+
+		// Run the page pipeline, re-running it from the fetch when the page
+		// fails with a transient (auto-retryable) error and page retries are enabled
+		s.startPageSpan(nextCrawlToken)
+		attempt, attempts, err := s.runPageWithRetry(cfg, seedScheme, nextCrawlToken)
+		s.endPageSpan(attempt, attempts, err)
+		if err != nil {
+			return CrawlingExecution{}, err
+		}
+		if attempts > 1 {
+			pageAttempts[getURLString(nextCrawlToken.URL())] = attempts
+		}
+		processedPages++
+
+		// The articles of a page split into separate outputs are each
+		// recorded like a page
+		for _, output := range append([]pageAttempt{attempt}, attempt.parts...) {
+			totalAssets += output.assets
+			totalErrors += output.errors
+			outputBytes += output.outputBytes
 			// Track for manual retry if eligible
-			if err.RetryPolicy() == failure.RetryPolicyManual {
+			if output.assetErr != nil && output.assetErr.RetryPolicy() == failure.RetryPolicyManual {
 				s.failureJournal.Record(failurejournal.FailureRecord{
 					URL:        getURLString(nextCrawlToken.URL()),
-					Stage:      failurejournal.StageFetch,
-					Error:      err.Error(),
-					RetryCount: 0,
+					Stage:      failurejournal.StageAsset,
+					Error:      output.assetErr.Error(),
+					RetryCount: attempts - 1,
 					Timestamp:  time.Now(),
 				})
 			}
-			// recoverable → log already done → count error
-			totalErrors++
-			continue
+			if output.err != nil {
+				// Track for manual retry if eligible
+				if output.stage != "" && output.err.RetryPolicy() == failure.RetryPolicyManual {
+					s.failureJournal.Record(failurejournal.FailureRecord{
+						URL:        getURLString(nextCrawlToken.URL()),
+						Stage:      output.stage,
+						Error:      output.err.Error(),
+						RetryCount: attempts - 1,
+						Timestamp:  time.Now(),
+					})
+				}
+				// recoverable → log already done → count error
+				totalErrors++
+				failedPages++
+				// Unless strict mode makes it fatal
+				if strictErr := s.strictModeError(cfg, output); strictErr != nil {
+					return CrawlingExecution{}, strictErr
+				}
+				// Abort once the same stage keeps failing page after page
+				if breakerErr := s.observePageFailure(output.stage); breakerErr != nil {
+					return CrawlingExecution{}, breakerErr
+				}
+				continue
+			}
+			// Strict mode also fails the crawl on assets a written page lacks
+			if strictErr := s.strictModeError(cfg, output); strictErr != nil {
+				return CrawlingExecution{}, strictErr
+			}
+			s.observePageSuccess()
+			// A skipped page was fetched but deliberately not written
+			if !output.skipped {
+				if cfg.RelativeLinks() {
+					path := output.writeResult.Path()
+					if output.duplicate {
+						path = s.writeResults[output.duplicateOf].Path()
+					}
+					s.recordPagePath(output.pageURL, path)
+				}
+				if output.duplicate {
+					s.writeResults[output.duplicateOf].AddAlias(output.pageURL)
+				} else {
+					if cfg.DuplicateContent() == "alias" {
+						if s.writtenContent == nil {
+							s.writtenContent = make(map[string]int)
+						}
+						s.writtenContent[output.contentHash] = len(s.writeResults)
+					}
+					s.writeResults = append(s.writeResults, output.writeResult)
+					s.recordPassPage(output)
+					if output.unchanged {
+						unchangedPages++
+					}
+					if output.matched {
+						matched = true
+					}
+				}
+			}
+		}
+
+		// Apply rate limiting delay at the end of the crawl loop using Wait,
+		// keyed by the host the page was fetched from
+		if err := s.rateLimiter.Wait(s.ctx, tokenURL.Host); err != nil {
+			// Context cancelled, exit the loop
+			return CrawlingExecution{}, err
+		}
+	}
+
+	// A link check writes its broken-links report instead of a manifest
+	var brokenLinks []BrokenLink
+	if s.linkChecker != nil {
+		report := s.checkUnfetchedLinks()
+		brokenLinks = report.BrokenLinks
+		if !cfg.DryRun() {
+			if err := writeLinkCheckReport(cfg.OutputDir(), report); err != nil {
+				log.Printf("failed to write link check report: %v", err)
+			}
+		}
+	}
+
+	// Persist the manifest so later runs can diff and verify this one.
+	// A re-crawl only covers part of a run, so its caller merges the results instead.
+	if !s.recrawlOnly && s.linkChecker == nil {
+		if err := s.storageSink.WriteManifest(cfg.OutputDir(), s.writeResults); err != nil {
+			log.Printf("failed to write manifest: %v", err)
+		}
+		// Record which pages reference each asset, for asset garbage collection
+		if resolver, ok := s.assetResolver.(*assets.LocalResolver); ok {
+			if err := resolver.WriteAssetManifest(cfg.OutputDir()); err != nil {
+				log.Printf("failed to write asset manifest: %v", err)
+			}
+		}
+		if cfg.GraphFormat() != "" && !cfg.DryRun() {
+			if err := writeGraph(cfg.OutputDir(), cfg.GraphFormat(), s.graph); err != nil {
+				log.Printf("failed to write crawl graph: %v", err)
+			}
+		}
+	}
+
+	// Stats are recorded by defer - return successful execution result
+	execution := NewCrawlingExecution(s.writeResults, s.frontier.VisitedCount(), totalAssets, totalErrors)
+	execution.pageAttempts = pageAttempts
+	execution.stopReason = stopReason
+	execution.graph = s.graph
+	execution.unchangedPages = unchangedPages
+	execution.brokenLinks = brokenLinks
+	return execution, budgetErr
+}
+
+// pageAttempt is the outcome of one run of the page pipeline.
+type pageAttempt struct {
+	// writeResult is set when the page was written
+	writeResult storage.WriteResult
+	// assets is the number of local assets resolved for the page
+	assets int
+	// outputBytes is the size of the page's Markdown, once written, plus
+	// its other output formats and the assets newly written for it
+	outputBytes int64
+	// errors counts non-fatal errors that did not stop the page
+	errors int
+	// assetErr is the asset resolution error, if any; the page still continues
+	assetErr failure.ClassifiedError
+	// err is the error that stopped the page, if any
+	err failure.ClassifiedError
+	// stage is the failure journal stage of err; empty for deterministic stages
+	stage failurejournal.Stage
+	// contentHash is the hash of the page's normalized content
+	contentHash string
+	// duplicate is set when the page was not written because its content
+	// matches writeResults[duplicateOf]; pageURL is then recorded as an alias
+	duplicate   bool
+	duplicateOf int
+	// pageURL is the fetched URL of a written or duplicate page
+	pageURL url.URL
+	// skipped is set when the page was fetched but not processed further,
+	// e.g. because it redirected out of scope; the skip is already recorded
+	skipped bool
+	// unchanged is set when writeResult was kept from the previous crawl
+	// pass because the page's content did not change
+	unchanged bool
+	// matched is set when the written page matches cfg.MatchPattern
+	matched bool
+	// parts are the attempts of the page's other articles when it is split
+	// into separate outputs (see articles.go)
+	parts []pageAttempt
+}
+
+// runPageWithRetry runs the page pipeline for token, re-running it after an
+// exponential backoff while it fails with RetryPolicyAuto (transient) and
+// cfg.PageRetry() allows another attempt. It returns the final attempt and
+// the number of runs. A non-nil error aborts the crawl.
+func (s *Scheduler) runPageWithRetry(
+	cfg config.Config,
+	seedScheme string,
+	token frontier.CrawlToken,
+) (pageAttempt, int, error) {
+	pageRetry := cfg.PageRetry()
+	for attempts := 1; ; attempts++ {
+		attempt, err := s.processPage(cfg, seedScheme, token)
+		if err != nil {
+			return pageAttempt{}, attempts, err
+		}
+		if attempt.err == nil ||
+			attempt.err.RetryPolicy() != failure.RetryPolicyAuto ||
+			attempts >= pageRetry.MaxAttempts() {
+			return attempt, attempts, nil
 		}
 
-		// Log fetcher completion
+		backoff := pageRetry.Backoff(attempts)
+		if s.debugLogger.Enabled() {
+			s.debugLogger.LogStep(s.ctx, "scheduler", "page_retry", debug.FieldMap{
+				"url":          getURLString(token.URL()),
+				"attempt":      attempts,
+				"max_attempts": pageRetry.MaxAttempts(),
+				"backoff_ms":   backoff.Milliseconds(),
+				"error":        attempt.err.Error(),
+			})
+		}
+		if err := s.sleeper.Sleep(s.ctx, backoff); err != nil {
+			return pageAttempt{}, attempts, err
+		}
+	}
+}
+
+// processPage runs the page pipeline (fetch through write) once for token.
+// Page-level failures are reported through pageAttempt.err; the returned
+// error is only set for failures that must abort the crawl.
+func (s *Scheduler) processPage(
+	cfg config.Config,
+	seedScheme string,
+	token frontier.CrawlToken,
+) (pageAttempt, failure.ClassifiedError) {
+	var attempt pageAttempt
+
+	urlStr := getURLString(token.URL())
+
+	// Log pipeline start for this URL
+	s.debugLogger.LogStage(s.ctx, "pipeline", debug.StageEvent{
+		Type: debug.EventTypeStart,
+		URL:  urlStr,
+	})
+
+	// 3. Fetch Page URL
+	fetchStartTime := time.Now()
+	s.debugLogger.LogStage(s.ctx, "fetcher", debug.StageEvent{
+		Type: debug.EventTypeStart,
+		URL:  urlStr,
+	})
+
+	s.runBeforeFetchHooks(token.URL(), token.Depth())
+	fetchSpan := s.startStageSpan("fetch")
+	fetchResult, err := s.htmlFetcher.Fetch(s.pageSpanContext(), token.Depth(), token.URL(), s.retryOptions(cfg))
+	if fetchSpan != nil && err == nil {
+		fetchSpan.SetAttributes(
+			attrStatusCode.Int(fetchResult.Code()),
+			attrBytes.Int(len(fetchResult.Body())),
+		)
+	}
+	endStageSpan(fetchSpan, err)
+	s.observeFetch(err)
+	s.recordLinkFetch(token, fetchResult, err)
+	if err != nil {
+		if err.Impact() == failure.ImpactLevelAbort {
+			return pageAttempt{}, err
+		}
+		// Log fetcher error
 		s.debugLogger.LogStage(s.ctx, "fetcher", debug.StageEvent{
-			Type:     debug.EventTypeComplete,
-			URL:      getURLString(fetchResult.URL()),
+			Type:     debug.EventTypeError,
+			URL:      urlStr,
 			Duration: time.Since(fetchStartTime),
-			Fields: debug.FieldMap{
-				"status_code": fetchResult.Code(),
-			},
 		})
+		attempt.stage = failurejournal.StageFetch
+		attempt.err = err
+		return attempt, nil
+	}
 
-		// Dump fetched HTML
-		s.stageDumper.DumpFetcherOutput(urlStr, fetchResult.Body())
+	// Log fetcher completion
+	s.debugLogger.LogStage(s.ctx, "fetcher", debug.StageEvent{
+		Type:     debug.EventTypeComplete,
+		URL:      getURLString(fetchResult.URL()),
+		Duration: time.Since(fetchStartTime),
+		Fields: debug.FieldMap{
+			"status_code": fetchResult.Code(),
+		},
+	})
 
-		// 4. Extract HTML DOM
-		extractionResult, err := s.domExtractor.Extract(fetchResult.URL(), fetchResult.Body())
-		if err != nil {
-			if err.Impact() == failure.ImpactLevelAbort {
-				return CrawlingExecution{}, err
+	// 3.0 A redirect that left the allowed hosts is not written under the
+	// requested URL and its links are not followed, unless configured to
+	if s.redirectedOutOfScope(cfg, token, fetchResult.FinalURL()) {
+		attempt.skipped = true
+		return attempt, nil
+	}
+
+	// Dump fetched HTML
+	s.stageDumper.DumpFetcherOutput(urlStr, fetchResult.Body())
+
+	// Save the fetched body before extraction, if enabled
+	s.saveRawHTML(cfg, fetchResult)
+
+	// A body too large to parse is skipped before pagination, link checking
+	// or extraction parse it
+	if s.tooLargeToParse(cfg, token, fetchResult) {
+		attempt.skipped = true
+		return attempt, nil
+	}
+
+	// 3.1 Follow rel="next"/rel="prev" pagination at the current depth so
+	// paginated pages stay on the same logical level
+	if cfg.FollowPagination() && s.followsLinks(cfg) {
+		paginated := paginationLinks(fetchResult.URL(), fetchResult.Headers(), fetchResult.Body())
+		paginated = s.filterInScope(s.dedupeCanonical(paginated), token.Depth())
+		for _, pageURL := range paginated {
+			submissionErr := s.submitLink(fetchResult.URL(), pageURL, token.Depth())
+			if submissionErr != nil {
+				if robotsErr, ok := submissionErr.(*robots.RobotsError); ok {
+					s.recordRobotsErrorAndBackoff(robotsErr, pageURL)
+				}
+				attempt.errors++
 			}
-			// Note: Extraction errors are deterministic (content invalid).
-			// Do NOT record to failure journal - retrying the same content yields the same error.
-			totalErrors++
-			continue
 		}
+	}
+
+	// 3.2 A link check discovers and checks the page's links, and stops
+	// before extraction
+	if s.linkChecker != nil {
+		attempt.errors += s.checkLinks(cfg, token, fetchResult)
+		attempt.skipped = true
+		return attempt, nil
+	}
+
+	// 4. Extract content with the extractor for the response's media type
+	extractSpan := s.startStageSpan("extract")
+	extractionResult, err := s.extractorRegistry().For(fetchResult.Header("Content-Type")).Extract(fetchResult.URL(), fetchResult.Body())
+	endStageSpan(extractSpan, err)
+	if err != nil {
+		if err.Impact() == failure.ImpactLevelAbort {
+			return pageAttempt{}, err
+		}
+		// Note: Extraction errors are deterministic (content invalid).
+		// Do NOT record to failure journal - retrying the same content yields the same error.
+		attempt.err = err
+		return attempt, nil
+	}
+
+	// Dump extraction result
+	s.stageDumper.DumpExtractorOutput(urlStr, extractionResult.ContentNode)
+
+	// 4.0 A page split into its articles goes through the rest of the
+	// pipeline once per article, each written as a page of its own
+	if len(extractionResult.ArticleNodes) > 1 {
+		return s.processArticles(cfg, seedScheme, token, fetchResult, extractionResult, attempt.errors)
+	}
+	contentAttempt, err := s.processContent(cfg, seedScheme, token, fetchResult, extractionResult, fetchResult.URL())
+	if err != nil {
+		return pageAttempt{}, err
+	}
+	contentAttempt.errors += attempt.errors
+	return contentAttempt, nil
+}
 
-		// Dump extraction result
-		s.stageDumper.DumpExtractorOutput(urlStr, extractionResult.ContentNode)
+// processContent runs the pipeline from sanitization to writing over the
+// extracted content of fetchResult, which is written as the page at
+// pageURL: the fetched URL, or one of its articles (see articles.go).
+func (s *Scheduler) processContent(
+	cfg config.Config,
+	seedScheme string,
+	token frontier.CrawlToken,
+	fetchResult fetcher.FetchResult,
+	extractionResult extractor.ExtractionResult,
+	pageURL url.URL,
+) (pageAttempt, failure.ClassifiedError) {
+	var attempt pageAttempt
+	urlStr := getURLString(pageURL)
+
+	// Pages and their assets go to the page's language partition, if enabled
+	language := languagePartition(cfg, extractionResult)
+	outputDir := cfg.OutputDir()
+	if language != "" {
+		outputDir = filepath.Join(outputDir, language)
+	}
 
+	// 4.1 Pages published as Markdown need no sanitization or conversion,
+	// and their links are not discovered
+	var markdownDoc mdconvert.ConversionResult
+	// sanitizedHtml stays nil for pages published as Markdown
+	var sanitizedHtml *sanitizer.SanitizedHTMLDoc
+	if extractionResult.Markdown != nil {
+		markdownDoc = mdconvert.NewConversionResult(extractionResult.Markdown, nil)
+		s.stageDumper.DumpMDConvertOutput(urlStr, markdownDoc.GetMarkdownContent())
+	} else {
 		// 5. Sanitize extracted HTML
-		sanitizedHtml, err := s.htmlSanitizer.Sanitize(extractionResult.ContentNode)
+		sanitizeSpan := s.startStageSpan("sanitize")
+		sanitized, err := s.htmlSanitizer.Sanitize(extractionResult.ContentNode)
+		endStageSpan(sanitizeSpan, err)
 		if err != nil {
 			if err.Impact() == failure.ImpactLevelAbort {
-				return CrawlingExecution{}, err
+				return pageAttempt{}, err
 			}
 			// Note: Sanitization errors are deterministic (invariant violations).
 			// Do NOT record to failure journal - retrying the same content yields the same error.
-			totalErrors++
-			continue
+			attempt.err = err
+			return attempt, nil
 		}
 
+		sanitizedHtml = &sanitized
+
 		// Dump sanitization result
 		s.stageDumper.DumpSanitizerOutput(urlStr, sanitizedHtml.GetContentNode())
 
 		// 5.2 Resolve relative URLs to absolute URLs and filter by host
-		discoveredURLs := sanitizedHtml.GetDiscoveredURLs()
-
-		// 5.3 Resolve all URLs to absolute form using the seed scheme and current host
-		resolvedURLs := make([]url.URL, 0, len(discoveredURLs))
-		for _, u := range discoveredURLs {
-			resolved := urlutil.Resolve(u, seedScheme, s.currentHost)
+		discoveredLinks := sanitizedHtml.GetDiscoveredLinks()
+
+		// 5.3 Resolve all URLs to absolute form using the seed scheme and current host,
+		// declining links whose anchor text matches config.SkipLinkTextPatterns
+		resolvedURLs := make([]url.URL, 0, len(discoveredLinks))
+		for _, link := range discoveredLinks {
+			resolved := urlutil.Resolve(link.URL(), seedScheme, s.currentHost)
+			if s.followsLinks(cfg) && cfg.LinkTextMatcher().Matches(link.Text()) {
+				s.recordSkip(urlutil.Canonicalize(resolved), metadata.SkipReasonLinkText, token.Depth()+1)
+				continue
+			}
 			resolvedURLs = append(resolvedURLs, resolved)
 		}
 
-		// 5.4 Filter to only keep URLs from the current host
-		filteredURLs := urlutil.FilterByHost(s.currentHost, resolvedURLs)
+		// 5.4 Drop links that resolve to the same canonical URL, keeping
+		// document order so BFS tie-breaking is reproducible across runs
+		dedupedURLs := s.dedupeCanonical(resolvedURLs)
+
+		// 5.5 Filter to only keep URLs from allowed hosts, recording the rest as skips.
+		// A re-crawl of fixed pages, or a single-page crawl, discovers nothing.
+		var filteredURLs []url.URL
+		if s.followsLinks(cfg) {
+			filteredURLs = s.filterInScope(dedupedURLs, token.Depth()+1)
+		}
 
-		// 5.5 submit all discovered links through robots checking to frontier
+		// 5.6 submit all discovered links through robots checking to frontier
 		for _, discoveredurl := range filteredURLs {
-			submissionErr := s.SubmitUrlForAdmission(discoveredurl, frontier.SourceCrawl, nextCrawlToken.Depth()+1)
+			submissionErr := s.submitLink(fetchResult.URL(), discoveredurl, token.Depth()+1)
 			if submissionErr != nil {
 				// Check if this is a robots error that requires backoff
 				if robotsErr, ok := submissionErr.(*robots.RobotsError); ok {
 					s.recordRobotsErrorAndBackoff(robotsErr, discoveredurl)
 				}
 				// Submission errors are scheduler-level errors, count them
-				totalErrors++
+				attempt.errors++
 				// Continue processing other URLs, don't abort the crawl
 			}
 		}
 
 		// 6. HTML → Markdown Conversion
-		markdownDoc, err := s.markdownConversionRule.Convert(sanitizedHtml, getURLString(fetchResult.URL()))
+		convertSpan := s.startStageSpan("convert")
+		markdownDoc, err = s.markdownConversionRule.Convert(sanitized, getURLString(fetchResult.URL()))
+		endStageSpan(convertSpan, err)
 		if err != nil {
 			if err.Impact() == failure.ImpactLevelAbort {
-				return CrawlingExecution{}, err
+				return pageAttempt{}, err
 			}
 			// Note: Conversion errors are deterministic (conversion failures).
 			// Do NOT record to failure journal - retrying the same content yields the same error.
-			totalErrors++
-			continue
+			attempt.err = err
+			return attempt, nil
 		}
 
 		// Dump markdown conversion result
 		s.stageDumper.DumpMDConvertOutput(urlStr, markdownDoc.GetMarkdownContent())
+	}
 
-		// 7. Assets Resolution
-		resolveParam := assets.NewResolveParam(cfg.OutputDir(), cfg.MaxAssetSize(), cfg.HashAlgo())
-		assetfulMarkdown, err := s.assetResolver.Resolve(
-			s.ctx,
-			fetchResult.URL(),
-			markdownDoc,
-			resolveParam,
-			RetryOptions(cfg),
-		)
-		if err != nil {
-			if err.Impact() == failure.ImpactLevelAbort {
-				return CrawlingExecution{}, err
-			}
-			// Track for manual retry if eligible
-			if err.RetryPolicy() == failure.RetryPolicyManual {
-				s.failureJournal.Record(failurejournal.FailureRecord{
-					URL:        getURLString(nextCrawlToken.URL()),
-					Stage:      failurejournal.StageAsset,
-					Error:      err.Error(),
-					RetryCount: 0,
-					Timestamp:  time.Now(),
+	// 7. Assets Resolution
+	resolveParam := assets.NewResolveParam(outputDir, cfg.MaxAssetSize(), cfg.HashAlgo()).
+		WithAssetNaming(assets.AssetNaming(cfg.AssetNaming())).
+		WithImageMaxWidth(cfg.ImageMaxWidth()).
+		WithImageFormat(assets.ImageFormat(cfg.ImageFormat())).
+		WithRetryJitter(s.retryJitter.next)
+	assetsSpan := s.startStageSpan("assets")
+	assetfulMarkdown, err := s.assetResolver.Resolve(
+		s.pageSpanContext(),
+		fetchResult.URL(),
+		markdownDoc,
+		resolveParam,
+		s.retryOptions(cfg),
+	)
+	if assetsSpan != nil {
+		assetsSpan.SetAttributes(attrAssets.Int(len(assetfulMarkdown.LocalAssets())))
+	}
+	endStageSpan(assetsSpan, err)
+	if err != nil {
+		if err.Impact() == failure.ImpactLevelAbort {
+			return pageAttempt{}, err
+		}
+		// Journaled once the page's final attempt is known
+		attempt.assetErr = err
+		attempt.errors++
+		// Continue to process the markdown even if asset resolution had errors
+	}
+	// Count assets processed - use the actual count of successfully resolved local assets
+	attempt.assets = len(assetfulMarkdown.LocalAssets())
+	attempt.outputBytes = assetfulMarkdown.WrittenBytes()
+
+	// Dump asset resolving result
+	s.stageDumper.DumpAssetResolverOutput(urlStr, assetfulMarkdown.Content())
+
+	// 8. Markdown Normalization
+	normalizeParam := normalize.NewNormalizeParam(
+		build.FullVersion(),
+		fetchResult.FetchedAt(),
+		cfg.HashAlgo(),
+		token.Depth(),
+		cfg.AllowedPathPrefix(),
+	).WithMarkdownFlavor(normalize.MarkdownFlavor(cfg.MarkdownFlavor())).
+		WithGenerateToC(cfg.GenerateToC()).
+		WithDedupeHeadings(cfg.DedupeHeadings()).
+		WithStripInvisibleInCode(cfg.StripInvisibleInCode()).
+		WithQueryFilter(s.queryFilter.Keeps).
+		WithTrailingSlash(s.trailingSlash).
+		WithKeepFragment(pageURL.Fragment != "").
+		WithStructuredSections(extractionResult.StructuredSections).
+		WithChunking(cfg.ChunkSize(), cfg.ChunkOverlap()).
+		WithRunID(s.runID)
+	if cfg.WriteProvenance() {
+		normalizeParam = normalizeParam.WithProvenance(token.Referrer(), fetchResult.RedirectChain())
+	}
+	if cfg.RelativeLinks() {
+		if pagePath, ok := pageOutputPath(cfg, outputDir, pageURL); ok {
+			normalizeParam = normalizeParam.WithLinkRewriting(pagePath, s.linkResolver(cfg, outputDir))
+		}
+	}
+	normalizeSpan := s.startStageSpan("normalize")
+	normalizedMarkdown, err := s.markdownConstraint.Normalize(
+		pageURL,
+		assetfulMarkdown,
+		normalizeParam,
+	)
+	endStageSpan(normalizeSpan, err)
+	if err != nil {
+		if err.Impact() == failure.ImpactLevelAbort {
+			return pageAttempt{}, err
+		}
+		// Note: Normalization errors are deterministic (invariant violations).
+		// Do NOT record to failure journal - retrying the same content yields the same error.
+		attempt.err = err
+		return attempt, nil
+	}
+
+	// 8.5 Registered Transformers
+	transformSpan := s.startStageSpan("transform")
+	transformedMarkdown, err := s.applyTransformers(
+		normalizedMarkdown,
+		NewTransformContext(pageURL, token.Depth()),
+		normalizeParam,
+	)
+	endStageSpan(transformSpan, err)
+	if err != nil {
+		if err.Impact() == failure.ImpactLevelAbort {
+			return pageAttempt{}, err
+		}
+		// Note: Transformers are deterministic over the same document.
+		// Do NOT record to failure journal - retrying the same content yields the same error.
+		attempt.err = err
+		return attempt, nil
+	}
+
+	// 8.6 Group identical content under the first written page
+	contentHash := transformedMarkdown.Frontmatter().ContentHash()
+	attempt.contentHash = contentHash
+	if cfg.DuplicateContent() == "alias" {
+		if index, ok := s.writtenContent[contentHash]; ok {
+			s.recordSkip(pageURL, metadata.SkipReasonDuplicateContent, token.Depth())
+			if s.debugLogger.Enabled() {
+				s.debugLogger.LogStep(s.ctx, "scheduler", "duplicate_content", debug.FieldMap{
+					"url":          getURLString(pageURL),
+					"content_hash": contentHash,
+					"canonical":    s.writeResults[index].Path(),
 				})
 			}
-			totalErrors++
-			// Continue to process the markdown even if asset resolution had errors
-		}
-		// Count assets processed - use the actual count of successfully resolved local assets
-		totalAssets += len(assetfulMarkdown.LocalAssets())
-
-		// Dump asset resolving result
-		s.stageDumper.DumpAssetResolverOutput(urlStr, assetfulMarkdown.Content())
-
-		// 8. Markdown Normalization
-		normalizeParam := normalize.NewNormalizeParam(
-			build.FullVersion(),
-			fetchResult.FetchedAt(),
-			cfg.HashAlgo(),
-			nextCrawlToken.Depth(),
-			cfg.AllowedPathPrefix(),
-		)
-		normalizedMarkdown, err := s.markdownConstraint.Normalize(
-			fetchResult.URL(),
-			assetfulMarkdown,
-			normalizeParam,
-		)
-		if err != nil {
-			if err.Impact() == failure.ImpactLevelAbort {
-				return CrawlingExecution{}, err
-			}
-			// Note: Normalization errors are deterministic (invariant violations).
-			// Do NOT record to failure journal - retrying the same content yields the same error.
-			totalErrors++
-			continue
+			attempt.duplicate = true
+			attempt.duplicateOf = index
+			attempt.pageURL = pageURL
+			return attempt, nil
 		}
+	}
 
-		// 9. Write Artifact
-		writeResult, err := s.storageSink.Write(
-			cfg.OutputDir(),
-			normalizedMarkdown,
-			cfg.HashAlgo(),
-		)
-		if err != nil {
-			if err.Impact() == failure.ImpactLevelAbort {
-				return CrawlingExecution{}, err
-			}
-			// Track for manual retry if eligible
-			if err.RetryPolicy() == failure.RetryPolicyManual {
-				s.failureJournal.Record(failurejournal.FailureRecord{
-					URL:        getURLString(nextCrawlToken.URL()),
-					Stage:      failurejournal.StageStorage,
-					Error:      err.Error(),
-					RetryCount: 0,
-					Timestamp:  time.Now(),
-				})
+	// 8.7 Keep the previous crawl pass's files of an unchanged page
+	if previous, ok := s.unchangedPage(pageURL, contentHash); ok {
+		if s.debugLogger.Enabled() {
+			s.debugLogger.LogStep(s.ctx, "scheduler", "unchanged_page", debug.FieldMap{
+				"url":  getURLString(pageURL),
+				"path": previous.Path(),
+			})
+		}
+		// The combined file is rewritten every pass, so it takes the page
+		// again
+		if combined, ok := s.storageSink.(*storage.SingleFileWriter); ok {
+			if err := combined.Keep(outputDir, transformedMarkdown, cfg.HashAlgo()); err != nil {
+				if err.Impact() == failure.ImpactLevelAbort {
+					return pageAttempt{}, err
+				}
+				attempt.stage = failurejournal.StageStorage
+				attempt.err = err
+				return attempt, nil
 			}
-			// recoverable → log already done → count error
-			totalErrors++
-			continue
 		}
-		s.writeResults = append(s.writeResults, writeResult)
+		attempt.writeResult = previous
+		attempt.pageURL = pageURL
+		attempt.unchanged = true
+		return attempt, nil
+	}
 
-		// Apply rate limiting delay at the end of the crawl loop using Wait
-		if err := s.rateLimiter.Wait(s.ctx, s.currentHost); err != nil {
-			// Context cancelled, exit the loop
-			return CrawlingExecution{}, err
+	// 9. Write Artifact
+	writeSpan := s.startStageSpan("write")
+	writeResult, err := s.storageSink.Write(
+		outputDir,
+		transformedMarkdown,
+		cfg.HashAlgo(),
+	)
+	if writeSpan != nil && err == nil {
+		writeSpan.SetAttributes(attrBytes.Int(len(transformedMarkdown.Content())))
+	}
+	endStageSpan(writeSpan, err)
+	if err != nil {
+		if err.Impact() == failure.ImpactLevelAbort {
+			return pageAttempt{}, err
 		}
+		attempt.stage = failurejournal.StageStorage
+		attempt.err = err
+		return attempt, nil
 	}
 
-	// Stats are recorded by defer - return successful execution result
-	return NewCrawlingExecution(s.writeResults, s.frontier.VisitedCount(), totalAssets, totalErrors), nil
+	// 9.1 Additional output formats, next to the Markdown file
+	var formatBytes int64
+	writeResult, formatBytes, err = s.writeFormats(cfg, writeResult, sanitizedHtml)
+	attempt.outputBytes += formatBytes
+	if err != nil {
+		if err.Impact() == failure.ImpactLevelAbort {
+			return pageAttempt{}, err
+		}
+		attempt.stage = failurejournal.StageStorage
+		attempt.err = err
+		return attempt, nil
+	}
+
+	attempt.writeResult = writeResult.WithLanguage(language).WithRunID(s.runID)
+	attempt.pageURL = pageURL
+	attempt.outputBytes += int64(len(transformedMarkdown.Content()))
+	attempt.matched = cfg.MatchesPage(getURLString(pageURL), transformedMarkdown.Content())
+	s.runAfterWriteHooks(attempt.writeResult, attempt.pageURL)
+	return attempt, nil
+}
+
+// undeterminedLanguage is the partition of pages without a declared
+// language (the BCP 47 "undetermined" code).
+const undeterminedLanguage = "und"
+
+// languagePartition returns the language subdirectory the page is written
+// to, or "" when cfg does not partition the output by language.
+func languagePartition(cfg config.Config, extractionResult extractor.ExtractionResult) string {
+	if !cfg.PartitionByLanguage() {
+		return ""
+	}
+	if language := extractionResult.Language(); language != "" {
+		return language
+	}
+	return undeterminedLanguage
 }
 
 func createHttpClient(
-	maxIdleConns int,
-	maxIdleConnsPerHost int,
-	idleConnTimeout time.Duration,
+	transportOptions fetcher.TransportOptions,
 	baseTimeout time.Duration,
 ) *http.Client {
-	transport := &http.Transport{
-		MaxIdleConns:        maxIdleConns,
-		MaxIdleConnsPerHost: maxIdleConnsPerHost,
-		IdleConnTimeout:     idleConnTimeout,
-	}
-
 	client := &http.Client{
 		Timeout:   baseTimeout,
-		Transport: transport,
+		Transport: fetcher.NewTransport(transportOptions),
 	}
 
 	return client
 }
 
+// fetchHeaderRules converts the configured header rules for the fetcher.
+func fetchHeaderRules(cfg config.Config) []fetcher.HeaderRule {
+	var rules []fetcher.HeaderRule
+	for _, rule := range cfg.HeaderRules() {
+		rules = append(rules, fetcher.HeaderRule{
+			Pattern: rule.Regexp(),
+			Headers: rule.Headers(),
+		})
+	}
+	return rules
+}
+
 // recordRobotsErrorAndBackoff records a robots error using metadataSink and
 // triggers exponential backoff on the rate limiter if the error cause warrants it.
 // This method handles ErrCauseHttpTooManyRequests (429) and ErrCauseHttpServerError (5xx)
@@ -675,16 +1417,164 @@ func (s *Scheduler) recordRobotsErrorAndBackoff(robotsErr *robots.RobotsError, t
 		if s.rateLimiter != nil {
 			s.rateLimiter.Backoff(s.ctx, targetURL.Host)
 		}
+		s.observeThrottle()
 	}
 }
 
-func RetryOptions(cfg config.Config) []retrier.RetryOption {
-	return []retrier.RetryOption{
-		retrier.WithMaxAttempts(cfg.MaxAttempt()),
-		retrier.WithInitialDuration(cfg.BackoffInitialDuration()),
-		retrier.WithJitter(cfg.Jitter()),
-		retrier.WithMultiplier(cfg.BackoffMultiplier()),
-		retrier.WithMaxDuration(cfg.BackoffMaxDuration()),
+// followsLinks reports whether links found on a page are discovered and
+// submitted to the frontier. A re-crawl and a single-page crawl only process
+// the pages they were given.
+func (s *Scheduler) followsLinks(cfg config.Config) bool {
+	return !s.recrawlOnly && !cfg.SinglePage()
+}
+
+// filterInScope keeps the URLs whose host matches the allowed hosts and
+// records every dropped URL as an out-of-scope skip at the given depth.
+// Without a configured matcher it falls back to the current host only.
+func (s *Scheduler) filterInScope(urls []url.URL, depth int) []url.URL {
+	filtered := make([]url.URL, 0, len(urls))
+	for _, u := range urls {
+		if s.isInScope(u) {
+			filtered = append(filtered, u)
+			continue
+		}
+		s.recordSkip(urlutil.Canonicalize(u), metadata.SkipReasonOutOfScope, depth)
+	}
+	return filtered
+}
+
+// isInScope reports whether u's host is allowed by the host matcher,
+// or equals the current host when no matcher is configured.
+func (s *Scheduler) isInScope(u url.URL) bool {
+	if s.hostMatcher.IsEmpty() {
+		return len(urlutil.FilterByHost(s.currentHost, []url.URL{u})) == 1
+	}
+	return s.hostMatcher.Matches(u.Host)
+}
+
+// redirectedOutOfScope reports whether the fetch of token ended on a host
+// outside the crawl scope, recording the final URL as a
+// redirect-out-of-scope skip. It is always false when the config writes
+// such pages.
+func (s *Scheduler) redirectedOutOfScope(cfg config.Config, token frontier.CrawlToken, finalURL url.URL) bool {
+	if cfg.RedirectOutOfScope() != config.RedirectOutOfScopeSkip {
+		return false
+	}
+	requested := token.URL()
+	if strings.EqualFold(finalURL.Host, requested.Host) || s.isInScope(finalURL) {
+		return false
+	}
+	s.metadataSink.RecordSkip(metadata.NewSkipEvent(
+		finalURL.String(),
+		metadata.SkipReasonRedirectOutOfScope,
+		time.Now(),
+		[]metadata.Attribute{
+			metadata.NewAttr(metadata.AttrHost, finalURL.Host),
+			metadata.NewAttr(metadata.AttrURL, requested.String()),
+			metadata.NewInt64Attr(metadata.AttrDepth, int64(token.Depth())),
+		},
+	))
+	if s.debugLogger.Enabled() {
+		s.debugLogger.LogStep(s.ctx, "scheduler", "redirect_out_of_scope", debug.FieldMap{
+			"url":       requested.String(),
+			"final_url": finalURL.String(),
+		})
+	}
+	return true
+}
+
+// tooLargeToParse reports whether the body of fetchResult exceeds
+// config.MaxHTMLParseBytes, recording the page as a too-large-to-parse
+// skip.
+func (s *Scheduler) tooLargeToParse(cfg config.Config, token frontier.CrawlToken, fetchResult fetcher.FetchResult) bool {
+	limit := cfg.MaxHTMLParseBytes()
+	if limit == 0 || int64(fetchResult.SizeByte()) <= limit {
+		return false
+	}
+	pageURL := token.URL()
+	s.recordSkip(pageURL, metadata.SkipReasonTooLargeToParse, token.Depth())
+	if s.debugLogger.Enabled() {
+		s.debugLogger.LogStep(s.ctx, "scheduler", "too_large_to_parse", debug.FieldMap{
+			"url":        pageURL.String(),
+			"size_bytes": fetchResult.SizeByte(),
+			"limit":      limit,
+		})
+	}
+	return true
+}
+
+// recordSkip records a deliberate, non-error skip of target.
+func (s *Scheduler) recordSkip(target url.URL, reason metadata.SkipReason, depth int) {
+	s.metadataSink.RecordSkip(metadata.NewSkipEvent(
+		target.String(),
+		reason,
+		time.Now(),
+		[]metadata.Attribute{
+			metadata.NewAttr(metadata.AttrHost, target.Host),
+			metadata.NewInt64Attr(metadata.AttrDepth, int64(depth)),
+		},
+	))
+}
+
+// crawlContext returns the context the crawl as a whole runs under.
+// Unlike s.ctx, which the init paths create with the per-request timeout,
+// it only carries a deadline or cancellation injected by the caller.
+func (s *Scheduler) crawlContext() context.Context {
+	if s.crawlCtx == nil {
+		return context.Background()
+	}
+	return s.crawlCtx
+}
+
+// waitForCrawlWindow blocks until the clock is inside a crawl window that
+// applies to host, and reports whether crawling may continue. Crawling is
+// always allowed when no window applies. If the next window opens after the
+// crawl deadline, it returns false without sleeping so the crawl ends
+// instead of idling past its deadline. All times come from s.clock.
+func (s *Scheduler) waitForCrawlWindow(windows []config.TimeWindow, host string) (bool, error) {
+	ctx := s.crawlContext()
+	for {
+		now := s.clock.Now()
+
+		var nextOpen time.Time
+		applicable := false
+		for _, window := range windows {
+			if !window.AppliesTo(host) {
+				continue
+			}
+			applicable = true
+			open := window.NextOpen(now)
+			if nextOpen.IsZero() || open.Before(nextOpen) {
+				nextOpen = open
+			}
+		}
+		if !applicable || !nextOpen.After(now) {
+			return true, nil
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && nextOpen.After(deadline) {
+			s.debugLogger.LogStep(ctx, "scheduler", "crawl_window_after_deadline", debug.FieldMap{
+				"host":      host,
+				"next_open": nextOpen.Format(time.RFC3339),
+				"deadline":  deadline.Format(time.RFC3339),
+			})
+			return false, nil
+		}
+
+		wait := nextOpen.Sub(now)
+		s.debugLogger.LogStage(ctx, "scheduler", debug.StageEvent{
+			Type: debug.EventTypeProgress,
+			Fields: debug.FieldMap{
+				"step":      "crawl_window_wait",
+				"host":      host,
+				"wait_ms":   wait.Milliseconds(),
+				"next_open": nextOpen.Format(time.RFC3339),
+			},
+		})
+
+		if err := s.sleeper.Sleep(ctx, wait); err != nil {
+			return false, err
+		}
 	}
 }
 
@@ -734,19 +1624,30 @@ func getURLString(u url.URL) string {
 }
 
 // NewSchedulerWithConfig creates a new Scheduler with config-based dependency injection.
-// This constructor determines whether to use DryRunSink or LocalSink based on cfg.DryRun().
+// This constructor determines whether to use DryRunSink or LocalSink based on cfg.DryRun(),
+// and fetches pages through a fetcher.ArchiveFetcher when cfg.ReplayArchive() is set.
 func NewSchedulerWithConfig(cfg config.Config) Scheduler {
 	recorder := metadata.NewRecorder("sample-single-sync-worker")
 	cachedRobot := robots.NewCachedRobot(&recorder)
 	frontier := frontier.NewCrawlFrontier()
-	fetcher := fetcher.NewHtmlFetcher(&recorder)
+	frontier.SetMetadataSink(&recorder)
 	ext := extractor.NewDomExtractor(&recorder)
 	sanitizer := sanitizer.NewHTMLSanitizer(&recorder)
 	conversionRule := mdconvert.NewRule(&recorder)
 	markdownConstraint := normalize.NewMarkdownConstraint(&recorder)
 
+	// A replayed crawl serves pages from the archive instead of the network
+	var pageFetcher fetcher.Fetcher
+	if cfg.ReplayArchive() != "" {
+		f := fetcher.NewArchiveFetcher(&recorder, cfg.ReplayArchive())
+		pageFetcher = &f
+	} else {
+		f := fetcher.NewHtmlFetcher(&recorder)
+		pageFetcher = &f
+	}
+
 	var resolver assets.Resolver
-	var storageSink storage.Sink
+	var storageSink storage.Writer
 	if cfg.DryRun() {
 		resolver = assets.NewDryRunResolver(&recorder)
 		storageSink = storage.NewDryRunSink(&recorder)
@@ -757,12 +1658,13 @@ func NewSchedulerWithConfig(cfg config.Config) Scheduler {
 	}
 
 	// Create rate limiter with config values
-	rateLimiter := ratelimiter.NewConcurrentRateLimiter(
-		ratelimiter.WithJitter(cfg.Jitter()),
+	// Jitter is drawn from a stream seeded by cfg.SubSeed (see jitter.go)
+	limiterJitter := newSeededJitter(cfg.SubSeed(config.SubSeedRateLimiterJitter), cfg.Jitter(), cfg.JitterDistribution())
+	rateLimiter := newSeededRateLimiter(ratelimiter.NewConcurrentRateLimiter(
 		ratelimiter.WithInitialDuration(cfg.BackoffInitialDuration()),
 		ratelimiter.WithMultiplier(cfg.BackoffMultiplier()),
 		ratelimiter.WithMaxDuration(cfg.BackoffMaxDuration()),
-	)
+	), limiterJitter)
 
 	// Initialize stage dumper based on config
 	var stageDumper stagedump.Dumper = stagedump.NewNoOpDumper()
@@ -784,7 +1686,6 @@ func NewSchedulerWithConfig(cfg config.Config) Scheduler {
 	}
 
 	// Propagate debug logger to all components
-	fetcher.SetDebugLogger(debugLogger)
 	ext.SetDebugLogger(debugLogger)
 	sanitizer.SetDebugLogger(debugLogger)
 	cachedRobot.SetDebugLogger(debugLogger)
@@ -792,7 +1693,7 @@ func NewSchedulerWithConfig(cfg config.Config) Scheduler {
 	conversionRule.SetDebugLogger(debugLogger)
 	markdownConstraint.SetDebugLogger(debugLogger)
 
-	// Set debug logger for resolver and storage sink
+	// Set debug logger for resolver, storage sink and page fetcher
 	// Note: These may be pointer or interface types, handle accordingly
 	if r, ok := resolver.(*assets.LocalResolver); ok {
 		r.SetDebugLogger(debugLogger)
@@ -803,6 +1704,12 @@ func NewSchedulerWithConfig(cfg config.Config) Scheduler {
 	if s, ok := storageSink.(*storage.DryRunSink); ok {
 		s.SetDebugLogger(debugLogger)
 	}
+	switch f := pageFetcher.(type) {
+	case *fetcher.HtmlFetcher:
+		f.SetDebugLogger(debugLogger)
+	case *fetcher.ArchiveFetcher:
+		f.SetDebugLogger(debugLogger)
+	}
 
 	// Set base delay on rate limiter
 	rateLimiter.SetBaseDelay(cfg.BaseDelay())
@@ -812,7 +1719,7 @@ func NewSchedulerWithConfig(cfg config.Config) Scheduler {
 		crawlFinalizer:         &recorder,
 		robot:                  &cachedRobot,
 		frontier:               &frontier,
-		htmlFetcher:            &fetcher,
+		htmlFetcher:            pageFetcher,
 		domExtractor:           &ext,
 		htmlSanitizer:          &sanitizer,
 		markdownConversionRule: conversionRule,
@@ -820,8 +1727,12 @@ func NewSchedulerWithConfig(cfg config.Config) Scheduler {
 		markdownConstraint:     &markdownConstraint,
 		storageSink:            storageSink,
 		rateLimiter:            rateLimiter,
+		limiterJitter:          limiterJitter,
 		stageDumper:            stageDumper,
 		debugLogger:            debugLogger,
+		clock:                  systemClock{},
+		sleeper:                contextSleeper{},
+		pause:                  newPauseGate(),
 	}
 }
 
@@ -858,6 +1769,11 @@ func (s *Scheduler) InitializeWithConfig(cfg config.Config) (init *CrawlInitiali
 		return nil, err
 	}
 
+	// Fail fast before any fetching when the output cannot be written
+	if err = s.preflightOutput(cfg); err != nil {
+		return nil, err
+	}
+
 	// Initialize file-based failure journal in output directory
 	if s.failureJournal == nil {
 		journalPath := filepath.Join(cfg.OutputDir(), "failures.jsonl")
@@ -874,20 +1790,47 @@ func (s *Scheduler) InitializeWithConfig(cfg config.Config) (init *CrawlInitiali
 
 	// Initialize HTTP Client
 	s.httpClient = createHttpClient(
-		cfg.MaxIdleConns(),
-		cfg.MaxIdleConnsPerHost(),
-		cfg.IdleConnTimeout(),
+		fetcher.TransportOptions{
+			MaxIdleConns:        cfg.MaxIdleConns(),
+			MaxIdleConnsPerHost: cfg.MaxIdleConnsPerHost(),
+			IdleConnTimeout:     cfg.IdleConnTimeout(),
+			HTTP2:               cfg.HTTP2(),
+		},
 		cfg.Timeout(),
 	)
+	// Replayed crawls read robots.txt, pages and assets from the archive
+	if cfg.ReplayArchive() != "" {
+		s.httpClient.Transport = fetcher.NewArchiveTransport(cfg.ReplayArchive())
+	} else if cfg.OfflineOnly() {
+		// Offline-only crawls send no request either: the fetch cache only
+		// holds pages, so anything else is answered as missing
+		s.httpClient.Transport = fetcher.NewArchiveTransport(cfg.FetchCacheDir())
+	} else if err = s.configureTLS(cfg); err != nil {
+		return nil, err
+	}
+
+	// Log in before anything is fetched, so every request carries the session
+	if err = s.authenticate(); err != nil {
+		return nil, err
+	}
 
 	// Initialize rate limiter
 	s.rateLimiter.SetBaseDelay(cfg.BaseDelay())
 	s.rateLimiter.SetJitter(cfg.Jitter())
+	s.defaultCrawlDelay = cfg.DefaultCrawlDelay()
+	s.initJitter(cfg)
+	s.initAutoTune(cfg)
+	s.initCircuitBreaker(cfg)
 
 	// Initialize Robots and Frontier
 	s.robot.Init(cfg.UserAgent(), s.httpClient)
+	s.robot.SetHostUserAgents(cfg.HostUserAgents())
+	s.robot.SetOverrides(cfg.RobotsOverride())
 	s.frontier.Init(cfg)
 
+	// Append written pages to the combined single-file export, if configured
+	s.initSingleFileOutput(cfg)
+
 	// Configure DOM Extractor
 	extractParam := extractor.ExtractParam{
 		BodySpecificityBias:  cfg.BodySpecificityBias(),
@@ -905,20 +1848,53 @@ func (s *Scheduler) InitializeWithConfig(cfg config.Config) (init *CrawlInitiali
 			MinParagraphsOrCode: cfg.ThresholdMinParagraphsOrCode(),
 			MaxLinkDensity:      cfg.ThresholdMaxLinkDensity(),
 		},
+		MinTextRatio:      cfg.MinTextRatio(),
 		SelectorBlacklist: cfg.SelectorBlacklist(),
+		NoscriptMode:      extractor.NoscriptMode(cfg.NoscriptMode()),
+		StructuredMode:    cfg.StructuredExtraction(),
+		StripByRole:       cfg.StripByRole(),
+		MultiArticleMode:  extractor.MultiArticleMode(cfg.MultiArticleMode()),
 	}
 	s.domExtractor.SetExtractParam(extractParam)
 
-	// Initialize Fetcher
+	// Configure Markdown conversion
+	if rule, ok := s.markdownConversionRule.(*mdconvert.StrictConversionRule); ok {
+		rule.SetImageDensity(cfg.ImageDensity())
+		rule.SetPreserveUnknownHTML(cfg.PreserveUnknownHTML())
+		rule.SetAdmonitionTypes(cfg.AdmonitionTypes())
+		rule.SetSpanStyles(cfg.SpanStyles())
+	}
+
+	// Initialize Fetcher, serving pages from the fetch cache if configured
+	s.initFetchCache(cfg)
 	s.htmlFetcher.Init(s.httpClient, cfg.UserAgent())
+	s.htmlFetcher.SetFetchParam(fetcher.FetchParam{
+		PreflightHead:       cfg.PreflightHead(),
+		AllowedContentTypes: cfg.AllowedContentTypes(),
+		MaxResponseBytes:    cfg.MaxResponseBytes(),
+		HostUserAgents:      cfg.HostUserAgents(),
+		TraceRequests:       cfg.TraceRequests(),
+		DefaultHeaders:      cfg.DefaultHeaders(),
+		HeaderRules:         fetchHeaderRules(cfg),
+		RetryJitter:         s.retryJitter.next,
+	})
 
 	// Initialize Asset Resolver
 	s.assetResolver.Init(s.httpClient, cfg.UserAgent())
 
+	// Pre-fetch robots.txt of every known host, if configured
+	s.warmupRobots(cfg)
+
 	// Submit seed URL to frontier
 	s.currentHost = cfg.SeedURLs()[0].Host
+	s.hostMatcher = cfg.HostMatcher()
+	s.extensionMatcher = cfg.ExtensionMatcher()
+	s.queryFilter = cfg.QueryFilter()
+	s.trailingSlash = urlutil.TrailingSlashPolicy(cfg.TrailingSlashPolicy())
+	s.traps = newTrapDetector(cfg.MaxPatternRepeats())
+	s.linkChecker = newLinkChecker(cfg, s.httpClient)
 	seedScheme := cfg.SeedURLs()[0].Scheme
-	err = s.SubmitUrlForAdmission(cfg.SeedURLs()[0], frontier.SourceSeed, 0)
+	err = s.SubmitUrlForAdmission(cfg.SeedURLs()[0], frontier.SourceSeed, cfg.SeedDepth(cfg.SeedURLs()[0]))
 	if err != nil {
 		if robotsErr, ok := err.(*robots.RobotsError); ok {
 			s.recordRobotsErrorAndBackoff(robotsErr, cfg.SeedURLs()[0])
@@ -0,0 +1,235 @@
+package scheduler_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/assets"
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/extractor"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/frontier"
+	"github.com/rohmanhakim/docs-crawler/internal/mdconvert"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/rohmanhakim/docs-crawler/internal/normalize"
+	"github.com/rohmanhakim/docs-crawler/internal/robots"
+	"github.com/rohmanhakim/docs-crawler/internal/sanitizer"
+	"github.com/rohmanhakim/docs-crawler/internal/scheduler"
+	"github.com/rohmanhakim/docs-crawler/internal/stagedump"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/rohmanhakim/docs-crawler/pkg/debug"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// archiveSitePages are the two pages served live and replayed from the
+// archive; the index links to the install page.
+var archiveSitePages = map[string]string{
+	"/docs": `<!DOCTYPE html>
+<html>
+<head><title>Docs</title></head>
+<body>
+<main>
+<h1>Documentation</h1>
+<p>Welcome to the documentation. It explains how to install and use the
+tool, with enough text to pass content extraction.</p>
+<p>Start with the <a href="/docs/install">installation guide</a>.</p>
+</main>
+</body>
+</html>`,
+	"/docs/install": `<!DOCTYPE html>
+<html>
+<head><title>Install</title></head>
+<body>
+<main>
+<h1>Installation</h1>
+<p>Download the release for your platform and put the binary on your PATH.
+Then run the crawler against a documentation site.</p>
+<pre><code>go install example.com/tool@latest</code></pre>
+</main>
+</body>
+</html>`,
+}
+
+// crawlPipelineForTest crawls seed through the real pipeline, fetching pages
+// with pageFetcher, and returns the fetched page URLs in order and the writer.
+func crawlPipelineForTest(t *testing.T, pageFetcher fetcher.Fetcher, sink *metadatatest.SinkMock, cfg config.Config) ([]string, *storage.MemoryWriter) {
+	t.Helper()
+	writer := storage.NewMemoryWriter()
+	_, err := runPipelineForTest(t, pageFetcher, sink, writer, cfg)
+	require.NoError(t, err)
+
+	var fetched []string
+	for _, event := range sink.FetchEvents {
+		if event.Kind() != metadata.KindPage {
+			continue
+		}
+		fetched = append(fetched, event.FetchURL())
+	}
+	return fetched, writer
+}
+
+// runPipelineForTest crawls cfg's seeds through the real pipeline, fetching
+// pages with pageFetcher and writing them with writer, and returns the
+// crawl's result. Each setup function runs on the scheduler before init.
+func runPipelineForTest(t *testing.T, pageFetcher fetcher.Fetcher, sink *metadatatest.SinkMock, writer storage.Writer, cfg config.Config, setup ...func(*scheduler.Scheduler)) (scheduler.CrawlingExecution, error) {
+	t.Helper()
+	s := newPipelineSchedulerForTest(t, pageFetcher, sink, writer)
+	for _, fn := range setup {
+		fn(&s)
+	}
+
+	init, err := s.InitializeWithConfig(cfg)
+	require.NoError(t, err)
+	return s.ExecuteCrawlingWithState(init)
+}
+
+// newPipelineSchedulerForTest builds a scheduler over the real pipeline
+// stages, fetching pages with pageFetcher and writing them with writer.
+func newPipelineSchedulerForTest(t *testing.T, pageFetcher fetcher.Fetcher, sink *metadatatest.SinkMock, writer storage.Writer) scheduler.Scheduler {
+	t.Helper()
+	return newPipelineSchedulerWithLimiterForTest(t, pageFetcher, sink, writer, newRateLimiterMockForTest(t))
+}
+
+// newPipelineSchedulerWithLimiterForTest is newPipelineSchedulerForTest with
+// the given rate limiter.
+func newPipelineSchedulerWithLimiterForTest(t *testing.T, pageFetcher fetcher.Fetcher, sink *metadatatest.SinkMock, writer storage.Writer, limiter *rateLimiterMock) scheduler.Scheduler {
+	t.Helper()
+	resolver := assets.NewLocalResolver(sink)
+	return newPipelineSchedulerWithResolverForTest(t, pageFetcher, sink, writer, limiter, &resolver)
+}
+
+// newPipelineSchedulerWithResolverForTest is newPipelineSchedulerWithLimiterForTest
+// with the given asset resolver.
+func newPipelineSchedulerWithResolverForTest(t *testing.T, pageFetcher fetcher.Fetcher, sink *metadatatest.SinkMock, writer storage.Writer, limiter *rateLimiterMock, resolver assets.Resolver) scheduler.Scheduler {
+	t.Helper()
+	realFrontier := frontier.NewCrawlFrontier()
+	realRobot := robots.NewCachedRobot(sink)
+	ext := extractor.NewDomExtractor(sink)
+	san := sanitizer.NewHTMLSanitizer(sink)
+	constraint := normalize.NewMarkdownConstraint(sink)
+
+	s := scheduler.NewSchedulerWithDeps(
+		context.Background(),
+		newMockFinalizer(t),
+		sink,
+		limiter,
+		&realFrontier,
+		pageFetcher,
+		&realRobot,
+		&ext,
+		&san,
+		mdconvert.NewRule(sink),
+		resolver,
+		&constraint,
+		writer,
+		newFailureJournalMockForTest(t),
+		stagedump.NewNoOpDumper(),
+		debug.NewNoOpLogger(),
+	)
+	return s
+}
+
+func TestScheduler_ReplayArchive_MatchesLiveCrawl(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, ok := archiveSitePages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(page))
+	}))
+	seed := *mustParseURL(server.URL + "/docs")
+	outputDir := t.TempDir()
+
+	// GIVEN a live crawl of the two-page site
+	liveConfig, err := config.WithDefault([]url.URL{seed}).WithOutputDir(outputDir).Build()
+	require.NoError(t, err)
+	liveSink := &metadatatest.SinkMock{}
+	liveFetcher := fetcher.NewHtmlFetcher(liveSink)
+	liveFetched, liveWriter := crawlPipelineForTest(t, &liveFetcher, liveSink, liveConfig)
+	require.Len(t, liveFetched, 2, "both pages should be fetched live")
+
+	// AND an archive of the fetched pages, after which the site goes away
+	archiveDir := t.TempDir()
+	for _, fetchedURL := range liveFetched {
+		require.NoError(t, fetcher.WriteArchiveEntry(archiveDir, fetcher.ArchiveEntry{
+			URL:     fetchedURL,
+			Status:  http.StatusOK,
+			Headers: http.Header{"Content-Type": {"text/html; charset=utf-8"}},
+			Body:    []byte(archiveSitePages[mustParseURL(fetchedURL).Path]),
+		}))
+	}
+	server.Close()
+
+	// WHEN the crawl is replayed from the archive
+	replayConfig, err := config.WithDefault([]url.URL{seed}).
+		WithOutputDir(outputDir).
+		WithReplayArchive(archiveDir).
+		Build()
+	require.NoError(t, err)
+	replaySink := &metadatatest.SinkMock{}
+	archiveFetcher := fetcher.NewArchiveFetcher(replaySink, archiveDir)
+	replayFetched, replayWriter := crawlPipelineForTest(t, &archiveFetcher, replaySink, replayConfig)
+
+	// THEN the same links are discovered and fetched in the same order
+	assert.Equal(t, liveFetched, replayFetched)
+	for _, event := range replaySink.FetchEvents {
+		if event.Kind() == metadata.KindPage {
+			assert.Equal(t, http.StatusOK, event.HTTPStatus())
+		}
+	}
+
+	// AND the same files are written with the same Markdown
+	assert.Equal(t, liveWriter.Paths(), replayWriter.Paths())
+	for _, path := range liveWriter.Paths() {
+		if !strings.HasSuffix(path, ".md") {
+			continue
+		}
+		live, _ := liveWriter.Get(path)
+		replayed, ok := replayWriter.Get(path)
+		require.True(t, ok, "replay should write %s", path)
+		assert.Equal(t, string(live), string(replayed))
+	}
+}
+
+func TestScheduler_ReplayArchive_MissingPageIsNotFound(t *testing.T) {
+	archiveDir := t.TempDir()
+	seed := *mustParseURL("https://docs.example.com/docs")
+	require.NoError(t, fetcher.WriteArchiveEntry(archiveDir, fetcher.ArchiveEntry{
+		URL:     seed.String(),
+		Status:  http.StatusOK,
+		Headers: http.Header{"Content-Type": {"text/html"}},
+		Body:    []byte(archiveSitePages["/docs"]),
+	}))
+
+	cfg, err := config.WithDefault([]url.URL{seed}).
+		WithOutputDir(t.TempDir()).
+		WithReplayArchive(archiveDir).
+		WithMaxAttempt(1).
+		Build()
+	require.NoError(t, err)
+	sink := &metadatatest.SinkMock{}
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	fetched, writer := crawlPipelineForTest(t, &archiveFetcher, sink, cfg)
+
+	// The linked page is not archived, so it fails like a live 404
+	assert.Equal(t, []string{
+		"https://docs.example.com/docs",
+		"https://docs.example.com/docs/install",
+	}, fetched)
+	assert.True(t, sink.RecordErrorCalled, "the missing page should be recorded as an error")
+	var pages []string
+	for _, path := range writer.Paths() {
+		if strings.HasSuffix(path, ".md") {
+			pages = append(pages, path)
+		}
+	}
+	assert.Len(t, pages, 1, "only the archived page should be written")
+}
@@ -0,0 +1,147 @@
+package scheduler_test
+
+import (
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/rohmanhakim/docs-crawler/internal/scheduler"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeMultiArticleArchiveForTest archives a release notes page made of
+// three articles, the first with an id.
+func writeMultiArticleArchiveForTest(t *testing.T) string {
+	t.Helper()
+	archiveDir := t.TempDir()
+	require.NoError(t, fetcher.WriteArchiveEntry(archiveDir, fetcher.ArchiveEntry{
+		URL:     "https://docs.example.com/releases",
+		Status:  http.StatusOK,
+		Headers: http.Header{"Content-Type": {"text/html"}},
+		Body: []byte(`<html><body><div class="feed">
+<article id="v2-0"><h1>Version 2.0</h1><p>Version 2.0 rewrites the crawl scheduler and adds output formats.</p></article>
+<article><h1>Version 1.1</h1><p>Version 1.1 brings retries for transient fetch failures and a fetch cache.</p></article>
+<article><h1>Version 1.0</h1><p>The first stable release, with robots.txt support and rate limiting.</p></article>
+</div></body></html>`),
+	}))
+	return archiveDir
+}
+
+func crawlMultiArticleForTest(t *testing.T, mode string) (*storage.MemoryWriter, []storage.WriteResult) {
+	t.Helper()
+	archiveDir := writeMultiArticleArchiveForTest(t)
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/releases")}).
+		WithOutputDir(t.TempDir()).
+		WithReplayArchive(archiveDir).
+		WithMultiArticleMode(mode).
+		Build()
+	require.NoError(t, err)
+
+	sink := &metadatatest.SinkMock{}
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	writer := storage.NewMemoryWriter()
+	execution, err := runPipelineForTest(t, &archiveFetcher, sink, writer, cfg)
+	require.NoError(t, err)
+	return writer, execution.WriteResults()
+}
+
+func TestScheduler_MultiArticleMode_SeparateOutputs_WritesEachArticle(t *testing.T) {
+	writer, results := crawlMultiArticleForTest(t, "separateOutputs")
+
+	require.Len(t, writtenPagePaths(writer), 3)
+	var sources, titles []string
+	for _, result := range results {
+		sources = append(sources, result.SourceURL())
+		content, ok := writer.Get(result.Path())
+		require.True(t, ok)
+		titles = append(titles, strings.SplitN(string(content), "\n", 2)[0])
+	}
+	assert.Equal(t, []string{
+		"https://docs.example.com/releases#v2-0",
+		"https://docs.example.com/releases#article-2",
+		"https://docs.example.com/releases#article-3",
+	}, sources)
+	assert.Equal(t, []string{"# Version 2.0", "# Version 1.1", "# Version 1.0"}, titles)
+}
+
+func TestScheduler_MultiArticleMode_Concatenate_WritesOnePage(t *testing.T) {
+	writer, results := crawlMultiArticleForTest(t, "concatenate")
+
+	require.Len(t, writtenPagePaths(writer), 1)
+	require.Len(t, results, 1)
+	content, _ := writer.Get(results[0].Path())
+	assert.Contains(t, string(content), "# Version 2.0")
+	assert.Contains(t, string(content), "## Version 1.1")
+	assert.Contains(t, string(content), "## Version 1.0")
+}
+
+func TestScheduler_MultiArticleMode_SeparateOutputs_KeysEachArticle(t *testing.T) {
+	archiveDir := t.TempDir()
+	writeReleases := func(secondArticle string) {
+		require.NoError(t, fetcher.WriteArchiveEntry(archiveDir, fetcher.ArchiveEntry{
+			URL:     "https://docs.example.com/releases",
+			Status:  http.StatusOK,
+			Headers: http.Header{"Content-Type": {"text/html"}},
+			Body: []byte(`<html><body><div class="feed">
+<article id="v2-0"><h1>Version 2.0</h1><p>Version 2.0 rewrites the crawl scheduler and adds output formats.</p></article>
+<article><h1>Version 1.1</h1><p>` + secondArticle + `</p></article>
+<article><h1>Version 1.0</h1><p>The first stable release, with robots.txt support. See the <a href="/guide">guide</a>.</p></article>
+</div></body></html>`),
+		}))
+	}
+	writeReleases("Version 1.1 brings retries for transient fetch failures and a fetch cache.")
+	require.NoError(t, fetcher.WriteArchiveEntry(archiveDir, fetcher.ArchiveEntry{
+		URL:     "https://docs.example.com/guide",
+		Status:  http.StatusOK,
+		Headers: http.Header{"Content-Type": {"text/html"}},
+		Body: []byte(`<html><body><main><h1>Guide</h1>
+<p>This guide has enough text to pass content extraction and be written.</p>
+<p>Retries arrived in <a href="/releases#article-2">1.1</a>; see all <a href="/releases">releases</a>.</p>
+</main></body></html>`),
+	}))
+
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/releases")}).
+		WithOutputDir("out").
+		WithReplayArchive(archiveDir).
+		WithMultiArticleMode("separateOutputs").
+		WithRelativeLinks(true).
+		WithMaxIterations(2).
+		WithRepeatInterval(time.Hour).
+		Build()
+	require.NoError(t, err)
+
+	writer := &recordingWriter{MemoryWriter: storage.NewMemoryWriter()}
+	var firstPass []string
+	sleeper := &passSleeper{interval: time.Hour, onSleep: func() {
+		firstPass = append([]string(nil), writer.written...)
+		writer.written = nil
+		// Only the second article changes between the passes
+		writeReleases("Version 1.1 brings retries, a fetch cache and, as of this edit, proxies.")
+	}}
+
+	sink := &metadatatest.SinkMock{}
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	_, err = runPipelineForTest(t, &archiveFetcher, sink, writer, cfg, func(s *scheduler.Scheduler) {
+		s.SetSleeper(sleeper)
+	})
+	require.NoError(t, err)
+
+	// Each article is compared with its own previous content
+	assert.Len(t, firstPass, 4)
+	assert.Equal(t, []string{"https://docs.example.com/releases#article-2"}, writer.written)
+
+	// Links resolve to the article named by the fragment, or to the first
+	// article without one
+	guide, ok := writer.Get(filepath.Join("out", pageFileNameForTest(t, "https://docs.example.com/guide")))
+	require.True(t, ok, "expected the guide to be written")
+	assert.Contains(t, string(guide), "[1.1]("+pageFileNameForTest(t, "https://docs.example.com/releases#article-2")+"#article-2)")
+	assert.Contains(t, string(guide), "[releases]("+pageFileNameForTest(t, "https://docs.example.com/releases#v2-0")+")")
+}
@@ -0,0 +1,65 @@
+package scheduler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/assets"
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_AssetManifest_WrittenAfterCrawl(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/docs":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<html><body><main><h1>Docs</h1>
+<p>The overview page, with enough text to pass content extraction.</p>
+<p><img src="/logo.png" alt="logo"></p>
+<p>Continue with the <a href="/docs/guide">guide</a>.</p></main></body></html>`))
+		case "/docs/guide":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<html><body><main><h1>Guide</h1>
+<p>The guide page, also with enough text to pass content extraction.</p>
+<p><img src="/logo.png" alt="logo"></p></main></body></html>`))
+		case "/logo.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte("logo-bytes"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	outputDir := t.TempDir()
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL(server.URL + "/docs")}).
+		WithOutputDir(outputDir).
+		Build()
+	require.NoError(t, err)
+
+	sink := &metadatatest.SinkMock{}
+	htmlFetcher := fetcher.NewHtmlFetcher(sink)
+	crawlPipelineForTest(t, &htmlFetcher, sink, cfg)
+
+	data, err := os.ReadFile(filepath.Join(outputDir, assets.AssetManifestFileName))
+	require.NoError(t, err, "expected the asset manifest in the output directory")
+	var manifest map[string]struct {
+		Pages    []string `json:"pages"`
+		RefCount int      `json:"refCount"`
+	}
+	require.NoError(t, json.Unmarshal(data, &manifest))
+	require.Len(t, manifest, 1)
+	for _, entry := range manifest {
+		assert.Equal(t, []string{server.URL + "/docs", server.URL + "/docs/guide"}, entry.Pages)
+		assert.Equal(t, 2, entry.RefCount)
+	}
+}
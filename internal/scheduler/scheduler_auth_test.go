@@ -0,0 +1,184 @@
+package scheduler_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/extractor"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/frontier"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/internal/robots"
+	"github.com/rohmanhakim/docs-crawler/internal/sanitizer"
+	"github.com/rohmanhakim/docs-crawler/internal/scheduler"
+	"github.com/rohmanhakim/docs-crawler/internal/stagedump"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/rohmanhakim/docs-crawler/pkg/debug"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const protectedPageHTML = `<!DOCTYPE html>
+<html>
+<head><title>Internal Guide</title></head>
+<body>
+<main>
+<h1>Internal Guide</h1>
+<p>This page is only served to clients that carry the session cookie set
+by the login endpoint, so fetching it proves the session persisted.</p>
+</main>
+</body>
+</html>`
+
+// newLoginServerForTest serves /login, which sets a session cookie, and
+// /docs, which requires it. Every request is appended to requests under mu.
+func newLoginServerForTest(t *testing.T, mu *sync.Mutex, requests *[]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		*requests = append(*requests, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/login":
+			if r.Method != http.MethodPost || r.FormValue("password") != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "valid", Path: "/"})
+			w.WriteHeader(http.StatusNoContent)
+		case "/docs":
+			if cookie, err := r.Cookie("session"); err != nil || cookie.Value != "valid" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(protectedPageHTML))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newAuthSchedulerForTest builds a scheduler with a real fetcher so page
+// requests go through the crawl's HTTP client.
+func newAuthSchedulerForTest(t *testing.T, memory *storage.MemoryWriter) *scheduler.Scheduler {
+	t.Helper()
+	mockRobot := NewRobotsMockForTest(t)
+	mockRobot.On("Init", mock.Anything, mock.Anything).Return()
+	mockRobot.OnDecide(mock.Anything, robots.Decision{Allowed: true, Reason: robots.EmptyRuleSet}, nil)
+
+	mockConvert := newConvertMockForTest(t)
+	setupConvertMockWithSuccess(mockConvert)
+	mockResolver := newResolverMockForTest(t)
+	setupResolverMockWithSuccess(mockResolver)
+	mockNormalize := newNormalizeMockForTest(t)
+	setupNormalizeMockWithSuccess(mockNormalize)
+
+	noopSink := &metadata.NoopSink{}
+	realFrontier := frontier.NewCrawlFrontier()
+	realFetcher := fetcher.NewHtmlFetcher(noopSink)
+	ext := extractor.NewDomExtractor(noopSink)
+	san := sanitizer.NewHTMLSanitizer(noopSink)
+	s := scheduler.NewSchedulerWithDeps(
+		context.Background(),
+		newMockFinalizer(t),
+		noopSink,
+		newRateLimiterMockForTest(t),
+		&realFrontier,
+		&realFetcher,
+		mockRobot,
+		&ext,
+		&san,
+		mockConvert,
+		mockResolver,
+		mockNormalize,
+		memory,
+		newFailureJournalMockForTest(t),
+		stagedump.NewNoOpDumper(),
+		debug.NewNoOpLogger(),
+	)
+	return &s
+}
+
+func TestScheduler_Authenticator_SessionPersistsForFetches(t *testing.T) {
+	// GIVEN a site whose docs require a cookie set by its login endpoint
+	var mu sync.Mutex
+	var requests []string
+	server := newLoginServerForTest(t, &mu, &requests)
+	memory := storage.NewMemoryWriter()
+	s := newAuthSchedulerForTest(t, memory)
+
+	s.SetAuthenticator(func(ctx context.Context, client *http.Client) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/login",
+			strings.NewReader("user=crawler&password=secret"))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusNoContent {
+			return errors.New("login rejected: " + resp.Status)
+		}
+		return nil
+	})
+
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL(server.URL + "/docs")}).
+		WithOutputDir(t.TempDir()).
+		Build()
+	assert.NoError(t, err)
+
+	// WHEN crawling
+	init, err := s.InitializeWithConfig(cfg)
+	assert.NoError(t, err)
+	execution, err := s.ExecuteCrawlingWithState(init)
+	assert.NoError(t, err)
+
+	// THEN the login ran first and the protected page was fetched with the session
+	mu.Lock()
+	defer mu.Unlock()
+	if assert.NotEmpty(t, requests) {
+		assert.Equal(t, "POST /login", requests[0])
+	}
+	assert.Contains(t, requests, "GET /docs")
+	assert.Len(t, execution.WriteResults(), 1)
+	assert.Equal(t, 0, execution.TotalErrors())
+}
+
+func TestScheduler_Authenticator_FailureAbortsCrawl(t *testing.T) {
+	// GIVEN an authenticator whose login is rejected
+	var mu sync.Mutex
+	var requests []string
+	server := newLoginServerForTest(t, &mu, &requests)
+	s := newAuthSchedulerForTest(t, storage.NewMemoryWriter())
+
+	loginErr := errors.New("login rejected")
+	s.SetAuthenticator(func(ctx context.Context, client *http.Client) error {
+		return loginErr
+	})
+
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL(server.URL + "/docs")}).Build()
+	assert.NoError(t, err)
+
+	// WHEN initializing the crawl
+	init, err := s.InitializeWithConfig(cfg)
+
+	// THEN initialization fails with the authenticator's error and nothing is fetched
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Nil(t, init)
+	assert.ErrorIs(t, err, loginErr)
+	assert.Empty(t, requests)
+}
@@ -0,0 +1,85 @@
+package scheduler_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/extractor"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/frontier"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/rohmanhakim/docs-crawler/internal/robots"
+	"github.com/rohmanhakim/docs-crawler/internal/sanitizer"
+	"github.com/rohmanhakim/docs-crawler/internal/scheduler"
+	"github.com/rohmanhakim/docs-crawler/internal/stagedump"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/rohmanhakim/docs-crawler/pkg/debug"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestScheduler_AutoTune_ThrottledFetchBacksOffRateLimiter(t *testing.T) {
+	// GIVEN a seed that answers 429 and a crawl with auto-tuning enabled
+	seedURL := *mustParseURL("https://example.com/")
+	mockRobot := NewRobotsMockForTest(t)
+	mockRobot.On("Init", mock.Anything, mock.Anything).Return()
+	mockRobot.OnDecide(mock.Anything, robots.Decision{Allowed: true, Reason: robots.EmptyRuleSet}, nil)
+	mockFetcher := new(fetcherMock)
+	mockFetcher.On("Init", mock.Anything, mock.Anything).Return()
+	mockFetcher.On("Fetch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
+		fetcher.FetchResult{},
+		fetcher.NewFetchError(fetcher.ErrCauseRequestTooMany, "429 Too Many Requests"),
+	)
+	mockLimiter := newRateLimiterMockForTest(t)
+
+	sink := &metadatatest.SinkMock{}
+	realFrontier := frontier.NewCrawlFrontier()
+	ext := extractor.NewDomExtractor(sink)
+	san := sanitizer.NewHTMLSanitizer(sink)
+	s := scheduler.NewSchedulerWithDeps(
+		context.Background(),
+		newMockFinalizer(t),
+		sink,
+		mockLimiter,
+		&realFrontier,
+		mockFetcher,
+		mockRobot,
+		&ext,
+		&san,
+		newConvertMockForTest(t),
+		newResolverMockForTest(t),
+		newNormalizeMockForTest(t),
+		storage.NewMemoryWriter(),
+		newFailureJournalMockForTest(t),
+		stagedump.NewNoOpDumper(),
+		debug.NewNoOpLogger(),
+	)
+
+	cfg, err := config.WithDefault([]url.URL{seedURL}).
+		WithAutoTune(config.NewAutoTune(2, 8, 100*time.Millisecond, time.Second)).
+		Build()
+	assert.NoError(t, err)
+
+	// WHEN crawling
+	init, err := s.InitializeWithConfig(cfg)
+	assert.NoError(t, err)
+	_, err = s.ExecuteCrawlingWithState(init)
+	assert.NoError(t, err)
+
+	// THEN the tuner started conservatively and reported the 429 at its bounds
+	if assert.Len(t, sink.TuningEvents, 2) {
+		initial, throttled := sink.TuningEvents[0], sink.TuningEvents[1]
+		assert.Equal(t, metadata.TuningReasonInitial, initial.Reason())
+		assert.Equal(t, 2, initial.RecommendedConcurrency())
+		assert.Equal(t, time.Second, initial.Delay())
+
+		assert.Equal(t, metadata.TuningReasonThrottled, throttled.Reason())
+		assert.Equal(t, 2, throttled.RecommendedConcurrency())
+		assert.Equal(t, time.Second, throttled.Delay())
+	}
+	mockLimiter.AssertCalled(t, "SetBaseDelay", time.Second)
+}
@@ -0,0 +1,135 @@
+package scheduler_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/assets"
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/mdconvert"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/rohmanhakim/docs-crawler/internal/scheduler"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/rohmanhakim/docs-crawler/pkg/failure"
+	"github.com/rohmanhakim/retrier"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// crawlWithBudgetsForTest crawls an archived site of pageCount pages with
+// the budgets set by configure.
+func crawlWithBudgetsForTest(t *testing.T, pageCount int, configure func(*config.Config) *config.Config) (scheduler.CrawlingExecution, error) {
+	t.Helper()
+	archiveDir := writeLinkedSiteArchiveForTest(t, pageCount)
+	cfg, err := configure(config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir("out").
+		WithReplayArchive(archiveDir)).
+		Build()
+	require.NoError(t, err)
+
+	sink := &metadatatest.SinkMock{}
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	return runPipelineForTest(t, &archiveFetcher, sink, storage.NewMemoryWriter(), cfg)
+}
+
+func TestScheduler_Budgets_StopsOnTightestBudget(t *testing.T) {
+	execution, err := crawlWithBudgetsForTest(t, 6, func(c *config.Config) *config.Config {
+		return c.WithMaxPages(3).
+			WithMaxOutputBytes(10 * 1024 * 1024).
+			WithMaxDuration(time.Hour).
+			WithMaxErrors(5)
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, scheduler.StopReasonMaxPages, execution.StopReason())
+	assert.Len(t, execution.WriteResults(), 3)
+}
+
+func TestScheduler_Budgets_CompletedWhenNoneSpent(t *testing.T) {
+	execution, err := crawlWithBudgetsForTest(t, 4, func(c *config.Config) *config.Config {
+		return c.WithMaxPages(10).WithMaxDuration(time.Hour).WithMaxErrors(5)
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, scheduler.StopReasonCompleted, execution.StopReason())
+	assert.Len(t, execution.WriteResults(), 4)
+}
+
+func TestScheduler_Budgets_ReportsOutputBudget(t *testing.T) {
+	execution, err := crawlWithBudgetsForTest(t, 6, func(c *config.Config) *config.Config {
+		return c.WithMaxPages(5).WithMaxOutputBytes(1)
+	})
+
+	require.ErrorIs(t, err, scheduler.ErrOutputBudgetExceeded)
+	assert.Equal(t, scheduler.StopReasonMaxBytes, execution.StopReason())
+	assert.Len(t, execution.WriteResults(), 1)
+}
+
+// assetErrorResolver resolves assets like the embedded resolver, then
+// reports a non-fatal asset error for every page.
+type assetErrorResolver struct {
+	*assets.LocalResolver
+}
+
+func (r assetErrorResolver) Resolve(
+	ctx context.Context,
+	pageUrl url.URL,
+	conversionResult mdconvert.ConversionResult,
+	resolveParam assets.ResolveParam,
+	retryOptions []retrier.RetryOption,
+) (assets.AssetfulMarkdownDoc, failure.ClassifiedError) {
+	doc, _ := r.LocalResolver.Resolve(ctx, pageUrl, conversionResult, resolveParam, retryOptions)
+	return doc, assets.NewAssetsError(assets.ErrCauseNetworkFailure, "asset request timed out")
+}
+
+// crawlWithMaxErrorsForTest crawls an archived site of pageCount pages with
+// the given page failure budget. Every written page reports an asset error;
+// the pages listed in missing are archived as 404s and fail.
+func crawlWithMaxErrorsForTest(t *testing.T, pageCount int, maxErrors int, missing ...int) (scheduler.CrawlingExecution, error) {
+	t.Helper()
+	archiveDir := writeLinkedSiteArchiveForTest(t, pageCount)
+	for _, page := range missing {
+		require.NoError(t, fetcher.WriteArchiveEntry(archiveDir, fetcher.ArchiveEntry{
+			URL:    fmt.Sprintf("https://docs.example.com/docs/page-%d", page),
+			Status: http.StatusNotFound,
+		}))
+	}
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir("out").
+		WithReplayArchive(archiveDir).
+		WithMaxAttempt(1).
+		WithMaxErrors(maxErrors).
+		Build()
+	require.NoError(t, err)
+
+	sink := &metadatatest.SinkMock{}
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	localResolver := assets.NewLocalResolver(sink)
+	s := newPipelineSchedulerWithResolverForTest(t, &archiveFetcher, sink, storage.NewMemoryWriter(),
+		newRateLimiterMockForTest(t), assetErrorResolver{&localResolver})
+	init, err := s.InitializeWithConfig(cfg)
+	require.NoError(t, err)
+	return s.ExecuteCrawlingWithState(init)
+}
+
+func TestScheduler_MaxErrors_IgnoresErrorsOfWrittenPages(t *testing.T) {
+	execution, err := crawlWithMaxErrorsForTest(t, 5, 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, scheduler.StopReasonCompleted, execution.StopReason())
+	assert.Len(t, execution.WriteResults(), 5)
+	assert.GreaterOrEqual(t, execution.TotalErrors(), 5)
+}
+
+func TestScheduler_MaxErrors_StopsOnFailedPages(t *testing.T) {
+	execution, err := crawlWithMaxErrorsForTest(t, 5, 2, 1, 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, scheduler.StopReasonMaxErrors, execution.StopReason())
+	assert.Len(t, execution.WriteResults(), 1)
+}
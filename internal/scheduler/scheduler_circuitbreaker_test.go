@@ -0,0 +1,94 @@
+package scheduler_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/rohmanhakim/docs-crawler/internal/scheduler"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// writeLinkedSiteArchiveForTest archives a site of pageCount pages under
+// https://docs.example.com, the index /docs linking to every other page,
+// and returns the archive directory.
+func writeLinkedSiteArchiveForTest(t *testing.T, pageCount int) string {
+	t.Helper()
+	archiveDir := t.TempDir()
+	var links strings.Builder
+	for i := 1; i < pageCount; i++ {
+		fmt.Fprintf(&links, `<li><a href="/docs/page-%d">Page %d</a></li>`, i, i)
+	}
+	writePage := func(path, title, body string) {
+		require.NoError(t, fetcher.WriteArchiveEntry(archiveDir, fetcher.ArchiveEntry{
+			URL:     "https://docs.example.com" + path,
+			Status:  http.StatusOK,
+			Headers: http.Header{"Content-Type": {"text/html"}},
+			Body: []byte(fmt.Sprintf(`<html><body><main><h1>%s</h1>
+<p>This page has enough text to pass content extraction and be written.</p>
+%s</main></body></html>`, title, body)),
+		}))
+	}
+	writePage("/docs", "Index", "<ul>"+links.String()+"</ul>")
+	for i := 1; i < pageCount; i++ {
+		writePage(fmt.Sprintf("/docs/page-%d", i), fmt.Sprintf("Page %d", i), "")
+	}
+	return archiveDir
+}
+
+// crawlWithFailingWritesForTest crawls an archived site of pageCount pages
+// with every Write failing. It returns the crawl error and the number of
+// Write calls.
+func crawlWithFailingWritesForTest(t *testing.T, pageCount int, threshold int) (error, int) {
+	t.Helper()
+	archiveDir := writeLinkedSiteArchiveForTest(t, pageCount)
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir(t.TempDir()).
+		WithReplayArchive(archiveDir).
+		WithCircuitBreakerThreshold(threshold).
+		Build()
+	require.NoError(t, err)
+
+	mockStorage := newStorageMockForTest(t)
+	mockStorage.On("Write", mock.Anything, mock.Anything, mock.Anything).
+		Return(storage.WriteResult{}, storage.NewStorageError(storage.ErrCauseWriteFailure, "read-only file system", "out"))
+
+	sink := &metadatatest.SinkMock{}
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	_, err = runPipelineForTest(t, &archiveFetcher, sink, mockStorage, cfg)
+
+	writes := 0
+	for _, call := range mockStorage.Calls {
+		if call.Method == "Write" {
+			writes++
+		}
+	}
+	return err, writes
+}
+
+func TestScheduler_CircuitBreaker_TripsAfterConsecutiveWriteFailures(t *testing.T) {
+	err, writes := crawlWithFailingWritesForTest(t, 6, 3)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, scheduler.ErrStageCircuitOpen)
+	var schedulerErr *scheduler.SchedulerError
+	require.ErrorAs(t, err, &schedulerErr)
+	assert.Equal(t, scheduler.ErrCauseStageCircuitOpen, schedulerErr.Cause)
+	assert.Contains(t, schedulerErr.Error(), "storage")
+	assert.Equal(t, 3, writes, "expected the crawl to stop at the threshold instead of writing every page")
+}
+
+func TestScheduler_CircuitBreaker_DisabledByDefault(t *testing.T) {
+	err, writes := crawlWithFailingWritesForTest(t, 6, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 6, writes, "expected every page to be attempted without a breaker")
+}
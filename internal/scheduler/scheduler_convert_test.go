@@ -2,16 +2,23 @@ package scheduler_test
 
 import (
 	"context"
+	"net/url"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/extractor"
 	"github.com/rohmanhakim/docs-crawler/internal/frontier"
+	"github.com/rohmanhakim/docs-crawler/internal/mdconvert"
 	"github.com/rohmanhakim/docs-crawler/internal/metadata"
 	"github.com/rohmanhakim/docs-crawler/internal/robots"
 	"github.com/rohmanhakim/docs-crawler/internal/sanitizer"
+	"github.com/rohmanhakim/docs-crawler/internal/scheduler"
+	"github.com/rohmanhakim/docs-crawler/internal/stagedump"
 	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/rohmanhakim/docs-crawler/pkg/debug"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"golang.org/x/net/html"
@@ -288,3 +295,47 @@ func TestScheduler_Convert_RecoverableError_ContinuesCrawl(t *testing.T) {
 	assert.NoError(t, execErr, "Recoverable convert error should not abort crawl")
 	mockConvert.AssertCalled(t, "Convert", mock.Anything, mock.Anything)
 }
+
+// TestScheduler_Convert_PreserveUnknownHTMLFromConfig verifies that the
+// conversion rule picks up preserveUnknownHTML from the config at init.
+func TestScheduler_Convert_PreserveUnknownHTMLFromConfig(t *testing.T) {
+	mockRobot := NewRobotsMockForTest(t)
+	mockRobot.On("Init", mock.Anything, mock.Anything).Return()
+	mockRobot.OnDecide(mock.Anything, robots.Decision{Allowed: true, Reason: robots.EmptyRuleSet}, nil)
+	mockFetcher := new(fetcherMock)
+	mockFetcher.On("Init", mock.Anything, mock.Anything).Return()
+
+	noopSink := &metadata.NoopSink{}
+	realFrontier := frontier.NewCrawlFrontier()
+	ext := extractor.NewDomExtractor(noopSink)
+	san := sanitizer.NewHTMLSanitizer(noopSink)
+	rule := mdconvert.NewRule(noopSink)
+	s := scheduler.NewSchedulerWithDeps(
+		context.Background(),
+		newMockFinalizer(t),
+		noopSink,
+		newRateLimiterMockForTest(t),
+		&realFrontier,
+		mockFetcher,
+		mockRobot,
+		&ext,
+		&san,
+		rule,
+		newResolverMockForTest(t),
+		newNormalizeMockForTest(t),
+		storage.NewMemoryWriter(),
+		newFailureJournalMockForTest(t),
+		stagedump.NewNoOpDumper(),
+		debug.NewNoOpLogger(),
+	)
+
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/")}).
+		WithPreserveUnknownHTML(true).
+		Build()
+	assert.NoError(t, err)
+
+	_, err = s.InitializeWithConfig(cfg)
+	assert.NoError(t, err)
+
+	assert.True(t, rule.PreserveUnknownHTML())
+}
@@ -0,0 +1,167 @@
+package scheduler_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/assets"
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/extractor"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/frontier"
+	"github.com/rohmanhakim/docs-crawler/internal/mdconvert"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/rohmanhakim/docs-crawler/internal/normalize"
+	"github.com/rohmanhakim/docs-crawler/internal/robots"
+	"github.com/rohmanhakim/docs-crawler/internal/sanitizer"
+	"github.com/rohmanhakim/docs-crawler/internal/scheduler"
+	"github.com/rohmanhakim/docs-crawler/internal/stagedump"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/rohmanhakim/docs-crawler/pkg/debug"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// crawlWithLimiterForTest crawls the site in archiveDir over the real
+// pipeline stages and returns the rate limiter mock the crawl set its host
+// timings on.
+func crawlWithLimiterForTest(t *testing.T, archiveDir string, cfg config.Config) *rateLimiterMock {
+	t.Helper()
+	sink := &metadatatest.SinkMock{}
+	limiter := newRateLimiterMockForTest(t)
+	realFrontier := frontier.NewCrawlFrontier()
+	realRobot := robots.NewCachedRobot(sink)
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	ext := extractor.NewDomExtractor(sink)
+	san := sanitizer.NewHTMLSanitizer(sink)
+	resolver := assets.NewLocalResolver(sink)
+	constraint := normalize.NewMarkdownConstraint(sink)
+
+	s := scheduler.NewSchedulerWithDeps(
+		context.Background(),
+		newMockFinalizer(t),
+		sink,
+		limiter,
+		&realFrontier,
+		&archiveFetcher,
+		&realRobot,
+		&ext,
+		&san,
+		mdconvert.NewRule(sink),
+		&resolver,
+		&constraint,
+		storage.NewMemoryWriter(),
+		newFailureJournalMockForTest(t),
+		stagedump.NewNoOpDumper(),
+		debug.NewNoOpLogger(),
+	)
+	init, err := s.InitializeWithConfig(cfg)
+	require.NoError(t, err)
+	_, err = s.ExecuteCrawlingWithState(init)
+	require.NoError(t, err)
+	return limiter
+}
+
+// writeRobotsForTest archives body as the robots.txt of docs.example.com.
+func writeRobotsForTest(t *testing.T, archiveDir string, body string) {
+	t.Helper()
+	require.NoError(t, fetcher.WriteArchiveEntry(archiveDir, fetcher.ArchiveEntry{
+		URL:     "https://docs.example.com/robots.txt",
+		Status:  http.StatusOK,
+		Headers: http.Header{"Content-Type": {"text/plain"}},
+		Body:    []byte(body),
+	}))
+}
+
+func TestScheduler_DefaultCrawlDelay_AppliedWhenRobotsSetsNone(t *testing.T) {
+	archiveDir := writeLinkedSiteArchiveForTest(t, 2)
+	writeRobotsForTest(t, archiveDir, "User-agent: *\nAllow: /\n")
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir("out").
+		WithReplayArchive(archiveDir).
+		WithDefaultCrawlDelay(3 * time.Second).
+		Build()
+	require.NoError(t, err)
+
+	limiter := crawlWithLimiterForTest(t, archiveDir, cfg)
+
+	limiter.AssertCalled(t, "SetResourceDelay", "docs.example.com", 3*time.Second)
+}
+
+func TestScheduler_DefaultCrawlDelay_LargerRobotsDelayWins(t *testing.T) {
+	archiveDir := writeLinkedSiteArchiveForTest(t, 2)
+	writeRobotsForTest(t, archiveDir, "User-agent: *\nAllow: /\nCrawl-delay: 10\n")
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir("out").
+		WithReplayArchive(archiveDir).
+		WithDefaultCrawlDelay(3 * time.Second).
+		Build()
+	require.NoError(t, err)
+
+	limiter := crawlWithLimiterForTest(t, archiveDir, cfg)
+
+	limiter.AssertCalled(t, "SetResourceDelay", "docs.example.com", 10*time.Second)
+	limiter.AssertNotCalled(t, "SetResourceDelay", "docs.example.com", 3*time.Second)
+}
+
+func TestScheduler_DefaultCrawlDelay_UnsetLeavesHostTimingsAlone(t *testing.T) {
+	archiveDir := writeLinkedSiteArchiveForTest(t, 2)
+	writeRobotsForTest(t, archiveDir, "User-agent: *\nAllow: /\n")
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir("out").
+		WithReplayArchive(archiveDir).
+		Build()
+	require.NoError(t, err)
+
+	limiter := crawlWithLimiterForTest(t, archiveDir, cfg)
+
+	limiter.AssertNotCalled(t, "SetResourceDelay", "docs.example.com", mock.Anything)
+}
+
+func TestScheduler_CrawlDelay_KeyedByEachHost(t *testing.T) {
+	archiveDir := t.TempDir()
+	writePage := func(pageURL, body string) {
+		require.NoError(t, fetcher.WriteArchiveEntry(archiveDir, fetcher.ArchiveEntry{
+			URL:     pageURL,
+			Status:  http.StatusOK,
+			Headers: http.Header{"Content-Type": {"text/html"}},
+			Body: []byte(`<html><body><main><h1>Page</h1>
+<p>This page has enough text to pass content extraction and be written.</p>
+` + body + `</main></body></html>`),
+		}))
+	}
+	writeRobots := func(host, body string) {
+		require.NoError(t, fetcher.WriteArchiveEntry(archiveDir, fetcher.ArchiveEntry{
+			URL:     "https://" + host + "/robots.txt",
+			Status:  http.StatusOK,
+			Headers: http.Header{"Content-Type": {"text/plain"}},
+			Body:    []byte(body),
+		}))
+	}
+	writePage("https://docs.example.com/docs", `<a href="https://api.example.com/reference">API</a>`)
+	writePage("https://api.example.com/reference", `<a href="https://docs.example.com/docs/guide">Guide</a>`)
+	writePage("https://docs.example.com/docs/guide", "")
+	writeRobots("docs.example.com", "User-agent: *\nAllow: /\nCrawl-delay: 5\n")
+	writeRobots("api.example.com", "User-agent: *\nAllow: /\nCrawl-delay: 10\n")
+
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir("out").
+		WithReplayArchive(archiveDir).
+		WithAllowedHosts(map[string]struct{}{"docs.example.com": {}, "api.example.com": {}}).
+		WithDefaultCrawlDelay(2 * time.Second).
+		Build()
+	require.NoError(t, err)
+
+	limiter := crawlWithLimiterForTest(t, archiveDir, cfg)
+
+	// Each host gets its own robots delay, and neither overwrites the other
+	limiter.AssertCalled(t, "SetResourceDelay", "docs.example.com", 5*time.Second)
+	limiter.AssertCalled(t, "SetResourceDelay", "api.example.com", 10*time.Second)
+	limiter.AssertNotCalled(t, "SetResourceDelay", "docs.example.com", 10*time.Second)
+	limiter.AssertNotCalled(t, "SetResourceDelay", "api.example.com", 5*time.Second)
+	// The delay is waited on the host each page was fetched from
+	limiter.AssertCalled(t, "Wait", mock.Anything, "api.example.com")
+}
@@ -0,0 +1,235 @@
+package scheduler_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/frontier"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/internal/robots"
+	"github.com/rohmanhakim/docs-crawler/internal/scheduler"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// crawlWindowTestRig bundles a scheduler wired with a fake clock and sleeper.
+type crawlWindowTestRig struct {
+	scheduler *scheduler.Scheduler
+	fetcher   *fetcherMock
+	frontier  *frontierMock
+	clock     *fakeClock
+	sleeper   *fakeSleeper
+}
+
+func newCrawlWindowTestRig(t *testing.T, ctx context.Context, now time.Time) crawlWindowTestRig {
+	t.Helper()
+	mockRobot := NewRobotsMockForTest(t)
+	mockRobot.OnDecide(mock.Anything, robots.Decision{
+		Allowed: true,
+		Reason:  robots.EmptyRuleSet,
+	}, nil)
+	mockRobot.On("Init", mock.Anything, mock.Anything).Return()
+
+	mockStorage := newStorageMockForTest(t)
+	mockStorage.On("Write", mock.Anything, mock.Anything, mock.Anything).Return(storage.WriteResult{}, nil)
+
+	mockFetcher := newFetcherMockForTest(t)
+	mockFrontier := newFrontierMockForTest(t)
+	s := createSchedulerForTest(
+		t,
+		ctx,
+		newMockFinalizer(t),
+		&metadata.NoopSink{},
+		newRateLimiterMockForTest(t),
+		mockFrontier,
+		mockRobot,
+		mockFetcher,
+		nil,
+		nil,
+		nil,
+		nil,
+		mockStorage,
+		newFailureJournalMockForTest(t),
+	)
+
+	clock := newFakeClock(now)
+	sleeper := newFakeSleeper(clock)
+	s.SetClock(clock)
+	s.SetSleeper(sleeper)
+
+	return crawlWindowTestRig{
+		scheduler: s,
+		fetcher:   mockFetcher,
+		frontier:  mockFrontier,
+		clock:     clock,
+		sleeper:   sleeper,
+	}
+}
+
+func crawlWindowConfig(t *testing.T, windows []config.TimeWindow) config.Config {
+	t.Helper()
+	seed, _ := url.Parse("https://example.com/docs")
+	cfg, err := config.WithDefault([]url.URL{*seed}).
+		WithMaxDepth(0).
+		WithCrawlWindows(windows).
+		WithOutputDir(t.TempDir()).
+		Build()
+	if err != nil {
+		t.Fatalf("failed to build config: %v", err)
+	}
+	return cfg
+}
+
+func TestCrawlWindow_OutsideWindow_SleepsUntilOpen(t *testing.T) {
+	// GIVEN a 22:00-06:00 window and a clock at 12:00
+	rig := newCrawlWindowTestRig(t, context.Background(), time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+	cfg := crawlWindowConfig(t, []config.TimeWindow{
+		config.NewTimeWindow(22*time.Hour, 6*time.Hour, ""),
+	})
+
+	init, err := rig.scheduler.InitializeWithConfig(cfg)
+	assert.NoError(t, err)
+
+	// WHEN the crawl is executed
+	result, err := rig.scheduler.ExecuteCrawlingWithState(init)
+
+	// THEN the scheduler sleeps until the window opens, then processes the page
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{10 * time.Hour}, rig.sleeper.sleeps)
+	assert.Equal(t, 1, result.TotalPages())
+	rig.fetcher.AssertNumberOfCalls(t, "Fetch", 1)
+}
+
+func TestCrawlWindow_InsideWindow_ProcessesWithoutSleeping(t *testing.T) {
+	// GIVEN a 22:00-06:00 window and a clock at 23:30
+	rig := newCrawlWindowTestRig(t, context.Background(), time.Date(2026, 3, 10, 23, 30, 0, 0, time.UTC))
+	cfg := crawlWindowConfig(t, []config.TimeWindow{
+		config.NewTimeWindow(22*time.Hour, 6*time.Hour, ""),
+	})
+
+	init, err := rig.scheduler.InitializeWithConfig(cfg)
+	assert.NoError(t, err)
+
+	result, err := rig.scheduler.ExecuteCrawlingWithState(init)
+
+	assert.NoError(t, err)
+	assert.Empty(t, rig.sleeper.sleeps)
+	assert.Equal(t, 1, result.TotalPages())
+}
+
+func TestCrawlWindow_EmptyWindows_AlwaysOn(t *testing.T) {
+	// GIVEN no crawl windows
+	rig := newCrawlWindowTestRig(t, context.Background(), time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+	cfg := crawlWindowConfig(t, nil)
+
+	init, err := rig.scheduler.InitializeWithConfig(cfg)
+	assert.NoError(t, err)
+
+	result, err := rig.scheduler.ExecuteCrawlingWithState(init)
+
+	// THEN crawling proceeds immediately
+	assert.NoError(t, err)
+	assert.Empty(t, rig.sleeper.sleeps)
+	assert.Equal(t, 1, result.TotalPages())
+}
+
+func TestCrawlWindow_OtherHostWindow_DoesNotApply(t *testing.T) {
+	// GIVEN a window scoped to a different host
+	rig := newCrawlWindowTestRig(t, context.Background(), time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+	cfg := crawlWindowConfig(t, []config.TimeWindow{
+		config.NewTimeWindow(22*time.Hour, 6*time.Hour, "partner.example.org"),
+	})
+
+	init, err := rig.scheduler.InitializeWithConfig(cfg)
+	assert.NoError(t, err)
+
+	result, err := rig.scheduler.ExecuteCrawlingWithState(init)
+
+	assert.NoError(t, err)
+	assert.Empty(t, rig.sleeper.sleeps)
+	assert.Equal(t, 1, result.TotalPages())
+}
+
+func TestCrawlWindow_EarliestWindowWins(t *testing.T) {
+	// GIVEN two windows, the 14:00 one opening before the 22:00 one
+	rig := newCrawlWindowTestRig(t, context.Background(), time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+	cfg := crawlWindowConfig(t, []config.TimeWindow{
+		config.NewTimeWindow(22*time.Hour, 6*time.Hour, ""),
+		config.NewTimeWindow(14*time.Hour, 15*time.Hour, "example.com"),
+	})
+
+	init, err := rig.scheduler.InitializeWithConfig(cfg)
+	assert.NoError(t, err)
+
+	_, err = rig.scheduler.ExecuteCrawlingWithState(init)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{2 * time.Hour}, rig.sleeper.sleeps)
+}
+
+func TestCrawlWindow_WindowOpensAfterDeadline_EndsCrawl(t *testing.T) {
+	// GIVEN a crawl deadline one minute after the clock's 12:00 and a window
+	// opening at 22:00; the clock runs a day ahead so the real deadline stays in the future
+	now := time.Now().UTC().Truncate(24 * time.Hour).Add(36 * time.Hour)
+	ctx, cancel := context.WithDeadline(context.Background(), now.Add(time.Minute))
+	defer cancel()
+	rig := newCrawlWindowTestRig(t, ctx, now)
+	cfg := crawlWindowConfig(t, []config.TimeWindow{
+		config.NewTimeWindow(22*time.Hour, 6*time.Hour, ""),
+	})
+
+	init, err := rig.scheduler.InitializeWithConfig(cfg)
+	assert.NoError(t, err)
+
+	result, err := rig.scheduler.ExecuteCrawlingWithState(init)
+
+	// THEN the crawl ends gracefully without sleeping or fetching
+	assert.NoError(t, err)
+	assert.Empty(t, rig.sleeper.sleeps)
+	assert.Equal(t, 0, result.TotalPages())
+	rig.fetcher.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCrawlWindow_WaitLongerThanRequestTimeout_Sleeps(t *testing.T) {
+	// GIVEN no injected context, so the scheduler's own context only carries
+	// the per-request timeout, and a window opening 10 hours away
+	rig := newCrawlWindowTestRig(t, nil, time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+	cfg := crawlWindowConfig(t, []config.TimeWindow{
+		config.NewTimeWindow(22*time.Hour, 6*time.Hour, ""),
+	})
+
+	init, err := rig.scheduler.InitializeWithConfig(cfg)
+	assert.NoError(t, err)
+
+	result, err := rig.scheduler.ExecuteCrawlingWithState(init)
+
+	// THEN the request timeout does not cut the wait short
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{10 * time.Hour}, rig.sleeper.sleeps)
+	assert.Equal(t, 1, result.TotalPages())
+}
+
+func TestCrawlWindow_ChecksDequeuedPageHost(t *testing.T) {
+	// GIVEN a window scoped to a partner host and a queued page on that host,
+	// while the seed host has no window
+	rig := newCrawlWindowTestRig(t, context.Background(), time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+	cfg := crawlWindowConfig(t, []config.TimeWindow{
+		config.NewTimeWindow(22*time.Hour, 6*time.Hour, "partner.example.org"),
+	})
+
+	init, err := rig.scheduler.InitializeWithConfig(cfg)
+	assert.NoError(t, err)
+	partnerURL, _ := url.Parse("https://partner.example.org/docs")
+	rig.frontier.Enqueue(frontier.NewCrawlToken(*partnerURL, 1))
+
+	result, err := rig.scheduler.ExecuteCrawlingWithState(init)
+
+	// THEN only the partner page waits for its window
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{10 * time.Hour}, rig.sleeper.sleeps)
+	assert.Equal(t, 2, result.TotalPages())
+}
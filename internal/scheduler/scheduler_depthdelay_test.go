@@ -0,0 +1,64 @@
+package scheduler_test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/rohmanhakim/docs-crawler/internal/scheduler"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// crawlPageChainWithSleeperForTest crawls the /docs/1 -> /docs/4 chain
+// (depths 0 to 3) and returns the sleeps the scheduler requested.
+func crawlPageChainWithSleeperForTest(t *testing.T, configure func(*config.Config)) []time.Duration {
+	t.Helper()
+	archiveDir := writePageChainArchiveForTest(t)
+	builder := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs/1")}).
+		WithOutputDir("out").
+		WithReplayArchive(archiveDir).
+		WithBaseDelay(time.Second)
+	configure(builder)
+	cfg, err := builder.Build()
+	require.NoError(t, err)
+
+	sink := &metadatatest.SinkMock{}
+	writer := storage.NewMemoryWriter()
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	sleeper := newFakeSleeper(newFakeClock(time.Now()))
+	execution, err := runPipelineForTest(t, &archiveFetcher, sink, writer, cfg, func(s *scheduler.Scheduler) {
+		s.SetSleeper(sleeper)
+	})
+	require.NoError(t, err)
+	require.Len(t, execution.WriteResults(), 4)
+	return sleeper.sleeps
+}
+
+func TestScheduler_DepthDelayFactor_DeeperPagesWaitLonger(t *testing.T) {
+	sleeps := crawlPageChainWithSleeperForTest(t, func(c *config.Config) {
+		c.WithDepthDelayFactor(0.5)
+	})
+
+	// Depth 0 waits only the base delay; depths 1 to 3 wait the excess of
+	// 1s * (1 + 0.5*d) over it
+	assert.Equal(t, []time.Duration{500 * time.Millisecond, time.Second, 1500 * time.Millisecond}, sleeps)
+}
+
+func TestScheduler_DepthDelayFactor_CappedAtMax(t *testing.T) {
+	sleeps := crawlPageChainWithSleeperForTest(t, func(c *config.Config) {
+		c.WithDepthDelayFactor(1).WithMaxDepthDelay(2 * time.Second)
+	})
+
+	assert.Equal(t, []time.Duration{time.Second, time.Second, time.Second}, sleeps)
+}
+
+func TestScheduler_DepthDelayFactor_ZeroKeepsCurrentBehavior(t *testing.T) {
+	sleeps := crawlPageChainWithSleeperForTest(t, func(c *config.Config) {})
+
+	assert.Empty(t, sleeps)
+}
@@ -0,0 +1,135 @@
+package scheduler_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/frontier"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/internal/robots"
+	"github.com/rohmanhakim/docs-crawler/internal/scheduler"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const duplicateContentHTML = `<!DOCTYPE html>
+<html>
+<head><title>Mirrored</title></head>
+<body>
+<main>
+<h1>Mirrored Page</h1>
+<p>The same guide is reachable from several legacy paths, and every one of
+them serves exactly this content.</p>
+</main>
+</body>
+</html>`
+
+// duplicateSchedulerForTest builds a scheduler that crawls pageURLs in order,
+// each serving identical HTML.
+func duplicateSchedulerForTest(t *testing.T, pageURLs []url.URL, mockStorage *storageMock) *scheduler.Scheduler {
+	t.Helper()
+
+	mockRobot := NewRobotsMockForTest(t)
+	mockRobot.On("Init", mock.Anything, mock.Anything).Return()
+	mockRobot.OnDecide(mock.Anything, robots.Decision{Allowed: true, Reason: robots.EmptyRuleSet}, nil)
+
+	mockFetcher := new(fetcherMock)
+	mockFetcher.On("Init", mock.Anything, mock.Anything).Return()
+	mockFrontier := newFrontierMockForTest(t)
+	mockFrontier.disableAutoEnqueue = true
+	for _, pageURL := range pageURLs {
+		mockFetcher.On("Fetch", mock.Anything, mock.Anything, pageURL, mock.Anything).Return(
+			fetcher.NewFetchResultForTest(
+				pageURL,
+				[]byte(duplicateContentHTML),
+				200,
+				"text/html",
+				map[string]string{"Content-Type": "text/html"},
+				time.Now(),
+			), nil)
+		mockFrontier.OnDequeue(frontier.NewCrawlToken(pageURL, 0), true).Once()
+	}
+	mockFrontier.OnDequeue(frontier.CrawlToken{}, false)
+
+	return createSchedulerForTest(
+		t,
+		context.Background(),
+		newMockFinalizer(t),
+		&metadata.NoopSink{},
+		newRateLimiterMockForTest(t),
+		mockFrontier,
+		mockRobot,
+		mockFetcher,
+		nil,
+		nil,
+		nil,
+		nil,
+		mockStorage,
+		newFailureJournalMockForTest(t),
+	)
+}
+
+func TestScheduler_DuplicateContent_AliasGroupsIdenticalPages(t *testing.T) {
+	// GIVEN three URLs serving identical content
+	pageURLs := []url.URL{
+		*mustParseURL("https://example.com/guide"),
+		*mustParseURL("https://example.com/legacy/guide"),
+		*mustParseURL("https://example.com/old/guide"),
+	}
+	mockStorage := newStorageMockForTest(t)
+	written := storage.NewWriteResult("abc123", "output/abc123.md", "sha256:x")
+	mockStorage.On("Write", mock.Anything, mock.Anything, mock.Anything).Return(written, nil).Once()
+
+	s := duplicateSchedulerForTest(t, pageURLs, mockStorage)
+	cfg, err := config.WithDefault(pageURLs[:1]).WithDuplicateContent("alias").Build()
+	assert.NoError(t, err)
+
+	// WHEN crawling with duplicate content grouped as aliases
+	init, err := s.InitializeWithConfig(cfg)
+	assert.NoError(t, err)
+	exec, err := s.ExecuteCrawlingWithState(init)
+	assert.NoError(t, err)
+
+	// THEN only the first page is written
+	mockStorage.AssertNumberOfCalls(t, "Write", 1)
+	results := exec.WriteResults()
+	if assert.Len(t, results, 1) {
+		// AND the other two URLs are recorded as its aliases
+		assert.Equal(t, "output/abc123.md", results[0].Path())
+		assert.Equal(t, pageURLs[1:], results[0].Aliases())
+	}
+	assert.Equal(t, 0, exec.TotalErrors())
+}
+
+func TestScheduler_DuplicateContent_WriteModeWritesEveryPage(t *testing.T) {
+	// GIVEN three URLs serving identical content
+	pageURLs := []url.URL{
+		*mustParseURL("https://example.com/guide"),
+		*mustParseURL("https://example.com/legacy/guide"),
+		*mustParseURL("https://example.com/old/guide"),
+	}
+	mockStorage := newStorageMockForTest(t)
+	mockStorage.On("Write", mock.Anything, mock.Anything, mock.Anything).
+		Return(storage.NewWriteResult("abc123", "output/abc123.md", "sha256:x"), nil)
+
+	s := duplicateSchedulerForTest(t, pageURLs, mockStorage)
+	cfg, err := config.WithDefault(pageURLs[:1]).Build()
+	assert.NoError(t, err)
+
+	// WHEN crawling with the default duplicate content mode
+	init, err := s.InitializeWithConfig(cfg)
+	assert.NoError(t, err)
+	exec, err := s.ExecuteCrawlingWithState(init)
+	assert.NoError(t, err)
+
+	// THEN every page is written without aliases
+	mockStorage.AssertNumberOfCalls(t, "Write", 3)
+	for _, result := range exec.WriteResults() {
+		assert.Empty(t, result.Aliases())
+	}
+}
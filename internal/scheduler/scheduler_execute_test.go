@@ -0,0 +1,85 @@
+package scheduler_test
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_ExecuteWithConfig_MatchesConfigFile(t *testing.T) {
+	archiveDir := writeLinkedSiteArchiveForTest(t, 4)
+	outputDir := t.TempDir()
+
+	// GIVEN a crawl loaded from a config file
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	configData := fmt.Sprintf(`{
+		"seedUrls": ["https://docs.example.com/docs"],
+		"outputDir": %q,
+		"replayArchive": %q,
+		"maxDepth": 2
+	}`, outputDir, archiveDir)
+	require.NoError(t, os.WriteFile(configPath, []byte(configData), 0644))
+
+	fileSink := &metadatatest.SinkMock{}
+	fileFetcher := fetcher.NewArchiveFetcher(fileSink, archiveDir)
+	fileWriter := storage.NewMemoryWriter()
+	fileScheduler := newPipelineSchedulerForTest(t, &fileFetcher, fileSink, fileWriter)
+	init, err := fileScheduler.InitializeCrawling(configPath)
+	require.NoError(t, err)
+	fileExec, err := fileScheduler.ExecuteCrawlingWithState(init)
+	require.NoError(t, err)
+
+	// WHEN the same config is built in code and executed directly
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir(outputDir).
+		WithReplayArchive(archiveDir).
+		WithMaxDepth(2).
+		Build()
+	require.NoError(t, err)
+
+	builtSink := &metadatatest.SinkMock{}
+	builtFetcher := fetcher.NewArchiveFetcher(builtSink, archiveDir)
+	builtWriter := storage.NewMemoryWriter()
+	builtScheduler := newPipelineSchedulerForTest(t, &builtFetcher, builtSink, builtWriter)
+	builtExec, err := builtScheduler.ExecuteWithConfig(context.Background(), cfg)
+	require.NoError(t, err)
+
+	// THEN both write the same pages with the same stats
+	assert.Len(t, builtExec.WriteResults(), 4)
+	assert.Equal(t, fileExec.WriteResults(), builtExec.WriteResults())
+	assert.Equal(t, fileWriter.Paths(), builtWriter.Paths())
+	for _, path := range fileWriter.Paths() {
+		// Each run snapshots its own random seed
+		if filepath.Base(path) == storage.ConfigSnapshotFileName {
+			continue
+		}
+		fileDoc, _ := fileWriter.Get(path)
+		builtDoc, _ := builtWriter.Get(path)
+		assert.Equal(t, fileDoc, builtDoc, path)
+	}
+	assert.Equal(t, fileExec.TotalPages(), builtExec.TotalPages())
+	assert.Equal(t, fileExec.TotalAssets(), builtExec.TotalAssets())
+	assert.Equal(t, fileExec.TotalErrors(), builtExec.TotalErrors())
+	assert.Equal(t, fileExec.StopReason(), builtExec.StopReason())
+}
+
+func TestScheduler_ExecuteWithConfig_InvalidConfig(t *testing.T) {
+	sink := &metadatatest.SinkMock{}
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, t.TempDir())
+	s := newPipelineSchedulerForTest(t, &archiveFetcher, sink, storage.NewMemoryWriter())
+
+	_, err := s.ExecuteWithConfig(context.Background(), config.Config{})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "error initializing crawl")
+}
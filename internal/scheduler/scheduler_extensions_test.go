@@ -0,0 +1,78 @@
+package scheduler_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/stretchr/testify/assert"
+)
+
+const excludedExtensionsHTML = `<!DOCTYPE html>
+<html>
+<head><title>Downloads</title></head>
+<body>
+<main>
+<h1>Downloads</h1>
+<p>This page links to documentation pages and to files that are not pages,
+so the scheduler's excluded extension check can be verified.</p>
+<p>Links: <a href="/downloads/release.zip">archive</a>,
+<a href="/img/Logo.PNG">logo</a>,
+<a href="/dist/app.TAR.GZ">tarball</a>,
+<a href="/guide.html">guide</a>,
+<a href="/reference">reference</a>,
+<a href="/README.md">readme</a>.</p>
+</main>
+</body>
+</html>`
+
+func excludedExtensionsConfig(t *testing.T, extensions []string) config.Config {
+	t.Helper()
+	seedURL, _ := url.Parse("https://docs.example.com/")
+	builder := config.WithDefault([]url.URL{*seedURL}).WithOutputDir(t.TempDir())
+	if extensions != nil {
+		builder = builder.WithExcludedExtensions(extensions)
+	}
+	cfg, err := builder.Build()
+	assert.NoError(t, err)
+	return cfg
+}
+
+func TestScheduler_ExcludedExtensions_DefaultsSkipBinaries(t *testing.T) {
+	sink := &metadatatest.SinkMock{}
+
+	candidates := crawlSinglePageWithSinkForTest(t, excludedExtensionsConfig(t, nil), excludedExtensionsHTML, nil, sink)
+
+	var submitted []string
+	for _, candidate := range candidates {
+		target := candidate.TargetURL()
+		submitted = append(submitted, target.String())
+	}
+	assert.Equal(t, []string{
+		"https://docs.example.com/guide.html",
+		"https://docs.example.com/reference",
+		"https://docs.example.com/README.md",
+	}, submitted)
+
+	var skipped []string
+	for _, skip := range sink.SkipEvents {
+		assert.Equal(t, metadata.SkipReasonExcludedExtension, skip.Reason())
+		assert.Equal(t, "1", skipAttr(skip, metadata.AttrDepth))
+		skipped = append(skipped, skip.SkippedURL())
+	}
+	// Matching is case-insensitive and covers multi-part extensions
+	assert.ElementsMatch(t, []string{
+		"https://docs.example.com/downloads/release.zip",
+		"https://docs.example.com/img/Logo.PNG",
+		"https://docs.example.com/dist/app.TAR.GZ",
+	}, skipped)
+	assert.False(t, sink.RecordErrorCalled, "excluded extensions must not be recorded as errors")
+}
+
+func TestScheduler_ExcludedExtensions_EmptyListAdmitsEverything(t *testing.T) {
+	submitted := crawlSinglePageForTest(t, excludedExtensionsConfig(t, []string{}), excludedExtensionsHTML)
+
+	assert.Len(t, submitted, 6)
+}
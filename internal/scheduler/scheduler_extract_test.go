@@ -148,6 +148,9 @@ func TestScheduler_Extract_SetExtractParamCalledWithCustomValues(t *testing.T) {
 			MaxLinkDensity:      0.9,
 		},
 		SelectorBlacklist: []string{},
+		NoscriptMode:      extractor.NoscriptModePreferWhenEmpty,
+		StripByRole:       true,
+		MultiArticleMode:  extractor.MultiArticleLargest,
 	}
 	// Set up extractor expectations with custom params
 	mockExtractor.On("SetExtractParam", customParams).Return()
@@ -213,7 +216,8 @@ func TestScheduler_Extract_SetExtractParamCalledWithCustomValues(t *testing.T) {
 		"thresholdMinNonWhitespace": 60,
 		"thresholdMinHeadings": 1,
 		"thresholdMinParagraphsOrCode": 2,
-		"thresholdMaxLinkDensity": 0.9
+		"thresholdMaxLinkDensity": 0.9,
+		"noscriptMode": "preferWhenEmpty"
 	}`
 	err := os.WriteFile(configPath, []byte(configData), 0644)
 	assert.NoError(t, err)
@@ -256,6 +260,9 @@ func TestScheduler_Extract_UsesConfiguredParams(t *testing.T) {
 			MaxLinkDensity:      0.8,
 		},
 		SelectorBlacklist: []string{},
+		NoscriptMode:      extractor.NoscriptModeDrop,
+		StripByRole:       true,
+		MultiArticleMode:  extractor.MultiArticleLargest,
 	}
 	// Set up extractor expectations with custom params
 	mockExtractor.On("SetExtractParam", customParams).Return()
@@ -374,6 +381,9 @@ func TestScheduler_Extract_DefaultParamsStructure(t *testing.T) {
 			MaxLinkDensity:      0.8,
 		},
 		SelectorBlacklist: []string{},
+		NoscriptMode:      extractor.NoscriptModeDrop,
+		StripByRole:       true,
+		MultiArticleMode:  extractor.MultiArticleLargest,
 	}
 
 	// Verify the default parameters match
@@ -0,0 +1,42 @@
+package scheduler_test
+
+import (
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_MarkdownPage_PassedThroughUnchanged(t *testing.T) {
+	markdownBody := "# Guide\n\nInstall the tool, then run it with `--help` to list the commands.\n\n```sh\ntool --help\n```"
+	archiveDir := t.TempDir()
+	require.NoError(t, fetcher.WriteArchiveEntry(archiveDir, fetcher.ArchiveEntry{
+		URL:     "https://docs.example.com/guide.md",
+		Status:  http.StatusOK,
+		Headers: http.Header{"Content-Type": {"text/markdown; charset=utf-8"}},
+		Body:    []byte(markdownBody),
+	}))
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/guide.md")}).
+		WithOutputDir("out").
+		WithReplayArchive(archiveDir).
+		WithAllowedContentTypes([]string{"text/html", "text/markdown"}).
+		Build()
+	require.NoError(t, err)
+
+	sink := &metadatatest.SinkMock{}
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	_, writer := crawlPipelineForTest(t, &archiveFetcher, sink, cfg)
+
+	name, err := storage.PageFileName("https://docs.example.com/guide.md", cfg.HashAlgo())
+	require.NoError(t, err)
+	written, ok := writer.Get(filepath.Join("out", name))
+	require.True(t, ok, "expected the Markdown page to be written")
+	assert.Contains(t, string(written), markdownBody)
+}
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/rohmanhakim/docs-crawler/internal/config"
 	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
 	"github.com/rohmanhakim/docs-crawler/internal/frontier"
 	"github.com/rohmanhakim/docs-crawler/internal/metadata"
@@ -682,3 +683,50 @@ func TestScheduler_Fetcher_HTTPErrorCodes_Handled(t *testing.T) {
 		})
 	}
 }
+
+// TestScheduler_Fetcher_ReceivesFetchParamFromConfig verifies that preflight,
+// content type, and size options from the config reach the fetcher.
+func TestScheduler_Fetcher_ReceivesFetchParamFromConfig(t *testing.T) {
+	mockRobot := NewRobotsMockForTest(t)
+	mockRobot.On("Init", mock.Anything, mock.Anything).Return()
+	mockRobot.OnDecide(mock.Anything, robots.Decision{Allowed: true, Reason: robots.EmptyRuleSet}, nil)
+	mockFetcher := newFetcherMockForTest(t)
+
+	s := createSchedulerForTest(
+		t,
+		context.Background(),
+		newMockFinalizer(t),
+		&metadata.NoopSink{},
+		newRateLimiterMockForTest(t),
+		newFrontierMockForTest(t),
+		mockRobot,
+		mockFetcher,
+		nil,
+		nil,
+		nil,
+		nil,
+		newStorageMockForTest(t),
+		newFailureJournalMockForTest(t),
+	)
+
+	seed, _ := url.Parse("https://example.com/docs")
+	cfg, err := config.WithDefault([]url.URL{*seed}).
+		WithPreflightHead(true).
+		WithAllowedContentTypes([]string{"text/html"}).
+		WithMaxResponseBytes(4096).
+		Build()
+	assert.NoError(t, err)
+
+	_, err = s.InitializeWithConfig(cfg)
+	assert.NoError(t, err)
+
+	// Funcs never compare equal, so check the retry jitter apart
+	param := mockFetcher.param
+	assert.NotNil(t, param.RetryJitter)
+	param.RetryJitter = nil
+	assert.Equal(t, fetcher.FetchParam{
+		PreflightHead:       true,
+		AllowedContentTypes: []string{"text/html"},
+		MaxResponseBytes:    4096,
+	}, param)
+}
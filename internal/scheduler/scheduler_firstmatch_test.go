@@ -0,0 +1,113 @@
+package scheduler_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/rohmanhakim/docs-crawler/internal/scheduler"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writePageChainArchiveForTest archives /docs/1 through /docs/4, each page
+// linking only to the next, so they are crawled in order. Only the third
+// page mentions the "flux capacitor".
+func writePageChainArchiveForTest(t *testing.T) string {
+	t.Helper()
+	archiveDir := t.TempDir()
+	for i := 1; i <= 4; i++ {
+		topic := "an ordinary topic"
+		if i == 3 {
+			topic = "the flux capacitor"
+		}
+		next := ""
+		if i < 4 {
+			next = fmt.Sprintf(`<p><a href="/docs/%d">Next</a></p>`, i+1)
+		}
+		require.NoError(t, fetcher.WriteArchiveEntry(archiveDir, fetcher.ArchiveEntry{
+			URL:     fmt.Sprintf("https://docs.example.com/docs/%d", i),
+			Status:  http.StatusOK,
+			Headers: http.Header{"Content-Type": {"text/html"}},
+			Body: []byte(fmt.Sprintf(`<html><body><main><h1>Page %d</h1>
+<p>This page explains %s in enough words to pass content extraction.</p>
+%s</main></body></html>`, i, topic, next)),
+		}))
+	}
+	return archiveDir
+}
+
+func crawlPageChainForTest(t *testing.T, configure func(*config.Config)) scheduler.CrawlingExecution {
+	t.Helper()
+	archiveDir := writePageChainArchiveForTest(t)
+	builder := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs/1")}).
+		WithOutputDir("out").
+		WithReplayArchive(archiveDir)
+	configure(builder)
+	cfg, err := builder.Build()
+	require.NoError(t, err)
+
+	sink := &metadatatest.SinkMock{}
+	writer := storage.NewMemoryWriter()
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	execution, err := runPipelineForTest(t, &archiveFetcher, sink, writer, cfg)
+	require.NoError(t, err)
+	return execution
+}
+
+func writtenSourceURLs(execution scheduler.CrawlingExecution) []string {
+	var written []string
+	for _, result := range execution.WriteResults() {
+		written = append(written, result.SourceURL())
+	}
+	return written
+}
+
+func TestScheduler_StopAfterFirstMatch_StopsAfterMatchingContent(t *testing.T) {
+	execution := crawlPageChainForTest(t, func(cfg *config.Config) {
+		cfg.WithStopAfterFirstMatch(true).WithMatchPattern(`(?i)flux capacitor`)
+	})
+
+	assert.Equal(t, []string{
+		"https://docs.example.com/docs/1",
+		"https://docs.example.com/docs/2",
+		"https://docs.example.com/docs/3",
+	}, writtenSourceURLs(execution))
+	assert.Equal(t, scheduler.StopReasonFirstMatch, execution.StopReason())
+}
+
+func TestScheduler_StopAfterFirstMatch_MatchesURL(t *testing.T) {
+	execution := crawlPageChainForTest(t, func(cfg *config.Config) {
+		cfg.WithStopAfterFirstMatch(true).WithMatchPattern(`/docs/3$`)
+	})
+
+	assert.Equal(t, []string{
+		"https://docs.example.com/docs/1",
+		"https://docs.example.com/docs/2",
+		"https://docs.example.com/docs/3",
+	}, writtenSourceURLs(execution))
+	assert.Equal(t, scheduler.StopReasonFirstMatch, execution.StopReason())
+}
+
+func TestScheduler_StopAfterFirstMatch_NoMatchCrawlsEverything(t *testing.T) {
+	execution := crawlPageChainForTest(t, func(cfg *config.Config) {
+		cfg.WithStopAfterFirstMatch(true).WithMatchPattern(`time machine`)
+	})
+
+	assert.Len(t, execution.WriteResults(), 4)
+	assert.Equal(t, scheduler.StopReasonCompleted, execution.StopReason())
+}
+
+func TestScheduler_MatchPattern_WithoutStopCrawlsEverything(t *testing.T) {
+	execution := crawlPageChainForTest(t, func(cfg *config.Config) {
+		cfg.WithMatchPattern(`flux capacitor`)
+	})
+
+	assert.Len(t, execution.WriteResults(), 4)
+	assert.Equal(t, scheduler.StopReasonCompleted, execution.StopReason())
+}
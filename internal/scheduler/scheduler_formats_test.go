@@ -0,0 +1,84 @@
+package scheduler_test
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_OutputFormats_MarkdownAndText(t *testing.T) {
+	archiveDir := writeLinkedSiteArchiveForTest(t, 3)
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir("out").
+		WithReplayArchive(archiveDir).
+		WithOutputFormats([]string{config.OutputFormatMarkdown, config.OutputFormatText}).
+		Build()
+	require.NoError(t, err)
+
+	sink := &metadatatest.SinkMock{}
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	writer := storage.NewMemoryWriter()
+	execution, err := runPipelineForTest(t, &archiveFetcher, sink, writer, cfg)
+	require.NoError(t, err)
+	require.Len(t, execution.WriteResults(), 3)
+
+	for _, result := range execution.WriteResults() {
+		base := strings.TrimSuffix(result.Path(), ".md")
+		textPath := base + ".txt"
+		assert.Equal(t, map[string]string{config.OutputFormatText: textPath}, result.Formats())
+
+		markdown, ok := writer.Get(result.Path())
+		require.True(t, ok, "missing %s", result.Path())
+		text, ok := writer.Get(textPath)
+		require.True(t, ok, "missing %s", textPath)
+
+		assert.Contains(t, string(markdown), "This page has enough text")
+		assert.Contains(t, string(text), "This page has enough text to pass content extraction and be written.\n")
+		assert.NotContains(t, string(text), "# ")
+		assert.NotContains(t, string(text), "<p>")
+		assert.Equal(t, filepath.Base(base), strings.TrimSuffix(filepath.Base(textPath), ".txt"))
+	}
+
+	// Only Markdown and text were written, plus the manifest
+	var htmlPaths []string
+	for _, path := range writer.Paths() {
+		if strings.HasSuffix(path, ".html") {
+			htmlPaths = append(htmlPaths, path)
+		}
+	}
+	assert.Empty(t, htmlPaths)
+	manifest, ok := writer.Get(filepath.Join("out", storage.ManifestFileName))
+	require.True(t, ok)
+	assert.Contains(t, string(manifest), `"formats"`)
+}
+
+func TestScheduler_OutputFormats_HTML(t *testing.T) {
+	archiveDir := writeLinkedSiteArchiveForTest(t, 1)
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir("out").
+		WithReplayArchive(archiveDir).
+		WithOutputFormats([]string{config.OutputFormatMarkdown, config.OutputFormatHTML}).
+		Build()
+	require.NoError(t, err)
+
+	sink := &metadatatest.SinkMock{}
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	writer := storage.NewMemoryWriter()
+	execution, err := runPipelineForTest(t, &archiveFetcher, sink, writer, cfg)
+	require.NoError(t, err)
+	require.Len(t, execution.WriteResults(), 1)
+
+	result := execution.WriteResults()[0]
+	page, ok := writer.Get(storage.FormatPath(result.Path(), config.OutputFormatHTML))
+	require.True(t, ok)
+	assert.Contains(t, string(page), "<h1>Index</h1>")
+	assert.Contains(t, string(page), "<p>This page has enough text")
+}
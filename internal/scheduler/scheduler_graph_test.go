@@ -0,0 +1,93 @@
+package scheduler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/rohmanhakim/docs-crawler/internal/scheduler"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeGraphSiteArchiveForTest archives a small link tree: the index links
+// to /docs/a, /docs/b and an out-of-scope host; /docs/a links back to the
+// index and to /docs/b, which is already known by then.
+func writeGraphSiteArchiveForTest(t *testing.T) string {
+	t.Helper()
+	archiveDir := t.TempDir()
+	writePage := func(path, body string) {
+		require.NoError(t, fetcher.WriteArchiveEntry(archiveDir, fetcher.ArchiveEntry{
+			URL:     "https://docs.example.com" + path,
+			Status:  http.StatusOK,
+			Headers: http.Header{"Content-Type": {"text/html"}},
+			Body: []byte(`<html><body><main><h1>Page</h1>
+<p>This page has enough text to pass content extraction and be written.</p>
+` + body + `</main></body></html>`),
+		}))
+	}
+	writePage("/docs", `<a href="/docs/a">A</a> <a href="/docs/b">B</a> <a href="https://other.example.org/x">X</a>`)
+	writePage("/docs/a", `<a href="/docs">Index</a> <a href="/docs/b">B</a>`)
+	writePage("/docs/b", "")
+	return archiveDir
+}
+
+func TestScheduler_Graph_RecordsSubmittedEdges(t *testing.T) {
+	archiveDir := writeGraphSiteArchiveForTest(t)
+	outputDir := t.TempDir()
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir(outputDir).
+		WithReplayArchive(archiveDir).
+		WithGraphFormat(config.GraphFormatJSON).
+		Build()
+	require.NoError(t, err)
+
+	sink := &metadatatest.SinkMock{}
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	execution, err := runPipelineForTest(t, &archiveFetcher, sink, storage.NewMemoryWriter(), cfg)
+	require.NoError(t, err)
+
+	// The out-of-scope link leaves no edge; links to known pages still do
+	want := []scheduler.Edge{
+		{From: "https://docs.example.com/docs", To: "https://docs.example.com/docs/a", Depth: 1},
+		{From: "https://docs.example.com/docs", To: "https://docs.example.com/docs/b", Depth: 1},
+		{From: "https://docs.example.com/docs/a", To: "https://docs.example.com/docs", Depth: 2},
+		{From: "https://docs.example.com/docs/a", To: "https://docs.example.com/docs/b", Depth: 2},
+	}
+	assert.Equal(t, want, execution.Graph())
+
+	data, readErr := os.ReadFile(filepath.Join(outputDir, scheduler.GraphJSONFileName))
+	require.NoError(t, readErr)
+	var written []scheduler.Edge
+	require.NoError(t, json.Unmarshal(data, &written))
+	assert.Equal(t, want, written)
+}
+
+func TestScheduler_Graph_WritesDOT(t *testing.T) {
+	archiveDir := writeGraphSiteArchiveForTest(t)
+	outputDir := t.TempDir()
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir(outputDir).
+		WithReplayArchive(archiveDir).
+		WithMaxDepth(1).
+		WithGraphFormat(config.GraphFormatDOT).
+		Build()
+	require.NoError(t, err)
+
+	sink := &metadatatest.SinkMock{}
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	_, err = runPipelineForTest(t, &archiveFetcher, sink, storage.NewMemoryWriter(), cfg)
+	require.NoError(t, err)
+
+	data, readErr := os.ReadFile(filepath.Join(outputDir, scheduler.GraphDOTFileName))
+	require.NoError(t, readErr)
+	assert.Contains(t, string(data), "digraph crawl {\n")
+	assert.Contains(t, string(data), `"https://docs.example.com/docs" -> "https://docs.example.com/docs/a" [label="1"];`)
+}
@@ -0,0 +1,89 @@
+package scheduler_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/rohmanhakim/docs-crawler/internal/scheduler"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_Hooks_FireForEveryPageInRegistrationOrder(t *testing.T) {
+	archiveDir := writeLinkedSiteArchiveForTest(t, 3)
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir(t.TempDir()).
+		WithReplayArchive(archiveDir).
+		Build()
+	require.NoError(t, err)
+
+	var calls []string
+	var fetches []string
+	var depths []int
+	var written []string
+	var writtenPaths []string
+
+	sink := &metadatatest.SinkMock{}
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	execution, err := runPipelineForTest(t, &archiveFetcher, sink, storage.NewMemoryWriter(), cfg, func(s *scheduler.Scheduler) {
+		s.OnBeforeFetch(func(u url.URL, depth int) {
+			calls = append(calls, "fetch-1")
+			fetches = append(fetches, u.String())
+			depths = append(depths, depth)
+		})
+		s.OnBeforeFetch(func(u url.URL, depth int) {
+			calls = append(calls, "fetch-2")
+		})
+		s.OnAfterWrite(func(result storage.WriteResult, u url.URL) {
+			calls = append(calls, "write-1")
+			written = append(written, u.String())
+			writtenPaths = append(writtenPaths, result.Path())
+		})
+		s.OnAfterWrite(func(result storage.WriteResult, u url.URL) {
+			calls = append(calls, "write-2")
+		})
+	})
+	require.NoError(t, err)
+
+	pages := []string{
+		"https://docs.example.com/docs",
+		"https://docs.example.com/docs/page-1",
+		"https://docs.example.com/docs/page-2",
+	}
+	assert.Equal(t, pages, fetches)
+	assert.Equal(t, []int{0, 1, 1}, depths)
+	assert.Equal(t, pages, written)
+
+	var expectedPaths []string
+	for _, result := range execution.WriteResults() {
+		expectedPaths = append(expectedPaths, result.Path())
+	}
+	assert.Equal(t, expectedPaths, writtenPaths)
+
+	// Each page fetches, then writes, each running its hooks in order
+	page := []string{"fetch-1", "fetch-2", "write-1", "write-2"}
+	assert.Equal(t, append(append(append([]string{}, page...), page...), page...), calls)
+}
+
+func TestScheduler_Hooks_NilHookIsNoOp(t *testing.T) {
+	archiveDir := writeLinkedSiteArchiveForTest(t, 2)
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir(t.TempDir()).
+		WithReplayArchive(archiveDir).
+		Build()
+	require.NoError(t, err)
+
+	sink := &metadatatest.SinkMock{}
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	execution, err := runPipelineForTest(t, &archiveFetcher, sink, storage.NewMemoryWriter(), cfg, func(s *scheduler.Scheduler) {
+		s.OnBeforeFetch(nil)
+		s.OnAfterWrite(nil)
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, execution.TotalPages())
+}
@@ -0,0 +1,72 @@
+package scheduler_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+const hostScopeHTML = `<!DOCTYPE html>
+<html>
+<head><title>Hosts</title></head>
+<body>
+<main>
+<h1>Host Scope</h1>
+<p>This page links to pages on several hosts so that the scheduler's host
+scope check can be verified against wildcard allowed-host patterns.</p>
+<p>Links: <a href="https://a.example.com/guide">A</a>,
+<a href="https://b.example.com/guide">B</a>,
+<a href="https://example.com/guide">apex</a>,
+<a href="https://evil-example.com/guide">lookalike</a>,
+<a href="https://a.b.example.com/guide">nested</a>.</p>
+</main>
+</body>
+</html>`
+
+func hostScopeConfig(t *testing.T, hosts ...string) config.Config {
+	t.Helper()
+	allowed := make(map[string]struct{}, len(hosts))
+	for _, h := range hosts {
+		allowed[h] = struct{}{}
+	}
+	seedURL, _ := url.Parse("https://docs.example.com/")
+	cfg, err := config.WithDefault([]url.URL{*seedURL}).
+		WithAllowedHosts(allowed).
+		WithOutputDir(t.TempDir()).
+		Build()
+	assert.NoError(t, err)
+	return cfg
+}
+
+func TestScheduler_HostScope_WildcardAdmitsSubdomains(t *testing.T) {
+	submitted := crawlSinglePageForTest(t, hostScopeConfig(t, "*.example.com"), hostScopeHTML)
+
+	assert.Equal(t, []string{
+		"https://a.example.com/guide",
+		"https://b.example.com/guide",
+	}, submitted)
+}
+
+func TestScheduler_HostScope_ExplicitApexIsAdmitted(t *testing.T) {
+	submitted := crawlSinglePageForTest(t, hostScopeConfig(t, "*.example.com", "example.com"), hostScopeHTML)
+
+	assert.Equal(t, []string{
+		"https://a.example.com/guide",
+		"https://b.example.com/guide",
+		"https://example.com/guide",
+	}, submitted)
+}
+
+func TestScheduler_HostScope_DefaultsToSeedHost(t *testing.T) {
+	seedURL, _ := url.Parse("https://docs.example.com/")
+	cfg, err := config.WithDefault([]url.URL{*seedURL}).
+		WithOutputDir(t.TempDir()).
+		Build()
+	assert.NoError(t, err)
+
+	submitted := crawlSinglePageForTest(t, cfg, hostScopeHTML)
+
+	assert.Empty(t, submitted)
+}
@@ -0,0 +1,90 @@
+package scheduler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// multilingualSitePages are an English index linking to a French page and a
+// page without a declared language.
+var multilingualSitePages = map[string]string{
+	"/docs": `<!DOCTYPE html>
+<html lang="en-US">
+<body><main>
+<h1>Documentation</h1>
+<p>Welcome to the documentation, with enough text to pass content extraction.</p>
+<p>Read it in <a href="/docs/fr">French</a> or see the <a href="/docs/misc">misc page</a>.</p>
+</main></body>
+</html>`,
+	"/docs/fr": `<!DOCTYPE html>
+<html lang="fr">
+<body><main>
+<h1>Documentation</h1>
+<p>Bienvenue dans la documentation, avec assez de texte pour passer l'extraction.</p>
+</main></body>
+</html>`,
+	"/docs/misc": `<!DOCTYPE html>
+<html>
+<body><main>
+<h1>Miscellaneous</h1>
+<p>This page does not declare its language but has enough text to be kept.</p>
+</main></body>
+</html>`,
+}
+
+func TestScheduler_PartitionByLanguage_WritesUnderLanguageDirectories(t *testing.T) {
+	archiveDir := t.TempDir()
+	for path, page := range multilingualSitePages {
+		require.NoError(t, fetcher.WriteArchiveEntry(archiveDir, fetcher.ArchiveEntry{
+			URL:     "https://docs.example.com" + path,
+			Status:  http.StatusOK,
+			Headers: http.Header{"Content-Type": {"text/html"}},
+			Body:    []byte(page),
+		}))
+	}
+	outputDir := "out"
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir(outputDir).
+		WithReplayArchive(archiveDir).
+		WithPartitionByLanguage(true).
+		Build()
+	require.NoError(t, err)
+
+	sink := &metadatatest.SinkMock{}
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	_, writer := crawlPipelineForTest(t, &archiveFetcher, sink, cfg)
+
+	manifestData, ok := writer.Get(filepath.Join(outputDir, storage.ManifestFileName))
+	require.True(t, ok, "expected the manifest at the output root")
+	var manifest []struct {
+		URL      string `json:"url"`
+		Path     string `json:"path"`
+		Language string `json:"language"`
+	}
+	require.NoError(t, json.Unmarshal(manifestData, &manifest))
+
+	want := map[string]string{
+		"https://docs.example.com/docs":      "en",
+		"https://docs.example.com/docs/fr":   "fr",
+		"https://docs.example.com/docs/misc": "und",
+	}
+	require.Len(t, manifest, len(want))
+	for _, entry := range manifest {
+		language, ok := want[entry.URL]
+		require.True(t, ok, "unexpected manifest entry %s", entry.URL)
+		assert.Equal(t, language, entry.Language, "language of %s", entry.URL)
+		assert.Equal(t, filepath.Join(outputDir, language), filepath.Dir(entry.Path), "directory of %s", entry.URL)
+		_, written := writer.Get(entry.Path)
+		assert.True(t, written, "expected %s to be written", entry.Path)
+	}
+}
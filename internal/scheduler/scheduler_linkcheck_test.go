@@ -0,0 +1,221 @@
+package scheduler_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/rohmanhakim/docs-crawler/internal/scheduler"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// writeBrokenLinkSiteArchiveForTest archives an index and a guide that both
+// link to a missing page, and an external page linked from the index.
+func writeBrokenLinkSiteArchiveForTest(t *testing.T) string {
+	t.Helper()
+	archiveDir := t.TempDir()
+	writePage := func(pageURL, title, body string) {
+		require.NoError(t, fetcher.WriteArchiveEntry(archiveDir, fetcher.ArchiveEntry{
+			URL:     pageURL,
+			Status:  http.StatusOK,
+			Headers: http.Header{"Content-Type": {"text/html"}},
+			Body: []byte(fmt.Sprintf(`<html><body><main><h1>%s</h1>
+<p>This page has enough text to pass content extraction and be written.</p>
+%s</main></body></html>`, title, body)),
+		}))
+	}
+
+	writePage("https://docs.example.com/docs", "Index", `<ul>
+<li><a href="/docs/guide">Guide</a></li>
+<li><a href="/docs/missing">Missing</a></li>
+<li><a href="https://external.example.org/reference#intro">Reference</a></li>
+<li><a href="mailto:docs@example.com">Contact</a></li>
+</ul>`)
+	writePage("https://docs.example.com/docs/guide", "Guide", `<p><a href="/docs/missing">Also missing</a></p>`)
+	writePage("https://external.example.org/reference", "Reference", "")
+	return archiveDir
+}
+
+func TestScheduler_LinkCheckOnly_ReportsBrokenLinks(t *testing.T) {
+	archiveDir := writeBrokenLinkSiteArchiveForTest(t)
+	outputDir := t.TempDir()
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir(outputDir).
+		WithReplayArchive(archiveDir).
+		WithLinkCheckOnly(true).
+		WithMaxAttempt(1).
+		Build()
+	require.NoError(t, err)
+
+	sink := &metadatatest.SinkMock{}
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	writer := storage.NewMemoryWriter()
+	limiter := newRateLimiterMockForTest(t)
+	s := newPipelineSchedulerWithLimiterForTest(t, &archiveFetcher, sink, writer, limiter)
+	init, err := s.InitializeWithConfig(cfg)
+	require.NoError(t, err)
+	execution, err := s.ExecuteCrawlingWithState(init)
+	require.NoError(t, err)
+
+	// The missing page is reported with both pages referring to it
+	expected := []scheduler.BrokenLink{{
+		URL:    "https://docs.example.com/docs/missing",
+		Status: http.StatusNotFound,
+		Referrers: []string{
+			"https://docs.example.com/docs",
+			"https://docs.example.com/docs/guide",
+		},
+	}}
+	assert.Equal(t, expected, execution.BrokenLinks())
+
+	data, err := os.ReadFile(filepath.Join(outputDir, scheduler.LinkCheckFileName))
+	require.NoError(t, err)
+	var report scheduler.LinkCheckReport
+	require.NoError(t, json.Unmarshal(data, &report))
+	assert.Equal(t, 3, report.CheckedLinks)
+	assert.Equal(t, expected, report.BrokenLinks)
+
+	// In-scope links are crawled, the missing page once, and their status
+	// comes from the crawl; the out-of-scope link is probed through the
+	// rate limiter for its host. Nothing is written.
+	var fetched []string
+	for _, event := range sink.FetchEvents {
+		fetched = append(fetched, event.FetchURL())
+	}
+	assert.Contains(t, fetched, "https://docs.example.com/docs/guide")
+	assert.Contains(t, fetched, "https://docs.example.com/docs/missing")
+	assert.NotContains(t, fetched, "https://external.example.org/reference")
+	limiter.AssertCalled(t, "Wait", mock.Anything, "external.example.org")
+	assert.Empty(t, writer.Paths())
+	assert.Zero(t, execution.TotalPages())
+}
+
+func TestScheduler_LinkCheckOnly_DisabledByDefault(t *testing.T) {
+	archiveDir := writeLinkedSiteArchiveForTest(t, 2)
+	outputDir := t.TempDir()
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir(outputDir).
+		WithReplayArchive(archiveDir).
+		Build()
+	require.NoError(t, err)
+
+	sink := &metadatatest.SinkMock{}
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	execution, err := runPipelineForTest(t, &archiveFetcher, sink, storage.NewMemoryWriter(), cfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, execution.TotalPages())
+	assert.Nil(t, execution.BrokenLinks())
+	assert.NoFileExists(t, filepath.Join(outputDir, scheduler.LinkCheckFileName))
+}
+
+func TestScheduler_LinkCheckOnly_InScopeLinksRequestedOnce(t *testing.T) {
+	var mu sync.Mutex
+	requests := map[string]int{}
+	countingServer := func(pages map[string]string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			requests[r.Method+" "+r.Host+r.URL.Path]++
+			mu.Unlock()
+			page, ok := pages[r.URL.Path]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte(page))
+		}))
+	}
+	external := countingServer(map[string]string{"/reference": "<html><body>Reference</body></html>"})
+	defer external.Close()
+	// Out of scope: the same loopback address under another host name
+	externalURL := strings.Replace(external.URL, "127.0.0.1", "localhost", 1)
+	docs := countingServer(map[string]string{
+		"/docs": `<html><body><main><h1>Index</h1>
+<a href="/docs/guide">Guide</a> <a href="/docs/missing">Missing</a>
+<a href="` + externalURL + `/reference">Reference</a></main></body></html>`,
+		"/docs/guide": `<html><body><main><h1>Guide</h1><a href="/docs">Index</a></main></body></html>`,
+	})
+	defer docs.Close()
+
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL(docs.URL + "/docs")}).
+		WithOutputDir(t.TempDir()).
+		WithLinkCheckOnly(true).
+		WithMaxAttempt(1).
+		Build()
+	require.NoError(t, err)
+
+	sink := &metadatatest.SinkMock{}
+	liveFetcher := fetcher.NewHtmlFetcher(sink)
+	execution, err := runPipelineForTest(t, &liveFetcher, sink, storage.NewMemoryWriter(), cfg)
+	require.NoError(t, err)
+
+	require.Len(t, execution.BrokenLinks(), 1)
+	assert.Equal(t, docs.URL+"/docs/missing", execution.BrokenLinks()[0].URL)
+	assert.Equal(t, http.StatusNotFound, execution.BrokenLinks()[0].Status)
+
+	// Each in-scope page is requested once, by the crawl; only the
+	// out-of-scope link is probed
+	docsHost := mustParseURL(docs.URL).Host
+	externalHost := mustParseURL(externalURL).Host
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 1, requests["GET "+docsHost+"/docs"])
+	assert.Equal(t, 1, requests["GET "+docsHost+"/docs/guide"])
+	assert.Equal(t, 1, requests["GET "+docsHost+"/docs/missing"])
+	assert.Zero(t, requests["HEAD "+docsHost+"/docs/missing"])
+	assert.Equal(t, 1, requests["HEAD "+externalHost+"/reference"])
+	assert.Zero(t, requests["GET "+externalHost+"/reference"])
+}
+
+func TestScheduler_LinkCheckOnly_ResolvesLinksAgainstRedirectedURL(t *testing.T) {
+	archiveDir := t.TempDir()
+	writeEntry := func(entry fetcher.ArchiveEntry) {
+		require.NoError(t, fetcher.WriteArchiveEntry(archiveDir, entry))
+	}
+	htmlPage := func(pageURL, body string) fetcher.ArchiveEntry {
+		return fetcher.ArchiveEntry{
+			URL:     pageURL,
+			Status:  http.StatusOK,
+			Headers: http.Header{"Content-Type": {"text/html"}},
+			Body:    []byte("<html><body><main><h1>Page</h1>" + body + "</main></body></html>"),
+		}
+	}
+	writeEntry(htmlPage("https://docs.example.com/docs", `<a href="/docs/old">Old</a>`))
+	writeEntry(fetcher.ArchiveEntry{
+		URL:     "https://docs.example.com/docs/old",
+		Status:  http.StatusMovedPermanently,
+		Headers: http.Header{"Location": {"/docs/new/"}},
+	})
+	// A relative link resolving under /docs/new/, not under /docs/
+	writeEntry(htmlPage("https://docs.example.com/docs/new/", `<a href="page">Page</a>`))
+	writeEntry(htmlPage("https://docs.example.com/docs/new/page", ""))
+
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir(t.TempDir()).
+		WithReplayArchive(archiveDir).
+		WithLinkCheckOnly(true).
+		WithMaxAttempt(1).
+		Build()
+	require.NoError(t, err)
+
+	sink := &metadatatest.SinkMock{}
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	execution, err := runPipelineForTest(t, &archiveFetcher, sink, storage.NewMemoryWriter(), cfg)
+	require.NoError(t, err)
+
+	assert.Empty(t, execution.BrokenLinks())
+}
@@ -0,0 +1,59 @@
+package scheduler_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// linkOrderHTML links to the same pages repeatedly, out of alphabetical
+// order, and through forms that share a canonical URL.
+const linkOrderHTML = `<!DOCTYPE html>
+<html>
+<head><title>Links</title></head>
+<body>
+<main>
+<h1>Link Ordering</h1>
+<p>This page links to several other pages in a deliberately scrambled order so
+that the submission sequence can be verified against document order.</p>
+<p>See <a href="/docs/c">page C</a> first, then <a href="/docs/a">page A</a>,
+then <a href="/docs/c">page C again</a>, then <a href="/docs/b#install">page B</a>,
+then <a href="/docs/a/">page A with a slash</a>, then
+<a href="https://example.com/docs/b">page B absolute</a>, and finally
+<a href="/docs/d">page D</a>.</p>
+</main>
+</body>
+</html>`
+
+// crawlLinkOrderPage crawls a single page containing linkOrderHTML and
+// returns the URLs submitted to the frontier for its discovered links.
+func crawlLinkOrderPage(t *testing.T) []string {
+	t.Helper()
+	seedURL, _ := url.Parse("https://example.com/docs")
+	cfg, err := config.WithDefault([]url.URL{*seedURL}).
+		WithOutputDir(t.TempDir()).
+		Build()
+	assert.NoError(t, err)
+	return crawlSinglePageForTest(t, cfg, linkOrderHTML)
+}
+
+func TestScheduler_DiscoveredLinks_SubmittedInDocumentOrderWithoutDuplicates(t *testing.T) {
+	submitted := crawlLinkOrderPage(t)
+
+	assert.Equal(t, []string{
+		"https://example.com/docs/c",
+		"https://example.com/docs/a",
+		"https://example.com/docs/b",
+		"https://example.com/docs/d",
+	}, submitted)
+}
+
+func TestScheduler_DiscoveredLinks_SubmissionOrderStableAcrossRuns(t *testing.T) {
+	first := crawlLinkOrderPage(t)
+
+	for run := 0; run < 5; run++ {
+		assert.Equal(t, first, crawlLinkOrderPage(t), "run %d submitted links in a different order", run)
+	}
+}
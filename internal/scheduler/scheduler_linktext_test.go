@@ -0,0 +1,62 @@
+package scheduler_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const linkTextPageHTML = `<!DOCTYPE html>
+<html>
+<body><main>
+<h1>Documentation</h1>
+<p>Welcome to the documentation, with enough text to pass content extraction.</p>
+<p>Start with the <a href="/docs/install">installation guide</a>.</p>
+<p><a href="/docs/guide.html">Download PDF</a></p>
+</main></body>
+</html>`
+
+func TestScheduler_SkipLinkTextPatterns_DeclinesMatchingLinks(t *testing.T) {
+	seedURL := mustParseURL("https://docs.example.com/docs")
+	cfg, err := config.WithDefault([]url.URL{*seedURL}).
+		WithOutputDir(t.TempDir()).
+		WithSkipLinkTextPatterns([]string{"(?i)^download", "Edit on GitHub"}).
+		Build()
+	require.NoError(t, err)
+	sink := &metadatatest.SinkMock{}
+
+	var submitted []string
+	for _, candidate := range crawlSinglePageWithSinkForTest(t, cfg, linkTextPageHTML, nil, sink) {
+		target := candidate.TargetURL()
+		submitted = append(submitted, target.String())
+	}
+
+	assert.Equal(t, []string{"https://docs.example.com/docs/install"}, submitted)
+	var skipped []string
+	for _, event := range sink.SkipEvents {
+		if event.Reason() == metadata.SkipReasonLinkText {
+			skipped = append(skipped, event.SkippedURL())
+		}
+	}
+	assert.Equal(t, []string{"https://docs.example.com/docs/guide.html"}, skipped)
+}
+
+func TestScheduler_SkipLinkTextPatterns_NoneSubmitsAllLinks(t *testing.T) {
+	seedURL := mustParseURL("https://docs.example.com/docs")
+	cfg, err := config.WithDefault([]url.URL{*seedURL}).
+		WithOutputDir(t.TempDir()).
+		Build()
+	require.NoError(t, err)
+
+	submitted := crawlSinglePageForTest(t, cfg, linkTextPageHTML)
+
+	assert.Equal(t, []string{
+		"https://docs.example.com/docs/install",
+		"https://docs.example.com/docs/guide.html",
+	}, submitted)
+}
@@ -317,6 +317,88 @@ func TestScheduler_Normalize_RecoverableError_ContinuesCrawl(t *testing.T) {
 	mockNormalize.AssertCalled(t, "Normalize", mock.Anything, mock.Anything, mock.Anything)
 }
 
+// TestScheduler_Normalize_RecoverableError_AbortsCrawlInStrictMode verifies that
+// strict mode turns a recoverable normalization error into a fatal one.
+func TestScheduler_Normalize_RecoverableError_AbortsCrawlInStrictMode(t *testing.T) {
+	ctx := context.Background()
+	mockFinalizer := newMockFinalizer(t)
+	noopSink := &metadata.NoopSink{}
+	mockLimiter := newRateLimiterMockForTest(t)
+	mockFetcher := newFetcherMockForTest(t)
+	mockRobot := NewRobotsMockForTest(t)
+	mockFrontier := newFrontierMockForTest(t)
+
+	mockExtractor := newExtractorMockForTest(t)
+	mockSanitizer := newSanitizerMockForTest(t)
+	mockConvert := newConvertMockForTest(t)
+	mockResolver := newResolverMockForTest(t)
+	mockNormalize := newNormalizeMockForTest(t)
+	mockStorage := newStorageMockForTest(t)
+	mockFailureJournal := newFailureJournalMockForTest(t)
+
+	mockRobot.On("Init", mock.Anything, mock.Anything).Return()
+	mockRobot.OnDecide(mock.Anything, robots.Decision{
+		Allowed:    true,
+		Reason:     robots.EmptyRuleSet,
+		CrawlDelay: 0,
+	}, nil).Once()
+
+	mockFetcher.On("Init", mock.Anything, mock.Anything).Return()
+	mockLimiter.On("ResolveDelay", mock.Anything).Return(time.Duration(0))
+
+	contentNode := &html.Node{Type: html.ElementNode, Data: "div"}
+	setupExtractorMockWithSuccess(mockExtractor, contentNode)
+	mockExtractor.On("SetExtractParam", mock.Anything).Return()
+	mockSanitizer.On("Sanitize", contentNode).Return(createSanitizedHTMLDocForTest(nil), nil)
+	setupConvertMockWithSuccess(mockConvert)
+	setupResolverMockWithSuccess(mockResolver)
+
+	// Setup normalize to return a recoverable error
+	setupNormalizeMockWithRecoverableError(mockNormalize)
+
+	seedURL, _ := url.Parse("http://example.com/")
+	mockFrontier.SetupDequeueToReturn(frontier.NewCrawlToken(*seedURL, 0), true)
+
+	s := createSchedulerWithAllMocksAndNormalize(
+		t,
+		ctx,
+		mockFinalizer,
+		noopSink,
+		mockLimiter,
+		mockRobot,
+		mockFrontier,
+		mockFetcher,
+		mockExtractor,
+		mockSanitizer,
+		mockConvert,
+		mockResolver,
+		mockNormalize,
+		mockStorage,
+		mockFailureJournal,
+	)
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.json")
+
+	configData := `{
+		"seedUrls": ["http://example.com"],
+		"maxDepth": 0,
+		"strictMode": true
+	}`
+	err := os.WriteFile(configPath, []byte(configData), 0644)
+	assert.NoError(t, err)
+
+	init, err := s.InitializeCrawling(configPath)
+	assert.NoError(t, err, "Failed to initialize")
+
+	_, execErr := s.ExecuteCrawlingWithState(init)
+
+	// The same error that lets a normal crawl continue aborts a strict one
+	assert.ErrorIs(t, execErr, scheduler.ErrStrictMode, "Recoverable normalize error should abort a strict crawl")
+	mockNormalize.AssertCalled(t, "Normalize", mock.Anything, mock.Anything, mock.Anything)
+	mockStorage.AssertNotCalled(t, "Write", mock.Anything, mock.Anything, mock.Anything)
+}
+
 // TestScheduler_Normalize_ErrorDoesNotPreventWriteForRecoverable verifies that when Normalize()
 // returns a recoverable error, the scheduler still continues (doesn't write but doesn't abort).
 func TestScheduler_Normalize_ErrorDoesNotPreventWriteForRecoverable(t *testing.T) {
@@ -505,7 +587,8 @@ func TestScheduler_NormalizeParam_CreatedWithCorrectValues(t *testing.T) {
 		"seedUrls": ["http://example.com"],
 		"maxDepth": 3,
 		"allowedPathPrefix": ["/docs", "/api"],
-		"hashAlgo": "sha256"
+		"hashAlgo": "sha256",
+		"generateToC": true
 	}`
 	err := os.WriteFile(configPath, []byte(configData), 0644)
 	assert.NoError(t, err)
@@ -616,6 +699,7 @@ func TestScheduler_NormalizeParam_CreatedWithCorrectValues(t *testing.T) {
 	assert.Equal(t, string(expectedHashAlgo), string(capturedParam.HashAlgo()), "hashAlgo should match cfg.HashAlgo()")
 	assert.Equal(t, expectedDepth, capturedParam.CrawlDepth(), "crawlDepth should match nextCrawlToken.Depth() (seed=0)")
 	assert.Equal(t, expectedAllowedPathPrefix, capturedParam.AllowedPathPrefixes(), "allowedPathPrefixes should match config.AllowedPathPrefix()")
+	assert.True(t, capturedParam.GenerateToC(), "generateToC should match config.GenerateToC()")
 }
 
 // TestScheduler_NormalizeParam_UsesTokenDepth verifies that the crawl depth
@@ -0,0 +1,104 @@
+package scheduler_test
+
+import (
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/rohmanhakim/docs-crawler/internal/scheduler"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// crawlWithOutputBudgetForTest crawls an archived site of pageCount pages
+// with the given output budget into a memory writer, writing formats when
+// any are given.
+func crawlWithOutputBudgetForTest(t *testing.T, pageCount int, maxBytes int64, formats ...string) (scheduler.CrawlingExecution, *storage.MemoryWriter, error) {
+	t.Helper()
+	archiveDir := writeLinkedSiteArchiveForTest(t, pageCount)
+	builder := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir("out").
+		WithReplayArchive(archiveDir).
+		WithMaxOutputBytes(maxBytes)
+	if len(formats) > 0 {
+		builder = builder.WithOutputFormats(formats)
+	}
+	cfg, err := builder.Build()
+	require.NoError(t, err)
+
+	sink := &metadatatest.SinkMock{}
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	writer := storage.NewMemoryWriter()
+	execution, err := runPipelineForTest(t, &archiveFetcher, sink, writer, cfg)
+	return execution, writer, err
+}
+
+// writtenPageBytes returns the cumulative size of the pages in writer after
+// each page, in write order.
+func writtenPageBytes(t *testing.T, writer *storage.MemoryWriter) []int64 {
+	t.Helper()
+	var cumulative []int64
+	var total int64
+	for _, path := range writer.Paths() {
+		if filepath.Ext(path) != ".md" {
+			continue
+		}
+		content, ok := writer.Get(path)
+		require.True(t, ok)
+		total += int64(len(content))
+		cumulative = append(cumulative, total)
+	}
+	return cumulative
+}
+
+func TestScheduler_MaxOutputBytes_StopsOnceBudgetExceeded(t *testing.T) {
+	// GIVEN the sizes an unlimited crawl writes
+	_, unlimited, err := crawlWithOutputBudgetForTest(t, 6, 0)
+	require.NoError(t, err)
+	sizes := writtenPageBytes(t, unlimited)
+	require.Len(t, sizes, 6)
+
+	// WHEN the budget is exceeded by the third page
+	budget := sizes[2] - 1
+	execution, writer, err := crawlWithOutputBudgetForTest(t, 6, budget)
+
+	// THEN the crawl stops after it, with the sentinel and a usable result
+	require.ErrorIs(t, err, scheduler.ErrOutputBudgetExceeded)
+	assert.Len(t, writtenPageBytes(t, writer), 3)
+	assert.Len(t, execution.WriteResults(), 3)
+	_, ok := writer.Get(filepath.Join("out", storage.ManifestFileName))
+	assert.True(t, ok, "expected the manifest to be written when the budget stops the crawl")
+}
+
+func TestScheduler_MaxOutputBytes_WithinBudget(t *testing.T) {
+	_, unlimited, err := crawlWithOutputBudgetForTest(t, 4, 0)
+	require.NoError(t, err)
+	sizes := writtenPageBytes(t, unlimited)
+
+	// A budget equal to the total is not exceeded
+	_, writer, err := crawlWithOutputBudgetForTest(t, 4, sizes[len(sizes)-1])
+
+	assert.NoError(t, err)
+	assert.Len(t, writtenPageBytes(t, writer), 4)
+}
+
+func TestScheduler_MaxOutputBytes_CountsAdditionalFormats(t *testing.T) {
+	// GIVEN a budget the Markdown of every page fits in
+	_, unlimited, err := crawlWithOutputBudgetForTest(t, 4, 0)
+	require.NoError(t, err)
+	sizes := writtenPageBytes(t, unlimited)
+	require.Len(t, sizes, 4)
+	budget := sizes[len(sizes)-1]
+
+	// WHEN html and txt files are written next to the Markdown
+	_, writer, err := crawlWithOutputBudgetForTest(t, 4, budget,
+		config.OutputFormatMarkdown, config.OutputFormatHTML, config.OutputFormatText)
+
+	// THEN their sizes count against the budget and the crawl stops early
+	require.ErrorIs(t, err, scheduler.ErrOutputBudgetExceeded)
+	assert.Less(t, len(writtenPageBytes(t, writer)), 4)
+}
@@ -0,0 +1,145 @@
+package scheduler_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/rohmanhakim/docs-crawler/internal/scheduler"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// outputCheckArchiveForTest archives a single page and returns the archive
+// directory and the page URL.
+func outputCheckArchiveForTest(t *testing.T) (string, url.URL) {
+	t.Helper()
+	archiveDir := t.TempDir()
+	seed := *mustParseURL("https://docs.example.com/docs")
+	require.NoError(t, fetcher.WriteArchiveEntry(archiveDir, fetcher.ArchiveEntry{
+		URL:     seed.String(),
+		Status:  http.StatusOK,
+		Headers: http.Header{"Content-Type": {"text/html"}},
+		Body:    []byte(archiveSitePages["/docs/install"]),
+	}))
+	return archiveDir, seed
+}
+
+// unwritableOutputDirForTest returns an output dir that cannot be created,
+// even by root, because a regular file stands in for its parent.
+func unwritableOutputDirForTest(t *testing.T) string {
+	t.Helper()
+	parent := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(parent, []byte("x"), 0644))
+	return filepath.Join(parent, "out")
+}
+
+// newOutputCheckSchedulerForTest returns a scheduler writing with writer
+// whose other collaborators expect no calls, for initializations that fail
+// before any fetching.
+func newOutputCheckSchedulerForTest(t *testing.T, mockFetcher *fetcherMock, writer storage.Writer) *scheduler.Scheduler {
+	t.Helper()
+	s := createSchedulerForTest(
+		t,
+		context.Background(),
+		newMockFinalizer(t),
+		&metadata.NoopSink{},
+		newRateLimiterMockForTest(t),
+		newFrontierMockForTest(t),
+		NewRobotsMockForTest(t),
+		mockFetcher,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		newFailureJournalMockForTest(t),
+	)
+	s.SetWriter(writer)
+	return s
+}
+
+func TestScheduler_OutputCheck_WritableDirProceeds(t *testing.T) {
+	archiveDir, seed := outputCheckArchiveForTest(t)
+	outputDir := filepath.Join(t.TempDir(), "nested", "out")
+	cfg, err := config.WithDefault([]url.URL{seed}).
+		WithOutputDir(outputDir).
+		WithReplayArchive(archiveDir).
+		Build()
+	require.NoError(t, err)
+	sink := &metadatatest.SinkMock{}
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+
+	_, err = runPipelineForTest(t, &archiveFetcher, sink, storage.NewLocalSink(sink), cfg)
+	require.NoError(t, err)
+
+	pages, err := filepath.Glob(filepath.Join(outputDir, "*.md"))
+	require.NoError(t, err)
+	assert.Len(t, pages, 1, "the page should be written to the created output dir")
+	probes, err := filepath.Glob(filepath.Join(outputDir, ".write-probe-*"))
+	require.NoError(t, err)
+	assert.Empty(t, probes, "the probe file should be removed")
+}
+
+func TestScheduler_OutputCheck_ReadOnlyDirFailsFast(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("permission checks do not apply to root")
+	}
+	outputDir := t.TempDir()
+	require.NoError(t, os.Chmod(outputDir, 0555))
+	t.Cleanup(func() { os.Chmod(outputDir, 0755) })
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir(outputDir).
+		Build()
+	require.NoError(t, err)
+
+	mockFetcher := new(fetcherMock)
+	s := newOutputCheckSchedulerForTest(t, mockFetcher, storage.NewLocalSink(&metadata.NoopSink{}))
+	init, err := s.InitializeWithConfig(cfg)
+
+	assert.Nil(t, init)
+	assert.True(t, errors.Is(err, scheduler.ErrOutputNotWritable), "got %v", err)
+	mockFetcher.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestScheduler_OutputCheck_UncreatableDirFailsFast(t *testing.T) {
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir(unwritableOutputDirForTest(t)).
+		Build()
+	require.NoError(t, err)
+
+	mockFetcher := new(fetcherMock)
+	s := newOutputCheckSchedulerForTest(t, mockFetcher, storage.NewLocalSink(&metadata.NoopSink{}))
+	init, err := s.InitializeWithConfig(cfg)
+
+	assert.Nil(t, init)
+	assert.True(t, errors.Is(err, scheduler.ErrOutputNotWritable), "got %v", err)
+	mockFetcher.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestScheduler_OutputCheck_DryRunSkipsCheck(t *testing.T) {
+	archiveDir, seed := outputCheckArchiveForTest(t)
+	outputDir := unwritableOutputDirForTest(t)
+	cfg, err := config.WithDefault([]url.URL{seed}).
+		WithOutputDir(outputDir).
+		WithReplayArchive(archiveDir).
+		WithDryRun(true).
+		Build()
+	require.NoError(t, err)
+	sink := &metadatatest.SinkMock{}
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+
+	// runPipelineForTest fails the test if initialization returns an error
+	runPipelineForTest(t, &archiveFetcher, sink, storage.NewLocalSink(sink), cfg)
+	assert.NotEmpty(t, sink.FetchEvents, "the dry run should fetch the seed")
+}
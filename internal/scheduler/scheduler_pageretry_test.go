@@ -0,0 +1,173 @@
+package scheduler_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/frontier"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/internal/robots"
+	"github.com/rohmanhakim/docs-crawler/internal/scheduler"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const pageRetryHTML = `<!DOCTYPE html>
+<html>
+<head><title>Retry</title></head>
+<body>
+<main>
+<h1>Flaky Page</h1>
+<p>This page is served by a flaky CDN that fails a couple of times before
+returning the content, so the whole pipeline has to be re-run.</p>
+</main>
+</body>
+</html>`
+
+// pageRetrySchedulerForTest builds a scheduler for a single seed page whose
+// fetch fails with fetchErrs in order before succeeding.
+func pageRetrySchedulerForTest(
+	t *testing.T,
+	seedURL url.URL,
+	fetchErrs []*fetcher.FetchError,
+	mockStorage *storageMock,
+) *scheduler.Scheduler {
+	t.Helper()
+
+	mockRobot := NewRobotsMockForTest(t)
+	mockRobot.On("Init", mock.Anything, mock.Anything).Return()
+	mockRobot.OnDecide(mock.Anything, robots.Decision{Allowed: true, Reason: robots.EmptyRuleSet}, nil)
+
+	mockFetcher := new(fetcherMock)
+	mockFetcher.On("Init", mock.Anything, mock.Anything).Return()
+	for _, fetchErr := range fetchErrs {
+		mockFetcher.On("Fetch", mock.Anything, mock.Anything, seedURL, mock.Anything).
+			Return(fetcher.FetchResult{}, fetchErr).Once()
+	}
+	mockFetcher.On("Fetch", mock.Anything, mock.Anything, seedURL, mock.Anything).Return(
+		fetcher.NewFetchResultForTest(
+			seedURL,
+			[]byte(pageRetryHTML),
+			200,
+			"text/html",
+			map[string]string{"Content-Type": "text/html"},
+			time.Now(),
+		), nil)
+
+	mockFrontier := newFrontierMockForTest(t)
+	mockFrontier.disableAutoEnqueue = true
+	mockFrontier.OnDequeue(frontier.NewCrawlToken(seedURL, 0), true).Once()
+	mockFrontier.OnDequeue(frontier.CrawlToken{}, false)
+
+	return createSchedulerForTest(
+		t,
+		context.Background(),
+		newMockFinalizer(t),
+		&metadata.NoopSink{},
+		newRateLimiterMockForTest(t),
+		mockFrontier,
+		mockRobot,
+		mockFetcher,
+		nil,
+		nil,
+		nil,
+		nil,
+		mockStorage,
+		newFailureJournalMockForTest(t),
+	)
+}
+
+func TestScheduler_PageRetry_SucceedsAfterTransientFailures(t *testing.T) {
+	// GIVEN a page whose fetch fails twice with a transient error, and page
+	// retries allowing three attempts
+	seedURL := *mustParseURL("https://example.com/flaky")
+	fetchErrs := []*fetcher.FetchError{
+		fetcher.NewFetchError(fetcher.ErrCauseRequest5xx, "http 503"),
+		fetcher.NewFetchError(fetcher.ErrCauseRequest5xx, "http 503"),
+	}
+	mockStorage := newStorageMockForTest(t)
+	written := storage.NewWriteResult("abc123", "output/abc123.md", "sha256:x")
+	mockStorage.On("Write", mock.Anything, mock.Anything, mock.Anything).Return(written, nil).Once()
+
+	s := pageRetrySchedulerForTest(t, seedURL, fetchErrs, mockStorage)
+	sleeper := newFakeSleeper(newFakeClock(time.Now()))
+	s.SetSleeper(sleeper)
+
+	cfg, err := config.WithDefault([]url.URL{seedURL}).
+		WithPageRetry(config.NewPageRetry(3, time.Second)).
+		Build()
+	assert.NoError(t, err)
+
+	// WHEN crawling
+	init, err := s.InitializeWithConfig(cfg)
+	assert.NoError(t, err)
+	exec, err := s.ExecuteCrawlingWithState(init)
+	assert.NoError(t, err)
+
+	// THEN the page is written once, after exponential backoffs
+	assert.Equal(t, []storage.WriteResult{written.WithRunID(scheduler.RunID(cfg))}, exec.WriteResults())
+	assert.Equal(t, 0, exec.TotalErrors())
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, sleeper.sleeps)
+
+	// AND the attempt count is recorded
+	assert.Equal(t, map[string]int{seedURL.String(): 3}, exec.PageAttempts())
+}
+
+func TestScheduler_PageRetry_StopsAtLimitAndSkipsPermanentErrors(t *testing.T) {
+	tests := []struct {
+		name             string
+		fetchErrs        []*fetcher.FetchError
+		expectedSleeps   int
+		expectedAttempts map[string]int
+	}{
+		{
+			name: "transient errors beyond the limit",
+			fetchErrs: []*fetcher.FetchError{
+				fetcher.NewFetchError(fetcher.ErrCauseTimeout, "timeout"),
+				fetcher.NewFetchError(fetcher.ErrCauseTimeout, "timeout"),
+			},
+			expectedSleeps:   1,
+			expectedAttempts: map[string]int{"https://example.com/flaky": 2},
+		},
+		{
+			name: "permanent error",
+			fetchErrs: []*fetcher.FetchError{
+				fetcher.NewFetchError(fetcher.ErrCauseRedirectLimitExceeded, "too many redirects"),
+			},
+			expectedSleeps:   0,
+			expectedAttempts: map[string]int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seedURL := *mustParseURL("https://example.com/flaky")
+			mockStorage := newStorageMockForTest(t)
+
+			s := pageRetrySchedulerForTest(t, seedURL, tt.fetchErrs, mockStorage)
+			sleeper := newFakeSleeper(newFakeClock(time.Now()))
+			s.SetSleeper(sleeper)
+
+			cfg, err := config.WithDefault([]url.URL{seedURL}).
+				WithPageRetry(config.NewPageRetry(2, time.Second)).
+				Build()
+			assert.NoError(t, err)
+
+			init, err := s.InitializeWithConfig(cfg)
+			assert.NoError(t, err)
+			exec, err := s.ExecuteCrawlingWithState(init)
+			assert.NoError(t, err)
+
+			assert.Empty(t, exec.WriteResults())
+			assert.Equal(t, 1, exec.TotalErrors())
+			assert.Len(t, sleeper.sleeps, tt.expectedSleeps)
+			assert.Equal(t, tt.expectedAttempts, exec.PageAttempts())
+			mockStorage.AssertNotCalled(t, "Write", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package scheduler_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/extractor"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/frontier"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/internal/robots"
+	"github.com/rohmanhakim/docs-crawler/internal/sanitizer"
+	"github.com/rohmanhakim/docs-crawler/internal/scheduler"
+	"github.com/rohmanhakim/docs-crawler/internal/stagedump"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/rohmanhakim/docs-crawler/pkg/debug"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// paginatedHTML returns a page whose <head> declares the given pagination links.
+func paginatedHTML(headLinks string) string {
+	return `<!DOCTYPE html>
+<html>
+<head><title>API Reference</title>` + headLinks + `</head>
+<body>
+<main>
+<h1>API Reference</h1>
+<p>This page lists a slice of the API reference. The remaining entries are
+reachable only through pagination links declared in the document head.</p>
+</main>
+</body>
+</html>`
+}
+
+func paginationConfig(t *testing.T, seed string, follow bool) config.Config {
+	t.Helper()
+	seedURL, _ := url.Parse(seed)
+	cfg, err := config.WithDefault([]url.URL{*seedURL}).
+		WithFollowPagination(follow).
+		WithOutputDir(t.TempDir()).
+		Build()
+	assert.NoError(t, err)
+	return cfg
+}
+
+func TestPagination_HeadNextSubmittedAtSameDepth(t *testing.T) {
+	cfg := paginationConfig(t, "https://example.com/api/page/1", true)
+	body := paginatedHTML(`<link rel="next" href="/api/page/2">`)
+
+	candidates := crawlSinglePageCandidatesForTest(t, cfg, body, nil)
+
+	assert.Len(t, candidates, 1)
+	target := candidates[0].TargetURL()
+	assert.Equal(t, "https://example.com/api/page/2", target.String())
+	assert.Equal(t, 0, candidates[0].DiscoveryMetadata().Depth(), "pagination must not increase depth")
+}
+
+func TestPagination_LinkHeaderNextAndPrev(t *testing.T) {
+	cfg := paginationConfig(t, "https://example.com/api/page/2", true)
+	headers := map[string]string{
+		"Link": `<https://example.com/api/page/3>; rel="next", </api/page/1>; rel="prev", </api/style.css>; rel="stylesheet"`,
+	}
+
+	candidates := crawlSinglePageCandidatesForTest(t, cfg, paginatedHTML(""), headers)
+
+	var targets []string
+	for _, c := range candidates {
+		target := c.TargetURL()
+		targets = append(targets, target.String())
+		assert.Equal(t, 0, c.DiscoveryMetadata().Depth())
+	}
+	assert.Equal(t, []string{
+		"https://example.com/api/page/3",
+		"https://example.com/api/page/1",
+	}, targets)
+}
+
+func TestPagination_Disabled_IgnoresRelLinks(t *testing.T) {
+	cfg := paginationConfig(t, "https://example.com/api/page/1", false)
+	body := paginatedHTML(`<link rel="next" href="/api/page/2">`)
+	headers := map[string]string{"Link": `</api/page/2>; rel="next"`}
+
+	candidates := crawlSinglePageCandidatesForTest(t, cfg, body, headers)
+
+	assert.Empty(t, candidates)
+}
+
+func TestPagination_CycleIsDeduped(t *testing.T) {
+	// GIVEN page 1 -> next -> page 2, and page 2 pointing back to page 1
+	// through both rel="prev" and rel="next"
+	page1, _ := url.Parse("https://example.com/api/page/1")
+	page2, _ := url.Parse("https://example.com/api/page/2")
+	bodies := map[string]string{
+		page1.String(): paginatedHTML(`<link rel="next" href="/api/page/2">`),
+		page2.String(): paginatedHTML(`<link rel="prev" href="/api/page/1"><link rel="next" href="/api/page/1">`),
+	}
+
+	mockFetcher := new(fetcherMock)
+	mockFetcher.On("Init", mock.Anything, mock.Anything).Return()
+	for _, u := range []*url.URL{page1, page2} {
+		target := *u
+		mockFetcher.On("Fetch", mock.Anything, mock.Anything, target, mock.Anything).Return(
+			fetcher.NewFetchResultForTest(
+				target,
+				[]byte(bodies[target.String()]),
+				200,
+				"text/html",
+				map[string]string{"Content-Type": "text/html"},
+				time.Now(),
+			), nil)
+	}
+
+	mockRobot := NewRobotsMockForTest(t)
+	mockRobot.On("Init", mock.Anything, mock.Anything).Return()
+	mockRobot.OnDecide(mock.Anything, robots.Decision{Allowed: true, Reason: robots.EmptyRuleSet}, nil)
+
+	mockConvert := newConvertMockForTest(t)
+	setupConvertMockWithSuccess(mockConvert)
+	mockResolver := newResolverMockForTest(t)
+	setupResolverMockWithSuccess(mockResolver)
+	mockNormalize := newNormalizeMockForTest(t)
+	setupNormalizeMockWithSuccess(mockNormalize)
+
+	noopSink := &metadata.NoopSink{}
+	realFrontier := frontier.NewCrawlFrontier()
+	ext := extractor.NewDomExtractor(noopSink)
+	san := sanitizer.NewHTMLSanitizer(noopSink)
+	s := scheduler.NewSchedulerWithDeps(
+		context.Background(),
+		newMockFinalizer(t),
+		noopSink,
+		newRateLimiterMockForTest(t),
+		&realFrontier,
+		mockFetcher,
+		mockRobot,
+		&ext,
+		&san,
+		mockConvert,
+		mockResolver,
+		mockNormalize,
+		storage.NewMemoryWriter(),
+		newFailureJournalMockForTest(t),
+		stagedump.NewNoOpDumper(),
+		debug.NewNoOpLogger(),
+	)
+
+	init, err := s.InitializeWithConfig(paginationConfig(t, page1.String(), true))
+	assert.NoError(t, err)
+
+	// WHEN crawling
+	_, err = s.ExecuteCrawlingWithState(init)
+
+	// THEN each page is fetched exactly once
+	assert.NoError(t, err)
+	mockFetcher.AssertNumberOfCalls(t, "Fetch", 2)
+	assert.Equal(t, 2, realFrontier.VisitedCount())
+}
@@ -0,0 +1,94 @@
+package scheduler_test
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeOversizedPageArchiveForTest archives an index linking to a normal
+// page and to a page whose HTML body is well over 8 KiB.
+func writeOversizedPageArchiveForTest(t *testing.T) string {
+	t.Helper()
+	archiveDir := t.TempDir()
+	writePage := func(path, title, body string) {
+		require.NoError(t, fetcher.WriteArchiveEntry(archiveDir, fetcher.ArchiveEntry{
+			URL:     "https://docs.example.com" + path,
+			Status:  http.StatusOK,
+			Headers: http.Header{"Content-Type": {"text/html"}},
+			Body: []byte(`<html><body><main><h1>` + title + `</h1>
+<p>This page has enough text to pass content extraction and be written.</p>
+` + body + `</main></body></html>`),
+		}))
+	}
+
+	writePage("/docs", "Index", `<ul><li><a href="/docs/small">Small</a></li>
+<li><a href="/docs/huge">Huge</a></li></ul>`)
+	writePage("/docs/small", "Small", "")
+	writePage("/docs/huge", "Huge", strings.Repeat("<div><span>filler</span></div>\n", 400))
+	return archiveDir
+}
+
+func TestScheduler_MaxHTMLParseBytes_SkipsOversizedPage(t *testing.T) {
+	archiveDir := writeOversizedPageArchiveForTest(t)
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir("out").
+		WithReplayArchive(archiveDir).
+		WithMaxHTMLParseBytes(8 << 10).
+		Build()
+	require.NoError(t, err)
+
+	sink := &metadatatest.SinkMock{}
+	writer := storage.NewMemoryWriter()
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	execution, err := runPipelineForTest(t, &archiveFetcher, sink, writer, cfg)
+	require.NoError(t, err)
+
+	// The huge page is fetched, then skipped before extraction
+	var skipped []string
+	for _, event := range sink.SkipEvents {
+		if event.Reason() == metadata.SkipReasonTooLargeToParse {
+			skipped = append(skipped, event.SkippedURL())
+		}
+	}
+	assert.Equal(t, []string{"https://docs.example.com/docs/huge"}, skipped)
+	assert.Empty(t, sink.ErrorRecords)
+
+	var written []string
+	for _, result := range execution.WriteResults() {
+		written = append(written, result.SourceURL())
+	}
+	assert.ElementsMatch(t, []string{
+		"https://docs.example.com/docs",
+		"https://docs.example.com/docs/small",
+	}, written)
+}
+
+func TestScheduler_MaxHTMLParseBytes_UnlimitedByDefault(t *testing.T) {
+	archiveDir := writeOversizedPageArchiveForTest(t)
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir("out").
+		WithReplayArchive(archiveDir).
+		Build()
+	require.NoError(t, err)
+
+	sink := &metadatatest.SinkMock{}
+	writer := storage.NewMemoryWriter()
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	execution, err := runPipelineForTest(t, &archiveFetcher, sink, writer, cfg)
+	require.NoError(t, err)
+
+	assert.Len(t, execution.WriteResults(), 3)
+	for _, event := range sink.SkipEvents {
+		assert.NotEqual(t, metadata.SkipReasonTooLargeToParse, event.Reason())
+	}
+}
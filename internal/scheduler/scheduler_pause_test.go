@@ -0,0 +1,103 @@
+package scheduler_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/rohmanhakim/docs-crawler/internal/scheduler"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/rohmanhakim/docs-crawler/pkg/failure"
+	"github.com/rohmanhakim/retrier"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gatedFetcher reports every fetch on fetched and holds the first one until
+// release is closed.
+type gatedFetcher struct {
+	fetcher.ArchiveFetcher
+	fetched chan string
+	release chan struct{}
+	first   bool
+}
+
+func (g *gatedFetcher) Fetch(
+	ctx context.Context,
+	crawlDepth int,
+	fetchUrl url.URL,
+	retryOptions []retrier.RetryOption,
+) (fetcher.FetchResult, failure.ClassifiedError) {
+	g.fetched <- fetchUrl.String()
+	if !g.first {
+		g.first = true
+		<-g.release
+	}
+	return g.ArchiveFetcher.Fetch(ctx, crawlDepth, fetchUrl, retryOptions)
+}
+
+func TestScheduler_PauseHoldsCrawlUntilResumed(t *testing.T) {
+	const pageCount = 4
+	archiveDir := writeLinkedSiteArchiveForTest(t, pageCount)
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir(t.TempDir()).
+		WithReplayArchive(archiveDir).
+		Build()
+	require.NoError(t, err)
+
+	sink := &metadatatest.SinkMock{}
+	gated := &gatedFetcher{
+		ArchiveFetcher: fetcher.NewArchiveFetcher(sink, archiveDir),
+		fetched:        make(chan string, pageCount),
+		release:        make(chan struct{}),
+	}
+
+	var sched *scheduler.Scheduler
+	ready := make(chan struct{})
+	done := make(chan scheduler.CrawlingExecution, 1)
+	go func() {
+		execution, err := runPipelineForTest(t, gated, sink, storage.NewMemoryWriter(), cfg, func(s *scheduler.Scheduler) {
+			sched = s
+			close(ready)
+		})
+		assert.NoError(t, err)
+		done <- execution
+	}()
+
+	// GIVEN the crawl is fetching its first page
+	<-ready
+	select {
+	case <-gated.fetched:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the seed page to be fetched")
+	}
+
+	// WHEN the crawl is paused from another goroutine while the page is in flight
+	sched.Pause()
+	assert.True(t, sched.Paused())
+	close(gated.release)
+
+	// THEN no further page starts while paused
+	select {
+	case fetchedURL := <-gated.fetched:
+		t.Fatalf("expected no fetch while paused, got %s", fetchedURL)
+	case <-done:
+		t.Fatal("expected the crawl not to finish while paused")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	// AND once resumed, the crawl runs to completion
+	sched.Resume()
+	assert.False(t, sched.Paused())
+	select {
+	case execution := <-done:
+		assert.Len(t, execution.WriteResults(), pageCount)
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the crawl to complete after resuming")
+	}
+	assert.Len(t, gated.fetched, pageCount-1, "expected every remaining page to be fetched after resuming")
+}
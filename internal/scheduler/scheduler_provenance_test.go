@@ -0,0 +1,149 @@
+package scheduler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/rohmanhakim/docs-crawler/internal/normalize"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/rohmanhakim/docs-crawler/pkg/failure"
+	"github.com/rohmanhakim/docs-crawler/pkg/hashutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// frontmatterWriter is a MemoryWriter that keeps the frontmatter of every
+// page written, by source URL.
+type frontmatterWriter struct {
+	*storage.MemoryWriter
+	frontmatter map[string]normalize.Frontmatter
+}
+
+func (w *frontmatterWriter) Write(outputDir string, doc normalize.NormalizedMarkdownDoc, hashAlgo hashutil.HashAlgo) (storage.WriteResult, failure.ClassifiedError) {
+	w.frontmatter[doc.Frontmatter().SourceURL()] = doc.Frontmatter()
+	return w.MemoryWriter.Write(outputDir, doc, hashAlgo)
+}
+
+// writeRedirectingSiteArchiveForTest archives an index linking to a guide
+// and to /docs/old, which redirects through /docs/moved to /docs/new.
+func writeRedirectingSiteArchiveForTest(t *testing.T) string {
+	t.Helper()
+	archiveDir := t.TempDir()
+	writePage := func(path, title, body string) {
+		require.NoError(t, fetcher.WriteArchiveEntry(archiveDir, fetcher.ArchiveEntry{
+			URL:     "https://docs.example.com" + path,
+			Status:  http.StatusOK,
+			Headers: http.Header{"Content-Type": {"text/html"}},
+			Body: []byte(`<html><body><main><h1>` + title + `</h1>
+<p>This page has enough text to pass content extraction and be written.</p>
+` + body + `</main></body></html>`),
+		}))
+	}
+	writeRedirect := func(path, location string) {
+		require.NoError(t, fetcher.WriteArchiveEntry(archiveDir, fetcher.ArchiveEntry{
+			URL:     "https://docs.example.com" + path,
+			Status:  http.StatusMovedPermanently,
+			Headers: http.Header{"Location": {location}},
+		}))
+	}
+
+	writePage("/docs", "Index", `<ul><li><a href="/docs/guide">Guide</a></li>
+<li><a href="/docs/old">Old</a></li></ul>`)
+	writePage("/docs/guide", "Guide", "")
+	writeRedirect("/docs/old", "/docs/moved")
+	writeRedirect("/docs/moved", "/docs/new")
+	writePage("/docs/new", "New", "")
+	return archiveDir
+}
+
+func TestScheduler_WriteProvenance_RecordsReferrerAndRedirectChain(t *testing.T) {
+	archiveDir := writeRedirectingSiteArchiveForTest(t)
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir("out").
+		WithReplayArchive(archiveDir).
+		WithWriteProvenance(true).
+		Build()
+	require.NoError(t, err)
+
+	sink := &metadatatest.SinkMock{}
+	writer := &frontmatterWriter{MemoryWriter: storage.NewMemoryWriter(), frontmatter: map[string]normalize.Frontmatter{}}
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	execution, err := runPipelineForTest(t, &archiveFetcher, sink, writer, cfg)
+	require.NoError(t, err)
+
+	// The seed has no referrer and was not redirected
+	seed, ok := writer.frontmatter["https://docs.example.com/docs"]
+	require.True(t, ok)
+	_, recorded := seed.Provenance()
+	assert.True(t, recorded)
+	assert.Empty(t, seed.Referrer())
+	assert.Empty(t, seed.RedirectChain())
+
+	// A discovered page lists the page it was found on
+	guide, ok := writer.frontmatter["https://docs.example.com/docs/guide"]
+	require.True(t, ok)
+	assert.Equal(t, "https://docs.example.com/docs", guide.Referrer())
+	assert.Empty(t, guide.RedirectChain())
+
+	// A redirected page lists every URL requested on the way
+	moved, ok := writer.frontmatter["https://docs.example.com/docs/old"]
+	require.True(t, ok)
+	assert.Equal(t, "https://docs.example.com/docs", moved.Referrer())
+	assert.Equal(t, []string{
+		"https://docs.example.com/docs/old",
+		"https://docs.example.com/docs/moved",
+		"https://docs.example.com/docs/new",
+	}, moved.RedirectChain())
+
+	// The sidecar records the same
+	var sidecar struct {
+		SourceURL     string   `json:"sourceUrl"`
+		CrawlDepth    int      `json:"crawlDepth"`
+		Referrer      string   `json:"referrer"`
+		RedirectChain []string `json:"redirectChain"`
+	}
+	var found bool
+	for _, result := range execution.WriteResults() {
+		if result.SourceURL() != "https://docs.example.com/docs/old" {
+			continue
+		}
+		data, ok := writer.Get(strings.TrimSuffix(result.Path(), ".md") + storage.ProvenanceFileSuffix)
+		require.True(t, ok, "expected a provenance sidecar, got paths %v", writer.Paths())
+		require.NoError(t, json.Unmarshal(data, &sidecar))
+		found = true
+	}
+	require.True(t, found)
+	assert.Equal(t, 1, sidecar.CrawlDepth)
+	assert.Equal(t, "https://docs.example.com/docs", sidecar.Referrer)
+	assert.Equal(t, moved.RedirectChain(), sidecar.RedirectChain)
+}
+
+func TestScheduler_WriteProvenance_DisabledByDefault(t *testing.T) {
+	archiveDir := writeRedirectingSiteArchiveForTest(t)
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir("out").
+		WithReplayArchive(archiveDir).
+		Build()
+	require.NoError(t, err)
+
+	sink := &metadatatest.SinkMock{}
+	writer := &frontmatterWriter{MemoryWriter: storage.NewMemoryWriter(), frontmatter: map[string]normalize.Frontmatter{}}
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	_, err = runPipelineForTest(t, &archiveFetcher, sink, writer, cfg)
+	require.NoError(t, err)
+
+	require.Len(t, writer.frontmatter, 3)
+	for _, frontmatter := range writer.frontmatter {
+		_, recorded := frontmatter.Provenance()
+		assert.False(t, recorded)
+	}
+	for _, path := range writer.Paths() {
+		assert.False(t, strings.HasSuffix(path, storage.ProvenanceFileSuffix))
+	}
+}
@@ -0,0 +1,52 @@
+package scheduler_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const facetedPageHTML = `<!DOCTYPE html>
+<html>
+<body><main>
+<h1>Documentation</h1>
+<p>Welcome to the documentation, with enough text to pass content extraction.</p>
+<p>Browse the <a href="/docs/search?sort=asc&amp;page=2">search results</a>.</p>
+<p>Read the <a href="/docs/api?sort=desc&amp;version=2">API reference</a>.</p>
+</main></body>
+</html>`
+
+func TestScheduler_DropQueryStrings_StripsQueriesAtAdmission(t *testing.T) {
+	seedURL := mustParseURL("https://docs.example.com/docs")
+	cfg, err := config.WithDefault([]url.URL{*seedURL}).
+		WithOutputDir(t.TempDir()).
+		WithDropQueryStrings(true).
+		Build()
+	require.NoError(t, err)
+
+	submitted := crawlSinglePageForTest(t, cfg, facetedPageHTML)
+
+	assert.Equal(t, []string{
+		"https://docs.example.com/docs/search",
+		"https://docs.example.com/docs/api",
+	}, submitted)
+}
+
+func TestScheduler_AllowedQueryParams_KeepsOnlyListedParams(t *testing.T) {
+	seedURL := mustParseURL("https://docs.example.com/docs")
+	cfg, err := config.WithDefault([]url.URL{*seedURL}).
+		WithOutputDir(t.TempDir()).
+		WithAllowedQueryParams([]string{"version"}).
+		Build()
+	require.NoError(t, err)
+
+	submitted := crawlSinglePageForTest(t, cfg, facetedPageHTML)
+
+	assert.Equal(t, []string{
+		"https://docs.example.com/docs/search",
+		"https://docs.example.com/docs/api?version=2",
+	}, submitted)
+}
@@ -0,0 +1,91 @@
+package scheduler_test
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/rohmanhakim/docs-crawler/pkg/hashutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// crawlRawHTMLForTest crawls a single archived page into outputDir with
+// the given raw HTML and dry-run settings, and returns the metadata sink.
+func crawlRawHTMLForTest(t *testing.T, outputDir string, saveRawHTML bool, dryRun bool) *metadatatest.SinkMock {
+	t.Helper()
+	archiveDir := t.TempDir()
+	seed := *mustParseURL("https://docs.example.com/docs/install")
+	require.NoError(t, fetcher.WriteArchiveEntry(archiveDir, fetcher.ArchiveEntry{
+		URL:     seed.String(),
+		Status:  http.StatusOK,
+		Headers: http.Header{"Content-Type": {"text/html"}},
+		Body:    []byte(archiveSitePages["/docs/install"]),
+	}))
+
+	cfg, err := config.WithDefault([]url.URL{seed}).
+		WithOutputDir(outputDir).
+		WithReplayArchive(archiveDir).
+		WithSaveRawHTML(saveRawHTML).
+		WithDryRun(dryRun).
+		Build()
+	require.NoError(t, err)
+	sink := &metadatatest.SinkMock{}
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	var writer storage.Writer = storage.NewLocalSink(sink)
+	if dryRun {
+		writer = storage.NewDryRunSink(sink)
+	}
+	_, err = runPipelineForTest(t, &archiveFetcher, sink, writer, cfg)
+	require.NoError(t, err)
+	return sink
+}
+
+func TestScheduler_SaveRawHTML_WritesFetchedBody(t *testing.T) {
+	outputDir := t.TempDir()
+	sink := crawlRawHTMLForTest(t, outputDir, true, false)
+
+	rawPath, err := storage.RawHTMLPath(outputDir, "https://docs.example.com/docs/install", hashutil.HashAlgoSHA256)
+	require.NoError(t, err)
+	raw, readErr := os.ReadFile(rawPath)
+	require.NoError(t, readErr, "expected the raw HTML under _raw")
+	assert.Equal(t, archiveSitePages["/docs/install"], string(raw))
+
+	// The raw file shares its name with the page's Markdown file
+	mdPath := filepath.Join(outputDir, pageFileNameForTest(t, "https://docs.example.com/docs/install"))
+	_, statErr := os.Stat(mdPath)
+	assert.NoError(t, statErr, "expected the Markdown page next to the raw HTML")
+
+	var recorded bool
+	for _, artifact := range sink.Artifacts {
+		if artifact.Kind() == metadata.ArtifactRawHTML {
+			recorded = true
+			assert.Equal(t, rawPath, artifact.WritePath())
+			assert.Equal(t, int64(len(raw)), artifact.Bytes())
+		}
+	}
+	assert.True(t, recorded, "expected the raw HTML to be recorded as an artifact")
+}
+
+func TestScheduler_SaveRawHTML_DisabledWritesNothing(t *testing.T) {
+	outputDir := t.TempDir()
+	crawlRawHTMLForTest(t, outputDir, false, false)
+
+	_, err := os.Stat(filepath.Join(outputDir, storage.RawHTMLDir))
+	assert.True(t, os.IsNotExist(err), "expected no _raw directory, got %v", err)
+}
+
+func TestScheduler_SaveRawHTML_DryRunWritesNothing(t *testing.T) {
+	outputDir := t.TempDir()
+	crawlRawHTMLForTest(t, outputDir, true, true)
+
+	_, err := os.Stat(filepath.Join(outputDir, storage.RawHTMLDir))
+	assert.True(t, os.IsNotExist(err), "expected no _raw directory, got %v", err)
+}
@@ -0,0 +1,107 @@
+package scheduler_test
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/extractor"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/frontier"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/internal/robots"
+	"github.com/rohmanhakim/docs-crawler/internal/sanitizer"
+	"github.com/rohmanhakim/docs-crawler/internal/scheduler"
+	"github.com/rohmanhakim/docs-crawler/internal/stagedump"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/rohmanhakim/docs-crawler/pkg/debug"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const recrawlPageHTML = `<!DOCTYPE html>
+<html>
+<head><title>Recrawl</title><link rel="next" href="/guide/next"></head>
+<body>
+<main>
+<h1>Recrawled Page</h1>
+<p>This page links to other pages of the site, which a re-crawl of a fixed
+page list must not follow. See <a href="/guide/linked">the linked page</a>.</p>
+</main>
+</body>
+</html>`
+
+func TestScheduler_RecrawlPages_FetchesOnlyGivenPages(t *testing.T) {
+	// GIVEN two pages to re-crawl, each linking to further pages
+	first := *mustParseURL("https://example.com/guide/a")
+	second := *mustParseURL("https://example.com/guide/b")
+
+	var fetched []string
+	mockFetcher := new(fetcherMock)
+	mockFetcher.On("Init", mock.Anything, mock.Anything).Return()
+	linked := *mustParseURL("https://example.com/guide/linked")
+	next := *mustParseURL("https://example.com/guide/next")
+	for _, target := range []url.URL{first, second, linked, next} {
+		target := target
+		mockFetcher.On("Fetch", mock.Anything, mock.Anything, target, mock.Anything).
+			Run(func(args mock.Arguments) {
+				fetched = append(fetched, target.String())
+			}).
+			Return(fetcher.NewFetchResultForTest(
+				target,
+				[]byte(recrawlPageHTML),
+				200,
+				"text/html",
+				map[string]string{"Content-Type": "text/html"},
+				time.Now(),
+			), nil).Maybe()
+	}
+
+	mockRobot := NewRobotsMockForTest(t)
+	mockRobot.On("Init", mock.Anything, mock.Anything).Return()
+	mockRobot.OnDecide(mock.Anything, robots.Decision{Allowed: true, Reason: robots.EmptyRuleSet}, nil)
+
+	mockConvert := newConvertMockForTest(t)
+	setupConvertMockWithSuccess(mockConvert)
+	mockResolver := newResolverMockForTest(t)
+	setupResolverMockWithSuccess(mockResolver)
+	mockNormalize := newNormalizeMockForTest(t)
+	setupNormalizeMockWithSuccess(mockNormalize)
+
+	noopSink := &metadata.NoopSink{}
+	realFrontier := frontier.NewCrawlFrontier()
+	ext := extractor.NewDomExtractor(noopSink)
+	san := sanitizer.NewHTMLSanitizer(noopSink)
+	memory := storage.NewMemoryWriter()
+	s := scheduler.NewSchedulerWithDeps(
+		context.Background(),
+		newMockFinalizer(t),
+		noopSink,
+		newRateLimiterMockForTest(t),
+		&realFrontier,
+		mockFetcher,
+		mockRobot,
+		&ext,
+		&san,
+		mockConvert,
+		mockResolver,
+		mockNormalize,
+		memory,
+		newFailureJournalMockForTest(t),
+		stagedump.NewNoOpDumper(),
+		debug.NewNoOpLogger(),
+	)
+
+	// WHEN re-crawling the pages with pagination following enabled
+	execution, err := s.RecrawlPages(paginationConfig(t, "https://example.com/", true), []url.URL{first, second})
+	assert.NoError(t, err)
+
+	// THEN only the given pages are fetched, in order, and no manifest is written
+	assert.Equal(t, []string{first.String(), second.String()}, fetched)
+	assert.NotEmpty(t, execution.WriteResults())
+	for _, path := range memory.Paths() {
+		assert.False(t, strings.HasSuffix(path, storage.ManifestFileName), "unexpected manifest %s", path)
+	}
+}
@@ -0,0 +1,96 @@
+package scheduler_test
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeOutOfScopeRedirectArchiveForTest archives an index linking to
+// /docs/moved, which redirects to a page on other.example.org that links on
+// to another page there.
+func writeOutOfScopeRedirectArchiveForTest(t *testing.T) string {
+	t.Helper()
+	archiveDir := t.TempDir()
+	writePage := func(pageURL, body string) {
+		require.NoError(t, fetcher.WriteArchiveEntry(archiveDir, fetcher.ArchiveEntry{
+			URL:     pageURL,
+			Status:  http.StatusOK,
+			Headers: http.Header{"Content-Type": {"text/html"}},
+			Body: []byte(`<html><body><main><h1>Page</h1>
+<p>This page has enough text to pass content extraction and be written.</p>
+` + body + `</main></body></html>`),
+		}))
+	}
+	writePage("https://docs.example.com/docs", `<a href="/docs/moved">Moved</a>`)
+	require.NoError(t, fetcher.WriteArchiveEntry(archiveDir, fetcher.ArchiveEntry{
+		URL:     "https://docs.example.com/docs/moved",
+		Status:  http.StatusMovedPermanently,
+		Headers: http.Header{"Location": {"https://other.example.org/landing"}},
+	}))
+	writePage("https://other.example.org/landing", `<a href="https://docs.example.com/docs/from-landing">Back</a>`)
+	writePage("https://docs.example.com/docs/from-landing", "")
+	return archiveDir
+}
+
+func TestScheduler_RedirectOutOfScope(t *testing.T) {
+	tests := []struct {
+		name        string
+		behavior    string
+		wantPages   int
+		wantSkip    bool
+		wantLanding bool
+	}{
+		{name: "skip", behavior: config.RedirectOutOfScopeSkip, wantPages: 1, wantSkip: true},
+		{name: "write", behavior: config.RedirectOutOfScopeWrite, wantPages: 3, wantLanding: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archiveDir := writeOutOfScopeRedirectArchiveForTest(t)
+			cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+				WithOutputDir("out").
+				WithReplayArchive(archiveDir).
+				WithRedirectOutOfScope(tt.behavior).
+				Build()
+			require.NoError(t, err)
+
+			sink := &metadatatest.SinkMock{}
+			archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+			execution, err := runPipelineForTest(t, &archiveFetcher, sink, storage.NewMemoryWriter(), cfg)
+			require.NoError(t, err)
+
+			assert.Len(t, execution.WriteResults(), tt.wantPages)
+
+			var skipped []string
+			for _, event := range sink.SkipEvents {
+				if event.Reason() == metadata.SkipReasonRedirectOutOfScope {
+					skipped = append(skipped, event.SkippedURL())
+				}
+			}
+			if tt.wantSkip {
+				assert.Equal(t, []string{"https://other.example.org/landing"}, skipped)
+			} else {
+				assert.Empty(t, skipped)
+			}
+
+			// Links on the redirect target are only followed when it is written
+			var fetchedFromLanding bool
+			for _, event := range sink.FetchEvents {
+				if event.Kind() == metadata.KindPage && strings.HasSuffix(event.FetchURL(), "/docs/from-landing") {
+					fetchedFromLanding = true
+				}
+			}
+			assert.Equal(t, tt.wantLanding, fetchedFromLanding)
+		})
+	}
+}
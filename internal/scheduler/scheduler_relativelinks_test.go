@@ -0,0 +1,95 @@
+package scheduler_test
+
+import (
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/rohmanhakim/docs-crawler/pkg/hashutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// linkedSitePages are an English index and a French page linking to each
+// other, plus a link to a download the crawl excludes.
+var linkedSitePages = map[string]string{
+	"/docs": `<!DOCTYPE html>
+<html lang="en">
+<body><main>
+<h1 id="intro">Documentation</h1>
+<p>Welcome to the documentation, with enough text to pass content extraction.</p>
+<p>Read it in <a href="/docs/fr">French</a> or see the <a href="guide.pdf">printable guide</a>.</p>
+</main></body>
+</html>`,
+	"/docs/fr": `<!DOCTYPE html>
+<html lang="fr">
+<body><main>
+<h1>Documentation</h1>
+<p>Bienvenue dans la documentation, avec assez de texte pour passer l'extraction.</p>
+<p>Retour à la <a href="/docs#intro">version anglaise</a>.</p>
+</main></body>
+</html>`,
+}
+
+func crawlLinkedSiteForTest(t *testing.T, partition bool) (config.Config, *storage.MemoryWriter) {
+	t.Helper()
+	archiveDir := t.TempDir()
+	for path, page := range linkedSitePages {
+		require.NoError(t, fetcher.WriteArchiveEntry(archiveDir, fetcher.ArchiveEntry{
+			URL:     "https://docs.example.com" + path,
+			Status:  http.StatusOK,
+			Headers: http.Header{"Content-Type": {"text/html"}},
+			Body:    []byte(page),
+		}))
+	}
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir("out").
+		WithReplayArchive(archiveDir).
+		WithPartitionByLanguage(partition).
+		WithRelativeLinks(true).
+		Build()
+	require.NoError(t, err)
+
+	sink := &metadatatest.SinkMock{}
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	writer := storage.NewMemoryWriter()
+	_, err = runPipelineForTest(t, &archiveFetcher, sink, writer, cfg)
+	require.NoError(t, err)
+	return cfg, writer
+}
+
+func pageFileNameForTest(t *testing.T, rawURL string) string {
+	t.Helper()
+	name, err := storage.PageFileName(rawURL, hashutil.HashAlgoSHA256)
+	require.NoError(t, err)
+	return name
+}
+
+func TestScheduler_RelativeLinks_BetweenLanguagePartitions(t *testing.T) {
+	_, writer := crawlLinkedSiteForTest(t, true)
+
+	englishName := pageFileNameForTest(t, "https://docs.example.com/docs")
+	french, ok := writer.Get(filepath.Join("out", "fr", pageFileNameForTest(t, "https://docs.example.com/docs/fr")))
+	require.True(t, ok, "expected the French page under out/fr")
+	assert.Contains(t, string(french), "[version anglaise](../en/"+englishName+"#intro)")
+
+	english, ok := writer.Get(filepath.Join("out", "en", englishName))
+	require.True(t, ok, "expected the English page under out/en")
+	// Neither link target was written when the English page was normalized
+	assert.Contains(t, string(english), "(https://docs.example.com/docs/fr)")
+	assert.Contains(t, string(english), "(https://docs.example.com/guide.pdf)")
+}
+
+func TestScheduler_RelativeLinks_PredictsAdmittedPages(t *testing.T) {
+	_, writer := crawlLinkedSiteForTest(t, false)
+
+	english, ok := writer.Get(filepath.Join("out", pageFileNameForTest(t, "https://docs.example.com/docs")))
+	require.True(t, ok, "expected the English page")
+	assert.Contains(t, string(english), "[French]("+pageFileNameForTest(t, "https://docs.example.com/docs/fr")+")")
+	assert.Contains(t, string(english), "(https://docs.example.com/guide.pdf)")
+}
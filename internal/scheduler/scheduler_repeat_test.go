@@ -0,0 +1,211 @@
+package scheduler_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/rohmanhakim/docs-crawler/internal/normalize"
+	"github.com/rohmanhakim/docs-crawler/internal/scheduler"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/rohmanhakim/docs-crawler/pkg/failure"
+	"github.com/rohmanhakim/docs-crawler/pkg/hashutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingWriter is a MemoryWriter that records the canonical URL of every
+// page written.
+type recordingWriter struct {
+	*storage.MemoryWriter
+	written []string
+}
+
+func (w *recordingWriter) Write(outputDir string, doc normalize.NormalizedMarkdownDoc, hashAlgo hashutil.HashAlgo) (storage.WriteResult, failure.ClassifiedError) {
+	w.written = append(w.written, doc.Frontmatter().CanonicalURL())
+	return w.MemoryWriter.Write(outputDir, doc, hashAlgo)
+}
+
+// passSleeper runs onSleep on every sleep of the repeat interval, between
+// two crawl passes, instead of blocking.
+type passSleeper struct {
+	interval time.Duration
+	onSleep  func()
+}
+
+func (s *passSleeper) Sleep(ctx context.Context, d time.Duration) error {
+	if d == s.interval {
+		s.onSleep()
+	}
+	return ctx.Err()
+}
+
+func TestScheduler_Repeat_RewritesOnlyChangedPages(t *testing.T) {
+	archiveDir := writeLinkedSiteArchiveForTest(t, 3)
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir(t.TempDir()).
+		WithReplayArchive(archiveDir).
+		WithMaxIterations(2).
+		WithRepeatInterval(time.Hour).
+		Build()
+	require.NoError(t, err)
+
+	writer := &recordingWriter{MemoryWriter: storage.NewMemoryWriter()}
+	var firstPass []string
+	sleeper := &passSleeper{interval: time.Hour, onSleep: func() {
+		firstPass = append([]string(nil), writer.written...)
+		writer.written = nil
+		// The second page changes between the passes
+		require.NoError(t, fetcher.WriteArchiveEntry(archiveDir, fetcher.ArchiveEntry{
+			URL:     "https://docs.example.com/docs/page-2",
+			Status:  http.StatusOK,
+			Headers: http.Header{"Content-Type": {"text/html"}},
+			Body: []byte(`<html><body><main><h1>Page 2</h1>
+<p>This page was updated after the first pass and has to be written again.</p>
+</main></body></html>`),
+		}))
+	}}
+
+	sink := &metadatatest.SinkMock{}
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	execution, err := runPipelineForTest(t, &archiveFetcher, sink, writer, cfg, func(s *scheduler.Scheduler) {
+		s.SetSleeper(sleeper)
+	})
+	require.NoError(t, err)
+
+	// Two passes ran: all pages were written first, then the changed one only
+	assert.Len(t, firstPass, 3)
+	assert.Equal(t, []string{"https://docs.example.com/docs/page-2"}, writer.written)
+
+	iterations := execution.Iterations()
+	require.Len(t, iterations, 2)
+	assert.Equal(t, 1, iterations[0].Iteration)
+	assert.Equal(t, 3, iterations[0].WrittenPages)
+	assert.Equal(t, 0, iterations[0].UnchangedPages)
+	assert.Equal(t, 2, iterations[1].Iteration)
+	assert.Equal(t, 1, iterations[1].WrittenPages)
+	assert.Equal(t, 2, iterations[1].UnchangedPages)
+
+	// The last pass still lists every page
+	assert.Equal(t, 3, execution.TotalPages())
+	assert.Equal(t, 2, execution.UnchangedPages())
+	page, ok := writer.Get(execution.WriteResults()[2].Path())
+	require.True(t, ok)
+	assert.Contains(t, string(page), "updated after the first pass")
+}
+
+func TestScheduler_Repeat_SinglePassByDefault(t *testing.T) {
+	archiveDir := writeLinkedSiteArchiveForTest(t, 2)
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir(t.TempDir()).
+		WithReplayArchive(archiveDir).
+		Build()
+	require.NoError(t, err)
+
+	sink := &metadatatest.SinkMock{}
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	execution, err := runPipelineForTest(t, &archiveFetcher, sink, storage.NewMemoryWriter(), cfg)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, execution.TotalPages())
+	assert.Nil(t, execution.Iterations())
+	assert.Zero(t, execution.UnchangedPages())
+}
+
+func TestScheduler_Repeat_BudgetStopEndsAllPasses(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(*config.Config) *config.Config
+		reason    scheduler.StopReason
+	}{
+		{"max pages", func(c *config.Config) *config.Config {
+			return c.WithMaxPages(2)
+		}, scheduler.StopReasonMaxPages},
+		{"first match", func(c *config.Config) *config.Config {
+			return c.WithStopAfterFirstMatch(true).WithMatchPattern(`/docs/page-1$`)
+		}, scheduler.StopReasonFirstMatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archiveDir := writeLinkedSiteArchiveForTest(t, 4)
+			cfg, err := tt.configure(config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+				WithOutputDir(t.TempDir()).
+				WithReplayArchive(archiveDir).
+				WithMaxIterations(3).
+				WithRepeatInterval(time.Hour)).
+				Build()
+			require.NoError(t, err)
+
+			var sleeps int
+			sleeper := &passSleeper{interval: time.Hour, onSleep: func() { sleeps++ }}
+			sink := &metadatatest.SinkMock{}
+			archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+			execution, err := runPipelineForTest(t, &archiveFetcher, sink, storage.NewMemoryWriter(), cfg, func(s *scheduler.Scheduler) {
+				s.SetSleeper(sleeper)
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.reason, execution.StopReason())
+			assert.Len(t, execution.Iterations(), 1)
+			assert.Zero(t, sleeps, "expected no wait for a further pass")
+		})
+	}
+}
+
+func TestScheduler_Repeat_SingleFileOutputHoldsEveryPageOnce(t *testing.T) {
+	archiveDir := writeLinkedSiteArchiveForTest(t, 3)
+	outputDir := t.TempDir()
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir(outputDir).
+		WithReplayArchive(archiveDir).
+		WithMaxIterations(3).
+		WithRepeatInterval(time.Hour).
+		WithSingleFileOutput("all.md").
+		Build()
+	require.NoError(t, err)
+
+	// The second page changes between every two passes
+	pass := 1
+	sleeper := &passSleeper{interval: time.Hour, onSleep: func() {
+		pass++
+		require.NoError(t, fetcher.WriteArchiveEntry(archiveDir, fetcher.ArchiveEntry{
+			URL:     "https://docs.example.com/docs/page-2",
+			Status:  http.StatusOK,
+			Headers: http.Header{"Content-Type": {"text/html"}},
+			Body: []byte(fmt.Sprintf(`<html><body><main><h1>Page 2</h1>
+<p>This page was updated before pass %d and has to be written again.</p>
+</main></body></html>`, pass)),
+		}))
+	}}
+
+	sink := &metadatatest.SinkMock{}
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	execution, err := runPipelineForTest(t, &archiveFetcher, sink, storage.NewMemoryWriter(), cfg, func(s *scheduler.Scheduler) {
+		s.SetSleeper(sleeper)
+	})
+	require.NoError(t, err)
+	require.Len(t, execution.Iterations(), 3)
+	assert.Equal(t, 2, execution.UnchangedPages())
+
+	combined, err := os.ReadFile(filepath.Join(outputDir, "all.md"))
+	require.NoError(t, err)
+	content := string(combined)
+	for _, source := range []string{
+		"https://docs.example.com/docs",
+		"https://docs.example.com/docs/page-1",
+		"https://docs.example.com/docs/page-2",
+	} {
+		assert.Equal(t, 1, strings.Count(content, "> Source: <"+source+">"), "expected %s once in the combined file", source)
+	}
+	assert.Contains(t, content, "updated before pass 3")
+	assert.NotContains(t, content, "updated before pass 2")
+}
@@ -0,0 +1,126 @@
+package scheduler_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// warmupHostPage is served by every host; the seed host's page also links
+// to the other hosts.
+const warmupHostPage = `<!DOCTYPE html>
+<html>
+<head><title>Host</title></head>
+<body>
+<main>
+<h1>Host %d</h1>
+<p>This page is served by one of several hosts so that robots.txt warmup
+can be verified across hosts, with enough text to pass extraction.</p>
+<p>%s</p>
+</main>
+</body>
+</html>`
+
+// requestLog records the requests served by the test hosts, in order.
+type requestLog struct {
+	mu       sync.Mutex
+	requests []string
+}
+
+func (l *requestLog) add(request string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.requests = append(l.requests, request)
+}
+
+func (l *requestLog) all() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.requests...)
+}
+
+func TestScheduler_RobotsWarmup_FetchesEachHostOnceBeforeCrawling(t *testing.T) {
+	log := &requestLog{}
+	servers := make([]*httptest.Server, 3)
+	for i := range servers {
+		servers[i] = httptest.NewUnstartedServer(nil)
+		servers[i].Start()
+		defer servers[i].Close()
+	}
+	var links []string
+	for _, server := range servers[1:] {
+		links = append(links, fmt.Sprintf(`<a href="%s/docs">%s</a>`, server.URL, server.URL))
+	}
+	for i, server := range servers {
+		body := ""
+		if i == 0 {
+			body = "Other hosts: " + strings.Join(links, ", ")
+		}
+		host := mustParseURL(server.URL).Host
+		server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			log.add(host + r.URL.Path)
+			switch r.URL.Path {
+			case "/robots.txt":
+				w.Header().Set("Content-Type", "text/plain")
+				w.Write([]byte("User-agent: *\nAllow: /"))
+			case "/docs":
+				w.Header().Set("Content-Type", "text/html; charset=utf-8")
+				fmt.Fprintf(w, warmupHostPage, i, body)
+			default:
+				http.NotFound(w, r)
+			}
+		})
+	}
+
+	allowed := make(map[string]struct{})
+	for _, server := range servers {
+		allowed[mustParseURL(server.URL).Host] = struct{}{}
+	}
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL(servers[0].URL + "/docs")}).
+		WithAllowedHosts(allowed).
+		WithOutputDir(t.TempDir()).
+		WithRobotsWarmup(true).
+		Build()
+	require.NoError(t, err)
+
+	sink := &metadatatest.SinkMock{}
+	htmlFetcher := fetcher.NewHtmlFetcher(sink)
+	fetched, _ := crawlPipelineForTest(t, &htmlFetcher, sink, cfg)
+	require.Len(t, fetched, 3, "every host's page should be crawled")
+
+	// Each robots.txt is fetched exactly once, all before the first page
+	requests := log.all()
+	robotsFetches := make(map[string]int)
+	firstPage := len(requests)
+	for i, request := range requests {
+		if strings.HasSuffix(request, "/robots.txt") {
+			robotsFetches[request]++
+			assert.Less(t, i, firstPage, "robots.txt fetched after the crawl started: %s", request)
+		} else if firstPage == len(requests) {
+			firstPage = i
+		}
+	}
+	assert.Len(t, robotsFetches, 3)
+	for request, count := range robotsFetches {
+		assert.Equal(t, 1, count, "expected one fetch of %s", request)
+	}
+
+	var robotsEvents int
+	for _, event := range sink.FetchEvents {
+		if event.Kind() == metadata.KindRobots {
+			robotsEvents++
+		}
+	}
+	assert.Equal(t, 3, robotsEvents, "expected one robots fetch event per host")
+}
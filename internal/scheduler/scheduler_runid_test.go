@@ -0,0 +1,108 @@
+package scheduler_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/rohmanhakim/docs-crawler/internal/scheduler"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runIDConfigForTest builds a config over two seeds, adjusted by edit.
+func runIDConfigForTest(t *testing.T, edit func(*config.Config) *config.Config) config.Config {
+	t.Helper()
+	builder := config.WithDefault([]url.URL{
+		*mustParseURL("https://docs.example.com/docs"),
+		*mustParseURL("https://api.example.com/reference"),
+	})
+	if edit != nil {
+		builder = edit(builder)
+	}
+	cfg, err := builder.Build()
+	require.NoError(t, err)
+	return cfg
+}
+
+func TestRunID_StableForIdenticalConfig(t *testing.T) {
+	first := scheduler.RunID(runIDConfigForTest(t, nil))
+	second := scheduler.RunID(runIDConfigForTest(t, nil))
+
+	assert.Equal(t, first, second)
+	assert.Len(t, first, 16)
+
+	// Seed order, output location and the random seed do not change the run
+	reordered := runIDConfigForTest(t, func(c *config.Config) *config.Config {
+		return c.WithSeedUrls([]url.URL{
+			*mustParseURL("https://api.example.com/reference"),
+			*mustParseURL("https://docs.example.com/docs"),
+		}).WithOutputDir("elsewhere").WithRandomSeed(42)
+	})
+	assert.Equal(t, first, scheduler.RunID(reordered))
+
+	// No path prefix admits the same paths as the default "/"
+	unprefixed := runIDConfigForTest(t, func(c *config.Config) *config.Config {
+		return c.WithAllowedPathPrefix(nil)
+	})
+	assert.Equal(t, first, scheduler.RunID(unprefixed))
+}
+
+func TestRunID_ChangesWithScope(t *testing.T) {
+	base := scheduler.RunID(runIDConfigForTest(t, nil))
+
+	tests := []struct {
+		name string
+		edit func(*config.Config) *config.Config
+	}{
+		{name: "seeds", edit: func(c *config.Config) *config.Config {
+			return c.WithSeedUrls([]url.URL{*mustParseURL("https://docs.example.com/guide")})
+		}},
+		{name: "allowed hosts", edit: func(c *config.Config) *config.Config {
+			return c.WithAllowedHosts(map[string]struct{}{"docs.example.com": {}})
+		}},
+		{name: "include subdomains", edit: func(c *config.Config) *config.Config {
+			return c.WithIncludeSubdomains(true)
+		}},
+		{name: "allowed path prefix", edit: func(c *config.Config) *config.Config {
+			return c.WithAllowedPathPrefix([]string{"/docs"})
+		}},
+		{name: "max depth", edit: func(c *config.Config) *config.Config {
+			return c.WithMaxDepth(7)
+		}},
+		{name: "max pages", edit: func(c *config.Config) *config.Config {
+			return c.WithMaxPages(5)
+		}},
+		{name: "single page", edit: func(c *config.Config) *config.Config {
+			return c.WithSinglePage(true)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NotEqual(t, base, scheduler.RunID(runIDConfigForTest(t, tt.edit)))
+		})
+	}
+}
+
+func TestScheduler_RunIDInWriteResults(t *testing.T) {
+	archiveDir := writeLinkedSiteArchiveForTest(t, 2)
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir("out").
+		WithReplayArchive(archiveDir).
+		Build()
+	require.NoError(t, err)
+
+	sink := &metadatatest.SinkMock{}
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	execution, err := runPipelineForTest(t, &archiveFetcher, sink, storage.NewMemoryWriter(), cfg)
+	require.NoError(t, err)
+
+	require.NotEmpty(t, execution.WriteResults())
+	for _, result := range execution.WriteResults() {
+		assert.Equal(t, scheduler.RunID(cfg), result.RunID())
+	}
+}
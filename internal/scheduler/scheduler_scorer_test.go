@@ -0,0 +1,71 @@
+package scheduler_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/rohmanhakim/docs-crawler/internal/scheduler"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_URLScorer_BestFirstDequeuesHigherScoresFirst(t *testing.T) {
+	// GIVEN an index linking to blog posts before reference pages, all at depth 1
+	archiveDir := t.TempDir()
+	paths := []string{"/blog/one", "/reference/one", "/blog/two", "/reference/two"}
+	var links strings.Builder
+	for _, p := range paths {
+		fmt.Fprintf(&links, `<li><a href="%s">%s</a></li>`, p, p)
+	}
+	writePage := func(path, body string) {
+		require.NoError(t, fetcher.WriteArchiveEntry(archiveDir, fetcher.ArchiveEntry{
+			URL:     "https://docs.example.com" + path,
+			Status:  http.StatusOK,
+			Headers: http.Header{"Content-Type": {"text/html"}},
+			Body: []byte(`<html><body><main><h1>Page</h1>
+<p>This page has enough text to pass content extraction and be written.</p>
+` + body + `</main></body></html>`),
+		}))
+	}
+	writePage("/", "<ul>"+links.String()+"</ul>")
+	for _, p := range paths {
+		writePage(p, "")
+	}
+
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/")}).
+		WithOutputDir("out").
+		WithReplayArchive(archiveDir).
+		WithFrontierStrategy(config.FrontierStrategyBestFirst).
+		Build()
+	require.NoError(t, err)
+
+	// WHEN the crawl scores /reference/ paths above everything else
+	sink := &metadatatest.SinkMock{}
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	_, err = runPipelineForTest(t, &archiveFetcher, sink, storage.NewMemoryWriter(), cfg, func(s *scheduler.Scheduler) {
+		s.SetURLScorer(func(u url.URL, depth int) float64 {
+			if strings.HasPrefix(u.Path, "/reference/") {
+				return 1
+			}
+			return 0
+		})
+	})
+	require.NoError(t, err)
+
+	// THEN the reference pages are fetched before the equal-depth blog posts
+	var fetched []string
+	for _, event := range sink.FetchEvents {
+		if event.Kind() == metadata.KindPage {
+			fetched = append(fetched, strings.TrimPrefix(event.FetchURL(), "https://docs.example.com"))
+		}
+	}
+	assert.Equal(t, []string{"/", "/reference/one", "/reference/two", "/blog/one", "/blog/two"}, fetched)
+}
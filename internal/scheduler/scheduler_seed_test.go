@@ -0,0 +1,305 @@
+package scheduler_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/extractor"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/frontier"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/rohmanhakim/docs-crawler/internal/robots"
+	"github.com/rohmanhakim/docs-crawler/internal/sanitizer"
+	"github.com/rohmanhakim/docs-crawler/internal/scheduler"
+	"github.com/rohmanhakim/docs-crawler/internal/stagedump"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/rohmanhakim/docs-crawler/pkg/debug"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const seededPageHTML = `<!DOCTYPE html>
+<html>
+<head><title>Seeded</title></head>
+<body>
+<main>
+<h1>Seeded Page</h1>
+<p>This page was injected into the frontier programmatically and has no
+outgoing links, so the crawl order depends only on the seeded depths.</p>
+</main>
+</body>
+</html>`
+
+func TestScheduler_SeedFrontier_ProcessesInBFSOrderAndFiltersDisallowed(t *testing.T) {
+	// GIVEN a config seed plus three injected candidates at mixed depths,
+	// one of which robots.txt disallows
+	seed := *mustParseURL("https://example.com/")
+	deep := *mustParseURL("https://example.com/guide/advanced")
+	shallow := *mustParseURL("https://example.com/guide")
+	private := *mustParseURL("https://example.com/private/notes")
+
+	var fetched []string
+	mockFetcher := new(fetcherMock)
+	mockFetcher.On("Init", mock.Anything, mock.Anything).Return()
+	for _, target := range []url.URL{seed, deep, shallow, private} {
+		target := target
+		mockFetcher.On("Fetch", mock.Anything, mock.Anything, target, mock.Anything).
+			Run(func(args mock.Arguments) {
+				fetched = append(fetched, target.String())
+			}).
+			Return(fetcher.NewFetchResultForTest(
+				target,
+				[]byte(seededPageHTML),
+				200,
+				"text/html",
+				map[string]string{"Content-Type": "text/html"},
+				time.Now(),
+			), nil).Maybe()
+	}
+
+	mockRobot := NewRobotsMockForTest(t)
+	mockRobot.On("Init", mock.Anything, mock.Anything).Return()
+	mockRobot.OnDecide(mock.MatchedBy(func(u url.URL) bool {
+		return strings.HasPrefix(u.Path, "/private")
+	}), robots.Decision{Allowed: false, Reason: robots.DisallowedByRobots}, nil)
+	mockRobot.OnDecide(mock.Anything, robots.Decision{Allowed: true, Reason: robots.EmptyRuleSet}, nil)
+
+	mockConvert := newConvertMockForTest(t)
+	setupConvertMockWithSuccess(mockConvert)
+	mockResolver := newResolverMockForTest(t)
+	setupResolverMockWithSuccess(mockResolver)
+	mockNormalize := newNormalizeMockForTest(t)
+	setupNormalizeMockWithSuccess(mockNormalize)
+
+	noopSink := &metadata.NoopSink{}
+	realFrontier := frontier.NewCrawlFrontier()
+	ext := extractor.NewDomExtractor(noopSink)
+	san := sanitizer.NewHTMLSanitizer(noopSink)
+	s := scheduler.NewSchedulerWithDeps(
+		context.Background(),
+		newMockFinalizer(t),
+		noopSink,
+		newRateLimiterMockForTest(t),
+		&realFrontier,
+		mockFetcher,
+		mockRobot,
+		&ext,
+		&san,
+		mockConvert,
+		mockResolver,
+		mockNormalize,
+		storage.NewMemoryWriter(),
+		newFailureJournalMockForTest(t),
+		stagedump.NewNoOpDumper(),
+		debug.NewNoOpLogger(),
+	)
+
+	init, err := s.InitializeWithConfig(paginationConfig(t, seed.String(), false))
+	assert.NoError(t, err)
+
+	// WHEN seeding the frontier before execution, deepest first
+	seedErr := s.SeedFrontier([]frontier.CrawlAdmissionCandidate{
+		frontier.NewCrawlAdmissionCandidate(deep, frontier.SourceCrawl, frontier.NewDiscoveryMetadata(2, nil)),
+		frontier.NewCrawlAdmissionCandidate(private, frontier.SourceCrawl, frontier.NewDiscoveryMetadata(1, nil)),
+		frontier.NewCrawlAdmissionCandidate(shallow, frontier.SourceCrawl, frontier.NewDiscoveryMetadata(1, nil)),
+	})
+	assert.Nil(t, seedErr)
+
+	_, err = s.ExecuteCrawlingWithState(init)
+	assert.NoError(t, err)
+
+	// THEN pages are fetched in BFS order and the disallowed candidate is never fetched
+	assert.Equal(t, []string{
+		seed.String(),
+		shallow.String(),
+		deep.String(),
+	}, fetched)
+	assert.Equal(t, 3, realFrontier.VisitedCount())
+}
+
+func TestScheduler_SeedFrontier_ReturnsRobotsError(t *testing.T) {
+	// GIVEN robots.txt infrastructure fails for one candidate
+	robotsErr := robots.NewRobotsError(robots.ErrCauseHttpServerError, "http error: 500")
+	failing := *mustParseURL("https://example.com/broken")
+	allowed := *mustParseURL("https://example.com/ok")
+
+	mockRobot := NewRobotsMockForTest(t)
+	mockRobot.OnDecide(failing, robots.Decision{}, robotsErr)
+	mockRobot.OnDecide(allowed, robots.Decision{Url: allowed, Allowed: true, Reason: robots.EmptyRuleSet}, nil)
+
+	mockFrontier := newFrontierMockForTest(t)
+	mockLimiter := newRateLimiterMockForTest(t)
+	s := createSchedulerForTest(
+		t,
+		context.Background(),
+		newMockFinalizer(t),
+		&metadata.NoopSink{},
+		mockLimiter,
+		mockFrontier,
+		mockRobot,
+		newFetcherMockForTest(t),
+		nil,
+		nil,
+		nil,
+		nil,
+		newStorageMockForTest(t),
+		newFailureJournalMockForTest(t),
+	)
+	s.SetCurrentHost("example.com")
+
+	// WHEN seeding both candidates
+	err := s.SeedFrontier([]frontier.CrawlAdmissionCandidate{
+		frontier.NewCrawlAdmissionCandidate(failing, frontier.SourceSeed, frontier.NewDiscoveryMetadata(0, nil)),
+		frontier.NewCrawlAdmissionCandidate(allowed, frontier.SourceSeed, frontier.NewDiscoveryMetadata(0, nil)),
+	})
+
+	// THEN the robots error is returned and the remaining candidate is still submitted
+	assert.Equal(t, robotsErr, err)
+	if assert.Len(t, mockFrontier.submittedCandidates, 1) {
+		target := mockFrontier.submittedCandidates[0].TargetURL()
+		assert.Equal(t, allowed.String(), target.String())
+	}
+}
+
+func TestScheduler_SeedFrontier_KeepsPriority(t *testing.T) {
+	// GIVEN a candidate seeded with a tie-break priority
+	target := *mustParseURL("https://example.com/important")
+
+	mockRobot := NewRobotsMockForTest(t)
+	mockRobot.OnDecide(mock.Anything, robots.Decision{Allowed: true, Reason: robots.EmptyRuleSet}, nil)
+
+	mockFrontier := newFrontierMockForTest(t)
+	s := createSchedulerForTest(
+		t,
+		context.Background(),
+		newMockFinalizer(t),
+		&metadata.NoopSink{},
+		newRateLimiterMockForTest(t),
+		mockFrontier,
+		mockRobot,
+		newFetcherMockForTest(t),
+		nil,
+		nil,
+		nil,
+		nil,
+		newStorageMockForTest(t),
+		newFailureJournalMockForTest(t),
+	)
+	s.SetCurrentHost("example.com")
+
+	// WHEN seeding it
+	err := s.SeedFrontier([]frontier.CrawlAdmissionCandidate{
+		frontier.NewCrawlAdmissionCandidate(target, frontier.SourceSeed, frontier.NewDiscoveryMetadata(1, nil).WithPriority(3)),
+	})
+
+	// THEN the frontier receives it with its depth and priority
+	assert.Nil(t, err)
+	if assert.Len(t, mockFrontier.submittedCandidates, 1) {
+		discovery := mockFrontier.submittedCandidates[0].DiscoveryMetadata()
+		assert.Equal(t, 1, discovery.Depth())
+		assert.Equal(t, 3, discovery.Priority())
+	}
+}
+
+// writeChainedSiteArchiveForTest archives a chain of pages under /docs, each
+// linking to the next, and returns the archive directory.
+func writeChainedSiteArchiveForTest(t *testing.T, paths ...string) string {
+	t.Helper()
+	archiveDir := t.TempDir()
+	for i, path := range paths {
+		next := ""
+		if i+1 < len(paths) {
+			next = fmt.Sprintf(`<p><a href="%s">Next</a></p>`, paths[i+1])
+		}
+		require.NoError(t, fetcher.WriteArchiveEntry(archiveDir, fetcher.ArchiveEntry{
+			URL:     "https://docs.example.com" + path,
+			Status:  http.StatusOK,
+			Headers: http.Header{"Content-Type": {"text/html"}},
+			Body: []byte(fmt.Sprintf(`<html><body><main><h1>Page %d</h1>
+<p>This page has enough text to pass content extraction and be written.</p>
+%s</main></body></html>`, i+1, next)),
+		}))
+	}
+	return archiveDir
+}
+
+// fetchDepthsForTest returns the crawl depth of every fetched page by URL.
+func fetchDepthsForTest(sink *metadatatest.SinkMock) map[string]int {
+	depths := make(map[string]int)
+	for _, event := range sink.FetchEvents {
+		if event.Kind() == metadata.KindPage {
+			depths[event.FetchURL()] = event.CrawlDepth()
+		}
+	}
+	return depths
+}
+
+func TestScheduler_SeedDepths_MeasuresMaxDepthFromSeedDepth(t *testing.T) {
+	archiveDir := writeChainedSiteArchiveForTest(t, "/docs/start", "/docs/next", "/docs/last")
+	seed := *mustParseURL("https://docs.example.com/docs/start")
+	cfg, err := config.WithDefault([]url.URL{seed}).
+		WithOutputDir(t.TempDir()).
+		WithReplayArchive(archiveDir).
+		WithMaxDepth(3).
+		WithSeedDepths(map[string]int{seed.String(): 2}).
+		Build()
+	require.NoError(t, err)
+
+	sink := &metadatatest.SinkMock{}
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	crawlPipelineForTest(t, &archiveFetcher, sink, cfg)
+
+	// The seed's link goes in at depth 3; its own link, at 4, is rejected
+	assert.Equal(t, map[string]int{
+		"https://docs.example.com/docs/start": 2,
+		"https://docs.example.com/docs/next":  3,
+	}, fetchDepthsForTest(sink))
+}
+
+func TestScheduler_SeedFrontier_SeedAtDepth(t *testing.T) {
+	// The config seed links nowhere; the chain is only reached through the
+	// seed injected at depth 2
+	archiveDir := writeChainedSiteArchiveForTest(t, "/docs/start", "/docs/next", "/docs/last")
+	require.NoError(t, fetcher.WriteArchiveEntry(archiveDir, fetcher.ArchiveEntry{
+		URL:     "https://docs.example.com/docs",
+		Status:  http.StatusOK,
+		Headers: http.Header{"Content-Type": {"text/html"}},
+		Body:    []byte(seededPageHTML),
+	}))
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir(t.TempDir()).
+		WithReplayArchive(archiveDir).
+		WithMaxDepth(3).
+		Build()
+	require.NoError(t, err)
+
+	sink := &metadatatest.SinkMock{}
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	s := newPipelineSchedulerForTest(t, &archiveFetcher, sink, storage.NewMemoryWriter())
+	init, err := s.InitializeWithConfig(cfg)
+	require.NoError(t, err)
+	seedErr := s.SeedFrontier([]frontier.CrawlAdmissionCandidate{
+		frontier.NewCrawlAdmissionCandidate(
+			*mustParseURL("https://docs.example.com/docs/start"),
+			frontier.SourceSeed,
+			frontier.NewDiscoveryMetadata(2, nil),
+		),
+	})
+	assert.Nil(t, seedErr)
+	_, err = s.ExecuteCrawlingWithState(init)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int{
+		"https://docs.example.com/docs":       0,
+		"https://docs.example.com/docs/start": 2,
+		"https://docs.example.com/docs/next":  3,
+	}, fetchDepthsForTest(sink))
+}
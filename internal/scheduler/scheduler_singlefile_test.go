@@ -0,0 +1,87 @@
+package scheduler_test
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// crawlArchiveSiteForTest crawls archiveSitePages from an archive with
+// apply adding to its config, and returns the combined file and the
+// per-page files written.
+func crawlArchiveSiteForTest(t *testing.T, outputDir string, apply func(*config.Config) *config.Config) (string, []string) {
+	t.Helper()
+	archiveDir := t.TempDir()
+	for path, page := range archiveSitePages {
+		require.NoError(t, fetcher.WriteArchiveEntry(archiveDir, fetcher.ArchiveEntry{
+			URL:     "https://docs.example.com" + path,
+			Status:  http.StatusOK,
+			Headers: http.Header{"Content-Type": {"text/html"}},
+			Body:    []byte(page),
+		}))
+	}
+
+	cfg, err := apply(config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir(outputDir).
+		WithReplayArchive(archiveDir).
+		WithSingleFileOutput("combined.md")).
+		Build()
+	require.NoError(t, err)
+
+	sink := &metadatatest.SinkMock{}
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	_, writer := crawlPipelineForTest(t, &archiveFetcher, sink, cfg)
+
+	combined, err := os.ReadFile(filepath.Join(outputDir, "combined.md"))
+	require.NoError(t, err)
+	var pages []string
+	for _, path := range writer.Paths() {
+		if strings.HasSuffix(path, ".md") {
+			pages = append(pages, path)
+		}
+	}
+	return string(combined), pages
+}
+
+func TestScheduler_SingleFileOutput_CombinesPagesInWriteOrder(t *testing.T) {
+	outputDir := t.TempDir()
+	keepPages := func(c *config.Config) *config.Config { return c }
+
+	combined, pages := crawlArchiveSiteForTest(t, outputDir, keepPages)
+
+	// Both pages are in the combined file, the seed first, separated once
+	sections := strings.Split(combined, "\n---\n\n")
+	require.Len(t, sections, 2, "expected one separator between two pages:\n%s", combined)
+	assert.Contains(t, sections[0], "> Source: <https://docs.example.com/docs>")
+	assert.Contains(t, sections[0], "# Documentation")
+	assert.Contains(t, sections[1], "> Source: <https://docs.example.com/docs/install>")
+	assert.Contains(t, sections[1], "# Installation")
+	for _, section := range sections {
+		assert.True(t, strings.HasPrefix(section, `<a id="page-`), "expected each section to start with its anchor:\n%s", section)
+	}
+
+	// The per-page files are written too
+	assert.Len(t, pages, 2)
+
+	// A second crawl rewrites the same file byte for byte
+	again, _ := crawlArchiveSiteForTest(t, outputDir, keepPages)
+	assert.Equal(t, combined, again)
+}
+
+func TestScheduler_SingleFileOnly_SkipsPerPageFiles(t *testing.T) {
+	combinedOnly := func(c *config.Config) *config.Config { return c.WithSingleFileOnly(true) }
+
+	combined, pages := crawlArchiveSiteForTest(t, t.TempDir(), combinedOnly)
+
+	assert.Equal(t, 1, strings.Count(combined, "\n---\n\n"))
+	assert.Empty(t, pages, "expected no per-page files when the combined file replaces them")
+}
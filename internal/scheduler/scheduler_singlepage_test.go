@@ -0,0 +1,88 @@
+package scheduler_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// manyLinksHTML returns a page titled title linking to count other pages,
+// with a rel="next" pagination link in its head.
+func manyLinksHTML(title string, count int) string {
+	var links strings.Builder
+	for i := 0; i < count; i++ {
+		fmt.Fprintf(&links, "<li><a href=\"/docs/page-%d\">Page %d</a></li>\n", i, i)
+	}
+	return `<!DOCTYPE html>
+<html>
+<head><link rel="next" href="/docs/page-0"></head>
+<body><main>
+<h1>` + title + `</h1>
+<p>This page links to many other pages, with enough text to pass content extraction.</p>
+<ul>
+` + links.String() + `</ul>
+</main></body>
+</html>`
+}
+
+func TestScheduler_SinglePage_SubmitsNoDiscoveredLinks(t *testing.T) {
+	seedURL := mustParseURL("https://docs.example.com/docs")
+	cfg, err := config.WithDefault([]url.URL{*seedURL}).
+		WithOutputDir(t.TempDir()).
+		WithFollowPagination(true).
+		WithSinglePage(true).
+		Build()
+	require.NoError(t, err)
+
+	candidates := crawlSinglePageCandidatesForTest(t, cfg, manyLinksHTML("Documentation", 50), nil)
+
+	assert.Empty(t, candidates, "single-page mode must not submit discovered or paginated links")
+}
+
+func TestScheduler_SinglePage_WritesOnlySeeds(t *testing.T) {
+	archiveDir := t.TempDir()
+	pages := map[string]string{
+		"/docs": manyLinksHTML("Documentation", 20),
+	}
+	for i := 0; i < 20; i++ {
+		pages[fmt.Sprintf("/docs/page-%d", i)] = manyLinksHTML(fmt.Sprintf("Page %d", i), 0)
+	}
+	for path, page := range pages {
+		require.NoError(t, fetcher.WriteArchiveEntry(archiveDir, fetcher.ArchiveEntry{
+			URL:     "https://docs.example.com" + path,
+			Status:  http.StatusOK,
+			Headers: http.Header{"Content-Type": {"text/html"}},
+			Body:    []byte(page),
+		}))
+	}
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir("out").
+		WithReplayArchive(archiveDir).
+		WithSinglePage(true).
+		Build()
+	require.NoError(t, err)
+
+	sink := &metadatatest.SinkMock{}
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	fetched, writer := crawlPipelineForTest(t, &archiveFetcher, sink, cfg)
+
+	assert.Equal(t, []string{"https://docs.example.com/docs"}, fetched)
+
+	var written []string
+	for _, path := range writer.Paths() {
+		if base := filepath.Base(path); base != storage.ManifestFileName && base != storage.ConfigSnapshotFileName {
+			written = append(written, path)
+		}
+	}
+	assert.Len(t, written, 1, "expected only the seed page to be written")
+}
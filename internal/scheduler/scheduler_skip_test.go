@@ -0,0 +1,86 @@
+package scheduler_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/frontier"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/rohmanhakim/docs-crawler/internal/robots"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// skipAttr returns the value of key in the skip event's attributes, or "".
+func skipAttr(event metadata.SkipEvent, key metadata.AttributeKey) string {
+	for _, attr := range event.Attrs() {
+		if attr.Key() == key {
+			return attr.Value()
+		}
+	}
+	return ""
+}
+
+func TestScheduler_Skip_RobotsDisallowRecordsSkip(t *testing.T) {
+	mockRobot := NewRobotsMockForTest(t)
+	mockRobot.OnDecide(mock.Anything, robots.Decision{
+		Allowed: false,
+		Reason:  robots.DisallowedByRobots,
+	}, nil)
+
+	sink := &metadatatest.SinkMock{}
+	s := createSchedulerForTest(
+		t,
+		context.Background(),
+		newMockFinalizer(t),
+		sink,
+		newRateLimiterMockForTest(t),
+		newFrontierMockForTest(t),
+		mockRobot,
+		newFetcherMockForTest(t),
+		nil,
+		nil,
+		nil,
+		nil,
+		newStorageMockForTest(t),
+		newFailureJournalMockForTest(t),
+	)
+
+	testURL, _ := url.Parse("https://example.com/private/page.html")
+	s.SetCurrentHost(testURL.Host)
+
+	err := s.SubmitUrlForAdmission(*testURL, frontier.SourceCrawl, 2)
+
+	assert.Nil(t, err)
+	assert.False(t, sink.RecordErrorCalled, "robots disallow must not be recorded as an error")
+	if assert.Len(t, sink.SkipEvents, 1) {
+		skip := sink.SkipEvents[0]
+		assert.Equal(t, metadata.SkipReasonRobotsDisallow, skip.Reason())
+		assert.Equal(t, "https://example.com/private/page.html", skip.SkippedURL())
+		assert.Equal(t, "example.com", skipAttr(skip, metadata.AttrHost))
+		assert.Equal(t, "2", skipAttr(skip, metadata.AttrDepth))
+	}
+}
+
+func TestScheduler_Skip_OutOfScopeRecordsSkip(t *testing.T) {
+	sink := &metadatatest.SinkMock{}
+
+	submitted := crawlSinglePageWithSinkForTest(t, hostScopeConfig(t, "*.example.com"), hostScopeHTML, nil, sink)
+
+	assert.Len(t, submitted, 2)
+	assert.False(t, sink.RecordErrorCalled, "out-of-scope links must not be recorded as errors")
+
+	var skipped []string
+	for _, skip := range sink.SkipEvents {
+		assert.Equal(t, metadata.SkipReasonOutOfScope, skip.Reason())
+		assert.Equal(t, "1", skipAttr(skip, metadata.AttrDepth))
+		skipped = append(skipped, skip.SkippedURL())
+	}
+	assert.ElementsMatch(t, []string{
+		"https://example.com/guide",
+		"https://evil-example.com/guide",
+		"https://a.b.example.com/guide",
+	}, skipped)
+}
@@ -0,0 +1,99 @@
+package scheduler_test
+
+import (
+	"encoding/json"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/rohmanhakim/docs-crawler/internal/scheduler"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func snapshotConfigForTest(t *testing.T, archiveDir string, dryRun bool) config.Config {
+	t.Helper()
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir("out").
+		WithReplayArchive(archiveDir).
+		WithMaxDepth(1).
+		WithDryRun(dryRun).
+		Build()
+	require.NoError(t, err)
+	return cfg
+}
+
+func TestScheduler_ConfigSnapshot_WrittenWithRunID(t *testing.T) {
+	archiveDir := writeLinkedSiteArchiveForTest(t, 3)
+	cfg := snapshotConfigForTest(t, archiveDir, false)
+
+	sink := &metadatatest.SinkMock{}
+	writer := storage.NewMemoryWriter()
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	_, err := runPipelineForTest(t, &archiveFetcher, sink, writer, cfg)
+	require.NoError(t, err)
+
+	snapshot, ok := writer.Get(filepath.Join("out", storage.ConfigSnapshotFileName))
+	require.True(t, ok, "expected the effective config to be written")
+
+	var fields struct {
+		RunID         string `json:"runId"`
+		MaxDepth      int    `json:"maxDepth"`
+		ReplayArchive string `json:"replayArchive"`
+		RandomSeed    int64  `json:"randomSeed"`
+	}
+	require.NoError(t, json.Unmarshal(snapshot, &fields))
+	assert.Equal(t, scheduler.RunID(cfg), fields.RunID)
+	assert.Equal(t, 1, fields.MaxDepth)
+	assert.Equal(t, archiveDir, fields.ReplayArchive)
+	assert.Equal(t, cfg.RandomSeed(), fields.RandomSeed)
+
+	expected, err := config.EncodeSnapshot(cfg, scheduler.RunID(cfg))
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(snapshot))
+}
+
+func TestScheduler_ConfigSnapshot_NotWrittenOnDryRun(t *testing.T) {
+	archiveDir := writeLinkedSiteArchiveForTest(t, 3)
+	cfg := snapshotConfigForTest(t, archiveDir, true)
+
+	sink := &metadatatest.SinkMock{}
+	writer := storage.NewMemoryWriter()
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	_, err := runPipelineForTest(t, &archiveFetcher, sink, writer, cfg)
+	require.NoError(t, err)
+
+	_, ok := writer.Get(filepath.Join("out", storage.ConfigSnapshotFileName))
+	assert.False(t, ok, "expected no effective config on a dry run")
+}
+
+func TestScheduler_ConfigSnapshot_RedactsHeaderValues(t *testing.T) {
+	archiveDir := writeLinkedSiteArchiveForTest(t, 3)
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir("out").
+		WithReplayArchive(archiveDir).
+		WithMaxDepth(1).
+		WithDefaultHeaders(map[string]string{"Authorization": "Bearer s3cr3t-token"}).
+		WithHeaderRules([]config.HeaderRule{
+			config.NewHeaderRule(`^https://docs\.example\.com/`, map[string]string{"X-Api-Key": "k3y-value"}),
+		}).
+		Build()
+	require.NoError(t, err)
+
+	sink := &metadatatest.SinkMock{}
+	writer := storage.NewMemoryWriter()
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	_, err = runPipelineForTest(t, &archiveFetcher, sink, writer, cfg)
+	require.NoError(t, err)
+
+	snapshot, ok := writer.Get(filepath.Join("out", storage.ConfigSnapshotFileName))
+	require.True(t, ok, "expected the effective config to be written")
+	assert.NotContains(t, string(snapshot), "s3cr3t-token")
+	assert.NotContains(t, string(snapshot), "k3y-value")
+	assert.Contains(t, string(snapshot), `"Authorization": "<redacted>"`)
+	assert.Contains(t, string(snapshot), `"X-Api-Key": "<redacted>"`)
+}
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/rohmanhakim/docs-crawler/internal/config"
 	"github.com/rohmanhakim/docs-crawler/internal/extractor"
 	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
 	"github.com/rohmanhakim/docs-crawler/internal/frontier"
@@ -1067,3 +1068,51 @@ func TestScheduler_Write_MultiplePages_MultipleWriteResults(t *testing.T) {
 	assert.Equal(t, writeResult1.URLHash(), writeResults[0].URLHash())
 	assert.Equal(t, writeResult2.URLHash(), writeResults[1].URLHash())
 }
+
+// TestScheduler_Storage_SetWriterUsesMemoryWriter verifies that any
+// storage.Writer can replace the configured sink.
+func TestScheduler_Storage_SetWriterUsesMemoryWriter(t *testing.T) {
+	mockRobot := NewRobotsMockForTest(t)
+	mockRobot.On("Init", mock.Anything, mock.Anything).Return()
+	mockRobot.OnDecide(mock.Anything, robots.Decision{Allowed: true, Reason: robots.EmptyRuleSet}, nil)
+	mockStorage := newStorageMockForTest(t)
+
+	s := createSchedulerForTest(
+		t,
+		context.Background(),
+		newMockFinalizer(t),
+		&metadata.NoopSink{},
+		newRateLimiterMockForTest(t),
+		newFrontierMockForTest(t),
+		mockRobot,
+		newFetcherMockForTest(t),
+		nil,
+		nil,
+		nil,
+		nil,
+		mockStorage,
+		newFailureJournalMockForTest(t),
+	)
+	memory := storage.NewMemoryWriter()
+	s.SetWriter(memory)
+
+	seedURL, _ := url.Parse("https://example.com/docs")
+	cfg, err := config.WithDefault([]url.URL{*seedURL}).WithOutputDir("out").Build()
+	assert.NoError(t, err)
+
+	init, err := s.InitializeWithConfig(cfg)
+	assert.NoError(t, err)
+	result, err := s.ExecuteCrawlingWithState(init)
+	assert.NoError(t, err)
+
+	mockStorage.AssertNotCalled(t, "Write", mock.Anything, mock.Anything, mock.Anything)
+	writeResults := result.WriteResults()
+	assert.Len(t, writeResults, 1)
+	assert.ElementsMatch(t, []string{writeResults[0].Path(), "out/manifest.json", "out/effective-config.json"}, memory.Paths())
+	content, ok := memory.Get(writeResults[0].Path())
+	assert.True(t, ok)
+	assert.NotEmpty(t, content)
+	manifest, ok := memory.Get("out/manifest.json")
+	assert.True(t, ok)
+	assert.Contains(t, string(manifest), `"url": "http://example.com/test"`)
+}
@@ -0,0 +1,84 @@
+package scheduler_test
+
+import (
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/rohmanhakim/docs-crawler/internal/scheduler"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSiteWithInvalidPageArchiveForTest archives a linked site of three
+// pages whose first linked page has two titles, which fails normalization.
+func writeSiteWithInvalidPageArchiveForTest(t *testing.T) string {
+	t.Helper()
+	archiveDir := writeLinkedSiteArchiveForTest(t, 3)
+	require.NoError(t, fetcher.WriteArchiveEntry(archiveDir, fetcher.ArchiveEntry{
+		URL:     "https://docs.example.com/docs/page-1",
+		Status:  http.StatusOK,
+		Headers: http.Header{"Content-Type": {"text/html"}},
+		Body: []byte(`<html><body><main><h1>Page 1</h1>
+<p>This page has enough text to pass content extraction, but two titles.</p>
+<h1>Page 1 again</h1><p>More text.</p></main></body></html>`),
+	}))
+	return archiveDir
+}
+
+// writtenPagePaths returns the Markdown pages in writer.
+func writtenPagePaths(writer *storage.MemoryWriter) []string {
+	var pages []string
+	for _, path := range writer.Paths() {
+		if filepath.Ext(path) == ".md" {
+			pages = append(pages, path)
+		}
+	}
+	return pages
+}
+
+func TestScheduler_StrictMode_AbortsOnFirstPipelineError(t *testing.T) {
+	archiveDir := writeSiteWithInvalidPageArchiveForTest(t)
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir(t.TempDir()).
+		WithReplayArchive(archiveDir).
+		WithStrictMode(true).
+		Build()
+	require.NoError(t, err)
+
+	sink := &metadatatest.SinkMock{}
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	writer := storage.NewMemoryWriter()
+	_, err = runPipelineForTest(t, &archiveFetcher, sink, writer, cfg)
+
+	assert.ErrorIs(t, err, scheduler.ErrStrictMode)
+	// The failure is still recorded, and nothing after it is crawled
+	require.NotEmpty(t, sink.ErrorRecords)
+	assert.Len(t, writtenPagePaths(writer), 1)
+	for _, event := range sink.FetchEvents {
+		assert.NotEqual(t, "https://docs.example.com/docs/page-2", event.FetchURL())
+	}
+}
+
+func TestScheduler_StrictMode_DisabledSkipsFailingPage(t *testing.T) {
+	archiveDir := writeSiteWithInvalidPageArchiveForTest(t)
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir(t.TempDir()).
+		WithReplayArchive(archiveDir).
+		Build()
+	require.NoError(t, err)
+
+	sink := &metadatatest.SinkMock{}
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	writer := storage.NewMemoryWriter()
+	execution, err := runPipelineForTest(t, &archiveFetcher, sink, writer, cfg)
+
+	require.NoError(t, err)
+	assert.Len(t, writtenPagePaths(writer), 2)
+	assert.Equal(t, 1, execution.TotalErrors())
+}
@@ -0,0 +1,130 @@
+package scheduler_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/extractor"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/frontier"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/internal/normalize"
+	"github.com/rohmanhakim/docs-crawler/internal/robots"
+	"github.com/rohmanhakim/docs-crawler/internal/sanitizer"
+	"github.com/rohmanhakim/docs-crawler/internal/scheduler"
+	"github.com/rohmanhakim/docs-crawler/internal/stagedump"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/rohmanhakim/docs-crawler/pkg/debug"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const apiReferenceHTML = `<!DOCTYPE html>
+<html>
+<head><title>Queue API</title></head>
+<body>
+<main>
+<h1>Queue API</h1>
+<p>The queue client pushes and pops jobs in order of submission.</p>
+<h2>Push</h2>
+<pre><code>func (q *Queue) Push(job Job) error</code></pre>
+<p><strong>Parameters</strong></p>
+<ul><li><code>job</code> – the job to enqueue</li></ul>
+<p><strong>Returns:</strong> an error if the queue is closed.</p>
+<h2>Pop</h2>
+<pre><code>func (q *Queue) Pop(ctx context.Context) (Job, error)</code></pre>
+<p><strong>Parameters</strong></p>
+<ul><li><code>ctx</code> – cancels the wait for a job</li></ul>
+<p><strong>Returns:</strong> the oldest job.</p>
+</main>
+</body>
+</html>`
+
+// runStructuredCrawlForTest crawls one API reference page and returns the
+// param the scheduler passed to normalize.
+func runStructuredCrawlForTest(t *testing.T, structured bool) normalize.NormalizeParam {
+	t.Helper()
+	seedURL := *mustParseURL("https://docs.example.com/api/queue")
+
+	mockRobot := NewRobotsMockForTest(t)
+	mockRobot.On("Init", mock.Anything, mock.Anything).Return()
+	mockRobot.OnDecide(mock.Anything, robots.Decision{Allowed: true, Reason: robots.EmptyRuleSet}, nil)
+	mockFetcher := new(fetcherMock)
+	mockFetcher.On("Init", mock.Anything, mock.Anything).Return()
+	mockFetcher.On("Fetch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
+		fetcher.NewFetchResultForTest(
+			seedURL,
+			[]byte(apiReferenceHTML),
+			200,
+			"text/html",
+			map[string]string{"Content-Type": "text/html"},
+			time.Now(),
+		), nil)
+
+	mockConvert := newConvertMockForTest(t)
+	setupConvertMockWithSuccess(mockConvert)
+	mockResolver := newResolverMockForTest(t)
+	setupResolverMockWithSuccess(mockResolver)
+	mockNormalize := newNormalizeMockForTest(t)
+	setupNormalizeMockWithSuccess(mockNormalize)
+	var param normalize.NormalizeParam
+	for _, call := range mockNormalize.ExpectedCalls {
+		call.Run(func(args mock.Arguments) {
+			param = args.Get(2).(normalize.NormalizeParam)
+		})
+	}
+
+	noopSink := &metadata.NoopSink{}
+	realFrontier := frontier.NewCrawlFrontier()
+	ext := extractor.NewDomExtractor(noopSink)
+	san := sanitizer.NewHTMLSanitizer(noopSink)
+	s := scheduler.NewSchedulerWithDeps(
+		context.Background(),
+		newMockFinalizer(t),
+		noopSink,
+		newRateLimiterMockForTest(t),
+		&realFrontier,
+		mockFetcher,
+		mockRobot,
+		&ext,
+		&san,
+		mockConvert,
+		mockResolver,
+		mockNormalize,
+		storage.NewMemoryWriter(),
+		newFailureJournalMockForTest(t),
+		stagedump.NewNoOpDumper(),
+		debug.NewNoOpLogger(),
+	)
+
+	cfg, err := config.WithDefault([]url.URL{seedURL}).
+		WithStructuredExtraction(structured).
+		Build()
+	assert.NoError(t, err)
+
+	init, err := s.InitializeWithConfig(cfg)
+	assert.NoError(t, err)
+	_, err = s.ExecuteCrawlingWithState(init)
+	assert.NoError(t, err)
+	return param
+}
+
+func TestScheduler_StructuredExtraction_SectionsPassedToNormalize(t *testing.T) {
+	param := runStructuredCrawlForTest(t, true)
+
+	sections := param.StructuredSections()
+	if assert.Len(t, sections, 2) {
+		assert.Equal(t, "Push", sections[0].Heading)
+		assert.Equal(t, "Pop", sections[1].Heading)
+		assert.Len(t, sections[0].Fields, 3)
+	}
+}
+
+func TestScheduler_StructuredExtraction_DisabledByDefault(t *testing.T) {
+	param := runStructuredCrawlForTest(t, false)
+
+	assert.Empty(t, param.StructuredSections())
+}
@@ -0,0 +1,51 @@
+package scheduler_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const subdomainLinkPageHTML = `<!DOCTYPE html>
+<html>
+<body><main>
+<h1>Example</h1>
+<p>Welcome to the product site, with enough text to pass content extraction.</p>
+<p>Read the <a href="https://docs.example.com/guide">documentation</a>.</p>
+<p>Or the <a href="/about">about page</a>.</p>
+</main></body>
+</html>`
+
+func TestScheduler_IncludeSubdomains(t *testing.T) {
+	tests := []struct {
+		name              string
+		includeSubdomains bool
+		want              []string
+	}{
+		{
+			name:              "on admits subdomains of the seed host",
+			includeSubdomains: true,
+			want:              []string{"https://docs.example.com/guide", "https://example.com/about"},
+		},
+		{
+			name:              "off restricts to the exact seed host",
+			includeSubdomains: false,
+			want:              []string{"https://example.com/about"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://example.com/")}).
+				WithOutputDir(t.TempDir()).
+				WithIncludeSubdomains(tt.includeSubdomains).
+				Build()
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, crawlSinglePageForTest(t, cfg, subdomainLinkPageHTML))
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package scheduler_test
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/extractor"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/frontier"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/internal/robots"
+	"github.com/rohmanhakim/docs-crawler/internal/sanitizer"
+	"github.com/rohmanhakim/docs-crawler/internal/scheduler"
+	"github.com/rohmanhakim/docs-crawler/internal/stagedump"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/rohmanhakim/docs-crawler/pkg/debug"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// tieBreakSite maps page paths to the paths they link to. Several pages at
+// the same depth link to each other, so BFS order depends on tie-breaking.
+var tieBreakSite = map[string][]string{
+	"/":              {"/guide", "/api", "/faq"},
+	"/guide":         {"/guide/install", "/api", "/guide/usage"},
+	"/api":           {"/api/client", "/guide/install", "/api/server"},
+	"/faq":           {"/guide/usage", "/faq/billing"},
+	"/guide/install": {"/"},
+	"/guide/usage":   {"/api/client"},
+	"/api/client":    {},
+	"/api/server":    {"/faq"},
+	"/faq/billing":   {},
+}
+
+func tieBreakPageHTML(path string, site map[string][]string) string {
+	links := ""
+	for _, link := range site[path] {
+		links += fmt.Sprintf(`<li><a href="%s">%s</a></li>`, link, link)
+	}
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head><title>%[1]s</title></head>
+<body>
+<main>
+<h1>Page %[1]s</h1>
+<p>This page documents %[1]s in enough detail to pass content extraction,
+and links to related pages of the site.</p>
+<ul>%[2]s</ul>
+</main>
+</body>
+</html>`, path, links)
+}
+
+// crawlTieBreakSiteForTest crawls tieBreakSite from its root and returns the
+// dequeued (fetched) URLs and the URLs that reached the write stages, in order.
+func crawlTieBreakSiteForTest(t *testing.T) (fetched []string, written []string) {
+	t.Helper()
+	return crawlSiteForTest(t, tieBreakSite, paginationConfig(t, "https://example.com/", false))
+}
+
+// crawlSiteForTest crawls site on https://example.com with cfg, whose seed
+// must be the site root, and returns the fetched and written URLs in order.
+func crawlSiteForTest(t *testing.T, site map[string][]string, cfg config.Config) (fetched []string, written []string) {
+	t.Helper()
+	mockFetcher := new(fetcherMock)
+	mockFetcher.On("Init", mock.Anything, mock.Anything).Return()
+	for path := range site {
+		target := *mustParseURL("https://example.com" + path)
+		mockFetcher.On("Fetch", mock.Anything, mock.Anything, target, mock.Anything).
+			Run(func(args mock.Arguments) {
+				fetched = append(fetched, target.String())
+			}).
+			Return(fetcher.NewFetchResultForTest(
+				target,
+				[]byte(tieBreakPageHTML(path, site)),
+				200,
+				"text/html",
+				map[string]string{"Content-Type": "text/html"},
+				time.Now(),
+			), nil)
+	}
+
+	mockRobot := NewRobotsMockForTest(t)
+	mockRobot.On("Init", mock.Anything, mock.Anything).Return()
+	mockRobot.OnDecide(mock.Anything, robots.Decision{Allowed: true, Reason: robots.EmptyRuleSet}, nil)
+
+	mockConvert := newConvertMockForTest(t)
+	setupConvertMockWithSuccess(mockConvert)
+	mockResolver := newResolverMockForTest(t)
+	setupResolverMockWithSuccess(mockResolver)
+	mockNormalize := newNormalizeMockForTest(t)
+	setupNormalizeMockWithSuccess(mockNormalize)
+	for _, call := range mockNormalize.ExpectedCalls {
+		call.Run(func(args mock.Arguments) {
+			u := args.Get(0).(url.URL)
+			written = append(written, u.String())
+		})
+	}
+
+	noopSink := &metadata.NoopSink{}
+	realFrontier := frontier.NewCrawlFrontier()
+	ext := extractor.NewDomExtractor(noopSink)
+	san := sanitizer.NewHTMLSanitizer(noopSink)
+	s := scheduler.NewSchedulerWithDeps(
+		context.Background(),
+		newMockFinalizer(t),
+		noopSink,
+		newRateLimiterMockForTest(t),
+		&realFrontier,
+		mockFetcher,
+		mockRobot,
+		&ext,
+		&san,
+		mockConvert,
+		mockResolver,
+		mockNormalize,
+		storage.NewMemoryWriter(),
+		newFailureJournalMockForTest(t),
+		stagedump.NewNoOpDumper(),
+		debug.NewNoOpLogger(),
+	)
+
+	init, err := s.InitializeWithConfig(cfg)
+	assert.NoError(t, err)
+	_, err = s.ExecuteCrawlingWithState(init)
+	assert.NoError(t, err)
+	return fetched, written
+}
+
+func TestScheduler_TieBreak_IdenticalCrawlsDequeueIdentically(t *testing.T) {
+	// GIVEN the same site crawled twice with identical inputs
+	firstFetched, firstWritten := crawlTieBreakSiteForTest(t)
+	secondFetched, secondWritten := crawlTieBreakSiteForTest(t)
+
+	// THEN both runs dequeue and write in exactly the same order
+	assert.Equal(t, firstFetched, secondFetched)
+	assert.Equal(t, firstWritten, secondWritten)
+
+	// AND that order is BFS with ties broken by submission order
+	expected := []string{
+		"https://example.com/",
+		"https://example.com/guide",
+		"https://example.com/api",
+		"https://example.com/faq",
+		"https://example.com/guide/install",
+		"https://example.com/guide/usage",
+		"https://example.com/api/client",
+		"https://example.com/api/server",
+		"https://example.com/faq/billing",
+	}
+	assert.Equal(t, expected, firstFetched)
+	assert.Equal(t, expected, firstWritten)
+}
+
+// reorderedTieBreakSite links to the same pages as tieBreakSite, but every
+// page lists its links in a different order, so discovery order differs.
+var reorderedTieBreakSite = map[string][]string{
+	"/":              {"/faq", "/api", "/guide"},
+	"/guide":         {"/guide/usage", "/api", "/guide/install"},
+	"/api":           {"/api/server", "/guide/install", "/api/client"},
+	"/faq":           {"/faq/billing", "/guide/usage"},
+	"/guide/install": {"/"},
+	"/guide/usage":   {"/api/client"},
+	"/api/client":    {},
+	"/api/server":    {"/faq"},
+	"/faq/billing":   {},
+}
+
+func TestScheduler_DeterministicOrder_IgnoresDiscoveryOrder(t *testing.T) {
+	deterministicConfig := func() config.Config {
+		cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://example.com/")}).
+			WithOutputDir(t.TempDir()).
+			WithDeterministicOrder(true).
+			Build()
+		assert.NoError(t, err)
+		return cfg
+	}
+
+	// GIVEN two crawls whose pages list the same links in different orders
+	_, defaultWritten := crawlSiteForTest(t, tieBreakSite, paginationConfig(t, "https://example.com/", false))
+	_, reorderedDefaultWritten := crawlSiteForTest(t, reorderedTieBreakSite, paginationConfig(t, "https://example.com/", false))
+	assert.NotEqual(t, defaultWritten, reorderedDefaultWritten, "discovery order should differ without the flag")
+
+	// WHEN both are crawled with deterministic ordering
+	_, firstWritten := crawlSiteForTest(t, tieBreakSite, deterministicConfig())
+	_, secondWritten := crawlSiteForTest(t, reorderedTieBreakSite, deterministicConfig())
+
+	// THEN they write pages in the same order: by depth, then canonical URL
+	assert.Equal(t, firstWritten, secondWritten)
+	expected := []string{
+		"https://example.com/",
+		"https://example.com/api",
+		"https://example.com/faq",
+		"https://example.com/guide",
+		"https://example.com/api/client",
+		"https://example.com/api/server",
+		"https://example.com/faq/billing",
+		"https://example.com/guide/install",
+		"https://example.com/guide/usage",
+	}
+	assert.Equal(t, expected, firstWritten)
+}
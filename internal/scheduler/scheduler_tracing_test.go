@@ -0,0 +1,126 @@
+package scheduler_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/rohmanhakim/docs-crawler/internal/scheduler"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// tracedCrawlForTest crawls the archived pages from seed with a tracer
+// recording into the returned recorder.
+func tracedCrawlForTest(t *testing.T, pages map[string]string, seed string) *tracetest.SpanRecorder {
+	t.Helper()
+	archiveDir := t.TempDir()
+	for path, page := range pages {
+		require.NoError(t, fetcher.WriteArchiveEntry(archiveDir, fetcher.ArchiveEntry{
+			URL:     "https://docs.example.com" + path,
+			Status:  http.StatusOK,
+			Headers: http.Header{"Content-Type": {"text/html"}},
+			Body:    []byte(page),
+		}))
+	}
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL(seed)}).
+		WithOutputDir(t.TempDir()).
+		WithReplayArchive(archiveDir).
+		WithMaxAttempt(1).
+		Build()
+	require.NoError(t, err)
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	sink := &metadatatest.SinkMock{}
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	_, err = runPipelineForTest(t, &archiveFetcher, sink, storage.NewMemoryWriter(), cfg, func(s *scheduler.Scheduler) {
+		s.SetTracer(provider.Tracer("docs-crawler"))
+	})
+	require.NoError(t, err)
+	return recorder
+}
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestScheduler_Tracing_PageAndStageSpans(t *testing.T) {
+	recorder := tracedCrawlForTest(t, map[string]string{"/docs/install": archiveSitePages["/docs/install"]}, "https://docs.example.com/docs/install")
+
+	spans := recorder.Ended()
+	var page sdktrace.ReadOnlySpan
+	for _, span := range spans {
+		if span.Name() == "crawl.page" {
+			require.Nil(t, page, "expected a single page span")
+			page = span
+		}
+	}
+	require.NotNil(t, page)
+
+	attrs := spanAttributes(page)
+	assert.Equal(t, "https://docs.example.com/docs/install", attrs["crawl.url"].AsString())
+	assert.Equal(t, int64(0), attrs["crawl.depth"].AsInt64())
+	assert.Equal(t, "written", attrs["crawl.outcome"].AsString())
+	assert.Positive(t, attrs["crawl.bytes"].AsInt64())
+	assert.Equal(t, codes.Unset, page.Status().Code)
+
+	var stages []string
+	for _, span := range spans {
+		if span.Parent().SpanID() != page.SpanContext().SpanID() {
+			continue
+		}
+		stages = append(stages, span.Name())
+		if span.Name() == "crawl.fetch" {
+			fetchAttrs := spanAttributes(span)
+			assert.Equal(t, int64(http.StatusOK), fetchAttrs["http.response.status_code"].AsInt64())
+			assert.Positive(t, fetchAttrs["crawl.bytes"].AsInt64())
+		}
+	}
+	assert.Equal(t, []string{
+		"crawl.fetch",
+		"crawl.extract",
+		"crawl.sanitize",
+		"crawl.convert",
+		"crawl.assets",
+		"crawl.normalize",
+		"crawl.transform",
+		"crawl.write",
+	}, stages)
+}
+
+func TestScheduler_Tracing_FailedPageHasErrorStatus(t *testing.T) {
+	// The linked page is missing from the archive, so its fetch fails
+	recorder := tracedCrawlForTest(t, map[string]string{"/docs": archiveSitePages["/docs"]}, "https://docs.example.com/docs")
+
+	var failed sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		if span.Name() == "crawl.page" && spanAttributes(span)["crawl.url"].AsString() == "https://docs.example.com/docs/install" {
+			failed = span
+		}
+	}
+	require.NotNil(t, failed, "expected a span for the missing page")
+	assert.Equal(t, codes.Error, failed.Status().Code)
+	assert.Equal(t, "failed", spanAttributes(failed)["crawl.outcome"].AsString())
+
+	var fetchFailed bool
+	for _, span := range recorder.Ended() {
+		if span.Parent().SpanID() == failed.SpanContext().SpanID() {
+			assert.Equal(t, "crawl.fetch", span.Name(), "no stage runs after a failed fetch")
+			fetchFailed = span.Status().Code == codes.Error
+		}
+	}
+	assert.True(t, fetchFailed, "expected the fetch span to have an error status")
+}
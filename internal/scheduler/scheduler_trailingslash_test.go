@@ -0,0 +1,107 @@
+package scheduler_test
+
+import (
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/rohmanhakim/docs-crawler/pkg/hashutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTrailingSlashArchiveForTest archives an index linking to both /docs/a
+// and /docs/a/, each serving its own page.
+func writeTrailingSlashArchiveForTest(t *testing.T) string {
+	t.Helper()
+	archiveDir := t.TempDir()
+	writePage := func(pageURL, title, body string) {
+		require.NoError(t, fetcher.WriteArchiveEntry(archiveDir, fetcher.ArchiveEntry{
+			URL:     pageURL,
+			Status:  http.StatusOK,
+			Headers: http.Header{"Content-Type": {"text/html"}},
+			Body: []byte(`<html><body><main><h1>` + title + `</h1>
+<p>This page has enough text to pass content extraction and be written.</p>
+` + body + `</main></body></html>`),
+		}))
+	}
+	writePage("https://docs.example.com/docs", "Index", `<a href="/docs/a/">With slash</a> <a href="/docs/a">Without slash</a>`)
+	writePage("https://docs.example.com/docs/", "Index", `<a href="/docs/a/">With slash</a> <a href="/docs/a">Without slash</a>`)
+	writePage("https://docs.example.com/docs/a/", "Directory", "")
+	writePage("https://docs.example.com/docs/a", "Page", "")
+	return archiveDir
+}
+
+// pagePathForTest returns the output path of the page with canonicalURL.
+func pagePathForTest(t *testing.T, canonicalURL string) string {
+	t.Helper()
+	name, err := storage.PageFileName(canonicalURL, hashutil.HashAlgoSHA256)
+	require.NoError(t, err)
+	return filepath.Join("out", name)
+}
+
+func TestScheduler_TrailingSlashPolicy(t *testing.T) {
+	tests := []struct {
+		policy    string
+		wantPaths []string
+	}{
+		{
+			policy: "strip",
+			wantPaths: []string{
+				"https://docs.example.com/docs",
+				"https://docs.example.com/docs/a",
+			},
+		},
+		{
+			policy: "preserve",
+			wantPaths: []string{
+				"https://docs.example.com/docs",
+				"https://docs.example.com/docs/a",
+				"https://docs.example.com/docs/a/",
+			},
+		},
+		{
+			policy: "add",
+			wantPaths: []string{
+				"https://docs.example.com/docs/",
+				"https://docs.example.com/docs/a/",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			archiveDir := writeTrailingSlashArchiveForTest(t)
+			cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+				WithOutputDir("out").
+				WithReplayArchive(archiveDir).
+				WithHashAlgo(hashutil.HashAlgoSHA256).
+				WithTrailingSlashPolicy(tt.policy).
+				Build()
+			require.NoError(t, err)
+
+			sink := &metadatatest.SinkMock{}
+			archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+			execution, err := runPipelineForTest(t, &archiveFetcher, sink, storage.NewMemoryWriter(), cfg)
+			require.NoError(t, err)
+
+			var paths []string
+			for _, result := range execution.WriteResults() {
+				paths = append(paths, result.Path())
+			}
+			var wantPaths []string
+			for _, canonicalURL := range tt.wantPaths {
+				wantPaths = append(wantPaths, pagePathForTest(t, canonicalURL))
+			}
+			sort.Strings(paths)
+			sort.Strings(wantPaths)
+			assert.Equal(t, wantPaths, paths)
+		})
+	}
+}
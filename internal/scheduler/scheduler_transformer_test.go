@@ -0,0 +1,209 @@
+package scheduler_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/frontier"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/internal/normalize"
+	"github.com/rohmanhakim/docs-crawler/internal/robots"
+	"github.com/rohmanhakim/docs-crawler/internal/scheduler"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/rohmanhakim/docs-crawler/pkg/failure"
+	"github.com/rohmanhakim/docs-crawler/pkg/hashutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// appendMarker returns a transformer that appends marker as its own line.
+func appendMarker(marker string) scheduler.Transformer {
+	return func(doc normalize.NormalizedMarkdownDoc, _ scheduler.TransformContext) (normalize.NormalizedMarkdownDoc, error) {
+		content := append([]byte{}, doc.Content()...)
+		content = append(content, []byte("\n"+marker)...)
+		return normalize.NewNormalizedMarkdownDoc(doc.Frontmatter(), content), nil
+	}
+}
+
+// setupTransformerTest creates a scheduler that crawls the seed plus extraPages
+// and captures every document passed to storage.
+func setupTransformerTest(t *testing.T, extraPages ...string) (*scheduler.Scheduler, *fetcherMock, *[]normalize.NormalizedMarkdownDoc) {
+	t.Helper()
+	mockRobot := NewRobotsMockForTest(t)
+	mockRobot.On("Init", mock.Anything, mock.Anything).Return()
+	mockRobot.OnDecide(mock.Anything, robots.Decision{
+		Allowed: true,
+		Reason:  robots.EmptyRuleSet,
+	}, nil)
+
+	var written []normalize.NormalizedMarkdownDoc
+	mockStorage := newStorageMockForTest(t)
+	mockStorage.On("Write", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			written = append(written, args.Get(1).(normalize.NormalizedMarkdownDoc))
+		}).
+		Return(storage.WriteResult{}, nil)
+
+	mockFrontier := newFrontierMockForTest(t)
+	for _, page := range extraPages {
+		u, _ := url.Parse(page)
+		mockFrontier.Enqueue(frontier.NewCrawlToken(*u, 1))
+	}
+
+	mockFetcher := newFetcherMockForTest(t)
+	s := createSchedulerForTest(
+		t,
+		context.Background(),
+		newMockFinalizer(t),
+		&metadata.NoopSink{},
+		newRateLimiterMockForTest(t),
+		mockFrontier,
+		mockRobot,
+		mockFetcher,
+		nil,
+		nil,
+		nil,
+		nil,
+		mockStorage,
+		newFailureJournalMockForTest(t),
+	)
+	return s, mockFetcher, &written
+}
+
+func transformerTestConfig(t *testing.T) config.Config {
+	t.Helper()
+	seed, _ := url.Parse("https://example.com/docs")
+	cfg, err := config.WithDefault([]url.URL{*seed}).
+		WithOutputDir(t.TempDir()).
+		Build()
+	if err != nil {
+		t.Fatalf("failed to build config: %v", err)
+	}
+	return cfg
+}
+
+func TestTransformer_RunsForEachPage(t *testing.T) {
+	s, _, written := setupTransformerTest(t, "https://example.com/docs/a", "https://example.com/docs/b")
+	s.RegisterTransformer(appendMarker("<!-- transformed -->"))
+
+	init, err := s.InitializeWithConfig(transformerTestConfig(t))
+	assert.NoError(t, err)
+
+	result, err := s.ExecuteCrawlingWithState(init)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.TotalPages())
+	assert.Len(t, *written, 3)
+	for _, doc := range *written {
+		assert.True(t, strings.HasSuffix(string(doc.Content()), "\n<!-- transformed -->"),
+			"expected marker at end of %q", doc.Content())
+	}
+}
+
+func TestTransformer_RefreshesContentHash(t *testing.T) {
+	s, _, written := setupTransformerTest(t)
+	s.RegisterTransformer(appendMarker("<!-- transformed -->"))
+
+	init, err := s.InitializeWithConfig(transformerTestConfig(t))
+	assert.NoError(t, err)
+
+	_, err = s.ExecuteCrawlingWithState(init)
+
+	// The written frontmatter hashes the transformed content, not the normalized one
+	assert.NoError(t, err)
+	assert.Len(t, *written, 1)
+	doc := (*written)[0]
+	expected, hashErr := hashutil.HashBytes(doc.Content(), hashutil.HashAlgoSHA256)
+	assert.NoError(t, hashErr)
+	assert.Equal(t, "sha256:"+expected, doc.Frontmatter().ContentHash())
+}
+
+func TestTransformer_RunsInRegistrationOrder(t *testing.T) {
+	s, _, written := setupTransformerTest(t)
+	s.RegisterTransformer(appendMarker("first"))
+	s.RegisterTransformer(appendMarker("second"))
+	s.RegisterTransformer(appendMarker("third"))
+
+	init, err := s.InitializeWithConfig(transformerTestConfig(t))
+	assert.NoError(t, err)
+
+	_, err = s.ExecuteCrawlingWithState(init)
+
+	assert.NoError(t, err)
+	assert.Len(t, *written, 1)
+	assert.True(t, strings.HasSuffix(string((*written)[0].Content()), "\nfirst\nsecond\nthird"))
+}
+
+func TestTransformer_ReceivesPageContext(t *testing.T) {
+	s, _, _ := setupTransformerTest(t)
+	var seen []scheduler.TransformContext
+	s.RegisterTransformer(func(doc normalize.NormalizedMarkdownDoc, ctx scheduler.TransformContext) (normalize.NormalizedMarkdownDoc, error) {
+		seen = append(seen, ctx)
+		return doc, nil
+	})
+
+	init, err := s.InitializeWithConfig(transformerTestConfig(t))
+	assert.NoError(t, err)
+
+	_, err = s.ExecuteCrawlingWithState(init)
+
+	assert.NoError(t, err)
+	assert.Len(t, seen, 1)
+	pageURL := seen[0].URL()
+	assert.Equal(t, "example.com", pageURL.Host)
+	assert.Equal(t, 0, seen[0].Depth())
+}
+
+func TestTransformer_UnclassifiedError_SkipsPageAndContinues(t *testing.T) {
+	s, _, written := setupTransformerTest(t, "https://example.com/docs/a")
+	calls := 0
+	s.RegisterTransformer(func(doc normalize.NormalizedMarkdownDoc, _ scheduler.TransformContext) (normalize.NormalizedMarkdownDoc, error) {
+		calls++
+		if calls == 1 {
+			return normalize.NormalizedMarkdownDoc{}, errors.New("unsupported admonition")
+		}
+		return doc, nil
+	})
+
+	init, err := s.InitializeWithConfig(transformerTestConfig(t))
+	assert.NoError(t, err)
+
+	result, err := s.ExecuteCrawlingWithState(init)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	assert.Len(t, *written, 1)
+	assert.Equal(t, 1, result.TotalErrors())
+}
+
+func TestTransformer_FatalError_AbortsCrawl(t *testing.T) {
+	s, mockFetcher, written := setupTransformerTest(t, "https://example.com/docs/a")
+	fatalErr := &mockClassifiedError{
+		msg:         "transformer invariant broken",
+		severity:    failure.SeverityFatal,
+		retryPolicy: failure.RetryPolicyNever,
+		impactLevel: failure.ImpactLevelAbort,
+	}
+	s.RegisterTransformer(func(doc normalize.NormalizedMarkdownDoc, _ scheduler.TransformContext) (normalize.NormalizedMarkdownDoc, error) {
+		return normalize.NormalizedMarkdownDoc{}, fatalErr
+	})
+	secondCalled := false
+	s.RegisterTransformer(func(doc normalize.NormalizedMarkdownDoc, _ scheduler.TransformContext) (normalize.NormalizedMarkdownDoc, error) {
+		secondCalled = true
+		return doc, nil
+	})
+
+	init, err := s.InitializeWithConfig(transformerTestConfig(t))
+	assert.NoError(t, err)
+
+	_, err = s.ExecuteCrawlingWithState(init)
+
+	assert.ErrorIs(t, err, fatalErr)
+	assert.False(t, secondCalled, "later transformers must not run after an error")
+	assert.Empty(t, *written)
+	mockFetcher.AssertNumberOfCalls(t, "Fetch", 1)
+}
@@ -0,0 +1,106 @@
+package scheduler_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata/metadatatest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writePaginatedSiteArchiveForTest archives an index linking to a guide and
+// to the first of pageCount numbered pages of a listing, "/docs/list?page=N",
+// each linking to the next but the last.
+func writePaginatedSiteArchiveForTest(t *testing.T, pageCount int) string {
+	t.Helper()
+	archiveDir := t.TempDir()
+	writePage := func(path, title, body string) {
+		require.NoError(t, fetcher.WriteArchiveEntry(archiveDir, fetcher.ArchiveEntry{
+			URL:     "https://docs.example.com" + path,
+			Status:  http.StatusOK,
+			Headers: http.Header{"Content-Type": {"text/html"}},
+			Body: []byte(fmt.Sprintf(`<html><body><main><h1>%s</h1>
+<p>This page has enough text to pass content extraction and be written.</p>
+%s</main></body></html>`, title, body)),
+		}))
+	}
+
+	writePage("/docs", "Index", `<ul><li><a href="/docs/guide">Guide</a></li>
+<li><a href="/docs/list?page=1">Listing</a></li></ul>`)
+	writePage("/docs/guide", "Guide", "")
+	for i := 1; i <= pageCount; i++ {
+		next := ""
+		if i < pageCount {
+			next = fmt.Sprintf(`<p><a href="/docs/list?page=%d">Next page</a></p>`, i+1)
+		}
+		writePage(fmt.Sprintf("/docs/list?page=%d", i), fmt.Sprintf("Listing %d", i), next)
+	}
+	return archiveDir
+}
+
+func TestScheduler_MaxPatternRepeats_StopsAdmittingTrapURLs(t *testing.T) {
+	archiveDir := writePaginatedSiteArchiveForTest(t, 100)
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir(t.TempDir()).
+		WithReplayArchive(archiveDir).
+		WithAllowedQueryParams([]string{"page"}).
+		WithMaxDepth(200).
+		WithMaxPages(200).
+		WithMaxPatternRepeats(10).
+		Build()
+	require.NoError(t, err)
+
+	sink := &metadatatest.SinkMock{}
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	fetched, _ := crawlPipelineForTest(t, &archiveFetcher, sink, cfg)
+
+	// The index, the guide and the first 10 listing pages are crawled
+	var listings []string
+	for _, fetchURL := range fetched {
+		if strings.Contains(fetchURL, "/docs/list") {
+			listings = append(listings, fetchURL)
+		}
+	}
+	assert.Contains(t, fetched, "https://docs.example.com/docs")
+	assert.Contains(t, fetched, "https://docs.example.com/docs/guide")
+	require.Len(t, listings, 10)
+	for i, listing := range listings {
+		assert.Equal(t, fmt.Sprintf("https://docs.example.com/docs/list?page=%d", i+1), listing)
+	}
+
+	// The link to the 11th is skipped as a trap, ending the chain
+	var trapSkips []string
+	for _, event := range sink.SkipEvents {
+		if event.Reason() == metadata.SkipReasonPatternRepeat {
+			trapSkips = append(trapSkips, event.SkippedURL())
+		}
+	}
+	assert.Equal(t, []string{"https://docs.example.com/docs/list?page=11"}, trapSkips)
+}
+
+func TestScheduler_MaxPatternRepeats_DisabledByDefault(t *testing.T) {
+	archiveDir := writePaginatedSiteArchiveForTest(t, 20)
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/docs")}).
+		WithOutputDir(t.TempDir()).
+		WithReplayArchive(archiveDir).
+		WithAllowedQueryParams([]string{"page"}).
+		WithMaxDepth(200).
+		Build()
+	require.NoError(t, err)
+
+	sink := &metadatatest.SinkMock{}
+	archiveFetcher := fetcher.NewArchiveFetcher(sink, archiveDir)
+	fetched, _ := crawlPipelineForTest(t, &archiveFetcher, sink, cfg)
+
+	assert.Len(t, fetched, 22)
+	for _, event := range sink.SkipEvents {
+		assert.NotEqual(t, metadata.SkipReasonPatternRepeat, event.Reason())
+	}
+}
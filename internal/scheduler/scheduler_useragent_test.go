@@ -0,0 +1,136 @@
+package scheduler_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/extractor"
+	"github.com/rohmanhakim/docs-crawler/internal/frontier"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/internal/robots"
+	"github.com/rohmanhakim/docs-crawler/internal/sanitizer"
+	"github.com/rohmanhakim/docs-crawler/internal/scheduler"
+	"github.com/rohmanhakim/docs-crawler/internal/stagedump"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/rohmanhakim/docs-crawler/pkg/debug"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestScheduler_HostUserAgents_AppliedToRobotsAndFetcher(t *testing.T) {
+	// GIVEN two hosts serving a robots.txt that only restricts SpecialBot
+	robotsContent := "User-agent: SpecialBot\nDisallow: /private/\n\nUser-agent: *\nAllow: /\n"
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(robotsContent))
+	})
+	overridden := httptest.NewServer(handler)
+	defer overridden.Close()
+	other := httptest.NewServer(handler)
+	defer other.Close()
+
+	overriddenHost := mustParseURL(overridden.URL).Host
+	otherHost := mustParseURL(other.URL).Host
+
+	mockFetcher := new(fetcherMock)
+	mockFetcher.On("Init", mock.Anything, "docs-crawler/1.0").Return()
+
+	noopSink := &metadata.NoopSink{}
+	realFrontier := frontier.NewCrawlFrontier()
+	realRobot := robots.NewCachedRobot(noopSink)
+	ext := extractor.NewDomExtractor(noopSink)
+	san := sanitizer.NewHTMLSanitizer(noopSink)
+	s := scheduler.NewSchedulerWithDeps(
+		context.Background(),
+		newMockFinalizer(t),
+		noopSink,
+		newRateLimiterMockForTest(t),
+		&realFrontier,
+		mockFetcher,
+		&realRobot,
+		&ext,
+		&san,
+		newConvertMockForTest(t),
+		newResolverMockForTest(t),
+		newNormalizeMockForTest(t),
+		storage.NewMemoryWriter(),
+		newFailureJournalMockForTest(t),
+		stagedump.NewNoOpDumper(),
+		debug.NewNoOpLogger(),
+	)
+
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL(overridden.URL + "/docs")}).
+		WithUserAgent("docs-crawler/1.0").
+		WithHostUserAgents(map[string]string{overriddenHost: "SpecialBot/2.0"}).
+		WithAllowedHosts(map[string]struct{}{overriddenHost: {}, otherHost: {}}).
+		Build()
+	assert.NoError(t, err)
+
+	_, err = s.InitializeWithConfig(cfg)
+	assert.NoError(t, err)
+
+	// WHEN submitting a /private/ page on each host
+	assert.Nil(t, s.SubmitUrlForAdmission(*mustParseURL(overridden.URL + "/private/page"), frontier.SourceCrawl, 1))
+	assert.Nil(t, s.SubmitUrlForAdmission(*mustParseURL(other.URL + "/private/page"), frontier.SourceCrawl, 1))
+
+	// THEN only the host using the global user agent admits it
+	var pending []string
+	for _, token := range realFrontier.PendingTokens() {
+		u := token.URL()
+		pending = append(pending, u.String())
+	}
+	assert.NotContains(t, pending, overridden.URL+"/private/page")
+	assert.Contains(t, pending, other.URL+"/private/page")
+
+	// AND the fetcher receives the overrides for page fetches
+	assert.Equal(t, map[string]string{overriddenHost: "SpecialBot/2.0"}, mockFetcher.param.HostUserAgents)
+}
+
+func TestScheduler_HostUserAgents_AppliedToInjectedRobot(t *testing.T) {
+	// GIVEN a scheduler with an injected robots implementation
+	mockRobot := NewRobotsMockForTest(t)
+	mockRobot.On("Init", "docs-crawler/1.0", mock.Anything).Return()
+	mockRobot.OnDecide(mock.Anything, robots.Decision{Allowed: true, Reason: robots.EmptyRuleSet}, nil)
+	mockFetcher := new(fetcherMock)
+	mockFetcher.On("Init", mock.Anything, "docs-crawler/1.0").Return()
+
+	noopSink := &metadata.NoopSink{}
+	realFrontier := frontier.NewCrawlFrontier()
+	ext := extractor.NewDomExtractor(noopSink)
+	san := sanitizer.NewHTMLSanitizer(noopSink)
+	s := scheduler.NewSchedulerWithDeps(
+		context.Background(),
+		newMockFinalizer(t),
+		noopSink,
+		newRateLimiterMockForTest(t),
+		&realFrontier,
+		mockFetcher,
+		mockRobot,
+		&ext,
+		&san,
+		newConvertMockForTest(t),
+		newResolverMockForTest(t),
+		newNormalizeMockForTest(t),
+		storage.NewMemoryWriter(),
+		newFailureJournalMockForTest(t),
+		stagedump.NewNoOpDumper(),
+		debug.NewNoOpLogger(),
+	)
+
+	cfg, err := config.WithDefault([]url.URL{*mustParseURL("https://docs.example.com/")}).
+		WithUserAgent("docs-crawler/1.0").
+		WithHostUserAgents(map[string]string{"docs.example.com": "SpecialBot/2.0"}).
+		Build()
+	assert.NoError(t, err)
+
+	// WHEN initializing the crawl
+	_, err = s.InitializeWithConfig(cfg)
+	assert.NoError(t, err)
+
+	// THEN the robot receives the overrides through the Robot interface
+	assert.Equal(t, map[string]string{"docs.example.com": "SpecialBot/2.0"}, mockRobot.hostUserAgents)
+}
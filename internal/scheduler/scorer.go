@@ -0,0 +1,25 @@
+package scheduler
+
+import "net/url"
+
+/*
+URL Scoring
+
+For targeted crawls a URLScorer rates every admitted URL, e.g. favoring
+paths containing "api" or "reference". The score travels with the URL into
+the frontier, which dequeues higher-scoring URLs first within a depth level
+when config.FrontierStrategy is config.FrontierStrategyBestFirst. Under the
+breadth-first strategy scores are recorded but do not affect the order.
+
+The scorer only orders URLs that passed admission; it never widens or
+narrows the crawl scope.
+*/
+
+// URLScorer rates a URL found at depth; higher scores are crawled first.
+type URLScorer func(u url.URL, depth int) float64
+
+// SetURLScorer sets the scorer used to rate admitted URLs.
+// A nil scorer gives every URL a score of 0.
+func (s *Scheduler) SetURLScorer(fn URLScorer) {
+	s.urlScorer = fn
+}
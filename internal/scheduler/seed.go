@@ -0,0 +1,39 @@
+package scheduler
+
+import (
+	"github.com/rohmanhakim/docs-crawler/internal/frontier"
+	"github.com/rohmanhakim/docs-crawler/internal/robots"
+	"github.com/rohmanhakim/docs-crawler/pkg/failure"
+)
+
+// SeedFrontier submits additional URLs to the frontier so embedders can
+// inject their own starting points instead of relying only on config seeds.
+//
+// It must be called after InitializeCrawling (or InitializeWithConfig) and
+// before ExecuteCrawlingWithState. Each candidate keeps its source context,
+// depth and tie-break priority, and goes through the same scope and robots
+// checks as SubmitUrlForAdmission; disallowed candidates are skipped.
+//
+// All candidates are attempted. Robots errors are recorded with backoff
+// and the first submission error, if any, is returned.
+func (s *Scheduler) SeedFrontier(candidates []frontier.CrawlAdmissionCandidate) failure.ClassifiedError {
+	var firstErr failure.ClassifiedError
+	for i := range candidates {
+		target := candidates[i].TargetURL()
+		_, err := s.submitForAdmission(
+			target,
+			candidates[i].SourceContext(),
+			candidates[i].DiscoveryMetadata(),
+		)
+		if err == nil {
+			continue
+		}
+		if robotsErr, ok := err.(*robots.RobotsError); ok {
+			s.recordRobotsErrorAndBackoff(robotsErr, target)
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,39 @@
+package scheduler
+
+import (
+	"fmt"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/pkg/failurejournal"
+)
+
+/*
+Strict Mode
+
+A crawl normally skips a page whose content cannot be extracted, sanitized,
+converted, normalized or written, and goes on with the next one; missing
+assets only leave their remote URLs in place. For CI gating any of these
+should fail the run instead. With config.StrictMode, the first page failing
+in one of these stages, asset resolution included, aborts the crawl with a
+fatal SchedulerError matching ErrStrictMode.
+
+The failure is handled as usual before the crawl aborts: the stage records
+its error, the page is retried when page retries allow it and journaled for
+manual retry when eligible. Fetch failures are about the site, not the
+pipeline, and keep their normal handling.
+*/
+
+// strictModeError returns the fatal error aborting the crawl for attempt
+// under cfg.StrictMode, or nil when the crawl goes on.
+func (s *Scheduler) strictModeError(cfg config.Config, attempt pageAttempt) *SchedulerError {
+	if !cfg.StrictMode() {
+		return nil
+	}
+	if attempt.err != nil && attempt.stage != failurejournal.StageFetch {
+		return NewSchedulerError(ErrCauseStrictMode, attempt.err.Error())
+	}
+	if attempt.assetErr != nil {
+		return NewSchedulerError(ErrCauseStrictMode, fmt.Sprintf("asset resolution failed: %v", attempt.assetErr))
+	}
+	return nil
+}
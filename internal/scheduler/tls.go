@@ -0,0 +1,48 @@
+package scheduler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/config"
+	"github.com/rohmanhakim/docs-crawler/internal/fetcher"
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+)
+
+// configureTLS applies config.TLS to the crawl's HTTP client (see
+// fetcher.NewHostTLSTransport). A CA file that cannot be loaded aborts
+// initialization: crawling on would fail every fetch to that host.
+func (s *Scheduler) configureTLS(cfg config.Config) error {
+	hosts := cfg.TLS()
+	if len(hosts) == 0 {
+		return nil
+	}
+	base, ok := s.httpClient.Transport.(*http.Transport)
+	if !ok {
+		return nil
+	}
+
+	settings := make(map[string]fetcher.HostTLS, len(hosts))
+	for host, hostTLS := range hosts {
+		settings[host] = fetcher.HostTLS{
+			CAFile:             hostTLS.CAFile(),
+			InsecureSkipVerify: hostTLS.InsecureSkipVerify(),
+		}
+	}
+	transport, err := fetcher.NewHostTLSTransport(base, settings)
+	if err != nil {
+		err = fmt.Errorf("failed to configure TLS: %w", err)
+		s.metadataSink.RecordError(metadata.NewErrorRecord(
+			time.Now(),
+			"scheduler",
+			"configureTLS",
+			metadata.CauseNetworkFailure,
+			err.Error(),
+			[]metadata.Attribute{},
+		))
+		return err
+	}
+	s.httpClient.Transport = transport
+	return nil
+}
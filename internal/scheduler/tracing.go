@@ -0,0 +1,138 @@
+package scheduler
+
+import (
+	"context"
+
+	"github.com/rohmanhakim/docs-crawler/internal/frontier"
+	"github.com/rohmanhakim/docs-crawler/pkg/failure"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+/*
+Tracing
+
+With a tracer set (SetTracer), every page gets a span covering all of its
+runs, with a child span per pipeline stage of each run:
+
+	crawl.page                 url, depth, outcome, bytes, attempts
+	├── crawl.fetch            http status code, bytes
+	├── crawl.extract
+	├── crawl.sanitize
+	├── crawl.convert
+	├── crawl.assets           assets
+	├── crawl.normalize
+	├── crawl.transform
+	└── crawl.write            bytes
+
+A stage that fails ends its span with an error status, and so does the page
+span when the page fails or aborts the crawl. Stages a page does not reach
+have no span. Page spans are children of the span in the scheduler's
+context, if any.
+
+Without a tracer no span is started and no attribute is built, so tracing
+costs nothing when it is off.
+*/
+
+// Span attribute keys.
+const (
+	attrURL        = attribute.Key("crawl.url")
+	attrDepth      = attribute.Key("crawl.depth")
+	attrOutcome    = attribute.Key("crawl.outcome")
+	attrBytes      = attribute.Key("crawl.bytes")
+	attrAttempts   = attribute.Key("crawl.attempts")
+	attrAssets     = attribute.Key("crawl.assets")
+	attrStatusCode = attribute.Key("http.response.status_code")
+)
+
+// Page outcomes, the crawl.outcome attribute of page spans.
+const (
+	outcomeWritten   = "written"
+	outcomeDuplicate = "duplicate"
+	outcomeFailed    = "failed"
+	outcomeAborted   = "aborted"
+)
+
+// SetTracer sets the tracer that records page and stage spans.
+// This is optional; a nil tracer turns tracing off.
+func (s *Scheduler) SetTracer(tracer trace.Tracer) {
+	s.tracer = tracer
+}
+
+// startPageSpan starts the span of the page at token, the parent of the
+// stage spans until endPageSpan.
+func (s *Scheduler) startPageSpan(token frontier.CrawlToken) {
+	if s.tracer == nil {
+		return
+	}
+	ctx, span := s.tracer.Start(s.ctx, "crawl.page", trace.WithAttributes(
+		attrURL.String(getURLString(token.URL())),
+		attrDepth.Int(token.Depth()),
+	))
+	s.pageSpanCtx = ctx
+	s.pageSpan = span
+}
+
+// endPageSpan ends the current page span with the page's final attempt, or
+// with err when the page aborted the crawl.
+func (s *Scheduler) endPageSpan(attempt pageAttempt, attempts int, err error) {
+	if s.pageSpan == nil {
+		return
+	}
+	span := s.pageSpan
+	s.pageSpan = nil
+	s.pageSpanCtx = nil
+
+	outcome := outcomeWritten
+	switch {
+	case err != nil:
+		outcome = outcomeAborted
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	case attempt.err != nil:
+		outcome = outcomeFailed
+		span.RecordError(attempt.err)
+		span.SetStatus(codes.Error, attempt.err.Error())
+	case attempt.duplicate:
+		outcome = outcomeDuplicate
+	}
+	span.SetAttributes(
+		attrOutcome.String(outcome),
+		attrBytes.Int64(attempt.outputBytes),
+		attrAttempts.Int(attempts),
+	)
+	span.End()
+}
+
+// startStageSpan starts the span of a pipeline stage under the current page
+// span. It returns nil when tracing is off.
+func (s *Scheduler) startStageSpan(stage string) trace.Span {
+	if s.pageSpan == nil {
+		return nil
+	}
+	_, span := s.tracer.Start(s.pageSpanCtx, "crawl."+stage)
+	return span
+}
+
+// endStageSpan ends span, with an error status when the stage failed.
+// A nil span is ignored.
+func endStageSpan(span trace.Span, err failure.ClassifiedError) {
+	if span == nil {
+		return
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// pageSpanContext returns the context stage work runs in: the page span's
+// when tracing, the scheduler's otherwise.
+func (s *Scheduler) pageSpanContext() context.Context {
+	if s.pageSpanCtx != nil {
+		return s.pageSpanCtx
+	}
+	return s.ctx
+}
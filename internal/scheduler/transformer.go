@@ -0,0 +1,111 @@
+package scheduler
+
+import (
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/internal/normalize"
+	"github.com/rohmanhakim/docs-crawler/pkg/failure"
+)
+
+// TransformContext describes the page a transformer is being applied to.
+type TransformContext struct {
+	url   url.URL
+	depth int
+}
+
+// NewTransformContext creates a TransformContext for the page at pageURL.
+func NewTransformContext(pageURL url.URL, depth int) TransformContext {
+	return TransformContext{
+		url:   pageURL,
+		depth: depth,
+	}
+}
+
+// URL returns the fetched URL of the page being transformed.
+func (c TransformContext) URL() url.URL {
+	return c.url
+}
+
+// Depth returns the crawl depth of the page being transformed.
+func (c TransformContext) Depth() int {
+	return c.depth
+}
+
+// Transformer rewrites a normalized document before it is written.
+// Returning a failure.ClassifiedError lets the transformer decide whether
+// the crawl continues or aborts; any other error skips the page.
+// Transformers only need to rewrite content: the title and content hash in
+// the frontmatter are recomputed once every transformer has run.
+type Transformer func(doc normalize.NormalizedMarkdownDoc, ctx TransformContext) (normalize.NormalizedMarkdownDoc, error)
+
+// RegisterTransformer appends a transformer to the stage between
+// normalization and storage. Transformers run in registration order,
+// each receiving the previous transformer's output.
+func (s *Scheduler) RegisterTransformer(fn Transformer) {
+	if fn == nil {
+		return
+	}
+	s.transformers = append(s.transformers, fn)
+}
+
+// applyTransformers runs every registered transformer over doc.
+// The first error stops the chain and is returned classified.
+// Once the chain has run, the content-derived frontmatter (title and
+// content hash) and the chunks are recomputed so they match the content
+// that is written.
+func (s *Scheduler) applyTransformers(
+	doc normalize.NormalizedMarkdownDoc,
+	tctx TransformContext,
+	normalizeParam normalize.NormalizeParam,
+) (normalize.NormalizedMarkdownDoc, failure.ClassifiedError) {
+	if len(s.transformers) == 0 {
+		return doc, nil
+	}
+	for _, transform := range s.transformers {
+		transformed, err := transform(doc, tctx)
+		if err != nil {
+			classified := classifyTransformError(err)
+			s.recordTransformError(classified, tctx)
+			return normalize.NormalizedMarkdownDoc{}, classified
+		}
+		doc = transformed
+	}
+
+	refreshed, err := normalize.RefreshFrontmatter(doc, normalizeParam.HashAlgo())
+	if err != nil {
+		s.recordTransformError(err, tctx)
+		return normalize.NormalizedMarkdownDoc{}, err
+	}
+	return normalize.RefreshChunks(refreshed, normalizeParam), nil
+}
+
+func classifyTransformError(err error) failure.ClassifiedError {
+	var classified failure.ClassifiedError
+	if errors.As(err, &classified) {
+		return classified
+	}
+	return NewSchedulerError(ErrCauseTransformFailed, err.Error())
+}
+
+func (s *Scheduler) recordTransformError(err failure.ClassifiedError, tctx TransformContext) {
+	var cause metadata.ErrorCause = metadata.CauseContentInvalid
+	if schedErr, ok := err.(*SchedulerError); ok {
+		cause = mapSchedulerErrorToMetadataCause(schedErr)
+	}
+	pageURL := tctx.URL()
+	s.metadataSink.RecordError(metadata.NewErrorRecord(
+		time.Now(),
+		"scheduler",
+		"Transform",
+		cause,
+		err.Error(),
+		[]metadata.Attribute{
+			metadata.NewAttr(metadata.AttrURL, pageURL.String()),
+			metadata.NewAttr(metadata.AttrHost, pageURL.Host),
+			metadata.NewAttr(metadata.AttrPath, pageURL.Path),
+		},
+	))
+}
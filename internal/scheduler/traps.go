@@ -0,0 +1,126 @@
+package scheduler
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+/*
+Crawler Traps
+
+Calendars, archives and unbounded pagination generate links without end:
+"?page=2" links to "?page=3", "/2020/01/02/" to "/2020/01/03/", and a
+breadth-first crawl follows them for as long as it is allowed to. With
+config.MaxPatternRepeats, every discovered URL is reduced to its pattern:
+the canonical URL with each all-digit path segment and query value replaced
+by a placeholder.
+
+	https://example.com/blog?page=17   -> example.com/blog?page={n}
+	https://example.com/2020/01/02/    -> example.com/{n}/{n}/{n}/
+
+Only patterns with a placeholder are tracked. Once MaxPatternRepeats
+distinct URLs of a pattern were admitted, further URLs of that pattern are
+skipped with metadata.SkipReasonPatternRepeat; URLs already admitted and
+URLs of other patterns are unaffected. Seeds are never skipped.
+*/
+
+// patternPlaceholder replaces a numeric path segment or query value.
+const patternPlaceholder = "{n}"
+
+// trapDetector counts the distinct URLs admitted per URL pattern.
+// A nil trapDetector admits every URL.
+type trapDetector struct {
+	maxRepeats int
+	// pattern -> canonical URLs admitted with it, at most maxRepeats
+	admitted map[string]map[string]struct{}
+}
+
+// newTrapDetector returns a detector admitting maxRepeats URLs per
+// pattern, or nil when maxRepeats is 0.
+func newTrapDetector(maxRepeats int) *trapDetector {
+	if maxRepeats <= 0 {
+		return nil
+	}
+	return &trapDetector{
+		maxRepeats: maxRepeats,
+		admitted:   make(map[string]map[string]struct{}),
+	}
+}
+
+// admit reports whether canonical may be admitted, counting it against its
+// pattern when it is new.
+func (d *trapDetector) admit(canonical url.URL) bool {
+	if d == nil {
+		return true
+	}
+	pattern, numeric := urlPattern(canonical)
+	if !numeric {
+		return true
+	}
+
+	key := canonical.String()
+	urls := d.admitted[pattern]
+	if _, ok := urls[key]; ok {
+		return true
+	}
+	if len(urls) >= d.maxRepeats {
+		return false
+	}
+	if urls == nil {
+		urls = make(map[string]struct{})
+		d.admitted[pattern] = urls
+	}
+	urls[key] = struct{}{}
+	return true
+}
+
+// urlPattern returns the pattern of u and whether it has any placeholder.
+// Query parameters are listed in sorted order.
+func urlPattern(u url.URL) (string, bool) {
+	numeric := false
+
+	segments := strings.Split(u.EscapedPath(), "/")
+	for i, segment := range segments {
+		if isNumeric(segment) {
+			segments[i] = patternPlaceholder
+			numeric = true
+		}
+	}
+	pattern := strings.ToLower(u.Host) + strings.Join(segments, "/")
+
+	query := u.Query()
+	if len(query) == 0 {
+		return pattern, numeric
+	}
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	params := make([]string, 0, len(keys))
+	for _, key := range keys {
+		values := query[key]
+		for i, value := range values {
+			if isNumeric(value) {
+				values[i] = patternPlaceholder
+				numeric = true
+			}
+		}
+		params = append(params, key+"="+strings.Join(values, ","))
+	}
+	return pattern + "?" + strings.Join(params, "&"), numeric
+}
+
+// isNumeric reports whether s is a non-empty run of ASCII digits.
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,56 @@
+package scheduler
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestURLPattern(t *testing.T) {
+	tests := []struct {
+		raw     string
+		pattern string
+		numeric bool
+	}{
+		{raw: "https://example.com/blog?page=17", pattern: "example.com/blog?page={n}", numeric: true},
+		{raw: "https://example.com/2020/01/02/", pattern: "example.com/{n}/{n}/{n}/", numeric: true},
+		{raw: "https://example.com/v2/guide?lang=en&page=3", pattern: "example.com/v2/guide?lang=en&page={n}", numeric: true},
+		{raw: "https://example.com/docs/intro", pattern: "example.com/docs/intro", numeric: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			u, err := url.Parse(tt.raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pattern, numeric := urlPattern(*u)
+			if pattern != tt.pattern || numeric != tt.numeric {
+				t.Errorf("got %q, %v; want %q, %v", pattern, numeric, tt.pattern, tt.numeric)
+			}
+		})
+	}
+}
+
+func TestTrapDetector_AdmitsUpToMaxRepeatsPerPattern(t *testing.T) {
+	detector := newTrapDetector(2)
+	admit := func(raw string) bool {
+		u, _ := url.Parse(raw)
+		return detector.admit(*u)
+	}
+
+	if !admit("https://example.com/list?page=1") || !admit("https://example.com/list?page=2") {
+		t.Fatal("expected the first two URLs of the pattern to be admitted")
+	}
+	if admit("https://example.com/list?page=3") {
+		t.Error("expected the third URL of the pattern to be rejected")
+	}
+	if !admit("https://example.com/list?page=1") {
+		t.Error("expected an admitted URL to stay admitted")
+	}
+	if !admit("https://example.com/archive?page=3") {
+		t.Error("expected another pattern to be unaffected")
+	}
+	if !newTrapDetector(0).admit(url.URL{Host: "example.com", Path: "/1"}) {
+		t.Error("expected a disabled detector to admit every URL")
+	}
+}
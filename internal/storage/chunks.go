@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/rohmanhakim/docs-crawler/internal/normalize"
+	"github.com/rohmanhakim/docs-crawler/pkg/failure"
+)
+
+/*
+Chunk Sidecar
+
+When a page was normalized with chunking enabled, its chunks are written
+next to the page as outputDir/<url_hash>.chunks.json:
+
+	[
+	  {"id": "<hash>-0", "index": 0, "start": 0, "end": 812, "content": "# Title\n\n..."},
+	  ...
+	]
+
+start and end are byte offsets into the page's .md file, so a consumer can
+either index the content directly or map a chunk back to the page. Pages
+without chunks get no sidecar.
+*/
+
+// ChunksFileSuffix replaces the .md extension of a page for its chunk sidecar.
+const ChunksFileSuffix = ".chunks.json"
+
+type chunkDTO struct {
+	ID      string `json:"id"`
+	Index   int    `json:"index"`
+	Start   int    `json:"start"`
+	End     int    `json:"end"`
+	Content string `json:"content"`
+}
+
+// chunksPath returns the sidecar path for the page written to markdownPath.
+func chunksPath(markdownPath string) string {
+	return strings.TrimSuffix(markdownPath, ".md") + ChunksFileSuffix
+}
+
+// encodeChunks renders chunks as the sidecar JSON written to path.
+func encodeChunks(path string, chunks []normalize.Chunk) ([]byte, failure.ClassifiedError) {
+	entries := make([]chunkDTO, 0, len(chunks))
+	for _, c := range chunks {
+		entries = append(entries, chunkDTO{
+			ID:      c.ID(),
+			Index:   c.Index(),
+			Start:   c.Start(),
+			End:     c.End(),
+			Content: string(c.Content()),
+		})
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return nil, NewStorageError(ErrCauseWriteFailure, fmt.Sprintf("failed to encode chunks: %v", err), path)
+	}
+	return append(data, '\n'), nil
+}
@@ -1,11 +1,20 @@
 package storage
 
+import "net/url"
+
 // Persistence
 
 type WriteResult struct {
 	urlHash     string // identity (filename without extension)
 	path        string
 	contentHash string
+	// structuralHash covers the heading outline and internal links; empty in older manifests
+	structuralHash string
+	sourceURL      string            // URL the page was fetched from; empty in older manifests
+	aliases        []url.URL         // other URLs whose content matched this one
+	language       string            // output language partition; empty unless partitioning by language
+	runID          string            // crawl run that wrote the page; empty in older manifests
+	formats        map[string]string // additional output format -> path
 }
 
 func NewWriteResult(
@@ -19,6 +28,47 @@ func NewWriteResult(
 		contentHash: contentHash,
 	}
 }
+
+// WithStructuralHash returns a copy of the result that records the
+// structural hash of the page (see normalize.Frontmatter.StructuralHash).
+func (w WriteResult) WithStructuralHash(structuralHash string) WriteResult {
+	w.structuralHash = structuralHash
+	return w
+}
+
+// WithSourceURL returns a copy of the result that records the URL the page
+// was fetched from.
+func (w WriteResult) WithSourceURL(sourceURL string) WriteResult {
+	w.sourceURL = sourceURL
+	return w
+}
+
+// WithLanguage returns a copy of the result that records the language
+// partition the page was written to.
+func (w WriteResult) WithLanguage(language string) WriteResult {
+	w.language = language
+	return w
+}
+
+// WithRunID returns a copy of the result that records the crawl run that
+// wrote the page.
+func (w WriteResult) WithRunID(runID string) WriteResult {
+	w.runID = runID
+	return w
+}
+
+// WithFormat returns a copy of the result that records the path the page
+// was written to in an additional output format.
+func (w WriteResult) WithFormat(format string, path string) WriteResult {
+	formats := make(map[string]string, len(w.formats)+1)
+	for f, p := range w.formats {
+		formats[f] = p
+	}
+	formats[format] = path
+	w.formats = formats
+	return w
+}
+
 func (w *WriteResult) URLHash() string {
 	return w.urlHash
 }
@@ -30,3 +80,51 @@ func (w *WriteResult) Path() string {
 func (w *WriteResult) ContentHash() string {
 	return w.contentHash
 }
+
+// StructuralHash returns the hash of the heading outline and internal link
+// targets of the page. Pages differing only in prose share it.
+func (w *WriteResult) StructuralHash() string {
+	return w.structuralHash
+}
+
+// SourceURL returns the URL the page was fetched from.
+// It is empty for results read from manifests that predate it.
+func (w *WriteResult) SourceURL() string {
+	return w.sourceURL
+}
+
+// Language returns the language partition the page was written to.
+// It is empty unless the crawl partitioned its output by language.
+func (w *WriteResult) Language() string {
+	return w.language
+}
+
+// RunID returns the ID of the crawl run that wrote the page.
+// It is empty for results read from manifests that predate it.
+func (w *WriteResult) RunID() string {
+	return w.runID
+}
+
+// Formats returns the paths the page was written to in additional output
+// formats, keyed by format. It is nil when only Markdown was written.
+func (w *WriteResult) Formats() map[string]string {
+	if len(w.formats) == 0 {
+		return nil
+	}
+	formats := make(map[string]string, len(w.formats))
+	for f, p := range w.formats {
+		formats[f] = p
+	}
+	return formats
+}
+
+// Aliases returns the URLs that produced identical content and were
+// grouped under this result instead of being written separately.
+func (w *WriteResult) Aliases() []url.URL {
+	return append([]url.URL(nil), w.aliases...)
+}
+
+// AddAlias records u as an alias of this result.
+func (w *WriteResult) AddAlias(u url.URL) {
+	w.aliases = append(w.aliases, u)
+}
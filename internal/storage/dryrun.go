@@ -95,7 +95,9 @@ func (d *DryRunSink) Write(
 	contentHash := normalizedDoc.Frontmatter().ContentHash()
 
 	// Create the WriteResult (same as LocalSink)
-	writeResult := NewWriteResult(urlHash, fullPath, contentHash)
+	writeResult := NewWriteResult(urlHash, fullPath, contentHash).
+		WithSourceURL(normalizedDoc.Frontmatter().SourceURL()).
+		WithStructuralHash(normalizedDoc.Frontmatter().StructuralHash())
 
 	// Log dry-run write
 	if d.debugLogger.Enabled() {
@@ -121,3 +123,20 @@ func (d *DryRunSink) Write(
 
 	return writeResult, nil
 }
+
+// WriteConfigSnapshot simulates writing the config snapshot; nothing is written.
+func (d *DryRunSink) WriteConfigSnapshot(outputDir string, snapshot []byte) failure.ClassifiedError {
+	return nil
+}
+
+// WriteManifest simulates writing the crawl manifest; nothing is written.
+func (d *DryRunSink) WriteManifest(outputDir string, results []WriteResult) failure.ClassifiedError {
+	if d.debugLogger.Enabled() {
+		d.debugLogger.LogStep(context.TODO(), "storage", "dryrun_write_manifest", debug.FieldMap{
+			"file_path": ManifestFileName,
+			"entries":   len(results),
+			"dry_run":   true,
+		})
+	}
+	return nil
+}
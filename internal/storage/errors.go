@@ -14,6 +14,8 @@ const (
 	ErrCauseWriteFailure          StorageErrorCause = "write failed"
 	ErrCauseHashComputationFailed StorageErrorCause = "hash computation failed"
 	ErrCausePathError             StorageErrorCause = "path error"
+	ErrCauseReadFailure           StorageErrorCause = "read failed"
+	ErrCauseManifestParseFailure  StorageErrorCause = "manifest parse failed"
 )
 
 // storageErrorClassifications provides explicit retry policy and impact level
@@ -25,6 +27,8 @@ const (
 // - WriteFailure: Never retry - permissions or I/O issue, permanent
 // - HashComputationFailed: Never retry - algorithm issue, permanent
 // - PathError: Never retry - path configuration issue, permanent
+// - ReadFailure: Never retry - missing file or permissions issue, permanent
+// - ManifestParseFailure: Never retry - malformed manifest, permanent
 var storageErrorClassifications = map[StorageErrorCause]struct {
 	Policy failure.RetryPolicy
 	Impact failure.ImpactLevel
@@ -33,6 +37,8 @@ var storageErrorClassifications = map[StorageErrorCause]struct {
 	ErrCauseWriteFailure:          {failure.RetryPolicyNever, failure.ImpactLevelContinue},
 	ErrCauseHashComputationFailed: {failure.RetryPolicyNever, failure.ImpactLevelContinue},
 	ErrCausePathError:             {failure.RetryPolicyNever, failure.ImpactLevelContinue},
+	ErrCauseReadFailure:           {failure.RetryPolicyNever, failure.ImpactLevelContinue},
+	ErrCauseManifestParseFailure:  {failure.RetryPolicyNever, failure.ImpactLevelContinue},
 }
 
 // StorageError represents an error that occurred during storage operations.
@@ -104,6 +110,10 @@ func mapStorageErrorToMetadataCause(err *StorageError) metadata.ErrorCause {
 		return metadata.CauseStorageFailure
 	case ErrCausePathError:
 		return metadata.CauseStorageFailure
+	case ErrCauseReadFailure:
+		return metadata.CauseStorageFailure
+	case ErrCauseManifestParseFailure:
+		return metadata.CauseContentInvalid
 	case ErrCauseHashComputationFailed:
 		return metadata.CauseInvariantViolation
 	default:
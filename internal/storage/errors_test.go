@@ -48,6 +48,20 @@ func TestStorageError_Classifications(t *testing.T) {
 			wantImpact:   failure.ImpactLevelContinue,
 			wantSeverity: failure.SeverityRecoverable,
 		},
+		{
+			name:         "ErrCauseReadFailure should be RetryPolicyNever",
+			cause:        ErrCauseReadFailure,
+			wantPolicy:   failure.RetryPolicyNever,
+			wantImpact:   failure.ImpactLevelContinue,
+			wantSeverity: failure.SeverityRecoverable,
+		},
+		{
+			name:         "ErrCauseManifestParseFailure should be RetryPolicyNever",
+			cause:        ErrCauseManifestParseFailure,
+			wantPolicy:   failure.RetryPolicyNever,
+			wantImpact:   failure.ImpactLevelContinue,
+			wantSeverity: failure.SeverityRecoverable,
+		},
 	}
 
 	for _, tt := range tests {
@@ -78,6 +92,8 @@ func TestStorageError_AllCausesCovered(t *testing.T) {
 		ErrCauseWriteFailure,
 		ErrCauseHashComputationFailed,
 		ErrCausePathError,
+		ErrCauseReadFailure,
+		ErrCauseManifestParseFailure,
 	}
 
 	for _, cause := range allCauses {
@@ -151,6 +167,16 @@ func TestMapStorageErrorToMetadataCause(t *testing.T) {
 			err:       NewStorageError(ErrCausePathError, "test", "/path"),
 			wantCause: metadata.CauseStorageFailure,
 		},
+		{
+			name:      "ErrCauseReadFailure maps to CauseStorageFailure",
+			err:       NewStorageError(ErrCauseReadFailure, "test", "/path"),
+			wantCause: metadata.CauseStorageFailure,
+		},
+		{
+			name:      "ErrCauseManifestParseFailure maps to CauseContentInvalid",
+			err:       NewStorageError(ErrCauseManifestParseFailure, "test", "/path"),
+			wantCause: metadata.CauseContentInvalid,
+		},
 		{
 			name:      "ErrCauseHashComputationFailed maps to CauseInvariantViolation",
 			err:       NewStorageError(ErrCauseHashComputationFailed, "test", "/path"),
@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/pkg/debug"
+	"github.com/rohmanhakim/docs-crawler/pkg/failure"
+	"github.com/rohmanhakim/docs-crawler/pkg/fileutil"
+)
+
+/*
+Output Formats
+
+A page can be written in more formats than Markdown, for consumers that want
+sanitized HTML or plain text from the same crawl. Each additional format is
+written next to the page's .md file with the same base name and the format
+as its extension:
+
+	outputDir/<url_hash>.md
+	outputDir/<url_hash>.html
+	outputDir/<url_hash>.txt
+
+Writers that support this implement FormatWriter. The written paths are
+recorded on the WriteResult and in the manifest entry's formats map, keyed
+by format; Markdown stays in path.
+*/
+
+// FormatWriter is implemented by writers that can store a page in
+// additional output formats.
+type FormatWriter interface {
+	// WriteFormat stores content as the page of result in format, and
+	// returns result with the written path recorded.
+	WriteFormat(result WriteResult, format string, content []byte) (WriteResult, failure.ClassifiedError)
+}
+
+// FormatPath returns the path of format next to the Markdown file at
+// markdownPath.
+func FormatPath(markdownPath string, format string) string {
+	return strings.TrimSuffix(markdownPath, ".md") + "." + format
+}
+
+// WriteFormat writes content next to the page's Markdown file.
+func (s *LocalSink) WriteFormat(result WriteResult, format string, content []byte) (WriteResult, failure.ClassifiedError) {
+	path := FormatPath(result.Path(), format)
+	var err failure.ClassifiedError
+	if ensureErr := fileutil.EnsureDir(filepath.Dir(path)); ensureErr != nil {
+		err = NewStorageError(ErrCausePathError, ensureErr.Error(), filepath.Dir(path))
+	} else if writeErr := os.WriteFile(path, content, 0644); writeErr != nil {
+		err = NewStorageError(writeFailureCause(writeErr), writeErr.Error(), path)
+	}
+	if err != nil {
+		var storageError *StorageError
+		errors.As(err, &storageError)
+		s.metadataSink.RecordError(metadata.NewErrorRecord(
+			time.Now(),
+			"storage",
+			"LocalSink.WriteFormat",
+			mapStorageErrorToMetadataCause(storageError),
+			err.Error(),
+			[]metadata.Attribute{
+				metadata.NewAttr(metadata.AttrURL, result.SourceURL()),
+				metadata.NewAttr(metadata.AttrWritePath, storageError.Path),
+			},
+		))
+		return WriteResult{}, storageError
+	}
+
+	if s.debugLogger.Enabled() {
+		s.debugLogger.LogStep(context.TODO(), "storage", "write_format", debug.FieldMap{
+			"file_path":  path,
+			"format":     format,
+			"size_bytes": len(content),
+		})
+	}
+	s.metadataSink.RecordArtifact(metadata.NewArtifactRecord(
+		metadata.ArtifactOutputFormat,
+		path,
+		result.SourceURL(),
+		result.ContentHash(),
+		false,
+		int64(len(content)),
+		time.Now(),
+	))
+	return result.WithFormat(format, path), nil
+}
+
+// WriteFormat stores content under the path the format would be written to.
+func (m *MemoryWriter) WriteFormat(result WriteResult, format string, content []byte) (WriteResult, failure.ClassifiedError) {
+	path := FormatPath(result.Path(), format)
+	m.mu.Lock()
+	m.files[path] = append([]byte(nil), content...)
+	m.mu.Unlock()
+
+	if m.debugLogger.Enabled() {
+		m.debugLogger.LogStep(context.TODO(), "storage", "memory_write_format", debug.FieldMap{
+			"file_path":  path,
+			"format":     format,
+			"size_bytes": len(content),
+		})
+	}
+	return result.WithFormat(format, path), nil
+}
+
+// WriteFormat simulates writing an additional format; nothing is written.
+func (d *DryRunSink) WriteFormat(result WriteResult, format string, content []byte) (WriteResult, failure.ClassifiedError) {
+	path := FormatPath(result.Path(), format)
+	if d.debugLogger.Enabled() {
+		d.debugLogger.LogStep(context.TODO(), "storage", "dryrun_write_format", debug.FieldMap{
+			"file_path":  path,
+			"format":     format,
+			"size_bytes": len(content),
+			"dry_run":    true,
+		})
+	}
+	d.metadataSink.RecordArtifact(metadata.NewArtifactRecord(
+		metadata.ArtifactOutputFormat,
+		path,
+		result.SourceURL(),
+		result.ContentHash(),
+		false,
+		int64(len(content)),
+		time.Now(),
+	))
+	return result.WithFormat(format, path), nil
+}
+
+// WriteFormat writes the format through the per-page writer. Without one
+// there are no per-page files, and the format is not written.
+func (w *SingleFileWriter) WriteFormat(result WriteResult, format string, content []byte) (WriteResult, failure.ClassifiedError) {
+	if formatWriter, ok := w.perPage.(FormatWriter); ok {
+		return formatWriter.WriteFormat(result, format, content)
+	}
+	return result, nil
+}
@@ -0,0 +1,221 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+
+	"github.com/rohmanhakim/docs-crawler/pkg/failure"
+)
+
+/*
+Manifest
+
+A manifest is the list of WriteResults produced by a crawl run, persisted as
+outputDir/manifest.json at the end of the run:
+
+	[
+	  {"urlHash": "...", "url": "...", "path": "...", "contentHash": "...", "runId": "...", "language": "...", "formats": {"txt": "..."}, "aliases": ["..."]},
+	  ...
+	]
+
+Entries are identified by urlHash (derived from the canonical URL), so two
+manifests can be compared across runs to find pages that were added, removed,
+or whose content changed. url is the page's source URL; it is what diffs
+report and what a repair re-crawls. language is the language subdirectory
+the page was written to; it is omitted unless the output is partitioned by
+language. aliases is omitted unless other URLs were grouped under the entry
+because their content was identical. runId identifies the crawl run that
+wrote the page; it is derived from the run's configuration, so re-running
+the same configuration reproduces it. formats maps each additional output
+format the page was written in to its path; it is omitted when only
+Markdown was written.
+*/
+
+// ManifestFileName is the name of the manifest written into the output directory.
+const ManifestFileName = "manifest.json"
+
+type manifestEntryDTO struct {
+	URLHash        string            `json:"urlHash"`
+	URL            string            `json:"url,omitempty"`
+	Path           string            `json:"path"`
+	ContentHash    string            `json:"contentHash"`
+	StructuralHash string            `json:"structuralHash,omitempty"`
+	RunID          string            `json:"runId,omitempty"`
+	Language       string            `json:"language,omitempty"`
+	Formats        map[string]string `json:"formats,omitempty"`
+	Aliases        []string          `json:"aliases,omitempty"`
+}
+
+// ReadManifest loads a JSON manifest file into WriteResults.
+func ReadManifest(path string) ([]WriteResult, failure.ClassifiedError) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, NewStorageError(ErrCauseReadFailure, fmt.Sprintf("failed to read manifest: %v", err), path)
+	}
+
+	var entries []manifestEntryDTO
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, NewStorageError(ErrCauseManifestParseFailure, fmt.Sprintf("failed to parse manifest: %v", err), path)
+	}
+
+	results := make([]WriteResult, 0, len(entries))
+	for _, e := range entries {
+		result := NewWriteResult(e.URLHash, e.Path, e.ContentHash).WithStructuralHash(e.StructuralHash).WithSourceURL(e.URL).WithLanguage(e.Language).WithRunID(e.RunID)
+		for format, formatPath := range e.Formats {
+			result = result.WithFormat(format, formatPath)
+		}
+		for _, raw := range e.Aliases {
+			alias, err := url.Parse(raw)
+			if err != nil {
+				return nil, NewStorageError(ErrCauseManifestParseFailure, fmt.Sprintf("invalid alias %q: %v", raw, err), path)
+			}
+			result.AddAlias(*alias)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// WriteManifest persists WriteResults as a JSON manifest file.
+// Entries are sorted by urlHash so the file is byte-stable across runs.
+func WriteManifest(path string, results []WriteResult) failure.ClassifiedError {
+	data, err := encodeManifest(path, results)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return NewStorageError(ErrCauseWriteFailure, fmt.Sprintf("failed to write manifest: %v", err), path)
+	}
+	return nil
+}
+
+// encodeManifest renders results as the manifest JSON written to path.
+func encodeManifest(path string, results []WriteResult) ([]byte, failure.ClassifiedError) {
+	entries := make([]manifestEntryDTO, 0, len(results))
+	for _, r := range results {
+		var aliases []string
+		for _, alias := range r.aliases {
+			aliases = append(aliases, alias.String())
+		}
+		entries = append(entries, manifestEntryDTO{
+			URLHash:        r.urlHash,
+			URL:            r.sourceURL,
+			Path:           r.path,
+			ContentHash:    r.contentHash,
+			StructuralHash: r.structuralHash,
+			RunID:          r.runID,
+			Language:       r.language,
+			Formats:        r.Formats(),
+			Aliases:        aliases,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].URLHash < entries[j].URLHash
+	})
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return nil, NewStorageError(ErrCauseWriteFailure, fmt.Sprintf("failed to encode manifest: %v", err), path)
+	}
+	return append(data, '\n'), nil
+}
+
+// ManifestDiff holds the difference between two manifests.
+// Entries are matched by urlHash and reported by their source URL; entries
+// from manifests that predate source URLs are reported by urlHash instead.
+// Each bucket is sorted for deterministic output.
+type ManifestDiff struct {
+	added   []string
+	removed []string
+	changed []string
+}
+
+// Added returns URLs present only in the new manifest.
+func (d ManifestDiff) Added() []string {
+	return append([]string(nil), d.added...)
+}
+
+// Removed returns URLs present only in the old manifest.
+func (d ManifestDiff) Removed() []string {
+	return append([]string(nil), d.removed...)
+}
+
+// Changed returns URLs present in both manifests with a different content hash.
+func (d ManifestDiff) Changed() []string {
+	return append([]string(nil), d.changed...)
+}
+
+// IsEmpty reports whether the two manifests are equivalent.
+func (d ManifestDiff) IsEmpty() bool {
+	return len(d.added) == 0 && len(d.removed) == 0 && len(d.changed) == 0
+}
+
+// DiffManifests compares an old and a new manifest.
+// Entries present in both with an identical content hash land in no bucket.
+func DiffManifests(oldManifest, newManifest []WriteResult) ManifestDiff {
+	oldEntries := indexManifest(oldManifest)
+	newEntries := indexManifest(newManifest)
+
+	var diff ManifestDiff
+	for urlHash, entry := range newEntries {
+		oldEntry, existed := oldEntries[urlHash]
+		switch {
+		case !existed:
+			diff.added = append(diff.added, displayURL(entry))
+		case oldEntry.contentHash != entry.contentHash:
+			diff.changed = append(diff.changed, displayURL(entry))
+		}
+	}
+	for urlHash, entry := range oldEntries {
+		if _, exists := newEntries[urlHash]; !exists {
+			diff.removed = append(diff.removed, displayURL(entry))
+		}
+	}
+
+	sort.Strings(diff.added)
+	sort.Strings(diff.removed)
+	sort.Strings(diff.changed)
+	return diff
+}
+
+// MergeManifests returns base with every entry replaced by the entry of
+// updates with the same urlHash. Entries only present in updates are
+// appended. It is used to fold the results of a partial re-crawl back into
+// the manifest of the run it repaired.
+func MergeManifests(base, updates []WriteResult) []WriteResult {
+	updated := indexManifest(updates)
+	merged := make([]WriteResult, 0, len(base)+len(updates))
+	for _, r := range base {
+		if update, ok := updated[r.urlHash]; ok {
+			r = update
+			delete(updated, r.urlHash)
+		}
+		merged = append(merged, r)
+	}
+	for _, r := range updates {
+		if _, pending := updated[r.urlHash]; pending {
+			merged = append(merged, r)
+			delete(updated, r.urlHash)
+		}
+	}
+	return merged
+}
+
+func indexManifest(results []WriteResult) map[string]WriteResult {
+	entries := make(map[string]WriteResult, len(results))
+	for _, r := range results {
+		entries[r.urlHash] = r
+	}
+	return entries
+}
+
+// displayURL returns the source URL, or the urlHash when none was recorded.
+func displayURL(r WriteResult) string {
+	if r.sourceURL != "" {
+		return r.sourceURL
+	}
+	return r.urlHash
+}
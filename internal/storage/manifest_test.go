@@ -0,0 +1,300 @@
+package storage_test
+
+import (
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+)
+
+// manifestEntry builds a WriteResult for a page at https://example.com/<name>.
+func manifestEntry(name, contentHash string) storage.WriteResult {
+	return storage.NewWriteResult(name, "out/"+name+".md", contentHash).
+		WithSourceURL("https://example.com/" + name)
+}
+
+func TestDiffManifests_Buckets(t *testing.T) {
+	oldManifest := []storage.WriteResult{
+		manifestEntry("aaa", "sha256:1"), // unchanged
+		manifestEntry("bbb", "sha256:2"), // changed
+		manifestEntry("ccc", "sha256:3"), // removed
+		manifestEntry("ddd", "sha256:4"), // removed
+	}
+	newManifest := []storage.WriteResult{
+		manifestEntry("aaa", "sha256:1"),
+		manifestEntry("bbb", "sha256:22"),
+		manifestEntry("fff", "sha256:6"), // added
+		manifestEntry("eee", "sha256:5"), // added
+	}
+
+	diff := storage.DiffManifests(oldManifest, newManifest)
+
+	if got, want := diff.Added(), []string{"https://example.com/eee", "https://example.com/fff"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Added() = %v, want %v", got, want)
+	}
+	if got, want := diff.Removed(), []string{"https://example.com/ccc", "https://example.com/ddd"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Removed() = %v, want %v", got, want)
+	}
+	if got, want := diff.Changed(), []string{"https://example.com/bbb"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Changed() = %v, want %v", got, want)
+	}
+
+	// An entry present in both with an identical hash lands in no bucket
+	for _, bucket := range [][]string{diff.Added(), diff.Removed(), diff.Changed()} {
+		for _, u := range bucket {
+			if u == "https://example.com/aaa" {
+				t.Errorf("unchanged entry 'aaa' must not appear in any bucket")
+			}
+		}
+	}
+	if diff.IsEmpty() {
+		t.Error("expected non-empty diff")
+	}
+}
+
+func TestDiffManifests_Identical(t *testing.T) {
+	manifest := []storage.WriteResult{
+		storage.NewWriteResult("aaa", "out/aaa.md", "sha256:1"),
+		storage.NewWriteResult("bbb", "out/bbb.md", "sha256:2"),
+	}
+
+	diff := storage.DiffManifests(manifest, manifest)
+
+	if !diff.IsEmpty() {
+		t.Errorf("expected empty diff, got added=%v removed=%v changed=%v",
+			diff.Added(), diff.Removed(), diff.Changed())
+	}
+}
+
+func TestDiffManifests_EmptyOld(t *testing.T) {
+	newManifest := []storage.WriteResult{
+		manifestEntry("aaa", "sha256:1"),
+	}
+
+	diff := storage.DiffManifests(nil, newManifest)
+
+	if got, want := diff.Added(), []string{"https://example.com/aaa"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Added() = %v, want %v", got, want)
+	}
+	if len(diff.Removed()) != 0 || len(diff.Changed()) != 0 {
+		t.Errorf("expected no removed/changed, got removed=%v changed=%v", diff.Removed(), diff.Changed())
+	}
+}
+
+func TestDiffManifests_WithoutSourceURLFallsBackToURLHash(t *testing.T) {
+	// Manifests written before source URLs were recorded only carry urlHash
+	oldManifest := []storage.WriteResult{
+		storage.NewWriteResult("aaa", "out/aaa.md", "sha256:1"),
+	}
+	newManifest := []storage.WriteResult{
+		manifestEntry("aaa", "sha256:2"),
+		storage.NewWriteResult("bbb", "out/bbb.md", "sha256:3"),
+	}
+
+	diff := storage.DiffManifests(oldManifest, newManifest)
+
+	if got, want := diff.Changed(), []string{"https://example.com/aaa"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Changed() = %v, want %v", got, want)
+	}
+	if got, want := diff.Added(), []string{"bbb"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Added() = %v, want %v", got, want)
+	}
+}
+
+func TestMergeManifests_ReplacesByURLHashAndAppendsNew(t *testing.T) {
+	base := []storage.WriteResult{
+		manifestEntry("aaa", "sha256:1"),
+		manifestEntry("bbb", "sha256:2"),
+		manifestEntry("ccc", "sha256:3"),
+	}
+	updates := []storage.WriteResult{
+		manifestEntry("ddd", "sha256:4"),
+		manifestEntry("bbb", "sha256:2b"),
+	}
+
+	merged := storage.MergeManifests(base, updates)
+
+	expected := []storage.WriteResult{
+		manifestEntry("aaa", "sha256:1"),
+		manifestEntry("bbb", "sha256:2b"),
+		manifestEntry("ccc", "sha256:3"),
+		manifestEntry("ddd", "sha256:4"),
+	}
+	if !reflect.DeepEqual(merged, expected) {
+		t.Errorf("unexpected merge result.\nexpected: %+v\ngot: %+v", expected, merged)
+	}
+}
+
+func TestManifest_WriteReadRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	results := []storage.WriteResult{
+		manifestEntry("bbb", "sha256:2"),
+		manifestEntry("aaa", "sha256:1"),
+	}
+
+	if err := storage.WriteManifest(path, results); err != nil {
+		t.Fatalf("WriteManifest failed: %v", err)
+	}
+
+	loaded, err := storage.ReadManifest(path)
+	if err != nil {
+		t.Fatalf("ReadManifest failed: %v", err)
+	}
+	if len(loaded) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(loaded))
+	}
+	// Entries are written sorted by urlHash
+	if loaded[0].URLHash() != "aaa" || loaded[0].Path() != "out/aaa.md" || loaded[0].ContentHash() != "sha256:1" {
+		t.Errorf("unexpected first entry: %+v", loaded[0])
+	}
+	if loaded[0].SourceURL() != "https://example.com/aaa" {
+		t.Errorf("SourceURL() = %q, want https://example.com/aaa", loaded[0].SourceURL())
+	}
+	if loaded[1].URLHash() != "bbb" {
+		t.Errorf("unexpected second entry: %+v", loaded[1])
+	}
+}
+
+func TestManifest_AliasesRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	result := storage.NewWriteResult("aaa", "out/aaa.md", "sha256:1")
+	result.AddAlias(url.URL{Scheme: "https", Host: "example.com", Path: "/copy"})
+	result.AddAlias(url.URL{Scheme: "https", Host: "example.com", Path: "/mirror"})
+
+	if err := storage.WriteManifest(path, []storage.WriteResult{result}); err != nil {
+		t.Fatalf("WriteManifest failed: %v", err)
+	}
+	loaded, err := storage.ReadManifest(path)
+	if err != nil {
+		t.Fatalf("ReadManifest failed: %v", err)
+	}
+	if len(loaded) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(loaded))
+	}
+
+	var got []string
+	for _, alias := range loaded[0].Aliases() {
+		got = append(got, alias.String())
+	}
+	want := []string{"https://example.com/copy", "https://example.com/mirror"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Aliases() = %v, want %v", got, want)
+	}
+}
+
+func TestManifest_LanguageRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	results := []storage.WriteResult{
+		storage.NewWriteResult("aaa", "out/fr/aaa.md", "sha256:1").WithLanguage("fr"),
+		storage.NewWriteResult("bbb", "out/bbb.md", "sha256:2"),
+	}
+
+	if err := storage.WriteManifest(path, results); err != nil {
+		t.Fatalf("WriteManifest failed: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if got := strings.Count(string(data), `"language"`); got != 1 {
+		t.Errorf("expected language only on the partitioned entry, got %d in:\n%s", got, data)
+	}
+	loaded, err := storage.ReadManifest(path)
+	if err != nil {
+		t.Fatalf("ReadManifest failed: %v", err)
+	}
+	if loaded[0].Language() != "fr" || loaded[1].Language() != "" {
+		t.Errorf("expected languages [fr, \"\"], got [%q, %q]", loaded[0].Language(), loaded[1].Language())
+	}
+}
+
+func TestManifest_RunIDRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	results := []storage.WriteResult{
+		storage.NewWriteResult("aaa", "out/aaa.md", "sha256:1").WithRunID("0123456789abcdef"),
+	}
+
+	if err := storage.WriteManifest(path, results); err != nil {
+		t.Fatalf("WriteManifest failed: %v", err)
+	}
+	loaded, err := storage.ReadManifest(path)
+	if err != nil {
+		t.Fatalf("ReadManifest failed: %v", err)
+	}
+	if loaded[0].RunID() != "0123456789abcdef" {
+		t.Errorf("expected run ID 0123456789abcdef, got %q", loaded[0].RunID())
+	}
+}
+
+func TestManifest_StructuralHashRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	results := []storage.WriteResult{
+		storage.NewWriteResult("aaa", "out/aaa.md", "sha256:1").WithStructuralHash("sha256:2"),
+	}
+
+	if err := storage.WriteManifest(path, results); err != nil {
+		t.Fatalf("WriteManifest failed: %v", err)
+	}
+	loaded, err := storage.ReadManifest(path)
+	if err != nil {
+		t.Fatalf("ReadManifest failed: %v", err)
+	}
+	if loaded[0].StructuralHash() != "sha256:2" {
+		t.Errorf("expected structural hash sha256:2, got %q", loaded[0].StructuralHash())
+	}
+}
+
+func TestManifest_FormatsRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	results := []storage.WriteResult{
+		storage.NewWriteResult("aaa", "out/aaa.md", "sha256:1").WithFormat("txt", "out/aaa.txt"),
+		storage.NewWriteResult("bbb", "out/bbb.md", "sha256:2"),
+	}
+
+	if err := storage.WriteManifest(path, results); err != nil {
+		t.Fatalf("WriteManifest failed: %v", err)
+	}
+	loaded, err := storage.ReadManifest(path)
+	if err != nil {
+		t.Fatalf("ReadManifest failed: %v", err)
+	}
+	if got := loaded[0].Formats()["txt"]; got != "out/aaa.txt" {
+		t.Errorf("expected txt format at out/aaa.txt, got %q", got)
+	}
+	if loaded[1].Formats() != nil {
+		t.Errorf("expected no formats, got %v", loaded[1].Formats())
+	}
+}
+
+func TestWriteResult_AliasesReturnsCopy(t *testing.T) {
+	result := storage.NewWriteResult("aaa", "out/aaa.md", "sha256:1")
+	result.AddAlias(url.URL{Scheme: "https", Host: "example.com", Path: "/copy"})
+
+	aliases := result.Aliases()
+	aliases[0].Path = "/changed"
+
+	if got := result.Aliases()[0].Path; got != "/copy" {
+		t.Errorf("expected alias unchanged after mutating the returned slice, got %q", got)
+	}
+}
+
+func TestReadManifest_Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := storage.ReadManifest(filepath.Join(dir, "missing.json"))
+	var storageErr *storage.StorageError
+	if !errors.As(err, &storageErr) || storageErr.Cause != storage.ErrCauseReadFailure {
+		t.Errorf("expected ErrCauseReadFailure, got %v", err)
+	}
+
+	malformed := filepath.Join(dir, "malformed.json")
+	if writeErr := os.WriteFile(malformed, []byte("{not json"), 0644); writeErr != nil {
+		t.Fatal(writeErr)
+	}
+	_, err = storage.ReadManifest(malformed)
+	if !errors.As(err, &storageErr) || storageErr.Cause != storage.ErrCauseManifestParseFailure {
+		t.Errorf("expected ErrCauseManifestParseFailure, got %v", err)
+	}
+}
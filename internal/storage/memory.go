@@ -0,0 +1,175 @@
+package storage
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/rohmanhakim/docs-crawler/internal/normalize"
+	"github.com/rohmanhakim/docs-crawler/pkg/debug"
+	"github.com/rohmanhakim/docs-crawler/pkg/failure"
+	"github.com/rohmanhakim/docs-crawler/pkg/hashutil"
+)
+
+/*
+MemoryWriter is a storage implementation that keeps written documents in
+memory instead of on disk.
+
+It follows the same contract as LocalSink:
+- Computes the same deterministic path (outputDir/<url_hash>.md)
+- Stores the chunk sidecar (outputDir/<url_hash>.chunks.json) of chunked pages
+  and the provenance sidecar (outputDir/<url_hash>.provenance.json) of pages
+  normalized with provenance
+- Overwrites on rewrite, so the same canonical URL maps to one entry
+- Returns WriteResult
+
+This lets programs embedding the crawler, and tests, read results
+without touching the filesystem.
+*/
+
+type MemoryWriter struct {
+	mu          sync.RWMutex
+	files       map[string][]byte
+	debugLogger debug.DebugLogger
+}
+
+func NewMemoryWriter() *MemoryWriter {
+	return &MemoryWriter{
+		files:       make(map[string][]byte),
+		debugLogger: debug.NewNoOpLogger(),
+	}
+}
+
+// SetDebugLogger sets the debug logger for the writer.
+// This is optional and defaults to NoOpLogger.
+// If logger is nil, NoOpLogger is used as a safe default.
+func (m *MemoryWriter) SetDebugLogger(logger debug.DebugLogger) {
+	if logger == nil {
+		m.debugLogger = debug.NewNoOpLogger()
+		return
+	}
+	m.debugLogger = logger
+}
+
+// Write stores the document content under its deterministic path.
+func (m *MemoryWriter) Write(
+	outputDir string,
+	normalizedDoc normalize.NormalizedMarkdownDoc,
+	hashAlgo hashutil.HashAlgo,
+) (WriteResult, failure.ClassifiedError) {
+	canonicalURL := normalizedDoc.Frontmatter().CanonicalURL()
+
+	urlHashFull, err := hashutil.HashBytes([]byte(canonicalURL), hashAlgo)
+	if err != nil {
+		if m.debugLogger.Enabled() {
+			m.debugLogger.LogStep(context.TODO(), "storage", "write_failed", debug.FieldMap{
+				"error_cause": string(ErrCauseHashComputationFailed),
+				"error_msg":   err.Error(),
+			})
+		}
+		return WriteResult{}, NewStorageError(
+			ErrCauseHashComputationFailed,
+			err.Error(),
+			"",
+		)
+	}
+
+	// Use first 12 hex characters for filename (same as LocalSink)
+	urlHash := urlHashFull[:12]
+	fullPath := filepath.Join(outputDir, urlHash+".md")
+
+	content := append([]byte(nil), normalizedDoc.Content()...)
+	var sidecar []byte
+	if chunks := normalizedDoc.Chunks(); len(chunks) > 0 {
+		data, encodeErr := encodeChunks(chunksPath(fullPath), chunks)
+		if encodeErr != nil {
+			return WriteResult{}, encodeErr
+		}
+		sidecar = data
+	}
+	provenance, encodeErr := encodeProvenance(provenancePath(fullPath), normalizedDoc.Frontmatter())
+	if encodeErr != nil {
+		return WriteResult{}, encodeErr
+	}
+
+	m.mu.Lock()
+	m.files[fullPath] = content
+	if sidecar != nil {
+		m.files[chunksPath(fullPath)] = sidecar
+	} else {
+		delete(m.files, chunksPath(fullPath))
+	}
+	if provenance != nil {
+		m.files[provenancePath(fullPath)] = provenance
+	} else {
+		delete(m.files, provenancePath(fullPath))
+	}
+	m.mu.Unlock()
+
+	if m.debugLogger.Enabled() {
+		m.debugLogger.LogStep(context.TODO(), "storage", "memory_write", debug.FieldMap{
+			"file_path":  fullPath,
+			"size_bytes": len(content),
+			"url_hash":   urlHash,
+		})
+	}
+
+	return NewWriteResult(urlHash, fullPath, normalizedDoc.Frontmatter().ContentHash()).
+		WithSourceURL(normalizedDoc.Frontmatter().SourceURL()).
+		WithStructuralHash(normalizedDoc.Frontmatter().StructuralHash()), nil
+}
+
+// WriteManifest stores the encoded crawl manifest under outputDir/manifest.json.
+func (m *MemoryWriter) WriteManifest(outputDir string, results []WriteResult) failure.ClassifiedError {
+	path := filepath.Join(outputDir, ManifestFileName)
+	data, err := encodeManifest(path, results)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.files[path] = data
+	m.mu.Unlock()
+
+	if m.debugLogger.Enabled() {
+		m.debugLogger.LogStep(context.TODO(), "storage", "memory_write_manifest", debug.FieldMap{
+			"file_path": path,
+			"entries":   len(results),
+		})
+	}
+	return nil
+}
+
+// WriteConfigSnapshot stores the config snapshot under
+// outputDir/effective-config.json.
+func (m *MemoryWriter) WriteConfigSnapshot(outputDir string, snapshot []byte) failure.ClassifiedError {
+	path := filepath.Join(outputDir, ConfigSnapshotFileName)
+	m.mu.Lock()
+	m.files[path] = append([]byte(nil), snapshot...)
+	m.mu.Unlock()
+	return nil
+}
+
+// Get returns a copy of the content stored at path.
+func (m *MemoryWriter) Get(path string) ([]byte, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	content, ok := m.files[path]
+	if !ok {
+		return nil, false
+	}
+	return append([]byte(nil), content...), true
+}
+
+// Paths returns every stored path in sorted order.
+func (m *MemoryWriter) Paths() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	paths := make([]string, 0, len(m.files))
+	for path := range m.files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
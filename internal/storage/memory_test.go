@@ -0,0 +1,171 @@
+package storage_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/normalize"
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/rohmanhakim/docs-crawler/pkg/hashutil"
+)
+
+var _ storage.Writer = (*storage.MemoryWriter)(nil)
+
+func TestMemoryWriter_Write_MultiplePages(t *testing.T) {
+	writer := storage.NewMemoryWriter()
+	pages := []struct {
+		canonicalURL string
+		content      string
+	}{
+		{"https://example.com/docs/a", "# A\n\nPage A."},
+		{"https://example.com/docs/b", "# B\n\nPage B."},
+		{"https://example.com/docs/c", "# C\n\nPage C."},
+	}
+
+	var wantPaths []string
+	for _, p := range pages {
+		doc := createTestNormalizedDoc(p.canonicalURL, p.canonicalURL, "hash-"+p.canonicalURL, []byte(p.content))
+		result, err := writer.Write("out", doc, hashutil.HashAlgoSHA256)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expectedHash := computeExpectedURLHash(p.canonicalURL, hashutil.HashAlgoSHA256)
+		expectedPath := filepath.Join("out", expectedHash+".md")
+		if result.Path() != expectedPath {
+			t.Errorf("expected path %s, got %s", expectedPath, result.Path())
+		}
+		if result.URLHash() != expectedHash {
+			t.Errorf("expected URLHash %s, got %s", expectedHash, result.URLHash())
+		}
+
+		got, ok := writer.Get(expectedPath)
+		if !ok {
+			t.Fatalf("expected content at %s", expectedPath)
+		}
+		if string(got) != p.content {
+			t.Errorf("expected content %q, got %q", p.content, got)
+		}
+		wantPaths = append(wantPaths, expectedPath)
+	}
+
+	paths := writer.Paths()
+	if len(paths) != len(wantPaths) {
+		t.Fatalf("expected %d paths, got %d: %v", len(wantPaths), len(paths), paths)
+	}
+	for i := 1; i < len(paths); i++ {
+		if paths[i-1] > paths[i] {
+			t.Errorf("expected sorted paths, got %v", paths)
+		}
+	}
+	for _, want := range wantPaths {
+		if _, ok := writer.Get(want); !ok {
+			t.Errorf("expected %s in Paths()", want)
+		}
+	}
+
+	// Nothing is written to disk
+	if _, err := os.Stat("out"); !os.IsNotExist(err) {
+		t.Errorf("expected no output directory on disk, stat err: %v", err)
+	}
+}
+
+func TestMemoryWriter_Write_SameCanonicalURLDeduplicates(t *testing.T) {
+	writer := storage.NewMemoryWriter()
+	canonicalURL := "https://example.com/docs/page"
+
+	first := createTestNormalizedDoc("https://example.com/docs/page?a=1", canonicalURL, "h1", []byte("first"))
+	second := createTestNormalizedDoc("https://example.com/docs/page#x", canonicalURL, "h2", []byte("second"))
+
+	r1, err := writer.Write("out", first, hashutil.HashAlgoSHA256)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r2, err := writer.Write("out", second, hashutil.HashAlgoSHA256)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if r1.Path() != r2.Path() {
+		t.Errorf("expected same path for same canonical URL, got %s and %s", r1.Path(), r2.Path())
+	}
+	if len(writer.Paths()) != 1 {
+		t.Errorf("expected 1 stored path, got %v", writer.Paths())
+	}
+	got, _ := writer.Get(r2.Path())
+	if string(got) != "second" {
+		t.Errorf("expected latest write to win, got %q", got)
+	}
+}
+
+func TestMemoryWriter_Get_MissingAndCopy(t *testing.T) {
+	writer := storage.NewMemoryWriter()
+	if _, ok := writer.Get("missing.md"); ok {
+		t.Error("expected missing path to report false")
+	}
+
+	doc := createTestNormalizedDoc("https://example.com/x", "https://example.com/x", "h", []byte("original"))
+	result, _ := writer.Write("", doc, hashutil.HashAlgoSHA256)
+
+	got, _ := writer.Get(result.Path())
+	got[0] = 'X'
+	again, _ := writer.Get(result.Path())
+	if string(again) != "original" {
+		t.Errorf("expected stored content to be unaffected by caller mutation, got %q", again)
+	}
+}
+
+func TestMemoryWriter_Write_ChunkSidecar(t *testing.T) {
+	writer := storage.NewMemoryWriter()
+	content := []byte("# Page\n\nFirst paragraph.\n\nSecond paragraph.\n")
+	doc := createChunkedTestNormalizedDoc("https://example.com/docs/chunked", content, 30)
+
+	result, err := writer.Write("out", doc, hashutil.HashAlgoSHA256)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sidecar := filepath.Join("out", result.URLHash()+storage.ChunksFileSuffix)
+	data, ok := writer.Get(sidecar)
+	if !ok {
+		t.Fatalf("expected chunk sidecar at %s, got paths %v", sidecar, writer.Paths())
+	}
+	if !strings.Contains(string(data), doc.Chunks()[1].ID()) {
+		t.Errorf("expected sidecar to list chunk %s, got:\n%s", doc.Chunks()[1].ID(), data)
+	}
+}
+
+func TestMemoryWriter_Write_StructuralHash(t *testing.T) {
+	writer := storage.NewMemoryWriter()
+	write := func(pageURL, content string) storage.WriteResult {
+		t.Helper()
+		doc, err := normalize.RefreshFrontmatter(createTestNormalizedDoc(pageURL, pageURL, "", []byte(content)), hashutil.HashAlgoSHA256)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		result, err := writer.Write("out", doc, hashutil.HashAlgoSHA256)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return result
+	}
+
+	original := write("https://example.com/docs/a", "# A\n\n## Usage\n\nRun it. See [B](/docs/b).")
+	reworded := write("https://example.com/docs/a2", "# A\n\n## Usage\n\nJust run it, then see [page B](https://example.com/docs/b).")
+	restructured := write("https://example.com/docs/a3", "# A\n\n## Usage\n\nRun it.\n\n## Options\n\nSee [B](/docs/b).")
+
+	if original.StructuralHash() == "" {
+		t.Fatal("expected a structural hash")
+	}
+	if reworded.StructuralHash() != original.StructuralHash() {
+		t.Errorf("expected the reworded page to share the structural hash %q, got %q", original.StructuralHash(), reworded.StructuralHash())
+	}
+	if reworded.ContentHash() == original.ContentHash() {
+		t.Error("expected the reworded page to have another content hash")
+	}
+	if restructured.StructuralHash() == original.StructuralHash() {
+		t.Error("expected a changed heading outline to change the structural hash")
+	}
+}
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/rohmanhakim/docs-crawler/internal/normalize"
+	"github.com/rohmanhakim/docs-crawler/pkg/failure"
+)
+
+/*
+Provenance Sidecar
+
+When a page was normalized with provenance (see
+normalize.NormalizeParam.WithProvenance), how it was reached is written
+next to the page as outputDir/<url_hash>.provenance.json:
+
+	{
+	  "sourceUrl": "https://example.com/docs/new",
+	  "crawlDepth": 1,
+	  "referrer": "https://example.com/docs",
+	  "redirectChain": ["https://example.com/docs/old", "https://example.com/docs/new"]
+	}
+
+referrer is left out for seeds, redirectChain when the fetch was not
+redirected. Pages without provenance get no sidecar.
+*/
+
+// ProvenanceFileSuffix replaces the .md extension of a page for its
+// provenance sidecar.
+const ProvenanceFileSuffix = ".provenance.json"
+
+type provenanceDTO struct {
+	SourceURL     string   `json:"sourceUrl"`
+	CrawlDepth    int      `json:"crawlDepth"`
+	Referrer      string   `json:"referrer,omitempty"`
+	RedirectChain []string `json:"redirectChain,omitempty"`
+}
+
+// provenancePath returns the sidecar path for the page written to
+// markdownPath.
+func provenancePath(markdownPath string) string {
+	return strings.TrimSuffix(markdownPath, ".md") + ProvenanceFileSuffix
+}
+
+// encodeProvenance renders the provenance of frontmatter as the sidecar
+// JSON written to path, or returns nil when it has none.
+func encodeProvenance(path string, frontmatter normalize.Frontmatter) ([]byte, failure.ClassifiedError) {
+	provenance, ok := frontmatter.Provenance()
+	if !ok {
+		return nil, nil
+	}
+	data, err := json.MarshalIndent(provenanceDTO{
+		SourceURL:     frontmatter.SourceURL(),
+		CrawlDepth:    frontmatter.CrawlDepth(),
+		Referrer:      provenance.Referrer(),
+		RedirectChain: provenance.RedirectChain(),
+	}, "", "  ")
+	if err != nil {
+		return nil, NewStorageError(ErrCauseWriteFailure, fmt.Sprintf("failed to encode provenance: %v", err), path)
+	}
+	return append(data, '\n'), nil
+}
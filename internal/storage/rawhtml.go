@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/rohmanhakim/docs-crawler/pkg/failure"
+	"github.com/rohmanhakim/docs-crawler/pkg/fileutil"
+	"github.com/rohmanhakim/docs-crawler/pkg/hashutil"
+)
+
+/*
+Raw HTML Archive
+
+With config.SaveRawHTML, the fetched body of every page is saved unchanged
+to outputDir/_raw/<url_hash>.html, where <url_hash> is the same 12 hex
+characters that name the page's .md file. The copy is taken before
+extraction, so pages that fail to extract are saved too.
+*/
+
+// RawHTMLDir is the subdirectory of the output directory raw pages are
+// saved to.
+const RawHTMLDir = "_raw"
+
+// RawHTMLPath returns the path the raw body of the page with canonicalURL
+// is saved to in outputDir.
+func RawHTMLPath(outputDir string, canonicalURL string, hashAlgo hashutil.HashAlgo) (string, failure.ClassifiedError) {
+	urlHashFull, err := hashutil.HashBytes([]byte(canonicalURL), hashAlgo)
+	if err != nil {
+		return "", NewStorageError(ErrCauseHashComputationFailed, err.Error(), "")
+	}
+	return filepath.Join(outputDir, RawHTMLDir, urlHashFull[:12]+".html"), nil
+}
+
+// WriteRawHTML writes body to path, creating its directory if needed.
+func WriteRawHTML(path string, body []byte) failure.ClassifiedError {
+	if err := fileutil.EnsureDir(filepath.Dir(path)); err != nil {
+		return NewStorageError(ErrCausePathError, err.Error(), filepath.Dir(path))
+	}
+	if err := os.WriteFile(path, body, 0644); err != nil {
+		return NewStorageError(writeFailureCause(err), err.Error(), path)
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/internal/metadata"
+	"github.com/rohmanhakim/docs-crawler/internal/normalize"
+	"github.com/rohmanhakim/docs-crawler/pkg/debug"
+	"github.com/rohmanhakim/docs-crawler/pkg/failure"
+	"github.com/rohmanhakim/docs-crawler/pkg/fileutil"
+	"github.com/rohmanhakim/docs-crawler/pkg/hashutil"
+)
+
+/*
+Single-File Export
+
+SingleFileWriter appends every written page to one combined Markdown file,
+in the order the pages are written, so a crawl can be imported or reviewed
+as a single document. Each page becomes a section:
+
+	<a id="page-<url_hash>"></a>
+
+	> Source: <source_url>
+
+	<page content>
+
+Sections are separated by a thematic break (---). The anchor uses the same
+URL hash as the page file name, so links into the combined file stay
+stable across runs. The file is truncated by the first write of a run;
+Restart starts a new run with the same writer, and Keep appends a page
+whose per-page files are kept from an earlier run.
+A relative path is resolved against the output directory of each write, so
+output partitioned into subdirectories gets one combined file per
+subdirectory.
+
+With a per-page Writer the pages are also written as usual and the
+combined file comes on top; without one the combined file replaces them,
+and every WriteResult points at it.
+*/
+
+// SingleFileWriter is a Writer that appends pages to a combined file.
+type SingleFileWriter struct {
+	metadataSink metadata.MetadataSink
+	path         string
+	perPage      Writer          // nil when the combined file replaces per-page files
+	started      map[string]bool // combined files written to in this run
+	debugLogger  debug.DebugLogger
+}
+
+// NewSingleFileWriter creates a SingleFileWriter appending to path, which is
+// resolved against the output directory when relative. perPage, when not
+// nil, also receives every write.
+func NewSingleFileWriter(
+	metadataSink metadata.MetadataSink,
+	path string,
+	perPage Writer,
+) *SingleFileWriter {
+	return &SingleFileWriter{
+		metadataSink: metadataSink,
+		path:         path,
+		perPage:      perPage,
+		started:      make(map[string]bool),
+		debugLogger:  debug.NewNoOpLogger(),
+	}
+}
+
+// SetDebugLogger sets the debug logger for the writer.
+// This is optional and defaults to NoOpLogger.
+// If logger is nil, NoOpLogger is used as a safe default.
+func (w *SingleFileWriter) SetDebugLogger(logger debug.DebugLogger) {
+	if logger == nil {
+		w.debugLogger = debug.NewNoOpLogger()
+		return
+	}
+	w.debugLogger = logger
+}
+
+// Write writes the page through the per-page writer, if any, then appends
+// it to the combined file.
+func (w *SingleFileWriter) Write(
+	outputDir string,
+	normalizedDoc normalize.NormalizedMarkdownDoc,
+	hashAlgo hashutil.HashAlgo,
+) (WriteResult, failure.ClassifiedError) {
+	combinedPath := w.combinedPath(outputDir)
+
+	urlHash, hashErr := sectionURLHash(normalizedDoc, hashAlgo)
+	if hashErr != nil {
+		return WriteResult{}, hashErr
+	}
+
+	var writeResult WriteResult
+	if w.perPage != nil {
+		result, err := w.perPage.Write(outputDir, normalizedDoc, hashAlgo)
+		if err != nil {
+			return WriteResult{}, err
+		}
+		writeResult = result
+	} else {
+		writeResult = NewWriteResult(urlHash, combinedPath, normalizedDoc.Frontmatter().ContentHash()).
+			WithSourceURL(normalizedDoc.Frontmatter().SourceURL()).
+			WithStructuralHash(normalizedDoc.Frontmatter().StructuralHash())
+	}
+
+	if err := w.appendPage(combinedPath, urlHash, normalizedDoc); err != nil {
+		return WriteResult{}, err
+	}
+
+	if w.perPage == nil {
+		w.metadataSink.RecordArtifact(metadata.NewArtifactRecord(
+			metadata.ArtifactMarkdown,
+			combinedPath,
+			normalizedDoc.Frontmatter().SourceURL(),
+			writeResult.ContentHash(),
+			false,
+			int64(len(normalizedDoc.Content())),
+			time.Now(),
+		))
+	}
+
+	return writeResult, nil
+}
+
+// Keep appends the page to the combined file only, for a page whose
+// per-page files are kept from an earlier run of the same writer.
+func (w *SingleFileWriter) Keep(
+	outputDir string,
+	normalizedDoc normalize.NormalizedMarkdownDoc,
+	hashAlgo hashutil.HashAlgo,
+) failure.ClassifiedError {
+	urlHash, err := sectionURLHash(normalizedDoc, hashAlgo)
+	if err != nil {
+		return err
+	}
+	if err := w.appendPage(w.combinedPath(outputDir), urlHash, normalizedDoc); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Restart starts a new run: the next write to each combined file truncates
+// it again.
+func (w *SingleFileWriter) Restart() {
+	w.started = make(map[string]bool)
+}
+
+// sectionURLHash returns the URL hash of the page's section anchor, the
+// first 12 hex characters of the hash also naming the page file.
+func sectionURLHash(normalizedDoc normalize.NormalizedMarkdownDoc, hashAlgo hashutil.HashAlgo) (string, *StorageError) {
+	urlHashFull, err := hashutil.HashBytes([]byte(normalizedDoc.Frontmatter().CanonicalURL()), hashAlgo)
+	if err != nil {
+		return "", NewStorageError(ErrCauseHashComputationFailed, err.Error(), "")
+	}
+	return urlHashFull[:12], nil
+}
+
+// appendPage appends the page's section to the combined file at path,
+// recording any failure.
+func (w *SingleFileWriter) appendPage(path string, urlHash string, normalizedDoc normalize.NormalizedMarkdownDoc) *StorageError {
+	section := renderSection(urlHash, normalizedDoc, w.started[path])
+	if err := w.appendSection(path, section); err != nil {
+		w.metadataSink.RecordError(metadata.NewErrorRecord(
+			time.Now(),
+			"storage",
+			"SingleFileWriter.Write",
+			mapStorageErrorToMetadataCause(err),
+			err.Error(),
+			[]metadata.Attribute{
+				metadata.NewAttr(metadata.AttrURL, normalizedDoc.Frontmatter().SourceURL()),
+				metadata.NewAttr(metadata.AttrWritePath, err.Path),
+			},
+		))
+		return err
+	}
+	w.started[path] = true
+
+	if w.debugLogger.Enabled() {
+		w.debugLogger.LogStep(context.TODO(), "storage", "single_file_append", debug.FieldMap{
+			"file_path":  path,
+			"size_bytes": len(section),
+			"url_hash":   urlHash,
+		})
+	}
+	return nil
+}
+
+// WriteManifest writes the manifest through the per-page writer, or to
+// outputDir/manifest.json when the combined file replaces it.
+func (w *SingleFileWriter) WriteManifest(outputDir string, results []WriteResult) failure.ClassifiedError {
+	if w.perPage != nil {
+		return w.perPage.WriteManifest(outputDir, results)
+	}
+	if err := fileutil.EnsureDir(outputDir); err != nil {
+		return NewStorageError(ErrCausePathError, err.Error(), outputDir)
+	}
+	return WriteManifest(filepath.Join(outputDir, ManifestFileName), results)
+}
+
+// WriteConfigSnapshot writes the config snapshot through the per-page
+// writer, or to outputDir/effective-config.json.
+func (w *SingleFileWriter) WriteConfigSnapshot(outputDir string, snapshot []byte) failure.ClassifiedError {
+	if w.perPage != nil {
+		return w.perPage.WriteConfigSnapshot(outputDir, snapshot)
+	}
+	return writeConfigSnapshot(outputDir, snapshot)
+}
+
+// combinedPath resolves the combined file path against outputDir.
+func (w *SingleFileWriter) combinedPath(outputDir string) string {
+	if filepath.IsAbs(w.path) {
+		return w.path
+	}
+	return filepath.Join(outputDir, w.path)
+}
+
+// appendSection appends section to the combined file, truncating it first
+// on the first write of the run.
+func (w *SingleFileWriter) appendSection(path string, section []byte) *StorageError {
+	if err := fileutil.EnsureDir(filepath.Dir(path)); err != nil {
+		return NewStorageError(ErrCausePathError, err.Error(), path)
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	if !w.started[path] {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	file, err := os.OpenFile(path, flags, 0644)
+	if err != nil {
+		return NewStorageError(writeFailureCause(err), err.Error(), path)
+	}
+	_, writeErr := file.Write(section)
+	closeErr := file.Close()
+	if err := errors.Join(writeErr, closeErr); err != nil {
+		return NewStorageError(writeFailureCause(err), err.Error(), path)
+	}
+	return nil
+}
+
+// renderSection renders one page of the combined file. Every page after
+// the first is preceded by a thematic break.
+func renderSection(urlHash string, normalizedDoc normalize.NormalizedMarkdownDoc, separated bool) []byte {
+	var b strings.Builder
+	if separated {
+		b.WriteString("\n---\n\n")
+	}
+	fmt.Fprintf(&b, "<a id=\"page-%s\"></a>\n\n", urlHash)
+	fmt.Fprintf(&b, "> Source: <%s>\n\n", normalizedDoc.Frontmatter().SourceURL())
+	b.WriteString(strings.TrimRight(string(normalizedDoc.Content()), "\n"))
+	b.WriteString("\n")
+	return []byte(b.String())
+}
@@ -0,0 +1,138 @@
+package storage_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/rohmanhakim/docs-crawler/pkg/hashutil"
+)
+
+func TestSingleFileWriter_Write_AppendsSectionsInWriteOrder(t *testing.T) {
+	outputDir := t.TempDir()
+	perPage := storage.NewMemoryWriter()
+	writer := storage.NewSingleFileWriter(&metadataSinkMock{}, "combined.md", perPage)
+
+	first := createTestNormalizedDoc("https://example.com/b", "https://example.com/b", "sha256:b", []byte("# B\n\nSecond URL, written first.\n"))
+	second := createTestNormalizedDoc("https://example.com/a", "https://example.com/a", "sha256:a", []byte("# A\n\nWritten second.\n"))
+	firstResult, err := writer.Write(outputDir, first, hashutil.HashAlgoSHA256)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if _, err := writer.Write(outputDir, second, hashutil.HashAlgoSHA256); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	got, readErr := os.ReadFile(filepath.Join(outputDir, "combined.md"))
+	if readErr != nil {
+		t.Fatalf("expected the combined file, got: %v", readErr)
+	}
+	expected := "<a id=\"page-" + computeExpectedURLHash("https://example.com/b", hashutil.HashAlgoSHA256) + "\"></a>\n\n" +
+		"> Source: <https://example.com/b>\n\n" +
+		"# B\n\nSecond URL, written first.\n" +
+		"\n---\n\n" +
+		"<a id=\"page-" + computeExpectedURLHash("https://example.com/a", hashutil.HashAlgoSHA256) + "\"></a>\n\n" +
+		"> Source: <https://example.com/a>\n\n" +
+		"# A\n\nWritten second.\n"
+	if string(got) != expected {
+		t.Errorf("unexpected combined file:\n%s\nexpected:\n%s", got, expected)
+	}
+
+	// Per-page files are still written and reported
+	if len(perPage.Paths()) != 2 {
+		t.Errorf("expected 2 per-page files, got %v", perPage.Paths())
+	}
+	if firstResult.Path() != filepath.Join(outputDir, firstResult.URLHash()+".md") {
+		t.Errorf("expected the per-page path in the result, got %q", firstResult.Path())
+	}
+}
+
+func TestSingleFileWriter_Write_TruncatesOnNewRun(t *testing.T) {
+	outputDir := t.TempDir()
+	doc := createTestNormalizedDoc("https://example.com/a", "https://example.com/a", "sha256:a", []byte("# A\n"))
+
+	for run := 0; run < 2; run++ {
+		writer := storage.NewSingleFileWriter(&metadataSinkMock{}, "combined.md", nil)
+		if _, err := writer.Write(outputDir, doc, hashutil.HashAlgoSHA256); err != nil {
+			t.Fatalf("run %d: expected no error, got: %v", run, err)
+		}
+	}
+
+	got, _ := os.ReadFile(filepath.Join(outputDir, "combined.md"))
+	expected := "<a id=\"page-" + computeExpectedURLHash("https://example.com/a", hashutil.HashAlgoSHA256) + "\"></a>\n\n" +
+		"> Source: <https://example.com/a>\n\n# A\n"
+	if string(got) != expected {
+		t.Errorf("expected a second run to replace the file, got:\n%s", got)
+	}
+}
+
+func TestSingleFileWriter_CombinedOnly(t *testing.T) {
+	outputDir := t.TempDir()
+	sink := &metadataSinkMock{}
+	writer := storage.NewSingleFileWriter(sink, "export/all.md", nil)
+	doc := createTestNormalizedDoc("https://example.com/a", "https://example.com/a", "sha256:a", []byte("# A\n"))
+
+	result, err := writer.Write(outputDir, doc, hashutil.HashAlgoSHA256)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	combinedPath := filepath.Join(outputDir, "export", "all.md")
+	if result.Path() != combinedPath {
+		t.Errorf("expected the result to point at %q, got %q", combinedPath, result.Path())
+	}
+	if result.ContentHash() != "sha256:a" || result.SourceURL() != "https://example.com/a" {
+		t.Errorf("expected the page's hash and source URL, got %q and %q", result.ContentHash(), result.SourceURL())
+	}
+	if _, statErr := os.Stat(filepath.Join(outputDir, result.URLHash()+".md")); !os.IsNotExist(statErr) {
+		t.Errorf("expected no per-page file, got: %v", statErr)
+	}
+	artifacts := sink.GetArtifactRecords()
+	if len(artifacts) != 1 || artifacts[0].WritePath() != combinedPath {
+		t.Errorf("expected one artifact record for the combined file, got %+v", artifacts)
+	}
+
+	if err := writer.WriteManifest(outputDir, []storage.WriteResult{result}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if _, statErr := os.Stat(filepath.Join(outputDir, storage.ManifestFileName)); statErr != nil {
+		t.Errorf("expected the manifest to be written, got: %v", statErr)
+	}
+}
+
+func TestSingleFileWriter_RestartAndKeep(t *testing.T) {
+	outputDir := t.TempDir()
+	perPage := storage.NewMemoryWriter()
+	writer := storage.NewSingleFileWriter(&metadataSinkMock{}, "combined.md", perPage)
+	kept := createTestNormalizedDoc("https://example.com/a", "https://example.com/a", "sha256:a", []byte("# A\n"))
+	changed := createTestNormalizedDoc("https://example.com/b", "https://example.com/b", "sha256:b", []byte("# B\n"))
+
+	for run := 0; run < 2; run++ {
+		writer.Restart()
+		if run == 0 {
+			if _, err := writer.Write(outputDir, kept, hashutil.HashAlgoSHA256); err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+		} else if err := writer.Keep(outputDir, kept, hashutil.HashAlgoSHA256); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if _, err := writer.Write(outputDir, changed, hashutil.HashAlgoSHA256); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+	}
+
+	got, _ := os.ReadFile(filepath.Join(outputDir, "combined.md"))
+	expected := "<a id=\"page-" + computeExpectedURLHash("https://example.com/a", hashutil.HashAlgoSHA256) + "\"></a>\n\n" +
+		"> Source: <https://example.com/a>\n\n# A\n" +
+		"\n---\n\n" +
+		"<a id=\"page-" + computeExpectedURLHash("https://example.com/b", hashutil.HashAlgoSHA256) + "\"></a>\n\n" +
+		"> Source: <https://example.com/b>\n\n# B\n"
+	if string(got) != expected {
+		t.Errorf("expected the restarted run to hold each page once, got:\n%s", got)
+	}
+	// A kept page is not written through the per-page writer again
+	if len(perPage.Paths()) != 2 {
+		t.Errorf("expected 2 per-page files, got %v", perPage.Paths())
+	}
+}
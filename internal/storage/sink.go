@@ -3,6 +3,7 @@ package storage
 import (
 	"context"
 	"errors"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"syscall"
@@ -28,14 +29,25 @@ Output Characteristics
 - Overwrite-safe reruns
 */
 
-type Sink interface {
+// Writer persists a normalized document and reports where it was stored.
+// LocalSink, DryRunSink and MemoryWriter all implement it.
+type Writer interface {
 	Write(
 		outputDir string,
 		normalizedDoc normalize.NormalizedMarkdownDoc,
 		hashAlgo hashutil.HashAlgo,
 	) (WriteResult, failure.ClassifiedError)
+	// WriteManifest persists the results of a crawl run as
+	// outputDir/manifest.json (see manifest.go).
+	WriteManifest(outputDir string, results []WriteResult) failure.ClassifiedError
+	// WriteConfigSnapshot persists the effective config of a crawl run as
+	// outputDir/effective-config.json (see snapshot.go).
+	WriteConfigSnapshot(outputDir string, snapshot []byte) failure.ClassifiedError
 }
 
+// Sink is the original name of Writer, kept for existing callers.
+type Sink = Writer
+
 type LocalSink struct {
 	metadataSink metadata.MetadataSink
 	debugLogger  debug.DebugLogger
@@ -95,6 +107,54 @@ func (s *LocalSink) Write(
 	return writeResult, nil
 }
 
+// WriteConfigSnapshot writes the config snapshot to
+// outputDir/effective-config.json, creating outputDir if needed.
+func (s *LocalSink) WriteConfigSnapshot(outputDir string, snapshot []byte) failure.ClassifiedError {
+	return writeConfigSnapshot(outputDir, snapshot)
+}
+
+// WriteManifest writes the crawl manifest to outputDir/manifest.json,
+// creating outputDir if needed.
+func (s *LocalSink) WriteManifest(outputDir string, results []WriteResult) failure.ClassifiedError {
+	path := filepath.Join(outputDir, ManifestFileName)
+	var err failure.ClassifiedError
+	if ensureErr := fileutil.EnsureDir(outputDir); ensureErr != nil {
+		err = NewStorageError(ErrCausePathError, ensureErr.Error(), outputDir)
+	} else {
+		err = WriteManifest(path, results)
+	}
+	if err != nil {
+		var storageError *StorageError
+		errors.As(err, &storageError)
+		if s.debugLogger.Enabled() {
+			s.debugLogger.LogStep(context.TODO(), "storage", "write_manifest_failed", debug.FieldMap{
+				"file_path":   storageError.Path,
+				"error_cause": string(storageError.Cause),
+				"error_msg":   err.Error(),
+			})
+		}
+		s.metadataSink.RecordError(metadata.NewErrorRecord(
+			time.Now(),
+			"storage",
+			"LocalSink.WriteManifest",
+			mapStorageErrorToMetadataCause(storageError),
+			err.Error(),
+			[]metadata.Attribute{
+				metadata.NewAttr(metadata.AttrWritePath, storageError.Path),
+			},
+		))
+		return storageError
+	}
+
+	if s.debugLogger.Enabled() {
+		s.debugLogger.LogStep(context.TODO(), "storage", "write_manifest", debug.FieldMap{
+			"file_path": path,
+			"entries":   len(results),
+		})
+	}
+	return nil
+}
+
 func write(
 	outputDir string,
 	normalizedDoc normalize.NormalizedMarkdownDoc,
@@ -186,13 +246,7 @@ func write(
 	// Write content to file
 	content := normalizedDoc.Content()
 	if err := os.WriteFile(fullPath, content, 0644); err != nil {
-		var cause StorageErrorCause
-		// Check if it's a disk full error (ENOSPC)
-		if errors.Is(err, syscall.ENOSPC) {
-			cause = ErrCauseDiskFull
-		} else {
-			cause = ErrCauseWriteFailure
-		}
+		cause := writeFailureCause(err)
 		// Log write failure
 		if logger.Enabled() {
 			logger.LogStep(context.TODO(), "storage", "write_failed", debug.FieldMap{
@@ -208,11 +262,38 @@ func write(
 		)
 	}
 
+	// Write the chunk sidecar when the page was chunked, and drop a sidecar
+	// left by an earlier run otherwise
+	sidecarPath := chunksPath(fullPath)
+	var sidecar []byte
+	if chunks := normalizedDoc.Chunks(); len(chunks) > 0 {
+		data, encodeErr := encodeChunks(sidecarPath, chunks)
+		if encodeErr != nil {
+			return WriteResult{}, encodeErr
+		}
+		sidecar = data
+	}
+	if err := writeSidecar(sidecarPath, sidecar, logger); err != nil {
+		return WriteResult{}, err
+	}
+
+	// Likewise for the provenance sidecar
+	sidecarPath = provenancePath(fullPath)
+	sidecar, encodeErr := encodeProvenance(sidecarPath, normalizedDoc.Frontmatter())
+	if encodeErr != nil {
+		return WriteResult{}, encodeErr
+	}
+	if err := writeSidecar(sidecarPath, sidecar, logger); err != nil {
+		return WriteResult{}, err
+	}
+
 	// Get content hash from frontmatter
 	contentHash := normalizedDoc.Frontmatter().ContentHash()
 
 	// Construct WriteResult
-	writeResult := NewWriteResult(urlHash, fullPath, contentHash)
+	writeResult := NewWriteResult(urlHash, fullPath, contentHash).
+		WithSourceURL(normalizedDoc.Frontmatter().SourceURL()).
+		WithStructuralHash(normalizedDoc.Frontmatter().StructuralHash())
 
 	// Log successful write
 	if logger.Enabled() {
@@ -225,3 +306,49 @@ func write(
 
 	return writeResult, nil
 }
+
+// PageFileName returns the name of the file a page with canonicalURL is
+// written to: the first 12 hex characters of the URL hash plus ".md".
+func PageFileName(canonicalURL string, hashAlgo hashutil.HashAlgo) (string, error) {
+	urlHashFull, err := hashutil.HashBytes([]byte(canonicalURL), hashAlgo)
+	if err != nil {
+		return "", err
+	}
+	return urlHashFull[:12] + ".md", nil
+}
+
+// writeSidecar writes data to the sidecar at path, or removes a sidecar
+// left there by an earlier run when data is nil.
+func writeSidecar(path string, data []byte, logger debug.DebugLogger) failure.ClassifiedError {
+	var err error
+	if data != nil {
+		err = os.WriteFile(path, data, 0644)
+	} else if removeErr := os.Remove(path); removeErr != nil && !errors.Is(removeErr, fs.ErrNotExist) {
+		err = removeErr
+	}
+	if err == nil {
+		return nil
+	}
+	cause := writeFailureCause(err)
+	if logger.Enabled() {
+		logger.LogStep(context.TODO(), "storage", "write_failed", debug.FieldMap{
+			"file_path":   path,
+			"error_cause": string(cause),
+			"error_msg":   err.Error(),
+		})
+	}
+	return NewStorageError(
+		cause,
+		err.Error(),
+		path,
+	)
+}
+
+// writeFailureCause classifies a failed file write, telling a full disk
+// (ENOSPC) apart from other failures.
+func writeFailureCause(err error) StorageErrorCause {
+	if errors.Is(err, syscall.ENOSPC) {
+		return ErrCauseDiskFull
+	}
+	return ErrCauseWriteFailure
+}
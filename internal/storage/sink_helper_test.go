@@ -24,6 +24,14 @@ func createTestNormalizedDoc(sourceURL, canonicalURL, contentHash string, conten
 	return normalize.NewNormalizedMarkdownDoc(frontmatter, content)
 }
 
+// createChunkedTestNormalizedDoc creates a normalized document split into
+// chunks of at most size bytes
+func createChunkedTestNormalizedDoc(canonicalURL string, content []byte, size int) normalize.NormalizedMarkdownDoc {
+	doc := createTestNormalizedDoc(canonicalURL, canonicalURL, "sha256:0123456789abcdef0123", content)
+	param := normalize.NewNormalizeParam("1.0.0", time.Now(), hashutil.HashAlgoSHA256, 1, nil).WithChunking(size, 0)
+	return normalize.RefreshChunks(doc, param)
+}
+
 // computeExpectedURLHash computes the expected URL hash for a given canonical URL
 func computeExpectedURLHash(canonicalURL string, hashAlgo hashutil.HashAlgo) string {
 	hash, _ := hashutil.HashBytes([]byte(canonicalURL), hashAlgo)
@@ -1,6 +1,7 @@
 package storage_test
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
@@ -415,6 +416,9 @@ func TestLocalSink_Write_FilenameDeterminism(t *testing.T) {
 			if filepath.Base(result.Path()) != expectedFilename {
 				t.Errorf("expected filename %s, got %s", expectedFilename, filepath.Base(result.Path()))
 			}
+			if name, _ := storage.PageFileName(tt.canonicalURL, tt.hashAlgo); name != expectedFilename {
+				t.Errorf("expected PageFileName %s, got %s", expectedFilename, name)
+			}
 
 			// Run twice and verify determinism
 			mockSink.Reset()
@@ -481,6 +485,116 @@ func TestLocalSink_Write_MultipleDocuments(t *testing.T) {
 	}
 }
 
+func TestLocalSink_WriteManifest(t *testing.T) {
+	// GIVEN a page written by the sink into a directory that does not exist yet
+	outputDir := filepath.Join(t.TempDir(), "out")
+	sink := storage.NewLocalSink(&metadataSinkMock{})
+	doc := createTestNormalizedDoc(
+		"https://example.com/docs/page1?ref=nav",
+		"https://example.com/docs/page1",
+		"sha256:abc",
+		[]byte("# Page 1"),
+	)
+	result, err := sink.Write(outputDir, doc, hashutil.HashAlgoSHA256)
+	if err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	// WHEN the manifest is written
+	if err := sink.WriteManifest(outputDir, []storage.WriteResult{result}); err != nil {
+		t.Fatalf("WriteManifest failed: %v", err)
+	}
+
+	// THEN outputDir/manifest.json records the page with its source URL
+	loaded, readErr := storage.ReadManifest(filepath.Join(outputDir, storage.ManifestFileName))
+	if readErr != nil {
+		t.Fatalf("ReadManifest failed: %v", readErr)
+	}
+	if len(loaded) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(loaded))
+	}
+	if loaded[0].URLHash() != result.URLHash() || loaded[0].ContentHash() != "sha256:abc" {
+		t.Errorf("unexpected entry: %+v", loaded[0])
+	}
+	if loaded[0].SourceURL() != "https://example.com/docs/page1?ref=nav" {
+		t.Errorf("SourceURL() = %q, want the page's source URL", loaded[0].SourceURL())
+	}
+}
+
+func TestLocalSink_WriteConfigSnapshot(t *testing.T) {
+	// GIVEN an output directory that does not exist yet
+	outputDir := filepath.Join(t.TempDir(), "out")
+	sink := storage.NewLocalSink(&metadataSinkMock{})
+	snapshot := []byte(`{"version": 2, "runId": "abc"}` + "\n")
+
+	// WHEN the config snapshot is written
+	if err := sink.WriteConfigSnapshot(outputDir, snapshot); err != nil {
+		t.Fatalf("WriteConfigSnapshot failed: %v", err)
+	}
+
+	// THEN it is stored verbatim as outputDir/effective-config.json
+	written, err := os.ReadFile(filepath.Join(outputDir, storage.ConfigSnapshotFileName))
+	if err != nil {
+		t.Fatalf("expected the snapshot file, got %v", err)
+	}
+	if string(written) != string(snapshot) {
+		t.Errorf("snapshot = %q, want %q", written, snapshot)
+	}
+}
+
+func TestLocalSink_Write_ChunkSidecar(t *testing.T) {
+	// GIVEN a page normalized into two chunks
+	outputDir := t.TempDir()
+	sink := storage.NewLocalSink(&metadataSinkMock{})
+	content := []byte("# Page\n\nFirst paragraph.\n\nSecond paragraph.\n")
+	doc := createChunkedTestNormalizedDoc("https://example.com/docs/chunked", content, 30)
+
+	// WHEN it is written
+	result, err := sink.Write(outputDir, doc, hashutil.HashAlgoSHA256)
+	if err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	// THEN the chunks are written next to the page with offsets into it
+	sidecar := filepath.Join(outputDir, result.URLHash()+storage.ChunksFileSuffix)
+	data, readErr := os.ReadFile(sidecar)
+	if readErr != nil {
+		t.Fatalf("expected chunk sidecar at %s: %v", sidecar, readErr)
+	}
+	var chunks []struct {
+		ID      string `json:"id"`
+		Index   int    `json:"index"`
+		Start   int    `json:"start"`
+		End     int    `json:"end"`
+		Content string `json:"content"`
+	}
+	if err := json.Unmarshal(data, &chunks); err != nil {
+		t.Fatalf("invalid sidecar JSON: %v", err)
+	}
+	if len(chunks) != 2 {
+		t.Fatalf("expected 2 chunks, got %d: %+v", len(chunks), chunks)
+	}
+	for i, c := range chunks {
+		if c.ID != doc.Chunks()[i].ID() || c.Index != i {
+			t.Errorf("chunk %d: unexpected id %q index %d", i, c.ID, c.Index)
+		}
+		if c.Content != string(content[c.Start:c.End]) {
+			t.Errorf("chunk %d: content %q does not match page bytes [%d:%d]", i, c.Content, c.Start, c.End)
+		}
+	}
+
+	// WHEN the page is rewritten without chunks
+	plain := createTestNormalizedDoc("https://example.com/docs/chunked", "https://example.com/docs/chunked", "sha256:abc", content)
+	if _, err := sink.Write(outputDir, plain, hashutil.HashAlgoSHA256); err != nil {
+		t.Fatalf("rewrite failed: %v", err)
+	}
+
+	// THEN the stale sidecar is removed
+	if _, statErr := os.Stat(sidecar); !os.IsNotExist(statErr) {
+		t.Errorf("expected stale sidecar to be removed, stat err: %v", statErr)
+	}
+}
+
 func TestWriteResult_Methods(t *testing.T) {
 	result := storage.NewWriteResult("urlhash123", "/path/to/file.md", "contenthash456")
 
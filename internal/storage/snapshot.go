@@ -0,0 +1,29 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rohmanhakim/docs-crawler/pkg/failure"
+	"github.com/rohmanhakim/docs-crawler/pkg/fileutil"
+)
+
+// ConfigSnapshotFileName is the name of the effective config snapshot
+// written into the output directory when a crawl starts. It is a complete
+// config file reproducing the crawl, plus its run ID (see
+// config.EncodeSnapshot).
+const ConfigSnapshotFileName = "effective-config.json"
+
+// writeConfigSnapshot writes snapshot to outputDir/effective-config.json,
+// creating outputDir if needed.
+func writeConfigSnapshot(outputDir string, snapshot []byte) failure.ClassifiedError {
+	if err := fileutil.EnsureDir(outputDir); err != nil {
+		return NewStorageError(ErrCausePathError, err.Error(), outputDir)
+	}
+	path := filepath.Join(outputDir, ConfigSnapshotFileName)
+	if err := os.WriteFile(path, snapshot, 0644); err != nil {
+		return NewStorageError(ErrCauseWriteFailure, fmt.Sprintf("failed to write config snapshot: %v", err), path)
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/rohmanhakim/docs-crawler/pkg/failure"
+	"github.com/rohmanhakim/docs-crawler/pkg/hashutil"
+)
+
+/*
+Verification
+
+VerifyManifest re-hashes the files a crawl run wrote and compares them with
+the content hashes recorded in its manifest. Files are located by urlHash
+(outputDir/<urlHash>.md), under the entry's language subdirectory when the
+crawl partitioned its output by language (outputDir/<lang>/<urlHash>.md),
+so a manifest stays verifiable after the output directory is moved.
+
+Each entry lands in at most one category:
+  - missing: the file no longer exists
+  - corrupt: the file exists but its content hash differs from the manifest
+*/
+
+// VerifyCategory classifies a manifest entry that failed verification.
+type VerifyCategory string
+
+const (
+	VerifyCategoryMissing VerifyCategory = "missing"
+	VerifyCategoryCorrupt VerifyCategory = "corrupt"
+)
+
+// VerifyIssue describes a single manifest entry that failed verification.
+type VerifyIssue struct {
+	urlHash      string
+	sourceURL    string
+	path         string
+	category     VerifyCategory
+	expectedHash string
+	actualHash   string
+}
+
+// URLHash returns the urlHash of the failing entry.
+func (i VerifyIssue) URLHash() string {
+	return i.urlHash
+}
+
+// SourceURL returns the source URL of the failing entry.
+// It is empty for entries from manifests that predate source URLs.
+func (i VerifyIssue) SourceURL() string {
+	return i.sourceURL
+}
+
+// Path returns the file path that was checked.
+func (i VerifyIssue) Path() string {
+	return i.path
+}
+
+// Category returns why the entry failed verification.
+func (i VerifyIssue) Category() VerifyCategory {
+	return i.category
+}
+
+// ExpectedHash returns the content hash recorded in the manifest.
+func (i VerifyIssue) ExpectedHash() string {
+	return i.expectedHash
+}
+
+// ActualHash returns the content hash of the file on disk.
+// It is empty for missing files.
+func (i VerifyIssue) ActualHash() string {
+	return i.actualHash
+}
+
+// VerifyReport holds the outcome of verifying a manifest.
+// Issues are sorted by urlHash for deterministic output.
+type VerifyReport struct {
+	checked int
+	issues  []VerifyIssue
+}
+
+// Checked returns the number of manifest entries that were verified.
+func (r VerifyReport) Checked() int {
+	return r.checked
+}
+
+// Issues returns every entry that failed verification.
+func (r VerifyReport) Issues() []VerifyIssue {
+	return append([]VerifyIssue(nil), r.issues...)
+}
+
+// Missing returns URL hashes whose files no longer exist.
+func (r VerifyReport) Missing() []string {
+	return r.byCategory(VerifyCategoryMissing)
+}
+
+// Corrupt returns URL hashes whose files no longer match the manifest.
+func (r VerifyReport) Corrupt() []string {
+	return r.byCategory(VerifyCategoryCorrupt)
+}
+
+// IsClean reports whether every entry passed verification.
+func (r VerifyReport) IsClean() bool {
+	return len(r.issues) == 0
+}
+
+func (r VerifyReport) byCategory(category VerifyCategory) []string {
+	var urlHashes []string
+	for _, issue := range r.issues {
+		if issue.category == category {
+			urlHashes = append(urlHashes, issue.urlHash)
+		}
+	}
+	return urlHashes
+}
+
+// VerifyManifest re-hashes every file listed in results and reports entries
+// whose file is missing or whose content hash no longer matches.
+// Errors other than a missing file abort verification.
+func VerifyManifest(outputDir string, results []WriteResult, algo hashutil.HashAlgo) (VerifyReport, failure.ClassifiedError) {
+	var report VerifyReport
+	for _, r := range results {
+		path := filepath.Join(outputDir, r.language, r.urlHash+".md")
+		report.checked++
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				report.issues = append(report.issues, VerifyIssue{
+					urlHash:      r.urlHash,
+					sourceURL:    r.sourceURL,
+					path:         path,
+					category:     VerifyCategoryMissing,
+					expectedHash: r.contentHash,
+				})
+				continue
+			}
+			return VerifyReport{}, NewStorageError(ErrCauseReadFailure, fmt.Sprintf("failed to read output file: %v", err), path)
+		}
+
+		hashValue, err := hashutil.HashBytes(content, algo)
+		if err != nil {
+			return VerifyReport{}, NewStorageError(ErrCauseHashComputationFailed, fmt.Sprintf("failed to hash output file: %v", err), path)
+		}
+		actualHash := string(algo) + ":" + hashValue
+		if actualHash != r.contentHash {
+			report.issues = append(report.issues, VerifyIssue{
+				urlHash:      r.urlHash,
+				sourceURL:    r.sourceURL,
+				path:         path,
+				category:     VerifyCategoryCorrupt,
+				expectedHash: r.contentHash,
+				actualHash:   actualHash,
+			})
+		}
+	}
+
+	sort.Slice(report.issues, func(i, j int) bool {
+		return report.issues[i].urlHash < report.issues[j].urlHash
+	})
+	return report, nil
+}
@@ -0,0 +1,139 @@
+package storage_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/rohmanhakim/docs-crawler/internal/storage"
+	"github.com/rohmanhakim/docs-crawler/pkg/hashutil"
+)
+
+// writeVerifiedFile writes content to outputDir/<urlHash>.md and returns
+// the WriteResult a sink would have recorded for it.
+func writeVerifiedFile(t *testing.T, outputDir, urlHash, content string) storage.WriteResult {
+	t.Helper()
+	path := filepath.Join(outputDir, urlHash+".md")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	hash, err := hashutil.HashBytes([]byte(content), hashutil.HashAlgoSHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return storage.NewWriteResult(urlHash, path, hashutil.HashAlgoSHA256+":"+hash)
+}
+
+func TestVerifyManifest_FlagsAlteredAndDeletedFiles(t *testing.T) {
+	dir := t.TempDir()
+	manifestPath := filepath.Join(dir, "manifest.json")
+
+	if err := storage.WriteManifest(manifestPath, []storage.WriteResult{
+		writeVerifiedFile(t, dir, "aaa", "# Intact\n"),
+		writeVerifiedFile(t, dir, "bbb", "# Altered\n"),
+		writeVerifiedFile(t, dir, "ccc", "# Deleted\n"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "bbb.md"), []byte("# Altered by hand\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(dir, "ccc.md")); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := storage.ReadManifest(manifestPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := storage.VerifyManifest(dir, results, hashutil.HashAlgoSHA256)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if report.Checked() != 3 {
+		t.Errorf("Checked() = %d, want 3", report.Checked())
+	}
+	if report.IsClean() {
+		t.Error("expected report with issues")
+	}
+	if got, want := report.Corrupt(), []string{"bbb"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Corrupt() = %v, want %v", got, want)
+	}
+	if got, want := report.Missing(), []string{"ccc"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Missing() = %v, want %v", got, want)
+	}
+
+	issues := report.Issues()
+	if len(issues) != 2 {
+		t.Fatalf("expected 2 issues, got %d", len(issues))
+	}
+	if issues[0].Category() != storage.VerifyCategoryCorrupt || issues[0].ActualHash() == "" {
+		t.Errorf("expected corrupt issue with actual hash, got %+v", issues[0])
+	}
+	if issues[1].Category() != storage.VerifyCategoryMissing || issues[1].ActualHash() != "" {
+		t.Errorf("expected missing issue without actual hash, got %+v", issues[1])
+	}
+}
+
+func TestVerifyManifest_Clean(t *testing.T) {
+	dir := t.TempDir()
+	results := []storage.WriteResult{
+		writeVerifiedFile(t, dir, "aaa", "# One\n"),
+		writeVerifiedFile(t, dir, "bbb", "# Two\n"),
+	}
+
+	report, err := storage.VerifyManifest(dir, results, hashutil.HashAlgoSHA256)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !report.IsClean() {
+		t.Errorf("expected clean report, got %+v", report.Issues())
+	}
+	if report.Checked() != 2 {
+		t.Errorf("Checked() = %d, want 2", report.Checked())
+	}
+}
+
+func TestVerifyManifest_LanguagePartitions(t *testing.T) {
+	dir := t.TempDir()
+	for _, language := range []string{"en", "fr"} {
+		if err := os.MkdirAll(filepath.Join(dir, language), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	manifestPath := filepath.Join(dir, "manifest.json")
+	if err := storage.WriteManifest(manifestPath, []storage.WriteResult{
+		writeVerifiedFile(t, filepath.Join(dir, "en"), "aaa", "# Intact\n").WithLanguage("en"),
+		writeVerifiedFile(t, filepath.Join(dir, "fr"), "bbb", "# Intact aussi\n").WithLanguage("fr"),
+		writeVerifiedFile(t, filepath.Join(dir, "fr"), "ccc", "# Supprimé\n").WithLanguage("fr"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(dir, "fr", "ccc.md")); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := storage.ReadManifest(manifestPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := storage.VerifyManifest(dir, results, hashutil.HashAlgoSHA256)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Only the deleted page is reported, at its partitioned path
+	if got, want := report.Missing(), []string{"ccc"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Missing() = %v, want %v", got, want)
+	}
+	if got := report.Corrupt(); len(got) != 0 {
+		t.Errorf("Corrupt() = %v, want none", got)
+	}
+	if issues := report.Issues(); len(issues) == 1 {
+		if want := filepath.Join(dir, "fr", "ccc.md"); issues[0].Path() != want {
+			t.Errorf("Path() = %q, want %q", issues[0].Path(), want)
+		}
+	}
+}
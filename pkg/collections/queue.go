@@ -12,6 +12,19 @@ func (f *FIFOQueue[T]) Enqueue(item T) {
 	*f = append(*f, item)
 }
 
+// EnqueueOrdered inserts item before the first queued element it is less
+// than, keeping the queue sorted by less. Items that compare equal keep
+// their insertion order.
+func (f *FIFOQueue[T]) EnqueueOrdered(item T, less func(a, b T) bool) {
+	i := len(*f)
+	for i > 0 && less(item, (*f)[i-1]) {
+		i--
+	}
+	*f = append(*f, item)
+	copy((*f)[i+1:], (*f)[i:])
+	(*f)[i] = item
+}
+
 // return false on the second returned values if queue is empty
 func (f *FIFOQueue[T]) Dequeue() (T, bool) {
 	var zero T
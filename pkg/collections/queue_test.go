@@ -80,6 +80,31 @@ func TestEnqueueDequeue(t *testing.T) {
 	}
 }
 
+func TestEnqueueOrdered(t *testing.T) {
+	queue := collections.NewFIFOQueue[int]()
+	// order by tens digit only, so items with the same tens digit tie
+	less := func(a, b int) bool { return a/10 < b/10 }
+
+	for _, item := range []int{20, 10, 21, 30, 11, 22} {
+		queue.EnqueueOrdered(item, less)
+	}
+
+	var got []int
+	for queue.Size() > 0 {
+		item, _ := queue.Dequeue()
+		got = append(got, item)
+	}
+	want := []int{10, 11, 20, 21, 22, 30}
+	if len(got) != len(want) {
+		t.Fatalf("should dequeue %v, got: %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("should dequeue %v, got: %v", want, got)
+		}
+	}
+}
+
 type MyQueueItem struct {
 	name string
 }
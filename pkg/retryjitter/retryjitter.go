@@ -0,0 +1,51 @@
+package retryjitter
+
+import (
+	"context"
+	"time"
+)
+
+/*
+Retry Jitter
+
+The retrier library draws its jitter from the global math/rand source,
+which cannot be seeded. Callers pass it a zero jitter and wrap the retried
+task with Wrap instead: every attempt after the first waits one draw of a
+jitter source they control, so each retry gets its own jitter and a seeded
+source makes the whole sequence reproducible.
+*/
+
+// Source returns the jitter to wait before the next retry.
+type Source func() time.Duration
+
+// Wrap returns task waiting one draw of next before every attempt after
+// the first. A nil next waits none. When ctx is done during the wait, the
+// attempt fails with the context's error without running task.
+func Wrap[T any](ctx context.Context, next Source, task func() (T, error)) func() (T, error) {
+	attempt := 0
+	return func() (T, error) {
+		attempt++
+		if attempt > 1 && next != nil {
+			if err := sleep(ctx, next()); err != nil {
+				var zero T
+				return zero, err
+			}
+		}
+		return task()
+	}
+}
+
+// sleep waits for d, or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,60 @@
+package retryjitter_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rohmanhakim/docs-crawler/pkg/retryjitter"
+)
+
+func TestWrap_DrawsBeforeEveryRetry(t *testing.T) {
+	draws := 0
+	next := func() time.Duration {
+		draws++
+		return time.Millisecond
+	}
+	calls := 0
+	task := retryjitter.Wrap(context.Background(), next, func() (int, error) {
+		calls++
+		return calls, nil
+	})
+
+	for i := 1; i <= 3; i++ {
+		if got, _ := task(); got != i {
+			t.Fatalf("expected attempt %d to run the task, got %d", i, got)
+		}
+	}
+	if draws != 2 {
+		t.Errorf("expected one draw per retry, got %d", draws)
+	}
+}
+
+func TestWrap_NilSourceWaitsNone(t *testing.T) {
+	task := retryjitter.Wrap(context.Background(), nil, func() (string, error) {
+		return "ok", nil
+	})
+	task()
+	if got, err := task(); got != "ok" || err != nil {
+		t.Errorf("expected the task result, got %q, %v", got, err)
+	}
+}
+
+func TestWrap_CancelledDuringWait(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	task := retryjitter.Wrap(ctx, func() time.Duration { return time.Hour }, func() (int, error) {
+		calls++
+		return calls, nil
+	})
+	task()
+	cancel()
+
+	if _, err := task(); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the context error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected the retry not to run, got %d calls", calls)
+	}
+}
@@ -1,6 +1,10 @@
 package urlutil
 
-import "net/url"
+import (
+	"net/url"
+	"path"
+	"strings"
+)
 
 // Canonicalize applies a deterministic normalization to a URL, producing a canonical form.
 // It maps equivalent URL spellings to a single canonical representation.
@@ -49,6 +53,92 @@ func Canonicalize(sourceUrl url.URL) url.URL {
 	return canonical
 }
 
+// CanonicalizeKeepingQuery is Canonicalize, except that the query
+// parameters for which keep returns true survive. Kept parameters are
+// sorted by name, and values keep their order, so spellings that only
+// differ in parameter order share one canonical form. A URL whose kept
+// query is empty has no query at all.
+//
+// Examples:
+//   - CanonicalizeKeepingQuery("https://example.com/api?v=2&sort=asc", keep "v") → "https://example.com/api?v=2"
+//   - CanonicalizeKeepingQuery("https://example.com/api?sort=asc", keep "v") → "https://example.com/api"
+//
+// Properties:
+//   - Pure: no state, no memory
+//   - Deterministic: same input always produces same output
+//   - Idempotent for a given keep function
+func CanonicalizeKeepingQuery(sourceUrl url.URL, keep func(param string) bool) url.URL {
+	canonical := Canonicalize(sourceUrl)
+	if keep == nil || sourceUrl.RawQuery == "" {
+		return canonical
+	}
+
+	query, err := url.ParseQuery(sourceUrl.RawQuery)
+	if err != nil {
+		// Unparseable queries are dropped, as Canonicalize does
+		return canonical
+	}
+	for param := range query {
+		if !keep(param) {
+			query.Del(param)
+		}
+	}
+	// Encode sorts by parameter name
+	canonical.RawQuery = query.Encode()
+	return canonical
+}
+
+// TrailingSlashPolicy decides whether the path of a canonical URL ends in a
+// slash, for sites that serve "/page" and "/page/" as different pages, or
+// as the same page under either spelling.
+type TrailingSlashPolicy string
+
+const (
+	// TrailingSlashStrip removes the trailing slash, as Canonicalize does,
+	// so "/page" and "/page/" are one URL. The empty policy behaves the same.
+	TrailingSlashStrip TrailingSlashPolicy = "strip"
+	// TrailingSlashPreserve keeps the trailing slash of the source URL, so
+	// "/page" and "/page/" are distinct URLs.
+	TrailingSlashPreserve TrailingSlashPolicy = "preserve"
+	// TrailingSlashAdd ends every path whose last segment has no file
+	// extension in a slash, so "/page" and "/page/" are one URL, spelled
+	// "/page/". "/page.html" is left alone.
+	TrailingSlashAdd TrailingSlashPolicy = "add"
+)
+
+// ApplyTrailingSlash returns canonical, the canonical form of source, with
+// its trailing slash set by policy. The root path is always "/".
+//
+// Examples:
+//   - ApplyTrailingSlash("https://example.com/a", "https://example.com/a/", TrailingSlashPreserve) → "https://example.com/a/"
+//   - ApplyTrailingSlash("https://example.com/a", "https://example.com/a", TrailingSlashAdd) → "https://example.com/a/"
+//   - ApplyTrailingSlash("https://example.com/a", "https://example.com/a/", TrailingSlashStrip) → "https://example.com/a"
+//
+// Properties:
+//   - Pure: no state, no memory
+//   - Idempotent: applying it to its own result with the same policy
+//     changes nothing
+func ApplyTrailingSlash(canonical url.URL, source url.URL, policy TrailingSlashPolicy) url.URL {
+	if len(canonical.Path) <= 1 || strings.HasSuffix(canonical.Path, "/") {
+		return canonical
+	}
+	switch policy {
+	case TrailingSlashPreserve:
+		if !strings.HasSuffix(source.Path, "/") {
+			return canonical
+		}
+	case TrailingSlashAdd:
+		if path.Ext(canonical.Path) != "" {
+			return canonical
+		}
+	default:
+		return canonical
+	}
+	canonical.Path += "/"
+	canonical.RawPath = ""
+	return canonical
+}
+
 // lowerASCII converts ASCII characters to lowercase without allocating.
 // This is faster than strings.ToLower for ASCII-only strings.
 func lowerASCII(s string) string {
@@ -144,3 +234,78 @@ func FilterByHost(host string, urls []url.URL) []url.URL {
 
 	return filtered
 }
+
+// DedupeCanonical removes URLs whose canonical form was already seen,
+// keeping the first occurrence of each and preserving input order.
+// The surviving URLs are returned as given, not canonicalized.
+//
+// Examples:
+//   - DedupeCanonical(["https://example.com/b", "https://example.com/a#x", "https://example.com/b/"])
+//     → ["https://example.com/b", "https://example.com/a#x"]
+//
+// Properties:
+//   - Pure: no state, no memory
+//   - Deterministic: same input always produces same output
+//   - Order-preserving: output order follows first occurrence in input
+func DedupeCanonical(urls []url.URL) []url.URL {
+	return DedupeCanonicalWithTrailingSlash(urls, TrailingSlashStrip)
+}
+
+// DedupeCanonicalWithTrailingSlash is DedupeCanonical with the trailing
+// slash of each canonical form set by policy (see ApplyTrailingSlash), so
+// under TrailingSlashPreserve "/b" and "/b/" both survive.
+func DedupeCanonicalWithTrailingSlash(urls []url.URL, policy TrailingSlashPolicy) []url.URL {
+	if len(urls) == 0 {
+		return []url.URL{}
+	}
+
+	seen := make(map[string]struct{}, len(urls))
+	deduped := make([]url.URL, 0, len(urls))
+	for _, u := range urls {
+		canonical := ApplyTrailingSlash(Canonicalize(u), u, policy)
+		key := canonical.String()
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		deduped = append(deduped, u)
+	}
+
+	return deduped
+}
+
+// StripDefaultDocument maps a URL whose last path segment is one of the
+// given default documents (such as "index.html") to its directory URL, so
+// "/docs/index.html" and "/docs/" share one key. Document names are matched
+// case-insensitively; the directory path follows Canonicalize and has no
+// trailing slash, except for the root "/".
+//
+// Examples:
+//   - StripDefaultDocument("https://example.com/docs/index.html", ["index.html"]) → "https://example.com/docs"
+//   - StripDefaultDocument("https://example.com/index.html", ["index.html"]) → "https://example.com/"
+//   - StripDefaultDocument("https://example.com/docs/intro.html", ["index.html"]) → unchanged
+//
+// Properties:
+//   - Pure: no state, no memory
+//   - Deterministic: same input always produces same output
+//   - Idempotent: directory URLs are returned unchanged
+func StripDefaultDocument(sourceUrl url.URL, defaultDocuments []string) url.URL {
+	slash := strings.LastIndex(sourceUrl.Path, "/")
+	if slash < 0 {
+		return sourceUrl
+	}
+	last := sourceUrl.Path[slash+1:]
+	if last == "" {
+		return sourceUrl
+	}
+	for _, document := range defaultDocuments {
+		if document == "" || !strings.EqualFold(last, document) {
+			continue
+		}
+		stripped := sourceUrl
+		stripped.Path = stripTrailingSlash(sourceUrl.Path[:slash+1])
+		stripped.RawPath = ""
+		return stripped
+	}
+	return sourceUrl
+}
@@ -478,6 +478,72 @@ func TestFilterByHostDoesNotMutateInput(t *testing.T) {
 	}
 }
 
+func TestDedupeCanonical(t *testing.T) {
+	tests := []struct {
+		name     string
+		urls     []string
+		expected []string
+	}{
+		{
+			name:     "empty input",
+			urls:     []string{},
+			expected: []string{},
+		},
+		{
+			name: "keeps first occurrence in input order",
+			urls: []string{
+				"https://example.com/c",
+				"https://example.com/a",
+				"https://example.com/c",
+				"https://example.com/b",
+				"https://example.com/a",
+			},
+			expected: []string{
+				"https://example.com/c",
+				"https://example.com/a",
+				"https://example.com/b",
+			},
+		},
+		{
+			name: "fragments, trailing slashes and host case collapse",
+			urls: []string{
+				"https://example.com/docs/intro#setup",
+				"https://EXAMPLE.com/docs/intro/",
+				"https://example.com/docs/intro",
+				"https://example.com/docs/next",
+			},
+			expected: []string{
+				"https://example.com/docs/intro#setup",
+				"https://example.com/docs/next",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			urls := make([]url.URL, len(tt.urls))
+			for i, u := range tt.urls {
+				urls[i] = *mustParseURL(u)
+			}
+
+			result := DedupeCanonical(urls)
+
+			resultStrs := make([]string, len(result))
+			for i, u := range result {
+				resultStrs[i] = u.String()
+			}
+			if len(resultStrs) != len(tt.expected) {
+				t.Fatalf("DedupeCanonical() = %v, want %v", resultStrs, tt.expected)
+			}
+			for i, expected := range tt.expected {
+				if resultStrs[i] != expected {
+					t.Errorf("DedupeCanonical()[%d] = %q, want %q", i, resultStrs[i], expected)
+				}
+			}
+		})
+	}
+}
+
 // mustParseURL is a test helper that parses a URL or panics
 func mustParseURL(s string) *url.URL {
 	u, err := url.Parse(s)
@@ -486,3 +552,99 @@ func mustParseURL(s string) *url.URL {
 	}
 	return u
 }
+
+func TestStripDefaultDocument(t *testing.T) {
+	documents := []string{"index.html", "README.md"}
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"https://example.com/docs/index.html", "https://example.com/docs"},
+		{"https://example.com/docs/INDEX.HTML", "https://example.com/docs"},
+		{"https://example.com/guide/readme.md", "https://example.com/guide"},
+		{"https://example.com/index.html", "https://example.com/"},
+		{"https://example.com/docs", "https://example.com/docs"},
+		{"https://example.com/", "https://example.com/"},
+		{"https://example.com/docs/intro.html", "https://example.com/docs/intro.html"},
+		{"https://example.com/index.html/more", "https://example.com/index.html/more"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result := StripDefaultDocument(*mustParseURL(tt.input), documents)
+			if result.String() != tt.expected {
+				t.Errorf("StripDefaultDocument(%q) = %q, want %q", tt.input, result.String(), tt.expected)
+			}
+		})
+	}
+
+	// Without default documents nothing is stripped
+	input := *mustParseURL("https://example.com/docs/index.html")
+	if result := StripDefaultDocument(input, nil); result.String() != input.String() {
+		t.Errorf("StripDefaultDocument(%q, nil) = %q, want it unchanged", input.String(), result.String())
+	}
+}
+
+func TestCanonicalizeKeepingQuery(t *testing.T) {
+	keepVersion := func(param string) bool { return param == "version" || param == "lang" }
+	tests := []struct {
+		name string
+		raw  string
+		keep func(string) bool
+		want string
+	}{
+		{"nil keep drops all", "https://Example.com/api/?version=2#top", nil, "https://example.com/api"},
+		{"only kept params survive", "https://example.com/api?sort=asc&version=2", keepVersion, "https://example.com/api?version=2"},
+		{"kept params are sorted", "https://example.com/api?version=2&lang=en", keepVersion, "https://example.com/api?lang=en&version=2"},
+		{"empty kept query is removed", "https://example.com/api?sort=asc", keepVersion, "https://example.com/api"},
+		{"repeated values keep their order", "https://example.com/api?version=2&version=1", keepVersion, "https://example.com/api?version=2&version=1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, _ := url.Parse(tt.raw)
+			got := CanonicalizeKeepingQuery(*u, tt.keep)
+			if got.String() != tt.want {
+				t.Errorf("CanonicalizeKeepingQuery(%q) = %q, want %q", tt.raw, got.String(), tt.want)
+			}
+			again := CanonicalizeKeepingQuery(got, tt.keep)
+			if again.String() != got.String() {
+				t.Errorf("expected idempotence, got %q then %q", got.String(), again.String())
+			}
+		})
+	}
+}
+
+func TestApplyTrailingSlash(t *testing.T) {
+	tests := []struct {
+		input    string
+		policy   TrailingSlashPolicy
+		expected string
+	}{
+		{"https://example.com/a/", TrailingSlashStrip, "https://example.com/a"},
+		{"https://example.com/a/", "", "https://example.com/a"},
+		{"https://example.com/a/", TrailingSlashPreserve, "https://example.com/a/"},
+		{"https://example.com/a", TrailingSlashPreserve, "https://example.com/a"},
+		{"https://example.com/a//?q=1#top", TrailingSlashPreserve, "https://example.com/a/"},
+		{"https://example.com/a", TrailingSlashAdd, "https://example.com/a/"},
+		{"https://example.com/a/", TrailingSlashAdd, "https://example.com/a/"},
+		{"https://example.com/a/page.html", TrailingSlashAdd, "https://example.com/a/page.html"},
+		{"https://example.com/", TrailingSlashAdd, "https://example.com/"},
+		{"https://example.com", TrailingSlashPreserve, "https://example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy)+" "+tt.input, func(t *testing.T) {
+			source := *mustParseURL(tt.input)
+			result := ApplyTrailingSlash(Canonicalize(source), source, tt.policy)
+			if result.String() != tt.expected {
+				t.Errorf("ApplyTrailingSlash(%q, %q) = %q, want %q", tt.input, tt.policy, result.String(), tt.expected)
+			}
+
+			// Applying the policy to its own result changes nothing
+			again := ApplyTrailingSlash(Canonicalize(result), result, tt.policy)
+			if again.String() != result.String() {
+				t.Errorf("ApplyTrailingSlash is not idempotent for %q: %q then %q", tt.input, result.String(), again.String())
+			}
+		})
+	}
+}